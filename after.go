@@ -0,0 +1,59 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+// AfterHandler is run by the after-response phase, see UseAfter
+type AfterHandler = func(c *Ctx)
+
+// afterPhaseKey is the user value key of the after-response phase
+const afterPhaseKey = "__local_after_phase__"
+
+// UseAfter registers handlers, which run after the response has been written
+// to the connection, e.g. for access logs or flushing metrics:
+//
+//	app.UseAfter(func(c *fiber.Ctx) {
+//		latency := time.Since(c.Context().Time())
+//		metrics.Observe(c.Route().Path, c.Response().StatusCode(), latency)
+//	})
+//
+// The handlers run in the order of registration for every request of the app,
+// including requests without a matching route. The request, the response and
+// the Locals are still available, but the response can no longer be changed.
+// Locals implementing io.Closer are closed after the handlers ran. The after
+// handlers of mounted apps are not run, register them on the app serving the
+// requests instead.
+func (app *App) UseAfter(handlers ...AfterHandler) {
+	app.mutex.Lock()
+	app.afterHandlers = append(app.afterHandlers, handlers...)
+	app.mutex.Unlock()
+}
+
+// afterPhase runs the after handlers once fasthttp resets the user values,
+// which happens after the response has been sent
+type afterPhase struct {
+	c *Ctx
+}
+
+// Close runs the after handlers and releases the Ctx
+func (a afterPhase) Close() error {
+	c := a.c
+	app := c.app
+	for _, handler := range app.afterHandlers {
+		handler(c)
+	}
+	app.ReleaseCtx(c)
+	return nil
+}
+
+// deferRelease registers the after phase, which releases the Ctx after running
+// the after handlers. It reports false if the app has no after handlers.
+func (app *App) deferRelease(c *Ctx) bool {
+	if len(app.afterHandlers) == 0 {
+		return false
+	}
+	// Registered first, so it runs before Locals implementing io.Closer are closed
+	c.setLocal(afterPhaseKey, afterPhase{c})
+	return true
+}
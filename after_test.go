@@ -0,0 +1,84 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// go test -run Test_App_UseAfter
+func Test_App_UseAfter(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	type record struct {
+		path, body, closed string
+		status             int
+	}
+	records := make(chan record, 1)
+	var closed string
+
+	app.UseAfter(func(c *Ctx) {
+		closed += "after1 "
+	}, func(c *Ctx) {
+		closed += "after2 "
+		records <- record{
+			path:   c.Route().Path,
+			status: c.Response().StatusCode(),
+			body:   string(c.Response().Body()),
+			closed: closed,
+		}
+	})
+	app.Get("/:name", func(c *Ctx) error {
+		c.Locals("resource", closerFunc(func() error {
+			closed += "resource "
+			return nil
+		}))
+		return c.Status(StatusCreated).SendString("hello " + c.Params("name"))
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/john", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "hello john", string(body))
+
+	select {
+	case r := <-records:
+		utils.AssertEqual(t, "/:name", r.path)
+		utils.AssertEqual(t, StatusCreated, r.status)
+		utils.AssertEqual(t, "hello john", r.body)
+		// Locals are closed after the after handlers ran
+		utils.AssertEqual(t, "after1 after2 ", r.closed)
+	case <-time.After(time.Second):
+		t.Fatal("after handlers did not run")
+	}
+
+	// Dispatch runs the after-response phase before returning
+	closed = ""
+	dresp, err := app.Dispatch(MethodGet, "/doe", nil, nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "hello doe", string(dresp.Body()))
+	r := <-records
+	utils.AssertEqual(t, "hello doe", r.body)
+	utils.AssertEqual(t, "after1 after2 resource ", closed)
+
+	// Requests without a matching route
+	_, err = app.Dispatch(MethodGet, "/", nil, nil)
+	utils.AssertEqual(t, nil, err)
+	r = <-records
+	utils.AssertEqual(t, StatusNotFound, r.status)
+}
@@ -0,0 +1,18 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+// Command fibervet reports common misuses of fiber, standalone or with go vet:
+//
+//	fibervet ./...
+//	go vet -vettool=$(which fibervet) ./...
+package main
+
+import (
+	"github.com/gofiber/fiber/v2/analysis/fibervet"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(fibervet.Analyzer)
+}
@@ -0,0 +1,311 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+// Package fibervet defines an analyzer, which reports common misuses of
+// fiber in user code, see the README of the analysis module.
+package fibervet
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const fiberPath = "github.com/gofiber/fiber/v2"
+
+const doc = `report common misuses of fiber
+
+The fibervet analyzer reports:
+  - *fiber.Ctx values and their zero-copy strings and byte slices, which are
+    used by goroutines, although they are reused once the handler returned
+  - discarded errors of Ctx.Next and missing returns after the error check
+  - BodyParser, QueryParser, ... and the Binder methods binding into values,
+    which aren't pointers`
+
+// Analyzer reports common misuses of fiber
+var Analyzer = &analysis.Analyzer{
+	Name:     "fibervet",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// zeroCopy are the methods of Ctx, whose results are only valid until the
+// handler returned, unless Config.Immutable is set
+var zeroCopy = map[string]bool{
+	"Body":          true,
+	"Cookies":       true,
+	"FormValue":     true,
+	"Get":           true,
+	"GetRespHeader": true,
+	"Hostname":      true,
+	"IP":            true,
+	"OriginalURL":   true,
+	"Params":        true,
+	"Path":          true,
+	"Query":         true,
+}
+
+// binders are the methods binding the request into their argument
+var binders = map[string]map[string]bool{
+	"Ctx": {
+		"BodyParser":      true,
+		"ParamsParser":    true,
+		"QueryParser":     true,
+		"ReqHeaderParser": true,
+	},
+	"Binder": {
+		"Body":    true,
+		"Header":  true,
+		"Params":  true,
+		"Query":   true,
+		"Request": true,
+	},
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// Variables holding zero-copy results of Ctx methods
+	unsafe := make(map[types.Object]string)
+	insp.Preorder([]ast.Node{(*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}, func(n ast.Node) {
+		var lhs []*ast.Ident
+		var rhs []ast.Expr
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, expr := range n.Lhs {
+				id, _ := expr.(*ast.Ident)
+				lhs = append(lhs, id)
+			}
+			rhs = n.Rhs
+		case *ast.ValueSpec:
+			lhs, rhs = n.Names, n.Values
+		}
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, expr := range rhs {
+			if lhs[i] == nil {
+				continue
+			}
+			if name, ok := ctxMethod(pass, expr, "Ctx"); ok && zeroCopy[name] {
+				if obj := pass.TypesInfo.ObjectOf(lhs[i]); obj != nil {
+					unsafe[obj] = name
+				}
+			}
+		}
+	})
+
+	nodes := []ast.Node{(*ast.GoStmt)(nil), (*ast.ExprStmt)(nil), (*ast.AssignStmt)(nil), (*ast.IfStmt)(nil), (*ast.CallExpr)(nil)}
+	insp.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.GoStmt:
+			checkGoroutine(pass, n, unsafe)
+		case *ast.ExprStmt:
+			if name, ok := ctxMethod(pass, n.X, "Ctx"); ok && name == "Next" {
+				pass.Reportf(n.Pos(), "the error of Ctx.Next is discarded, return it to the error handler")
+			}
+		case *ast.AssignStmt:
+			if len(n.Rhs) == 1 && isBlank(n.Lhs[0]) {
+				if name, ok := ctxMethod(pass, n.Rhs[0], "Ctx"); ok && name == "Next" {
+					pass.Reportf(n.Pos(), "the error of Ctx.Next is discarded, return it to the error handler")
+				}
+			}
+		case *ast.IfStmt:
+			checkNextError(pass, n)
+		case *ast.CallExpr:
+			checkBinding(pass, n)
+		}
+	})
+	return nil, nil
+}
+
+// checkGoroutine reports the Ctx values and zero-copy results used by the goroutine
+func checkGoroutine(pass *analysis.Pass, stmt *ast.GoStmt, unsafe map[types.Object]string) {
+	reported := make(map[types.Object]bool)
+	report := func(id *ast.Ident, inside ast.Node) {
+		obj := pass.TypesInfo.Uses[id]
+		if obj == nil || reported[obj] {
+			return
+		}
+		// Values declared by the goroutine itself are fine
+		if inside != nil && obj.Pos() >= inside.Pos() && obj.Pos() < inside.End() {
+			return
+		}
+		if isCtx(obj.Type()) {
+			reported[obj] = true
+			pass.Reportf(id.Pos(), "*fiber.Ctx %s is used by a goroutine, but it's reused once the handler returned; copy the values the goroutine needs", id.Name)
+		} else if method, ok := unsafe[obj]; ok {
+			reported[obj] = true
+			pass.Reportf(id.Pos(), "%s holds the result of Ctx.%s and is used by a goroutine, but it's only valid until the handler returned; copy it, e.g. with utils.CopyString", id.Name, method)
+		}
+	}
+
+	// Arguments are evaluated before the goroutine starts, but still shared
+	for _, arg := range stmt.Call.Args {
+		ast.Inspect(arg, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			if id, ok := n.(*ast.Ident); ok {
+				report(id, nil)
+			}
+			return true
+		})
+	}
+	ast.Inspect(stmt.Call.Fun, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			report(id, stmt.Call.Fun)
+		}
+		return true
+	})
+	for _, arg := range stmt.Call.Args {
+		if lit, ok := arg.(*ast.FuncLit); ok {
+			ast.Inspect(lit, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok {
+					report(id, lit)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// checkNextError reports if statements checking the error of Ctx.Next
+// without returning it:
+//
+//	if err := c.Next(); err != nil {
+//		log.Println(err)
+//	}
+func checkNextError(pass *analysis.Pass, stmt *ast.IfStmt) {
+	assign, ok := stmt.Init.(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return
+	}
+	if name, ok := ctxMethod(pass, assign.Rhs[0], "Ctx"); !ok || name != "Next" {
+		return
+	}
+	returns := false
+	ast.Inspect(stmt.Body, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			returns = true
+		case *ast.CallExpr:
+			// e.g. panic or log.Fatal
+			returns = isNoReturn(pass, n.(*ast.CallExpr))
+		}
+		return !returns
+	})
+	if !returns {
+		pass.Reportf(stmt.Pos(), "missing return after the error of Ctx.Next, the handler continues with an unfinished response")
+	}
+}
+
+// checkBinding reports binders called with values, which aren't pointers
+func checkBinding(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) != 1 {
+		return
+	}
+	var method string
+	for recv, methods := range binders {
+		if name, ok := ctxMethod(pass, call, recv); ok && methods[name] {
+			method = recv + "." + name
+		}
+	}
+	if method == "" {
+		return
+	}
+	typ := pass.TypesInfo.TypeOf(call.Args[0])
+	if typ == nil {
+		return
+	}
+	switch typ.Underlying().(type) {
+	case *types.Pointer, *types.Map, *types.Interface:
+		return
+	}
+	pass.Reportf(call.Args[0].Pos(), "%s binds into a non-pointer value of type %s, the result is lost; pass a pointer", method, typ)
+}
+
+// ctxMethod returns the name of the method of the fiber type recv called by expr
+func ctxMethod(pass *analysis.Pass, expr ast.Expr, recv string) (string, bool) {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = paren.X
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok {
+		return "", false
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil || !isFiberType(sig.Recv().Type(), recv) {
+		return "", false
+	}
+	return fn.Name(), true
+}
+
+// isCtx reports whether typ is *fiber.Ctx
+func isCtx(typ types.Type) bool {
+	ptr, ok := typ.(*types.Pointer)
+	return ok && isFiberType(ptr.Elem(), "Ctx")
+}
+
+// isFiberType reports whether typ is the fiber type name or a pointer to it
+func isFiberType(typ types.Type, name string) bool {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == fiberPath && obj.Name() == name
+}
+
+// isNoReturn reports whether the call never returns, e.g. panic
+func isNoReturn(pass *analysis.Pass, call *ast.CallExpr) bool {
+	var id *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return false
+	}
+	switch obj := pass.TypesInfo.Uses[id].(type) {
+	case *types.Builtin:
+		return obj.Name() == "panic"
+	case *types.Func:
+		if obj.Pkg() == nil {
+			return false
+		}
+		switch obj.Pkg().Path() + "." + obj.Name() {
+		case "log.Fatal", "log.Fatalf", "log.Fatalln", "log.Panic", "log.Panicf", "log.Panicln", "os.Exit":
+			return true
+		}
+	}
+	return false
+}
+
+func isBlank(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == "_"
+}
@@ -0,0 +1,64 @@
+package a
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type user struct {
+	Name string
+}
+
+func process(id string) {}
+
+func goroutines(c *fiber.Ctx) error {
+	id := c.Params("id")
+	tenant := string(c.Get("X-Tenant"))
+	method := c.Method()
+	body := c.Body()
+
+	go func() {
+		_ = c.SendString("done") // want `\*fiber.Ctx c is used by a goroutine`
+		process(id)              // want `id holds the result of Ctx.Params and is used by a goroutine`
+		process(tenant)
+		process(method)
+		log.Println(body) // want `body holds the result of Ctx.Body and is used by a goroutine`
+	}()
+
+	go process(id) // want `id holds the result of Ctx.Params`
+
+	go func(c *fiber.Ctx) {}(nil)
+	return nil
+}
+
+func next(c *fiber.Ctx) error {
+	c.Next()     // want `the error of Ctx.Next is discarded`
+	_ = c.Next() // want `the error of Ctx.Next is discarded`
+
+	if err := c.Next(); err != nil { // want `missing return after the error of Ctx.Next`
+		log.Println(err)
+	}
+	if err := c.Next(); err != nil {
+		log.Fatal(err)
+	}
+	if err := c.Next(); err != nil {
+		return err
+	}
+	return c.Next()
+}
+
+func binding(c *fiber.Ctx) error {
+	var u user
+	if err := c.BodyParser(u); err != nil { // want `Ctx.BodyParser binds into a non-pointer value of type a.user`
+		return err
+	}
+	if err := c.Binder().Query(u); err != nil { // want `Binder.Query binds into a non-pointer value`
+		return err
+	}
+	m := map[string]string{}
+	var out interface{} = &u
+	_ = c.QueryParser(m)
+	_ = c.QueryParser(out)
+	return c.BodyParser(&u)
+}
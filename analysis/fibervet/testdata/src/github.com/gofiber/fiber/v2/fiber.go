@@ -0,0 +1,18 @@
+// Package fiber is a stub of the methods fibervet knows about
+package fiber
+
+type Ctx struct{}
+
+func (c *Ctx) Next() error                       { return nil }
+func (c *Ctx) Params(key string) string          { return "" }
+func (c *Ctx) Get(key string) string             { return "" }
+func (c *Ctx) Body() []byte                      { return nil }
+func (c *Ctx) Method() string                    { return "" }
+func (c *Ctx) SendString(body string) error      { return nil }
+func (c *Ctx) BodyParser(out interface{}) error  { return nil }
+func (c *Ctx) QueryParser(out interface{}) error { return nil }
+func (c *Ctx) Binder() *Binder                   { return nil }
+
+type Binder struct{}
+
+func (b *Binder) Query(out interface{}) error { return nil }
@@ -10,11 +10,13 @@ package fiber
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
@@ -25,6 +27,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 
+	"github.com/gofiber/fiber/v2/internal/cbor"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/valyala/fasthttp"
 )
@@ -62,6 +65,25 @@ type Storage interface {
 	Close() error
 }
 
+// StorageWithContext is a Storage with context-aware operations, so that
+// remote storages can cancel their requests, e.g. with the context of
+// Ctx.UserContext. The storage of the storage/memory package implements it.
+type StorageWithContext interface {
+	Storage
+
+	// GetWithContext gets the value for the given key like Get.
+	GetWithContext(ctx context.Context, key string) ([]byte, error)
+
+	// SetWithContext stores the value for the given key like Set.
+	SetWithContext(ctx context.Context, key string, val []byte, exp time.Duration) error
+
+	// DeleteWithContext deletes the value for the given key like Delete.
+	DeleteWithContext(ctx context.Context, key string) error
+
+	// ResetWithContext resets the storage like Reset.
+	ResetWithContext(ctx context.Context) error
+}
+
 // ErrorHandler defines a function that will process all errors
 // returned from any handlers in the stack
 //
@@ -93,6 +115,8 @@ type App struct {
 	treeStack []map[string][]*Route
 	// contains the information if the route stack has been changed to build the optimized tree
 	routesRefreshed bool
+	// 1 if the app or a mounted app registered routes since the tree was built, see refreshTree
+	treeStale uint32
 	// Amount of registered routes
 	routesCount uint32
 	// Amount of registered handlers
@@ -109,13 +133,30 @@ type App struct {
 	getString func(b []byte) string
 	// Mounted and main apps
 	appList map[string]*App
+	// Mounted sub apps, see Mount
+	mounts []*mountedApp
+	// Parent app and the prefix along which the app is mounted
+	parent      *App
+	mountPrefix string
 	// Hooks
 	hooks *Hooks
-	// Latest route & group
+	// Latest route
 	latestRoute *Route
-	latestGroup *Group
 	// TLS handler
 	tlsHandler *TLSHandler
+	// Languages of the message catalog, see Ctx.Message
+	messageLanguages []string
+	// Handlers of the after-response phase, see UseAfter
+	afterHandlers []AfterHandler
+	// Set to 1 once Shutdown started draining, see Draining
+	draining uint32
+	// Closed on shutdown, cancels the request contexts
+	done     chan struct{}
+	doneOnce sync.Once
+	// Upgraded WebSocket connections, closed on shutdown
+	websockets webSockets
+	// Open connections of the server, closed once the shutdown timed out
+	conns connSet
 }
 
 // Config is a struct holding the server settings.
@@ -159,12 +200,27 @@ type Config struct {
 	// Default: false
 	UnescapePath bool `json:"unescape_path"`
 
-	// Enable or disable ETag header generation, since both weak and strong etags are generated
-	// using the same hashing method (CRC-32). Weak ETags are the default when enabled.
+	// Enable or disable strong ETag header generation, requests with a matching
+	// If-None-Match header get a 304 Not Modified response. Routes can override it, see App.ETag.
 	//
 	// Default: false
 	ETag bool `json:"etag"`
 
+	// JSONETag enables the ETag generation like ETag, but the ETags of Ctx.JSON
+	// are the xxhash of the JSON or the ETag of values implementing ETagger,
+	// which aren't encoded if the If-None-Match header matches. Routes can
+	// override it, see ETagJSON.
+	//
+	// Default: false
+	JSONETag bool `json:"json_etag"`
+
+	// MockExamples serves the example response of a route, if its handler returns
+	// ErrNotImplemented. It allows clients to work against an incomplete API, see
+	// App.Example.
+	//
+	// Default: false
+	MockExamples bool `json:"mock_examples"`
+
 	// Max body size that the server accepts.
 	// -1 will decline any body size
 	//
@@ -210,6 +266,29 @@ type Config struct {
 	// Default: unlimited
 	IdleTimeout time.Duration `json:"idle_timeout"`
 
+	// The period Shutdown drains the app before it closes the listeners.
+	// Meanwhile App.Draining reports true, so that readiness probes fail,
+	// see the healthcheck middleware, and load balancers stop sending traffic
+	// while the requests in flight are still served.
+	//
+	// Default: 0
+	ShutdownDrainPeriod time.Duration `json:"shutdown_drain_period"`
+
+	// When set to true, responses are sent with "Connection: close" during
+	// the drain period, so that clients reconnect to other instances instead
+	// of reusing their keep-alive connections.
+	//
+	// Default: false
+	DrainCloseConnections bool `json:"drain_close_connections"`
+
+	// The period Shutdown waits for WebSocket connections to return after
+	// it sent them a close frame with CloseGoingAway. Connections still open
+	// afterwards are closed forcibly and reported by Shutdown, see
+	// WebSocketShutdownError.
+	//
+	// Default: 5 * time.Second
+	WebSocketShutdownTimeout time.Duration `json:"websocket_shutdown_timeout"`
+
 	// Per-connection buffer size for requests' reading.
 	// This also limits the maximum header size.
 	// Increase this buffer if your clients send multi-KB RequestURIs
@@ -295,6 +374,12 @@ type Config struct {
 	// Server pre parses multipart form data by default.
 	DisablePreParseMultipartForm bool
 
+	// Multipart defines per-field and per-file size limits and allowed
+	// file types, which are enforced while parsing multipart forms.
+	//
+	// Default: MultipartConfig{}
+	Multipart MultipartConfig `json:"multipart"`
+
 	// Aggressively reduces memory usage at the cost of higher CPU usage
 	// if set to true.
 	//
@@ -323,7 +408,8 @@ type Config struct {
 	// When set by an external client of Fiber it will use the provided implementation of a
 	// JSONUnmarshal
 	//
-	// Allowing for flexibility in using another json library for decoding
+	// Allowing for flexibility in using another json library for decoding.
+	// See NewJSONDecoder for the handling of numeric strings and large numbers.
 	// Default: json.Unmarshal
 	JSONDecoder utils.JSONUnmarshal `json:"-"`
 
@@ -334,6 +420,51 @@ type Config struct {
 	// Default: xml.Marshal
 	XMLEncoder utils.XMLMarshal `json:"-"`
 
+	// MsgPackEncoder is used by Ctx.MsgPack for encoding MessagePack.
+	//
+	// Allowing for flexibility in using another MessagePack library, e.g. one
+	// encoding structs by reflection.
+	// Default: types generated by github.com/tinylib/msgp, maps, slices and basic types
+	MsgPackEncoder utils.MsgPackMarshal `json:"-"`
+
+	// MsgPackDecoder is used by BodyParser for decoding MessagePack.
+	//
+	// Allowing for flexibility in using another MessagePack library, e.g. one
+	// decoding structs by reflection.
+	// Default: types generated by github.com/tinylib/msgp and interface{}
+	MsgPackDecoder utils.MsgPackUnmarshal `json:"-"`
+
+	// ProtobufEncoder is used by Ctx.Protobuf for encoding protobuf messages,
+	// so that Fiber doesn't depend on a protobuf library:
+	//
+	//	ProtobufEncoder: func(v interface{}) ([]byte, error) {
+	//		return proto.Marshal(v.(proto.Message))
+	//	},
+	//
+	// Default: the Marshal method of messages generated by gogo/protobuf or similar
+	ProtobufEncoder utils.ProtobufMarshal `json:"-"`
+
+	// ProtobufDecoder is used by BodyParser for decoding protobuf messages.
+	//
+	//	ProtobufDecoder: func(data []byte, v interface{}) error {
+	//		return proto.Unmarshal(data, v.(proto.Message))
+	//	},
+	//
+	// Default: the Unmarshal method of messages generated by gogo/protobuf or similar
+	ProtobufDecoder utils.ProtobufUnmarshal `json:"-"`
+
+	// CBOREncoder is used by Ctx.CBOR for encoding CBOR.
+	//
+	// Allowing for flexibility in using another CBOR library, e.g. github.com/fxamacker/cbor
+	// Default: a built-in encoder, which encodes structs as maps keyed by their cbor or json tags
+	CBOREncoder utils.CBORMarshal `json:"-"`
+
+	// CBORDecoder is used by BodyParser for decoding CBOR.
+	//
+	// Allowing for flexibility in using another CBOR library, e.g. github.com/fxamacker/cbor
+	// Default: a built-in decoder, which decodes maps into structs by their cbor or json tags
+	CBORDecoder utils.CBORUnmarshal `json:"-"`
+
 	// Known networks are "tcp", "tcp4" (IPv4-only), "tcp6" (IPv6-only)
 	// WARNING: When prefork is set to true, only "tcp4" and "tcp6" can be chose.
 	//
@@ -384,6 +515,56 @@ type Config struct {
 	//
 	// Optional. Default: DefaultColors
 	ColorScheme Colors `json:"color_scheme"`
+
+	// Messages overrides the user-facing messages of the built-in middleware,
+	// e.g. to translate them. See Ctx.Message.
+	//
+	// Default: nil
+	Messages Messages `json:"-"`
+
+	// NegotiationFallback is the MIME type of the offer, which Ctx.Negotiate
+	// responds with if the client accepts none of the offers. If it's empty or
+	// not offered, Negotiate returns ErrNotAcceptable.
+	//
+	// Default: ""
+	NegotiationFallback string `json:"negotiation_fallback"`
+
+	// RequestMethods are the HTTP methods the app routes, requests with other
+	// methods are answered with 400 Bad Request. Extend the default methods
+	// to route custom methods, e.g. the ones of WebDAV:
+	//
+	//	RequestMethods: append(fiber.DefaultMethods, "PROPFIND", "REPORT")
+	//
+	// Default: DefaultMethods
+	RequestMethods []string `json:"request_methods"`
+
+	// Binder configures how BodyParser, QueryParser, ReqHeaderParser,
+	// ParamsParser and BindTo bind requests to structs, e.g. their strictness
+	// and limits. See BinderConfig.
+	//
+	// Default: BinderConfig{}
+	Binder BinderConfig `json:"binder"`
+
+	// NewCtxFunc creates the custom context of a framework built on fiber,
+	// which embeds *Ctx to add typed methods. It's created once per pooled
+	// Ctx and returned by Ctx.Custom, see CtxOf and HandlerOf. A context with
+	// a Reset method is reset before the Ctx is reused for another request.
+	//
+	// Default: nil
+	NewCtxFunc func(c *Ctx) interface{} `json:"-"`
+
+	// IDFormat is the format of the IDs generated by Ctx.NewID, which the
+	// middleware use for request IDs, e.g. IDFormatXID or IDFormatULID.
+	//
+	// Default: IDFormatUUIDv7
+	IDFormat string `json:"id_format"`
+
+	// RandSeed seeds Ctx.Rand for every request, which makes the random
+	// numbers and the IDs of Ctx.NewID reproducible, e.g. in tests. Zero
+	// seeds every request randomly.
+	//
+	// Default: 0
+	RandSeed int64 `json:"rand_seed"`
 }
 
 // Static defines configuration options when defining static assets.
@@ -393,7 +574,8 @@ type Static struct {
 	// Optional. Default value false
 	Compress bool `json:"compress"`
 
-	// When set to true, enables byte range requests.
+	// When set to true, enables byte range requests including If-Range and
+	// multiple ranges, which are served as multipart/byteranges.
 	// Optional. Default value false
 	ByteRange bool `json:"byte_range"`
 
@@ -409,6 +591,12 @@ type Static struct {
 	// Optional. Default value "index.html".
 	Index string `json:"index"`
 
+	// When set to true, precompressed files are served to the clients
+	// accepting their encoding, e.g. "app.js.br" for "app.js". Brotli (.br),
+	// zstd (.zst) and gzip (.gz) files are supported.
+	// Optional. Default value false.
+	Precompressed bool `json:"precompressed"`
+
 	// Expiration duration for inactive file handlers.
 	// Use a negative time.Duration to disable it.
 	//
@@ -442,6 +630,8 @@ const (
 	DefaultReadBufferSize       = 4096
 	DefaultWriteBufferSize      = 4096
 	DefaultCompressedFileSuffix = ".fiber.gz"
+
+	DefaultWebSocketShutdownTimeout = 5 * time.Second
 )
 
 // DefaultErrorHandler that process return errors from handlers
@@ -468,9 +658,6 @@ var DefaultErrorHandler = func(c *Ctx, err error) error {
 func New(config ...Config) *App {
 	// Create a new app
 	app := &App{
-		// Create router stack
-		stack:     make([][]*Route, len(intMethod)),
-		treeStack: make([]map[string][]*Route, len(intMethod)),
 		// Create Ctx pool
 		pool: sync.Pool{
 			New: func() interface{} {
@@ -483,7 +670,6 @@ func New(config ...Config) *App {
 		getString:   utils.UnsafeString,
 		appList:     make(map[string]*App),
 		latestRoute: &Route{},
-		latestGroup: &Group{},
 	}
 
 	// Define hooks
@@ -494,16 +680,16 @@ func New(config ...Config) *App {
 		app.config = config[0]
 	}
 
-	if app.config.ETag {
-		if !IsChild() {
-			fmt.Println("[Warning] Config.ETag is deprecated since v2.0.6, please use 'middleware/etag'.")
-		}
-	}
-
 	// Override default values
 	if app.config.BodyLimit == 0 {
 		app.config.BodyLimit = DefaultBodyLimit
 	}
+	if app.config.IDFormat == "" {
+		app.config.IDFormat = IDFormatUUIDv7
+	}
+	if app.config.WebSocketShutdownTimeout <= 0 {
+		app.config.WebSocketShutdownTimeout = DefaultWebSocketShutdownTimeout
+	}
 	if app.config.Concurrency <= 0 {
 		app.config.Concurrency = DefaultConcurrency
 	}
@@ -533,9 +719,40 @@ func New(config ...Config) *App {
 	if app.config.XMLEncoder == nil {
 		app.config.XMLEncoder = xml.Marshal
 	}
+	if app.config.MsgPackEncoder == nil {
+		app.config.MsgPackEncoder = msgpackMarshal
+	}
+	if app.config.MsgPackDecoder == nil {
+		app.config.MsgPackDecoder = msgpackUnmarshal
+	}
+	if app.config.ProtobufEncoder == nil {
+		app.config.ProtobufEncoder = protobufMarshal
+	}
+	if app.config.ProtobufDecoder == nil {
+		app.config.ProtobufDecoder = protobufUnmarshal
+	}
+	if app.config.CBOREncoder == nil {
+		app.config.CBOREncoder = cbor.Marshal
+	}
+	if app.config.CBORDecoder == nil {
+		app.config.CBORDecoder = cbor.Unmarshal
+	}
 	if app.config.Network == "" {
 		app.config.Network = NetworkTCP4
 	}
+	if len(app.config.RequestMethods) == 0 {
+		app.config.RequestMethods = DefaultMethods
+	} else {
+		methods := make([]string, len(app.config.RequestMethods))
+		for i, method := range app.config.RequestMethods {
+			methods[i] = utils.ToUpper(method)
+		}
+		app.config.RequestMethods = methods
+	}
+
+	// Create router stack
+	app.stack = make([][]*Route, len(app.config.RequestMethods))
+	app.treeStack = make([]map[string][]*Route, len(app.config.RequestMethods))
 
 	app.config.trustedProxiesMap = make(map[string]struct{}, len(app.config.TrustedProxies))
 	for _, ipAddress := range app.config.TrustedProxies {
@@ -583,36 +800,114 @@ func (app *App) SetTLSHandler(tlsHandler *TLSHandler) {
 // compose them as a single service using Mount. The fiber's error handler and
 // any of the fiber's sub apps are added to the application's error handlers
 // to be invoked on errors that happen within the prefix route.
+//
+// Routes, which the fiber registers after Mount, are merged when the app starts.
+// Handlers of the fiber get the prefix by Ctx.MountPath.
 func (app *App) Mount(prefix string, fiber *App) Router {
-	stack := fiber.Stack()
 	prefix = strings.TrimRight(prefix, "/")
 	if prefix == "" {
 		prefix = "/"
 	}
+	app.mount(prefix, fiber)
+
+	return app
+}
+
+// mountedApp is a sub app, which is mounted along the prefix
+type mountedApp struct {
+	prefix   string
+	app      *App
+	copied   map[*Route]struct{}
+	handlers uint32
+}
+
+// mount attaches the sub app along the prefix and copies its routes
+func (app *App) mount(prefix string, sub *App) {
+	sub.mutex.Lock()
+	sub.mountPrefix, sub.parent = prefix, app
+	sub.mutex.Unlock()
+
+	m := &mountedApp{prefix: prefix, app: sub, copied: make(map[*Route]struct{})}
+	app.mutex.Lock()
+	app.mounts = append(app.mounts, m)
+	app.mutex.Unlock()
+	app.markTreeStale()
+	app.mergeMount(m)
+
+	if err := sub.hooks.executeOnMountHooks(app); err != nil {
+		panic(err)
+	}
+}
+
+// mergeMounts copies the routes, which the mounted apps registered after Mount
+func (app *App) mergeMounts() {
+	app.mutex.Lock()
+	mounts := app.mounts
+	app.mutex.Unlock()
+	for _, m := range mounts {
+		app.mergeMount(m)
+	}
+}
 
-	for m := range stack {
-		for r := range stack[m] {
-			route := app.copyRoute(stack[m][r])
-			app.addRoute(route.Method, app.addPrefixToRoute(prefix, route))
+// mergeMount copies the routes of the mounted app, which aren't copied yet
+func (app *App) mergeMount(m *mountedApp) {
+	sub := m.app
+	// Routes of mounted apps of the sub app are copied to the sub app first
+	sub.mergeMounts()
+
+	stack := sub.Stack()
+	for i := range stack {
+		for _, r := range stack[i] {
+			if _, ok := m.copied[r]; ok {
+				continue
+			}
+			m.copied[r] = struct{}{}
+			route := app.copyRoute(r)
+			if route.mount == nil {
+				route.mount = sub
+			}
+			app.addRoute(route.Method, app.addPrefixToRoute(m.prefix, route))
 		}
 	}
 
 	// Support for configs of mounted-apps and sub-mounted-apps
-	for mountedPrefixes, subApp := range fiber.appList {
-		app.appList[prefix+mountedPrefixes] = subApp
-		subApp.init()
+	sub.mutex.Lock()
+	appList := make(map[string]*App, len(sub.appList))
+	for mountedPrefixes, subApp := range sub.appList {
+		appList[mountedPrefixes] = subApp
+	}
+	sub.mutex.Unlock()
+	for mountedPrefixes, subApp := range appList {
+		key := m.prefix + mountedPrefixes
+		if mountedPrefixes != "" {
+			key = strings.TrimRight(m.prefix, "/") + mountedPrefixes
+		}
+		if app.appList[key] != subApp {
+			app.appList[key] = subApp
+			subApp.init()
+		}
 	}
 
-	atomic.AddUint32(&app.handlersCount, fiber.handlersCount)
+	handlers := atomic.LoadUint32(&sub.handlersCount)
+	atomic.AddUint32(&app.handlersCount, handlers-m.handlers)
+	m.handlers = handlers
+}
 
-	return app
+// MountPath returns the path along which the app is mounted, including the
+// prefixes of its parents, e.g. "/api/admin". It's empty for the root app.
+func (app *App) MountPath() string {
+	if app.parent == nil {
+		return ""
+	}
+	return strings.TrimRight(app.parent.MountPath()+app.mountPrefix, "/")
 }
 
 // Assign name to specific route.
 func (app *App) Name(name string) Router {
 	app.mutex.Lock()
-	if strings.HasPrefix(app.latestRoute.path, app.latestGroup.Prefix) {
-		app.latestRoute.Name = app.latestGroup.name + name
+	// Routes of a group inherit the names of the group and its parents
+	if grp := app.latestRoute.group; grp != nil {
+		app.latestRoute.Name = grp.name + name
 	} else {
 		app.latestRoute.Name = name
 	}
@@ -620,11 +915,85 @@ func (app *App) Name(name string) Router {
 	if err := app.hooks.executeOnNameHooks(*app.latestRoute); err != nil {
 		panic(err)
 	}
+	for _, grp := range app.latestRoute.group.lineage() {
+		if err := grp.hooks.executeOnNameHooks(*app.latestRoute); err != nil {
+			panic(err)
+		}
+	}
 	app.mutex.Unlock()
 
 	return app
 }
 
+// RateLimit declares a rate limit for the latest registered route, which is
+// enforced by the limiter middleware instead of its global limit:
+//
+//	app.Use(limiter.New())
+//	app.Post("/login", handler)
+//	app.RateLimit(5, time.Minute)
+//
+// The key generator of the limiter is used if keyGenerator is omitted.
+func (app *App) RateLimit(max int, expiration time.Duration, keyGenerator ...func(*Ctx) string) *App {
+	limit := &RateLimit{
+		Max:        max,
+		Expiration: expiration,
+	}
+	if len(keyGenerator) > 0 {
+		limit.KeyGenerator = keyGenerator[0]
+	}
+
+	app.mutex.Lock()
+	for _, route := range app.latestRoutes() {
+		route.RateLimit = limit
+	}
+	app.mutex.Unlock()
+
+	return app
+}
+
+// Meta attaches metadata to the latest registered route, e.g. for route
+// listings, API docs or middleware:
+//
+//	app.Get("/health", handler)
+//	app.Meta("auth", "none")
+//
+// The metadata is available by Route.Metadata, see App.GetRoutes, and to
+// middleware by Ctx.RouteMeta.
+func (app *App) Meta(key string, value interface{}) *App {
+	app.mutex.Lock()
+	for _, route := range app.latestRoutes() {
+		metadata := make(map[string]interface{}, len(route.Metadata)+1)
+		for k, v := range route.Metadata {
+			metadata[k] = v
+		}
+		metadata[key] = value
+		route.Metadata = metadata
+	}
+	app.mutex.Unlock()
+
+	return app
+}
+
+// latestRoutes returns the latest registered route with the routes registered
+// alongside it, which are the HEAD route of GET routes and the copies of
+// middleware for the other methods
+func (app *App) latestRoutes() []*Route {
+	latest := app.latestRoute
+	if latest == nil {
+		return nil
+	}
+	routes := []*Route{latest}
+	for m, stack := range app.stack {
+		if !latest.use && (latest.Method != MethodGet || m != app.methodInt(MethodHead)) {
+			continue
+		}
+		if l := len(stack); l > 0 && stack[l-1] != latest && stack[l-1].use == latest.use && stack[l-1].Path == latest.Path {
+			routes = append(routes, stack[l-1])
+		}
+	}
+	return routes
+}
+
 // Get route by name
 func (app *App) GetRoute(name string) Route {
 	for _, routes := range app.stack {
@@ -638,6 +1007,85 @@ func (app *App) GetRoute(name string) Route {
 	return Route{}
 }
 
+// RouteURL generates the URL of the named route with the params, e.g. for
+// templates and redirects:
+//
+//	app.Get("/users/:id", handler).Name("user.show")
+//	app.RouteURL("user.show", fiber.Map{"id": 5, "tab": "posts"}) // "/users/5?tab=posts"
+//
+// The values of the params are escaped, params which aren't part of the path
+// are appended as query string. Wildcard params are set by "*" or "+". It fails
+// if the route doesn't exist or a required param is missing.
+func (app *App) RouteURL(name string, params Map) (string, error) {
+	route := app.GetRoute(name)
+	if route.Name == "" {
+		return "", fmt.Errorf("route %q not found", name)
+	}
+
+	used := make(map[string]bool, len(params))
+	var b strings.Builder
+	for _, segment := range parseRoute(route.Path).segs {
+		if !segment.IsParam {
+			b.WriteString(RemoveEscapeChar(segment.Const))
+			continue
+		}
+
+		key, val, ok := routeParam(params, segment, app.config.CaseSensitive)
+		if !ok {
+			if segment.IsOptional {
+				continue
+			}
+			return "", fmt.Errorf("route %q: missing param %q", name, segment.ParamName)
+		}
+		used[key] = true
+		value := utils.ToString(val)
+		if segment.IsGreedy {
+			// Wildcards span several segments
+			parts := strings.Split(value, "/")
+			for i := range parts {
+				parts[i] = url.PathEscape(parts[i])
+			}
+			b.WriteString(strings.Join(parts, "/"))
+		} else {
+			b.WriteString(url.PathEscape(value))
+		}
+	}
+
+	query := url.Values{}
+	for key, val := range params {
+		if used[key] {
+			continue
+		}
+		switch val := val.(type) {
+		case []string:
+			query[key] = append(query[key], val...)
+		default:
+			query.Add(key, utils.ToString(val))
+		}
+	}
+	if len(query) > 0 {
+		b.WriteString("?" + query.Encode())
+	}
+	return b.String(), nil
+}
+
+// routeParam returns the param of the route segment
+func routeParam(params Map, segment *routeSegment, caseSensitive bool) (string, interface{}, bool) {
+	if val, ok := params[segment.ParamName]; ok {
+		return segment.ParamName, val, true
+	}
+	for key, val := range params {
+		if !caseSensitive && utils.EqualFold(key, segment.ParamName) {
+			return key, val, true
+		}
+		// The first wildcard may be set without its iterator, e.g. "*" for "*1"
+		if segment.IsGreedy && len(key) == 1 && key[0] == segment.ParamName[0] && segment.ParamName[1:] == "1" {
+			return key, val, true
+		}
+	}
+	return "", nil, false
+}
+
 // Use registers a middleware route that will match requests
 // with the provided prefix (which is optional and defaults to "/").
 //
@@ -666,66 +1114,92 @@ func (app *App) Use(args ...interface{}) Router {
 			panic(fmt.Sprintf("use: invalid handler %v\n", reflect.TypeOf(arg)))
 		}
 	}
-	app.register(methodUse, prefix, handlers...)
+	app.register(methodUse, prefix, nil, handlers...)
 	return app
 }
 
 // Get registers a route for GET methods that requests a representation
 // of the specified resource. Requests using GET should only retrieve data.
 func (app *App) Get(path string, handlers ...Handler) Router {
-	return app.Head(path, handlers...).Add(MethodGet, path, handlers...)
+	app.register(MethodHead, path, nil, handlers...)
+	return app.register(MethodGet, path, nil, handlers...)
 }
 
 // Head registers a route for HEAD methods that asks for a response identical
 // to that of a GET request, but without the response body.
 func (app *App) Head(path string, handlers ...Handler) Router {
-	return app.Add(MethodHead, path, handlers...)
+	return app.register(MethodHead, path, nil, handlers...)
 }
 
 // Post registers a route for POST methods that is used to submit an entity to the
 // specified resource, often causing a change in state or side effects on the server.
 func (app *App) Post(path string, handlers ...Handler) Router {
-	return app.Add(MethodPost, path, handlers...)
+	return app.register(MethodPost, path, nil, handlers...)
 }
 
 // Put registers a route for PUT methods that replaces all current representations
 // of the target resource with the request payload.
 func (app *App) Put(path string, handlers ...Handler) Router {
-	return app.Add(MethodPut, path, handlers...)
+	return app.register(MethodPut, path, nil, handlers...)
 }
 
 // Delete registers a route for DELETE methods that deletes the specified resource.
 func (app *App) Delete(path string, handlers ...Handler) Router {
-	return app.Add(MethodDelete, path, handlers...)
+	return app.register(MethodDelete, path, nil, handlers...)
 }
 
 // Connect registers a route for CONNECT methods that establishes a tunnel to the
 // server identified by the target resource.
 func (app *App) Connect(path string, handlers ...Handler) Router {
-	return app.Add(MethodConnect, path, handlers...)
+	return app.register(MethodConnect, path, nil, handlers...)
 }
 
 // Options registers a route for OPTIONS methods that is used to describe the
 // communication options for the target resource.
 func (app *App) Options(path string, handlers ...Handler) Router {
-	return app.Add(MethodOptions, path, handlers...)
+	return app.register(MethodOptions, path, nil, handlers...)
 }
 
 // Trace registers a route for TRACE methods that performs a message loop-back
 // test along the path to the target resource.
 func (app *App) Trace(path string, handlers ...Handler) Router {
-	return app.Add(MethodTrace, path, handlers...)
+	return app.register(MethodTrace, path, nil, handlers...)
 }
 
 // Patch registers a route for PATCH methods that is used to apply partial
 // modifications to a resource.
 func (app *App) Patch(path string, handlers ...Handler) Router {
-	return app.Add(MethodPatch, path, handlers...)
+	return app.register(MethodPatch, path, nil, handlers...)
 }
 
-// Add allows you to specify a HTTP method to register a route
-func (app *App) Add(method, path string, handlers ...Handler) Router {
-	return app.register(method, path, handlers...)
+// Ws registers a GET route, which upgrades the requests to WebSocket
+// connections, see Ctx.Upgrade. Params, middleware and Locals are shared
+// with the other routes:
+//
+//	app.Use("/ws", authMiddleware)
+//	app.Ws("/ws/:room", func(ws *fiber.WebSocket) {
+//		room := ws.Ctx().Params("room")
+//		...
+//	})
+func (app *App) Ws(path string, handler func(ws *WebSocket), config ...WebSocketConfig) Router {
+	return app.register(MethodGet, path, nil, func(c *Ctx) error {
+		return c.Upgrade(handler, config...)
+	})
+}
+
+// Add registers a route for the HTTP methods, e.g. custom methods:
+//
+//	app := fiber.New(fiber.Config{
+//	    RequestMethods: append(fiber.DefaultMethods, "PROPFIND", "REPORT"),
+//	})
+//	app.Add([]string{"PROPFIND", "REPORT"}, "/files/*", handler)
+//
+// The methods have to be in Config.RequestMethods.
+func (app *App) Add(methods []string, path string, handlers ...Handler) Router {
+	for _, method := range methods {
+		app.register(method, path, nil, handlers...)
+	}
+	return app
 }
 
 // Static will create a file server serving static files
@@ -733,28 +1207,25 @@ func (app *App) Static(prefix, root string, config ...Static) Router {
 	return app.registerStatic(prefix, root, config...)
 }
 
-// All will register the handler on all HTTP methods
+// All will register the handler on all HTTP methods of Config.RequestMethods
 func (app *App) All(path string, handlers ...Handler) Router {
-	for _, method := range intMethod {
-		_ = app.Add(method, path, handlers...)
-	}
-	return app
+	return app.Add(app.config.RequestMethods, path, handlers...)
 }
 
 // Group is used for Routes with common prefix to define a new sub-router with optional middleware.
 //
-//	api := app.Group("/api")
-//	api.Get("/users", handler)
+//	api := app.Group("/api", middleware).Name("api.")
+//	v1 := api.Group("/v1").Name("v1.")
+//	v1.Get("/users", handler).Name("users") // GET /api/v1/users named "api.v1.users"
+//
+// Groups can be nested, the routes of a group inherit the prefixes, middleware and
+// names of its parents. Hooks registered by Group.Hooks only run for the routes,
+// names and sub-groups of the group.
 func (app *App) Group(prefix string, handlers ...Handler) Router {
 	if len(handlers) > 0 {
-		app.register(methodUse, prefix, handlers...)
-	}
-	grp := &Group{Prefix: prefix, app: app}
-	if err := app.hooks.executeOnGroupHooks(*grp); err != nil {
-		panic(err)
+		app.register(methodUse, prefix, nil, handlers...)
 	}
-
-	return grp
+	return app.newGroup(prefix, nil)
 }
 
 // Route is used to define routes with a common prefix inside the common function.
@@ -801,6 +1272,31 @@ func (app *App) Handler() fasthttp.RequestHandler {
 	return app.handler
 }
 
+// GetRoutes returns copies of all registered routes, e.g. for route listings
+// or API docs. If filterUnique is true, middleware registered for all methods
+// by Use is listed once with the method USE instead of once per method.
+func (app *App) GetRoutes(filterUnique ...bool) []Route {
+	unique := len(filterUnique) > 0 && filterUnique[0]
+	seen := make(map[string]bool)
+
+	var routes []Route
+	for _, stack := range app.stack {
+		for _, route := range stack {
+			r := *route
+			if unique && r.use {
+				key := r.Path + " " + strings.Join(r.HandlerNames(), " ")
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				r.Method = methodUse
+			}
+			routes = append(routes, r)
+		}
+	}
+	return routes
+}
+
 // Stack returns the raw router stack.
 func (app *App) Stack() [][]*Route {
 	return app.stack
@@ -817,17 +1313,92 @@ func (app *App) HandlersCount() uint32 {
 // Make sure the program doesn't exit and waits instead for Shutdown to return.
 //
 // Shutdown does not close keepalive connections so its recommended to set ReadTimeout to something else than 0.
+//
+// With Config.ShutdownDrainPeriod, Shutdown first drains the app for the period, see Draining.
+// Upgrades to WebSocket are rejected once Shutdown has been called, and the upgraded
+// connections are closed with CloseGoingAway before the listeners are closed, see
+// Config.WebSocketShutdownTimeout.
+//
+// The OnShutdown hooks are executed once Shutdown finished, see ShutdownWithTimeout
+// to limit the wait.
 func (app *App) Shutdown() error {
+	return app.ShutdownWithContext(context.Background())
+}
+
+// ShutdownWithTimeout gracefully shuts down the server like Shutdown, but
+// closes the connections still open once the timeout expired and returns
+// context.DeadlineExceeded:
+//
+//	if err := app.ShutdownWithTimeout(30 * time.Second); err != nil {
+//		log.Printf("shutdown: %v", err)
+//	}
+func (app *App) ShutdownWithTimeout(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return app.ShutdownWithContext(ctx)
+}
+
+// ShutdownWithContext gracefully shuts down the server like Shutdown, but
+// closes the connections still open once the context is done and returns the
+// error of the context. The drain period and the wait for the WebSocket
+// connections are cut short by the context as well.
+func (app *App) ShutdownWithContext(ctx context.Context) error {
 	if app.hooks != nil {
 		defer app.hooks.executeOnShutdownHooks()
 	}
 
+	// The mutex isn't held while shutting down, handlers may call Dispatch
 	app.mutex.Lock()
-	defer app.mutex.Unlock()
-	if app.server == nil {
+	server := app.server
+	app.mutex.Unlock()
+	if server == nil {
 		return fmt.Errorf("shutdown: server is not running")
 	}
-	return app.server.Shutdown()
+
+	// Keep serving while load balancers notice the failing readiness probes
+	if atomic.CompareAndSwapUint32(&app.draining, 0, 1) && app.config.ShutdownDrainPeriod > 0 {
+		timer := time.NewTimer(app.config.ShutdownDrainPeriod)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	app.doneOnce.Do(func() {
+		close(app.done)
+	})
+
+	// Hijacked connections are not tracked by the server
+	wsTimeout := app.config.WebSocketShutdownTimeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wsTimeout {
+		wsTimeout = time.Until(deadline)
+	}
+	wsErr := app.websockets.shutdown(wsTimeout)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Shutdown()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+		return wsErr
+	case <-ctx.Done():
+		// The server returns once the handlers of the closed connections
+		// returned, the OnShutdown hooks run after that
+		app.conns.closeAll()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Draining reports whether the app is shutting down, i.e. Shutdown has been
+// called. Readiness probes should fail while the app is draining.
+func (app *App) Draining() bool {
+	return atomic.LoadUint32(&app.draining) == 1
 }
 
 // Server returns the underlying fasthttp server
@@ -914,6 +1485,45 @@ func (app *App) Test(req *http.Request, msTimeout ...int) (resp *http.Response,
 	return http.ReadResponse(buffer, req)
 }
 
+// Dispatch runs a request through the middleware and handlers of the app
+// in-process, without a network listener, and returns the response. It's meant
+// for batch endpoints, re-rendering responses in the background and tests:
+//
+//	resp, err := app.Dispatch(fiber.MethodGet, "/users/1?fields=name", map[string]string{
+//		fiber.HeaderAccept: fiber.MIMEApplicationJSON,
+//	}, nil)
+//
+// The path may contain a query string. Unlike Test, it doesn't run the OnListen hooks.
+func (app *App) Dispatch(method, path string, headers map[string]string, body []byte) (*fasthttp.Response, error) {
+	if app.methodInt(method) == -1 {
+		return nil, fmt.Errorf("dispatch: invalid http method %q", method)
+	}
+	if len(path) == 0 || path[0] != '/' {
+		return nil, fmt.Errorf("dispatch: path %q must start with a slash", path)
+	}
+
+	// Build the tree with routes registered in the meantime
+	if atomic.LoadUint32(&app.treeStale) == 1 {
+		app.refreshTree()
+	}
+
+	fctx := &fasthttp.RequestCtx{}
+	req := &fctx.Request
+	req.Header.SetMethod(method)
+	req.SetRequestURI(path)
+	for key, val := range headers {
+		req.Header.Set(key, val)
+	}
+	if len(body) > 0 {
+		req.SetBody(body)
+	}
+
+	app.handler(fctx)
+	// Run the after-response phase and close Locals as the server does
+	fctx.ResetUserValues()
+	return &fctx.Response, nil
+}
+
 type disableLogger struct{}
 
 func (dl *disableLogger) Printf(_ string, _ ...interface{}) {
@@ -931,11 +1541,16 @@ func (app *App) init() *App {
 		}
 	}
 
+	app.messageLanguages = app.config.Messages.languages()
+
+	app.done = make(chan struct{})
+
 	// create fasthttp server
 	app.server = &fasthttp.Server{
 		Logger:       &disableLogger{},
 		LogAllErrors: false,
 		ErrorHandler: app.serverErrorHandler,
+		ConnState:    app.conns.track,
 	}
 
 	// fasthttp server settings
@@ -957,6 +1572,10 @@ func (app *App) init() *App {
 	app.server.ReduceMemoryUsage = app.config.ReduceMemoryUsage
 	app.server.StreamRequestBody = app.config.StreamRequestBody
 	app.server.DisablePreParseMultipartForm = app.config.DisablePreParseMultipartForm
+	// The limits can only be enforced on the raw body
+	if app.config.Multipart.enabled() {
+		app.server.DisablePreParseMultipartForm = true
+	}
 
 	// unlock application
 	app.mutex.Unlock()
@@ -970,17 +1589,22 @@ func (app *App) init() *App {
 // the app, which if not set is the DefaultErrorHandler.
 func (app *App) ErrorHandler(ctx *Ctx, err error) error {
 	var (
-		mountedErrHandler  ErrorHandler
-		mountedPrefixParts int
+		mountedErrHandler ErrorHandler
+		mountedPrefixLen  = -1
 	)
 
+	// The most specific mounted app handles the error
 	for prefix, subApp := range app.appList {
-		if prefix != "" && strings.HasPrefix(ctx.path, prefix) {
-			parts := len(strings.Split(prefix, "/"))
-			if mountedPrefixParts <= parts {
-				mountedErrHandler = subApp.config.ErrorHandler
-				mountedPrefixParts = parts
-			}
+		if prefix == "" {
+			continue
+		}
+		prefix = strings.TrimRight(prefix, "/")
+		if prefix != "" && ctx.path != prefix && !strings.HasPrefix(ctx.path, prefix+"/") {
+			continue
+		}
+		if len(prefix) > mountedPrefixLen {
+			mountedErrHandler = subApp.config.ErrorHandler
+			mountedPrefixLen = len(prefix)
 		}
 	}
 
@@ -1023,8 +1647,57 @@ func (app *App) startupProcess() *App {
 		panic(err)
 	}
 
+	app.refreshTree()
+	return app
+}
+
+// refreshTree copies the late routes of the mounted apps and builds the tree
+func (app *App) refreshTree() {
+	// Routes registered while the tree is built mark it as stale again
+	atomic.StoreUint32(&app.treeStale, 0)
+	app.mergeMounts()
 	app.mutex.Lock()
 	app.buildTree()
 	app.mutex.Unlock()
-	return app
+}
+
+// markTreeStale marks the trees of the app and the apps it's mounted to as stale
+func (app *App) markTreeStale() {
+	for a := app; a != nil; {
+		atomic.StoreUint32(&a.treeStale, 1)
+		a.mutex.Lock()
+		parent := a.parent
+		a.mutex.Unlock()
+		a = parent
+	}
+}
+
+// connSet tracks the open connections of the server, see ShutdownWithContext
+type connSet struct {
+	mutex sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// track implements fasthttp.Server.ConnState
+func (s *connSet) track(conn net.Conn, state fasthttp.ConnState) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	switch state {
+	case fasthttp.StateNew:
+		if s.conns == nil {
+			s.conns = make(map[net.Conn]struct{})
+		}
+		s.conns[conn] = struct{}{}
+	case fasthttp.StateHijacked, fasthttp.StateClosed:
+		delete(s.conns, conn)
+	}
+}
+
+// closeAll closes the open connections
+func (s *connSet) closeAll() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
 }
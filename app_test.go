@@ -6,6 +6,7 @@ package fiber
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -19,6 +20,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -323,6 +325,73 @@ func Test_App_Mount(t *testing.T) {
 	utils.AssertEqual(t, uint32(2), app.handlersCount)
 }
 
+// go test -run Test_App_Mount_Late_Routes
+func Test_App_Mount_Late_Routes(t *testing.T) {
+	t.Parallel()
+	admin := New()
+	users := New()
+	admin.Mount("/users", users)
+
+	app := New()
+	app.Mount("/admin", admin)
+
+	// Registered after Mount, merged on startup
+	admin.Get("/stats", func(c *Ctx) error {
+		return c.SendString(c.MountPath())
+	})
+	users.Get("/:id", func(c *Ctx) error {
+		return c.SendString(c.MountPath() + " " + c.Params("id"))
+	})
+	app.Get("/", func(c *Ctx) error {
+		return c.SendString(c.MountPath())
+	})
+
+	test := func(path, expected string) {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, StatusOK, resp.StatusCode, path)
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, expected, string(body), path)
+	}
+	test("/admin/stats", "/admin")
+	test("/admin/users/1", "/admin/users 1")
+	test("/", "")
+
+	// The routes are merged once
+	utils.AssertEqual(t, 3, len(app.stack[app.methodInt(MethodGet)]))
+	utils.AssertEqual(t, "", app.MountPath())
+	utils.AssertEqual(t, "/admin/users", users.MountPath())
+}
+
+// go test -run Test_App_Mount_ErrorHandler_Prefix
+func Test_App_Mount_ErrorHandler_Prefix(t *testing.T) {
+	t.Parallel()
+	admin := New(Config{
+		ErrorHandler: func(c *Ctx, err error) error {
+			return c.Status(StatusTeapot).SendString("admin")
+		},
+	})
+	admin.Get("/", func(c *Ctx) error {
+		return ErrBadRequest
+	})
+
+	app := New()
+	app.Mount("/admin", admin)
+	app.Get("/administrator", func(c *Ctx) error {
+		return ErrBadRequest
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/admin", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusTeapot, resp.StatusCode)
+
+	// The path only shares the prefix with the mounted app
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/administrator", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusBadRequest, resp.StatusCode)
+}
+
 func Test_App_Use_Params(t *testing.T) {
 	app := New()
 
@@ -496,7 +565,55 @@ func Test_App_Add_Method_Test(t *testing.T) {
 			utils.AssertEqual(t, "add: invalid http method JOHN\n", fmt.Sprintf("%v", err))
 		}
 	}()
-	app.Add("JOHN", "/doe", testEmptyHandler)
+	app.Add([]string{"JOHN"}, "/doe", testEmptyHandler)
+}
+
+// go test -run Test_App_Add_Custom_Methods
+func Test_App_Add_Custom_Methods(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		RequestMethods: append(DefaultMethods, "propfind", "REPORT"),
+	})
+	app.Add([]string{"PROPFIND", "report"}, "/files/*", func(c *Ctx) error {
+		return c.SendString(c.Method() + " " + c.Params("*"))
+	})
+	app.All("/all", func(c *Ctx) error {
+		return c.SendString(c.Method())
+	})
+	app.Group("/api").Add([]string{MethodGet, "REPORT"}, "/report", func(c *Ctx) error {
+		return c.SendString("report")
+	})
+
+	test := func(method, path string, status int, expected string) {
+		resp, err := app.Test(httptest.NewRequest(method, path, nil))
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, status, resp.StatusCode, method+" "+path)
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		if status == StatusOK {
+			utils.AssertEqual(t, expected, string(body), method+" "+path)
+		}
+	}
+	test("PROPFIND", "/files/a.txt", StatusOK, "PROPFIND a.txt")
+	test("REPORT", "/files/a.txt", StatusOK, "REPORT a.txt")
+	test("REPORT", "/all", StatusOK, "REPORT")
+	test(MethodPatch, "/all", StatusOK, MethodPatch)
+	test("REPORT", "/api/report", StatusOK, "report")
+	test(MethodGet, "/api/report", StatusOK, "report")
+	test("MKCOL", "/files/a.txt", StatusBadRequest, "")
+
+	// Other methods matching the path are listed in the Allow header
+	resp, err := app.Test(httptest.NewRequest(MethodPost, "/files/a.txt", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusMethodNotAllowed, resp.StatusCode)
+	utils.AssertEqual(t, "PROPFIND, REPORT", resp.Header.Get(HeaderAllow))
+
+	// The default apps don't route custom methods
+	defaultApp := New()
+	resp, err = defaultApp.Test(httptest.NewRequest("PROPFIND", "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusBadRequest, resp.StatusCode)
+	utils.AssertEqual(t, 9, len(defaultApp.Stack()))
 }
 
 // go test -run Test_App_GETOnly
@@ -542,7 +659,7 @@ func Test_App_Chaining(t *testing.T) {
 		return c.SendStatus(202)
 	})
 	// check handler count for registered HEAD route
-	utils.AssertEqual(t, 5, len(app.stack[methodInt(MethodHead)][0].Handlers), "app.Test(req)")
+	utils.AssertEqual(t, 5, len(app.stack[app.methodInt(MethodHead)][0].Handlers), "app.Test(req)")
 
 	req := httptest.NewRequest(MethodPost, "/john", nil)
 
@@ -661,6 +778,68 @@ func Test_App_Route_Naming(t *testing.T) {
 	utils.AssertEqual(t, "test", app.GetRoute("test").Name)
 }
 
+// go test -run Test_App_Group_Naming
+func Test_App_Group_Naming(t *testing.T) {
+	t.Parallel()
+	app := New()
+	handler := func(c *Ctx) error {
+		return c.SendStatus(StatusOK)
+	}
+
+	api := app.Group("/api").Name("api.")
+	v1 := api.Group("/v1").Name("v1.")
+	v2 := api.Group("/v2").Name("v2.")
+	users := v1.Group("/users").Name("users.")
+
+	// The names are inherited from the parents, regardless of the registration order
+	users.Get("/", handler).Name("list")
+	v2.Get("/users", handler).Name("users")
+	v1.Post("/login", handler).Name("login")
+	api.Get("/health", handler).Name("health")
+	app.Get("/api/v1/about", handler).Name("about")
+
+	utils.AssertEqual(t, "/api/v1/users/", app.GetRoute("api.v1.users.list").Path)
+	utils.AssertEqual(t, "/api/v2/users", app.GetRoute("api.v2.users").Path)
+	utils.AssertEqual(t, "/api/v1/login", app.GetRoute("api.v1.login").Path)
+	utils.AssertEqual(t, "/api/health", app.GetRoute("api.health").Path)
+	utils.AssertEqual(t, "/api/v1/about", app.GetRoute("about").Path)
+
+	// Unnamed groups don't add to the names
+	admin := app.Group("/admin").Group("/settings").Name("settings.")
+	admin.Put("/", handler).Name("update")
+	utils.AssertEqual(t, "/admin/settings/", app.GetRoute("settings.update").Path)
+}
+
+func Test_App_RateLimit(t *testing.T) {
+	app := New()
+	handler := func(c *Ctx) error {
+		return c.SendStatus(StatusOK)
+	}
+	app.Get("/john", handler)
+	app.RateLimit(10, time.Minute)
+	app.Group("/jane").Post("/", handler)
+	app.RateLimit(5, time.Second)
+	// Handlers registered twice for the same path are merged into one route
+	app.Put("/doe", handler)
+	app.Put("/doe", handler)
+	app.RateLimit(1, time.Second)
+
+	sub := New()
+	sub.Delete("/", handler)
+	sub.RateLimit(3, time.Second)
+	app.Mount("/sub", sub)
+
+	stack := app.Stack()
+	get, head := stack[app.methodInt(MethodGet)][0], stack[app.methodInt(MethodHead)][0]
+	utils.AssertEqual(t, 10, get.RateLimit.Max)
+	utils.AssertEqual(t, time.Minute, get.RateLimit.Expiration)
+	utils.AssertEqual(t, get.RateLimit, head.RateLimit)
+	utils.AssertEqual(t, 5, stack[app.methodInt(MethodPost)][0].RateLimit.Max)
+	utils.AssertEqual(t, 1, len(stack[app.methodInt(MethodPut)]))
+	utils.AssertEqual(t, 1, stack[app.methodInt(MethodPut)][0].RateLimit.Max)
+	utils.AssertEqual(t, 3, stack[app.methodInt(MethodDelete)][0].RateLimit.Max)
+}
+
 func Test_App_New(t *testing.T) {
 	app := New()
 	app.Get("/", testEmptyHandler)
@@ -686,6 +865,118 @@ func Test_App_Shutdown(t *testing.T) {
 		utils.AssertEqual(t, true, app.Shutdown() == nil)
 	})
 
+	t.Run("drain", func(t *testing.T) {
+		app := New(Config{
+			DisableStartupMessage: true,
+			ShutdownDrainPeriod:   50 * time.Millisecond,
+		})
+		utils.AssertEqual(t, false, app.Draining())
+		start := time.Now()
+		utils.AssertEqual(t, nil, app.Shutdown())
+		utils.AssertEqual(t, true, app.Draining())
+		utils.AssertEqual(t, true, time.Since(start) >= 50*time.Millisecond)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		app := New(Config{DisableStartupMessage: true})
+		var hooked int32
+		app.Hooks().OnShutdown(func() error {
+			atomic.StoreInt32(&hooked, 1)
+			return nil
+		})
+		started, release := make(chan struct{}), make(chan struct{})
+		app.Get("/", func(c *Ctx) error {
+			close(started)
+			<-release
+			return nil
+		})
+
+		ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+		utils.AssertEqual(t, nil, err)
+		go func() {
+			_ = app.Listener(ln)
+		}()
+		requested := make(chan error, 1)
+		go func() {
+			resp, err := http.Get("http://" + ln.Addr().String())
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			requested <- err
+			// The handler returns once the connection has been closed
+			close(release)
+		}()
+		<-started
+
+		// The hanging request is closed once the timeout expired
+		utils.AssertEqual(t, context.DeadlineExceeded, app.ShutdownWithTimeout(50*time.Millisecond))
+		utils.AssertEqual(t, int32(1), atomic.LoadInt32(&hooked))
+		utils.AssertEqual(t, true, <-requested != nil)
+	})
+
+	t.Run("dispatch", func(t *testing.T) {
+		app := New(Config{DisableStartupMessage: true})
+		started := make(chan struct{})
+		app.Get("/", func(c *Ctx) error {
+			close(started)
+			for !app.Draining() {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(20 * time.Millisecond)
+			// Handlers may register routes and dispatch while shutting down
+			app.Get("/late", func(c *Ctx) error {
+				return c.SendString("late")
+			})
+			resp, err := app.Dispatch(MethodGet, "/late", nil, nil)
+			if err != nil {
+				return err
+			}
+			return c.Send(resp.Body())
+		})
+
+		ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+		utils.AssertEqual(t, nil, err)
+		go func() {
+			_ = app.Listener(ln)
+		}()
+		type result struct {
+			body string
+			err  error
+		}
+		requested := make(chan result, 1)
+		go func() {
+			resp, err := http.Get("http://" + ln.Addr().String())
+			if err != nil {
+				requested <- result{err: err}
+				return
+			}
+			body, err := ioutil.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			requested <- result{body: string(body), err: err}
+		}()
+		<-started
+
+		utils.AssertEqual(t, nil, app.ShutdownWithTimeout(5*time.Second))
+		res := <-requested
+		utils.AssertEqual(t, nil, res.err)
+		utils.AssertEqual(t, "late", res.body)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		app := New(Config{
+			DisableStartupMessage: true,
+			ShutdownDrainPeriod:   time.Minute,
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		// The drain period is cut short, the idle server may still stop in time
+		start := time.Now()
+		err := app.ShutdownWithContext(ctx)
+		utils.AssertEqual(t, true, err == nil || err == context.Canceled)
+		utils.AssertEqual(t, true, app.Draining())
+		utils.AssertEqual(t, true, time.Since(start) < time.Second)
+	})
+
 	t.Run("no server", func(t *testing.T) {
 		app := &App{}
 		if err := app.Shutdown(); err != nil {
@@ -1301,15 +1592,54 @@ func Test_App_Stack(t *testing.T) {
 
 	stack := app.Stack()
 	utils.AssertEqual(t, 9, len(stack))
-	utils.AssertEqual(t, 3, len(stack[methodInt(MethodGet)]))
-	utils.AssertEqual(t, 3, len(stack[methodInt(MethodHead)]))
-	utils.AssertEqual(t, 2, len(stack[methodInt(MethodPost)]))
-	utils.AssertEqual(t, 1, len(stack[methodInt(MethodPut)]))
-	utils.AssertEqual(t, 1, len(stack[methodInt(MethodPatch)]))
-	utils.AssertEqual(t, 1, len(stack[methodInt(MethodDelete)]))
-	utils.AssertEqual(t, 1, len(stack[methodInt(MethodConnect)]))
-	utils.AssertEqual(t, 1, len(stack[methodInt(MethodOptions)]))
-	utils.AssertEqual(t, 1, len(stack[methodInt(MethodTrace)]))
+	utils.AssertEqual(t, 3, len(stack[app.methodInt(MethodGet)]))
+	utils.AssertEqual(t, 3, len(stack[app.methodInt(MethodHead)]))
+	utils.AssertEqual(t, 2, len(stack[app.methodInt(MethodPost)]))
+	utils.AssertEqual(t, 1, len(stack[app.methodInt(MethodPut)]))
+	utils.AssertEqual(t, 1, len(stack[app.methodInt(MethodPatch)]))
+	utils.AssertEqual(t, 1, len(stack[app.methodInt(MethodDelete)]))
+	utils.AssertEqual(t, 1, len(stack[app.methodInt(MethodConnect)]))
+	utils.AssertEqual(t, 1, len(stack[app.methodInt(MethodOptions)]))
+	utils.AssertEqual(t, 1, len(stack[app.methodInt(MethodTrace)]))
+}
+
+// go test -run Test_App_GetRoutes
+func Test_App_GetRoutes(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	app.Use(emptyHandler)
+	app.Meta("kind", "middleware")
+	app.Get("/users/:id", testEmptyHandler).Name("user")
+	app.Meta("auth", "none").Meta("owner", "team-a")
+	app.Group("/api").Post("/items", testEmptyHandler)
+	app.Meta("auth", "token")
+
+	routes := app.GetRoutes()
+	utils.AssertEqual(t, 9+2+1, len(routes))
+
+	routes = app.GetRoutes(true)
+	utils.AssertEqual(t, 1+2+1, len(routes))
+	byMethod := make(map[string]Route)
+	for _, route := range routes {
+		byMethod[route.Method] = route
+	}
+
+	use := byMethod[methodUse]
+	utils.AssertEqual(t, "/", use.Path)
+	utils.AssertEqual(t, map[string]interface{}{"kind": "middleware"}, use.Metadata)
+	utils.AssertEqual(t, []string{"github.com/gofiber/fiber/v2.emptyHandler"}, use.HandlerNames())
+
+	get := byMethod[MethodGet]
+	utils.AssertEqual(t, "user", get.Name)
+	utils.AssertEqual(t, "/users/:id", get.Path)
+	utils.AssertEqual(t, []string{"id"}, get.Params)
+	utils.AssertEqual(t, map[string]interface{}{"auth": "none", "owner": "team-a"}, get.Metadata)
+	// The HEAD route of GET routes shares the metadata
+	utils.AssertEqual(t, get.Metadata, byMethod[MethodHead].Metadata)
+
+	utils.AssertEqual(t, "/api/items", byMethod[MethodPost].Path)
+	utils.AssertEqual(t, map[string]interface{}{"auth": "token"}, byMethod[MethodPost].Metadata)
 }
 
 // go test -run Test_App_HandlersCount
@@ -1600,6 +1930,69 @@ func Test_App_UseMountedErrorHandlerForBestPrefixMatch(t *testing.T) {
 	utils.AssertEqual(t, "hi, i'm a custom sub sub fiber error", string(b), "Third fiber Response body")
 }
 
+// go test -run Test_App_Dispatch
+func Test_App_Dispatch(t *testing.T) {
+	t.Parallel()
+	app := New()
+	var listened bool
+	app.Hooks().OnListen(func() error {
+		listened = true
+		return nil
+	})
+	app.Use(func(c *Ctx) error {
+		c.Set("X-Middleware", "1")
+		return c.Next()
+	})
+	app.Post("/users/:id", func(c *Ctx) error {
+		return c.SendString(c.Params("id") + " " + c.Query("q") + " " + c.Hostname() + " " + c.Get(HeaderAuthorization) + " " + string(c.Body()))
+	})
+
+	resp, err := app.Dispatch(MethodPost, "/users/1?q=search", map[string]string{
+		HeaderHost:          "example.com",
+		HeaderAuthorization: "token",
+	}, []byte("body"))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusOK, resp.StatusCode())
+	utils.AssertEqual(t, "1", string(resp.Header.Peek("X-Middleware")))
+	utils.AssertEqual(t, "1 search example.com token body", string(resp.Body()))
+	utils.AssertEqual(t, false, listened)
+
+	// Routes registered later on are found
+	app.Get("/later", func(c *Ctx) error {
+		return c.SendStatus(StatusAccepted)
+	})
+	resp, err = app.Dispatch(MethodGet, "/later", nil, nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusAccepted, resp.StatusCode())
+
+	// The tree is only rebuilt after routes were registered
+	utils.AssertEqual(t, uint32(0), atomic.LoadUint32(&app.treeStale))
+	sub := New()
+	app.Mount("/sub", sub)
+	utils.AssertEqual(t, uint32(1), atomic.LoadUint32(&app.treeStale))
+	_, err = app.Dispatch(MethodGet, "/later", nil, nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, uint32(0), atomic.LoadUint32(&app.treeStale))
+
+	// Routes registered later on by mounted apps mark the tree as stale
+	sub.Get("/late", func(c *Ctx) error {
+		return c.SendStatus(StatusCreated)
+	})
+	utils.AssertEqual(t, uint32(1), atomic.LoadUint32(&app.treeStale))
+	resp, err = app.Dispatch(MethodGet, "/sub/late", nil, nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusCreated, resp.StatusCode())
+
+	resp, err = app.Dispatch(MethodGet, "/missing", nil, nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusNotFound, resp.StatusCode())
+
+	_, err = app.Dispatch("INVALID", "/", nil, nil)
+	utils.AssertEqual(t, true, err != nil)
+	_, err = app.Dispatch(MethodGet, "users", nil, nil)
+	utils.AssertEqual(t, true, err != nil)
+}
+
 func Test_App_Test_no_timeout_infinitely(t *testing.T) {
 	var err error
 	c := make(chan int)
@@ -1645,3 +2038,42 @@ func Test_App_SetTLSHandler(t *testing.T) {
 
 	utils.AssertEqual(t, "example.golang", c.ClientHelloInfo().ServerName)
 }
+
+// go test -run Test_App_RouteURL
+func Test_App_RouteURL(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/Users/:id", testEmptyHandler).Name("user.show")
+	app.Get("/users/:id/posts/:slug?", testEmptyHandler).Name("user.posts")
+	app.Get("/files/*", testEmptyHandler).Name("files")
+	app.Get("/docs/+", testEmptyHandler).Name("docs")
+	app.Group("/api").Name("api.").Get("/v\\:1/items/:id", testEmptyHandler).Name("item")
+
+	test := func(name string, params Map, expected string) {
+		url, err := app.RouteURL(name, params)
+		utils.AssertEqual(t, nil, err, name)
+		utils.AssertEqual(t, expected, url, name)
+	}
+	test("user.show", Map{"id": 5}, "/Users/5")
+	test("user.show", Map{"ID": "a b/c"}, "/Users/a%20b%2Fc")
+	test("user.show", Map{"id": 5, "tab": "posts", "tags": []string{"a", "b"}}, "/Users/5?tab=posts&tags=a&tags=b")
+	test("user.posts", Map{"id": 1, "slug": "hello"}, "/users/1/posts/hello")
+	test("user.posts", Map{"id": 1}, "/users/1/posts/")
+	test("files", Map{"*": "css/main file.css"}, "/files/css/main%20file.css")
+	test("files", Map{}, "/files/")
+	test("docs", Map{"+1": "a/b"}, "/docs/a/b")
+	test("api.item", Map{"id": 2}, "/api/v:1/items/2")
+
+	_, err := app.RouteURL("user.show", Map{})
+	utils.AssertEqual(t, `route "user.show": missing param "id"`, err.Error())
+	_, err = app.RouteURL("docs", nil)
+	utils.AssertEqual(t, `route "docs": missing param "+1"`, err.Error())
+	_, err = app.RouteURL("unknown", nil)
+	utils.AssertEqual(t, `route "unknown" not found`, err.Error())
+
+	// Case sensitive apps match the params exactly
+	app = New(Config{CaseSensitive: true})
+	app.Get("/users/:id", testEmptyHandler).Name("user")
+	_, err = app.RouteURL("user", Map{"ID": 1})
+	utils.AssertEqual(t, `route "user": missing param "id"`, err.Error())
+}
@@ -0,0 +1,56 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is passed to the after phase of Around if the chain panics
+type PanicError struct {
+	// Value passed to panic
+	Value interface{}
+	// Stack of the panicking goroutine
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Around returns a middleware, which runs after once the rest of the chain
+// completed, whether it returned, returned an error or panicked:
+//
+//	app.Use(fiber.Around(func(c *fiber.Ctx, err error) error {
+//		span.End(err)
+//		return err
+//	}))
+//
+// The after phase receives the error returned by the chain and returns the
+// error, which is propagated further, i.e. returning nil handles the error.
+// If the chain panics, it receives a *PanicError and the panic continues
+// afterwards, so that a recover middleware registered before Around still
+// recovers it. A recover middleware registered after Around converts the
+// panic into an error before Around sees it.
+func Around(after func(c *Ctx, err error) error) Handler {
+	return func(c *Ctx) error {
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			// The chain panicked or called runtime.Goexit
+			r := recover()
+			_ = after(c, &PanicError{Value: r, Stack: debug.Stack()})
+			if r != nil {
+				panic(r)
+			}
+		}()
+		err := c.Next()
+		completed = true
+		return after(c, err)
+	}
+}
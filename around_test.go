@@ -0,0 +1,109 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_Around
+func Test_Around(t *testing.T) {
+	t.Parallel()
+	var (
+		phases []string
+		seen   error
+	)
+	app := New()
+	app.Use(func(c *Ctx) error {
+		err := c.Next()
+		phases = append(phases, "outer")
+		return err
+	})
+	app.Use(Around(func(c *Ctx, err error) error {
+		phases = append(phases, "around")
+		seen = err
+		if err != nil && err.Error() == "handled" {
+			return nil
+		}
+		return err
+	}))
+	app.Get("/ok", func(c *Ctx) error {
+		return c.SendString("ok")
+	})
+	app.Get("/error", func(c *Ctx) error {
+		return ErrTeapot
+	})
+	app.Get("/handled", func(c *Ctx) error {
+		return errors.New("handled")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/ok", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, nil, seen)
+	utils.AssertEqual(t, []string{"around", "outer"}, phases)
+
+	// Errors pass the after phase and reach the ErrorHandler
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/error", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusTeapot, resp.StatusCode)
+	utils.AssertEqual(t, ErrTeapot, seen)
+
+	// The after phase may handle the error
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/handled", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_Around_Panic
+func Test_Around_Panic(t *testing.T) {
+	t.Parallel()
+	var (
+		outerRan bool
+		seen     error
+	)
+	app := New()
+	// Recovers the panic after Around saw it
+	app.Use(func(c *Ctx) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = NewError(StatusInternalServerError, "recovered")
+			}
+		}()
+		return c.Next()
+	})
+	app.Use(func(c *Ctx) error {
+		err := c.Next()
+		outerRan = true
+		return err
+	})
+	app.Use(Around(func(c *Ctx, err error) error {
+		seen = err
+		return err
+	}))
+	app.Get("/", func(c *Ctx) error {
+		panic("boom")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusInternalServerError, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "recovered", string(body))
+
+	// The code after Next of plain middleware doesn't run on panics
+	utils.AssertEqual(t, false, outerRan)
+	var perr *PanicError
+	utils.AssertEqual(t, true, errors.As(seen, &perr))
+	utils.AssertEqual(t, "boom", perr.Value)
+	utils.AssertEqual(t, "panic: boom", perr.Error())
+	utils.AssertEqual(t, true, len(perr.Stack) > 0)
+}
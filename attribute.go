@@ -0,0 +1,158 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Attribute is a value derived from a request, e.g. a log field, a metric
+// label or a part of a rate limit key. See ParseAttribute for the syntax.
+type Attribute struct {
+	// Name of the attribute, e.g. the key of the log field
+	Name string
+	// Value returns the value of the request, which is safe to keep after
+	// the handler returned
+	Value func(c *Ctx) string
+}
+
+// AttributeSource derives the value of an attribute from the request, arg is
+// the part of the spec after the colon, e.g. the header name.
+type AttributeSource = func(c *Ctx, arg string) string
+
+var (
+	attributeMutex   sync.RWMutex
+	attributeSources = map[string]AttributeSource{
+		"header": func(c *Ctx, arg string) string { return c.Get(arg) },
+		"query":  func(c *Ctx, arg string) string { return c.Query(arg) },
+		"param":  func(c *Ctx, arg string) string { return c.Params(arg) },
+		"cookie": func(c *Ctx, arg string) string { return c.Cookies(arg) },
+		"form":   func(c *Ctx, arg string) string { return c.FormValue(arg) },
+		"local": func(c *Ctx, arg string) string {
+			switch v := c.Locals(arg).(type) {
+			case nil:
+				return ""
+			case string:
+				return v
+			default:
+				return fmt.Sprint(v)
+			}
+		},
+		"ip":       func(c *Ctx, _ string) string { return c.IP() },
+		"method":   func(c *Ctx, _ string) string { return c.Method() },
+		"path":     func(c *Ctx, _ string) string { return c.Path() },
+		"host":     func(c *Ctx, _ string) string { return c.Hostname() },
+		"protocol": func(c *Ctx, _ string) string { return c.Protocol() },
+		"route": func(c *Ctx, _ string) string {
+			if route := c.MatchedRoute(); route != nil {
+				return route.Path
+			}
+			return ""
+		},
+	}
+)
+
+// RegisterAttribute registers a source of attributes, e.g. the claims of a
+// token, for all specs parsed afterwards:
+//
+//	fiber.RegisterAttribute("claim", func(c *fiber.Ctx, name string) string {
+//		claims, _ := c.Locals("claims").(map[string]string)
+//		return claims[name]
+//	})
+//	key := fiber.Key("claim:tenant", "ip")
+func RegisterAttribute(source string, fn AttributeSource) {
+	attributeMutex.Lock()
+	attributeSources[source] = fn
+	attributeMutex.Unlock()
+}
+
+// ParseAttribute parses the spec of an attribute, "[name=]source[:arg]".
+// The built-in sources are:
+//
+//   - "header:<name>", "query:<name>", "param:<name>", "cookie:<name>",
+//     "form:<name>" and "local:<key>"
+//   - "ip", "method", "path", "host", "protocol" and "route", the path of
+//     the matched route, e.g. "/users/:id"
+//
+// The name defaults to the spec, e.g. "tenant=header:X-Tenant" is named
+// "tenant" and "header:X-Tenant" is named "header:X-Tenant".
+func ParseAttribute(spec string) (Attribute, error) {
+	name, source, arg := spec, spec, ""
+	// The name precedes the source, e.g. "tenant=query:a=b"
+	if i := strings.IndexByte(spec, '='); i >= 0 && !strings.Contains(spec[:i], ":") {
+		name, source = strings.TrimSpace(spec[:i]), strings.TrimSpace(spec[i+1:])
+	}
+	if i := strings.IndexByte(source, ':'); i >= 0 {
+		source, arg = source[:i], source[i+1:]
+	}
+
+	attributeMutex.RLock()
+	fn, ok := attributeSources[source]
+	attributeMutex.RUnlock()
+	if !ok || name == "" {
+		return Attribute{}, fmt.Errorf("fiber: invalid attribute %q", spec)
+	}
+	return Attribute{
+		Name: name,
+		Value: func(c *Ctx) string {
+			return utils.CopyString(fn(c, arg))
+		},
+	}, nil
+}
+
+// parseAttributes parses the specs and panics on invalid ones
+func parseAttributes(specs []string) []Attribute {
+	attrs := make([]Attribute, len(specs))
+	for i, spec := range specs {
+		attr, err := ParseAttribute(spec)
+		if err != nil {
+			panic(err)
+		}
+		attrs[i] = attr
+	}
+	return attrs
+}
+
+// Key returns a key generator, which joins the attributes of the specs with
+// colons, see ParseAttribute. It panics on invalid specs. The same specs
+// derive the same keys in all middleware, e.g. in the limiter and cache:
+//
+//	app.Use(limiter.New(limiter.Config{
+//		KeyGenerator: fiber.Key("header:X-Tenant", "ip"),
+//	}))
+func Key(specs ...string) func(c *Ctx) string {
+	attrs := parseAttributes(specs)
+	return func(c *Ctx) string {
+		if len(attrs) == 1 {
+			return attrs[0].Value(c)
+		}
+		var b strings.Builder
+		for i, attr := range attrs {
+			if i > 0 {
+				b.WriteByte(':')
+			}
+			b.WriteString(attr.Value(c))
+		}
+		return b.String()
+	}
+}
+
+// Fields returns a function, which derives the attributes of the specs by
+// their names, e.g. the fields of a log entry or the labels of a metric, see
+// ParseAttribute. It panics on invalid specs.
+func Fields(specs ...string) func(c *Ctx) map[string]string {
+	attrs := parseAttributes(specs)
+	return func(c *Ctx) map[string]string {
+		fields := make(map[string]string, len(attrs))
+		for _, attr := range attrs {
+			fields[attr.Name] = attr.Value(c)
+		}
+		return fields
+	}
+}
@@ -0,0 +1,68 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_Attribute_Parse
+func Test_Attribute_Parse(t *testing.T) {
+	t.Parallel()
+	for spec, name := range map[string]string{
+		"header:X-Tenant":        "header:X-Tenant",
+		"tenant=header:X-Tenant": "tenant",
+		" id = param:id":         "id",
+		"query:a=b":              "query:a=b",
+		"ip":                     "ip",
+	} {
+		attr, err := ParseAttribute(spec)
+		utils.AssertEqual(t, nil, err, spec)
+		utils.AssertEqual(t, name, attr.Name, spec)
+	}
+	for _, spec := range []string{"", "unknown:x", "=ip", "name=unknown"} {
+		_, err := ParseAttribute(spec)
+		utils.AssertEqual(t, "fiber: invalid attribute \""+spec+"\"", err.Error(), spec)
+	}
+}
+
+// go test -run Test_Attribute_Key_Fields
+func Test_Attribute_Key_Fields(t *testing.T) {
+	t.Parallel()
+	RegisterAttribute("test_claim", func(c *Ctx, name string) string {
+		return name + "-" + c.Get("X-User")
+	})
+
+	var key string
+	var fields map[string]string
+	keyOf := Key("header:X-Tenant", "param:id", "query:page", "local:user", "method", "route", "test_claim:sub")
+	fieldsOf := Fields("tenant=header:X-Tenant", "route", "cookie:session")
+	app := New()
+	app.Get("/users/:id", func(c *Ctx) error {
+		c.Locals("user", 42)
+		key, fields = keyOf(c), fieldsOf(c)
+		return nil
+	})
+
+	req := httptest.NewRequest(MethodGet, "/users/1?page=2", nil)
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set("X-User", "john")
+	req.Header.Set(HeaderCookie, "session=abc")
+	_, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "acme:1:2:42:GET:/users/:id:sub-john", key)
+	utils.AssertEqual(t, map[string]string{"tenant": "acme", "route": "/users/:id", "cookie:session": "abc"}, fields)
+
+	utils.AssertEqual(t, "fiber: invalid attribute \"unknown\"", func() (msg string) {
+		defer func() {
+			msg = recover().(error).Error()
+		}()
+		Key("unknown")
+		return
+	}())
+}
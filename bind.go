@@ -0,0 +1,337 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2/internal/schema"
+)
+
+// customDecoders holds the decoders registered by RegisterDecoder
+var (
+	customDecoders   = make(map[reflect.Type]func(string) (interface{}, error))
+	customDecodersMu sync.RWMutex
+)
+
+// RegisterDecoder teaches BodyParser, QueryParser, ReqHeaderParser and ParamsParser
+// how to decode a custom type, e.g. uuid.UUID, decimal.Decimal or enums, without
+// implementing encoding.TextUnmarshaler on foreign types:
+//
+//	fiber.RegisterDecoder(reflect.TypeOf(decimal.Decimal{}), func(s string) (interface{}, error) {
+//		return decimal.NewFromString(s)
+//	})
+//
+// The returned value must be assignable or convertible to the type. An error is
+// reported as ConversionError.Err. RegisterDecoder is meant to be called during
+// initialization, before the parsers are used.
+func RegisterDecoder(typ reflect.Type, decoder func(string) (interface{}, error)) {
+	customDecodersMu.Lock()
+	customDecoders[typ] = decoder
+	customDecodersMu.Unlock()
+
+	// Rebuild the decoders, since the struct metadata depends on the supported types
+	SetParserDecoder(parserDecoders.config)
+}
+
+// registerCustomDecoders registers the custom decoders at the schema decoder
+func registerCustomDecoders(decoder *schema.Decoder) {
+	customDecodersMu.RLock()
+	defer customDecodersMu.RUnlock()
+	for typ, fn := range customDecoders {
+		fn := fn
+		decoder.RegisterDecoder(typ, func(s string) (reflect.Value, error) {
+			v, err := fn(s)
+			if err != nil || v == nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(v), nil
+		})
+	}
+}
+
+// newBindError creates a BindError from the errors of the decoder
+func newBindError(source string, multi MultiError, data map[string][]string) *BindError {
+	bindErr := &BindError{
+		Fields: make([]*FieldError, 0, len(multi)),
+		multi:  multi,
+	}
+	for path, err := range multi {
+		fieldErr := &FieldError{
+			Field:   path,
+			Source:  source,
+			Message: err.Error(),
+			Err:     err,
+		}
+		values := data[path]
+		var convErr ConversionError
+		if errors.As(err, &convErr) {
+			if convErr.Type != nil {
+				fieldErr.Type = convErr.Type.String()
+			}
+			if convErr.Index >= 0 && convErr.Index < len(values) {
+				values = values[convErr.Index : convErr.Index+1]
+			}
+		}
+		if len(values) > 0 {
+			fieldErr.Value = values[len(values)-1]
+		}
+		bindErr.Fields = append(bindErr.Fields, fieldErr)
+	}
+	sort.Slice(bindErr.Fields, func(i, j int) bool {
+		return bindErr.Fields[i].Field < bindErr.Fields[j].Field
+	})
+	return bindErr
+}
+
+// bindSource is a bit set of the request sources a struct type declares tags for
+type bindSource uint8
+
+const (
+	bindParams bindSource = 1 << iota
+	bindQuery
+	bindReqHeader
+)
+
+// bindSourceCache caches the bind sources per struct type
+var bindSourceCache sync.Map // reflect.Type => bindSource
+
+// bindSourcesOf returns the request sources the struct type declares tags for
+func bindSourcesOf(t reflect.Type) bindSource {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if sources, ok := bindSourceCache.Load(t); ok {
+		return sources.(bindSource)
+	}
+	var sources bindSource
+	if t.Kind() == reflect.Struct {
+		sources = collectBindSources(t, map[reflect.Type]bool{})
+	}
+	bindSourceCache.Store(t, sources)
+	return sources
+}
+
+func collectBindSources(t reflect.Type, seen map[reflect.Type]bool) bindSource {
+	if seen[t] {
+		return 0
+	}
+	seen[t] = true
+	var sources bindSource
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup(paramsTag); ok {
+			sources |= bindParams
+		}
+		if _, ok := field.Tag.Lookup(queryTag); ok {
+			sources |= bindQuery
+		}
+		if _, ok := field.Tag.Lookup(reqHeaderTag); ok {
+			sources |= bindReqHeader
+		}
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			sources |= collectBindSources(ft, seen)
+		}
+	}
+	return sources
+}
+
+// wildcardSlicesCache caches the wildcard params bound to slices per struct type
+// and alias tag
+var wildcardSlicesCache sync.Map // wildcardSlicesKey => map[string]bool
+
+type wildcardSlicesKey struct {
+	t   reflect.Type
+	tag string
+}
+
+// wildcardSlicesOf returns the names of the wildcard params, e.g. "*1" or "+",
+// which are bound to slice fields of the struct type
+func wildcardSlicesOf(t reflect.Type, tag string) map[string]bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	key := wildcardSlicesKey{t: t, tag: tag}
+	if names, ok := wildcardSlicesCache.Load(key); ok {
+		return names.(map[string]bool)
+	}
+	var names map[string]bool
+	if t.Kind() == reflect.Struct {
+		names = collectWildcardSlices(t, tag, nil)
+	}
+	wildcardSlicesCache.Store(key, names)
+	return names
+}
+
+func collectWildcardSlices(t reflect.Type, tag string, names map[string]bool) map[string]bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		// Fields of embedded structs are promoted by the decoder
+		if field.Anonymous && ft.Kind() == reflect.Struct {
+			names = collectWildcardSlices(ft, tag, names)
+			continue
+		}
+		name := field.Tag.Get(tag)
+		if i := strings.IndexByte(name, ','); i != -1 {
+			name = name[:i]
+		}
+		if name == "" || (name[0] != '*' && name[0] != '+') || ft.Kind() != reflect.Slice {
+			continue
+		}
+		if names == nil {
+			names = make(map[string]bool)
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// appendSegments appends the non-empty path segments of a wildcard value
+func appendSegments(dst []string, value string) []string {
+	for _, seg := range strings.Split(value, "/") {
+		if seg != "" {
+			dst = append(dst, seg)
+		}
+	}
+	return dst
+}
+
+// bindRequest binds the route params, query string, request headers and body
+// to out. The params, query string and headers are only bound if out declares
+// a field tagged for them, the body is bound if the request has one.
+// Later sources overwrite the fields set by earlier ones.
+func (c *Ctx) bindRequest(out interface{}, s *decoderSet) error {
+	sources := bindSourcesOf(reflect.TypeOf(out))
+	if sources&bindParams != 0 {
+		if err := c.paramsParser(out, s, paramsTag); err != nil {
+			return err
+		}
+	}
+	if sources&bindQuery != 0 {
+		if err := c.queryParser(out, s); err != nil {
+			return err
+		}
+	}
+	if sources&bindReqHeader != 0 {
+		if err := c.reqHeaderParser(out, s); err != nil {
+			return err
+		}
+	}
+	// Requests without a Content-Length, e.g. GET requests, report -2
+	if len(c.fasthttp.Request.Body()) != 0 {
+		return c.bodyParser(out, s)
+	}
+	return nil
+}
+
+// Default limits of BinderConfig, which keep crafted keys like
+// "items.100000.name" from allocating huge slices
+const (
+	DefaultBinderMaxDepth       = 32
+	DefaultBinderMaxSliceLength = 10000
+)
+
+// BinderConfig configures how the parsers bind requests to structs, see
+// Config.Binder and Binder.WithConfig. The zero value binds like the parsers
+// always did with the default limits. The limits apply to form bodies, query
+// strings, headers and params, the other bodies are bound by their decoders.
+type BinderConfig struct {
+	// Strict rejects keys, which don't match a field, with an UnknownKeyError.
+	//
+	// Optional. Default: false
+	Strict bool `json:"strict"`
+
+	// DisableSplitting binds comma separated values like "tags=a,b" as one
+	// value instead of splitting them for slice fields.
+	//
+	// Optional. Default: false
+	DisableSplitting bool `json:"disable_splitting"`
+
+	// CaseSensitive matches the keys case-sensitively to the tags of the fields.
+	//
+	// Optional. Default: false
+	CaseSensitive bool `json:"case_sensitive"`
+
+	// TimeFormat is the layout of time.Time fields without a time_format tag,
+	// e.g. time.RFC3339 or "unix".
+	//
+	// Optional. Default: ""
+	TimeFormat string `json:"time_format"`
+
+	// MaxDepth limits the nesting of the keys in dot notation, e.g.
+	// "user.address.city" has a depth of 3, slice indexes like in
+	// "items.0.name" don't count. -1 disables the limit.
+	//
+	// Optional. Default: DefaultBinderMaxDepth
+	MaxDepth int `json:"max_depth"`
+
+	// MaxSliceLength limits the number of values of a key and the indexes in
+	// the keys, e.g. "items.100000.name". -1 disables the limit.
+	//
+	// Optional. Default: DefaultBinderMaxSliceLength
+	MaxSliceLength int `json:"max_slice_length"`
+}
+
+// Binder binds the request to structs with a BinderConfig, see Ctx.Binder.
+type Binder struct {
+	c      *Ctx
+	config BinderConfig
+}
+
+// Binder returns a binder with the BinderConfig of the app, which can be
+// overridden per call:
+//
+//	err := c.Binder().WithConfig(fiber.BinderConfig{Strict: true}).Query(q)
+func (c *Ctx) Binder() *Binder {
+	return &Binder{c: c, config: c.app.config.Binder}
+}
+
+// WithConfig returns a binder with the config instead of the one of the app.
+func (b *Binder) WithConfig(config BinderConfig) *Binder {
+	return &Binder{c: b.c, config: config}
+}
+
+// Body binds the request body like BodyParser.
+func (b *Binder) Body(out interface{}) error {
+	return b.c.bodyParser(out, decodersFor(b.config))
+}
+
+// Query binds the query string like QueryParser.
+func (b *Binder) Query(out interface{}) error {
+	return b.c.queryParser(out, decodersFor(b.config))
+}
+
+// Header binds the request headers like ReqHeaderParser.
+func (b *Binder) Header(out interface{}) error {
+	return b.c.reqHeaderParser(out, decodersFor(b.config))
+}
+
+// Params binds the route params like ParamsParser.
+func (b *Binder) Params(out interface{}) error {
+	return b.c.paramsParser(out, decodersFor(b.config), paramsTag)
+}
+
+// URI binds the route params tagged with "uri" like URIParser.
+func (b *Binder) URI(out interface{}) error {
+	return b.c.paramsParser(out, decodersFor(b.config), uriTag)
+}
+
+// Request binds the route params, query string, request headers and body
+// like BindTo.
+func (b *Binder) Request(out interface{}) error {
+	return b.c.bindRequest(out, decodersFor(b.config))
+}
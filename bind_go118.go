@@ -0,0 +1,23 @@
+//go:build go1.18
+// +build go1.18
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+// BindTo binds the request to a new value of type T and returns it,
+// without declaring a variable first:
+//
+//	req, err := fiber.BindTo[CreateUserReq](c)
+//
+// The route params, query string and request headers are bound if T has
+// fields tagged with `params`, `query` or `reqHeader`, the body is bound
+// like BodyParser does if the request has one. The decoders and the
+// struct metadata are cached and shared with the other parsers.
+func BindTo[T any](c *Ctx) (T, error) {
+	var out T
+	err := c.bindRequest(&out, c.decoders())
+	return out, err
+}
@@ -0,0 +1,106 @@
+//go:build go1.18
+// +build go1.18
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_BindTo
+func Test_BindTo(t *testing.T) {
+	t.Parallel()
+	type Paging struct {
+		Page int `query:"page"`
+	}
+	type CreateUserReq struct {
+		Paging
+		Org   string `params:"org"`
+		Token string `reqHeader:"X-Token"`
+		Name  string `json:"name" form:"name"`
+	}
+	app := New()
+	app.Post("/:org", func(c *Ctx) error {
+		req, err := BindTo[CreateUserReq](c)
+		if err != nil {
+			return err
+		}
+		return c.JSON(req)
+	})
+
+	req := httptest.NewRequest(MethodPost, "/gofiber?page=2", strings.NewReader(`{"name":"john"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	req.Header.Set("X-Token", "secret")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `{"Page":2,"Org":"gofiber","Token":"secret","name":"john"}`, string(body))
+
+	// Without a body
+	resp, err = app.Test(httptest.NewRequest(MethodPost, "/gofiber", nil))
+	utils.AssertEqual(t, nil, err)
+	body, err = ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `{"Page":0,"Org":"gofiber","Token":"","name":""}`, string(body))
+
+	// Unknown content type
+	req = httptest.NewRequest(MethodPost, "/gofiber", strings.NewReader("name=john"))
+	req.Header.Set(HeaderContentType, MIMETextPlain)
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusUnprocessableEntity, resp.StatusCode)
+}
+
+// go test -run Test_BindTo_Netip
+func Test_BindTo_Netip(t *testing.T) {
+	t.Parallel()
+	type AllowReq struct {
+		Addr   netip.Addr    `params:"addr"`
+		Prefix netip.Prefix  `query:"prefix"`
+		Via    []netip.Addr  `reqHeader:"X-Via"`
+		Deny   *netip.Prefix `query:"deny"`
+	}
+	app := New()
+	app.Get("/allow/:addr", func(c *Ctx) error {
+		req, err := BindTo[AllowReq](c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(req.Addr.String() + " " + req.Prefix.String() + " " + strconv.FormatBool(req.Prefix.Contains(req.Addr)) + " " + strconv.Itoa(len(req.Via)))
+	})
+
+	req := httptest.NewRequest(MethodGet, "/allow/10.1.2.3?prefix=10.0.0.0/8", nil)
+	req.Header.Add("X-Via", "::1")
+	req.Header.Add("X-Via", "192.168.0.1")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "10.1.2.3 10.0.0.0/8 true 2", string(body))
+
+	var bindErr *BindError
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	c.Request().URI().SetQueryString("prefix=10.0.0.0/33&deny=::1/129")
+	err = c.QueryParser(new(AllowReq))
+	utils.AssertEqual(t, true, errors.As(err, &bindErr))
+	utils.AssertEqual(t, 2, len(bindErr.Fields), err.Error())
+	utils.AssertEqual(t, "deny", bindErr.Fields[0].Field)
+	utils.AssertEqual(t, "prefix", bindErr.Fields[1].Field)
+	utils.AssertEqual(t, "10.0.0.0/33", bindErr.Fields[1].Value)
+}
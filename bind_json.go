@@ -0,0 +1,276 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// JSONDecoderConfig configures the number handling of NewJSONDecoder
+type JSONDecoderConfig struct {
+	// StringNumbers accepts numeric strings like "42" for number fields,
+	// including big.Int fields, as sent by JavaScript clients to avoid the
+	// precision loss of large integers.
+	//
+	// Optional. Default: false
+	StringNumbers bool
+
+	// UseNumber decodes the numbers of interface{} values as json.Number
+	// instead of float64, which keeps the precision of large integers.
+	//
+	// Optional. Default: false
+	UseNumber bool
+}
+
+// NewJSONDecoder returns a JSON decoder for Config.JSONDecoder, which handles
+// numbers as configured:
+//
+//	app := fiber.New(fiber.Config{
+//	    JSONDecoder: fiber.NewJSONDecoder(fiber.JSONDecoderConfig{StringNumbers: true}),
+//	})
+//
+// Numbers of the wrong type, e.g. a float for an integer field, are reported
+// as BindError with the path of the field.
+func NewJSONDecoder(config ...JSONDecoderConfig) utils.JSONUnmarshal {
+	var cfg JSONDecoderConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	return func(data []byte, v interface{}) error {
+		if cfg.StringNumbers {
+			var err error
+			if data, err = convertStringNumbers(data, reflect.TypeOf(v)); err != nil {
+				return err
+			}
+		}
+
+		// The decoder doesn't report syntax errors after the value, e.g. trailing data
+		if !cfg.UseNumber || !json.Valid(data) {
+			return newJSONBindError(json.Unmarshal(data, v))
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		return newJSONBindError(dec.Decode(v))
+	}
+}
+
+// newJSONBindError reports type errors of the JSON decoder as BindError
+func newJSONBindError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Type == nil {
+		return err
+	}
+
+	fieldErr := &FieldError{
+		Field:  typeErr.Field,
+		Source: "json",
+		Type:   typeErr.Type.String(),
+		Err:    err,
+	}
+	got := typeErr.Value
+	if strings.HasPrefix(got, "number ") {
+		got = got[len("number "):]
+		fieldErr.Value = got
+	}
+	expected := "a " + fieldErr.Type
+	switch typeErr.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		expected = "an integer"
+		if fieldErr.Value != "" && !strings.ContainsAny(got, ".eE") {
+			expected = "an integer in the range of " + fieldErr.Type
+		}
+	case reflect.Float32, reflect.Float64:
+		expected = "a number"
+	}
+	fieldErr.Message = "expected " + expected + ", got " + got
+	if fieldErr.Field != "" {
+		fieldErr.Message = fieldErr.Field + ": " + fieldErr.Message
+	}
+
+	return &BindError{
+		Fields: []*FieldError{fieldErr},
+		multi:  MultiError{fieldErr.Field: errors.New(fieldErr.Message)},
+	}
+}
+
+var (
+	bigIntType        = reflect.TypeOf(big.Int{})
+	jsonUnmarshalType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// convertStringNumbers replaces the numeric strings of number fields in data
+// by numbers
+func convertStringNumbers(data []byte, t reflect.Type) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		// The decoder of the target reports the error
+		return data, nil
+	}
+	tree, changed := convertStringNumber(tree, t)
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(tree)
+}
+
+// convertStringNumber converts the numeric strings of the value to json.Number,
+// if the target type is a number
+func convertStringNumber(value interface{}, t reflect.Type) (interface{}, bool) {
+	if t == nil {
+		return value, false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == bigIntType || isNumberKind(t.Kind()) {
+		if s, ok := value.(string); ok {
+			if n := json.Number(utils.Trim(s, ' ')); isJSONNumber(string(n)) {
+				return n, true
+			}
+		}
+		return value, false
+	}
+	// Types with their own decoding get the value as is
+	if reflect.PtrTo(t).Implements(jsonUnmarshalType) {
+		return value, false
+	}
+
+	changed := false
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return value, false
+		}
+		fields := jsonFieldsOf(t)
+		for key, v := range obj {
+			ft, ok := fields[key]
+			if !ok {
+				// Keys are matched case-insensitively like by encoding/json
+				ft, ok = fields[strings.ToLower(key)]
+			}
+			if !ok || ft == nil {
+				continue
+			}
+			if converted, c := convertStringNumber(v, ft); c {
+				obj[key], changed = converted, true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		list, ok := value.([]interface{})
+		if !ok {
+			return value, false
+		}
+		for i, v := range list {
+			if converted, c := convertStringNumber(v, t.Elem()); c {
+				list[i], changed = converted, true
+			}
+		}
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return value, false
+		}
+		for key, v := range obj {
+			if converted, c := convertStringNumber(v, t.Elem()); c {
+				obj[key], changed = converted, true
+			}
+		}
+	}
+	return value, changed
+}
+
+// jsonFieldsCache caches the JSON fields per struct type
+var jsonFieldsCache sync.Map // reflect.Type => map[string]reflect.Type
+
+// jsonFieldsOf returns the types of the fields of the struct type by their
+// JSON name and its lower case. Fields with the string option map to nil,
+// since their numbers are quoted anyway.
+func jsonFieldsOf(t reflect.Type) map[string]reflect.Type {
+	if fields, ok := jsonFieldsCache.Load(t); ok {
+		return fields.(map[string]reflect.Type)
+	}
+	fields := make(map[string]reflect.Type)
+	collectJSONFields(t, fields, map[reflect.Type]bool{})
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+func collectJSONFields(t reflect.Type, fields map[string]reflect.Type, seen map[reflect.Type]bool) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.IndexByte(tag, ','); i != -1 {
+			name, opts = tag[:i], tag[i:]
+		}
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		// Fields of embedded structs are promoted by the decoder
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			collectJSONFields(ft, fields, seen)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		typ := field.Type
+		if strings.Contains(opts, ",string") {
+			typ = nil
+		}
+		if _, ok := fields[name]; !ok {
+			fields[name] = typ
+		}
+		if lower := strings.ToLower(name); lower != name {
+			if _, ok := fields[lower]; !ok {
+				fields[lower] = typ
+			}
+		}
+	}
+}
+
+// isNumberKind reports if the kind is an integer or float
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isJSONNumber reports if s is a valid JSON number
+func isJSONNumber(s string) bool {
+	return s != "" && (s[0] == '-' || (s[0] >= '0' && s[0] <= '9')) && json.Valid([]byte(s))
+}
@@ -0,0 +1,137 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_NewJSONDecoder_StringNumbers
+func Test_NewJSONDecoder_StringNumbers(t *testing.T) {
+	t.Parallel()
+	type item struct {
+		Qty uint `json:"qty"`
+	}
+	type embedded struct {
+		Rank int `json:"rank"`
+	}
+	type order struct {
+		embedded
+		Age   int
+		Price float64         `json:"price"`
+		ID    *big.Int        `json:"id"`
+		Items []item          `json:"items"`
+		Tags  map[string]int8 `json:"tags"`
+		Name  string          `json:"name"`
+		Count int             `json:"count,string"`
+		Ref   json.Number     `json:"ref"`
+	}
+
+	decode := NewJSONDecoder(JSONDecoderConfig{StringNumbers: true})
+	var o order
+	err := decode([]byte(`{
+		"rank": "1",
+		"AGE": "42",
+		"price": " 1.5",
+		"id": "123456789012345678901234567890",
+		"items": [{"qty": "3"}, {"qty": 4}],
+		"tags": {"a": "-1"},
+		"name": "7",
+		"count": "5",
+		"ref": "12"
+	}`), &o)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, 1, o.Rank)
+	utils.AssertEqual(t, 42, o.Age)
+	utils.AssertEqual(t, 1.5, o.Price)
+	utils.AssertEqual(t, "123456789012345678901234567890", o.ID.String())
+	utils.AssertEqual(t, []item{{Qty: 3}, {Qty: 4}}, o.Items)
+	utils.AssertEqual(t, map[string]int8{"a": -1}, o.Tags)
+	utils.AssertEqual(t, "7", o.Name)
+	utils.AssertEqual(t, 5, o.Count)
+	utils.AssertEqual(t, json.Number("12"), o.Ref)
+
+	// Only valid JSON numbers are accepted
+	for _, value := range []string{`"0x10"`, `"1e"`, `"NaN"`, `"+1"`, `"01"`, `""`, `"1 2"`} {
+		err = decode([]byte(`{"price": `+value+`}`), &o)
+		utils.AssertEqual(t, true, err != nil, value)
+	}
+}
+
+// go test -run Test_NewJSONDecoder_Errors
+func Test_NewJSONDecoder_Errors(t *testing.T) {
+	t.Parallel()
+	type user struct {
+		Age   int     `json:"age"`
+		Level int8    `json:"level"`
+		Score float64 `json:"score"`
+	}
+
+	decode := NewJSONDecoder()
+	test := func(body string) *FieldError {
+		var u user
+		err := decode([]byte(body), &u)
+		var bindErr *BindError
+		utils.AssertEqual(t, true, errors.As(err, &bindErr), body)
+		utils.AssertEqual(t, 1, len(bindErr.Fields))
+		return bindErr.Fields[0]
+	}
+
+	fieldErr := test(`{"age": 1.5}`)
+	utils.AssertEqual(t, "age", fieldErr.Field)
+	utils.AssertEqual(t, "json", fieldErr.Source)
+	utils.AssertEqual(t, "1.5", fieldErr.Value)
+	utils.AssertEqual(t, "int", fieldErr.Type)
+	utils.AssertEqual(t, "age: expected an integer, got 1.5", fieldErr.Message)
+	var typeErr *json.UnmarshalTypeError
+	utils.AssertEqual(t, true, errors.As(fieldErr, &typeErr))
+
+	utils.AssertEqual(t, "age: expected an integer, got string", test(`{"age": "42"}`).Message)
+	utils.AssertEqual(t, "level: expected an integer in the range of int8, got 300", test(`{"level": 300}`).Message)
+	utils.AssertEqual(t, "score: expected a number, got bool", test(`{"score": true}`).Message)
+
+	// Syntax errors aren't bind errors
+	var u user
+	var syntaxErr *json.SyntaxError
+	utils.AssertEqual(t, true, errors.As(decode([]byte(`{"age": }`), &u), &syntaxErr))
+	utils.AssertEqual(t, true, errors.As(NewJSONDecoder(JSONDecoderConfig{UseNumber: true})([]byte(`{} {}`), &u), &syntaxErr))
+}
+
+// go test -run Test_NewJSONDecoder_UseNumber
+func Test_NewJSONDecoder_UseNumber(t *testing.T) {
+	t.Parallel()
+	var out map[string]interface{}
+	err := NewJSONDecoder(JSONDecoderConfig{UseNumber: true})([]byte(`{"id": 9007199254740993}`), &out)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, json.Number("9007199254740993"), out["id"])
+}
+
+// go test -run Test_Ctx_BodyParser_JSONNumbers
+func Test_Ctx_BodyParser_JSONNumbers(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		JSONDecoder: NewJSONDecoder(JSONDecoderConfig{StringNumbers: true}),
+	})
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().Header.SetContentType(MIMEApplicationJSON)
+	c.Request().SetBody([]byte(`{"id": "9007199254740993", "age": 1.5}`))
+	var out struct {
+		ID  int64 `json:"id"`
+		Age int   `json:"age"`
+	}
+	err := c.BodyParser(&out)
+	utils.AssertEqual(t, int64(9007199254740993), out.ID)
+	var bindErr *BindError
+	utils.AssertEqual(t, true, errors.As(err, &bindErr))
+	utils.AssertEqual(t, "age", bindErr.Fields[0].Field)
+}
@@ -0,0 +1,299 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_BindSourcesOf
+func Test_BindSourcesOf(t *testing.T) {
+	t.Parallel()
+	type Node struct {
+		Children []*Node `query:"children"`
+	}
+	type Body struct {
+		Name string `json:"name"`
+	}
+	utils.AssertEqual(t, bindQuery, bindSourcesOf(reflect.TypeOf(&Node{})))
+	utils.AssertEqual(t, bindSource(0), bindSourcesOf(reflect.TypeOf(Body{})))
+	utils.AssertEqual(t, bindSource(0), bindSourcesOf(reflect.TypeOf(map[string]string{})))
+}
+
+type testRole int
+
+const (
+	testRoleUser testRole = iota + 1
+	testRoleAdmin
+)
+
+// go test -run Test_RegisterDecoder
+func Test_RegisterDecoder(t *testing.T) {
+	RegisterDecoder(reflect.TypeOf(testRole(0)), func(s string) (interface{}, error) {
+		switch s {
+		case "user":
+			return testRoleUser, nil
+		case "admin":
+			return testRoleAdmin, nil
+		}
+		return nil, errors.New("unknown role " + s)
+	})
+
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Query struct {
+		Role  testRole   `query:"role"`
+		Roles []testRole `query:"roles"`
+		Ptr   *testRole  `query:"ptr"`
+	}
+
+	q := new(Query)
+	c.Request().URI().SetQueryString("role=admin&roles=user&roles=admin&ptr=user")
+	utils.AssertEqual(t, nil, c.QueryParser(q))
+	utils.AssertEqual(t, testRoleAdmin, q.Role)
+	utils.AssertEqual(t, []testRole{testRoleUser, testRoleAdmin}, q.Roles)
+	utils.AssertEqual(t, testRoleUser, *q.Ptr)
+
+	c.Request().URI().SetQueryString("role=root")
+	err := c.QueryParser(new(Query))
+	var merr MultiError
+	utils.AssertEqual(t, true, errors.As(err, &merr))
+	var cerr ConversionError
+	utils.AssertEqual(t, true, errors.As(merr["role"], &cerr))
+	utils.AssertEqual(t, "unknown role root", cerr.Err.Error())
+
+	// The decoders survive SetParserDecoder
+	SetParserDecoder(ParserConfig{IgnoreUnknownKeys: true, ZeroEmpty: true})
+	c.Request().URI().SetQueryString("role=user")
+	utils.AssertEqual(t, nil, c.QueryParser(q))
+	utils.AssertEqual(t, testRoleUser, q.Role)
+}
+
+// go test -run Test_BindError
+func Test_BindError(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Item struct {
+		Age int `query:"age"`
+	}
+	type Query struct {
+		ID   int    `query:"id"`
+		Tags []int  `query:"tags"`
+		Name string `query:"name,required"`
+		Data []Item `query:"data"`
+	}
+
+	c.Request().URI().SetQueryString("id=abc&tags=1&tags=x&data.0.age=1&data.1.age=old")
+	err := c.QueryParser(new(Query))
+
+	var bindErr *BindError
+	utils.AssertEqual(t, true, errors.As(err, &bindErr))
+	utils.AssertEqual(t, 4, len(bindErr.Fields))
+
+	utils.AssertEqual(t, "data.1.age", bindErr.Fields[0].Field)
+	utils.AssertEqual(t, "query", bindErr.Fields[0].Source)
+	utils.AssertEqual(t, "old", bindErr.Fields[0].Value)
+	utils.AssertEqual(t, "int", bindErr.Fields[0].Type)
+
+	utils.AssertEqual(t, "id", bindErr.Fields[1].Field)
+	utils.AssertEqual(t, "abc", bindErr.Fields[1].Value)
+
+	utils.AssertEqual(t, "name", bindErr.Fields[2].Field)
+	utils.AssertEqual(t, "", bindErr.Fields[2].Value)
+	var emptyErr EmptyFieldError
+	utils.AssertEqual(t, true, errors.As(bindErr.Fields[2], &emptyErr))
+
+	utils.AssertEqual(t, "tags", bindErr.Fields[3].Field)
+	utils.AssertEqual(t, "x", bindErr.Fields[3].Value)
+	utils.AssertEqual(t, "int", bindErr.Fields[3].Type)
+
+	// Backwards compatibility
+	var multi MultiError
+	utils.AssertEqual(t, true, errors.As(err, &multi))
+	utils.AssertEqual(t, 4, len(multi))
+}
+
+// go test -run Test_Binder_Config
+func Test_Binder_Config(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		Binder: BinderConfig{Strict: true, TimeFormat: "2006-01-02"},
+	})
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Query struct {
+		Name  string    `query:"name"`
+		Tags  []string  `query:"tags"`
+		Since time.Time `query:"since"`
+	}
+
+	q := new(Query)
+	c.Request().URI().SetQueryString("name=john&tags=a,b&since=2022-08-27")
+	utils.AssertEqual(t, nil, c.QueryParser(q))
+	utils.AssertEqual(t, "john", q.Name)
+	utils.AssertEqual(t, []string{"a", "b"}, q.Tags)
+	utils.AssertEqual(t, time.Date(2022, 8, 27, 0, 0, 0, 0, time.Local), q.Since)
+
+	// Unknown keys are rejected by the strict binder of the app
+	c.Request().URI().SetQueryString("name=john&age=42")
+	var bindErr *BindError
+	utils.AssertEqual(t, true, errors.As(c.QueryParser(new(Query)), &bindErr))
+	var unknownErr UnknownKeyError
+	utils.AssertEqual(t, true, errors.As(bindErr.Fields[0], &unknownErr))
+	utils.AssertEqual(t, "age", unknownErr.Key)
+
+	// The config is overridden per call
+	q = new(Query)
+	c.Request().URI().SetQueryString("NAME=john&tags=a,b&age=42")
+	utils.AssertEqual(t, nil, c.Binder().WithConfig(BinderConfig{DisableSplitting: true}).Query(q))
+	utils.AssertEqual(t, "john", q.Name)
+	utils.AssertEqual(t, []string{"a,b"}, q.Tags)
+
+	q = new(Query)
+	utils.AssertEqual(t, nil, c.Binder().WithConfig(BinderConfig{CaseSensitive: true}).Query(q))
+	utils.AssertEqual(t, "", q.Name)
+	utils.AssertEqual(t, []string{"a", "b"}, q.Tags)
+
+	// The default parsers aren't affected
+	c.Request().URI().SetQueryString("name=john&age=42")
+	utils.AssertEqual(t, nil, New().AcquireCtx(c.Context()).QueryParser(new(Query)))
+}
+
+// go test -run Test_Binder_URI
+func Test_Binder_URI(t *testing.T) {
+	t.Parallel()
+	type Request struct {
+		ID    int           `uri:"id|user_id"`
+		Slug  string        `uri:"slug"`
+		TTL   time.Duration `uri:"ttl"`
+		Path  []string      `uri:"*"`
+		Other string        `params:"slug"`
+	}
+	app := New()
+	app.Get("/users/:user_id/posts/:slug/:ttl/*", func(c *Ctx) error {
+		req := new(Request)
+		if err := c.Binder().URI(req); err != nil {
+			var bindErr *BindError
+			utils.AssertEqual(t, true, errors.As(err, &bindErr))
+			return c.Status(StatusBadRequest).SendString(bindErr.Fields[0].Error())
+		}
+		utils.AssertEqual(t, 42, req.ID)
+		utils.AssertEqual(t, "hello", req.Slug)
+		utils.AssertEqual(t, 90*time.Second, req.TTL)
+		utils.AssertEqual(t, []string{"a", "b"}, req.Path)
+		// Only the fields tagged with uri are bound
+		utils.AssertEqual(t, "", req.Other)
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/users/42/posts/hello/1m30s/a/b?id=7", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/users/john/posts/hello/1m30s/a", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusBadRequest, resp.StatusCode)
+}
+
+// go test -run Test_Binder_Limits
+func Test_Binder_Limits(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		Binder: BinderConfig{MaxDepth: 2, MaxSliceLength: 3},
+	})
+
+	type Item struct {
+		Name string `form:"name"`
+	}
+	type Form struct {
+		Items []Item   `form:"items"`
+		Tags  []string `form:"tags"`
+	}
+	test := func(body string) *LimitError {
+		c := app.AcquireCtx(&fasthttp.RequestCtx{})
+		defer app.ReleaseCtx(c)
+		c.Request().Header.SetContentType(MIMEApplicationForm)
+		c.Request().SetBody([]byte(body))
+		err := c.Binder().Body(new(Form))
+		if err == nil {
+			return nil
+		}
+		var bindErr *BindError
+		utils.AssertEqual(t, true, errors.As(err, &bindErr), body)
+		var limitErr LimitError
+		utils.AssertEqual(t, true, errors.As(bindErr.Fields[0], &limitErr), body)
+		return &limitErr
+	}
+
+	utils.AssertEqual(t, true, test("items.2.name=a&tags=a,b,c") == nil)
+	utils.AssertEqual(t, true, test("items[2][name]=a&tags=a&tags=b") == nil)
+
+	limitErr := test("items.3.name=a")
+	utils.AssertEqual(t, "items.3.name", limitErr.Key)
+	utils.AssertEqual(t, `schema: "items.3.name" exceeds the max slice length of 3`, limitErr.Error())
+	utils.AssertEqual(t, "max slice length", test("items.100000000000000000000.name=a").Limit)
+	utils.AssertEqual(t, "tags", test("tags=a,b,c,d").Key)
+	utils.AssertEqual(t, "max depth", test("a.b.c=1").Limit)
+}
+
+// go test -run Test_Binder_DefaultLimits
+func Test_Binder_DefaultLimits(t *testing.T) {
+	t.Parallel()
+	type Item struct {
+		Name string `query:"name"`
+	}
+	type Query struct {
+		Data []Item `query:"data"`
+	}
+	test := func(app *App, query string) (Query, error) {
+		c := app.AcquireCtx(&fasthttp.RequestCtx{})
+		defer app.ReleaseCtx(c)
+		c.Request().URI().SetQueryString(query)
+		var q Query
+		err := c.QueryParser(&q)
+		return q, err
+	}
+
+	// The global parsers reject huge indexes
+	app := New()
+	q, err := test(app, "data.100000.name=a")
+	var bindErr *BindError
+	utils.AssertEqual(t, true, errors.As(err, &bindErr))
+	var limitErr LimitError
+	utils.AssertEqual(t, true, errors.As(bindErr.Fields[0], &limitErr))
+	utils.AssertEqual(t, DefaultBinderMaxSliceLength, limitErr.Max)
+	utils.AssertEqual(t, 0, len(q.Data))
+
+	q, err = test(app, "data.9999.name=a")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "a", q.Data[9999].Name)
+
+	_, err = test(app, strings.Repeat("a.", DefaultBinderMaxDepth)+"b=1")
+	utils.AssertEqual(t, true, errors.As(err, &bindErr))
+	utils.AssertEqual(t, true, errors.As(bindErr.Fields[0], &limitErr))
+	utils.AssertEqual(t, "max depth", limitErr.Limit)
+
+	// Negative limits disable them
+	app = New(Config{
+		Binder: BinderConfig{MaxSliceLength: -1},
+	})
+	q, err = test(app, "data.100000.name=a")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, 100001, len(q.Data))
+}
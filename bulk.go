@@ -0,0 +1,124 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// BulkResult is the outcome of an item of a bulk operation
+type BulkResult struct {
+	// Index of the item in the request
+	Index  int         `json:"index"`
+	Status int         `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  string      `json:"error,omitempty"`
+	// Fields are the failed fields of a BindError
+	Fields []*FieldError `json:"errors,omitempty"`
+}
+
+// Bulk collects the outcomes of the items of a bulk operation, which are sent
+// by Ctx.SendBulk with 200 OK if all items succeeded or 207 Multi-Status:
+//
+//	bulk := fiber.NewBulk(len(users))
+//	for i, user := range users {
+//		if err := db.Create(user); err != nil {
+//			bulk.SetError(i, err)
+//			continue
+//		}
+//		bulk.Set(i, fiber.StatusCreated, user)
+//	}
+//	return c.SendBulk(bulk)
+type Bulk struct {
+	Results []BulkResult
+}
+
+// NewBulk returns a bulk of the items. Set and SetError are safe for
+// concurrent use, as long as the indexes differ.
+func NewBulk(items int) *Bulk {
+	b := &Bulk{Results: make([]BulkResult, items)}
+	for i := range b.Results {
+		b.Results[i].Index = i
+	}
+	return b
+}
+
+// Set sets the outcome of the item at the index
+func (b *Bulk) Set(index, status int, data interface{}) {
+	b.Results[index] = BulkResult{Index: index, Status: status, Data: data}
+}
+
+// SetError sets the error of the item at the index. The status is the code
+// of an *Error, 400 Bad Request with the failed fields of a *BindError or
+// 500 Internal Server Error, like the DefaultErrorHandler.
+func (b *Bulk) SetError(index int, err error) {
+	r := BulkResult{Index: index, Status: StatusInternalServerError, Error: err.Error()}
+	var (
+		e       *Error
+		bindErr *BindError
+	)
+	if errors.As(err, &e) {
+		r.Status = e.Code
+	} else if errors.As(err, &bindErr) {
+		r.Status = StatusBadRequest
+		r.Fields = bindErr.Fields
+	}
+	b.Results[index] = r
+}
+
+// Add appends the outcome of the next item
+func (b *Bulk) Add(status int, data interface{}) {
+	b.Results = append(b.Results, BulkResult{})
+	b.Set(len(b.Results)-1, status, data)
+}
+
+// AddError appends the error of the next item, see SetError
+func (b *Bulk) AddError(err error) {
+	b.Results = append(b.Results, BulkResult{})
+	b.SetError(len(b.Results)-1, err)
+}
+
+// Failed returns the number of items, which failed with a status >= 400
+func (b *Bulk) Failed() int {
+	n := 0
+	for _, r := range b.Results {
+		if r.Status >= StatusBadRequest {
+			n++
+		}
+	}
+	return n
+}
+
+// Status returns 200 OK if all items succeeded, otherwise 207 Multi-Status
+func (b *Bulk) Status() int {
+	if b.Failed() > 0 {
+		return StatusMultiStatus
+	}
+	return StatusOK
+}
+
+// MarshalJSON encodes the results with the numbers of succeeded and failed items:
+//
+//	{"succeeded":1,"failed":1,"results":[{"index":0,"status":201,"data":{}},{"index":1,"status":409,"error":"Conflict"}]}
+func (b *Bulk) MarshalJSON() ([]byte, error) {
+	failed := b.Failed()
+	results := b.Results
+	if results == nil {
+		results = []BulkResult{}
+	}
+	return json.Marshal(struct {
+		Succeeded int          `json:"succeeded"`
+		Failed    int          `json:"failed"`
+		Results   []BulkResult `json:"results"`
+	}{len(results) - failed, failed, results})
+}
+
+// SendBulk sends the outcomes of the bulk operation as JSON with the status of
+// the bulk, see Bulk.Status.
+func (c *Ctx) SendBulk(b *Bulk) error {
+	c.Status(b.Status())
+	return c.JSON(b)
+}
@@ -0,0 +1,79 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_Ctx_SendBulk
+func Test_Ctx_SendBulk(t *testing.T) {
+	t.Parallel()
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	app := New()
+	app.Post("/users", func(c *Ctx) error {
+		var users []user
+		if err := c.BodyParser(&users); err != nil {
+			return err
+		}
+		bulk := NewBulk(len(users))
+		for i, u := range users {
+			switch {
+			case u.Name == "":
+				bulk.SetError(i, NewError(StatusUnprocessableEntity, "name is required"))
+			case u.Age < 0:
+				bulk.SetError(i, errors.New("db: constraint violated"))
+			default:
+				bulk.Set(i, StatusCreated, u)
+			}
+		}
+		return c.SendBulk(bulk)
+	})
+
+	for _, tc := range []struct {
+		body     string
+		status   int
+		expected string
+	}{
+		{`[{"name":"john","age":30}]`, StatusOK, `{"succeeded":1,"failed":0,"results":[{"index":0,"status":201,"data":{"name":"john","age":30}}]}`},
+		{`[{"name":"john"},{"age":3},{"name":"jane","age":-1}]`, StatusMultiStatus, `{"succeeded":1,"failed":2,"results":[` +
+			`{"index":0,"status":201,"data":{"name":"john","age":0}},` +
+			`{"index":1,"status":422,"error":"name is required"},` +
+			`{"index":2,"status":500,"error":"db: constraint violated"}]}`},
+		{`[]`, StatusOK, `{"succeeded":0,"failed":0,"results":[]}`},
+	} {
+		req := httptest.NewRequest(MethodPost, "/users", strings.NewReader(tc.body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, tc.status, resp.StatusCode)
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.expected, string(body))
+	}
+}
+
+// go test -run Test_Bulk_BindError
+func Test_Bulk_BindError(t *testing.T) {
+	t.Parallel()
+	bindErr := &BindError{Fields: []*FieldError{{Field: "age", Source: "json", Message: "invalid"}}}
+
+	var bulk Bulk
+	bulk.Add(StatusOK, nil)
+	bulk.AddError(bindErr)
+	utils.AssertEqual(t, 1, bulk.Failed())
+	utils.AssertEqual(t, StatusMultiStatus, bulk.Status())
+	utils.AssertEqual(t, BulkResult{Index: 1, Status: StatusBadRequest, Error: bindErr.Error(), Fields: bindErr.Fields}, bulk.Results[1])
+}
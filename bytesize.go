@@ -0,0 +1,121 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// ByteSize is a size in bytes, which is bound from values like "512", "10MB"
+// or "1.5GiB" by the parsers, e.g. for upload limits of ops-facing endpoints:
+//
+//	type Query struct {
+//	    MaxSize fiber.ByteSize `query:"max_size"`
+//	}
+//
+// The units kB, MB, GB, TB and PB are powers of 1000, the units KiB, MiB,
+// GiB, TiB and PiB are powers of 1024, see ParseByteSize.
+type ByteSize int64
+
+// byteUnits are the units of ByteSize by their lower case name
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// errInvalidByteSize is returned by ParseByteSize for invalid sizes
+var errInvalidByteSize = errors.New("invalid byte size, expected a number with an optional unit like 10MB or 1.5GiB")
+
+// ParseByteSize parses a size in bytes with an optional unit, e.g. "512",
+// "10MB", "10 mb" or "1.5GiB". The units are case-insensitive.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = utils.Trim(s, ' ')
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+	if i == 0 {
+		return 0, errInvalidByteSize
+	}
+	unit, ok := byteUnits[utils.ToLower(utils.Trim(s[i:], ' '))]
+	if !ok {
+		return 0, errInvalidByteSize
+	}
+	// Integers are parsed exactly, fractions are rounded to whole bytes
+	if n, err := strconv.ParseInt(s[:i], 10, 64); err == nil && unit == float64(int64(unit)) {
+		if n > math.MaxInt64/int64(unit) {
+			return 0, errInvalidByteSize
+		}
+		return ByteSize(n * int64(unit)), nil
+	}
+	f, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil || f*unit >= math.MaxInt64 {
+		return 0, errInvalidByteSize
+	}
+	return ByteSize(math.Round(f * unit)), nil
+}
+
+// String formats the size with the largest binary unit, which divides it, e.g. "10MiB"
+func (b ByteSize) String() string {
+	units := []string{"PiB", "TiB", "GiB", "MiB", "KiB"}
+	for i, unit := range units {
+		size := ByteSize(1) << (10 * (len(units) - i))
+		if b != 0 && b%size == 0 {
+			return strconv.FormatInt(int64(b/size), 10) + unit
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// MarshalText encodes the size as its String.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText decodes the size with ParseByteSize.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// UnmarshalJSON decodes the size from a string like "10MB" or a number of bytes.
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, "\"") {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return err
+		}
+		s = unquoted
+	}
+	return b.UnmarshalText([]byte(s))
+}
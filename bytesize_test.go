@@ -0,0 +1,71 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_ParseByteSize
+func Test_ParseByteSize(t *testing.T) {
+	t.Parallel()
+	for input, expected := range map[string]ByteSize{
+		"0":       0,
+		"512":     512,
+		"512B":    512,
+		"10kB":    10_000,
+		"10MB":    10_000_000,
+		" 10 mb ": 10_000_000,
+		"2G":      2_000_000_000,
+		"1KiB":    1024,
+		"1.5KiB":  1536,
+		"4MiB":    4 << 20,
+		"0.5GiB":  1 << 29,
+		"1TiB":    1 << 40,
+		"1PB":     1e15,
+		"9223P":   9223 * 1e15,
+	} {
+		size, err := ParseByteSize(input)
+		utils.AssertEqual(t, nil, err, input)
+		utils.AssertEqual(t, expected, size, input)
+	}
+
+	for _, input := range []string{"", "MB", "-1MB", "10XB", "1.2.3MB", "1e3", "9223372036854775808", "10000000PiB"} {
+		_, err := ParseByteSize(input)
+		utils.AssertEqual(t, errInvalidByteSize, err, input)
+	}
+}
+
+// go test -run Test_ByteSize_String
+func Test_ByteSize_String(t *testing.T) {
+	t.Parallel()
+	utils.AssertEqual(t, "0B", ByteSize(0).String())
+	utils.AssertEqual(t, "1000B", ByteSize(1000).String())
+	utils.AssertEqual(t, "1KiB", ByteSize(1024).String())
+	utils.AssertEqual(t, "1536B", ByteSize(1536).String())
+	utils.AssertEqual(t, "4MiB", ByteSize(4<<20).String())
+	utils.AssertEqual(t, "3GiB", ByteSize(3<<30).String())
+}
+
+// go test -run Test_ByteSize_JSON
+func Test_ByteSize_JSON(t *testing.T) {
+	t.Parallel()
+	var limits struct {
+		Body   ByteSize `json:"body"`
+		Upload ByteSize `json:"upload"`
+	}
+	utils.AssertEqual(t, nil, json.Unmarshal([]byte(`{"body": "4MiB", "upload": 1024}`), &limits))
+	utils.AssertEqual(t, ByteSize(4<<20), limits.Body)
+	utils.AssertEqual(t, ByteSize(1024), limits.Upload)
+
+	out, err := json.Marshal(limits)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `{"body":"4MiB","upload":"1KiB"}`, string(out))
+
+	utils.AssertEqual(t, true, json.Unmarshal([]byte(`{"body": "4XB"}`), &limits) != nil)
+}
@@ -0,0 +1,98 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+// RequestClass classifies the effect of a request on the server state, see
+// RFC 7231, 4.2. Middleware uses it e.g. to enforce CSRF protection only on
+// mutating requests, to allow retries of idempotent requests or to route
+// safe requests to a database replica.
+type RequestClass uint8
+
+// Request classes, the zero value means the class is derived from the method
+const (
+	// ClassMutating requests change the state, repeating them may change it again
+	ClassMutating RequestClass = iota + 1
+	// ClassIdempotent requests change the state, repeating them has no further effect
+	ClassIdempotent
+	// ClassSafe requests are read-only
+	ClassSafe
+)
+
+// MethodClass returns the class of the HTTP method as defined by RFC 7231,
+// unknown methods are mutating.
+func MethodClass(method string) RequestClass {
+	switch method {
+	case MethodGet, MethodHead, MethodOptions, MethodTrace:
+		return ClassSafe
+	case MethodPut, MethodDelete:
+		return ClassIdempotent
+	default:
+		return ClassMutating
+	}
+}
+
+// IsSafe reports if requests of the class are read-only.
+func (rc RequestClass) IsSafe() bool {
+	return rc == ClassSafe
+}
+
+// IsIdempotent reports if requests of the class can be repeated, e.g. retried
+// after a network failure, safe requests are idempotent too.
+func (rc RequestClass) IsIdempotent() bool {
+	return rc == ClassIdempotent || rc == ClassSafe
+}
+
+// String returns "safe", "idempotent" or "mutating".
+func (rc RequestClass) String() string {
+	switch rc {
+	case ClassSafe:
+		return "safe"
+	case ClassIdempotent:
+		return "idempotent"
+	case ClassMutating:
+		return "mutating"
+	default:
+		return ""
+	}
+}
+
+// MarshalText encodes the class as its name, see String.
+func (rc RequestClass) MarshalText() ([]byte, error) {
+	return []byte(rc.String()), nil
+}
+
+// Class declares the request class of the latest registered route, which
+// overrides the class derived from the method:
+//
+//	app.Post("/search", handler)
+//	app.Class(fiber.ClassSafe)
+func (app *App) Class(class RequestClass) *App {
+	app.mutex.Lock()
+	for _, route := range app.latestRoutes() {
+		route.Class = class
+	}
+	app.mutex.Unlock()
+
+	return app
+}
+
+// RequestClass returns the class of the route, which is the declared one
+// or the one derived from the method.
+func (r *Route) RequestClass() RequestClass {
+	if r.Class != 0 {
+		return r.Class
+	}
+	return MethodClass(r.Method)
+}
+
+// RequestClass returns the class of the request, which is the class declared
+// on the matched route or the one derived from the method, see App.Class.
+// It's available in middleware before the route handler is reached.
+func (c *Ctx) RequestClass() RequestClass {
+	if route := c.MatchedRoute(); route != nil && route.Class != 0 {
+		return route.Class
+	}
+	return MethodClass(c.method)
+}
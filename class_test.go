@@ -0,0 +1,76 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_MethodClass
+func Test_MethodClass(t *testing.T) {
+	t.Parallel()
+	for method, class := range map[string]RequestClass{
+		MethodGet:     ClassSafe,
+		MethodHead:    ClassSafe,
+		MethodOptions: ClassSafe,
+		MethodTrace:   ClassSafe,
+		MethodPut:     ClassIdempotent,
+		MethodDelete:  ClassIdempotent,
+		MethodPost:    ClassMutating,
+		MethodPatch:   ClassMutating,
+		"PURGE":       ClassMutating,
+	} {
+		utils.AssertEqual(t, class, MethodClass(method), method)
+	}
+	utils.AssertEqual(t, true, ClassSafe.IsIdempotent())
+	utils.AssertEqual(t, false, ClassIdempotent.IsSafe())
+	utils.AssertEqual(t, false, ClassMutating.IsIdempotent())
+}
+
+// go test -run Test_Ctx_RequestClass
+func Test_Ctx_RequestClass(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var classes []RequestClass
+	app.Use(func(c *Ctx) error {
+		classes = append(classes, c.RequestClass())
+		return c.Next()
+	})
+	app.Post("/search", func(c *Ctx) error {
+		return c.SendStatus(StatusOK)
+	})
+	app.Class(ClassSafe)
+	app.Group("/api").Get("/logout", func(c *Ctx) error {
+		return c.SendStatus(StatusOK)
+	})
+	app.Class(ClassMutating)
+	app.Put("/users/:id", func(c *Ctx) error {
+		return c.SendStatus(StatusOK)
+	})
+
+	for _, req := range []struct{ method, path string }{
+		{MethodPost, "/search"},
+		{MethodGet, "/api/logout"},
+		{MethodHead, "/api/logout"},
+		{MethodPut, "/users/1"},
+		{MethodPost, "/not-found"},
+	} {
+		_, err := app.Test(httptest.NewRequest(req.method, req.path, nil))
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+	}
+	utils.AssertEqual(t, []RequestClass{ClassSafe, ClassMutating, ClassMutating, ClassIdempotent, ClassMutating}, classes)
+
+	route := app.Stack()[app.methodInt(MethodPost)][1]
+	utils.AssertEqual(t, ClassSafe, route.RequestClass())
+	data, err := json.Marshal(route)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, strings.Contains(string(data), `"class":"safe"`))
+}
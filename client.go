@@ -78,6 +78,40 @@ type Client struct {
 	//
 	// Allowing for flexibility in using another json library for decoding
 	JSONDecoder utils.JSONUnmarshal
+
+	// NoTracePropagation disables the propagation of the trace context of
+	// the originating Ctx, see Agent.WithCtx.
+	NoTracePropagation bool
+
+	handlers []AgentHandler
+}
+
+// AgentHandler is a handler of the outgoing requests of agents, see Client.Use
+type AgentHandler = func(a *Agent) error
+
+// Use registers handlers, which run for every request of the agents created
+// by the client, in the order of registration, like the middleware of an app.
+// A handler calls Agent.Next to continue with the next handler, the last one
+// sends the request. Once Next returned, Agent.Response is the response:
+//
+//	client.Use(func(a *fiber.Agent) error {
+//		start := time.Now()
+//		err := a.Next()
+//		log.Printf("%s %s: %d in %v", a.Request().Header.Method(), a.Request().URI(),
+//			a.Response().StatusCode(), time.Since(start))
+//		return err
+//	})
+//
+// Handlers may call Next several times, e.g. to retry the request with a
+// refreshed access token, or not at all, e.g. to respond from a cache by
+// filling the response themselves. Errors of handlers are returned by the
+// agent. The handlers must not change the host of the request, since the
+// agent has been connected to it already.
+func (c *Client) Use(handlers ...AgentHandler) *Client {
+	c.mutex.Lock()
+	c.handlers = append(c.handlers, handlers...)
+	c.mutex.Unlock()
+	return c
 }
 
 // Get returns a agent with http method GET.
@@ -136,6 +170,8 @@ func (c *Client) createAgent(method, url string) *Agent {
 	c.mutex.RLock()
 	a.Name = c.UserAgent
 	a.NoDefaultUserAgentHeader = c.NoDefaultUserAgentHeader
+	a.NoTracePropagation = c.NoTracePropagation
+	a.handlers = append(a.handlers, c.handlers...)
 	a.jsonDecoder = c.JSONDecoder
 	a.jsonEncoder = c.JSONEncoder
 	if a.jsonDecoder == nil {
@@ -160,6 +196,10 @@ type Agent struct {
 	// User-Agent header to be excluded from the Request.
 	NoDefaultUserAgentHeader bool
 
+	// NoTracePropagation when set to true, causes WithCtx to not
+	// propagate the trace context.
+	NoTracePropagation bool
+
 	// HostClient is an embedded fasthttp HostClient
 	*fasthttp.HostClient
 
@@ -175,6 +215,10 @@ type Agent struct {
 	jsonEncoder       utils.JSONMarshal
 	jsonDecoder       utils.JSONUnmarshal
 	maxRedirectsCount int
+	upstreams         *UpstreamPool
+	handlers          []AgentHandler
+	handlerIndex      int
+	response          *Response
 	boundary          string
 	reuse             bool
 	parsed            bool
@@ -653,6 +697,43 @@ func (a *Agent) Timeout(timeout time.Duration) *Agent {
 	return a
 }
 
+// Upstreams sends the request to the next server of the pool instead of the
+// host of the url, the Host header is kept. The TLS and buffer settings of the
+// agent are not used, see UpstreamPoolConfig.NewClient.
+func (a *Agent) Upstreams(pool *UpstreamPool) *Agent {
+	a.upstreams = pool
+
+	return a
+}
+
+// Use registers handlers for the requests of the agent, which run after the
+// handlers of the client, see Client.Use.
+func (a *Agent) Use(handlers ...AgentHandler) *Agent {
+	a.handlers = append(a.handlers, handlers...)
+
+	return a
+}
+
+// Next runs the next handler of the agent, the last one sends the request,
+// see Client.Use.
+func (a *Agent) Next() error {
+	i := a.handlerIndex
+	a.handlerIndex++
+	// Restore the index, so that handlers can call Next again, e.g. to retry
+	defer func() { a.handlerIndex = i }()
+
+	if i < len(a.handlers) {
+		return a.handlers[i](a)
+	}
+	return a.do()
+}
+
+// Response returns the response of the request, which is being sent by the
+// handlers of the agent, nil outside of the handlers, see Client.Use.
+func (a *Agent) Response() *Response {
+	return a.response
+}
+
 // Reuse enables the Agent instance to be used again after one request.
 //
 // If agent is reusable, then it should be released manually when it is no
@@ -782,23 +863,42 @@ func (a *Agent) bytes() (code int, body []byte, errs []error) {
 		}
 	}()
 
-	if a.timeout > 0 {
-		if err := a.HostClient.DoTimeout(req, resp, a.timeout); err != nil {
-			errs = append(errs, err)
-			return
-		}
-	} else if a.maxRedirectsCount > 0 && (string(req.Header.Method()) == MethodGet || string(req.Header.Method()) == MethodHead) {
-		if err := a.HostClient.DoRedirects(req, resp, a.maxRedirectsCount); err != nil {
-			errs = append(errs, err)
-			return
-		}
-	} else if err := a.HostClient.Do(req, resp); err != nil {
+	// Run the handlers, the last one sends the request
+	a.response = resp
+	a.handlerIndex = 0
+	if err := a.Next(); err != nil {
 		errs = append(errs, err)
 	}
+	a.response = nil
 
 	return
 }
 
+// do sends the request, it's the last handler of the agent
+func (a *Agent) do() (err error) {
+	req, resp := a.req, a.response
+
+	client := a.HostClient
+	if a.upstreams != nil {
+		upstream := a.upstreams.Next()
+		if upstream == nil {
+			return ErrServiceUnavailable
+		}
+		client = upstream.Client()
+		defer func() {
+			a.upstreams.Report(upstream, err != nil || isUpstreamFailure(resp.StatusCode()))
+		}()
+	}
+
+	if a.timeout > 0 {
+		return client.DoTimeout(req, resp, a.timeout)
+	}
+	if a.maxRedirectsCount > 0 && (string(req.Header.Method()) == MethodGet || string(req.Header.Method()) == MethodHead) {
+		return client.DoRedirects(req, resp, a.maxRedirectsCount)
+	}
+	return client.Do(req, resp)
+}
+
 func printDebugInfo(req *Request, resp *Response, w io.Writer) {
 	msg := fmt.Sprintf("Connected to %s(%s)\r\n\r\n", req.URI().Host(), resp.RemoteAddr())
 	_, _ = w.Write(utils.UnsafeBytes(msg))
@@ -854,9 +954,17 @@ func (a *Agent) reset() {
 	a.reuse = false
 	a.parsed = false
 	a.maxRedirectsCount = 0
+	a.upstreams = nil
+	for i := range a.handlers {
+		a.handlers[i] = nil
+	}
+	a.handlers = a.handlers[:0]
+	a.handlerIndex = 0
+	a.response = nil
 	a.boundary = ""
 	a.Name = ""
 	a.NoDefaultUserAgentHeader = false
+	a.NoTracePropagation = false
 	for i, ff := range a.formFiles {
 		if ff.autoRelease {
 			ReleaseFormFile(ff)
@@ -900,6 +1008,8 @@ func ReleaseClient(c *Client) {
 	c.NoDefaultUserAgentHeader = false
 	c.JSONEncoder = nil
 	c.JSONDecoder = nil
+	c.NoTracePropagation = false
+	c.handlers = nil
 
 	clientPool.Put(c)
 }
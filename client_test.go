@@ -904,6 +904,71 @@ func Test_Client_Agent_Timeout(t *testing.T) {
 	utils.AssertEqual(t, "timeout", errs[0].Error())
 }
 
+// go test -run Test_Client_Use
+func Test_Client_Use(t *testing.T) {
+	t.Parallel()
+
+	ln := fasthttputil.NewInmemoryListener()
+	app := New(Config{DisableStartupMessage: true})
+	app.Get("/", func(c *Ctx) error {
+		if c.Get(HeaderAuthorization) != "Bearer fresh" {
+			return ErrUnauthorized
+		}
+		return c.SendString(c.Get("X-Tenant"))
+	})
+	go func() { utils.AssertEqual(t, nil, app.Listener(ln)) }()
+
+	var (
+		token    = "stale"
+		statuses []int
+	)
+	client := &Client{}
+	client.Use(func(a *Agent) error {
+		// Logs the status of every attempt
+		err := a.Next()
+		statuses = append(statuses, a.Response().StatusCode())
+		return err
+	}, func(a *Agent) error {
+		// Injects the access token and refreshes it once if it has expired
+		a.Request().Header.Set(HeaderAuthorization, "Bearer "+token)
+		if err := a.Next(); err != nil || a.Response().StatusCode() != StatusUnauthorized {
+			return err
+		}
+		token = "fresh"
+		a.Request().Header.Set(HeaderAuthorization, "Bearer "+token)
+		return a.Next()
+	})
+
+	a := client.Get("http://example.com").Use(func(a *Agent) error {
+		a.Request().Header.Set("X-Tenant", "acme")
+		return a.Next()
+	})
+	a.HostClient.Dial = func(addr string) (net.Conn, error) { return ln.Dial() }
+	code, body, errs := a.String()
+	utils.AssertEqual(t, 0, len(errs))
+	utils.AssertEqual(t, StatusOK, code)
+	utils.AssertEqual(t, "acme", body)
+	// The retry runs the following handlers only
+	utils.AssertEqual(t, []int{StatusOK}, statuses)
+
+	// Handlers can respond without sending the request or fail it
+	a = client.Get("http://example.com").Use(func(a *Agent) error {
+		a.Response().SetStatusCode(StatusTeapot)
+		a.Response().SetBodyString("cached")
+		return nil
+	})
+	code, body, errs = a.String()
+	utils.AssertEqual(t, 0, len(errs))
+	utils.AssertEqual(t, StatusTeapot, code)
+	utils.AssertEqual(t, "cached", body)
+
+	errTokenSource := errors.New("token source unavailable")
+	_, _, errs = client.Get("http://example.com").Use(func(a *Agent) error {
+		return errTokenSource
+	}).String()
+	utils.AssertEqual(t, []error{errTokenSource}, errs)
+}
+
 func Test_Client_Agent_Reuse(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,152 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/internal/xxhash"
+)
+
+// ETagMode controls the ETag generation of a route, see App.ETag
+type ETagMode uint8
+
+// ETag modes, the zero value means the mode of Config.ETag is used
+const (
+	// ETagDisabled disables the ETag generation
+	ETagDisabled ETagMode = iota + 1
+	// ETagStrong generates strong ETags
+	ETagStrong
+	// ETagWeak generates weak ETags
+	ETagWeak
+	// ETagJSON generates strong ETags, the ones of Ctx.JSON are the xxhash of
+	// the JSON or the ETag of an ETagger, see Config.JSONETag
+	ETagJSON
+)
+
+// ETagger is implemented by values with their own ETag, e.g. a version or an
+// update time, which Ctx.JSON uses with ETagJSON. Values with an ETag matching
+// the If-None-Match header of the request aren't encoded at all.
+type ETagger interface {
+	ETag() string
+}
+
+// ETag declares the ETag generation of the latest registered route, which
+// overrides Config.ETag:
+//
+//	app.Get("/report", handler)
+//	app.ETag(fiber.ETagWeak)
+//
+// The ETag of a body is its length and checksum, the one of a streamed body,
+// e.g. sent by SendFile, is its length and modification time. Requests with a
+// matching If-None-Match header get a 304 Not Modified response. See
+// ETagJSON for the ETags of JSON responses.
+func (app *App) ETag(mode ETagMode) *App {
+	app.mutex.Lock()
+	for _, route := range app.latestRoutes() {
+		route.ETag = mode
+	}
+	app.mutex.Unlock()
+
+	return app
+}
+
+// etagMode returns the ETag mode of the route, which handled the request
+func (app *App) etagMode(c *Ctx) ETagMode {
+	if c.route != nil && c.route.ETag != 0 {
+		return c.route.ETag
+	}
+	if app.config.JSONETag {
+		return ETagJSON
+	}
+	if app.config.ETag {
+		return ETagStrong
+	}
+	return ETagDisabled
+}
+
+// setJSONETag sets the ETag of a JSON response with ETagJSON and reports if
+// the request has a matching If-None-Match header, then the response is
+// 304 Not Modified
+func setJSONETag(c *Ctx, etag string) bool {
+	if c.fasthttp.Response.StatusCode() != StatusOK {
+		return false
+	}
+	if !strings.HasPrefix(etag, "\"") && !strings.HasPrefix(etag, "W/\"") {
+		etag = "\"" + etag + "\""
+	}
+	c.setCanonical(normalizedHeaderETag, etag)
+
+	noneMatch := c.fasthttp.Request.Header.Peek(HeaderIfNoneMatch)
+	if len(noneMatch) == 0 || !(c.fasthttp.IsGet() || c.fasthttp.IsHead()) {
+		return false
+	}
+	if c.app.getString(noneMatch) == "*" || !c.app.isEtagStale(etag, noneMatch) {
+		notModified(c)
+		return true
+	}
+	return false
+}
+
+// jsonETag returns the ETag of the JSON, which is its xxhash
+func jsonETag(raw []byte) string {
+	return "\"" + strconv.FormatUint(xxhash.Sum64(raw), 16) + "\""
+}
+
+// streamETag returns the ETag of a streamed body, which is derived from its
+// length and Last-Modified header, since reading the stream would buffer it
+func streamETag(c *Ctx) string {
+	length := c.fasthttp.Response.Header.ContentLength()
+	lastModified, err := http.ParseTime(c.app.getString(c.fasthttp.Response.Header.Peek(HeaderLastModified)))
+	if length < 0 || err != nil {
+		return ""
+	}
+	return "\"" + strconv.Itoa(length) + "-" + strconv.FormatInt(lastModified.Unix(), 16) + "\""
+}
+
+// setNotModified responds with 304 Not Modified if the validators of the
+// response match the conditions of the request, see RFC 7232, 6. It's only
+// called for routes with ETags, so that the responses of the other routes
+// don't change.
+func setNotModified(c *Ctx) {
+	if c.fasthttp.Response.StatusCode() != StatusOK || !(c.fasthttp.IsGet() || c.fasthttp.IsHead()) {
+		return
+	}
+
+	// If-Modified-Since is ignored if If-None-Match is present
+	if noneMatch := c.fasthttp.Request.Header.Peek(HeaderIfNoneMatch); len(noneMatch) > 0 {
+		etag := c.app.getString(c.fasthttp.Response.Header.Peek(HeaderETag))
+		if c.app.getString(noneMatch) == "*" || (etag != "" && !c.app.isEtagStale(etag, noneMatch)) {
+			notModified(c)
+		}
+		return
+	}
+
+	modifiedSince := c.fasthttp.Request.Header.Peek(HeaderIfModifiedSince)
+	lastModified := c.fasthttp.Response.Header.Peek(HeaderLastModified)
+	if len(modifiedSince) == 0 || len(lastModified) == 0 {
+		return
+	}
+	modifiedSinceTime, err := http.ParseTime(c.app.getString(modifiedSince))
+	if err != nil {
+		return
+	}
+	lastModifiedTime, err := http.ParseTime(c.app.getString(lastModified))
+	if err != nil {
+		return
+	}
+	if !lastModifiedTime.After(modifiedSinceTime) {
+		notModified(c)
+	}
+}
+
+// notModified responds with 304 Not Modified, keeping the headers of the
+// response, e.g. ETag and Cache-Control
+func notModified(c *Ctx) {
+	c.fasthttp.Response.ResetBody()
+	c.fasthttp.Response.SetStatusCode(StatusNotModified)
+}
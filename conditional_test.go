@@ -0,0 +1,230 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/internal/xxhash"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_App_ETag
+func Test_App_ETag(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "file.txt")
+	utils.AssertEqual(t, nil, ioutil.WriteFile(file, []byte("file content"), 0o600))
+	modTime := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	utils.AssertEqual(t, nil, os.Chtimes(file, modTime, modTime))
+
+	app := New(Config{ETag: true})
+	app.Get("/strong", func(c *Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+	app.Group("/v1").Get("/weak", func(c *Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+	app.ETag(ETagWeak)
+	app.Get("/disabled", func(c *Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+	app.ETag(ETagDisabled)
+	app.Get("/file", func(c *Ctx) error {
+		return c.SendFile(file)
+	})
+
+	request := func(method, target string, header ...string) (int, string, string) {
+		req := httptest.NewRequest(method, target, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		return resp.StatusCode, resp.Header.Get(HeaderETag), string(body)
+	}
+
+	status, etag, _ := request(MethodGet, "/strong")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, `"13-1831710635"`, etag)
+	status, _, body := request(MethodGet, "/strong", HeaderIfNoneMatch, `"other", "13-1831710635"`)
+	utils.AssertEqual(t, StatusNotModified, status)
+	utils.AssertEqual(t, "", body)
+
+	status, etag, _ = request(MethodHead, "/v1/weak")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, `W/"13-1831710635"`, etag)
+
+	status, etag, _ = request(MethodGet, "/disabled")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, "", etag)
+
+	// The file isn't buffered to generate the ETag
+	status, etag, body = request(MethodGet, "/file")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, `"12-61d11625"`, etag)
+	utils.AssertEqual(t, "file content", body)
+	status, _, _ = request(MethodGet, "/file", HeaderIfNoneMatch, etag)
+	utils.AssertEqual(t, StatusNotModified, status)
+}
+
+type versionedUser struct {
+	Name    string `json:"name"`
+	Version int    `json:"-"`
+}
+
+func (u *versionedUser) ETag() string {
+	return "v" + strconv.Itoa(u.Version)
+}
+
+// go test -run Test_App_ETagJSON
+func Test_App_ETagJSON(t *testing.T) {
+	t.Parallel()
+	encoded := 0
+	app := New(Config{
+		JSONETag: true,
+		JSONEncoder: func(v interface{}) ([]byte, error) {
+			encoded++
+			return json.Marshal(v)
+		},
+	})
+	app.Get("/json", func(c *Ctx) error {
+		return c.JSON(Map{"name": "john"})
+	})
+	app.Get("/text", func(c *Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+	app.Get("/user", func(c *Ctx) error {
+		return c.JSON(&versionedUser{Name: "john", Version: 3})
+	})
+	app.Get("/strong", func(c *Ctx) error {
+		return c.JSON(Map{"name": "john"})
+	})
+	app.ETag(ETagStrong)
+	app.Post("/json", func(c *Ctx) error {
+		return c.JSON(Map{"name": "john"})
+	})
+
+	request := func(method, target string, header ...string) (int, string, string) {
+		req := httptest.NewRequest(method, target, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		return resp.StatusCode, resp.Header.Get(HeaderETag), string(body)
+	}
+
+	// The ETag of JSON is its xxhash
+	status, etag, body := request(MethodGet, "/json")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, `"`+strconv.FormatUint(xxhash.Sum64String(`{"name":"john"}`), 16)+`"`, etag)
+	utils.AssertEqual(t, `{"name":"john"}`, body)
+	status, _, body = request(MethodGet, "/json", HeaderIfNoneMatch, etag)
+	utils.AssertEqual(t, StatusNotModified, status)
+	utils.AssertEqual(t, "", body)
+	status, etag2, _ := request(MethodPost, "/json", HeaderIfNoneMatch, etag)
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, etag, etag2)
+
+	// Other responses get the ETags of ETagStrong
+	_, etag, _ = request(MethodGet, "/text")
+	utils.AssertEqual(t, `"13-1831710635"`, etag)
+	_, etag, _ = request(MethodGet, "/strong")
+	utils.AssertEqual(t, `"15-3265726049"`, etag)
+
+	// ETaggers aren't encoded for matching requests
+	encoded = 0
+	status, etag, body = request(MethodGet, "/user")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, `"v3"`, etag)
+	utils.AssertEqual(t, `{"name":"john"}`, body)
+	status, _, body = request(MethodGet, "/user", HeaderIfNoneMatch, `W/"v3"`)
+	utils.AssertEqual(t, StatusNotModified, status)
+	utils.AssertEqual(t, "", body)
+	utils.AssertEqual(t, 1, encoded)
+}
+
+// go test -run Test_App_NotModified
+func Test_App_NotModified(t *testing.T) {
+	t.Parallel()
+	// The conditions are evaluated with ETags, ETagJSON keeps the ones of the handlers
+	app := New(Config{JSONETag: true})
+	app.All("/", func(c *Ctx) error {
+		c.Set(HeaderETag, `W/"v1"`)
+		c.Set(HeaderLastModified, "Sun, 02 Jan 2022 03:04:05 GMT")
+		return c.SendString("content")
+	})
+
+	for _, tc := range []struct {
+		method, header, value string
+		status                int
+	}{
+		{MethodGet, HeaderIfNoneMatch, `"v1"`, StatusNotModified},
+		{MethodHead, HeaderIfNoneMatch, `W/"v1"`, StatusNotModified},
+		{MethodGet, HeaderIfNoneMatch, "*", StatusNotModified},
+		{MethodGet, HeaderIfNoneMatch, `"v2"`, StatusOK},
+		{MethodPost, HeaderIfNoneMatch, `"v1"`, StatusOK},
+		{MethodGet, HeaderIfModifiedSince, "Sun, 02 Jan 2022 03:04:05 GMT", StatusNotModified},
+		{MethodGet, HeaderIfModifiedSince, "Sat, 01 Jan 2022 03:04:05 GMT", StatusOK},
+		{MethodGet, HeaderIfModifiedSince, "invalid", StatusOK},
+	} {
+		req := httptest.NewRequest(tc.method, "/", nil)
+		req.Header.Set(tc.header, tc.value)
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, tc.status, resp.StatusCode, tc.method+" "+tc.header+": "+tc.value)
+		if tc.status == StatusNotModified {
+			utils.AssertEqual(t, `W/"v1"`, resp.Header.Get(HeaderETag))
+		}
+	}
+
+	// If-Modified-Since is ignored if If-None-Match is present
+	req := httptest.NewRequest(MethodGet, "/", nil)
+	req.Header.Set(HeaderIfNoneMatch, `"v2"`)
+	req.Header.Set(HeaderIfModifiedSince, "Sun, 02 Jan 2022 03:04:05 GMT")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_App_NotModified_Disabled
+func Test_App_NotModified_Disabled(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c *Ctx) error {
+		c.Set(HeaderLastModified, "Sun, 02 Jan 2022 03:04:05 GMT")
+		return c.SendString("content")
+	})
+	app.Get("/etag", func(c *Ctx) error {
+		return c.SendString("content")
+	})
+	app.ETag(ETagStrong)
+
+	request := func(path, header, value string) int {
+		req := httptest.NewRequest(MethodGet, path, nil)
+		req.Header.Set(header, value)
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		return resp.StatusCode
+	}
+
+	// The conditions aren't evaluated without ETags
+	utils.AssertEqual(t, StatusOK, request("/", HeaderIfNoneMatch, "*"))
+	utils.AssertEqual(t, StatusOK, request("/", HeaderIfModifiedSince, "Sun, 02 Jan 2022 03:04:05 GMT"))
+
+	// Routes with ETags evaluate them
+	utils.AssertEqual(t, StatusNotModified, request("/etag", HeaderIfNoneMatch, "*"))
+}
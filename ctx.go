@@ -5,6 +5,7 @@
 package fiber
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/tls"
@@ -14,9 +15,12 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"mime/multipart"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
 	"path/filepath"
 	"reflect"
 	"strconv"
@@ -35,17 +39,23 @@ import (
 // maxParams defines the maximum number of parameters per route.
 const maxParams = 30
 
-// Some constants for BodyParser, QueryParser and ReqHeaderParser.
+// Some constants for BodyParser, QueryParser, ReqHeaderParser and SetFromStruct.
 const (
-	queryTag     = "query"
-	reqHeaderTag = "reqHeader"
-	bodyTag      = "form"
-	paramsTag    = "params"
+	queryTag      = "query"
+	reqHeaderTag  = "reqHeader"
+	bodyTag       = "form"
+	paramsTag     = "params"
+	uriTag        = "uri"
+	respHeaderTag = "respHeader"
+	cookieTag     = "cookie"
 )
 
 // userContextKey define the key name for storing context.Context in *fasthttp.RequestCtx
 const userContextKey = "__local_user_context__"
 
+// deferredKey define the key name for storing the functions registered by Ctx.Defer
+const deferredKey = "__local_deferred__"
+
 // Ctx represents the Context which hold the HTTP request and response.
 // It has methods for the request query string, parameters, body, HTTP headers and so on.
 type Ctx struct {
@@ -65,7 +75,13 @@ type Ctx struct {
 	values              [maxParams]string    // Route parameter values
 	fasthttp            *fasthttp.RequestCtx // Reference to *fasthttp.RequestCtx
 	matched             bool                 // Non use route matched
+	multipartChecked    bool                 // Multipart form has been validated against the MultipartConfig
+	multipartForm       *multipart.Form      // Multipart form parsed in streaming mode
 	viewBindMap         *dictpool.Dict       // Default view map to bind template engine
+	custom              interface{}          // Custom context created by Config.NewCtxFunc
+	requestContext      *requestContext      // Default user context, see UserContext
+	rand                *rand.Rand           // Random number generator of the request, see Rand
+	randSeeded          bool                 // rand has been seeded for the request
 }
 
 // TLSHandler object
@@ -133,11 +149,14 @@ func (app *App) AcquireCtx(fctx *fasthttp.RequestCtx) *Ctx {
 	c.indexHandler = 0
 	// Reset matched flag
 	c.matched = false
+	c.multipartChecked = false
+	c.multipartForm = nil
+	c.randSeeded = false
 	// Set paths
 	c.pathOriginal = app.getString(fctx.URI().PathOriginal())
 	// Set method
 	c.method = app.getString(fctx.Request.Header.Method())
-	c.methodINT = methodInt(c.method)
+	c.methodINT = app.methodInt(c.method)
 	// Attach *fasthttp.RequestCtx to ctx
 	c.fasthttp = fctx
 	// reset base uri
@@ -149,12 +168,20 @@ func (app *App) AcquireCtx(fctx *fasthttp.RequestCtx) *Ctx {
 
 // ReleaseCtx releases the ctx back into the pool.
 func (app *App) ReleaseCtx(c *Ctx) {
+	// Cancel the request context, before its values are gone
+	if c.requestContext != nil {
+		c.requestContext.finish()
+		c.requestContext = nil
+	}
 	// Reset values
 	c.route = nil
 	c.fasthttp = nil
 	if c.viewBindMap != nil {
 		dictpool.ReleaseDict(c.viewBindMap)
 	}
+	if r, ok := c.custom.(ctxResetter); ok {
+		r.Reset()
+	}
 	app.pool.Put(c)
 }
 
@@ -281,6 +308,8 @@ func (c *Ctx) BaseURL() string {
 // Body contains the raw body submitted in a POST request.
 // Returned value is only valid within the handler. Do not store any references.
 // Make copies or use the Immutable setting instead.
+// Bodies with a gzip, br or deflate Content-Encoding are decompressed without a
+// size limit, use the decompress middleware to limit it.
 func (c *Ctx) Body() []byte {
 	var err error
 	var encoding string
@@ -310,39 +339,129 @@ func (c *Ctx) Body() []byte {
 	return body
 }
 
-// decoderPool helps to improve BodyParser's, QueryParser's and ReqHeaderParser's performance
-var decoderPool = &sync.Pool{New: func() interface{} {
-	return decoderBuilder(ParserConfig{
-		IgnoreUnknownKeys: true,
-		ZeroEmpty:         true,
-	})
-}}
+// parserDecoders helps to improve BodyParser's, QueryParser's and ReqHeaderParser's performance
+var parserDecoders = newDecoderSet(ParserConfig{
+	IgnoreUnknownKeys: true,
+	ZeroEmpty:         true,
+})
 
-// SetParserDecoder allow globally change the option of form decoder, update decoderPool
+// SetParserDecoder allow globally change the option of form decoder, update parserDecoders
 func SetParserDecoder(parserConfig ParserConfig) {
-	decoderPool = &sync.Pool{New: func() interface{} {
-		return decoderBuilder(parserConfig)
-	}}
+	parserDecoders = newDecoderSet(parserConfig)
+}
+
+// decoderSet holds one decoder per alias tag. The decoders are safe for concurrent
+// use and cache the reflection metadata of the bound struct types, so it is
+// only computed on the first use of a type.
+type decoderSet struct {
+	config   ParserConfig
+	binder   BinderConfig
+	decoders sync.Map // alias tag => *schema.Decoder
+	binders  sync.Map // BinderConfig => *decoderSet
+}
+
+func newDecoderSet(parserConfig ParserConfig) *decoderSet {
+	return &decoderSet{config: parserConfig}
+}
+
+// get returns the decoder for the given alias tag
+func (s *decoderSet) get(aliasTag string) *schema.Decoder {
+	if decoder, ok := s.decoders.Load(aliasTag); ok {
+		return decoder.(*schema.Decoder)
+	}
+	decoder := decoderBuilder(s.config).(*schema.Decoder)
+	decoder.SetAliasTag(aliasTag)
+	if s.binder.Strict {
+		decoder.IgnoreUnknownKeys(false)
+	}
+	decoder.CaseSensitive(s.binder.CaseSensitive)
+	decoder.DefaultTimeFormat(s.binder.TimeFormat)
+	decoder.MaxDepth(binderLimit(s.binder.MaxDepth, DefaultBinderMaxDepth))
+	decoder.MaxSliceLength(binderLimit(s.binder.MaxSliceLength, DefaultBinderMaxSliceLength))
+	actual, _ := s.decoders.LoadOrStore(aliasTag, decoder)
+	return actual.(*schema.Decoder)
+}
+
+// binderLimit returns the limit of the decoders for a limit of BinderConfig,
+// which is the default one if it's 0 and disabled if it's negative
+func binderLimit(limit, defaultLimit int) int {
+	if limit == 0 {
+		return defaultLimit
+	}
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// decodersFor returns the decoders for the binder config, which are derived
+// from the global ones and rebuilt with them by SetParserDecoder
+func decodersFor(config BinderConfig) *decoderSet {
+	global := parserDecoders
+	if config == (BinderConfig{}) {
+		return global
+	}
+	if s, ok := global.binders.Load(config); ok {
+		return s.(*decoderSet)
+	}
+	s := newDecoderSet(global.config)
+	s.binder = config
+	actual, _ := global.binders.LoadOrStore(config, s)
+	return actual.(*decoderSet)
 }
 
+// decoders returns the decoders for the binder config of the app
+func (c *Ctx) decoders() *decoderSet {
+	return decodersFor(c.app.config.Binder)
+}
+
+var (
+	// durationType is bound by time.ParseDuration
+	durationType = reflect.TypeOf(time.Duration(0))
+	// urlType is bound by url.Parse
+	urlType = reflect.TypeOf(url.URL{})
+)
+
 func decoderBuilder(parserConfig ParserConfig) interface{} {
 	decoder := schema.NewDecoder()
 	decoder.IgnoreUnknownKeys(parserConfig.IgnoreUnknownKeys)
 	if parserConfig.SetAliasTag != "" {
 		decoder.SetAliasTag(parserConfig.SetAliasTag)
 	}
+	// Durations like "5s" or "2h", ByteSize, net.IP, netip.Addr and
+	// netip.Prefix are TextUnmarshalers
+	decoder.RegisterDecoder(durationType, func(s string) (reflect.Value, error) {
+		d, err := time.ParseDuration(utils.Trim(s, ' '))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(d), nil
+	})
+	decoder.RegisterDecoder(urlType, func(s string) (reflect.Value, error) {
+		u, err := url.Parse(utils.Trim(s, ' '))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(*u), nil
+	})
 	for _, v := range parserConfig.ParserType {
 		decoder.RegisterConverter(reflect.ValueOf(v.Customtype).Interface(), v.Converter)
 	}
+	registerCustomDecoders(decoder)
 	decoder.ZeroEmpty(parserConfig.ZeroEmpty)
 	return decoder
 }
 
 // BodyParser binds the request body to a struct.
 // It supports decoding the following content types based on the Content-Type header:
-// application/json, application/xml, application/x-www-form-urlencoded, multipart/form-data
+// application/json, application/xml, application/x-www-form-urlencoded, multipart/form-data,
+// application/msgpack, application/x-protobuf, application/cbor
 // If none of the content types above are matched, it will return a ErrUnprocessableEntity error
 func (c *Ctx) BodyParser(out interface{}) error {
+	return c.bodyParser(out, c.decoders())
+}
+
+func (c *Ctx) bodyParser(out interface{}, s *decoderSet) error {
 	// Get content-type
 	ctype := utils.ToLower(utils.UnsafeString(c.fasthttp.Request.Header.ContentType()))
 
@@ -368,7 +487,7 @@ func (c *Ctx) BodyParser(out interface{}) error {
 				k, err = parseParamSquareBrackets(k)
 			}
 
-			if strings.Contains(v, ",") && equalFieldType(out, reflect.Slice, k) {
+			if !s.binder.DisableSplitting && strings.Contains(v, ",") && equalFieldType(out, reflect.Slice, k) {
 				values := strings.Split(v, ",")
 				for i := 0; i < len(values); i++ {
 					data[k] = append(data[k], values[i])
@@ -379,18 +498,27 @@ func (c *Ctx) BodyParser(out interface{}) error {
 
 		})
 
-		return c.parseToStruct(bodyTag, out, data)
+		return c.parseToStruct(s, bodyTag, out, data)
 	}
 	if strings.HasPrefix(ctype, MIMEMultipartForm) {
-		data, err := c.fasthttp.MultipartForm()
+		data, err := c.MultipartForm()
 		if err != nil {
 			return err
 		}
-		return c.parseToStruct(bodyTag, out, data.Value)
+		return c.parseToStruct(s, bodyTag, out, data.Value)
 	}
 	if strings.HasPrefix(ctype, MIMETextXML) || strings.HasPrefix(ctype, MIMEApplicationXML) {
 		return xml.Unmarshal(c.Body(), out)
 	}
+	if strings.HasPrefix(ctype, MIMEApplicationMsgPack) || strings.HasPrefix(ctype, mimeApplicationXMsgPack) {
+		return c.app.config.MsgPackDecoder(c.Body(), out)
+	}
+	if strings.HasPrefix(ctype, MIMEApplicationProtobuf) || strings.HasPrefix(ctype, mimeApplicationProtobuf) {
+		return c.app.config.ProtobufDecoder(c.Body(), out)
+	}
+	if strings.HasPrefix(ctype, MIMEApplicationCBOR) {
+		return c.app.config.CBORDecoder(c.Body(), out)
+	}
 	// No suitable content type found
 	return ErrUnprocessableEntity
 }
@@ -416,11 +544,17 @@ func (c *Ctx) Context() *fasthttp.RequestCtx {
 }
 
 // UserContext returns a context implementation that was set by
-// user earlier or returns a non-nil, empty context,if it was not set earlier.
+// user earlier or returns the context of the request, if it was not set earlier.
+//
+// The context of the request is cancelled once the client disconnected, with
+// ErrClientDisconnected, see Done, once the handlers returned, with
+// ErrRequestFinished, or once the server shuts down. Its values are the Locals of
+// the request, e.g. ctx.Value("user") returns c.Locals("user"). Derive the
+// contexts of goroutines, which outlive the request, from context.Background.
 func (c *Ctx) UserContext() context.Context {
 	ctx, ok := c.fasthttp.UserValue(userContextKey).(context.Context)
 	if !ok {
-		ctx = context.Background()
+		ctx = c.requestCtx()
 		c.SetUserContext(ctx)
 	}
 
@@ -429,7 +563,7 @@ func (c *Ctx) UserContext() context.Context {
 
 // SetUserContext sets a context implementation by user.
 func (c *Ctx) SetUserContext(ctx context.Context) {
-	c.fasthttp.SetUserValue(userContextKey, ctx)
+	c.setLocal(userContextKey, ctx)
 }
 
 // Cookie sets a cookie by passing a cookie struct.
@@ -473,6 +607,40 @@ func (c *Ctx) Cookies(key string, defaultValue ...string) string {
 	return defaultString(c.app.getString(c.fasthttp.Request.Header.Cookie(key)), defaultValue)
 }
 
+// deferred are the functions registered by Ctx.Defer, which fasthttp calls
+// by closing the user values once the request is done
+type deferred []func()
+
+// Close runs the functions in last-in-first-out order
+func (d *deferred) Close() error {
+	for i := len(*d) - 1; i >= 0; i-- {
+		(*d)[i]()
+	}
+	*d = nil
+	return nil
+}
+
+// Defer registers fn to run once the request is done, i.e. after the response
+// has been sent, to clean up per-request resources:
+//
+//	f, err := os.Open(path)
+//	if err != nil {
+//		return err
+//	}
+//	c.Defer(func() { _ = f.Close() })
+//
+// The functions run in last-in-first-out order, also if a handler returned
+// an error or a recover middleware recovered a panic. They run after the
+// handlers of UseAfter, Locals implementing io.Closer are closed likewise.
+func (c *Ctx) Defer(fn func()) {
+	d, ok := c.fasthttp.UserValue(deferredKey).(*deferred)
+	if !ok {
+		d = &deferred{}
+		c.setLocal(deferredKey, d)
+	}
+	*d = append(*d, fn)
+}
+
 // Download transfers the file from path as an attachment.
 // Typically, browsers will prompt the user for download.
 // By default, the Content-Disposition header filename= parameter is the filepath (this typically appears in the browser dialog).
@@ -507,7 +675,7 @@ func (c *Ctx) Response() *fasthttp.Response {
 // If the header is not specified or there is no proper format, text/plain is used.
 func (c *Ctx) Format(body interface{}) error {
 	// Get accepted content type
-	accept := c.Accepts("html", "json", "txt", "xml")
+	accept := c.Accepts("html", "json", "txt", "xml", "msgpack")
 	// Set accepted content type
 	c.Type(accept)
 	// Type convert provided body
@@ -531,12 +699,24 @@ func (c *Ctx) Format(body interface{}) error {
 		return c.SendString(b)
 	case "xml":
 		return c.XML(body)
+	case "msgpack":
+		return c.MsgPack(body)
 	}
 	return c.SendString(b)
 }
 
 // FormFile returns the first file by key from a MultipartForm.
 func (c *Ctx) FormFile(key string) (*multipart.FileHeader, error) {
+	if c.app.config.Multipart.enabled() {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return nil, err
+		}
+		if fhh := form.File[key]; len(fhh) > 0 {
+			return fhh[0], nil
+		}
+		return nil, fasthttp.ErrMissingFile
+	}
 	return c.fasthttp.FormFile(key)
 }
 
@@ -771,10 +951,21 @@ func (c *Ctx) Is(extension string) bool {
 // and a nil slice encodes as the null JSON value.
 // This method also sets the content header to application/json.
 func (c *Ctx) JSON(data interface{}) error {
+	// With ETagJSON, unchanged values aren't sent or even encoded
+	etagJSON := c.app.etagMode(c) == ETagJSON
+	if tagger, ok := data.(ETagger); ok && etagJSON {
+		if setJSONETag(c, tagger.ETag()) {
+			return nil
+		}
+		etagJSON = false
+	}
 	raw, err := c.app.config.JSONEncoder(data)
 	if err != nil {
 		return err
 	}
+	if etagJSON && setJSONETag(c, jsonETag(raw)) {
+		return nil
+	}
 	c.fasthttp.Response.SetBodyRaw(raw)
 	c.fasthttp.Response.Header.SetContentType(MIMEApplicationJSON)
 	return nil
@@ -816,6 +1007,18 @@ func (c *Ctx) XML(data interface{}) error {
 	return nil
 }
 
+// CBOR encodes the data as CBOR with the CBOREncoder of the config.
+// This method also sets the content header to application/cbor.
+func (c *Ctx) CBOR(data interface{}) error {
+	raw, err := c.app.config.CBOREncoder(data)
+	if err != nil {
+		return err
+	}
+	c.fasthttp.Response.SetBodyRaw(raw)
+	c.fasthttp.Response.Header.SetContentType(MIMEApplicationCBOR)
+	return nil
+}
+
 // Links joins the links followed by the property to populate the response's Link HTTP header field.
 func (c *Ctx) Links(link ...string) {
 	if len(link) == 0 {
@@ -837,14 +1040,25 @@ func (c *Ctx) Links(link ...string) {
 
 // Locals makes it possible to pass interface{} values under string keys scoped to the request
 // and therefore available to all following routes that match the request.
+// Values implementing io.Closer are closed in the order they were set once the request is done,
+// i.e. after the response has been sent, see Defer.
 func (c *Ctx) Locals(key string, value ...interface{}) (val interface{}) {
 	if len(value) == 0 {
 		return c.fasthttp.UserValue(key)
 	}
-	c.fasthttp.SetUserValue(key, value[0])
+	c.setLocal(key, value[0])
 	return value[0]
 }
 
+// setLocal sets a local, the request context reads them concurrently, see requestContext.Value
+func (c *Ctx) setLocal(key string, value interface{}) {
+	if r := c.requestContext; r != nil {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+	}
+	c.fasthttp.SetUserValue(key, value)
+}
+
 // Location sets the response Location HTTP header to the specified path parameter.
 func (c *Ctx) Location(path string) {
 	c.setCanonical(HeaderLocation, path)
@@ -854,7 +1068,7 @@ func (c *Ctx) Location(path string) {
 func (c *Ctx) Method(override ...string) string {
 	if len(override) > 0 {
 		method := utils.ToUpper(override[0])
-		mINT := methodInt(method)
+		mINT := c.app.methodInt(method)
 		if mINT == -1 {
 			return c.method
 		}
@@ -866,7 +1080,16 @@ func (c *Ctx) Method(override ...string) string {
 
 // MultipartForm parse form entries from binary.
 // This returns a map[string][]string, so given a key the value will be a string slice.
+// The limits of Config.Multipart are enforced before the form is parsed.
 func (c *Ctx) MultipartForm() (*multipart.Form, error) {
+	if c.app.config.Multipart.FileWriter != nil {
+		return c.streamMultipartForm()
+	}
+	if c.app.config.Multipart.enabled() {
+		if err := c.checkMultipartForm(); err != nil {
+			return nil, err
+		}
+	}
 	return c.fasthttp.MultipartForm()
 }
 
@@ -880,6 +1103,17 @@ func (c *Ctx) ClientHelloInfo() *tls.ClientHelloInfo {
 }
 
 // Next executes the next method in the stack that matches the current route.
+//
+// It returns the error of the rest of the chain, so that every middleware
+// before it sees the error after Next returns and may handle it, e.g. by
+// returning nil, or replace it. A middleware returning without calling Next
+// short-circuits the chain. The ErrorHandler is called with the error returned
+// by the first handler, after all handlers returned, i.e. the response isn't
+// written yet when a middleware sees the error. Call app.ErrorHandler to
+// write it right away.
+//
+// The code after Next doesn't run if the chain panics. Use Around for
+// middleware which always have to run a deferred phase.
 func (c *Ctx) Next() (err error) {
 	// Increment handler index
 	c.indexHandler++
@@ -902,6 +1136,40 @@ func (c *Ctx) RestartRouting() error {
 	return err
 }
 
+// Fork returns a context for a copy of the request, e.g. to refresh a response
+// in the background after it has been sent. It's positioned at the current
+// handler with the same route params and Locals, so Next executes the handlers
+// after it like for the request, the handlers before it aren't executed again.
+// Locals implementing io.Closer, e.g. files or transactions, aren't copied,
+// since they're closed when the request is done.
+// The fork isn't bound to the connection and must be released with
+// App.ReleaseCtx, when it's no longer used.
+func (c *Ctx) Fork() *Ctx {
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Init(&c.fasthttp.Request, c.fasthttp.RemoteAddr(), nil)
+	c.fasthttp.VisitUserValues(func(key []byte, value interface{}) {
+		// The user context and the deferred functions belong to the request
+		if k := string(key); k == userContextKey || k == deferredKey {
+			return
+		}
+		if _, ok := value.(io.Closer); ok {
+			return
+		}
+		fctx.SetUserValue(string(key), value)
+	})
+
+	fork := c.app.AcquireCtx(fctx)
+	fork.route = c.route
+	fork.indexRoute = c.indexRoute
+	fork.indexHandler = c.indexHandler
+	fork.matched = c.matched
+	// The params must point to the copied request
+	if fork.route != nil {
+		fork.route.match(fork.detectionPath, fork.path, &fork.values)
+	}
+	return fork
+}
+
 // OriginalURL contains the original request URL.
 // Returned value is only valid within the handler. Do not store any references.
 // Make copies or use the Immutable setting to use the value outside the Handler.
@@ -945,12 +1213,71 @@ func (c *Ctx) AllParams() map[string]string {
 }
 
 // ParamsParser binds the param string to a struct.
+// It's the binder for the URI of the request, only the route params are
+// considered and neither the query string, the headers nor the body are read.
+// Use it instead of BindTo if a handler only needs the route params, BindTo
+// binds all sources and the params have the lowest precedence there.
+//
+// Wildcard params bound to slices are split into their path segments, e.g.
+// `params:"*1"` binds "a/b/c" as []string{"a", "b", "c"}. The tags "*" and "+"
+// collect the segments of all wildcards of the route:
+//
+//	// app.Get("/sum/+/+", ...) with /sum/1/2/3/4
+//	var req struct {
+//		Numbers []int `params:"+"` // [1 2 3 4]
+//	}
+//
+// Other fields tagged with "*" or "+" get the value of the first wildcard,
+// like Params. Missing optional params, e.g. `:id?`, leave their fields
+// untouched, so pointer fields stay nil.
 func (c *Ctx) ParamsParser(out interface{}) error {
+	return c.paramsParser(out, c.decoders(), paramsTag)
+}
+
+// URIParser binds the route params to the fields tagged with "uri", like
+// ParamsParser binds the ones tagged with "params". Neither the query string,
+// the headers nor the body are read:
+//
+//	// app.Get("/users/:id/posts/:slug", ...)
+//	var req struct {
+//		ID   int    `uri:"id"`
+//		Slug string `uri:"slug"`
+//	}
+//	if err := c.URIParser(&req); err != nil {
+//		return err
+//	}
+func (c *Ctx) URIParser(out interface{}) error {
+	return c.paramsParser(out, c.decoders(), uriTag)
+}
+
+// paramsParser binds the route params to the fields with the alias tag,
+// "params" for ParamsParser and "uri" for URIParser
+func (c *Ctx) paramsParser(out interface{}, s *decoderSet, aliasTag string) error {
+	wildcards := wildcardSlicesOf(reflect.TypeOf(out), aliasTag)
 	params := make(map[string][]string, len(c.route.Params))
-	for _, param := range c.route.Params {
-		params[param] = append(params[param], c.Params(param))
+	for i, param := range c.route.Params {
+		if i >= len(c.values) {
+			break
+		}
+		value := c.values[i]
+		if value == "" {
+			continue
+		}
+		if (param == "*1" || param == "+1") && !wildcards[param[:1]] {
+			params[param[:1]] = []string{value}
+		}
+		if len(wildcards) > 0 && (param[0] == '*' || param[0] == '+') {
+			if kind := param[:1]; wildcards[kind] {
+				params[kind] = appendSegments(params[kind], value)
+			}
+			if wildcards[param] {
+				params[param] = appendSegments(params[param], value)
+				continue
+			}
+		}
+		params[param] = append(params[param], value)
 	}
-	return c.parseToStruct(paramsTag, out, params)
+	return c.parseToStruct(s, aliasTag, out, params)
 }
 
 // ParamsInt is used to get an integer from the route parameters
@@ -987,6 +1314,20 @@ func (c *Ctx) Path(override ...string) string {
 	return c.path
 }
 
+// MountPath returns the path along which the app of the matched route is
+// mounted, see App.Mount. It's empty for the routes of the app itself.
+//
+//	admin.Get("/users", func(c *fiber.Ctx) error {
+//	     c.Path()      // "/admin/users"
+//	     c.MountPath() // "/admin"
+//	})
+func (c *Ctx) MountPath() string {
+	if c.route == nil || c.route.mount == nil {
+		return ""
+	}
+	return c.route.mount.MountPath()
+}
+
 // Protocol contains the request protocol string: http or https for TLS requests.
 // Use Config.EnableTrustedProxyCheck to prevent header spoofing, in case when your app is behind the proxy.
 func (c *Ctx) Protocol() string {
@@ -1025,7 +1366,19 @@ func (c *Ctx) Query(key string, defaultValue ...string) string {
 }
 
 // QueryParser binds the query string to a struct.
+// Like in the other parsers, the keys are matched case-insensitively and a field
+// may declare several aliases separated by "|", e.g. `query:"userId|user_id"`.
+// time.Time fields may declare their layout with the time_format tag, e.g.
+// `time_format:"2006-01-02"` or `time_format:"unix"`, and their location
+// with `time_utc:"1"` or `time_location:"Europe/Berlin"`. time.Duration fields
+// accept values like "5s" or "2h", ByteSize fields values like "10MB".
+// net.IP, netip.Addr, netip.Prefix and url.URL fields are parsed as well, invalid
+// addresses and URLs are reported as BindError.
 func (c *Ctx) QueryParser(out interface{}) error {
+	return c.queryParser(out, c.decoders())
+}
+
+func (c *Ctx) queryParser(out interface{}, s *decoderSet) error {
 	data := make(map[string][]string)
 	var err error
 
@@ -1041,7 +1394,7 @@ func (c *Ctx) QueryParser(out interface{}) error {
 			k, err = parseParamSquareBrackets(k)
 		}
 
-		if strings.Contains(v, ",") && equalFieldType(out, reflect.Slice, k) {
+		if !s.binder.DisableSplitting && strings.Contains(v, ",") && equalFieldType(out, reflect.Slice, k) {
 			values := strings.Split(v, ",")
 			for i := 0; i < len(values); i++ {
 				data[k] = append(data[k], values[i])
@@ -1056,7 +1409,7 @@ func (c *Ctx) QueryParser(out interface{}) error {
 		return err
 	}
 
-	return c.parseToStruct(queryTag, out, data)
+	return c.parseToStruct(s, queryTag, out, data)
 }
 
 func parseParamSquareBrackets(k string) (string, error) {
@@ -1087,12 +1440,16 @@ func parseParamSquareBrackets(k string) (string, error) {
 
 // ReqHeaderParser binds the request header strings to a struct.
 func (c *Ctx) ReqHeaderParser(out interface{}) error {
+	return c.reqHeaderParser(out, c.decoders())
+}
+
+func (c *Ctx) reqHeaderParser(out interface{}, s *decoderSet) error {
 	data := make(map[string][]string)
 	c.fasthttp.Request.Header.VisitAll(func(key, val []byte) {
 		k := utils.UnsafeString(key)
 		v := utils.UnsafeString(val)
 
-		if strings.Contains(v, ",") && equalFieldType(out, reflect.Slice, k) {
+		if !s.binder.DisableSplitting && strings.Contains(v, ",") && equalFieldType(out, reflect.Slice, k) {
 			values := strings.Split(v, ",")
 			for i := 0; i < len(values); i++ {
 				data[k] = append(data[k], values[i])
@@ -1103,18 +1460,17 @@ func (c *Ctx) ReqHeaderParser(out interface{}) error {
 
 	})
 
-	return c.parseToStruct(reqHeaderTag, out, data)
+	return c.parseToStruct(s, reqHeaderTag, out, data)
 }
 
-func (c *Ctx) parseToStruct(aliasTag string, out interface{}, data map[string][]string) error {
-	// Get decoder from pool
-	schemaDecoder := decoderPool.Get().(*schema.Decoder)
-	defer decoderPool.Put(schemaDecoder)
-
-	// Set alias tag
-	schemaDecoder.SetAliasTag(aliasTag)
-
-	return schemaDecoder.Decode(out, data)
+func (c *Ctx) parseToStruct(s *decoderSet, aliasTag string, out interface{}, data map[string][]string) error {
+	// Get the cached decoder of the alias tag
+	err := s.get(aliasTag).Decode(out, data)
+	var multi MultiError
+	if errors.As(err, &multi) {
+		return newBindError(aliasTag, multi, data)
+	}
+	return err
 }
 
 func equalFieldType(out interface{}, kind reflect.Kind, key string) bool {
@@ -1150,9 +1506,11 @@ func equalFieldType(out interface{}, kind reflect.Kind, key string) bool {
 		} else {
 			inputFieldName = strings.Split(inputFieldName, ",")[0]
 		}
-		// Compare field/tag with provided key
-		if utils.ToLower(inputFieldName) == key {
-			return true
+		// Compare field/tag aliases with provided key
+		for _, alias := range strings.Split(inputFieldName, "|") {
+			if utils.ToLower(alias) == key {
+				return true
+			}
 		}
 	}
 	return false
@@ -1273,6 +1631,14 @@ func (c *Ctx) GetRouteURL(routeName string, params Map) (string, error) {
 	return c.getLocationFromRoute(c.App().GetRoute(routeName), params)
 }
 
+// RouteURL generates the URL of the named route with the params, unused params
+// are appended as query string, see App.RouteURL.
+//
+//	c.RouteURL("user.show", fiber.Map{"id": 5}) // "/users/5"
+func (c *Ctx) RouteURL(routeName string, params Map) (string, error) {
+	return c.app.RouteURL(routeName, params)
+}
+
 // RedirectToRoute to the Route registered in the app with appropriate parameters
 // If status is not specified, status defaults to 302 Found.
 // If you want to send queries to route, you must add "queries" key typed as map[string]string to params.
@@ -1320,6 +1686,10 @@ func (c *Ctx) Render(name string, bind interface{}, layouts ...string) error {
 	buf := bytebufferpool.Get()
 	defer bytebufferpool.Put(buf)
 
+	// Bound variables and locals are passed without data too
+	if bind == nil && (c.viewBindMap != nil || c.app.config.PassLocalsToViews) {
+		bind = Map{}
+	}
 	// Pass-locals-to-views & bind
 	c.renderExtensions(bind)
 
@@ -1392,7 +1762,50 @@ func (c *Ctx) renderExtensions(bind interface{}) {
 	}
 }
 
-// Route returns the matched Route struct.
+// MatchedRoute returns the route which handles the request, i.e. the first
+// matching route which isn't a middleware registered with Use.
+// Unlike Route, it can be used in middleware before the request reaches the route.
+// Returns nil if no route matches.
+func (c *Ctx) MatchedRoute() *Route {
+	var values [maxParams]string
+	return c.matchEndpoint(&values)
+}
+
+// matchEndpoint returns the first matching route which isn't a middleware
+// and writes its param values to values
+func (c *Ctx) matchEndpoint(values *[maxParams]string) *Route {
+	tree, ok := c.app.treeStack[c.methodINT][c.treePath]
+	if !ok {
+		tree = c.app.treeStack[c.methodINT][""]
+	}
+	for _, route := range tree {
+		if !route.use && route.match(c.detectionPath, c.path, values) {
+			return route
+		}
+	}
+	return nil
+}
+
+// settleRoute points the Ctx to the matched endpoint with its params, once
+// the handlers returned, e.g. after a middleware returned an error before
+// calling Next, so that the error handler and the after handlers see the
+// endpoint. Without a matching endpoint the current route is kept.
+func (c *Ctx) settleRoute() {
+	if c.route == nil || !c.route.use {
+		return
+	}
+	var values [maxParams]string
+	if route := c.matchEndpoint(&values); route != nil {
+		c.route, c.values = route, values
+	}
+}
+
+// Route returns the matched Route struct with the registered path, name,
+// param names and metadata. Within handlers it's the route of the running
+// handler, e.g. the middleware registered by Use, see MatchedRoute for the
+// endpoint. Once the handlers returned, i.e. in the error handler and the
+// after handlers of UseAfter, it's the matched endpoint, so that metrics and
+// traces can be labeled by the route pattern.
 func (c *Ctx) Route() *Route {
 	if c.route == nil {
 		// Fallback for fasthttp error handler
@@ -1407,6 +1820,38 @@ func (c *Ctx) Route() *Route {
 	return c.route
 }
 
+// RouteMeta returns the metadata of the key of the route, which handles the
+// request, see App.Meta. Unlike Route, it's the matched endpoint even in
+// middleware registered by Use, so that middleware can adapt to the routes
+// declaratively instead of keeping lists of paths:
+//
+//	app.Use(func(c *fiber.Ctx) error {
+//		if c.RouteMeta("auth") == "none" {
+//			return c.Next()
+//		}
+//		return authenticate(c)
+//	})
+//
+//	app.Get("/health", handler)
+//	app.Meta("auth", "none")
+//
+// The metadata of the current route is used if the endpoint has no value of
+// the key, nil is returned if neither has.
+func (c *Ctx) RouteMeta(key string) interface{} {
+	route := c.route
+	if route == nil || route.use {
+		if matched := c.MatchedRoute(); matched != nil {
+			if value, ok := matched.Metadata[key]; ok {
+				return value
+			}
+		}
+	}
+	if route != nil {
+		return route.Metadata[key]
+	}
+	return nil
+}
+
 // SaveFile saves any multipart file to disk.
 func (c *Ctx) SaveFile(fileheader *multipart.FileHeader, path string) error {
 	return fasthttp.SaveMultipartFile(fileheader, path)
@@ -1449,6 +1894,7 @@ var (
 // SendFile transfers the file from the given path.
 // The file is not compressed by default, enable this by passing a 'true' argument
 // Sets the Content-Type response HTTP header field based on the filenames extension.
+// Range requests are served including If-Range and multiple ranges, which are sent as multipart/byteranges.
 func (c *Ctx) SendFile(file string, compress ...bool) error {
 	// Save the filename, we will need it in the error message if the file isn't found
 	filename := file
@@ -1504,7 +1950,7 @@ func (c *Ctx) SendFile(file string, compress ...bool) error {
 	// Save status code
 	status := c.fasthttp.Response.StatusCode()
 	// Serve file
-	sendFileHandler(c.fasthttp)
+	serveFileRanges(c.fasthttp, sendFileHandler)
 	// Get the status code which is set by fasthttp
 	fsStatus := c.fasthttp.Response.StatusCode()
 	// Set the status code set by the user if it is different from the fasthttp status code and 200
@@ -1550,6 +1996,61 @@ func (c *Ctx) SendStream(stream io.Reader, size ...int) error {
 	return nil
 }
 
+// SendStreamWriter sets the response body to the output of streamWriter, which
+// is sent with chunked transfer encoding. streamWriter is called once the
+// headers are sent and must not use the Ctx, every Flush sends the buffered
+// output to the client:
+//
+//	return c.SendStreamWriter(func(w *bufio.Writer) error {
+//		for row := range rows {
+//			if _, err := fmt.Fprintln(w, row); err != nil {
+//				return err // the client disconnected
+//			}
+//			if err := w.Flush(); err != nil {
+//				return err
+//			}
+//		}
+//		return nil
+//	})
+//
+// Writes fail once the client disconnected. If streamWriter returns an error,
+// the response is aborted, so that the client doesn't mistake the partial
+// body for the complete one.
+func (c *Ctx) SendStreamWriter(streamWriter func(w *bufio.Writer) error) error {
+	pr, pw := io.Pipe()
+	c.fasthttp.Response.SetBodyStream(&streamWriterReader{
+		pr: pr,
+		run: func() {
+			w := bufio.NewWriter(pw)
+			err := streamWriter(w)
+			if err == nil {
+				err = w.Flush()
+			}
+			_ = pw.CloseWithError(err)
+		},
+	}, -1)
+	return nil
+}
+
+// streamWriterReader runs the stream writer of SendStreamWriter on the first
+// read, closing it stops the stream writer by failing its writes
+type streamWriterReader struct {
+	pr    *io.PipeReader
+	run   func()
+	start sync.Once
+}
+
+func (s *streamWriterReader) Read(p []byte) (int, error) {
+	s.start.Do(func() {
+		go s.run()
+	})
+	return s.pr.Read(p)
+}
+
+func (s *streamWriterReader) Close() error {
+	return s.pr.Close()
+}
+
 // Set sets the response's HTTP header field to the specified key, value.
 func (c *Ctx) Set(key string, val string) {
 	c.fasthttp.Response.Header.Set(key, val)
@@ -1602,6 +2103,32 @@ func (c *Ctx) String() string {
 	)
 }
 
+// TempFile creates a temporary file in the default directory for temporary
+// files, see ioutil.TempFile. The file is closed and removed once the request
+// is done, see Defer, so it can be used to stage uploads or downloads:
+//
+//	f, err := c.TempFile("report-*.csv")
+//	if err != nil {
+//		return err
+//	}
+//	if err = writeReport(f); err != nil {
+//		return err
+//	}
+//	return c.SendFile(f.Name())
+//
+// Files of multipart forms spilled to disk are removed by fasthttp likewise.
+func (c *Ctx) TempFile(pattern string) (*os.File, error) {
+	f, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return nil, err
+	}
+	c.Defer(func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	})
+	return f, nil
+}
+
 // Type sets the Content-Type HTTP header to the MIME type specified by the file extension.
 func (c *Ctx) Type(extension string, charset ...string) *Ctx {
 	if len(charset) > 0 {
@@ -19,6 +19,7 @@ import (
 	"io"
 	"io/ioutil"
 	"mime/multipart"
+	"net"
 	"net/http/httptest"
 	"net/url"
 	"os"
@@ -31,10 +32,11 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2/internal/bytebufferpool"
-	"github.com/gofiber/fiber/v2/internal/storage/memory"
 	"github.com/gofiber/fiber/v2/internal/template/html"
+	"github.com/gofiber/fiber/v2/storage/memory"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
 )
 
 // go test -run Test_Ctx_Accepts
@@ -451,6 +453,171 @@ func Test_Ctx_ParamParser(t *testing.T) {
 
 }
 
+// go test -run Test_Ctx_ParamsParser_URIOnly
+func Test_Ctx_ParamsParser_URIOnly(t *testing.T) {
+	t.Parallel()
+	app := New()
+	type Demo struct {
+		ID   int    `params:"id" query:"id"`
+		Name string `query:"name"`
+	}
+	app.Get("/users/:id", func(c *Ctx) error {
+		d := new(Demo)
+		if err := c.ParamsParser(d); err != nil {
+			return err
+		}
+		return c.SendString(strconv.Itoa(d.ID) + ":" + d.Name)
+	})
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/users/42?id=1&name=john", nil))
+	utils.AssertEqual(t, nil, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "42:", string(body))
+}
+
+// go test -run Test_Ctx_URIParser
+func Test_Ctx_URIParser(t *testing.T) {
+	t.Parallel()
+	app := New()
+	type Demo struct {
+		ID    int    `uri:"id" query:"id"`
+		Page  uint8  `uri:"page"`
+		Slug  string `params:"page"`
+		Title string `query:"title"`
+	}
+	app.Get("/users/:id/:page", func(c *Ctx) error {
+		d := new(Demo)
+		if err := c.URIParser(d); err != nil {
+			var bindErr *BindError
+			utils.AssertEqual(t, true, errors.As(err, &bindErr))
+			return c.Status(StatusBadRequest).SendString(bindErr.Fields[0].Source + ":" + bindErr.Fields[0].Field)
+		}
+		return c.SendString(strconv.Itoa(d.ID) + ":" + strconv.Itoa(int(d.Page)) + ":" + d.Slug + ":" + d.Title)
+	})
+
+	// Only the fields tagged with uri are bound
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/users/42/3?id=1&title=john", nil))
+	utils.AssertEqual(t, nil, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "42:3::", string(body))
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/users/42/300", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusBadRequest, resp.StatusCode)
+	body, err = ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "uri:page", string(body))
+}
+
+// go test -run Test_Ctx_ParamsParser_Wildcards
+func Test_Ctx_ParamsParser_Wildcards(t *testing.T) {
+	t.Parallel()
+	app := New()
+	type Demo struct {
+		Path     string   `params:"*1"`
+		Segments []string `params:"*"`
+		Numbers  []int    `params:"+"`
+		Last     []int    `params:"+2"`
+	}
+	app.Get("/files/*/sum/+/+", func(c *Ctx) error {
+		d := new(Demo)
+		if err := c.ParamsParser(d); err != nil {
+			return err
+		}
+		return c.JSON(d)
+	})
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/files/a/b/c/sum/1/2/3/4", nil))
+	utils.AssertEqual(t, nil, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `{"Path":"a/b/c","Segments":["a","b","c"],"Numbers":[1,2,3,4],"Last":[4]}`, string(body))
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/files/a/sum/1/x", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusInternalServerError, resp.StatusCode)
+}
+
+// go test -run Test_Ctx_ParamsParser_Optional
+func Test_Ctx_ParamsParser_Optional(t *testing.T) {
+	t.Parallel()
+	app := New()
+	type Demo struct {
+		ID   *int   `params:"id"`
+		Path string `params:"*"`
+		Rest string `params:"+"`
+	}
+	handler := func(c *Ctx) error {
+		d := new(Demo)
+		if err := c.ParamsParser(d); err != nil {
+			return err
+		}
+		return c.JSON(d)
+	}
+	// Routes match in the order of registration, specific routes come first
+	app.Get("/users/me", func(c *Ctx) error {
+		return c.SendString("me")
+	})
+	app.Get("/users/:id<int>?", handler)
+	app.Get("/files/*", handler)
+	app.Get("/plus/+", handler)
+
+	test := func(url string, status int, expected string) {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, url, nil))
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, status, resp.StatusCode, url)
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		if status == StatusOK {
+			utils.AssertEqual(t, expected, string(body), url)
+		}
+	}
+	test("/users/me", StatusOK, "me")
+	test("/users", StatusOK, `{"ID":null,"Path":"","Rest":""}`)
+	test("/users/42", StatusOK, `{"ID":42,"Path":"","Rest":""}`)
+	test("/users/john", StatusNotFound, "")
+	test("/files", StatusOK, `{"ID":null,"Path":"","Rest":""}`)
+	test("/files/a/b.txt", StatusOK, `{"ID":null,"Path":"a/b.txt","Rest":""}`)
+	test("/plus/a/b", StatusOK, `{"ID":null,"Path":"","Rest":"a/b"}`)
+	test("/plus", StatusNotFound, "")
+}
+
+// go test -run Test_Ctx_QueryParser_Aliases
+func Test_Ctx_QueryParser_Aliases(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	type Query struct {
+		UserID int      `query:"userId|user_id,required"`
+		Tags   []string `query:"tags|tag"`
+	}
+
+	q := new(Query)
+	c.Request().URI().SetQueryString("user_id=1&tag=a,b")
+	utils.AssertEqual(t, nil, c.QueryParser(q))
+	utils.AssertEqual(t, 1, q.UserID)
+	utils.AssertEqual(t, []string{"a", "b"}, q.Tags)
+
+	q = new(Query)
+	c.Request().URI().SetQueryString("USERID=2&tags=c")
+	utils.AssertEqual(t, nil, c.QueryParser(q))
+	utils.AssertEqual(t, 2, q.UserID)
+	utils.AssertEqual(t, []string{"c"}, q.Tags)
+
+	q = new(Query)
+	c.Request().URI().SetQueryString("tags=c")
+	utils.AssertEqual(t, "userId is empty", c.QueryParser(q).Error())
+
+	type Header struct {
+		RequestID string `reqHeader:"X-Request-Id|X-Correlation-Id"`
+	}
+	h := new(Header)
+	c.Request().Header.Set("x-correlation-id", "abc")
+	utils.AssertEqual(t, nil, c.ReqHeaderParser(h))
+	utils.AssertEqual(t, "abc", h.RequestID)
+}
+
 // go test -run Test_Ctx_BodyParser_WithSetParserDecoder
 func Test_Ctx_BodyParser_WithSetParserDecoder(t *testing.T) {
 	type CustomTime time.Time
@@ -618,7 +785,9 @@ func Test_Ctx_UserContext(t *testing.T) {
 
 	t.Run("Nil_Context", func(t *testing.T) {
 		ctx := c.UserContext()
-		utils.AssertEqual(t, ctx, context.Background())
+		utils.AssertEqual(t, true, ctx != nil)
+		utils.AssertEqual(t, nil, ctx.Err())
+		utils.AssertEqual(t, ctx, c.UserContext())
 	})
 	t.Run("ValueContext", func(t *testing.T) {
 		testKey := "Test Key"
@@ -628,6 +797,103 @@ func Test_Ctx_UserContext(t *testing.T) {
 	})
 }
 
+// go test -run Test_Ctx_UserContext_Request
+func Test_Ctx_UserContext_Request(t *testing.T) {
+	t.Parallel()
+	app := New()
+	var ctx context.Context
+	app.Get("/", func(c *Ctx) error {
+		ctx = c.UserContext()
+		c.Locals("user", "john")
+		utils.AssertEqual(t, "john", ctx.Value("user"))
+		utils.AssertEqual(t, nil, ctx.Value(struct{}{}))
+		utils.AssertEqual(t, nil, ctx.Err())
+		_, ok := ctx.Deadline()
+		utils.AssertEqual(t, false, ok)
+
+		// Derived contexts observe the cancellation
+		child, cancel := context.WithCancel(ctx)
+		go func() {
+			defer cancel()
+			<-child.Done()
+		}()
+		return nil
+	})
+
+	_, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+
+	// The context is cancelled once the request finished
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context has not been cancelled")
+	}
+	utils.AssertEqual(t, ErrRequestFinished, ctx.Err())
+	utils.AssertEqual(t, nil, ctx.Value("user"))
+}
+
+// go test -race -run Test_Ctx_UserContext_Locals
+func Test_Ctx_UserContext_Locals(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c *Ctx) error {
+		ctx := c.UserContext()
+		reading, stop, done := make(chan struct{}), make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = ctx.Value("key")
+			close(reading)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					_ = ctx.Value("key")
+				}
+			}
+		}()
+		<-reading
+		// The locals are written while another goroutine reads them
+		for i := 0; i < 1000; i++ {
+			c.Locals("key", i)
+			c.Locals(strconv.Itoa(i), i)
+		}
+		close(stop)
+		<-done
+		utils.AssertEqual(t, 999, ctx.Value("key"))
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_Ctx_UserContext_Shutdown
+func Test_Ctx_UserContext_Shutdown(t *testing.T) {
+	t.Parallel()
+	app := New()
+	started, cancelled := make(chan struct{}), make(chan error, 1)
+	app.Get("/", func(c *Ctx) error {
+		close(started)
+		select {
+		case <-c.UserContext().Done():
+			cancelled <- c.UserContext().Err()
+		case <-time.After(time.Second):
+			cancelled <- nil
+		}
+		return nil
+	})
+
+	go func() {
+		_, _ = app.Test(httptest.NewRequest(MethodGet, "/", nil), -1)
+	}()
+	<-started
+	utils.AssertEqual(t, nil, app.Shutdown())
+	utils.AssertEqual(t, context.Canceled, <-cancelled)
+}
+
 // go test -run Test_Ctx_SetUserContext
 func Test_Ctx_SetUserContext(t *testing.T) {
 	app := New()
@@ -748,6 +1014,46 @@ func Test_Ctx_Cookies(t *testing.T) {
 	utils.AssertEqual(t, "default", c.Cookies("unknown", "default"))
 }
 
+// go test -run Test_Ctx_Defer
+func Test_Ctx_Defer(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var calls []string
+	app.Use(func(c *Ctx) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = ErrInternalServerError
+			}
+		}()
+		return c.Next()
+	})
+	app.Get("/:action", func(c *Ctx) error {
+		c.Defer(func() { calls = append(calls, "first") })
+		c.Locals("resource", closerFunc(func() error {
+			calls = append(calls, "closed")
+			return nil
+		}))
+		c.Defer(func() { calls = append(calls, "second") })
+
+		switch c.Params("action") {
+		case "error":
+			return ErrBadRequest
+		case "panic":
+			panic("boom")
+		}
+		return c.SendStatus(StatusOK)
+	})
+
+	for action, status := range map[string]int{"ok": StatusOK, "error": StatusBadRequest, "panic": StatusInternalServerError} {
+		calls = nil
+		resp, err := app.Dispatch(MethodGet, "/"+action, nil, nil)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, status, resp.StatusCode(), action)
+		utils.AssertEqual(t, []string{"second", "first", "closed"}, calls, action)
+	}
+}
+
 // go test -run Test_Ctx_Format
 func Test_Ctx_Format(t *testing.T) {
 	t.Parallel()
@@ -2005,6 +2311,139 @@ func Test_Ctx_Route(t *testing.T) {
 	utils.AssertEqual(t, 0, len(c.Route().Handlers))
 }
 
+// go test -run Test_Ctx_Route_Settled
+func Test_Ctx_Route_Settled(t *testing.T) {
+	t.Parallel()
+	routes := make(chan string, 1)
+	app := New(Config{
+		ErrorHandler: func(c *Ctx, err error) error {
+			route := c.Route()
+			c.Set("X-Route", route.Path+" "+route.Name+" "+c.Params("id")+" "+fmt.Sprint(route.Metadata["owner"]))
+			return DefaultErrorHandler(c, err)
+		},
+	})
+	app.UseAfter(func(c *Ctx) {
+		routes <- c.Route().Path
+	})
+	app.Use(func(c *Ctx) error {
+		utils.AssertEqual(t, "/", c.Route().Path)
+		if c.Get(HeaderAuthorization) == "" {
+			return ErrUnauthorized
+		}
+		return c.Next()
+	})
+	app.Get("/users/:id", func(c *Ctx) error {
+		return ErrNotFound
+	}).Name("user")
+	app.Meta("owner", "team-a")
+
+	for _, tc := range []struct {
+		target, authorization string
+		status                int
+		route, after          string
+	}{
+		// The middleware returned before the endpoint
+		{"/users/1", "", StatusUnauthorized, "/users/:id user 1 team-a", "/users/:id"},
+		{"/users/2", "token", StatusNotFound, "/users/:id user 2 team-a", "/users/:id"},
+		// Without an endpoint the middleware route is kept
+		{"/missing", "token", StatusNotFound, "/   <nil>", "/"},
+	} {
+		req := httptest.NewRequest(MethodGet, tc.target, nil)
+		if tc.authorization != "" {
+			req.Header.Set(HeaderAuthorization, tc.authorization)
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, tc.status, resp.StatusCode, tc.target)
+		utils.AssertEqual(t, tc.route, resp.Header.Get("X-Route"), tc.target)
+		select {
+		case route := <-routes:
+			utils.AssertEqual(t, tc.after, route, tc.target)
+		case <-time.After(time.Second):
+			t.Fatal("after handler wasn't run")
+		}
+	}
+}
+
+// go test -run Test_Ctx_MatchedRoute
+func Test_Ctx_MatchedRoute(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Use(func(c *Ctx) error {
+		if route := c.MatchedRoute(); route != nil {
+			c.Set("X-Route", route.Method+" "+route.Path)
+		}
+		return c.Next()
+	})
+	app.Get("/user/:id", func(c *Ctx) error {
+		utils.AssertEqual(t, c.Route(), c.MatchedRoute())
+		utils.AssertEqual(t, "1", c.Params("id"))
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/user/1", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusOK, resp.StatusCode, "Status code")
+	utils.AssertEqual(t, "GET /user/:id", resp.Header.Get("X-Route"))
+
+	resp, err = app.Test(httptest.NewRequest(MethodPost, "/user/1", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusMethodNotAllowed, resp.StatusCode, "Status code")
+	utils.AssertEqual(t, "", resp.Header.Get("X-Route"))
+}
+
+// go test -run Test_Ctx_RouteMeta
+func Test_Ctx_RouteMeta(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Use(func(c *Ctx) error {
+		if c.RouteMeta("auth") == "none" {
+			return c.Next()
+		}
+		if c.Get(HeaderAuthorization) == "" {
+			return ErrUnauthorized
+		}
+		c.Set("X-Owner", fmt.Sprint(c.RouteMeta("owner")))
+		return c.Next()
+	})
+	app.Meta("owner", "platform")
+	app.Get("/health", func(c *Ctx) error {
+		utils.AssertEqual(t, "none", c.RouteMeta("auth"))
+		utils.AssertEqual(t, nil, c.RouteMeta("missing"))
+		return nil
+	})
+	app.Meta("auth", "none")
+	app.Get("/users", testEmptyHandler)
+	app.Meta("owner", "team-a")
+	app.Get("/orders", testEmptyHandler)
+
+	for _, tc := range []struct {
+		target, authorization string
+		status                int
+		owner                 string
+	}{
+		{"/health", "", StatusOK, ""},
+		{"/users", "", StatusUnauthorized, ""},
+		{"/users", "token", StatusOK, "team-a"},
+		// The metadata of the middleware route is the fallback
+		{"/orders", "token", StatusOK, "platform"},
+		{"/missing", "token", StatusNotFound, "platform"},
+	} {
+		req := httptest.NewRequest(MethodGet, tc.target, nil)
+		if tc.authorization != "" {
+			req.Header.Set(HeaderAuthorization, tc.authorization)
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, tc.status, resp.StatusCode, tc.target)
+		utils.AssertEqual(t, tc.owner, resp.Header.Get("X-Owner"), tc.target)
+	}
+
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	utils.AssertEqual(t, nil, c.RouteMeta("auth"))
+}
+
 // go test -run Test_Ctx_RouteNormalized
 func Test_Ctx_RouteNormalized(t *testing.T) {
 	t.Parallel()
@@ -2468,6 +2907,58 @@ func Benchmark_Ctx_XML(b *testing.B) {
 	utils.AssertEqual(b, `<SomeStruct><Name>Grame</Name><Age>20</Age></SomeStruct>`, string(c.Response().Body()))
 }
 
+// go test -run Test_Ctx_CBOR
+func Test_Ctx_CBOR(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	utils.AssertEqual(t, true, c.CBOR(complex(1, 1)) != nil)
+
+	type cborResult struct {
+		Name string `cbor:"name"`
+		Age  int    `json:"age"`
+	}
+	err := c.CBOR(cborResult{Name: "Grame", Age: 20})
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "\xa2\x64name\x65Grame\x63age\x14", string(c.Response().Body()))
+	utils.AssertEqual(t, MIMEApplicationCBOR, string(c.Response().Header.Peek("content-type")))
+
+	// Custom encoder
+	app = New(Config{
+		CBOREncoder: func(v interface{}) ([]byte, error) {
+			return []byte("custom"), nil
+		},
+	})
+	c2 := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c2)
+	utils.AssertEqual(t, nil, c2.CBOR(nil))
+	utils.AssertEqual(t, "custom", string(c2.Response().Body()))
+}
+
+// go test -run Test_Ctx_BodyParser_CBOR
+func Test_Ctx_BodyParser_CBOR(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Demo struct {
+		Name string   `cbor:"name"`
+		Tags []string `json:"tags"`
+	}
+	c.Request().Header.SetContentType(MIMEApplicationCBOR)
+	c.Request().SetBody([]byte("\xa2\x64name\x64john\x64tags\x81\x61a"))
+	d := new(Demo)
+	utils.AssertEqual(t, nil, c.BodyParser(d))
+	utils.AssertEqual(t, "john", d.Name)
+	utils.AssertEqual(t, []string{"a"}, d.Tags)
+
+	c.Request().SetBody([]byte("\xa1\x64name"))
+	utils.AssertEqual(t, true, c.BodyParser(d) != nil)
+}
+
 // go test -run Test_Ctx_Links
 func Test_Ctx_Links(t *testing.T) {
 	t.Parallel()
@@ -3000,6 +3491,42 @@ func Test_Ctx_RestartRouting(t *testing.T) {
 	utils.AssertEqual(t, 3, calls, "Number of calls")
 }
 
+// go test -run Test_Ctx_Fork
+func Test_Ctx_Fork(t *testing.T) {
+	t.Parallel()
+	app := New()
+	before, handled := 0, 0
+	app.Use(func(c *Ctx) error {
+		before++
+		c.Locals("user", "john")
+		c.Locals("tx", ioutil.NopCloser(nil))
+		return c.Next()
+	})
+	var fork *Ctx
+	app.Use(func(c *Ctx) error {
+		fork = c.Fork()
+		return c.Next()
+	})
+	app.Get("/users/:id", func(c *Ctx) error {
+		handled++
+		return c.SendString(c.Params("id") + " " + c.Locals("user").(string) + " " + strconv.Itoa(handled))
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/users/42", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "42 john 1", string(body))
+
+	// The fork continues after the forking handler, once the request is gone
+	utils.AssertEqual(t, nil, fork.Next())
+	utils.AssertEqual(t, "42 john 2", string(fork.Response().Body()))
+	utils.AssertEqual(t, 1, before)
+	// The Locals closed with the request aren't copied
+	utils.AssertEqual(t, nil, fork.Locals("tx"))
+	app.ReleaseCtx(fork)
+}
+
 // go test -run Test_Ctx_RestartRoutingWithChangedPath
 func Test_Ctx_RestartRoutingWithChangedPath(t *testing.T) {
 	app := New()
@@ -3304,6 +3831,91 @@ func Test_Ctx_SendStream(t *testing.T) {
 	utils.AssertEqual(t, "Hello bufio", string(c.Response().Body()))
 }
 
+// go test -run Test_Ctx_SendStreamWriter
+func Test_Ctx_SendStreamWriter(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c *Ctx) error {
+		return c.SendStreamWriter(func(w *bufio.Writer) error {
+			for i := 0; i < 3; i++ {
+				if _, err := fmt.Fprintf(w, "line %d\n", i); err != nil {
+					return err
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	app.Get("/error", func(c *Ctx) error {
+		return c.SendStreamWriter(func(w *bufio.Writer) error {
+			_, _ = w.WriteString("partial")
+			return errors.New("database gone")
+		})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, []string{"chunked"}, resp.TransferEncoding)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "line 0\nline 1\nline 2\n", string(body))
+
+	// The response is aborted
+	_, err = app.Test(httptest.NewRequest(MethodGet, "/error", nil))
+	utils.AssertEqual(t, true, err != nil)
+}
+
+// go test -run Test_Ctx_SendStreamWriter_Disconnect
+func Test_Ctx_SendStreamWriter_Disconnect(t *testing.T) {
+	t.Parallel()
+	app := New(Config{DisableStartupMessage: true})
+
+	stopped := make(chan error, 1)
+	app.Get("/", func(c *Ctx) error {
+		return c.SendStreamWriter(func(w *bufio.Writer) error {
+			for i := 0; ; i++ {
+				_, _ = fmt.Fprintf(w, "line %d\n", i)
+				if err := w.Flush(); err != nil {
+					stopped <- err
+					return err
+				}
+				time.Sleep(time.Millisecond)
+			}
+		})
+	})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		utils.AssertEqual(t, nil, app.Listener(ln))
+	}()
+	defer func() {
+		utils.AssertEqual(t, nil, app.Shutdown())
+	}()
+
+	conn, err := ln.Dial()
+	utils.AssertEqual(t, nil, err)
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	utils.AssertEqual(t, nil, err)
+	br := bufio.NewReader(conn)
+	for {
+		line, err := br.ReadString('\n')
+		utils.AssertEqual(t, nil, err)
+		if line == "line 0\n" {
+			break
+		}
+	}
+	utils.AssertEqual(t, nil, conn.Close())
+
+	select {
+	case err = <-stopped:
+		utils.AssertEqual(t, true, err != nil)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream writer did not stop")
+	}
+}
+
 // go test -run Test_Ctx_Set
 func Test_Ctx_Set(t *testing.T) {
 	t.Parallel()
@@ -3361,6 +3973,35 @@ func Test_Ctx_Status(t *testing.T) {
 	utils.AssertEqual(t, "Hello, World", string(c.Response().Body()))
 }
 
+// go test -run Test_Ctx_TempFile
+func Test_Ctx_TempFile(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var name string
+	app.Get("/", func(c *Ctx) error {
+		f, err := c.TempFile("fiber-*.txt")
+		if err != nil {
+			return err
+		}
+		name = f.Name()
+		if _, err = f.WriteString("staged"); err != nil {
+			return err
+		}
+		return c.SendFile(name)
+	})
+
+	resp, err := app.Dispatch(MethodGet, "/", nil, nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusOK, resp.StatusCode())
+	utils.AssertEqual(t, true, strings.HasSuffix(name, ".txt"))
+	utils.AssertEqual(t, "staged", string(resp.Body()))
+
+	// Removed once the request is done
+	_, err = os.Stat(name)
+	utils.AssertEqual(t, true, os.IsNotExist(err))
+}
+
 // go test -run Test_Ctx_Type
 func Test_Ctx_Type(t *testing.T) {
 	t.Parallel()
@@ -3656,6 +4297,168 @@ func Test_Ctx_QueryParser_WithSetParserDecoder(t *testing.T) {
 	utils.AssertEqual(t, "", q.Title)
 }
 
+// go test -run Test_Ctx_QueryParser_TimeFormat -v
+func Test_Ctx_QueryParser_TimeFormat(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Query struct {
+		Date    time.Time   `query:"date" time_format:"2006-01-02" time_utc:"1"`
+		Local   *time.Time  `query:"local" time_format:"2006-01-02 15:04" time_location:"Europe/Berlin"`
+		Since   time.Time   `query:"since" time_format:"unix" time_utc:"true"`
+		Days    []time.Time `query:"days" time_format:"2006-01-02" time_utc:"1"`
+		Default time.Time   `query:"default"`
+		Invalid time.Time   `query:"invalid" time_format:"2006-01-02" time_location:"Mars/Olympus"`
+	}
+
+	q := new(Query)
+	c.Request().URI().SetQueryString("date=2021-04-10&local=2021-04-10+12:30&since=1618057800&days=2021-04-10,2021-04-11&default=2021-04-10T12:30:00Z")
+	utils.AssertEqual(t, nil, c.QueryParser(q))
+	utils.AssertEqual(t, time.Date(2021, 4, 10, 0, 0, 0, 0, time.UTC), q.Date)
+	utils.AssertEqual(t, "2021-04-10T12:30:00+02:00", q.Local.Format(time.RFC3339))
+	utils.AssertEqual(t, time.Date(2021, 4, 10, 12, 30, 0, 0, time.UTC), q.Since)
+	utils.AssertEqual(t, []time.Time{time.Date(2021, 4, 10, 0, 0, 0, 0, time.UTC), time.Date(2021, 4, 11, 0, 0, 0, 0, time.UTC)}, q.Days)
+	utils.AssertEqual(t, time.Date(2021, 4, 10, 12, 30, 0, 0, time.UTC), q.Default)
+
+	c.Request().URI().SetQueryString("date=10.04.2021")
+	utils.AssertEqual(t, true, c.QueryParser(new(Query)) != nil)
+
+	c.Request().URI().SetQueryString("invalid=2021-04-10")
+	utils.AssertEqual(t, true, c.QueryParser(new(Query)) != nil)
+}
+
+// go test -run Test_Ctx_Parsers_DurationByteSize -v
+func Test_Ctx_Parsers_DurationByteSize(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Params struct {
+		Timeout  time.Duration   `query:"timeout" form:"timeout" reqHeader:"X-Timeout"`
+		Retries  []time.Duration `query:"retries"`
+		Interval *time.Duration  `query:"interval"`
+		MaxSize  ByteSize        `query:"max_size" form:"max_size" reqHeader:"X-Max-Size"`
+		Sizes    []ByteSize      `query:"sizes"`
+	}
+
+	p := new(Params)
+	c.Request().URI().SetQueryString("timeout=5s&retries=1s,1m30s&interval=2h&max_size=10MB&sizes=512,1.5KiB")
+	utils.AssertEqual(t, nil, c.QueryParser(p))
+	utils.AssertEqual(t, 5*time.Second, p.Timeout)
+	utils.AssertEqual(t, []time.Duration{time.Second, 90 * time.Second}, p.Retries)
+	utils.AssertEqual(t, 2*time.Hour, *p.Interval)
+	utils.AssertEqual(t, ByteSize(10_000_000), p.MaxSize)
+	utils.AssertEqual(t, []ByteSize{512, 1536}, p.Sizes)
+
+	p = new(Params)
+	c.Request().Header.SetContentType(MIMEApplicationForm)
+	c.Request().SetBody([]byte("timeout=250ms&max_size=2+GiB"))
+	utils.AssertEqual(t, nil, c.BodyParser(p))
+	utils.AssertEqual(t, 250*time.Millisecond, p.Timeout)
+	utils.AssertEqual(t, ByteSize(2<<30), p.MaxSize)
+
+	p = new(Params)
+	c.Request().Header.Set("X-Timeout", "1h")
+	c.Request().Header.Set("X-Max-Size", "1k")
+	utils.AssertEqual(t, nil, c.ReqHeaderParser(p))
+	utils.AssertEqual(t, time.Hour, p.Timeout)
+	utils.AssertEqual(t, ByteSize(1000), p.MaxSize)
+
+	// Invalid values are reported per field
+	c.Request().URI().SetQueryString("timeout=5&max_size=10XB")
+	err := c.QueryParser(new(Params))
+	var bindErr *BindError
+	utils.AssertEqual(t, true, errors.As(err, &bindErr))
+	utils.AssertEqual(t, 2, len(bindErr.Fields), err.Error())
+	utils.AssertEqual(t, "max_size", bindErr.Fields[0].Field)
+	utils.AssertEqual(t, "10XB", bindErr.Fields[0].Value)
+	utils.AssertEqual(t, "timeout", bindErr.Fields[1].Field)
+}
+
+// go test -run Test_Ctx_Parsers_IPURL -v
+func Test_Ctx_Parsers_IPURL(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Params struct {
+		IP       net.IP   `query:"ip" form:"ip" reqHeader:"X-Ip" params:"ip"`
+		Peers    []net.IP `query:"peers"`
+		Callback *url.URL `query:"callback" form:"callback" reqHeader:"X-Callback"`
+		Origin   url.URL  `query:"origin"`
+	}
+
+	p := new(Params)
+	c.Request().URI().SetQueryString("ip=10.0.0.1&peers=::1,192.168.0.1&callback=https%3A%2F%2Fexample.com%2Fhook%3Fid%3D1&origin=http://localhost:3000")
+	utils.AssertEqual(t, nil, c.QueryParser(p))
+	utils.AssertEqual(t, "10.0.0.1", p.IP.String())
+	utils.AssertEqual(t, []net.IP{net.ParseIP("::1"), net.ParseIP("192.168.0.1")}, p.Peers)
+	utils.AssertEqual(t, "https://example.com/hook?id=1", p.Callback.String())
+	utils.AssertEqual(t, "1", p.Callback.Query().Get("id"))
+	utils.AssertEqual(t, "localhost:3000", p.Origin.Host)
+
+	p = new(Params)
+	c.Request().Header.SetContentType(MIMEApplicationForm)
+	c.Request().SetBody([]byte("ip=fe80::1&callback=%2Fhooks"))
+	utils.AssertEqual(t, nil, c.BodyParser(p))
+	utils.AssertEqual(t, "fe80::1", p.IP.String())
+	utils.AssertEqual(t, "/hooks", p.Callback.Path)
+
+	p = new(Params)
+	c.Request().Header.Set("X-Ip", "127.0.0.1")
+	c.Request().Header.Set("X-Callback", "https://example.com")
+	utils.AssertEqual(t, nil, c.ReqHeaderParser(p))
+	utils.AssertEqual(t, true, p.IP.IsLoopback())
+	utils.AssertEqual(t, "example.com", p.Callback.Host)
+
+	// Invalid values are reported per field
+	c.Request().URI().SetQueryString("ip=10.0.0&callback=http://[::1")
+	err := c.QueryParser(new(Params))
+	var bindErr *BindError
+	utils.AssertEqual(t, true, errors.As(err, &bindErr))
+	utils.AssertEqual(t, 2, len(bindErr.Fields), err.Error())
+	utils.AssertEqual(t, "callback", bindErr.Fields[0].Field)
+	utils.AssertEqual(t, "http://[::1", bindErr.Fields[0].Value)
+	var urlErr *url.Error
+	utils.AssertEqual(t, true, errors.As(bindErr.Fields[0], &urlErr))
+	utils.AssertEqual(t, "ip", bindErr.Fields[1].Field)
+	utils.AssertEqual(t, "10.0.0", bindErr.Fields[1].Value)
+	var ipErr *net.ParseError
+	utils.AssertEqual(t, true, errors.As(bindErr.Fields[1], &ipErr))
+}
+
+// go test -run Test_Ctx_Parsers_SameStructType -v
+func Test_Ctx_Parsers_SameStructType(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Filter struct {
+		Name string `query:"q" form:"name" reqHeader:"X-Name"`
+	}
+
+	// The struct metadata is cached per alias tag
+	for i := 0; i < 2; i++ {
+		c.Request().URI().SetQueryString("q=query&name=wrong")
+		c.Request().Header.SetContentType(MIMEApplicationForm)
+		c.Request().SetBody([]byte("name=form&q=wrong"))
+		c.Request().Header.Set("X-Name", "header")
+
+		f := new(Filter)
+		utils.AssertEqual(t, nil, c.QueryParser(f))
+		utils.AssertEqual(t, "query", f.Name)
+		utils.AssertEqual(t, nil, c.BodyParser(f))
+		utils.AssertEqual(t, "form", f.Name)
+		utils.AssertEqual(t, nil, c.ReqHeaderParser(f))
+		utils.AssertEqual(t, "header", f.Name)
+	}
+}
+
 // go test -run Test_Ctx_QueryParser_Schema -v
 func Test_Ctx_QueryParser_Schema(t *testing.T) {
 	t.Parallel()
@@ -4346,3 +5149,34 @@ func Test_Ctx_IsFromLocal(t *testing.T) {
 		utils.AssertEqual(t, false, c.IsFromLocal())
 	}
 }
+
+// go test -run Test_Ctx_RouteURL
+func Test_Ctx_RouteURL(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/users/:id", func(c *Ctx) error {
+		url, err := c.RouteURL("user", Map{"id": c.Params("id"), "page": 2})
+		if err != nil {
+			return err
+		}
+		return c.SendString(url)
+	}).Name("user")
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/users/7", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "/users/7?page=2", string(body))
+}
+
+// go test -run Test_Ctx_RequestID
+func Test_Ctx_RequestID(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	utils.AssertEqual(t, "", c.RequestID())
+	c.SetRequestID("abc")
+	utils.AssertEqual(t, "abc", c.RequestID())
+}
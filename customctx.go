@@ -0,0 +1,35 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+// ctxResetter is implemented by custom contexts with per-request state
+type ctxResetter interface {
+	Reset()
+}
+
+// Custom returns the custom context created by Config.NewCtxFunc, or nil if
+// the app has none. The context is created once per pooled Ctx, so that
+// frameworks built on fiber can add typed methods without an allocation per
+// request:
+//
+//	type Ctx struct {
+//		*fiber.Ctx
+//		user *User
+//	}
+//
+//	func (c *Ctx) User() *User { return c.user }
+//	func (c *Ctx) Reset()      { c.user = nil }
+//
+//	app := fiber.New(fiber.Config{
+//		NewCtxFunc: func(c *fiber.Ctx) interface{} {
+//			return &Ctx{Ctx: c}
+//		},
+//	})
+func (c *Ctx) Custom() interface{} {
+	if c.custom == nil && c.app.config.NewCtxFunc != nil {
+		c.custom = c.app.config.NewCtxFunc(c)
+	}
+	return c.custom
+}
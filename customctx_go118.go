@@ -0,0 +1,33 @@
+//go:build go1.18
+// +build go1.18
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import "fmt"
+
+// CtxOf returns the custom context of the request as T, see Config.NewCtxFunc.
+// It panics if the app has no custom context of type T.
+func CtxOf[T any](c *Ctx) T {
+	custom, ok := c.Custom().(T)
+	if !ok {
+		var zero T
+		panic(fmt.Sprintf("fiber: the app has no custom context of type %T but %T", zero, c.Custom()))
+	}
+	return custom
+}
+
+// HandlerOf adapts a handler of the custom context T to a Handler, so that
+// frameworks can register their handlers without wrapping each of them:
+//
+//	func Get(app *fiber.App, path string, handler func(c *Ctx) error) {
+//		app.Get(path, fiber.HandlerOf(handler))
+//	}
+func HandlerOf[T any](handler func(T) error) Handler {
+	return func(c *Ctx) error {
+		return handler(CtxOf[T](c))
+	}
+}
@@ -0,0 +1,74 @@
+//go:build go1.18
+// +build go1.18
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+type testCustomCtx struct {
+	*Ctx
+	user string
+}
+
+func (c *testCustomCtx) User() string {
+	return c.user
+}
+
+func (c *testCustomCtx) Reset() {
+	c.user = ""
+}
+
+// go test -run Test_Ctx_Custom
+func Test_Ctx_Custom(t *testing.T) {
+	t.Parallel()
+	created := 0
+	app := New(Config{
+		NewCtxFunc: func(c *Ctx) interface{} {
+			created++
+			return &testCustomCtx{Ctx: c}
+		},
+	})
+	app.Use(HandlerOf(func(c *testCustomCtx) error {
+		utils.AssertEqual(t, "", c.User())
+		c.user = c.Query("user")
+		return c.Next()
+	}))
+	app.Get("/", HandlerOf(func(c *testCustomCtx) error {
+		return c.SendString("hello " + c.User())
+	}))
+
+	for _, user := range []string{"john", "jane"} {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, "/?user="+user, nil))
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, "hello "+user, string(body))
+	}
+	// The context is created once per pooled Ctx
+	utils.AssertEqual(t, true, created >= 1 && created <= 2)
+}
+
+// go test -run Test_CtxOf_Missing
+func Test_CtxOf_Missing(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	utils.AssertEqual(t, nil, c.Custom())
+	defer func() {
+		utils.AssertEqual(t, "fiber: the app has no custom context of type *fiber.testCustomCtx but <nil>", recover())
+	}()
+	_ = CtxOf[*testCustomCtx](c)
+}
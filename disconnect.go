@@ -0,0 +1,83 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// ErrClientDisconnected is the error of the request context, see
+// Ctx.UserContext, once the client closed the connection.
+var ErrClientDisconnected = errors.New("fiber: client disconnected")
+
+// Done returns a channel, which is closed once the client disconnected, the
+// request finished or the server shuts down. It's the Done channel of the
+// request context, see UserContext, so long-running handlers, e.g. SSE
+// streams or expensive queries, can abort when the peer goes away:
+//
+//	select {
+//	case <-c.Done():
+//		return c.UserContext().Err() // ErrClientDisconnected
+//	case result := <-results:
+//		return c.JSON(result)
+//	}
+//
+// Disconnects are detected on TCP and unix connections of Linux, macOS and
+// the BSDs while the handlers run, by peeking at the connection. They aren't
+// detected if the client sent further data, e.g. a pipelined request or a
+// TLS alert.
+func (c *Ctx) Done() <-chan struct{} {
+	return c.requestCtx().Done()
+}
+
+// OnDisconnect registers a function, which is called once if the client
+// disconnects while the handlers run, see Done. The function is called by
+// another goroutine, concurrently to the handlers.
+func (c *Ctx) OnDisconnect(fn func()) {
+	c.requestCtx().onDisconnect(fn)
+}
+
+// requestCtx returns the request context, even if the user context has been replaced
+func (c *Ctx) requestCtx() *requestContext {
+	if c.requestContext == nil {
+		c.requestContext = newRequestContext(c)
+	}
+	return c.requestContext
+}
+
+// aLongTimeAgo is a deadline in the past, which interrupts the blocked reads
+var aLongTimeAgo = time.Unix(1, 0)
+
+// disconnectWatch watches the connection of a request for a disconnect
+type disconnectWatch struct {
+	conn    net.Conn
+	stopped chan struct{}
+}
+
+// watchDisconnect starts watching the connection, it calls disconnected if the
+// client closed the connection. It returns nil if the connection can't be watched.
+func watchDisconnect(conn net.Conn, disconnected func()) *disconnectWatch {
+	if conn == nil || !canPeek(conn) {
+		return nil
+	}
+	w := &disconnectWatch{conn: conn, stopped: make(chan struct{})}
+	go func() {
+		defer close(w.stopped)
+		if peekDisconnect(conn) {
+			disconnected()
+		}
+	}()
+	return w
+}
+
+// stop stops watching, before the server reads the connection again
+func (w *disconnectWatch) stop() {
+	_ = w.conn.SetReadDeadline(aLongTimeAgo)
+	<-w.stopped
+	// The server sets the deadlines of keep-alive connections itself
+	_ = w.conn.SetReadDeadline(time.Time{})
+}
@@ -0,0 +1,21 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import "net"
+
+// canPeek reports whether peekDisconnect is able to watch the connection,
+// disconnects aren't detected on this platform
+func canPeek(net.Conn) bool {
+	return false
+}
+
+// peekDisconnect reports whether the client closed the connection
+func peekDisconnect(net.Conn) bool {
+	return false
+}
@@ -0,0 +1,56 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net"
+	"syscall"
+)
+
+// syscallConn returns the connection of the file descriptor, e.g. of a TLS connection
+func syscallConn(conn net.Conn) (syscall.Conn, bool) {
+	if tlsConn, ok := conn.(interface{ NetConn() net.Conn }); ok {
+		conn = tlsConn.NetConn()
+	}
+	sc, ok := conn.(syscall.Conn)
+	return sc, ok
+}
+
+// canPeek reports whether peekDisconnect is able to watch the connection
+func canPeek(conn net.Conn) bool {
+	_, ok := syscallConn(conn)
+	return ok
+}
+
+// peekDisconnect blocks until the connection is readable and reports whether
+// the client closed it, without consuming any data
+func peekDisconnect(conn net.Conn) (disconnected bool) {
+	sc, ok := syscallConn(conn)
+	if !ok {
+		return false
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return false
+	}
+	var buf [1]byte
+	// Read waits for the readability in the netpoller, until the deadline
+	_ = raw.Read(func(fd uintptr) bool {
+		n, _, err := syscall.Recvfrom(int(fd), buf[:], syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+		switch err {
+		case syscall.EAGAIN, syscall.EINTR:
+			return false
+		case nil:
+			disconnected = n == 0
+		default:
+			disconnected = err == syscall.ECONNRESET
+		}
+		return true
+	})
+	return disconnected
+}
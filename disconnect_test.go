@@ -0,0 +1,104 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_Ctx_Done_Disconnect
+func Test_Ctx_Done_Disconnect(t *testing.T) {
+	t.Parallel()
+	if !canPeek(&net.TCPConn{}) {
+		t.Skip("disconnects aren't detected on " + runtime.GOOS)
+	}
+	app := New(Config{DisableStartupMessage: true})
+	started, result := make(chan struct{}), make(chan error, 1)
+	called := make(chan struct{})
+	app.Get("/slow", func(c *Ctx) error {
+		c.OnDisconnect(func() {
+			close(called)
+		})
+		close(started)
+		select {
+		case <-c.Done():
+			result <- c.UserContext().Err()
+		case <-time.After(2 * time.Second):
+			result <- nil
+		}
+		return nil
+	})
+
+	ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+	utils.AssertEqual(t, nil, err)
+	go func() {
+		_ = app.Listener(ln)
+	}()
+	defer func() {
+		_ = app.Shutdown()
+	}()
+
+	conn, err := net.Dial(NetworkTCP4, ln.Addr().String())
+	utils.AssertEqual(t, nil, err)
+	_, err = conn.Write([]byte("GET /slow HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+	utils.AssertEqual(t, nil, err)
+	<-started
+	utils.AssertEqual(t, nil, conn.Close())
+
+	utils.AssertEqual(t, ErrClientDisconnected, <-result)
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("OnDisconnect has not been called")
+	}
+}
+
+// go test -run Test_Ctx_Done_KeepAlive
+func Test_Ctx_Done_KeepAlive(t *testing.T) {
+	t.Parallel()
+	app := New(Config{DisableStartupMessage: true})
+	app.Get("/", func(c *Ctx) error {
+		select {
+		case <-c.Done():
+			return c.SendString("cancelled")
+		default:
+			return c.SendString("ok")
+		}
+	})
+
+	ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+	utils.AssertEqual(t, nil, err)
+	go func() {
+		_ = app.Listener(ln)
+	}()
+	defer func() {
+		_ = app.Shutdown()
+	}()
+
+	// The watching stops with the handlers, the connection is reused
+	conn, err := net.Dial(NetworkTCP4, ln.Addr().String())
+	utils.AssertEqual(t, nil, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+	br := bufio.NewReader(conn)
+	for i := 0; i < 3; i++ {
+		_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+		utils.AssertEqual(t, nil, err)
+		resp, err := http.ReadResponse(br, nil)
+		utils.AssertEqual(t, nil, err)
+		body := make([]byte, resp.ContentLength)
+		_, err = br.Read(body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, "ok", string(body))
+	}
+}
@@ -15,8 +15,56 @@ type (
 	EmptyFieldError = schema.EmptyFieldError
 	// MultiError error exposes the internal schema.MultiError for public use.
 	MultiError = schema.MultiError
+	// LimitError error exposes the internal schema.LimitError for public use.
+	LimitError = schema.LimitError
 )
 
+// BindError is returned by BodyParser, QueryParser, ReqHeaderParser and
+// ParamsParser when fields of the struct can't be bound. It lists all failed
+// fields, so that machine-readable 400 responses can be created:
+//
+//	var bindErr *fiber.BindError
+//	if errors.As(err, &bindErr) {
+//		return c.Status(fiber.StatusBadRequest).JSON(bindErr)
+//	}
+//
+// It unwraps to the MultiError of the decoder.
+type BindError struct {
+	Fields []*FieldError `json:"errors"` // Sorted by field path
+
+	multi MultiError
+}
+
+// Error makes it compatible with the `error` interface.
+func (e *BindError) Error() string {
+	return e.multi.Error()
+}
+
+// Unwrap returns the underlying MultiError.
+func (e *BindError) Unwrap() error {
+	return e.multi
+}
+
+// FieldError describes a field of a BindError
+type FieldError struct {
+	Field   string `json:"field"`           // Full path of the field, e.g. "data.1.age"
+	Source  string `json:"source"`          // Tag of the source: "query", "reqHeader", "form", "params" or "json"
+	Value   string `json:"value,omitempty"` // Raw value
+	Type    string `json:"type,omitempty"`  // Target type
+	Message string `json:"message"`
+	Err     error  `json:"-"` // ConversionError, UnknownKeyError, EmptyFieldError or another error
+}
+
+// Error makes it compatible with the `error` interface.
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error of the decoder.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
 type (
 	// An InvalidUnmarshalError describes an invalid argument passed to Unmarshal.
 	// (The argument to Unmarshal must be a non-nil pointer.)
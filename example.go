@@ -0,0 +1,115 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// RouteExample is an example request and response of a route, e.g. for the
+// documentation of the API. With Config.MockExamples, it's served by routes
+// which aren't implemented yet.
+type RouteExample struct {
+	// Name identifies the example, e.g. "not-found"
+	Name string `json:"name,omitempty"`
+	// Summary describes the example
+	Summary string `json:"summary,omitempty"`
+	// Request is the example request body
+	Request interface{} `json:"request,omitempty"`
+	// Status is the status code of the response, 200 if zero
+	Status int `json:"status,omitempty"`
+	// ContentType of the response, derived from the body if empty
+	ContentType string `json:"content_type,omitempty"`
+	// Response is the example response body, strings and byte slices are sent
+	// as is and other values are encoded as JSON
+	Response interface{} `json:"response,omitempty"`
+}
+
+// Example attaches an example request and response to the latest registered
+// route, a route may have several examples:
+//
+//	app.Get("/users/:id", handler)
+//	app.Example(fiber.RouteExample{Name: "found", Response: User{ID: 1, Name: "john"}}).
+//	    Example(fiber.RouteExample{Name: "not-found", Status: fiber.StatusNotFound})
+//
+// The examples are listed in the Examples field of the route, see App.Stack.
+func (app *App) Example(example RouteExample) *App {
+	app.mutex.Lock()
+	if app.latestRoute != nil {
+		examples := append(app.latestRoute.Examples, example)
+		for _, route := range app.latestRoutes() {
+			route.Examples = examples
+		}
+	}
+	app.mutex.Unlock()
+
+	return app
+}
+
+// mockExample serves an example response of the route instead of the error,
+// if the route isn't implemented yet. The client can choose the example by
+// its name with a "Prefer: example=<name>" header.
+func (app *App) mockExample(c *Ctx, err error) bool {
+	if !app.config.MockExamples || c.route == nil || len(c.route.Examples) == 0 {
+		return false
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Code != StatusNotImplemented {
+		return false
+	}
+
+	example, applied := c.route.Examples[0], ""
+	if name := preferredExample(c.Get(HeaderPrefer)); name != "" {
+		for _, ex := range c.route.Examples {
+			if ex.Name == name {
+				example, applied = ex, "example="+name
+				break
+			}
+		}
+	}
+
+	c.fasthttp.Response.Reset()
+	if applied != "" {
+		c.Set(HeaderPreferenceApplied, applied)
+	}
+	status := example.Status
+	if status == 0 {
+		status = StatusOK
+	}
+	c.Status(status)
+	switch body := example.Response.(type) {
+	case nil:
+	case string:
+		c.fasthttp.Response.SetBodyString(body)
+		c.Type("txt")
+	case []byte:
+		c.fasthttp.Response.SetBody(body)
+		c.Type("bin")
+	default:
+		if err := c.JSON(body); err != nil {
+			return false
+		}
+	}
+	if example.ContentType != "" {
+		c.Set(HeaderContentType, example.ContentType)
+	}
+	return true
+}
+
+// preferredExample returns the example parameter of the Prefer header
+func preferredExample(prefer string) string {
+	for _, pref := range strings.Split(prefer, ",") {
+		for _, param := range strings.Split(pref, ";") {
+			param = utils.Trim(param, ' ')
+			if strings.HasPrefix(param, "example=") {
+				return strings.Trim(param[len("example="):], "\"")
+			}
+		}
+	}
+	return ""
+}
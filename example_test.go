@@ -0,0 +1,99 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_App_Example
+func Test_App_Example(t *testing.T) {
+	t.Parallel()
+	app := New()
+	grp := app.Group("/api")
+	grp.Get("/users/:id", testEmptyHandler)
+	app.Example(RouteExample{Name: "found", Response: map[string]string{"name": "john"}}).
+		Example(RouteExample{Name: "not-found", Status: StatusNotFound})
+
+	stack := app.Stack()
+	get, head := stack[app.methodInt(MethodGet)][0], stack[app.methodInt(MethodHead)][0]
+	utils.AssertEqual(t, 2, len(get.Examples))
+	utils.AssertEqual(t, "not-found", get.Examples[1].Name)
+	utils.AssertEqual(t, get.Examples, head.Examples)
+
+	out, err := json.Marshal(get.Examples[1])
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `{"name":"not-found","status":404}`, string(out))
+}
+
+// go test -run Test_App_MockExamples
+func Test_App_MockExamples(t *testing.T) {
+	t.Parallel()
+	app := New(Config{MockExamples: true})
+	notImplemented := func(c *Ctx) error {
+		return ErrNotImplemented
+	}
+	app.Get("/users/:id", notImplemented)
+	app.Example(RouteExample{Name: "found", Response: map[string]string{"name": "john"}}).
+		Example(RouteExample{Name: "not-found", Status: StatusNotFound, Response: "user not found"})
+	app.Get("/avatar", notImplemented)
+	app.Example(RouteExample{ContentType: MIMETextHTML, Response: []byte("<img>")})
+	app.Get("/implemented", func(c *Ctx) error {
+		return c.SendString("real")
+	})
+	app.Example(RouteExample{Response: "example"})
+	app.Get("/undocumented", notImplemented)
+
+	test := func(path, prefer string) (int, string, string) {
+		req := httptest.NewRequest(MethodGet, path, nil)
+		if prefer != "" {
+			req.Header.Set(HeaderPrefer, prefer)
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		return resp.StatusCode, resp.Header.Get(HeaderContentType), string(body)
+	}
+
+	status, contentType, body := test("/users/1", "")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, MIMEApplicationJSON, contentType)
+	utils.AssertEqual(t, `{"name":"john"}`, body)
+
+	req := httptest.NewRequest(MethodGet, "/users/1", nil)
+	req.Header.Set(HeaderPrefer, `return=minimal; example="not-found"`)
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusNotFound, resp.StatusCode)
+	utils.AssertEqual(t, "example=not-found", resp.Header.Get(HeaderPreferenceApplied))
+
+	status, _, body = test("/users/1", "example=unknown")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, `{"name":"john"}`, body)
+
+	status, contentType, body = test("/avatar", "")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, MIMETextHTML, contentType)
+	utils.AssertEqual(t, "<img>", body)
+
+	_, _, body = test("/implemented", "")
+	utils.AssertEqual(t, "real", body)
+
+	status, _, _ = test("/undocumented", "")
+	utils.AssertEqual(t, StatusNotImplemented, status)
+
+	// Without MockExamples, the error is handled as usual
+	app = New()
+	app.Get("/", notImplemented)
+	app.Example(RouteExample{Response: "example"})
+	status, _, _ = test("/", "")
+	utils.AssertEqual(t, StatusNotImplemented, status)
+}
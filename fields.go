@@ -0,0 +1,260 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// JSONFields sends the data as JSON like JSON, limited to the fields of the
+// "fields" query param, e.g. ?fields=id,name,author.name for sparse fieldsets.
+// The data is sent as is without the param, see SelectFields for the fields
+// and errors.
+func (c *Ctx) JSONFields(data interface{}) error {
+	query := c.Query("fields")
+	if query == "" {
+		return c.JSON(data)
+	}
+	selected, err := SelectFields(data, strings.Split(query, ",")...)
+	if err != nil {
+		return err
+	}
+	return c.JSON(selected)
+}
+
+// SelectFields returns the data limited to the fields, which are the JSON
+// names of the struct fields and map keys. Nested fields are separated by
+// dots, e.g. "author.name", and the fields of slices apply to their elements:
+//
+//	fiber.SelectFields(articles, "id", "title", "author.name")
+//
+// The selected values are encoded by the JSONEncoder of the app as usual.
+// Unknown fields of structs, including the ones with the json tag "-", are
+// rejected with 400 Bad Request, and so are fields of values other than
+// structs and maps.
+func SelectFields(data interface{}, fields ...string) (interface{}, error) {
+	tree := fieldTree{}
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			tree.add(field)
+		}
+	}
+	if len(tree) == 0 {
+		return data, nil
+	}
+	return tree.selectValue(reflect.ValueOf(data), "")
+}
+
+// fieldTree holds the selected fields, a nil subtree selects the whole value
+type fieldTree map[string]fieldTree
+
+// add adds the dotted path of a field to the tree
+func (t fieldTree) add(path string) {
+	name, rest := path, ""
+	if i := strings.IndexByte(path, '.'); i != -1 {
+		name, rest = path[:i], path[i+1:]
+	}
+	sub, ok := t[name]
+	switch {
+	case rest == "":
+		// The whole value is selected
+		t[name] = nil
+	case !ok:
+		sub = fieldTree{}
+		t[name] = sub
+		sub.add(rest)
+	case sub != nil:
+		sub.add(rest)
+	}
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	selectFieldsCache sync.Map // reflect.Type => map[string]selectField
+)
+
+// selectValue returns the fields of the tree of the value
+func (t fieldTree) selectValue(v reflect.Value, path string) (interface{}, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return nil, fieldsError(path, "isn't an object")
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := selectFieldsOf(v.Type())
+		selected := make(map[string]interface{}, len(t))
+		for name, sub := range t {
+			field, ok := fields[name]
+			if !ok {
+				return nil, fieldsError(joinField(path, name), "is unknown")
+			}
+			fv, ok := embeddedField(v, field.index)
+			if !ok || (field.omitEmpty && fv.IsZero()) {
+				continue
+			}
+			if err := t.set(selected, name, sub, fv, path); err != nil {
+				return nil, err
+			}
+		}
+		return selected, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if v.IsNil() {
+			return nil, nil
+		}
+		selected := make(map[string]interface{}, len(t))
+		for name, sub := range t {
+			mv := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !mv.IsValid() {
+				continue
+			}
+			if err := t.set(selected, name, sub, mv, path); err != nil {
+				return nil, err
+			}
+		}
+		return selected, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		selected := make([]interface{}, v.Len())
+		for i := range selected {
+			item, err := t.selectValue(v.Index(i), path)
+			if err != nil {
+				return nil, err
+			}
+			selected[i] = item
+		}
+		return selected, nil
+	}
+	return nil, fieldsError(path, "isn't an object")
+}
+
+// set sets the selected value of the field
+func (t fieldTree) set(selected map[string]interface{}, name string, sub fieldTree, v reflect.Value, path string) error {
+	if sub == nil {
+		selected[name] = v.Interface()
+		return nil
+	}
+	value, err := sub.selectValue(v, joinField(path, name))
+	if err != nil {
+		return err
+	}
+	selected[name] = value
+	return nil
+}
+
+// joinField joins the path of a field and the name of a nested field
+func joinField(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// fieldsError returns the 400 Bad Request error of a field
+func fieldsError(path, reason string) error {
+	if path == "" {
+		return NewError(StatusBadRequest, "fields: value "+reason)
+	}
+	return NewError(StatusBadRequest, "fields: "+path+" "+reason)
+}
+
+// selectField is a field of a struct as it's encoded by encoding/json
+type selectField struct {
+	index     []int
+	omitEmpty bool
+}
+
+// structFields returns the fields of the struct type by their JSON names
+func selectFieldsOf(typ reflect.Type) map[string]selectField {
+	if fields, ok := selectFieldsCache.Load(typ); ok {
+		return fields.(map[string]selectField)
+	}
+	fields := make(map[string]selectField)
+	collectSelectFields(typ, nil, fields)
+	selectFieldsCache.Store(typ, fields)
+	return fields
+}
+
+// collectFields adds the fields of the struct type, the fields of embedded
+// structs without name are promoted unless the name is already taken
+func collectSelectFields(typ reflect.Type, index []int, fields map[string]selectField) {
+	var embedded []int
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if j := strings.IndexByte(tag, ','); j != -1 {
+			name, opts = tag[:j], tag[j+1:]
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, i)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			// Unexported
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = selectField{
+			index:     append(append([]int{}, index...), i),
+			omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+		}
+	}
+	for _, i := range embedded {
+		f := typ.Field(i)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		promoted := make(map[string]selectField)
+		collectSelectFields(ft, append(append([]int{}, index...), i), promoted)
+		for name, field := range promoted {
+			if _, ok := fields[name]; !ok {
+				fields[name] = field
+			}
+		}
+	}
+}
+
+// fieldByIndex returns the nested field, it's false for nil embedded pointers
+func embeddedField(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
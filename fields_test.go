@@ -0,0 +1,101 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type fieldsAuthor struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type fieldsBase struct {
+	ID int `json:"id"`
+}
+
+type fieldsArticle struct {
+	fieldsBase
+	Title     string            `json:"title"`
+	Body      string            `json:"body,omitempty"`
+	Author    *fieldsAuthor     `json:"author"`
+	Meta      map[string]string `json:"meta"`
+	CreatedAt time.Time         `json:"created_at"`
+	Secret    string            `json:"-"`
+	Raw       int
+}
+
+// go test -run Test_SelectFields
+func Test_SelectFields(t *testing.T) {
+	t.Parallel()
+	article := fieldsArticle{
+		fieldsBase: fieldsBase{ID: 1},
+		Title:      "Hello",
+		Author:     &fieldsAuthor{ID: 2, Name: "john"},
+		Meta:       map[string]string{"lang": "en", "draft": "no"},
+		Raw:        3,
+	}
+
+	selected, err := SelectFields(article, "id", " title", "body", "author.name", "meta.lang", "Raw")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, map[string]interface{}{
+		"id":     1,
+		"title":  "Hello",
+		"author": map[string]interface{}{"name": "john"},
+		"meta":   map[string]interface{}{"lang": "en"},
+		"Raw":    3,
+	}, selected)
+
+	// Selecting a field selects all of its fields
+	selected, err = SelectFields([]*fieldsArticle{&article, nil}, "author.name", "author")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, []interface{}{map[string]interface{}{"author": article.Author}, nil}, selected)
+
+	selected, err = SelectFields(article)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, article, selected)
+
+	_, err = SelectFields(article, "Secret")
+	utils.AssertEqual(t, "fields: Secret is unknown", err.Error())
+	utils.AssertEqual(t, StatusBadRequest, err.(*Error).Code)
+	_, err = SelectFields(article, "author.email")
+	utils.AssertEqual(t, "fields: author.email is unknown", err.Error())
+	_, err = SelectFields(article, "title.length")
+	utils.AssertEqual(t, "fields: title isn't an object", err.Error())
+	_, err = SelectFields(article, "created_at.year")
+	utils.AssertEqual(t, "fields: created_at isn't an object", err.Error())
+}
+
+// go test -run Test_Ctx_JSONFields
+func Test_Ctx_JSONFields(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c *Ctx) error {
+		return c.JSONFields([]fieldsAuthor{{ID: 1, Name: "john"}})
+	})
+
+	for query, expected := range map[string]string{
+		"":             `[{"id":1,"name":"john"}]`,
+		"?fields=name": `[{"name":"john"}]`,
+	} {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, "/"+query, nil))
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, StatusOK, resp.StatusCode)
+		utils.AssertEqual(t, MIMEApplicationJSON, resp.Header.Get(HeaderContentType))
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, expected, string(body))
+	}
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/?fields=email", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusBadRequest, resp.StatusCode)
+}
@@ -3,6 +3,10 @@ module github.com/gofiber/fiber/v2
 go 1.16
 
 require (
+	github.com/andybalholm/brotli v1.0.4
+	github.com/klauspost/compress v1.15.0
+	github.com/valyala/bytebufferpool v1.0.0
 	github.com/valyala/fasthttp v1.40.0
 	golang.org/x/sys v0.0.0-20220227234510-4e6760a101f9
+	golang.org/x/text v0.3.7
 )
@@ -8,42 +8,68 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
-	"sync/atomic"
 )
 
 // Group struct
 type Group struct {
-	app  *App
-	name string
+	app    *App
+	parent *Group
+	hooks  *Hooks
+	name   string
 
 	Prefix string
 }
 
+// newGroup creates a group of the parent, which is nil for groups of the app,
+// and executes the OnGroup hooks
+func (app *App) newGroup(prefix string, parent *Group) *Group {
+	grp := &Group{Prefix: prefix, app: app, parent: parent}
+	grp.hooks = newHooks(app)
+
+	if err := app.hooks.executeOnGroupHooks(*grp); err != nil {
+		panic(err)
+	}
+	for _, g := range parent.lineage() {
+		if err := g.hooks.executeOnGroupHooks(*grp); err != nil {
+			panic(err)
+		}
+	}
+
+	return grp
+}
+
+// lineage returns the group and its parents, starting with the outermost one
+func (grp *Group) lineage() []*Group {
+	var groups []*Group
+	for g := grp; g != nil; g = g.parent {
+		groups = append([]*Group{g}, groups...)
+	}
+	return groups
+}
+
+// Hooks returns the hooks of the group. The OnRoute, OnName, OnGroup and OnGroupName
+// hooks only run for the routes and sub-groups of the group, after the ones of the
+// app. The other hooks are registered at the app.
+//
+//	admin := app.Group("/admin", auth).(*fiber.Group)
+//	admin.Hooks().OnRoute(func(r fiber.Route) error {
+//	     log.Println("admin route", r.Method, r.Path)
+//	     return nil
+//	})
+func (grp *Group) Hooks() *Hooks {
+	return grp.hooks
+}
+
 // Mount attaches another app instance as a sub-router along a routing path.
 // It's very useful to split up a large API as many independent routers and
 // compose them as a single service using Mount.
 func (grp *Group) Mount(prefix string, fiber *App) Router {
-	stack := fiber.Stack()
 	groupPath := getGroupPath(grp.Prefix, prefix)
 	groupPath = strings.TrimRight(groupPath, "/")
 	if groupPath == "" {
 		groupPath = "/"
 	}
-
-	for m := range stack {
-		for r := range stack[m] {
-			route := grp.app.copyRoute(stack[m][r])
-			grp.app.addRoute(route.Method, grp.app.addPrefixToRoute(groupPath, route))
-		}
-	}
-
-	// Support for configs of mounted-apps and sub-mounted-apps
-	for mountedPrefixes, subApp := range fiber.appList {
-		grp.app.appList[groupPath+mountedPrefixes] = subApp
-		subApp.init()
-	}
-
-	atomic.AddUint32(&grp.app.handlersCount, fiber.handlersCount)
+	grp.app.mount(groupPath, fiber)
 
 	return grp
 }
@@ -51,17 +77,21 @@ func (grp *Group) Mount(prefix string, fiber *App) Router {
 // Assign name to specific route.
 func (grp *Group) Name(name string) Router {
 	grp.app.mutex.Lock()
-	if strings.HasPrefix(grp.Prefix, grp.app.latestGroup.Prefix) {
-		grp.name = grp.app.latestGroup.name + name
+	// Sub-groups inherit the names of their parents
+	if grp.parent != nil {
+		grp.name = grp.parent.name + name
 	} else {
 		grp.name = name
 	}
 
-	grp.app.latestGroup = grp
-
-	if err := grp.app.hooks.executeOnGroupNameHooks(*grp.app.latestGroup); err != nil {
+	if err := grp.app.hooks.executeOnGroupNameHooks(*grp); err != nil {
 		panic(err)
 	}
+	for _, g := range grp.parent.lineage() {
+		if err := g.hooks.executeOnGroupNameHooks(*grp); err != nil {
+			panic(err)
+		}
+	}
 	grp.app.mutex.Unlock()
 
 	return grp
@@ -94,7 +124,7 @@ func (grp *Group) Use(args ...interface{}) Router {
 			panic(fmt.Sprintf("use: invalid handler %v\n", reflect.TypeOf(arg)))
 		}
 	}
-	grp.app.register(methodUse, getGroupPath(grp.Prefix, prefix), handlers...)
+	grp.app.register(methodUse, getGroupPath(grp.Prefix, prefix), grp, handlers...)
 	return grp
 }
 
@@ -102,59 +132,71 @@ func (grp *Group) Use(args ...interface{}) Router {
 // of the specified resource. Requests using GET should only retrieve data.
 func (grp *Group) Get(path string, handlers ...Handler) Router {
 	path = getGroupPath(grp.Prefix, path)
-	return grp.app.Add(MethodHead, path, handlers...).Add(MethodGet, path, handlers...)
+	grp.app.register(MethodHead, path, grp, handlers...)
+	return grp.app.register(MethodGet, path, grp, handlers...)
 }
 
 // Head registers a route for HEAD methods that asks for a response identical
 // to that of a GET request, but without the response body.
 func (grp *Group) Head(path string, handlers ...Handler) Router {
-	return grp.Add(MethodHead, path, handlers...)
+	return grp.app.register(MethodHead, getGroupPath(grp.Prefix, path), grp, handlers...)
 }
 
 // Post registers a route for POST methods that is used to submit an entity to the
 // specified resource, often causing a change in state or side effects on the server.
 func (grp *Group) Post(path string, handlers ...Handler) Router {
-	return grp.Add(MethodPost, path, handlers...)
+	return grp.app.register(MethodPost, getGroupPath(grp.Prefix, path), grp, handlers...)
 }
 
 // Put registers a route for PUT methods that replaces all current representations
 // of the target resource with the request payload.
 func (grp *Group) Put(path string, handlers ...Handler) Router {
-	return grp.Add(MethodPut, path, handlers...)
+	return grp.app.register(MethodPut, getGroupPath(grp.Prefix, path), grp, handlers...)
 }
 
 // Delete registers a route for DELETE methods that deletes the specified resource.
 func (grp *Group) Delete(path string, handlers ...Handler) Router {
-	return grp.Add(MethodDelete, path, handlers...)
+	return grp.app.register(MethodDelete, getGroupPath(grp.Prefix, path), grp, handlers...)
 }
 
 // Connect registers a route for CONNECT methods that establishes a tunnel to the
 // server identified by the target resource.
 func (grp *Group) Connect(path string, handlers ...Handler) Router {
-	return grp.Add(MethodConnect, path, handlers...)
+	return grp.app.register(MethodConnect, getGroupPath(grp.Prefix, path), grp, handlers...)
 }
 
 // Options registers a route for OPTIONS methods that is used to describe the
 // communication options for the target resource.
 func (grp *Group) Options(path string, handlers ...Handler) Router {
-	return grp.Add(MethodOptions, path, handlers...)
+	return grp.app.register(MethodOptions, getGroupPath(grp.Prefix, path), grp, handlers...)
 }
 
 // Trace registers a route for TRACE methods that performs a message loop-back
 // test along the path to the target resource.
 func (grp *Group) Trace(path string, handlers ...Handler) Router {
-	return grp.Add(MethodTrace, path, handlers...)
+	return grp.app.register(MethodTrace, getGroupPath(grp.Prefix, path), grp, handlers...)
 }
 
 // Patch registers a route for PATCH methods that is used to apply partial
 // modifications to a resource.
 func (grp *Group) Patch(path string, handlers ...Handler) Router {
-	return grp.Add(MethodPatch, path, handlers...)
+	return grp.app.register(MethodPatch, getGroupPath(grp.Prefix, path), grp, handlers...)
 }
 
-// Add allows you to specify a HTTP method to register a route
-func (grp *Group) Add(method, path string, handlers ...Handler) Router {
-	return grp.app.register(method, getGroupPath(grp.Prefix, path), handlers...)
+// Ws registers a GET route, which upgrades the requests to WebSocket connections
+func (grp *Group) Ws(path string, handler func(ws *WebSocket), config ...WebSocketConfig) Router {
+	return grp.app.register(MethodGet, getGroupPath(grp.Prefix, path), grp, func(c *Ctx) error {
+		return c.Upgrade(handler, config...)
+	})
+}
+
+// Add registers a route for the HTTP methods, see App.Add
+func (grp *Group) Add(methods []string, path string, handlers ...Handler) Router {
+	path = getGroupPath(grp.Prefix, path)
+	for _, method := range methods {
+		grp.app.register(method, path, grp, handlers...)
+	}
+	return grp.app
 }
 
 // Static will create a file server serving static files
@@ -162,11 +204,9 @@ func (grp *Group) Static(prefix, root string, config ...Static) Router {
 	return grp.app.registerStatic(getGroupPath(grp.Prefix, prefix), root, config...)
 }
 
-// All will register the handler on all HTTP methods
+// All will register the handler on all HTTP methods of Config.RequestMethods
 func (grp *Group) All(path string, handlers ...Handler) Router {
-	for _, method := range intMethod {
-		_ = grp.Add(method, path, handlers...)
-	}
+	_ = grp.Add(grp.app.config.RequestMethods, path, handlers...)
 	return grp
 }
 
@@ -177,9 +217,9 @@ func (grp *Group) All(path string, handlers ...Handler) Router {
 func (grp *Group) Group(prefix string, handlers ...Handler) Router {
 	prefix = getGroupPath(grp.Prefix, prefix)
 	if len(handlers) > 0 {
-		_ = grp.app.register(methodUse, prefix, handlers...)
+		_ = grp.app.register(methodUse, prefix, grp, handlers...)
 	}
-	return grp.app.Group(prefix)
+	return grp.app.newGroup(prefix, grp)
 }
 
 // Route is used to define routes with a common prefix inside the common function.
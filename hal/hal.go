@@ -0,0 +1,211 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+// Package hal decorates response payloads with the _links and _embedded
+// resources of HAL (https://stateless.group/hal_specification.html), the
+// links are built from the named routes of the app:
+//
+//	app.Get("/users/:id", handler).Name("user.show")
+//	app.Get("/users/:id/orders", handler).Name("user.orders")
+//
+//	return hal.New(c, user).
+//		Route("self", "user.show", fiber.Map{"id": user.ID}).
+//		Route("orders", "user.orders", fiber.Map{"id": user.ID}).
+//		Send()
+package hal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEApplicationHALJSON is the media type of HAL documents
+const MIMEApplicationHALJSON = "application/hal+json"
+
+// Link is a link object
+type Link struct {
+	Href      string `json:"href"`
+	Templated bool   `json:"templated,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// Resource is a payload with its links and embedded resources
+type Resource struct {
+	c        *fiber.Ctx
+	payload  interface{}
+	links    map[string][]Link
+	rels     []string // Order of the relations for the Link header
+	embedded map[string][]*Resource
+	// Relations encoded as array, see Links and EmbedList
+	linkLists  map[string]bool
+	embedLists map[string]bool
+	err        error
+}
+
+// New returns the resource of the payload, a struct, a map or nil. The links
+// are added by Link and Route, including self:
+//
+//	hal.New(c, user).Route("self", "user.show", fiber.Map{"id": user.ID})
+func New(c *fiber.Ctx, payload interface{}) *Resource {
+	return &Resource{c: c, payload: payload}
+}
+
+// Link adds a link of the relation, several links of the same relation are
+// encoded as array. Only the first link object is used if given.
+func (r *Resource) Link(rel, href string, link ...Link) *Resource {
+	l := Link{}
+	if len(link) > 0 {
+		l = link[0]
+	}
+	l.Href = href
+	if r.links == nil {
+		r.links = make(map[string][]Link)
+	}
+	if _, ok := r.links[rel]; !ok {
+		r.rels = append(r.rels, rel)
+	}
+	r.links[rel] = append(r.links[rel], l)
+	return r
+}
+
+// Links adds the links of the relation, which are encoded as array even if
+// there is only one, e.g. for a list of items
+func (r *Resource) Links(rel string, links ...Link) *Resource {
+	for _, l := range links {
+		r.Link(rel, l.Href, l)
+	}
+	if r.linkLists == nil {
+		r.linkLists = make(map[string]bool)
+	}
+	r.linkLists[rel] = true
+	return r
+}
+
+// Route adds the link of the relation to the named route with the params,
+// see fiber.Ctx.RouteURL. The first error is returned by MarshalJSON and Send.
+func (r *Resource) Route(rel, name string, params fiber.Map, link ...Link) *Resource {
+	href, err := r.c.RouteURL(name, params)
+	if err != nil {
+		if r.err == nil {
+			r.err = fmt.Errorf("hal: link %q: %w", rel, err)
+		}
+		return r
+	}
+	return r.Link(rel, href, link...)
+}
+
+// Embed embeds the resources of the relation, several resources of the same
+// relation are encoded as array
+func (r *Resource) Embed(rel string, resources ...*Resource) *Resource {
+	if r.embedded == nil {
+		r.embedded = make(map[string][]*Resource)
+	}
+	r.embedded[rel] = append(r.embedded[rel], resources...)
+	return r
+}
+
+// EmbedList embeds the resources of the relation, which are encoded as array
+// even if there is only one or none, e.g. for the items of a collection
+func (r *Resource) EmbedList(rel string, resources ...*Resource) *Resource {
+	r.Embed(rel, resources...)
+	if r.embedLists == nil {
+		r.embedLists = make(map[string]bool)
+	}
+	r.embedLists[rel] = true
+	return r
+}
+
+// MarshalJSON encodes the payload with the JSONEncoder of the app and adds the
+// _links and _embedded properties before its fields
+func (r *Resource) MarshalJSON() ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	var payload []byte
+	if r.payload != nil {
+		raw, err := r.c.App().Config().JSONEncoder(r.payload)
+		if err != nil {
+			return nil, err
+		}
+		raw = bytes.TrimSpace(raw)
+		if len(raw) < 2 || raw[0] != '{' || raw[len(raw)-1] != '}' {
+			return nil, fmt.Errorf("hal: payload %T isn't an object", r.payload)
+		}
+		payload = bytes.TrimSpace(raw[1 : len(raw)-1])
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	if len(r.links) > 0 {
+		links := make(map[string]interface{}, len(r.links))
+		for rel, l := range r.links {
+			if len(l) == 1 && !r.linkLists[rel] {
+				links[rel] = l[0]
+			} else {
+				links[rel] = l
+			}
+		}
+		raw, err := json.Marshal(links)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(`"_links":`)
+		buf.Write(raw)
+	}
+	if len(r.embedded) > 0 {
+		embedded := make(map[string]interface{}, len(r.embedded))
+		for rel, resources := range r.embedded {
+			if len(resources) == 1 && !r.embedLists[rel] {
+				embedded[rel] = resources[0]
+			} else {
+				embedded[rel] = resources
+			}
+		}
+		raw, err := json.Marshal(embedded)
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`"_embedded":`)
+		buf.Write(raw)
+	}
+	if len(payload) > 0 {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(payload)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// LinkHeader sets the links of the resource as Link header, see
+// fiber.Ctx.Links, e.g. for clients which don't read the body
+func (r *Resource) LinkHeader() *Resource {
+	pairs := make([]string, 0, 2*len(r.links))
+	for _, rel := range r.rels {
+		for _, l := range r.links[rel] {
+			pairs = append(pairs, l.Href, rel)
+		}
+	}
+	r.c.Links(pairs...)
+	return r
+}
+
+// Send responds with the resource as application/hal+json
+func (r *Resource) Send() error {
+	raw, err := r.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	r.c.Set(fiber.HeaderContentType, MIMEApplicationHALJSON)
+	return r.c.Send(raw)
+}
@@ -0,0 +1,82 @@
+package hal
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type order struct {
+	ID    int `json:"id"`
+	Total int `json:"total"`
+}
+
+func newApp() *fiber.App {
+	app := fiber.New()
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		u := user{ID: 1, Name: "john"}
+		orders := make([]*Resource, 0, 1)
+		for _, o := range []order{{ID: 7, Total: 30}} {
+			orders = append(orders, New(c, o).Route("self", "order.show", fiber.Map{"id": o.ID}))
+		}
+		return New(c, u).
+			Route("self", "user.show", fiber.Map{"id": u.ID}).
+			Route("orders", "user.orders", fiber.Map{"id": u.ID, "page": 2}).
+			Links("curies", Link{Href: "/docs/{rel}", Name: "doc", Templated: true}).
+			EmbedList("orders", orders...).
+			Embed("manager", New(c, user{ID: 2, Name: "jane"})).
+			LinkHeader().
+			Send()
+	}).Name("user.show")
+	app.Get("/users/:id/orders", func(c *fiber.Ctx) error {
+		return New(c, nil).Route("user", "user.missing", nil).Send()
+	}).Name("user.orders")
+	app.Get("/orders/:id", func(c *fiber.Ctx) error {
+		return New(c, []int{1}).Send()
+	}).Name("order.show")
+	return app
+}
+
+// go test -run Test_Resource
+func Test_Resource(t *testing.T) {
+	t.Parallel()
+	app := newApp()
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/users/1", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, MIMEApplicationHALJSON, resp.Header.Get(fiber.HeaderContentType))
+	utils.AssertEqual(t, `</users/1>; rel="self",</users/1/orders?page=2>; rel="orders",</docs/{rel}>; rel="curies"`, resp.Header.Get(fiber.HeaderLink))
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `{"_links":{"curies":[{"href":"/docs/{rel}","templated":true,"name":"doc"}],"orders":{"href":"/users/1/orders?page=2"},"self":{"href":"/users/1"}},`+
+		`"_embedded":{"manager":{"id":2,"name":"jane"},"orders":[{"_links":{"self":{"href":"/orders/7"}},"id":7,"total":30}]},`+
+		`"id":1,"name":"john"}`, string(body))
+}
+
+// go test -run Test_Resource_Errors
+func Test_Resource_Errors(t *testing.T) {
+	t.Parallel()
+	app := newApp()
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/users/1/orders", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `hal: link "user": route "user.missing" not found`, string(body))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/orders/7", nil))
+	utils.AssertEqual(t, nil, err)
+	body, err = ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `hal: payload []int isn't an object`, string(body))
+}
@@ -79,7 +79,8 @@ func (app *App) quoteString(raw string) string {
 
 // Scan stack if other methods match the request
 func methodExist(ctx *Ctx) (exist bool) {
-	for i := 0; i < len(intMethod); i++ {
+	methods := ctx.app.config.RequestMethods
+	for i := 0; i < len(methods); i++ {
 		// Skip original method
 		if ctx.methodINT == i {
 			continue
@@ -109,7 +110,7 @@ func methodExist(ctx *Ctx) (exist bool) {
 				// We matched
 				exist = true
 				// Add method to Allow header
-				ctx.Append(HeaderAllow, intMethod[i])
+				ctx.Append(HeaderAllow, methods[i])
 				// Break stack loop
 				break
 			}
@@ -150,18 +151,25 @@ func setETag(c *Ctx, weak bool) {
 	if c.fasthttp.Response.StatusCode() != StatusOK {
 		return
 	}
-	body := c.fasthttp.Response.Body()
-	// Skips ETag if no response body is present
-	if len(body) == 0 {
-		return
+	var etag string
+	if c.fasthttp.Response.IsBodyStream() {
+		// Don't buffer the stream, e.g. a file
+		if etag = streamETag(c); etag == "" {
+			return
+		}
+	} else {
+		body := c.fasthttp.Response.Body()
+		// Skips ETag if no response body is present
+		if len(body) == 0 {
+			return
+		}
+		// Generate ETag for response
+		crc32q := crc32.MakeTable(0xD5828281)
+		etag = fmt.Sprintf("\"%d-%v\"", len(body), crc32.Checksum(body, crc32q))
 	}
 	// Get ETag header from request
 	clientEtag := c.Get(HeaderIfNoneMatch)
 
-	// Generate ETag for response
-	crc32q := crc32.MakeTable(0xD5828281)
-	etag := fmt.Sprintf("\"%d-%v\"", len(body), crc32.Checksum(body, crc32q))
-
 	// Enable weak tag
 	if weak {
 		etag = "W/" + etag
@@ -330,8 +338,24 @@ var getBytesImmutable = func(s string) (b []byte) {
 	return []byte(s)
 }
 
-// HTTP methods and their unique INTs
-func methodInt(s string) int {
+// methodInt returns the index of the HTTP method in the methods of the app,
+// -1 if the app doesn't route it
+func (app *App) methodInt(s string) int {
+	methods := app.config.RequestMethods
+	if i := defaultMethodInt(s); i != -1 && i < len(methods) && methods[i] == s {
+		return i
+	}
+	// Custom methods or a custom order
+	for i, method := range methods {
+		if method == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// defaultMethodInt returns the index of the HTTP method in DefaultMethods
+func defaultMethodInt(s string) int {
 	switch s {
 	case MethodGet:
 		return 0
@@ -356,8 +380,9 @@ func methodInt(s string) int {
 	}
 }
 
-// HTTP methods slice
-var intMethod = []string{
+// DefaultMethods are the HTTP methods, which the apps route by default,
+// see Config.RequestMethods
+var DefaultMethods = []string{
 	MethodGet,
 	MethodHead,
 	MethodPost,
@@ -388,9 +413,13 @@ const (
 	MIMETextXML               = "text/xml"
 	MIMETextHTML              = "text/html"
 	MIMETextPlain             = "text/plain"
+	MIMETextEventStream       = "text/event-stream"
 	MIMEApplicationXML        = "application/xml"
 	MIMEApplicationJSON       = "application/json"
 	MIMEApplicationJavaScript = "application/javascript"
+	MIMEApplicationMsgPack    = "application/msgpack"
+	MIMEApplicationProtobuf   = "application/x-protobuf"
+	MIMEApplicationCBOR       = "application/cbor"
 	MIMEApplicationForm       = "application/x-www-form-urlencoded"
 	MIMEOctetStream           = "application/octet-stream"
 	MIMEMultipartForm         = "multipart/form-data"
@@ -515,84 +544,89 @@ var (
 
 // HTTP Headers were copied from net/http.
 const (
-	HeaderAuthorization                   = "Authorization"
-	HeaderProxyAuthenticate               = "Proxy-Authenticate"
-	HeaderProxyAuthorization              = "Proxy-Authorization"
-	HeaderWWWAuthenticate                 = "WWW-Authenticate"
-	HeaderAge                             = "Age"
-	HeaderCacheControl                    = "Cache-Control"
-	HeaderClearSiteData                   = "Clear-Site-Data"
-	HeaderExpires                         = "Expires"
-	HeaderPragma                          = "Pragma"
-	HeaderWarning                         = "Warning"
-	HeaderAcceptCH                        = "Accept-CH"
-	HeaderAcceptCHLifetime                = "Accept-CH-Lifetime"
-	HeaderContentDPR                      = "Content-DPR"
-	HeaderDPR                             = "DPR"
-	HeaderEarlyData                       = "Early-Data"
-	HeaderSaveData                        = "Save-Data"
-	HeaderViewportWidth                   = "Viewport-Width"
-	HeaderWidth                           = "Width"
-	HeaderETag                            = "ETag"
-	HeaderIfMatch                         = "If-Match"
-	HeaderIfModifiedSince                 = "If-Modified-Since"
-	HeaderIfNoneMatch                     = "If-None-Match"
-	HeaderIfUnmodifiedSince               = "If-Unmodified-Since"
-	HeaderLastModified                    = "Last-Modified"
-	HeaderVary                            = "Vary"
-	HeaderConnection                      = "Connection"
-	HeaderKeepAlive                       = "Keep-Alive"
-	HeaderAccept                          = "Accept"
-	HeaderAcceptCharset                   = "Accept-Charset"
-	HeaderAcceptEncoding                  = "Accept-Encoding"
-	HeaderAcceptLanguage                  = "Accept-Language"
-	HeaderCookie                          = "Cookie"
-	HeaderExpect                          = "Expect"
-	HeaderMaxForwards                     = "Max-Forwards"
-	HeaderSetCookie                       = "Set-Cookie"
-	HeaderAccessControlAllowCredentials   = "Access-Control-Allow-Credentials"
-	HeaderAccessControlAllowHeaders       = "Access-Control-Allow-Headers"
-	HeaderAccessControlAllowMethods       = "Access-Control-Allow-Methods"
-	HeaderAccessControlAllowOrigin        = "Access-Control-Allow-Origin"
-	HeaderAccessControlExposeHeaders      = "Access-Control-Expose-Headers"
-	HeaderAccessControlMaxAge             = "Access-Control-Max-Age"
-	HeaderAccessControlRequestHeaders     = "Access-Control-Request-Headers"
-	HeaderAccessControlRequestMethod      = "Access-Control-Request-Method"
-	HeaderOrigin                          = "Origin"
-	HeaderTimingAllowOrigin               = "Timing-Allow-Origin"
-	HeaderXPermittedCrossDomainPolicies   = "X-Permitted-Cross-Domain-Policies"
-	HeaderDNT                             = "DNT"
-	HeaderTk                              = "Tk"
-	HeaderContentDisposition              = "Content-Disposition"
-	HeaderContentEncoding                 = "Content-Encoding"
-	HeaderContentLanguage                 = "Content-Language"
-	HeaderContentLength                   = "Content-Length"
-	HeaderContentLocation                 = "Content-Location"
-	HeaderContentType                     = "Content-Type"
-	HeaderForwarded                       = "Forwarded"
-	HeaderVia                             = "Via"
-	HeaderXForwardedFor                   = "X-Forwarded-For"
-	HeaderXForwardedHost                  = "X-Forwarded-Host"
-	HeaderXForwardedProto                 = "X-Forwarded-Proto"
-	HeaderXForwardedProtocol              = "X-Forwarded-Protocol"
-	HeaderXForwardedSsl                   = "X-Forwarded-Ssl"
-	HeaderXUrlScheme                      = "X-Url-Scheme"
-	HeaderLocation                        = "Location"
-	HeaderFrom                            = "From"
-	HeaderHost                            = "Host"
-	HeaderReferer                         = "Referer"
-	HeaderReferrerPolicy                  = "Referrer-Policy"
-	HeaderUserAgent                       = "User-Agent"
-	HeaderAllow                           = "Allow"
-	HeaderServer                          = "Server"
-	HeaderAcceptRanges                    = "Accept-Ranges"
-	HeaderContentRange                    = "Content-Range"
-	HeaderIfRange                         = "If-Range"
-	HeaderRange                           = "Range"
-	HeaderContentSecurityPolicy           = "Content-Security-Policy"
-	HeaderContentSecurityPolicyReportOnly = "Content-Security-Policy-Report-Only"
-	HeaderCrossOriginResourcePolicy       = "Cross-Origin-Resource-Policy"
-	HeaderExpectCT                        = "Expect-CT"
+	HeaderAuthorization                      = "Authorization"
+	HeaderProxyAuthenticate                  = "Proxy-Authenticate"
+	HeaderProxyAuthorization                 = "Proxy-Authorization"
+	HeaderWWWAuthenticate                    = "WWW-Authenticate"
+	HeaderAge                                = "Age"
+	HeaderCacheControl                       = "Cache-Control"
+	HeaderClearSiteData                      = "Clear-Site-Data"
+	HeaderExpires                            = "Expires"
+	HeaderPragma                             = "Pragma"
+	HeaderWarning                            = "Warning"
+	HeaderAcceptCH                           = "Accept-CH"
+	HeaderAcceptCHLifetime                   = "Accept-CH-Lifetime"
+	HeaderContentDPR                         = "Content-DPR"
+	HeaderDPR                                = "DPR"
+	HeaderEarlyData                          = "Early-Data"
+	HeaderSaveData                           = "Save-Data"
+	HeaderViewportWidth                      = "Viewport-Width"
+	HeaderWidth                              = "Width"
+	HeaderETag                               = "ETag"
+	HeaderIfMatch                            = "If-Match"
+	HeaderIfModifiedSince                    = "If-Modified-Since"
+	HeaderIfNoneMatch                        = "If-None-Match"
+	HeaderIfUnmodifiedSince                  = "If-Unmodified-Since"
+	HeaderLastModified                       = "Last-Modified"
+	HeaderVary                               = "Vary"
+	HeaderConnection                         = "Connection"
+	HeaderKeepAlive                          = "Keep-Alive"
+	HeaderAccept                             = "Accept"
+	HeaderAcceptCharset                      = "Accept-Charset"
+	HeaderAcceptEncoding                     = "Accept-Encoding"
+	HeaderAcceptLanguage                     = "Accept-Language"
+	HeaderCookie                             = "Cookie"
+	HeaderExpect                             = "Expect"
+	HeaderMaxForwards                        = "Max-Forwards"
+	HeaderSetCookie                          = "Set-Cookie"
+	HeaderAccessControlAllowCredentials      = "Access-Control-Allow-Credentials"
+	HeaderAccessControlAllowHeaders          = "Access-Control-Allow-Headers"
+	HeaderAccessControlAllowMethods          = "Access-Control-Allow-Methods"
+	HeaderAccessControlAllowOrigin           = "Access-Control-Allow-Origin"
+	HeaderAccessControlAllowPrivateNetwork   = "Access-Control-Allow-Private-Network"
+	HeaderAccessControlExposeHeaders         = "Access-Control-Expose-Headers"
+	HeaderAccessControlMaxAge                = "Access-Control-Max-Age"
+	HeaderAccessControlRequestHeaders        = "Access-Control-Request-Headers"
+	HeaderAccessControlRequestMethod         = "Access-Control-Request-Method"
+	HeaderAccessControlRequestPrivateNetwork = "Access-Control-Request-Private-Network"
+	HeaderOrigin                             = "Origin"
+	HeaderTimingAllowOrigin                  = "Timing-Allow-Origin"
+	HeaderXPermittedCrossDomainPolicies      = "X-Permitted-Cross-Domain-Policies"
+	HeaderDNT                                = "DNT"
+	HeaderTk                                 = "Tk"
+	HeaderContentDisposition                 = "Content-Disposition"
+	HeaderContentEncoding                    = "Content-Encoding"
+	HeaderContentLanguage                    = "Content-Language"
+	HeaderContentLength                      = "Content-Length"
+	HeaderContentLocation                    = "Content-Location"
+	HeaderContentType                        = "Content-Type"
+	HeaderForwarded                          = "Forwarded"
+	HeaderVia                                = "Via"
+	HeaderXForwardedFor                      = "X-Forwarded-For"
+	HeaderXForwardedHost                     = "X-Forwarded-Host"
+	HeaderXForwardedProto                    = "X-Forwarded-Proto"
+	HeaderXForwardedProtocol                 = "X-Forwarded-Protocol"
+	HeaderXForwardedSsl                      = "X-Forwarded-Ssl"
+	HeaderXUrlScheme                         = "X-Url-Scheme"
+	HeaderLocation                           = "Location"
+	HeaderFrom                               = "From"
+	HeaderHost                               = "Host"
+	HeaderReferer                            = "Referer"
+	HeaderReferrerPolicy                     = "Referrer-Policy"
+	HeaderUserAgent                          = "User-Agent"
+	HeaderAllow                              = "Allow"
+	HeaderServer                             = "Server"
+	HeaderAcceptRanges                       = "Accept-Ranges"
+	HeaderContentRange                       = "Content-Range"
+	HeaderIfRange                            = "If-Range"
+	HeaderRange                              = "Range"
+	HeaderContentSecurityPolicy              = "Content-Security-Policy"
+	HeaderContentSecurityPolicyReportOnly    = "Content-Security-Policy-Report-Only"
+	HeaderCrossOriginResourcePolicy          = "Cross-Origin-Resource-Policy"
+	HeaderCrossOriginOpenerPolicy            = "Cross-Origin-Opener-Policy"
+	HeaderCrossOriginEmbedderPolicy          = "Cross-Origin-Embedder-Policy"
+	HeaderOriginAgentCluster                 = "Origin-Agent-Cluster"
+	HeaderExpectCT                           = "Expect-CT"
 	// Deprecated: use HeaderPermissionsPolicy instead
 	HeaderFeaturePolicy           = "Feature-Policy"
 	HeaderPermissionsPolicy       = "Permissions-Policy"
@@ -609,6 +643,8 @@ const (
 	HeaderNEL                     = "NEL"
 	HeaderPingFrom                = "Ping-From"
 	HeaderPingTo                  = "Ping-To"
+	HeaderPrefer                  = "Prefer"
+	HeaderPreferenceApplied       = "Preference-Applied"
 	HeaderReportTo                = "Report-To"
 	HeaderTE                      = "TE"
 	HeaderTrailer                 = "Trailer"
@@ -618,6 +654,10 @@ const (
 	HeaderSecWebSocketKey         = "Sec-WebSocket-Key"
 	HeaderSecWebSocketProtocol    = "Sec-WebSocket-Protocol"
 	HeaderSecWebSocketVersion     = "Sec-WebSocket-Version"
+	HeaderSecFetchDest            = "Sec-Fetch-Dest"
+	HeaderSecFetchMode            = "Sec-Fetch-Mode"
+	HeaderSecFetchSite            = "Sec-Fetch-Site"
+	HeaderSecFetchUser            = "Sec-Fetch-User"
 	HeaderAcceptPatch             = "Accept-Patch"
 	HeaderAcceptPushPolicy        = "Accept-Push-Policy"
 	HeaderAcceptSignature         = "Accept-Signature"
@@ -639,6 +679,9 @@ const (
 	HeaderXRequestedWith          = "X-Requested-With"
 	HeaderXRobotsTag              = "X-Robots-Tag"
 	HeaderXUACompatible           = "X-UA-Compatible"
+	HeaderTraceParent             = "Traceparent"
+	HeaderTraceState              = "Tracestate"
+	HeaderBaggage                 = "Baggage"
 )
 
 // Network types that are commonly used
@@ -8,6 +8,18 @@ type OnGroupNameHandler = OnGroupHandler
 type OnListenHandler = func() error
 type OnShutdownHandler = OnListenHandler
 type OnForkHandler = func(int) error
+type OnListenDataHandler = func(ListenData) error
+type OnMountHandler = func(parent *App) error
+
+// ListenData is the resolved address of the listener, which is passed to the
+// OnListenData hooks, e.g. to register the service in a discovery.
+type ListenData struct {
+	Host string
+	Port string
+	TLS  bool
+	// Prefork is set in the child processes of the prefork mode
+	Prefork bool
+}
 
 // Hooks is a struct to use it with App.
 type Hooks struct {
@@ -15,25 +27,29 @@ type Hooks struct {
 	app *App
 
 	// Hooks
-	onRoute     []OnRouteHandler
-	onName      []OnNameHandler
-	onGroup     []OnGroupHandler
-	onGroupName []OnGroupNameHandler
-	onListen    []OnListenHandler
-	onShutdown  []OnShutdownHandler
-	onFork      []OnForkHandler
+	onRoute      []OnRouteHandler
+	onName       []OnNameHandler
+	onGroup      []OnGroupHandler
+	onGroupName  []OnGroupNameHandler
+	onListen     []OnListenHandler
+	onShutdown   []OnShutdownHandler
+	onFork       []OnForkHandler
+	onListenData []OnListenDataHandler
+	onMount      []OnMountHandler
 }
 
 func newHooks(app *App) *Hooks {
 	return &Hooks{
-		app:         app,
-		onRoute:     make([]OnRouteHandler, 0),
-		onGroup:     make([]OnGroupHandler, 0),
-		onGroupName: make([]OnGroupNameHandler, 0),
-		onName:      make([]OnNameHandler, 0),
-		onListen:    make([]OnListenHandler, 0),
-		onShutdown:  make([]OnShutdownHandler, 0),
-		onFork:      make([]OnForkHandler, 0),
+		app:          app,
+		onRoute:      make([]OnRouteHandler, 0),
+		onGroup:      make([]OnGroupHandler, 0),
+		onGroupName:  make([]OnGroupNameHandler, 0),
+		onName:       make([]OnNameHandler, 0),
+		onListen:     make([]OnListenHandler, 0),
+		onShutdown:   make([]OnShutdownHandler, 0),
+		onFork:       make([]OnForkHandler, 0),
+		onListenData: make([]OnListenDataHandler, 0),
+		onMount:      make([]OnMountHandler, 0),
 	}
 }
 
@@ -94,6 +110,23 @@ func (h *Hooks) OnFork(handler ...OnForkHandler) {
 	h.app.mutex.Unlock()
 }
 
+// OnListenData is a hook to execute user functions with the address of the
+// listener, once Listen, ListenTLS, ListenMutualTLS or Listener starts serving,
+// also in the child processes of prefork. Listen returns the error of a hook.
+func (h *Hooks) OnListenData(handler ...OnListenDataHandler) {
+	h.app.mutex.Lock()
+	h.onListenData = append(h.onListenData, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnMount is a hook to execute user functions once the app is mounted on
+// another app, the parent. Also you can get the path of the app by MountPath.
+func (h *Hooks) OnMount(handler ...OnMountHandler) {
+	h.app.mutex.Lock()
+	h.onMount = append(h.onMount, handler...)
+	h.app.mutex.Unlock()
+}
+
 func (h *Hooks) executeOnRouteHooks(route Route) error {
 	for _, v := range h.onRoute {
 		if err := v(route); err != nil {
@@ -144,6 +177,26 @@ func (h *Hooks) executeOnListenHooks() error {
 	return nil
 }
 
+func (h *Hooks) executeOnListenDataHooks(data ListenData) error {
+	for _, v := range h.onListenData {
+		if err := v(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Hooks) executeOnMountHooks(parent *App) error {
+	for _, v := range h.onMount {
+		if err := v(parent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (h *Hooks) executeOnShutdownHooks() {
 	for _, v := range h.onShutdown {
 		_ = v()
@@ -1,8 +1,10 @@
 package fiber
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"testing"
 	"time"
 
@@ -136,6 +138,43 @@ func Test_Hook_OnGroupName_Error(t *testing.T) {
 	grp.Get("/test", testSimpleHandler)
 }
 
+// go test -run Test_Hook_Group
+func Test_Hook_Group(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+
+	var routes, names, groups, groupNames []string
+	api := app.Group("/api").(*Group)
+	api.Hooks().OnRoute(func(r Route) error {
+		routes = append(routes, r.Method+" "+r.Path)
+		return nil
+	})
+	api.Hooks().OnName(func(r Route) error {
+		names = append(names, r.Name)
+		return nil
+	})
+	api.Hooks().OnGroup(func(g Group) error {
+		groups = append(groups, g.Prefix)
+		return nil
+	})
+	api.Hooks().OnGroupName(func(g Group) error {
+		groupNames = append(groupNames, g.name)
+		return nil
+	})
+
+	app.Post("/login", testSimpleHandler).Name("login")
+	app.Group("/web").Name("web.")
+	api.Post("/users", testSimpleHandler).Name("users")
+	v1 := api.Group("/v1").Name("v1.")
+	v1.Delete("/users/:id", testSimpleHandler).Name("user")
+
+	utils.AssertEqual(t, []string{"POST /api/users", "DELETE /api/v1/users/:id"}, routes)
+	utils.AssertEqual(t, []string{"users", "v1.user"}, names)
+	utils.AssertEqual(t, []string{"/api/v1"}, groups)
+	utils.AssertEqual(t, []string{"v1."}, groupNames)
+}
+
 func Test_Hook_OnShutdown(t *testing.T) {
 	t.Parallel()
 
@@ -181,6 +220,68 @@ func Test_Hook_OnListen(t *testing.T) {
 	utils.AssertEqual(t, "ready", buf.String())
 }
 
+func Test_Hook_OnListenData(t *testing.T) {
+	t.Parallel()
+
+	app := New(Config{
+		DisableStartupMessage: true,
+	})
+
+	var data []ListenData
+	app.Hooks().OnListenData(func(d ListenData) error {
+		data = append(data, d)
+		// Stops listening
+		return errors.New("registration failed")
+	})
+
+	ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+	utils.AssertEqual(t, nil, err)
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "registration failed", app.Listener(ln).Error())
+
+	ln, err = net.Listen(NetworkTCP4, "127.0.0.1:0")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "registration failed", app.Listener(tls.NewListener(ln, &tls.Config{})).Error())
+
+	utils.AssertEqual(t, 2, len(data))
+	utils.AssertEqual(t, ListenData{Host: "127.0.0.1", Port: port}, data[0])
+	utils.AssertEqual(t, true, data[1].TLS)
+}
+
+func Test_Hook_OnMount(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+	sub := New()
+
+	var mounted *App
+	sub.Hooks().OnMount(func(parent *App) error {
+		mounted = parent
+		utils.AssertEqual(t, "/api/v1", sub.MountPath())
+		return nil
+	})
+
+	app.Group("/api").Mount("/v1", sub)
+	utils.AssertEqual(t, app, mounted)
+}
+
+func Test_Hook_OnMount_Error(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+	sub := New()
+	defer func() {
+		utils.AssertEqual(t, "unknown error", fmt.Sprintf("%v", recover()))
+	}()
+
+	sub.Hooks().OnMount(func(parent *App) error {
+		return errors.New("unknown error")
+	})
+
+	app.Mount("/sub", sub)
+}
+
 func Test_Hook_OnHook(t *testing.T) {
 	// Reset test var
 	testPreforkMaster = true
@@ -0,0 +1,58 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"html/template"
+	"sync"
+
+	"github.com/valyala/bytebufferpool"
+)
+
+// htmlTemplates caches the parsed templates of SendHTMLSafe by their text
+var htmlTemplates sync.Map
+
+// SendHTMLSafe renders the html/template snippet with the data and sends it
+// with the text/html content type. Unlike building HTML with fmt.Sprintf,
+// the values are escaped according to their context, i.e. HTML text,
+// attributes, URLs, JavaScript and CSS, so that user input can't inject markup:
+//
+//	c.SendHTMLSafe(`<a href="/users/{{.ID}}" title="{{.Name}}">{{.Name}}</a>`, user)
+//
+// The parsed templates are cached by their text, so tmpl should be a constant.
+// Use a Views engine and Render for full pages.
+func (c *Ctx) SendHTMLSafe(tmpl string, data interface{}) error {
+	t, err := htmlTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+
+	// Get new buffer from pool
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+
+	if err = t.Execute(buf, data); err != nil {
+		return err
+	}
+
+	// Set Content-Type to text/html
+	c.fasthttp.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
+	// Set rendered template to body
+	c.fasthttp.Response.SetBody(buf.Bytes())
+	return nil
+}
+
+// htmlTemplate returns the cached template for the text or parses it
+func htmlTemplate(text string) (*template.Template, error) {
+	if t, ok := htmlTemplates.Load(text); ok {
+		return t.(*template.Template), nil
+	}
+	t, err := template.New("").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := htmlTemplates.LoadOrStore(text, t)
+	return actual.(*template.Template), nil
+}
@@ -0,0 +1,56 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Ctx_SendHTMLSafe
+func Test_Ctx_SendHTMLSafe(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	data := Map{
+		"Name": `<script>alert("xss")</script>`,
+		"URL":  `javascript:alert(1)`,
+	}
+	err := c.SendHTMLSafe(`<a href="{{.URL}}" title="{{.Name}}">{{.Name}}</a>`, data)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, MIMETextHTMLCharsetUTF8, string(c.Response().Header.ContentType()))
+	utils.AssertEqual(t,
+		`<a href="#ZgotmplZ" title="&lt;script&gt;alert(&#34;xss&#34;)&lt;/script&gt;">&lt;script&gt;alert(&#34;xss&#34;)&lt;/script&gt;</a>`,
+		string(c.Response().Body()))
+
+	// Values in scripts are encoded as JavaScript
+	err = c.SendHTMLSafe(`<script>var name = {{.Name}};</script>`, data)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `<script>var name = "\u003cscript\u003ealert(\"xss\")\u003c/script\u003e";</script>`, string(c.Response().Body()))
+
+	// Invalid templates return the parse error
+	err = c.SendHTMLSafe(`<p>{{.Name</p>`, data)
+	utils.AssertEqual(t, true, err != nil)
+}
+
+// go test -v -run=^$ -bench=Benchmark_Ctx_SendHTMLSafe -benchmem -count=4
+func Benchmark_Ctx_SendHTMLSafe(b *testing.B) {
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	data := Map{"Name": "<b>john</b>"}
+	var err error
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		err = c.SendHTMLSafe(`<p>Hello {{.Name}}</p>`, data)
+	}
+	utils.AssertEqual(b, nil, err)
+	utils.AssertEqual(b, "<p>Hello &lt;b&gt;john&lt;/b&gt;</p>", string(c.Response().Body()))
+}
@@ -0,0 +1,104 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+// Package i18n registers decoders for the language tags and currency units of
+// golang.org/x/text at the parsers, so i18n-sensitive params are validated at
+// bind time.
+package i18n
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// Config defines the config for Register.
+type Config struct {
+	// Languages are the supported languages. A language.Tag field gets the
+	// supported language, which matches the value or one of its parents,
+	// e.g. "en" for "en-US". Other languages are rejected.
+	//
+	// Optional. Default: nil, all well-formed BCP 47 tags are accepted
+	Languages []language.Tag
+
+	// Currencies are the supported currencies, other currencies are rejected.
+	//
+	// Optional. Default: nil, all ISO 4217 currency codes are accepted
+	Currencies []currency.Unit
+}
+
+var (
+	tagType  = reflect.TypeOf(language.Tag{})
+	unitType = reflect.TypeOf(currency.Unit{})
+)
+
+// Register registers the decoders of language.Tag and currency.Unit fields at
+// BodyParser, QueryParser, ReqHeaderParser and ParamsParser:
+//
+//	i18n.Register(i18n.Config{
+//	    Languages: []language.Tag{language.English, language.German},
+//	})
+//
+//	type Query struct {
+//	    Lang     language.Tag  `query:"lang"`     // "de-AT" => de
+//	    Currency currency.Unit `query:"currency"` // "eur" => EUR
+//	}
+//
+// Like fiber.RegisterDecoder, it's meant to be called during initialization.
+func Register(config ...Config) {
+	var cfg Config
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	fiber.RegisterDecoder(tagType, func(s string) (interface{}, error) {
+		return parseLanguage(s, cfg.Languages)
+	})
+	fiber.RegisterDecoder(unitType, func(s string) (interface{}, error) {
+		return parseCurrency(s, cfg.Currencies)
+	})
+}
+
+// parseLanguage parses the tag and returns the supported language it belongs to
+func parseLanguage(s string, supported []language.Tag) (language.Tag, error) {
+	tag, err := language.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return language.Und, err
+	}
+	if len(supported) == 0 {
+		return tag, nil
+	}
+	for t := tag; ; t = t.Parent() {
+		for _, lang := range supported {
+			if t == lang {
+				return lang, nil
+			}
+		}
+		if t.IsRoot() {
+			break
+		}
+	}
+	return language.Und, fmt.Errorf("i18n: unsupported language %q", s)
+}
+
+// parseCurrency parses the ISO 4217 code and checks it's supported
+func parseCurrency(s string, supported []currency.Unit) (currency.Unit, error) {
+	unit, err := currency.ParseISO(strings.TrimSpace(s))
+	if err != nil {
+		return currency.Unit{}, err
+	}
+	if len(supported) == 0 {
+		return unit, nil
+	}
+	for _, u := range supported {
+		if u == unit {
+			return unit, nil
+		}
+	}
+	return currency.Unit{}, fmt.Errorf("i18n: unsupported currency %q", s)
+}
@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// go test -run Test_Register
+func Test_Register(t *testing.T) {
+	Register(Config{
+		Languages:  []language.Tag{language.English, language.German, language.MustParse("pt-BR")},
+		Currencies: []currency.Unit{currency.EUR, currency.USD},
+	})
+
+	app := fiber.New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Query struct {
+		Lang     language.Tag    `query:"lang"`
+		Accept   []language.Tag  `query:"accept"`
+		Currency currency.Unit   `query:"currency"`
+		Prices   []currency.Unit `query:"prices"`
+	}
+
+	q := new(Query)
+	c.Request().URI().SetQueryString("lang=de-AT&accept=en-US,pt-BR&currency=eur&prices=USD")
+	utils.AssertEqual(t, nil, c.QueryParser(q))
+	utils.AssertEqual(t, language.German, q.Lang)
+	utils.AssertEqual(t, []language.Tag{language.English, language.MustParse("pt-BR")}, q.Accept)
+	utils.AssertEqual(t, currency.EUR, q.Currency)
+	utils.AssertEqual(t, []currency.Unit{currency.USD}, q.Prices)
+
+	c.Request().URI().SetQueryString("lang=fr&accept=pt-PT&currency=JPY&prices=XYZ")
+	err := c.QueryParser(new(Query))
+	var bindErr *fiber.BindError
+	utils.AssertEqual(t, true, errors.As(err, &bindErr))
+	utils.AssertEqual(t, 4, len(bindErr.Fields), err.Error())
+	utils.AssertEqual(t, "accept", bindErr.Fields[0].Field)
+	utils.AssertEqual(t, "currency", bindErr.Fields[1].Field)
+	utils.AssertEqual(t, "lang", bindErr.Fields[2].Field)
+	utils.AssertEqual(t, "fr", bindErr.Fields[2].Value)
+	utils.AssertEqual(t, "prices", bindErr.Fields[3].Field)
+}
+
+// go test -run Test_ParseLanguage
+func Test_ParseLanguage(t *testing.T) {
+	t.Parallel()
+	tag, err := parseLanguage(" en-GB ", nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, language.BritishEnglish, tag)
+
+	_, err = parseLanguage("not a tag", nil)
+	utils.AssertEqual(t, true, err != nil)
+
+	_, err = parseLanguage("en", []language.Tag{language.AmericanEnglish})
+	utils.AssertEqual(t, `i18n: unsupported language "en"`, err.Error())
+}
+
+// go test -run Test_ParseCurrency
+func Test_ParseCurrency(t *testing.T) {
+	t.Parallel()
+	unit, err := parseCurrency("chf", nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, currency.CHF, unit)
+
+	_, err = parseCurrency("EURO", nil)
+	utils.AssertEqual(t, true, err != nil)
+
+	_, err = parseCurrency("CHF", []currency.Unit{currency.EUR})
+	utils.AssertEqual(t, `i18n: unsupported currency "CHF"`, err.Error())
+}
@@ -0,0 +1,179 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"crypto/md5"
+	crand "crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Formats of the IDs generated by NewID and Ctx.NewID, see Config.IDFormat
+const (
+	// IDFormatUUIDv7 is a time-ordered UUID, see RFC 9562, e.g.
+	// "01890a5d-ac96-774b-bcce-b302099a8057"
+	IDFormatUUIDv7 = "uuidv7"
+	// IDFormatXID is a 20 characters long xid, see https://github.com/rs/xid,
+	// e.g. "9m4e2mr0ui3e8a215n4g"
+	IDFormatXID = "xid"
+	// IDFormatULID is a 26 characters long ULID, see https://github.com/ulid/spec,
+	// e.g. "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	IDFormatULID = "ulid"
+)
+
+// ulidAlphabet is Crockford's base32
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var (
+	xidEncoding = base32.NewEncoding("0123456789abcdefghijklmnopqrstuv").WithPadding(base32.NoPadding)
+
+	xidSetup   sync.Once
+	xidMachine [3]byte
+	xidPid     uint16
+	xidCounter uint32
+)
+
+// NewID returns a new random ID in the format, UUIDv7 by default. All
+// middleware of an app should use Ctx.NewID instead, whose format is set by
+// Config.IDFormat, so that the IDs in the logs are consistent.
+func NewID(format ...string) string {
+	f := IDFormatUUIDv7
+	if len(format) > 0 {
+		f = format[0]
+	}
+	return newID(f, time.Now(), cryptoRandom)
+}
+
+// NewID returns a new ID in the format of Config.IDFormat. Its random bits are
+// drawn from Rand, so the IDs are reproducible with Config.RandSeed.
+func (c *Ctx) NewID() string {
+	return newID(c.app.config.IDFormat, time.Now(), c.randomBytes)
+}
+
+// UUID returns a new UUIDv7, see NewID
+func (c *Ctx) UUID() string {
+	return newID(IDFormatUUIDv7, time.Now(), c.randomBytes)
+}
+
+// Rand returns the random number generator of the request. It's seeded for
+// every request with Config.RandSeed if set, so that handlers and tests are
+// deterministic, otherwise with a cryptographically secure seed. It must not
+// be used after the handler returned and is no source of secrets.
+func (c *Ctx) Rand() *rand.Rand {
+	if c.randSeeded {
+		return c.rand
+	}
+	seed := c.app.config.RandSeed
+	if seed == 0 {
+		var b [8]byte
+		cryptoRandom(b[:])
+		seed = int64(binary.LittleEndian.Uint64(b[:]))
+	}
+	if c.rand == nil {
+		c.rand = rand.New(rand.NewSource(seed))
+	} else {
+		c.rand.Seed(seed)
+	}
+	c.randSeeded = true
+	return c.rand
+}
+
+func (c *Ctx) randomBytes(b []byte) {
+	_, _ = c.Rand().Read(b)
+}
+
+func cryptoRandom(b []byte) {
+	_, _ = crand.Read(b)
+}
+
+// newID returns an ID in the format, with the random bits of random
+func newID(format string, now time.Time, random func(b []byte)) string {
+	switch format {
+	case IDFormatXID:
+		return newXID(now)
+	case IDFormatULID:
+		return newULID(now, random)
+	default:
+		return newUUIDv7(now, random)
+	}
+}
+
+// newUUIDv7 returns a UUID version 7, a 48 bit unix timestamp in milliseconds
+// followed by 74 random bits
+func newUUIDv7(now time.Time, random func(b []byte)) string {
+	var uuid [16]byte
+	random(uuid[6:])
+	putMillis(uuid[:6], now)
+	uuid[6] = (uuid[6] & 0x0f) | 0x70
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+
+	b := make([]byte, 36)
+	hex.Encode(b[0:8], uuid[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], uuid[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], uuid[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], uuid[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], uuid[10:16])
+	return string(b)
+}
+
+// newULID returns a ULID, a 48 bit unix timestamp in milliseconds followed by
+// 80 random bits, encoded in Crockford's base32
+func newULID(now time.Time, random func(b []byte)) string {
+	var id [16]byte
+	putMillis(id[:6], now)
+	random(id[6:])
+
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+	b := make([]byte, 26)
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = ulidAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(b)
+}
+
+// newXID returns a xid, a unix timestamp in seconds, the machine, the process
+// and a counter. It's unique without random bits.
+func newXID(now time.Time) string {
+	xidSetup.Do(func() {
+		if hostname, err := os.Hostname(); err == nil {
+			sum := md5.Sum([]byte(hostname))
+			copy(xidMachine[:], sum[:])
+		} else {
+			cryptoRandom(xidMachine[:])
+		}
+		xidPid = uint16(os.Getpid())
+		var b [4]byte
+		cryptoRandom(b[:])
+		xidCounter = binary.BigEndian.Uint32(b[:])
+	})
+
+	var id [12]byte
+	binary.BigEndian.PutUint32(id[:4], uint32(now.Unix()))
+	copy(id[4:7], xidMachine[:])
+	binary.BigEndian.PutUint16(id[7:9], xidPid)
+	n := atomic.AddUint32(&xidCounter, 1)
+	id[9], id[10], id[11] = byte(n>>16), byte(n>>8), byte(n)
+	return xidEncoding.EncodeToString(id[:])
+}
+
+// putMillis writes the unix timestamp in milliseconds as 48 bit big endian
+func putMillis(b []byte, now time.Time) {
+	ms := uint64(now.UnixNano() / int64(time.Millisecond))
+	b[0], b[1], b[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	b[3], b[4], b[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+}
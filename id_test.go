@@ -0,0 +1,89 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_NewID
+func Test_NewID(t *testing.T) {
+	t.Parallel()
+	zero := func(b []byte) {
+		for i := range b {
+			b[i] = 0
+		}
+	}
+	// Example of the ULID spec
+	now := time.Unix(0, 1469918176385*int64(time.Millisecond))
+	utils.AssertEqual(t, "01ARYZ6S410000000000000000", newID(IDFormatULID, now, zero))
+	utils.AssertEqual(t, "01563df3-6481-7000-8000-000000000000", newID(IDFormatUUIDv7, now, zero))
+
+	uuid := NewID()
+	utils.AssertEqual(t, 36, len(uuid))
+	utils.AssertEqual(t, byte('7'), uuid[14])
+	utils.AssertEqual(t, true, strings.ContainsRune("89ab", rune(uuid[19])))
+	utils.AssertEqual(t, 26, len(NewID(IDFormatULID)))
+
+	// xids of the same second are ordered by the counter
+	a, b := newID(IDFormatXID, now, nil), newID(IDFormatXID, now, nil)
+	utils.AssertEqual(t, 20, len(a))
+	utils.AssertEqual(t, a[:14], b[:14])
+	utils.AssertEqual(t, true, a != b)
+}
+
+// go test -run Test_Ctx_NewID
+func Test_Ctx_NewID(t *testing.T) {
+	t.Parallel()
+	app := New(Config{IDFormat: IDFormatULID, RandSeed: 42})
+	var ids, uuids []string
+	var numbers []int64
+	app.Get("/", func(c *Ctx) error {
+		ids = append(ids, c.NewID())
+		uuids = append(uuids, c.UUID())
+		numbers = append(numbers, c.Rand().Int63())
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+		utils.AssertEqual(t, nil, err)
+	}
+	utils.AssertEqual(t, 26, len(ids[0]))
+	// The random parts are reproducible with the seed
+	utils.AssertEqual(t, ids[0][10:], ids[1][10:])
+	utils.AssertEqual(t, uuids[0][15:], uuids[1][15:])
+	utils.AssertEqual(t, numbers[0], numbers[1])
+
+	// Without a seed every request is seeded randomly
+	app = New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	first := c.Rand().Int63()
+	app.ReleaseCtx(c)
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	utils.AssertEqual(t, true, first != c.Rand().Int63())
+	utils.AssertEqual(t, 36, len(c.NewID()))
+}
+
+// go test -v -run=^$ -bench=Benchmark_Ctx_NewID -benchmem -count=4
+func Benchmark_Ctx_NewID(b *testing.B) {
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	b.ReportAllocs()
+	b.ResetTimer()
+	var id string
+	for n := 0; n < b.N; n++ {
+		id = c.NewID()
+	}
+	utils.AssertEqual(b, 36, len(id))
+}
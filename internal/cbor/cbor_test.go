@@ -0,0 +1,158 @@
+package cbor
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_Marshal
+func Test_Marshal(t *testing.T) {
+	t.Parallel()
+	// Examples of RFC 8949, Appendix A
+	for _, tc := range []struct {
+		value interface{}
+		hex   string
+	}{
+		{0, "00"},
+		{23, "17"},
+		{24, "1818"},
+		{1000, "1903e8"},
+		{uint64(1000000000000), "1b000000e8d4a51000"},
+		{-1, "20"},
+		{-1000, "3903e7"},
+		{1.1, "fb3ff199999999999a"},
+		{float32(100000), "fa47c35000"},
+		{true, "f5"},
+		{nil, "f6"},
+		{"", "60"},
+		{"IETF", "6449455446"},
+		{[]byte{1, 2, 3, 4}, "4401020304"},
+		{[]int{1, 2, 3}, "83010203"},
+		{map[string]int{"b": 2, "a": 1, "aa": 3}, "a361610161620262616103"},
+		{time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC), "c074323031332d30332d32315432303a30343a30305a"},
+	} {
+		b, err := Marshal(tc.value)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.hex, hex.EncodeToString(b), tc.hex)
+	}
+
+	_, err := Marshal(make(chan int))
+	utils.AssertEqual(t, true, err != nil)
+}
+
+type embedded struct {
+	ID int `cbor:"id"`
+}
+
+type user struct {
+	embedded
+	Name    string            `json:"name"`
+	Email   string            `cbor:"email,omitempty"`
+	Secret  string            `cbor:"-"`
+	Tags    []string          `cbor:"tags"`
+	Attrs   map[string]uint16 `cbor:"attrs"`
+	Created time.Time         `cbor:"created"`
+	Parent  *user             `cbor:"parent"`
+	private int
+}
+
+// go test -run Test_Struct
+func Test_Struct(t *testing.T) {
+	t.Parallel()
+	in := user{
+		embedded: embedded{ID: 1},
+		Name:     "john",
+		Secret:   "secret",
+		Tags:     []string{"a", "b"},
+		Attrs:    map[string]uint16{"age": 30},
+		Created:  time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Parent:   &user{Name: "doe"},
+	}
+	b, err := Marshal(in)
+	utils.AssertEqual(t, nil, err)
+
+	var out user
+	utils.AssertEqual(t, nil, Unmarshal(b, &out))
+	utils.AssertEqual(t, in.ID, out.ID)
+	utils.AssertEqual(t, in.Name, out.Name)
+	utils.AssertEqual(t, "", out.Secret)
+	utils.AssertEqual(t, in.Tags, out.Tags)
+	utils.AssertEqual(t, in.Attrs, out.Attrs)
+	utils.AssertEqual(t, true, in.Created.Equal(out.Created))
+	utils.AssertEqual(t, "doe", out.Parent.Name)
+
+	// Generic values
+	var v interface{}
+	utils.AssertEqual(t, nil, Unmarshal(b, &v))
+	m := v.(map[string]interface{})
+	utils.AssertEqual(t, uint64(1), m["id"])
+	utils.AssertEqual(t, "john", m["name"])
+	utils.AssertEqual(t, nil, m["email"])
+	utils.AssertEqual(t, []interface{}{"a", "b"}, m["tags"])
+
+	// Keys are matched case-insensitively
+	b, err = Marshal(map[string]string{"NAME": "john"})
+	utils.AssertEqual(t, nil, err)
+	out = user{}
+	utils.AssertEqual(t, nil, Unmarshal(b, &out))
+	utils.AssertEqual(t, "john", out.Name)
+}
+
+// go test -run Test_Unmarshal
+func Test_Unmarshal(t *testing.T) {
+	t.Parallel()
+	decode := func(s string, v interface{}) error {
+		b, err := hex.DecodeString(s)
+		utils.AssertEqual(t, nil, err)
+		return Unmarshal(b, v)
+	}
+
+	var f float64
+	utils.AssertEqual(t, nil, decode("f93c00", &f))
+	utils.AssertEqual(t, 1.0, f)
+	utils.AssertEqual(t, nil, decode("f9c400", &f))
+	utils.AssertEqual(t, -4.0, f)
+	utils.AssertEqual(t, nil, decode("f97c00", &f))
+	utils.AssertEqual(t, math.Inf(1), f)
+
+	var tm time.Time
+	utils.AssertEqual(t, nil, decode("c11a514b67b0", &tm))
+	utils.AssertEqual(t, int64(1363896240), tm.Unix())
+
+	// Indefinite lengths
+	var s string
+	utils.AssertEqual(t, nil, decode("7f657374726561646d696e67ff", &s))
+	utils.AssertEqual(t, "streaming", s)
+	var arr []int
+	utils.AssertEqual(t, nil, decode("9f018202039f0405ffff", &[]interface{}{}))
+	utils.AssertEqual(t, nil, decode("9f0102ff", &arr))
+	utils.AssertEqual(t, []int{1, 2}, arr)
+	var m map[string]interface{}
+	utils.AssertEqual(t, nil, decode("bf61610161629f0203ffff", &m))
+	utils.AssertEqual(t, map[string]interface{}{"a": uint64(1), "b": []interface{}{uint64(2), uint64(3)}}, m)
+
+	// Non-string keys
+	var v interface{}
+	utils.AssertEqual(t, nil, decode("a201020304", &v))
+	utils.AssertEqual(t, map[interface{}]interface{}{uint64(1): uint64(2), uint64(3): uint64(4)}, v)
+
+	// Errors
+	var i8 int8
+	utils.AssertEqual(t, "cbor: 1000 overflows Go value of type int8", decode("1903e8", &i8).Error())
+	var u uint
+	utils.AssertEqual(t, true, decode("20", &u) != nil)
+	utils.AssertEqual(t, "cbor: cannot unmarshal text string into Go value of type int8", decode("6449455446", &i8).Error())
+	utils.AssertEqual(t, errUnexpectedEnd, decode("1903", &i8))
+	utils.AssertEqual(t, errUnexpectedEnd, decode("9b00000000ffffffff", &v))
+	utils.AssertEqual(t, true, decode("0000", &i8) != nil)
+	utils.AssertEqual(t, true, decode("00", i8) != nil)
+	deep := ""
+	for i := 0; i <= maxDepth+1; i++ {
+		deep += "81"
+	}
+	utils.AssertEqual(t, true, decode(deep+"00", &v) != nil)
+}
@@ -0,0 +1,569 @@
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxDepth is the maximum nesting depth of arrays, maps and tags
+const maxDepth = 256
+
+var errUnexpectedEnd = errors.New("cbor: unexpected end of data")
+
+// pair is an entry of a decoded map, the keys may be unhashable
+type pair struct {
+	key, val interface{}
+}
+
+// mapValue is a decoded map in the order of its entries
+type mapValue []pair
+
+// Unmarshal parses the CBOR-encoded data and stores the result in the value
+// pointed to by v. Maps decoded into interface{} values are map[string]interface{}
+// if all their keys are strings, map[interface{}]interface{} otherwise.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cbor: Unmarshal requires a non-nil pointer, got %T", v)
+	}
+	d := decoder{data: data}
+	val, err := d.value(0)
+	if err != nil {
+		return err
+	}
+	if d.off != len(d.data) {
+		return errors.New("cbor: unexpected data after the top-level value")
+	}
+	return assign(rv.Elem(), val)
+}
+
+type decoder struct {
+	data []byte
+	off  int
+}
+
+// head reads the initial byte and the argument of a data item
+func (d *decoder) head() (major, ai byte, arg uint64, err error) {
+	if d.off >= len(d.data) {
+		return 0, 0, 0, errUnexpectedEnd
+	}
+	b := d.data[d.off]
+	d.off++
+	major, ai = b>>5, b&0x1f
+	switch {
+	case ai < 24:
+		arg = uint64(ai)
+	case ai <= 27:
+		n := 1 << (ai - 24)
+		if len(d.data)-d.off < n {
+			return 0, 0, 0, errUnexpectedEnd
+		}
+		for _, c := range d.data[d.off : d.off+n] {
+			arg = arg<<8 | uint64(c)
+		}
+		d.off += n
+	case ai == 31:
+		// Indefinite length, checked by the caller
+	default:
+		return 0, 0, 0, fmt.Errorf("cbor: invalid additional information %d", ai)
+	}
+	return major, ai, arg, nil
+}
+
+// isBreak skips the break stop code of indefinite length items
+func (d *decoder) isBreak() bool {
+	if d.off < len(d.data) && d.data[d.off] == 0xff {
+		d.off++
+		return true
+	}
+	return false
+}
+
+// length checks that the rest of the data can hold n items of at least a byte
+func (d *decoder) length(n uint64) (int, error) {
+	if n > uint64(len(d.data)-d.off) {
+		return 0, errUnexpectedEnd
+	}
+	return int(n), nil
+}
+
+func (d *decoder) value(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, errors.New("cbor: exceeded max depth")
+	}
+	major, ai, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	if ai == 31 && (major < majorBytes || major == majorTag) {
+		return nil, fmt.Errorf("cbor: invalid indefinite length of major type %d", major)
+	}
+
+	switch major {
+	case majorUint:
+		return arg, nil
+	case majorNegInt:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: negative integer overflows int64")
+		}
+		return -1 - int64(arg), nil
+	case majorBytes, majorText:
+		b, err := d.str(major, ai, arg)
+		if err != nil {
+			return nil, err
+		}
+		if major == majorText {
+			if !utf8.Valid(b) {
+				return nil, errors.New("cbor: invalid UTF-8 text string")
+			}
+			return string(b), nil
+		}
+		return b, nil
+	case majorArray:
+		var arr []interface{}
+		if ai != 31 {
+			n, err := d.length(arg)
+			if err != nil {
+				return nil, err
+			}
+			arr = make([]interface{}, 0, n)
+		}
+		for i := uint64(0); ai == 31 || i < arg; i++ {
+			if ai == 31 && d.isBreak() {
+				break
+			}
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	case majorMap:
+		var m mapValue
+		if ai != 31 {
+			n, err := d.length(arg)
+			if err != nil {
+				return nil, err
+			}
+			m = make(mapValue, 0, n)
+		}
+		for i := uint64(0); ai == 31 || i < arg; i++ {
+			if ai == 31 && d.isBreak() {
+				break
+			}
+			k, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, pair{k, v})
+		}
+		return m, nil
+	case majorTag:
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		switch arg {
+		case tagDateTime:
+			if s, ok := v.(string); ok {
+				return time.Parse(time.RFC3339Nano, s)
+			}
+			return nil, errors.New("cbor: invalid date/time string")
+		case tagEpoch:
+			if t, ok := epochTime(v); ok {
+				return t, nil
+			}
+			return nil, errors.New("cbor: invalid epoch-based date/time")
+		}
+		// Other tags are ignored
+		return v, nil
+	default:
+		switch ai {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			// null and undefined
+			return nil, nil
+		case 25:
+			return halfToFloat(uint16(arg)), nil
+		case 26:
+			return float64(math.Float32frombits(uint32(arg))), nil
+		case 27:
+			return math.Float64frombits(arg), nil
+		case 31:
+			return nil, errors.New("cbor: unexpected break stop code")
+		}
+		return nil, fmt.Errorf("cbor: unsupported simple value %d", arg)
+	}
+}
+
+// str reads a byte or text string, indefinite length strings are concatenated
+func (d *decoder) str(major, ai byte, arg uint64) ([]byte, error) {
+	if ai != 31 {
+		n, err := d.length(arg)
+		if err != nil {
+			return nil, err
+		}
+		b := d.data[d.off : d.off+n]
+		d.off += n
+		return b, nil
+	}
+	var b []byte
+	for !d.isBreak() {
+		chunkMajor, chunkAI, chunkArg, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkAI == 31 {
+			return nil, errors.New("cbor: invalid chunk of indefinite length string")
+		}
+		chunk, err := d.str(major, chunkAI, chunkArg)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, chunk...)
+	}
+	return b, nil
+}
+
+// halfToFloat converts a half-precision float
+func halfToFloat(h uint16) float64 {
+	exp, frac := int(h>>10)&0x1f, float64(h&0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(frac+0x400, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+// epochTime converts the seconds since the epoch
+func epochTime(v interface{}) (time.Time, bool) {
+	switch n := v.(type) {
+	case uint64:
+		if n > math.MaxInt64 {
+			return time.Time{}, false
+		}
+		return time.Unix(int64(n), 0), true
+	case int64:
+		return time.Unix(n, 0), true
+	case float64:
+		sec, frac := math.Modf(n)
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+	}
+	return time.Time{}, false
+}
+
+// assign stores the decoded value in v
+func assign(v reflect.Value, val interface{}) error {
+	if val == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return assign(v.Elem(), val)
+	}
+	if v.Type() == timeType {
+		switch t := val.(type) {
+		case time.Time:
+			v.Set(reflect.ValueOf(t))
+			return nil
+		case string:
+			parsed, err := time.Parse(time.RFC3339Nano, t)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(parsed))
+			return nil
+		}
+		if t, ok := epochTime(val); ok {
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return mismatch(val, v)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			break
+		}
+		g, err := generic(val)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(g))
+		return nil
+	case reflect.Bool:
+		if b, ok := val.(bool); ok {
+			v.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch n := val.(type) {
+		case uint64:
+			if n > math.MaxInt64 {
+				return overflow(val, v)
+			}
+			i = int64(n)
+		case int64:
+			i = n
+		default:
+			return mismatch(val, v)
+		}
+		if v.OverflowInt(i) {
+			return overflow(val, v)
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := val.(uint64)
+		if !ok {
+			if _, neg := val.(int64); neg {
+				return overflow(val, v)
+			}
+			return mismatch(val, v)
+		}
+		if v.OverflowUint(n) {
+			return overflow(val, v)
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch n := val.(type) {
+		case float64:
+			v.SetFloat(n)
+		case uint64:
+			v.SetFloat(float64(n))
+		case int64:
+			v.SetFloat(float64(n))
+		default:
+			return mismatch(val, v)
+		}
+		return nil
+	case reflect.String:
+		if s, ok := val.(string); ok {
+			v.SetString(s)
+			return nil
+		}
+	case reflect.Slice:
+		if b, ok := val.([]byte); ok && v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		if arr, ok := val.([]interface{}); ok {
+			s := reflect.MakeSlice(v.Type(), len(arr), len(arr))
+			for i, elem := range arr {
+				if err := assign(s.Index(i), elem); err != nil {
+					return err
+				}
+			}
+			v.Set(s)
+			return nil
+		}
+	case reflect.Array:
+		if b, ok := val.([]byte); ok && v.Type().Elem().Kind() == reflect.Uint8 && len(b) <= v.Len() {
+			reflect.Copy(v, reflect.ValueOf(b))
+			return nil
+		}
+		if arr, ok := val.([]interface{}); ok && len(arr) <= v.Len() {
+			for i, elem := range arr {
+				if err := assign(v.Index(i), elem); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		if m, ok := val.(mapValue); ok {
+			if v.IsNil() {
+				v.Set(reflect.MakeMapWithSize(v.Type(), len(m)))
+			}
+			for _, p := range m {
+				key := reflect.New(v.Type().Key()).Elem()
+				if err := assign(key, p.key); err != nil {
+					return err
+				}
+				if key.Kind() == reflect.Interface && !key.IsNil() && !key.Elem().Type().Comparable() {
+					return errors.New("cbor: unhashable map key")
+				}
+				elem := reflect.New(v.Type().Elem()).Elem()
+				if err := assign(elem, p.val); err != nil {
+					return err
+				}
+				v.SetMapIndex(key, elem)
+			}
+			return nil
+		}
+	case reflect.Struct:
+		if m, ok := val.(mapValue); ok {
+			return assignStruct(v, m)
+		}
+	}
+	return mismatch(val, v)
+}
+
+// assignStruct stores the decoded map in the struct, the keys are matched
+// case-insensitively if no field has the exact name
+func assignStruct(v reflect.Value, m mapValue) error {
+	fields := fieldsOf(v.Type())
+	for _, p := range m {
+		name, ok := p.key.(string)
+		if !ok {
+			continue
+		}
+		var f *field
+		for i := range fields {
+			if fields[i].name == name {
+				f = &fields[i]
+				break
+			}
+		}
+		if f == nil {
+			for i := range fields {
+				if strings.EqualFold(fields[i].name, name) {
+					f = &fields[i]
+					break
+				}
+			}
+		}
+		if f == nil {
+			continue
+		}
+		fv := settableField(v, f.index)
+		if !fv.IsValid() {
+			continue
+		}
+		if err := assign(fv, p.val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settableField returns the field and allocates nil embedded pointers,
+// fields of unexported nil embedded pointers are invalid
+func settableField(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// generic converts the decoded value for interface{} values
+func generic(val interface{}) (interface{}, error) {
+	switch t := val.(type) {
+	case []interface{}:
+		for i, elem := range t {
+			g, err := generic(elem)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = g
+		}
+		return t, nil
+	case mapValue:
+		strKeys := true
+		for _, p := range t {
+			if _, ok := p.key.(string); !ok {
+				strKeys = false
+				break
+			}
+		}
+		if strKeys {
+			m := make(map[string]interface{}, len(t))
+			for _, p := range t {
+				g, err := generic(p.val)
+				if err != nil {
+					return nil, err
+				}
+				m[p.key.(string)] = g
+			}
+			return m, nil
+		}
+		m := make(map[interface{}]interface{}, len(t))
+		for _, p := range t {
+			switch p.key.(type) {
+			case []byte, []interface{}, mapValue:
+				return nil, errors.New("cbor: unhashable map key")
+			}
+			g, err := generic(p.val)
+			if err != nil {
+				return nil, err
+			}
+			m[p.key] = g
+		}
+		return m, nil
+	case []byte:
+		// The data may be reused by the caller
+		return append([]byte(nil), t...), nil
+	}
+	return val, nil
+}
+
+func mismatch(val interface{}, v reflect.Value) error {
+	return fmt.Errorf("cbor: cannot unmarshal %s into Go value of type %s", kindOf(val), v.Type())
+}
+
+func overflow(val interface{}, v reflect.Value) error {
+	return fmt.Errorf("cbor: %v overflows Go value of type %s", val, v.Type())
+}
+
+// kindOf describes the decoded value
+func kindOf(val interface{}) string {
+	switch val.(type) {
+	case uint64, int64:
+		return "integer"
+	case float64:
+		return "float"
+	case bool:
+		return "bool"
+	case string:
+		return "text string"
+	case []byte:
+		return "byte string"
+	case []interface{}:
+		return "array"
+	case mapValue:
+		return "map"
+	case time.Time:
+		return "date/time"
+	}
+	return fmt.Sprintf("%T", val)
+}
@@ -0,0 +1,256 @@
+// Package cbor is a small encoder and decoder for the Concise Binary Object
+// Representation (RFC 8949), which is the default CBOR codec of Fiber.
+// Structs are encoded as maps, their keys are taken from the "cbor" tag,
+// the "json" tag or the field name.
+package cbor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Major types
+const (
+	majorUint   byte = 0
+	majorNegInt byte = 1
+	majorBytes  byte = 2
+	majorText   byte = 3
+	majorArray  byte = 4
+	majorMap    byte = 5
+	majorTag    byte = 6
+	majorSimple byte = 7
+)
+
+// Tags of date/time strings and epoch-based date/times
+const (
+	tagDateTime uint64 = 0
+	tagEpoch    uint64 = 1
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Marshal returns the CBOR encoding of v.
+func Marshal(v interface{}) ([]byte, error) {
+	var e encoder
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+type encoder struct {
+	buf []byte
+}
+
+// head writes the initial byte and the argument of a data item
+func (e *encoder) head(major byte, n uint64) {
+	major <<= 5
+	switch {
+	case n < 24:
+		e.buf = append(e.buf, major|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, major|25)
+		e.buf = appendUint16(e.buf, uint16(n))
+	case n <= math.MaxUint32:
+		e.buf = append(e.buf, major|26)
+		e.buf = appendUint32(e.buf, uint32(n))
+	default:
+		e.buf = append(e.buf, major|27)
+		e.buf = appendUint64(e.buf, n)
+	}
+}
+
+func appendUint16(b []byte, n uint16) []byte {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], n)
+	return append(b, buf[:]...)
+}
+
+func appendUint32(b []byte, n uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], n)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, n uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	return append(b, buf[:]...)
+}
+
+func (e *encoder) int(i int64) {
+	if i < 0 {
+		e.head(majorNegInt, uint64(-1-i))
+		return
+	}
+	e.head(majorUint, uint64(i))
+}
+
+func (e *encoder) null() {
+	e.buf = append(e.buf, 0xf6)
+}
+
+func (e *encoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.null()
+		return nil
+	}
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		e.head(majorTag, tagDateTime)
+		s := t.Format(time.RFC3339Nano)
+		e.head(majorText, uint64(len(s)))
+		e.buf = append(e.buf, s...)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, 0xf5)
+		} else {
+			e.buf = append(e.buf, 0xf4)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.head(majorUint, v.Uint())
+	case reflect.Float32:
+		e.buf = append(e.buf, majorSimple<<5|26)
+		e.buf = appendUint32(e.buf, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		e.buf = append(e.buf, majorSimple<<5|27)
+		e.buf = appendUint64(e.buf, math.Float64bits(v.Float()))
+	case reflect.String:
+		e.head(majorText, uint64(v.Len()))
+		e.buf = append(e.buf, v.String()...)
+	case reflect.Slice:
+		if v.IsNil() {
+			e.null()
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.head(majorBytes, uint64(v.Len()))
+			e.buf = append(e.buf, v.Bytes()...)
+			return nil
+		}
+		return e.array(v)
+	case reflect.Array:
+		return e.array(v)
+	case reflect.Map:
+		if v.IsNil() {
+			e.null()
+			return nil
+		}
+		return e.mapping(v)
+	case reflect.Struct:
+		return e.structure(v)
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			e.null()
+			return nil
+		}
+		return e.encode(v.Elem())
+	default:
+		return fmt.Errorf("cbor: unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func (e *encoder) array(v reflect.Value) error {
+	e.head(majorArray, uint64(v.Len()))
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapping encodes the map with its keys sorted in the canonical order,
+// i.e. shorter keys first, then bytewise
+func (e *encoder) mapping(v reflect.Value) error {
+	type entry struct {
+		key []byte
+		val reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		var ke encoder
+		if err := ke.encode(iter.Key()); err != nil {
+			return err
+		}
+		entries = append(entries, entry{ke.buf, iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if len(entries[i].key) != len(entries[j].key) {
+			return len(entries[i].key) < len(entries[j].key)
+		}
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	e.head(majorMap, uint64(len(entries)))
+	for _, en := range entries {
+		e.buf = append(e.buf, en.key...)
+		if err := e.encode(en.val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *encoder) structure(v reflect.Value) error {
+	fields := fieldsOf(v.Type())
+	n := 0
+	for _, f := range fields {
+		if !f.omitEmpty || !isEmpty(fieldByIndex(v, f.index)) {
+			n++
+		}
+	}
+	e.head(majorMap, uint64(n))
+	for _, f := range fields {
+		fv := fieldByIndex(v, f.index)
+		if f.omitEmpty && isEmpty(fv) {
+			continue
+		}
+		e.head(majorText, uint64(len(f.name)))
+		e.buf = append(e.buf, f.name...)
+		if err := e.encode(fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns the field, fields of nil embedded pointers are invalid
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func isEmpty(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
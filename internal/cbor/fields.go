@@ -0,0 +1,89 @@
+package cbor
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// field is an encoded field of a struct
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// fieldCache caches the fields of the struct types
+var fieldCache sync.Map
+
+// fieldsOf returns the encoded fields of the struct type. The fields of
+// embedded structs are promoted, unless they are shadowed by a shallower field.
+func fieldsOf(t reflect.Type) []field {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]field)
+	}
+	var fields []field
+	collectFields(t, nil, &fields)
+
+	// Keep the shallowest field of each name
+	depth := make(map[string]int, len(fields))
+	for _, f := range fields {
+		if d, ok := depth[f.name]; !ok || len(f.index) < d {
+			depth[f.name] = len(f.index)
+		}
+	}
+	visible := fields[:0]
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if len(f.index) == depth[f.name] && !seen[f.name] {
+			seen[f.name] = true
+			visible = append(visible, f)
+		}
+	}
+
+	actual, _ := fieldCache.LoadOrStore(t, visible)
+	return actual.([]field)
+}
+
+func collectFields(t reflect.Type, index []int, fields *[]field) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("cbor")
+		if !ok {
+			tag = sf.Tag.Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.IndexByte(tag, ','); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		// Promote the fields of untagged embedded structs
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != timeType {
+			collectFields(ft, fieldIndex, fields)
+			continue
+		}
+		if sf.PkgPath != "" {
+			// Unexported field
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		*fields = append(*fields, field{
+			name:      name,
+			index:     fieldIndex,
+			omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+		})
+	}
+}
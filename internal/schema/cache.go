@@ -10,15 +10,19 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 var errInvalidPath = errors.New("schema: invalid path")
 
+var timeType = reflect.TypeOf(time.Time{})
+
 // newCache returns a new cache.
 func newCache() *cache {
 	c := cache{
 		m:       make(map[reflect.Type]*structInfo),
 		regconv: make(map[reflect.Type]Converter),
+		regdec:  make(map[reflect.Type]DecoderFunc),
 		tag:     "schema",
 	}
 	return &c
@@ -29,7 +33,25 @@ type cache struct {
 	l       sync.RWMutex
 	m       map[reflect.Type]*structInfo
 	regconv map[reflect.Type]Converter
+	regdec  map[reflect.Type]DecoderFunc
 	tag     string
+	// paths caches the parsed paths without slice indexes, keyed by pathKey.
+	paths sync.Map
+	// required caches the required fields of a struct, keyed by reflect.Type.
+	required sync.Map
+	// plain caches the unmarshaler info of the types which don't implement
+	// encoding.TextUnmarshaler, keyed by reflect.Type.
+	plain sync.Map
+	// caseSensitive matches the keys case-sensitively to the aliases.
+	caseSensitive bool
+	// timeFormat is the layout of time fields without a time_format tag.
+	timeFormat string
+}
+
+// pathKey identifies a parsed path of a struct type.
+type pathKey struct {
+	typ  reflect.Type
+	path string
 }
 
 // registerConverter registers a converter function for a custom type.
@@ -37,6 +59,32 @@ func (c *cache) registerConverter(value interface{}, converterFunc Converter) {
 	c.regconv[reflect.TypeOf(value)] = converterFunc
 }
 
+// registerDecoder registers a decoder function for a custom type.
+func (c *cache) registerDecoder(t reflect.Type, decoderFunc DecoderFunc) {
+	c.regdec[t] = decoderFunc
+	// The converter marks the type as supported
+	c.regconv[t] = func(s string) reflect.Value {
+		v, _ := decoderFunc(s)
+		return v
+	}
+}
+
+// textUnmarshaler returns isTextUnmarshaler(v). The result is cached for the
+// types which don't implement encoding.TextUnmarshaler, since it doesn't
+// depend on the value then.
+func (c *cache) textUnmarshaler(v reflect.Value) unmarshaler {
+	t := v.Type()
+	if m, ok := c.plain.Load(t); ok {
+		return m.(unmarshaler)
+	}
+	m := isTextUnmarshaler(v)
+	// The dynamic type of interfaces may differ per value
+	if !m.IsValid && t.Kind() != reflect.Interface {
+		c.plain.Store(t, m)
+	}
+	return m
+}
+
 // parsePath parses a path in dotted notation verifying that it is a valid
 // path to a struct field.
 //
@@ -44,6 +92,10 @@ func (c *cache) registerConverter(value interface{}, converterFunc Converter) {
 // reflect.Value.FieldByString(). Multiple parts are required for slices of
 // structs.
 func (c *cache) parsePath(p string, t reflect.Type) ([]pathPart, error) {
+	key := pathKey{typ: t, path: p}
+	if parts, ok := c.paths.Load(key); ok {
+		return parts.([]pathPart), nil
+	}
 	var struc *structInfo
 	var field *fieldInfo
 	var index64 int64
@@ -58,12 +110,14 @@ func (c *cache) parsePath(p string, t reflect.Type) ([]pathPart, error) {
 		if struc = c.get(t); struc == nil {
 			return nil, errInvalidPath
 		}
-		if field = struc.get(keys[i]); field == nil {
+		if field = struc.get(keys[i], c.caseSensitive); field == nil {
 			return nil, errInvalidPath
 		}
 		// Valid field. Append index.
 		path = append(path, field.name)
-		if field.isSliceOfStructs && (!field.unmarshalerInfo.IsValid || (field.unmarshalerInfo.IsValid && field.unmarshalerInfo.IsSliceElement)) {
+		// Slices of TextUnmarshaler structs are decoded from plain values without an index
+		isPlainSlice := i+1 == len(keys) && field.unmarshalerInfo.IsValid
+		if field.isSliceOfStructs && !isPlainSlice && (!field.unmarshalerInfo.IsValid || (field.unmarshalerInfo.IsValid && field.unmarshalerInfo.IsSliceElement)) {
 			// Parse a special case: slices of structs.
 			// i+1 must be the slice index.
 			//
@@ -108,6 +162,11 @@ func (c *cache) parsePath(p string, t reflect.Type) ([]pathPart, error) {
 		field: field,
 		index: -1,
 	})
+	// Paths with slice indexes are not cached, since their number is
+	// controlled by the client and the cache would grow without bounds.
+	if len(parts) == 1 {
+		c.paths.Store(key, parts)
+	}
 	return parts, nil
 }
 
@@ -157,6 +216,12 @@ func (c *cache) createField(field reflect.StructField, parentAlias string) *fiel
 		// Ignore this field.
 		return nil
 	}
+	// Multiple aliases are separated by "|", e.g. `query:"userId|user_id"`
+	var aliases []string
+	if strings.IndexByte(alias, '|') != -1 {
+		aliases = strings.Split(alias, "|")
+		alias = aliases[0]
+	}
 	canonicalAlias := alias
 	if parentAlias != "" {
 		canonicalAlias = parentAlias + "." + alias
@@ -182,22 +247,51 @@ func (c *cache) createField(field reflect.StructField, parentAlias string) *fiel
 		}
 	}
 	if isStruct = ft.Kind() == reflect.Struct; !isStruct {
-		if c.converter(ft) == nil && builtinConverters[ft.Kind()] == nil {
+		if c.converter(ft) == nil && builtinConverters[ft.Kind()] == nil && !m.IsValid {
 			// Type is not supported.
 			return nil
 		}
 	}
 
-	return &fieldInfo{
+	info := &fieldInfo{
 		typ:              field.Type,
 		name:             field.Name,
 		alias:            alias,
+		aliases:          aliases,
 		canonicalAlias:   canonicalAlias,
 		unmarshalerInfo:  m,
 		isSliceOfStructs: isSlice && isStruct,
 		isAnonymous:      field.Anonymous,
 		isRequired:       options.Contains("required"),
 	}
+	// Slices of structs with a converter are decoded from plain values
+	if info.isSliceOfStructs && c.converter(ft) != nil {
+		info.isSliceOfStructs = false
+	}
+	if ft == timeType {
+		info.timeFormat = field.Tag.Get("time_format")
+		if info.timeFormat == "" {
+			info.timeFormat = c.timeFormat
+		}
+		info.timeLocation, info.timeErr = timeLocation(field.Tag)
+		// Slices of formatted times are decoded from plain values
+		if info.timeFormat != "" {
+			info.isSliceOfStructs = false
+		}
+	}
+	return info
+}
+
+// timeLocation returns the location of a time field according to the
+// time_utc and time_location tags, defaults to time.Local.
+func timeLocation(tag reflect.StructTag) (*time.Location, error) {
+	if utc, _ := strconv.ParseBool(tag.Get("time_utc")); utc {
+		return time.UTC, nil
+	}
+	if name := tag.Get("time_location"); name != "" {
+		return time.LoadLocation(name)
+	}
+	return time.Local, nil
 }
 
 // converter returns the converter for a type.
@@ -205,15 +299,24 @@ func (c *cache) converter(t reflect.Type) Converter {
 	return c.regconv[t]
 }
 
+// convert converts the value with the decoder registered for the type,
+// falling back to the given converter.
+func (c *cache) convert(t reflect.Type, conv Converter, value string) (reflect.Value, error) {
+	if dec := c.regdec[t]; dec != nil {
+		return dec(value)
+	}
+	return conv(value), nil
+}
+
 // ----------------------------------------------------------------------------
 
 type structInfo struct {
 	fields []*fieldInfo
 }
 
-func (i *structInfo) get(alias string) *fieldInfo {
+func (i *structInfo) get(alias string, caseSensitive bool) *fieldInfo {
 	for _, field := range i.fields {
-		if strings.EqualFold(field.alias, alias) {
+		if field.hasAlias(alias, caseSensitive) {
 			return field
 		}
 	}
@@ -222,7 +325,7 @@ func (i *structInfo) get(alias string) *fieldInfo {
 
 func containsAlias(infos []*structInfo, alias string) bool {
 	for _, info := range infos {
-		if info.get(alias) != nil {
+		if info.get(alias, false) != nil {
 			return true
 		}
 	}
@@ -234,6 +337,8 @@ type fieldInfo struct {
 	// name is the field name in the struct.
 	name  string
 	alias string
+	// aliases holds all aliases, including alias, if the tag declares several.
+	aliases []string
 	// canonicalAlias is almost the same as the alias, but is prefixed with
 	// an embedded struct field alias in dotted notation if this field is
 	// promoted from the struct.
@@ -248,9 +353,68 @@ type fieldInfo struct {
 	// isAnonymous indicates whether the field is embedded in the struct.
 	isAnonymous bool
 	isRequired  bool
+	// timeFormat is the layout of a time.Time field given by the time_format tag,
+	// or one of "unix", "unixmilli" and "unixnano" for timestamps.
+	timeFormat   string
+	timeLocation *time.Location
+	timeErr      error
+}
+
+// parseTime parses the value of a time field according to its time_format.
+func (f *fieldInfo) parseTime(value string) (time.Time, error) {
+	if f.timeErr != nil {
+		return time.Time{}, f.timeErr
+	}
+	switch f.timeFormat {
+	case "unix", "unixmilli", "unixnano":
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		switch f.timeFormat {
+		case "unix":
+			return time.Unix(i, 0).In(f.timeLocation), nil
+		case "unixmilli":
+			return time.Unix(0, i*int64(time.Millisecond)).In(f.timeLocation), nil
+		}
+		return time.Unix(0, i).In(f.timeLocation), nil
+	}
+	return time.ParseInLocation(f.timeFormat, value, f.timeLocation)
+}
+
+// hasAlias reports whether the field has the alias, ignoring the case unless
+// caseSensitive is set.
+func (f *fieldInfo) hasAlias(alias string, caseSensitive bool) bool {
+	if f.aliases == nil {
+		return equalKey(f.alias, alias, caseSensitive)
+	}
+	for _, a := range f.aliases {
+		if equalKey(a, alias, caseSensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalKey compares the keys, ignoring the case unless caseSensitive is set.
+func equalKey(a, b string, caseSensitive bool) bool {
+	if caseSensitive {
+		return a == b
+	}
+	return strings.EqualFold(a, b)
 }
 
 func (f *fieldInfo) paths(prefix string) []string {
+	if f.aliases != nil {
+		paths := make([]string, 0, len(f.aliases)+1)
+		for _, a := range f.aliases {
+			paths = append(paths, prefix+a)
+		}
+		if f.alias != f.canonicalAlias {
+			paths = append(paths, prefix+f.canonicalAlias)
+		}
+		return paths
+	}
 	if f.alias == f.canonicalAlias {
 		return []string{prefix + f.alias}
 	}
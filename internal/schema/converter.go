@@ -11,6 +11,9 @@ import (
 
 type Converter func(string) reflect.Value
 
+// DecoderFunc converts a string to a value of a custom type, or returns an error.
+type DecoderFunc func(string) (reflect.Value, error)
+
 var (
 	invalidValue = reflect.Value{}
 	boolType     = reflect.Bool
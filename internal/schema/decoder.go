@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -22,6 +23,8 @@ type Decoder struct {
 	cache             *cache
 	zeroEmpty         bool
 	ignoreUnknownKeys bool
+	maxDepth          int
+	maxSliceLength    int
 }
 
 // SetAliasTag changes the tag used to locate custom field aliases.
@@ -54,11 +57,45 @@ func (d *Decoder) IgnoreUnknownKeys(i bool) {
 	d.ignoreUnknownKeys = i
 }
 
+// CaseSensitive controls whether the keys are matched case-sensitively to the
+// aliases of the fields. The default value is false.
+func (d *Decoder) CaseSensitive(c bool) {
+	d.cache.caseSensitive = c
+}
+
+// DefaultTimeFormat sets the layout of time.Time fields without a time_format
+// tag, which are decoded as structs by default.
+func (d *Decoder) DefaultTimeFormat(layout string) {
+	d.cache.timeFormat = layout
+}
+
+// MaxDepth limits the number of fields in the keys in dotted notation, e.g.
+// "a.0.b" has a depth of 2, since slice indexes don't count. Keys exceeding it are reported as LimitError, even if
+// unknown keys are ignored. 0 disables the limit, which is the default.
+func (d *Decoder) MaxDepth(n int) {
+	d.maxDepth = n
+}
+
+// MaxSliceLength limits the number of values of a key and the slice indexes
+// in the keys, e.g. "items.100000.name". Keys exceeding it are reported as
+// LimitError, even if unknown keys are ignored. 0 disables the limit, which
+// is the default.
+func (d *Decoder) MaxSliceLength(n int) {
+	d.maxSliceLength = n
+}
+
 // RegisterConverter registers a converter function for a custom type.
 func (d *Decoder) RegisterConverter(value interface{}, converterFunc Converter) {
 	d.cache.registerConverter(value, converterFunc)
 }
 
+// RegisterDecoder registers a decoder function for a custom type. Unlike a
+// Converter, the decoder reports why a value can't be converted, the error
+// is returned as ConversionError.Err.
+func (d *Decoder) RegisterDecoder(t reflect.Type, decoderFunc DecoderFunc) {
+	d.cache.registerDecoder(t, decoderFunc)
+}
+
 // Decode decodes a map[string][]string to a struct.
 //
 // The first parameter must be a pointer to a struct.
@@ -76,6 +113,10 @@ func (d *Decoder) Decode(dst interface{}, src map[string][]string) error {
 	t := v.Type()
 	multiError := MultiError{}
 	for path, values := range src {
+		if err := d.checkLimits(path, values); err != nil {
+			multiError[path] = err
+			continue
+		}
 		if parts, err := d.cache.parsePath(path, t); err == nil {
 			if err = d.decode(v, path, parts, values); err != nil {
 				multiError[path] = err
@@ -91,21 +132,90 @@ func (d *Decoder) Decode(dst interface{}, src map[string][]string) error {
 	return nil
 }
 
+// checkLimits checks the key and the number of its values against MaxDepth
+// and MaxSliceLength
+func (d *Decoder) checkLimits(path string, values []string) error {
+	if d.maxSliceLength > 0 && len(values) > d.maxSliceLength {
+		return LimitError{Key: path, Limit: "max slice length", Max: d.maxSliceLength}
+	}
+	if d.maxDepth <= 0 && d.maxSliceLength <= 0 {
+		return nil
+	}
+	depth := 0
+	for start := 0; start <= len(path); {
+		end := strings.IndexByte(path[start:], '.')
+		if end == -1 {
+			end = len(path)
+		} else {
+			end += start
+		}
+		part := path[start:end]
+		start = end + 1
+
+		if part == "" || !isIndex(part) {
+			if depth++; d.maxDepth > 0 && depth > d.maxDepth {
+				return LimitError{Key: path, Limit: "max depth", Max: d.maxDepth}
+			}
+			continue
+		}
+		// Slices are allocated up to the index
+		if index, err := strconv.Atoi(part); d.maxSliceLength > 0 && (err != nil || index >= d.maxSliceLength) {
+			return LimitError{Key: path, Limit: "max slice length", Max: d.maxSliceLength}
+		}
+	}
+	return nil
+}
+
+// isIndex reports whether the part of a key consists of digits
+func isIndex(part string) bool {
+	for i := 0; i < len(part); i++ {
+		if part[i] < '0' || part[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // checkRequired checks whether required fields are empty
 //
 // check type t recursively if t has struct fields.
 //
 // src is the source map for decoding, we use it here to see if those required fields are included in src
 func (d *Decoder) checkRequired(t reflect.Type, src map[string][]string) MultiError {
-	m, errs := d.findRequiredFields(t, "", "")
-	for key, fields := range m {
-		if isEmptyFields(fields, src) {
+	req := d.requiredFields(t)
+	var errs MultiError
+	if len(req.errs) > 0 {
+		errs = MultiError{}
+		errs.merge(req.errs)
+	}
+	for key, fields := range req.fields {
+		if isEmptyFields(fields, src, d.cache.caseSensitive) {
+			if errs == nil {
+				errs = MultiError{}
+			}
 			errs[key] = EmptyFieldError{Key: key}
 		}
 	}
 	return errs
 }
 
+// requiredInfo holds the required fields of a struct type.
+type requiredInfo struct {
+	fields map[string][]fieldWithPrefix
+	errs   MultiError
+}
+
+// requiredFields returns the cached required fields of the struct type t.
+func (d *Decoder) requiredFields(t reflect.Type) *requiredInfo {
+	if req, ok := d.cache.required.Load(t); ok {
+		return req.(*requiredInfo)
+	}
+	fields, errs := d.findRequiredFields(t, "", "")
+	req := &requiredInfo{fields: fields, errs: errs}
+	d.cache.required.Store(t, req)
+	return req
+}
+
 // findRequiredFields recursively searches the struct type t for required fields.
 //
 // canonicalPrefix and searchPrefix are used to resolve full paths in dotted notation
@@ -149,7 +259,7 @@ type fieldWithPrefix struct {
 }
 
 // isEmptyFields returns true if all of specified fields are empty.
-func isEmptyFields(fields []fieldWithPrefix, src map[string][]string) bool {
+func isEmptyFields(fields []fieldWithPrefix, src map[string][]string, caseSensitive bool) bool {
 	for _, f := range fields {
 		for _, path := range f.paths(f.prefix) {
 			v, ok := src[path]
@@ -162,14 +272,17 @@ func isEmptyFields(fields []fieldWithPrefix, src map[string][]string) bool {
 				// https://github.com/gorilla/schema/issues/176
 				nested := strings.IndexByte(key, '.') != -1
 
+				// keys are matched like the aliases of the fields
+				equal := equalKey(key, path, caseSensitive)
+
 				// for non required nested structs
-				c1 := strings.HasSuffix(f.prefix, ".") && key == path
+				c1 := strings.HasSuffix(f.prefix, ".") && equal
 
 				// for required nested structs
-				c2 := f.prefix == "" && nested && strings.HasPrefix(key, path)
+				c2 := f.prefix == "" && nested && len(key) >= len(path) && equalKey(key[:len(path)], path, caseSensitive)
 
 				// for non nested fields
-				c3 := f.prefix == "" && !nested && key == path
+				c3 := f.prefix == "" && !nested && equal
 				if !isEmpty(f.typ, src[key]) && (c1 || c2 || c3) {
 					return false
 				}
@@ -243,9 +356,14 @@ func (d *Decoder) decode(v reflect.Value, path string, parts []pathPart, values
 		return d.decode(v.Index(idx), path, parts[1:], values)
 	}
 
+	// Time fields with a time_format tag
+	if f := parts[0].field; f.timeFormat != "" {
+		return d.decodeTime(v, t, path, f, values)
+	}
+
 	// Get the converter early in case there is one for a slice type.
 	conv := d.cache.converter(t)
-	m := isTextUnmarshaler(v)
+	m := d.cache.textUnmarshaler(v)
 	if conv == nil && t.Kind() == reflect.Slice && m.IsSliceElement {
 		var items []reflect.Value
 		elemT := t.Elem()
@@ -254,11 +372,13 @@ func (d *Decoder) decode(v reflect.Value, path string, parts []pathPart, values
 			elemT = elemT.Elem()
 		}
 
-		// Try to get a converter for the element type.
+		// Try to get a converter for the element type, it takes precedence
+		// over the TextUnmarshaler like for single values.
 		conv := d.cache.converter(elemT)
+		unmarshal := m.IsValid && conv == nil
 		if conv == nil {
 			conv = builtinConverters[elemT.Kind()]
-			if conv == nil {
+			if conv == nil && !m.IsValid {
 				// As we are not dealing with slice of structs here, we don't need to check if the type
 				// implements TextUnmarshaler interface
 				return fmt.Errorf("schema: converter not found for %v", elemT)
@@ -270,7 +390,7 @@ func (d *Decoder) decode(v reflect.Value, path string, parts []pathPart, values
 				if d.zeroEmpty {
 					items = append(items, reflect.Zero(elemT))
 				}
-			} else if m.IsValid {
+			} else if unmarshal {
 				u := reflect.New(elemT)
 				if m.IsSliceElementPtr {
 					u = reflect.New(reflect.PtrTo(elemT).Elem())
@@ -290,7 +410,7 @@ func (d *Decoder) decode(v reflect.Value, path string, parts []pathPart, values
 				} else {
 					items = append(items, u)
 				}
-			} else if item := conv(value); item.IsValid() {
+			} else if item, err := d.cache.convert(elemT, conv, value); item.IsValid() {
 				if isPtrElem {
 					ptr := reflect.New(elemT)
 					ptr.Elem().Set(item)
@@ -300,6 +420,13 @@ func (d *Decoder) decode(v reflect.Value, path string, parts []pathPart, values
 					item = item.Convert(elemT)
 				}
 				items = append(items, item)
+			} else if err != nil {
+				return ConversionError{
+					Key:   path,
+					Type:  elemT,
+					Index: key,
+					Err:   err,
+				}
 			} else {
 				if strings.Contains(value, ",") {
 					values := strings.Split(value, ",")
@@ -345,13 +472,14 @@ func (d *Decoder) decode(v reflect.Value, path string, parts []pathPart, values
 		}
 
 		if conv != nil {
-			if value := conv(val); value.IsValid() {
+			if value, err := d.cache.convert(t, conv, val); value.IsValid() {
 				v.Set(value.Convert(t))
 			} else {
 				return ConversionError{
 					Key:   path,
 					Type:  t,
 					Index: -1,
+					Err:   err,
 				}
 			}
 		} else if m.IsValid {
@@ -399,6 +527,64 @@ func (d *Decoder) decode(v reflect.Value, path string, parts []pathPart, values
 	return nil
 }
 
+// decodeTime fills a time.Time or []time.Time field according to its time_format tag.
+func (d *Decoder) decodeTime(v reflect.Value, t reflect.Type, path string, f *fieldInfo, values []string) error {
+	if t.Kind() == reflect.Slice {
+		elemT := t.Elem()
+		items := reflect.MakeSlice(t, 0, len(values))
+		for key, value := range values {
+			var item reflect.Value
+			if value == "" {
+				if !d.zeroEmpty {
+					continue
+				}
+				item = reflect.Zero(elemT)
+			} else {
+				tm, err := f.parseTime(value)
+				if err != nil {
+					return ConversionError{
+						Key:   path,
+						Type:  elemT,
+						Index: key,
+						Err:   err,
+					}
+				}
+				item = reflect.ValueOf(tm)
+				if elemT.Kind() == reflect.Ptr {
+					item = reflect.New(timeType)
+					item.Elem().Set(reflect.ValueOf(tm))
+				}
+			}
+			items = reflect.Append(items, item)
+		}
+		v.Set(items)
+		return nil
+	}
+
+	val := ""
+	// Use the last value provided if any values were provided
+	if len(values) > 0 {
+		val = values[len(values)-1]
+	}
+	if val == "" {
+		if d.zeroEmpty {
+			v.Set(reflect.Zero(t))
+		}
+		return nil
+	}
+	tm, err := f.parseTime(val)
+	if err != nil {
+		return ConversionError{
+			Key:   path,
+			Type:  t,
+			Index: -1,
+			Err:   err,
+		}
+	}
+	v.Set(reflect.ValueOf(tm))
+	return nil
+}
+
 func isTextUnmarshaler(v reflect.Value) unmarshaler {
 	// Create a new unmarshaller instance
 	m := unmarshaler{}
@@ -485,6 +671,23 @@ func (e ConversionError) Error() string {
 	return output
 }
 
+// Unwrap returns the low-level error, e.g. of a TextUnmarshaler.
+func (e ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// LimitError stores information about a key exceeding a limit of the decoder,
+// see MaxDepth and MaxSliceLength.
+type LimitError struct {
+	Key   string // key from the source map.
+	Limit string // name of the exceeded limit, e.g. "max depth".
+	Max   int    // value of the limit.
+}
+
+func (e LimitError) Error() string {
+	return fmt.Sprintf("schema: %q exceeds the %s of %d", e.Key, e.Limit, e.Max)
+}
+
 // UnknownKeyError stores information about an unknown key in the source map.
 type UnknownKeyError struct {
 	Key string // key from the source map.
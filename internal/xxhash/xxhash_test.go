@@ -0,0 +1,25 @@
+package xxhash
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSum64(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  uint64
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"abc", 0x44bc2cf5ad770999},
+		{"asdf", 0x415872f599cea71e},
+		{"hello world 12345", 0x10eed33ff63f304d},
+		{strings.Repeat("xyz", 13), 0x679cdbe4f62258cb},
+		{strings.Repeat("a", 100), 0x375041e8b1decfb3},
+	} {
+		if got := Sum64String(tt.input); got != tt.want {
+			t.Errorf("Sum64String(%q): got 0x%x, want 0x%x", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package jsonapi
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// Offer responds with the JSON:API document of the value for Ctx.Negotiate:
+//
+//	return c.Negotiate(articles, jsonapi.Offer, fiber.OfferJSON)
+var Offer = fiber.Offer{Type: MIMEApplicationJSONAPI, Render: func(c *fiber.Ctx, v interface{}) error {
+	return Send(c, v)
+}}
+
+// Send responds with the JSON:API document of the value, see Marshal. A
+// *Document is sent as is.
+func Send(c *fiber.Ctx, v interface{}) error {
+	doc, ok := v.(*Document)
+	if !ok {
+		var err error
+		if doc, err = Marshal(v); err != nil {
+			return err
+		}
+	}
+	return sendDocument(c, doc)
+}
+
+// SendPage responds with the JSON:API document of the page of values with
+// the pagination links and the known total in the meta, see PageOf.
+func SendPage(c *fiber.Ctx, v interface{}, page Page) error {
+	doc, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	doc.Links = page.Links(c)
+	if page.Total >= 0 {
+		doc.Meta = map[string]interface{}{"total": page.Total}
+	}
+	return sendDocument(c, doc)
+}
+
+// sendDocument encodes the document with the JSONEncoder of the app
+func sendDocument(c *fiber.Ctx, doc *Document) error {
+	raw, err := c.App().Config().JSONEncoder(doc)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, MIMEApplicationJSONAPI)
+	return c.Send(raw)
+}
+
+// Bind decodes the request body into v, see Unmarshal. Other content types
+// than application/vnd.api+json are rejected with 415 Unsupported Media Type,
+// invalid documents with 400 Bad Request.
+func Bind(c *fiber.Ctx, v interface{}) error {
+	if utils.ToLower(utils.Trim(c.Get(fiber.HeaderContentType), ' ')) != MIMEApplicationJSONAPI {
+		return fiber.ErrUnsupportedMediaType
+	}
+	if err := Unmarshal(c.Body(), v); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return nil
+}
+
+// ErrorHandler responds with the errors document of the error, it can be used
+// as the ErrorHandler of the app or called by it:
+//
+//	app := fiber.New(fiber.Config{
+//		ErrorHandler: jsonapi.ErrorHandler,
+//	})
+//
+// The *Error values of the error are sent as they are, the status of the
+// response is the one of the first error. The message of a *fiber.Error is
+// sent as detail, other errors as 500 Internal Server Error without detail.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var (
+		apiErr   *Error
+		fiberErr *fiber.Error
+		errs     Errors
+		doc      = &Document{}
+	)
+	switch {
+	case errors.As(err, &errs) && len(errs) > 0:
+		doc.Errors = errs
+	case errors.As(err, &apiErr):
+		doc.Errors = []*Error{apiErr}
+	case errors.As(err, &fiberErr):
+		doc.Errors = []*Error{{
+			Status: strconv.Itoa(fiberErr.Code),
+			Title:  utils.StatusMessage(fiberErr.Code),
+			Detail: fiberErr.Message,
+		}}
+	default:
+		doc.Errors = []*Error{{
+			Status: strconv.Itoa(fiber.StatusInternalServerError),
+			Title:  utils.StatusMessage(fiber.StatusInternalServerError),
+		}}
+	}
+
+	status, convErr := strconv.Atoi(doc.Errors[0].Status)
+	if convErr != nil || status < 400 || status > 599 {
+		status = fiber.StatusInternalServerError
+	}
+	c.Status(status)
+	return sendDocument(c, doc)
+}
+
+// Errors are multiple error objects, which are sent together by ErrorHandler
+type Errors []*Error
+
+// Error returns the message of the first error
+func (errs Errors) Error() string {
+	if len(errs) == 0 {
+		return ""
+	}
+	return errs[0].Error()
+}
+
+// Page is a page of a paginated collection
+type Page struct {
+	Number int // Starts at 1
+	Size   int
+	Total  int // Number of all items, the last page is unknown if it's negative
+}
+
+// PageOf returns the page of the query params page[number] and page[size],
+// the size is limited to maxSize:
+//
+//	page := jsonapi.PageOf(c, 20, 100)
+//	articles, total := db.Articles(page.Offset(), page.Size)
+//	page.Total = total
+//	return jsonapi.SendPage(c, articles, page)
+func PageOf(c *fiber.Ctx, defaultSize, maxSize int) Page {
+	page := Page{Number: 1, Size: defaultSize, Total: -1}
+	if n, err := strconv.Atoi(c.Query("page[number]")); err == nil && n > 0 {
+		page.Number = n
+	}
+	if n, err := strconv.Atoi(c.Query("page[size]")); err == nil && n > 0 {
+		page.Size = n
+	}
+	if maxSize > 0 && page.Size > maxSize {
+		page.Size = maxSize
+	}
+	return page
+}
+
+// Offset returns the number of items before the page
+func (p Page) Offset() int {
+	return (p.Number - 1) * p.Size
+}
+
+// Links returns the links of the page, first, prev, next and last are
+// relative to the request URI with the other query params
+func (p Page) Links(c *fiber.Ctx) *Links {
+	links := &Links{
+		Self:  p.link(c, p.Number),
+		First: p.link(c, 1),
+	}
+	if p.Number > 1 {
+		links.Prev = p.link(c, p.Number-1)
+	}
+	if p.Total < 0 {
+		links.Next = p.link(c, p.Number+1)
+		return links
+	}
+	last := 1
+	if p.Size > 0 && p.Total > 0 {
+		last = (p.Total + p.Size - 1) / p.Size
+	}
+	if p.Number < last {
+		links.Next = p.link(c, p.Number+1)
+	}
+	links.Last = p.link(c, last)
+	return links
+}
+
+// link returns the request URI of the page number
+func (p Page) link(c *fiber.Ctx, number int) string {
+	uri := fasthttp.AcquireURI()
+	defer fasthttp.ReleaseURI(uri)
+	c.Request().URI().CopyTo(uri)
+	args := uri.QueryArgs()
+	args.Set("page[number]", strconv.Itoa(number))
+	args.Set("page[size]", strconv.Itoa(p.Size))
+	return string(uri.RequestURI())
+}
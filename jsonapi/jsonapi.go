@@ -0,0 +1,478 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+// Package jsonapi produces and binds JSON:API documents
+// (https://jsonapi.org) of structs with jsonapi tags, including relationships,
+// included resources, errors and pagination links:
+//
+//	type Article struct {
+//		ID     int     `jsonapi:"primary,articles"`
+//		Title  string  `jsonapi:"attr,title"`
+//		Author *Person `jsonapi:"relation,author"`
+//	}
+package jsonapi
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MIMEApplicationJSONAPI is the media type of JSON:API documents
+const MIMEApplicationJSONAPI = "application/vnd.api+json"
+
+// Document is a top-level JSON:API document
+type Document struct {
+	// Data is the primary data, a *Resource, a []*Resource or null
+	Data     interface{}            `json:"data,omitempty"`
+	Included []*Resource            `json:"included,omitempty"`
+	Errors   []*Error               `json:"errors,omitempty"`
+	Meta     map[string]interface{} `json:"meta,omitempty"`
+	Links    *Links                 `json:"links,omitempty"`
+}
+
+// Resource is a resource object
+type Resource struct {
+	Type          string                   `json:"type"`
+	ID            string                   `json:"id,omitempty"`
+	Attributes    map[string]interface{}   `json:"attributes,omitempty"`
+	Relationships map[string]*Relationship `json:"relationships,omitempty"`
+	Links         *Links                   `json:"links,omitempty"`
+	Meta          map[string]interface{}   `json:"meta,omitempty"`
+}
+
+// Relationship is a relationship object
+type Relationship struct {
+	// Data is the resource linkage, a *Identifier, a []*Identifier or null
+	Data  interface{}            `json:"data"`
+	Links *Links                 `json:"links,omitempty"`
+	Meta  map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Identifier is a resource identifier object
+type Identifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Links is a links object
+type Links struct {
+	Self    string `json:"self,omitempty"`
+	Related string `json:"related,omitempty"`
+	First   string `json:"first,omitempty"`
+	Prev    string `json:"prev,omitempty"`
+	Next    string `json:"next,omitempty"`
+	Last    string `json:"last,omitempty"`
+}
+
+// Error is an error object, it can be returned by handlers for ErrorHandler
+type Error struct {
+	ID     string                 `json:"id,omitempty"`
+	Status string                 `json:"status,omitempty"`
+	Code   string                 `json:"code,omitempty"`
+	Title  string                 `json:"title,omitempty"`
+	Detail string                 `json:"detail,omitempty"`
+	Source *ErrorSource           `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// ErrorSource is the source of an error object
+type ErrorSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Header    string `json:"header,omitempty"`
+}
+
+// Error returns the detail or the title of the error
+func (e *Error) Error() string {
+	if e.Detail != "" {
+		return e.Detail
+	}
+	return e.Title
+}
+
+// null is the primary data of a nil resource, which is null instead of missing
+var null = json.RawMessage("null")
+
+// resourceType describes the jsonapi fields of a struct type
+type resourceType struct {
+	name      string
+	id        int
+	attrs     []attrField
+	relations []attrField
+}
+
+// attrField is an attribute or relationship field
+type attrField struct {
+	name      string
+	index     int
+	omitEmpty bool
+}
+
+var (
+	resourceTypes     sync.Map // reflect.Type => *resourceType
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// resourceTypeOf returns the jsonapi fields of the struct type
+func resourceTypeOf(typ reflect.Type) (*resourceType, error) {
+	if rt, ok := resourceTypes.Load(typ); ok {
+		return rt.(*resourceType), nil
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonapi: %s isn't a struct", typ)
+	}
+	rt := &resourceType{id: -1}
+	for i := 0; i < typ.NumField(); i++ {
+		tag, ok := typ.Field(i).Tag.Lookup("jsonapi")
+		if !ok || typ.Field(i).PkgPath != "" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		if len(parts) < 2 || parts[1] == "" {
+			return nil, fmt.Errorf("jsonapi: invalid tag %q of %s.%s", tag, typ, typ.Field(i).Name)
+		}
+		field := attrField{name: parts[1], index: i, omitEmpty: len(parts) > 2 && parts[2] == "omitempty"}
+		switch parts[0] {
+		case "primary":
+			rt.name, rt.id = parts[1], i
+		case "attr":
+			rt.attrs = append(rt.attrs, field)
+		case "relation":
+			rt.relations = append(rt.relations, field)
+		default:
+			return nil, fmt.Errorf("jsonapi: invalid tag %q of %s.%s", tag, typ, typ.Field(i).Name)
+		}
+	}
+	if rt.id == -1 {
+		return nil, fmt.Errorf("jsonapi: %s has no primary field", typ)
+	}
+	resourceTypes.Store(typ, rt)
+	return rt, nil
+}
+
+// Marshal returns the document of a struct, a slice of structs or nil. The
+// values of the relationships are added to the included resources.
+func Marshal(v interface{}) (*Document, error) {
+	m := &marshaler{seen: make(map[Identifier]bool)}
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return &Document{Data: null}, nil
+	}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		// The primary resources aren't included again
+		values := make([]reflect.Value, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			if item := indirect(rv.Index(i)); item.IsValid() {
+				values = append(values, item)
+				if err := m.see(item); err != nil {
+					return nil, err
+				}
+			}
+		}
+		data := make([]*Resource, len(values))
+		for i, item := range values {
+			res, err := m.resource(item)
+			if err != nil {
+				return nil, err
+			}
+			data[i] = res
+		}
+		return &Document{Data: data, Included: m.included}, nil
+	}
+
+	if err := m.see(rv); err != nil {
+		return nil, err
+	}
+	res, err := m.resource(rv)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{Data: res, Included: m.included}, nil
+}
+
+// marshaler collects the included resources of a document
+type marshaler struct {
+	seen     map[Identifier]bool
+	included []*Resource
+}
+
+// see marks the resource as part of the document
+func (m *marshaler) see(v reflect.Value) error {
+	id, err := identify(v)
+	if err != nil {
+		return err
+	}
+	m.seen[*id] = true
+	return nil
+}
+
+// resource returns the resource object of the struct
+func (m *marshaler) resource(v reflect.Value) (*Resource, error) {
+	rt, err := resourceTypeOf(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	res := &Resource{Type: rt.name, ID: formatID(v.Field(rt.id))}
+	if len(rt.attrs) > 0 {
+		res.Attributes = make(map[string]interface{}, len(rt.attrs))
+		for _, attr := range rt.attrs {
+			fv := v.Field(attr.index)
+			if attr.omitEmpty && fv.IsZero() {
+				continue
+			}
+			res.Attributes[attr.name] = fv.Interface()
+		}
+	}
+	if len(rt.relations) > 0 {
+		res.Relationships = make(map[string]*Relationship, len(rt.relations))
+		for _, rel := range rt.relations {
+			data, err := m.linkage(v.Field(rel.index))
+			if err != nil {
+				return nil, err
+			}
+			res.Relationships[rel.name] = &Relationship{Data: data}
+		}
+	}
+	return res, nil
+}
+
+// linkage returns the resource linkage of a relationship and includes the
+// related resources
+func (m *marshaler) linkage(v reflect.Value) (interface{}, error) {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		ids := make([]*Identifier, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item := indirect(v.Index(i))
+			if !item.IsValid() {
+				continue
+			}
+			id, err := m.include(item)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+	if v = indirect(v); !v.IsValid() {
+		return nil, nil
+	}
+	return m.include(v)
+}
+
+// include adds the related resource unless it's already part of the document
+func (m *marshaler) include(v reflect.Value) (*Identifier, error) {
+	id, err := identify(v)
+	if err != nil {
+		return nil, err
+	}
+	if m.seen[*id] {
+		return id, nil
+	}
+	m.seen[*id] = true
+	// The resource is included before its own related resources
+	i := len(m.included)
+	m.included = append(m.included, nil)
+	res, err := m.resource(v)
+	if err != nil {
+		return nil, err
+	}
+	m.included[i] = res
+	return id, nil
+}
+
+// identify returns the resource identifier of the struct
+func identify(v reflect.Value) (*Identifier, error) {
+	rt, err := resourceTypeOf(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	return &Identifier{Type: rt.name, ID: formatID(v.Field(rt.id))}, nil
+}
+
+// indirect dereferences pointers and interfaces, nil values are invalid
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// formatID returns the ID of a string, integer or encoding.TextMarshaler field
+func formatID(v reflect.Value) string {
+	if v.Type().Implements(textMarshalerType) {
+		text, _ := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// parseID sets the ID of a string, integer or encoding.TextUnmarshaler field
+func parseID(v reflect.Value, id string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(id))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(id)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(id, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("jsonapi: invalid id %q", id)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(id, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("jsonapi: invalid id %q", id)
+		}
+		v.SetUint(n)
+		return nil
+	}
+	return fmt.Errorf("jsonapi: unsupported id type %s", v.Type())
+}
+
+// rawResource is a resource object to decode
+type rawResource struct {
+	Type          string                     `json:"type"`
+	ID            string                     `json:"id"`
+	Attributes    map[string]json.RawMessage `json:"attributes"`
+	Relationships map[string]struct {
+		Data json.RawMessage `json:"data"`
+	} `json:"relationships"`
+}
+
+// Unmarshal decodes the primary data of the document into v, a pointer to a
+// struct or to a slice of structs. The attributes are decoded with
+// encoding/json, the related structs of the relationships only get their IDs.
+func Unmarshal(data []byte, v interface{}) error {
+	var doc struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("jsonapi: non-nil pointer expected, got %T", v)
+	}
+	rv = rv.Elem()
+	if len(doc.Data) == 0 || bytes.Equal(doc.Data, null) {
+		return fmt.Errorf("jsonapi: missing primary data")
+	}
+
+	if rv.Kind() == reflect.Slice {
+		var items []rawResource
+		if err := json.Unmarshal(doc.Data, &items); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(rv.Type(), len(items), len(items))
+		for i := range items {
+			if err := unmarshalResource(&items[i], alloc(slice.Index(i))); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+	}
+
+	var res rawResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return err
+	}
+	return unmarshalResource(&res, alloc(rv))
+}
+
+// alloc returns the struct of the value, nil pointers are allocated
+func alloc(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// unmarshalResource sets the fields of the struct
+func unmarshalResource(res *rawResource, v reflect.Value) error {
+	rt, err := resourceTypeOf(v.Type())
+	if err != nil {
+		return err
+	}
+	if res.Type != rt.name {
+		return fmt.Errorf("jsonapi: type %q, expected %q", res.Type, rt.name)
+	}
+	if res.ID != "" {
+		if err := parseID(v.Field(rt.id), res.ID); err != nil {
+			return err
+		}
+	}
+	for _, attr := range rt.attrs {
+		raw, ok := res.Attributes[attr.name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, v.Field(attr.index).Addr().Interface()); err != nil {
+			return fmt.Errorf("jsonapi: attribute %q: %w", attr.name, err)
+		}
+	}
+	for _, rel := range rt.relations {
+		raw, ok := res.Relationships[rel.name]
+		if !ok {
+			continue
+		}
+		if err := unmarshalLinkage(raw.Data, v.Field(rel.index)); err != nil {
+			return fmt.Errorf("jsonapi: relationship %q: %w", rel.name, err)
+		}
+	}
+	return nil
+}
+
+// unmarshalLinkage sets the related structs of the resource linkage
+func unmarshalLinkage(data json.RawMessage, v reflect.Value) error {
+	if len(data) == 0 || bytes.Equal(data, null) {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Kind() == reflect.Slice {
+		var ids []rawResource
+		if err := json.Unmarshal(data, &ids); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), len(ids), len(ids))
+		for i := range ids {
+			if err := unmarshalResource(&ids[i], alloc(slice.Index(i))); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+	var id rawResource
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	return unmarshalResource(&id, alloc(v))
+}
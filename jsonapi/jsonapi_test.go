@@ -0,0 +1,195 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type person struct {
+	ID   string `jsonapi:"primary,people"`
+	Name string `jsonapi:"attr,name"`
+}
+
+type comment struct {
+	ID     uint64  `jsonapi:"primary,comments"`
+	Body   string  `jsonapi:"attr,body"`
+	Author *person `jsonapi:"relation,author"`
+}
+
+type article struct {
+	ID       int        `jsonapi:"primary,articles"`
+	Title    string     `jsonapi:"attr,title"`
+	Draft    bool       `jsonapi:"attr,draft,omitempty"`
+	Author   *person    `jsonapi:"relation,author"`
+	Comments []*comment `jsonapi:"relation,comments"`
+	Internal string
+}
+
+func newArticle() *article {
+	john := &person{ID: "9", Name: "John"}
+	return &article{
+		ID:     1,
+		Title:  "JSON:API paints my bikeshed!",
+		Author: john,
+		Comments: []*comment{
+			{ID: 5, Body: "First!", Author: &person{ID: "2", Name: "Jane"}},
+			{ID: 12, Body: "I like XML better", Author: john},
+		},
+	}
+}
+
+// go test -run Test_Marshal
+func Test_Marshal(t *testing.T) {
+	t.Parallel()
+	doc, err := Marshal(newArticle())
+	utils.AssertEqual(t, nil, err)
+	data, err := json.Marshal(doc)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `{"data":{"type":"articles","id":"1","attributes":{"title":"JSON:API paints my bikeshed!"},`+
+		`"relationships":{"author":{"data":{"type":"people","id":"9"}},"comments":{"data":[{"type":"comments","id":"5"},{"type":"comments","id":"12"}]}}},`+
+		`"included":[{"type":"people","id":"9","attributes":{"name":"John"}},`+
+		`{"type":"comments","id":"5","attributes":{"body":"First!"},"relationships":{"author":{"data":{"type":"people","id":"2"}}}},`+
+		`{"type":"people","id":"2","attributes":{"name":"Jane"}},`+
+		`{"type":"comments","id":"12","attributes":{"body":"I like XML better"},"relationships":{"author":{"data":{"type":"people","id":"9"}}}}]}`, string(data))
+
+	// Primary resources aren't included
+	doc, err = Marshal([]*comment{{ID: 1, Author: &person{ID: "9"}}, {ID: 2}, nil})
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, 2, len(doc.Data.([]*Resource)))
+	utils.AssertEqual(t, 1, len(doc.Included))
+	utils.AssertEqual(t, nil, doc.Data.([]*Resource)[1].Relationships["author"].Data)
+
+	doc, err = Marshal(nil)
+	utils.AssertEqual(t, nil, err)
+	data, err = json.Marshal(doc)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `{"data":null}`, string(data))
+
+	_, err = Marshal(struct{ Name string }{})
+	utils.AssertEqual(t, "jsonapi: struct { Name string } has no primary field", err.Error())
+}
+
+// go test -run Test_Unmarshal
+func Test_Unmarshal(t *testing.T) {
+	t.Parallel()
+	var a article
+	err := Unmarshal([]byte(`{"data":{"type":"articles","id":"3","attributes":{"title":"Hello","draft":true,"unknown":1},
+		"relationships":{"author":{"data":{"type":"people","id":"9"}},"comments":{"data":[{"type":"comments","id":"5"}]}}}}`), &a)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, article{
+		ID:       3,
+		Title:    "Hello",
+		Draft:    true,
+		Author:   &person{ID: "9"},
+		Comments: []*comment{{ID: 5}},
+	}, a)
+
+	var people []person
+	err = Unmarshal([]byte(`{"data":[{"type":"people","attributes":{"name":"John"}},{"type":"people","id":"2"}]}`), &people)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, []person{{Name: "John"}, {ID: "2"}}, people)
+
+	err = Unmarshal([]byte(`{"data":{"type":"people","id":"9"}}`), &a)
+	utils.AssertEqual(t, `jsonapi: type "people", expected "articles"`, err.Error())
+	err = Unmarshal([]byte(`{"data":{"type":"articles","id":"x"}}`), &a)
+	utils.AssertEqual(t, `jsonapi: invalid id "x"`, err.Error())
+	err = Unmarshal([]byte(`{"data":null}`), &a)
+	utils.AssertEqual(t, "jsonapi: missing primary data", err.Error())
+}
+
+// go test -run Test_Fiber
+func Test_Fiber(t *testing.T) {
+	t.Parallel()
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/articles/1", func(c *fiber.Ctx) error {
+		return c.Negotiate(newArticle(), Offer, fiber.OfferJSON)
+	})
+	app.Post("/people", func(c *fiber.Ctx) error {
+		var p person
+		if err := Bind(c, &p); err != nil {
+			return err
+		}
+		if p.Name == "" {
+			return Errors{{Status: "422", Title: "Invalid Attribute", Source: &ErrorSource{Pointer: "/data/attributes/name"}}}
+		}
+		c.Status(fiber.StatusCreated)
+		return Send(c, &p)
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return errors.New("secret")
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/articles/1", nil)
+	req.Header.Set(fiber.HeaderAccept, MIMEApplicationJSONAPI)
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, MIMEApplicationJSONAPI, resp.Header.Get(fiber.HeaderContentType))
+
+	for _, tc := range []struct {
+		body     string
+		status   int
+		expected string
+	}{
+		{`{"data":{"type":"people","attributes":{"name":"John"}}}`, fiber.StatusCreated, `{"data":{"type":"people","attributes":{"name":"John"}}}`},
+		{`{"data":{"type":"people","attributes":{}}}`, fiber.StatusUnprocessableEntity, `{"errors":[{"status":"422","title":"Invalid Attribute","source":{"pointer":"/data/attributes/name"}}]}`},
+		{`{"data":{"type":"articles"}}`, fiber.StatusBadRequest, `{"errors":[{"status":"400","title":"Bad Request","detail":"jsonapi: type \"articles\", expected \"people\""}]}`},
+	} {
+		req = httptest.NewRequest(fiber.MethodPost, "/people", strings.NewReader(tc.body))
+		req.Header.Set(fiber.HeaderContentType, MIMEApplicationJSONAPI)
+		resp, err = app.Test(req)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.status, resp.StatusCode)
+		data, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.expected, string(data))
+	}
+
+	req = httptest.NewRequest(fiber.MethodPost, "/people", strings.NewReader(`{}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+	data, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `{"errors":[{"status":"500","title":"Internal Server Error"}]}`, string(data))
+}
+
+// go test -run Test_SendPage
+func Test_SendPage(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/people", func(c *fiber.Ctx) error {
+		page := PageOf(c, 2, 10)
+		utils.AssertEqual(t, 2, page.Offset())
+		page.Total = 5
+		return SendPage(c, []person{{ID: "3"}, {ID: "4"}}, page)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/people?sort=name&page[number]=2", nil))
+	utils.AssertEqual(t, nil, err)
+	var doc struct {
+		Links Links                  `json:"links"`
+		Meta  map[string]interface{} `json:"meta"`
+	}
+	utils.AssertEqual(t, nil, json.NewDecoder(resp.Body).Decode(&doc))
+	utils.AssertEqual(t, Links{
+		Self:  "/people?sort=name&page%5Bnumber%5D=2&page%5Bsize%5D=2",
+		First: "/people?sort=name&page%5Bnumber%5D=1&page%5Bsize%5D=2",
+		Prev:  "/people?sort=name&page%5Bnumber%5D=1&page%5Bsize%5D=2",
+		Next:  "/people?sort=name&page%5Bnumber%5D=3&page%5Bsize%5D=2",
+		Last:  "/people?sort=name&page%5Bnumber%5D=3&page%5Bsize%5D=2",
+	}, doc.Links)
+	utils.AssertEqual(t, float64(5), doc.Meta["total"])
+}
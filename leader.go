@@ -0,0 +1,284 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2/storage/memory"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// CASStorage is a Storage with an atomic compare-and-swap operation, e.g.
+// implemented by a Lua script in Redis or a transaction in etcd.
+type CASStorage interface {
+	Storage
+
+	// CompareAndSwap sets the value of the key to val, if its current value
+	// equals old. A nil old value means that the key must not exist.
+	// It reports whether the value has been set.
+	CompareAndSwap(key string, old, val []byte, exp time.Duration) (bool, error)
+}
+
+// LeaderConfig defines the config for the leader election.
+type LeaderConfig struct {
+	// Storage holds the lease and is shared by the instances.
+	//
+	// Default: an in memory store for this process only
+	Storage CASStorage
+
+	// Key of the lease in the storage, the instances using the same key
+	// compete for the same leadership.
+	//
+	// Default: "fiber_leader"
+	Key string
+
+	// ID identifies this instance in the lease.
+	//
+	// Default: hostname, process id and a random suffix
+	ID string
+
+	// LeaseDuration is the time the leadership is held without a renewal.
+	//
+	// Default: 15 * time.Second
+	LeaseDuration time.Duration
+
+	// RenewInterval is the time between the attempts to acquire or renew
+	// the lease, it has to be shorter than LeaseDuration.
+	//
+	// Default: LeaseDuration / 3
+	RenewInterval time.Duration
+}
+
+// leaderConfigDefault is the default config
+var leaderConfigDefault = LeaderConfig{
+	Key:           "fiber_leader",
+	LeaseDuration: 15 * time.Second,
+}
+
+// Leader elects a single instance among the instances sharing a storage, e.g.
+// for running scheduled jobs only once in the cluster. The leader holds a lease
+// in the storage, which it renews periodically. If it stops renewing, another
+// instance takes over after the lease expired.
+//
+//	leader := fiber.NewLeader(fiber.LeaderConfig{Storage: redisStorage})
+//	leader.Start()
+//	app.Hooks().OnShutdown(leader.Stop)
+//
+//	// in the cron job
+//	leader.Do(func(token uint64) error {
+//		return sendNewsletter(token)
+//	})
+//
+// Each acquisition of the lease increments its fencing token. A former leader
+// might still run a task after it lost the lease, e.g. after a long GC pause, so
+// tasks writing to shared resources should pass the token along and the
+// resources should reject tokens lower than the highest one seen.
+// The clocks of the instances should be synchronized.
+type Leader struct {
+	config  LeaderConfig
+	storage CASStorage
+
+	mux   sync.RWMutex
+	token uint64
+	// until is the local deadline of the lease held by this instance
+	until time.Time
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// NewLeader creates a new leader election
+func NewLeader(config ...LeaderConfig) *Leader {
+	cfg := leaderConfigDefault
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Storage == nil {
+		cfg.Storage = memory.New()
+	}
+	if cfg.Key == "" {
+		cfg.Key = leaderConfigDefault.Key
+	}
+	if cfg.ID == "" {
+		hostname, _ := os.Hostname()
+		cfg.ID = fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), utils.UUID()[:8])
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = leaderConfigDefault.LeaseDuration
+	}
+	if cfg.RenewInterval <= 0 || cfg.RenewInterval >= cfg.LeaseDuration {
+		cfg.RenewInterval = cfg.LeaseDuration / 3
+	}
+	return &Leader{
+		config:  cfg,
+		storage: cfg.Storage,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Start campaigns for the leadership in the background until Stop is called.
+func (l *Leader) Start() {
+	l.startOnce.Do(func() {
+		go l.run()
+	})
+}
+
+func (l *Leader) run() {
+	defer close(l.stopped)
+	ticker := time.NewTicker(l.config.RenewInterval)
+	defer ticker.Stop()
+	for {
+		// Storage errors are retried with the next tick
+		_, _ = l.Campaign()
+		select {
+		case <-ticker.C:
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// Stop stops campaigning and releases the lease, if this instance holds it,
+// so that another instance can take over right away.
+func (l *Leader) Stop() (err error) {
+	l.stopOnce.Do(func() {
+		close(l.done)
+		// Wait for a running campaign, if started
+		started := true
+		l.startOnce.Do(func() { started = false })
+		if started {
+			<-l.stopped
+		}
+		err = l.release()
+	})
+	return err
+}
+
+// IsLeader reports whether this instance holds the lease and returns its
+// fencing token.
+func (l *Leader) IsLeader() (token uint64, ok bool) {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	if l.token == 0 || !time.Now().Before(l.until) {
+		return 0, false
+	}
+	return l.token, true
+}
+
+// Do runs the task with the fencing token, if this instance is the leader.
+// It reports whether the task has been run.
+func (l *Leader) Do(task func(token uint64) error) (bool, error) {
+	token, ok := l.IsLeader()
+	if !ok {
+		return false, nil
+	}
+	return true, task(token)
+}
+
+// Campaign tries to acquire or renew the lease once and reports whether this
+// instance is the leader. Start calls it every RenewInterval.
+func (l *Leader) Campaign() (bool, error) {
+	// The lease is valid for LeaseDuration from before the storage is accessed
+	start := time.Now()
+
+	old, err := l.storage.Get(l.config.Key)
+	if err != nil {
+		return false, err
+	}
+	if len(old) == 0 {
+		old = nil
+	}
+
+	cur, valid := decodeLease(old)
+	l.mux.RLock()
+	held := l.token != 0 && cur.token == l.token && cur.id == l.config.ID
+	l.mux.RUnlock()
+
+	next := lease{token: cur.token, id: l.config.ID, expiry: start.Add(l.config.LeaseDuration).UnixNano()}
+	if !held {
+		if valid && cur.expiry > start.UnixNano() {
+			// Another instance holds the lease
+			l.demote()
+			return false, nil
+		}
+		next.token++
+	}
+
+	// The lease never expires in the storage, so that the token keeps increasing
+	ok, err := l.storage.CompareAndSwap(l.config.Key, old, next.encode(), 0)
+	if err != nil || !ok {
+		l.demote()
+		return false, err
+	}
+
+	l.mux.Lock()
+	l.token = next.token
+	l.until = start.Add(l.config.LeaseDuration)
+	l.mux.Unlock()
+	return true, nil
+}
+
+// demote gives up the leadership locally
+func (l *Leader) demote() {
+	l.mux.Lock()
+	l.token = 0
+	l.mux.Unlock()
+}
+
+// release expires the lease in the storage, if this instance holds it
+func (l *Leader) release() error {
+	token, ok := l.IsLeader()
+	l.demote()
+	if !ok {
+		return nil
+	}
+	old, err := l.storage.Get(l.config.Key)
+	if err != nil {
+		return err
+	}
+	if cur, _ := decodeLease(old); cur.token != token || cur.id != l.config.ID {
+		return nil
+	}
+	expired := lease{token: token, id: l.config.ID}
+	_, err = l.storage.CompareAndSwap(l.config.Key, old, expired.encode(), 0)
+	return err
+}
+
+// lease is the state of the leadership in the storage
+type lease struct {
+	token uint64
+	// expiry in unix nanoseconds
+	expiry int64
+	id     string
+}
+
+// encode returns the token, the expiry and the id of the lease
+func (ls lease) encode() []byte {
+	b := make([]byte, 16, 16+len(ls.id))
+	binary.BigEndian.PutUint64(b, ls.token)
+	binary.BigEndian.PutUint64(b[8:], uint64(ls.expiry))
+	return append(b, ls.id...)
+}
+
+// decodeLease decodes the lease and reports whether it's well-formed.
+// Malformed leases are treated as expired.
+func decodeLease(b []byte) (lease, bool) {
+	if len(b) < 16 {
+		return lease{}, false
+	}
+	return lease{
+		token:  binary.BigEndian.Uint64(b),
+		expiry: int64(binary.BigEndian.Uint64(b[8:])),
+		id:     string(b[16:]),
+	}, true
+}
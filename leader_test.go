@@ -0,0 +1,111 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/storage/memory"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_Leader
+func Test_Leader(t *testing.T) {
+	t.Parallel()
+	storage := memory.New()
+	a := NewLeader(LeaderConfig{Storage: storage, ID: "a", LeaseDuration: time.Second})
+	b := NewLeader(LeaderConfig{Storage: storage, ID: "b", LeaseDuration: time.Second})
+
+	ok, err := a.Campaign()
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, ok)
+	ok, err = b.Campaign()
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, false, ok)
+
+	token, ok := a.IsLeader()
+	utils.AssertEqual(t, true, ok)
+	utils.AssertEqual(t, uint64(1), token)
+	_, ok = b.IsLeader()
+	utils.AssertEqual(t, false, ok)
+
+	// Only the leader runs the task
+	ran, err := b.Do(func(token uint64) error { return nil })
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, false, ran)
+	ran, err = a.Do(func(token uint64) error {
+		utils.AssertEqual(t, uint64(1), token)
+		return nil
+	})
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, ran)
+
+	// Renewals keep the token
+	ok, _ = a.Campaign()
+	utils.AssertEqual(t, true, ok)
+	token, _ = a.IsLeader()
+	utils.AssertEqual(t, uint64(1), token)
+
+	// Stopping releases the lease, the next leader gets a higher token
+	utils.AssertEqual(t, nil, a.Stop())
+	_, ok = a.IsLeader()
+	utils.AssertEqual(t, false, ok)
+	ok, _ = b.Campaign()
+	utils.AssertEqual(t, true, ok)
+	token, _ = b.IsLeader()
+	utils.AssertEqual(t, uint64(2), token)
+}
+
+// go test -run Test_Leader_Expiry
+func Test_Leader_Expiry(t *testing.T) {
+	t.Parallel()
+	storage := memory.New()
+	a := NewLeader(LeaderConfig{Storage: storage, ID: "a", LeaseDuration: 100 * time.Millisecond})
+	b := NewLeader(LeaderConfig{Storage: storage, ID: "b", LeaseDuration: 100 * time.Millisecond})
+
+	ok, _ := a.Campaign()
+	utils.AssertEqual(t, true, ok)
+
+	// The lease expires without renewals and is taken over
+	time.Sleep(150 * time.Millisecond)
+	_, ok = a.IsLeader()
+	utils.AssertEqual(t, false, ok)
+	ok, _ = b.Campaign()
+	utils.AssertEqual(t, true, ok)
+	token, _ := b.IsLeader()
+	utils.AssertEqual(t, uint64(2), token)
+
+	// The former leader can't renew the lease anymore
+	ok, _ = a.Campaign()
+	utils.AssertEqual(t, false, ok)
+}
+
+// go test -run Test_Leader_Start
+func Test_Leader_Start(t *testing.T) {
+	t.Parallel()
+	storage := memory.New()
+	a := NewLeader(LeaderConfig{Storage: storage, ID: "a", LeaseDuration: 300 * time.Millisecond})
+	b := NewLeader(LeaderConfig{Storage: storage, ID: "b", LeaseDuration: 300 * time.Millisecond})
+
+	a.Start()
+	time.Sleep(50 * time.Millisecond)
+	b.Start()
+
+	// The leader keeps renewing the lease
+	time.Sleep(500 * time.Millisecond)
+	_, ok := a.IsLeader()
+	utils.AssertEqual(t, true, ok)
+	_, ok = b.IsLeader()
+	utils.AssertEqual(t, false, ok)
+
+	// The other instance takes over after the leader stopped
+	utils.AssertEqual(t, nil, a.Stop())
+	time.Sleep(200 * time.Millisecond)
+	token, ok := b.IsLeader()
+	utils.AssertEqual(t, true, ok)
+	utils.AssertEqual(t, uint64(2), token)
+	utils.AssertEqual(t, nil, b.Stop())
+}
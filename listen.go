@@ -12,13 +12,15 @@ import (
 	"io/ioutil"
 	"net"
 	"os"
+	"os/signal"
 	"path/filepath"
-	"reflect"
 	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"text/tabwriter"
+	"time"
 
 	"github.com/gofiber/fiber/v2/internal/colorable"
 	"github.com/gofiber/fiber/v2/internal/isatty"
@@ -45,6 +47,20 @@ func (app *App) Listener(ln net.Listener) error {
 	}
 
 	// Start listening
+	return app.serve(ln)
+}
+
+// serve executes the OnListenData hooks and serves the listener
+func (app *App) serve(ln net.Listener) error {
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	if err != nil {
+		host = ln.Addr().String()
+	}
+	data := ListenData{Host: host, Port: port, TLS: getTlsConfig(ln) != nil, Prefork: IsChild()}
+	if err := app.hooks.executeOnListenDataHooks(data); err != nil {
+		_ = ln.Close()
+		return err
+	}
 	return app.server.Serve(ln)
 }
 
@@ -78,7 +94,36 @@ func (app *App) Listen(addr string) error {
 	}
 
 	// Start listening
-	return app.server.Serve(ln)
+	return app.serve(ln)
+}
+
+// ListenWithGracefulShutdown serves HTTP requests from the given addr like
+// Listen until the process receives SIGINT or SIGTERM, then it shuts the app
+// down gracefully with the timeout, see ShutdownWithTimeout:
+//
+//	if err := app.ListenWithGracefulShutdown(":8080", 30*time.Second); err != nil {
+//		log.Fatal(err)
+//	}
+func (app *App) ListenWithGracefulShutdown(addr string, timeout time.Duration) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- app.Listen(addr)
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-signals:
+	}
+	err := app.ShutdownWithTimeout(timeout)
+	if serveErr := <-served; err == nil {
+		err = serveErr
+	}
+	return err
 }
 
 // ListenTLS serves HTTPS requests from the given addr.
@@ -135,7 +180,7 @@ func (app *App) ListenTLS(addr, certFile, keyFile string) error {
 	app.SetTLSHandler(tlsHandler)
 
 	// Start listening
-	return app.server.Serve(ln)
+	return app.serve(ln)
 }
 
 // ListenMutualTLS serves HTTPS requests from the given addr.
@@ -199,7 +244,7 @@ func (app *App) ListenMutualTLS(addr, certFile, keyFile, clientCertFile string)
 	app.SetTLSHandler(tlsHandler)
 
 	// Start listening
-	return app.server.Serve(ln)
+	return app.serve(ln)
 }
 
 // startupMessage prepares the startup message with the handler number, port, address and other information
@@ -419,8 +464,8 @@ func (app *App) printRoutesMessage() {
 			newRoute.name = route.Name
 			newRoute.method = route.Method
 			newRoute.path = route.Path
-			for _, handler := range route.Handlers {
-				newRoute.handlers += runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name() + " "
+			for _, name := range route.HandlerNames() {
+				newRoute.handlers += name + " "
 			}
 			routes = append(routes, newRoute)
 		}
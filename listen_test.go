@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"os"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -34,6 +36,37 @@ func Test_App_Listen(t *testing.T) {
 	utils.AssertEqual(t, nil, app.Listen(":4003"))
 }
 
+// go test -run Test_App_ListenWithGracefulShutdown
+func Test_App_ListenWithGracefulShutdown(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts can't be sent to the process on windows")
+	}
+	app := New(Config{DisableStartupMessage: true})
+	var shutdown bool
+	app.Hooks().OnShutdown(func() error {
+		shutdown = true
+		return nil
+	})
+	go func() {
+		// Interrupt once the server accepts connections
+		for {
+			conn, err := net.Dial(NetworkTCP4, "127.0.0.1:4007")
+			if err == nil {
+				_ = conn.Close()
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		process, err := os.FindProcess(os.Getpid())
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, nil, process.Signal(os.Interrupt))
+	}()
+
+	utils.AssertEqual(t, nil, app.ListenWithGracefulShutdown("127.0.0.1:4007", time.Second))
+	utils.AssertEqual(t, true, shutdown)
+	utils.AssertEqual(t, true, app.Draining())
+}
+
 // go test -run Test_App_Listen_Prefork
 func Test_App_Listen_Prefork(t *testing.T) {
 	testPreforkMaster = true
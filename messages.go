@@ -0,0 +1,61 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"sort"
+)
+
+// Messages is a catalog of user-facing messages, e.g. the error messages of
+// the built-in middleware, keyed by language tag and message key:
+//
+//	app := fiber.New(fiber.Config{
+//		Messages: fiber.Messages{
+//			"de": {"limiter.limit_reached": "Zu viele Anfragen"},
+//			"*":  {"limiter.limit_reached": "Slow down!"},
+//		},
+//	})
+//
+// The messages of the language "*" are used if no language of the
+// Accept-Language header is available.
+type Messages map[string]map[string]string
+
+// languages returns the languages of the catalog, the most specific tags first
+func (m Messages) languages() []string {
+	languages := make([]string, 0, len(m))
+	for lang := range m {
+		if lang != "*" {
+			languages = append(languages, lang)
+		}
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if len(languages[i]) != len(languages[j]) {
+			return len(languages[i]) > len(languages[j])
+		}
+		return languages[i] < languages[j]
+	})
+	return languages
+}
+
+// Message returns the message of the key from Config.Messages in the language
+// negotiated by the Accept-Language header. If the catalog has no such message,
+// the defaultValue is returned, or an empty string if omitted.
+func (c *Ctx) Message(key string, defaultValue ...string) string {
+	messages := c.app.config.Messages
+	if len(messages) == 0 {
+		return defaultString("", defaultValue)
+	}
+	if header := c.Get(HeaderAcceptLanguage); header != "" {
+		if lang := getOffer(header, c.app.messageLanguages...); lang != "" {
+			if msg, ok := messages[lang][key]; ok {
+				return msg
+			}
+		}
+	}
+	if msg, ok := messages["*"][key]; ok {
+		return msg
+	}
+	return defaultString("", defaultValue)
+}
@@ -0,0 +1,46 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Ctx_Message
+func Test_Ctx_Message(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		Messages: Messages{
+			"de":    {"greeting": "Hallo"},
+			"de-AT": {"greeting": "Servus"},
+			"*":     {"greeting": "Hello", "farewell": "Bye"},
+		},
+	})
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	utils.AssertEqual(t, "Hello", c.Message("greeting"))
+	utils.AssertEqual(t, "default", c.Message("unknown", "default"))
+	utils.AssertEqual(t, "", c.Message("unknown"))
+
+	c.Request().Header.Set(HeaderAcceptLanguage, "de-DE, en;q=0.8")
+	utils.AssertEqual(t, "Hallo", c.Message("greeting"))
+	utils.AssertEqual(t, "Bye", c.Message("farewell"))
+
+	c.Request().Header.Set(HeaderAcceptLanguage, "de-AT")
+	utils.AssertEqual(t, "Servus", c.Message("greeting"))
+
+	c.Request().Header.Set(HeaderAcceptLanguage, "fr, en;q=0.8")
+	utils.AssertEqual(t, "Hello", c.Message("greeting"))
+
+	// Without a catalog the default value is returned
+	app = New()
+	c2 := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c2)
+	utils.AssertEqual(t, "default", c2.Message("greeting", "default"))
+}
@@ -0,0 +1,20 @@
+//go:build go1.18
+// +build go1.18
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+// MetaOf returns the metadata of the key of the route, which handles the
+// request, as T, see Ctx.RouteMeta. It reports false if the route has no
+// value of the key or it isn't a T:
+//
+//	if scopes, ok := fiber.MetaOf[[]string](c, "scopes"); ok {
+//		// check scopes
+//	}
+func MetaOf[T any](c *Ctx, key string) (T, bool) {
+	value, ok := c.RouteMeta(key).(T)
+	return value, ok
+}
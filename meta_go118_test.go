@@ -0,0 +1,38 @@
+//go:build go1.18
+// +build go1.18
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_MetaOf
+func Test_MetaOf(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Use(func(c *Ctx) error {
+		scopes, ok := MetaOf[[]string](c, "scopes")
+		utils.AssertEqual(t, true, ok)
+		utils.AssertEqual(t, []string{"orders:read"}, scopes)
+
+		_, ok = MetaOf[int](c, "scopes")
+		utils.AssertEqual(t, false, ok)
+		_, ok = MetaOf[string](c, "missing")
+		utils.AssertEqual(t, false, ok)
+		return c.Next()
+	})
+	app.Get("/orders", testEmptyHandler)
+	app.Meta("scopes", []string{"orders:read"})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/orders", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+}
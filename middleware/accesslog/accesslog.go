@@ -0,0 +1,146 @@
+package accesslog
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Entry is the structured access log entry of a request
+type Entry struct {
+	Time      time.Time
+	Level     Level
+	Method    string
+	Path      string
+	Route     string // Registered path of the route, e.g. "/users/:id"
+	RouteName string
+	Status    int
+	Latency   time.Duration
+	BytesIn   int
+	BytesOut  int
+	IP        string
+	UserAgent string
+	RequestID string // See fiber.Ctx.RequestID
+	Error     error
+	Fields    []Field // Custom fields, see Config.Fields
+}
+
+// Field is a custom field of an entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Level is the severity of an entry, which is derived from the status
+type Level string
+
+// Levels of the entries
+const (
+	LevelInfo  Level = "INFO"  // Responses up to 3xx
+	LevelWarn  Level = "WARN"  // 4xx responses
+	LevelError Level = "ERROR" // 5xx responses
+)
+
+// levelOf returns the level of a response status
+func levelOf(status int) Level {
+	switch {
+	case status >= fiber.StatusInternalServerError:
+		return LevelError
+	case status >= fiber.StatusBadRequest:
+		return LevelWarn
+	}
+	return LevelInfo
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Add the attributes to the custom fields
+	if len(cfg.Attributes) > 0 {
+		fields := make(map[string]func(c *fiber.Ctx) interface{}, len(cfg.Fields)+len(cfg.Attributes))
+		for key, fn := range cfg.Fields {
+			fields[key] = fn
+		}
+		for _, spec := range cfg.Attributes {
+			attr, err := fiber.ParseAttribute(spec)
+			if err != nil {
+				panic(err)
+			}
+			fields[attr.Name] = func(c *fiber.Ctx) interface{} {
+				return attr.Value(c)
+			}
+		}
+		cfg.Fields = fields
+	}
+
+	// The custom fields are logged in the order of their keys
+	keys := make([]string, 0, len(cfg.Fields))
+	for key := range cfg.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var (
+		once       sync.Once
+		errHandler fiber.ErrorHandler
+	)
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		// Set error handler once
+		once.Do(func() {
+			errHandler = c.App().ErrorHandler
+		})
+
+		start := time.Now()
+
+		// Handle request, store err for logging
+		chainErr := c.Next()
+
+		// Manually call error handler, so that the entry has the status of the error
+		if chainErr != nil {
+			if err := errHandler(c, chainErr); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// The entry may outlive the request, e.g. with an AsyncSink
+		status := c.Response().StatusCode()
+		entry := &Entry{
+			Time:      start,
+			Level:     levelOf(status),
+			Method:    utils.CopyString(c.Method()),
+			Path:      utils.CopyString(c.Path()),
+			Route:     c.Route().Path,
+			RouteName: c.Route().Name,
+			Status:    status,
+			Latency:   time.Since(start),
+			BytesIn:   len(c.Request().Body()),
+			BytesOut:  len(c.Response().Body()),
+			IP:        utils.CopyString(c.IP()),
+			UserAgent: string(c.Request().Header.UserAgent()),
+			RequestID: c.RequestID(),
+			Error:     chainErr,
+		}
+		if len(keys) > 0 {
+			entry.Fields = make([]Field, len(keys))
+			for i, key := range keys {
+				entry.Fields[i] = Field{Key: key, Value: cfg.Fields[key](c)}
+			}
+		}
+		cfg.Sink.Log(entry)
+
+		// End chain
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_AccessLog
+func Test_AccessLog(t *testing.T) {
+	t.Parallel()
+	var entries []*Entry
+	app := fiber.New()
+	app.Use(requestid.New(requestid.Config{Generator: func() string {
+		return "req-1"
+	}}))
+	app.Use(New(Config{
+		Sink: SinkFunc(func(entry *Entry) {
+			entries = append(entries, entry)
+		}),
+		Fields: map[string]func(c *fiber.Ctx) interface{}{
+			"user": func(c *fiber.Ctx) interface{} {
+				return c.Locals("user")
+			},
+		},
+	}))
+	app.Post("/users/:id", func(c *fiber.Ctx) error {
+		c.Locals("user", "john")
+		return c.SendString("created")
+	}).Name("createUser")
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return fiber.ErrBadRequest
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/users/1", bytes.NewBufferString("body")))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, 1, len(entries))
+	entry := entries[0]
+	utils.AssertEqual(t, LevelInfo, entry.Level)
+	utils.AssertEqual(t, fiber.MethodPost, entry.Method)
+	utils.AssertEqual(t, "/users/1", entry.Path)
+	utils.AssertEqual(t, "/users/:id", entry.Route)
+	utils.AssertEqual(t, "createUser", entry.RouteName)
+	utils.AssertEqual(t, fiber.StatusOK, entry.Status)
+	utils.AssertEqual(t, 4, entry.BytesIn)
+	utils.AssertEqual(t, 7, entry.BytesOut)
+	utils.AssertEqual(t, "req-1", entry.RequestID)
+	utils.AssertEqual(t, []Field{{Key: "user", Value: "john"}}, entry.Fields)
+
+	// The status is the one of the error
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+	utils.AssertEqual(t, 2, len(entries))
+	utils.AssertEqual(t, LevelWarn, entries[1].Level)
+	utils.AssertEqual(t, fiber.StatusBadRequest, entries[1].Status)
+	utils.AssertEqual(t, fiber.ErrBadRequest, entries[1].Error)
+}
+
+// go test -run Test_AccessLog_Attributes
+func Test_AccessLog_Attributes(t *testing.T) {
+	t.Parallel()
+	var entry *Entry
+	app := fiber.New()
+	app.Use(New(Config{
+		Sink: SinkFunc(func(e *Entry) {
+			entry = e
+		}),
+		Attributes: []string{"tenant=header:X-Tenant", "param:id"},
+	}))
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Tenant", "acme")
+	_, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, []Field{{Key: "param:id", Value: "1"}, {Key: "tenant", Value: "acme"}}, entry.Fields)
+
+	utils.AssertEqual(t, "fiber: invalid attribute \"unknown:x\"", func() (msg string) {
+		defer func() {
+			msg = fmt.Sprint(recover())
+		}()
+		New(Config{Attributes: []string{"unknown:x"}})
+		return
+	}())
+}
+
+// go test -run Test_JSONSink
+func Test_JSONSink(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	NewJSONSink(&buf).Log(&Entry{
+		Time:      time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC),
+		Level:     LevelError,
+		Method:    fiber.MethodGet,
+		Path:      "/a\"b",
+		Route:     "/*",
+		Status:    fiber.StatusInternalServerError,
+		Latency:   1500 * time.Microsecond,
+		IP:        "0.0.0.0",
+		RequestID: "req-1",
+		Error:     errors.New("line\nbreak"),
+		Fields:    []Field{{Key: "user", Value: map[string]int{"id": 1}}},
+	})
+	utils.AssertEqual(t, `{"time":"2022-01-02T15:04:05Z","level":"ERROR","msg":"GET /a\"b","method":"GET","path":"/a\"b","route":"/*",`+
+		`"status":500,"latency_ms":1.5,"bytes_in":0,"bytes_out":0,"ip":"0.0.0.0","request_id":"req-1","error":"line\u000abreak","user":{"id":1}}`+"\n", buf.String())
+
+	var m map[string]interface{}
+	utils.AssertEqual(t, nil, json.Unmarshal(buf.Bytes(), &m))
+	utils.AssertEqual(t, "line\nbreak", m["error"])
+}
+
+// go test -run Test_AsyncSink
+func Test_AsyncSink(t *testing.T) {
+	t.Parallel()
+	var (
+		mu      sync.Mutex
+		count   int
+		blocked = make(chan struct{})
+	)
+	sink := NewAsyncSink(SinkFunc(func(entry *Entry) {
+		<-blocked
+		mu.Lock()
+		count++
+		mu.Unlock()
+	}), 2)
+
+	// The first entry blocks the goroutine, two are buffered and the rest is dropped
+	sink.Log(&Entry{})
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 4; i++ {
+		sink.Log(&Entry{})
+	}
+	utils.AssertEqual(t, uint64(2), sink.Dropped())
+
+	close(blocked)
+	utils.AssertEqual(t, nil, sink.Close())
+	utils.AssertEqual(t, 3, count)
+}
+
+// go test -v -run=^$ -bench=Benchmark_AccessLog -benchmem -count=4
+func Benchmark_AccessLog(b *testing.B) {
+	var buf bytes.Buffer
+	app := fiber.New()
+	app.Use(New(Config{Sink: NewJSONSink(&buf)}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+	h := app.Handler()
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.SetMethod(fiber.MethodGet)
+	fctx.Request.SetRequestURI("/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		buf.Reset()
+		h(fctx)
+	}
+}
@@ -0,0 +1,57 @@
+package accesslog
+
+import (
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// Sink receives the entries, e.g. an adapter to slog or zerolog. Wrap it
+	// with NewAsyncSink to keep slow outputs off the request path.
+	//
+	// Optional. Default: NewJSONSink(os.Stdout)
+	Sink Sink
+
+	// Fields are custom fields of the entries by their keys, which are
+	// extracted from the request after the handlers, e.g. the user ID.
+	//
+	// Optional. Default: nil
+	Fields map[string]func(c *fiber.Ctx) interface{}
+
+	// Attributes are custom fields declared by attribute specs, e.g.
+	// "tenant=header:X-Tenant", see fiber.ParseAttribute. New panics on
+	// invalid specs.
+	//
+	// Optional. Default: nil
+	Attributes []string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next: nil,
+	Sink: NewJSONSink(os.Stdout),
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Sink == nil {
+		cfg.Sink = ConfigDefault.Sink
+	}
+	return cfg
+}
@@ -0,0 +1,174 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/valyala/bytebufferpool"
+)
+
+// Sink receives the entries of the requests
+type Sink interface {
+	Log(entry *Entry)
+}
+
+// SinkFunc is an adapter to use a function as Sink, e.g. to log the entries
+// with slog or zerolog
+type SinkFunc func(entry *Entry)
+
+// Log calls f(entry)
+func (f SinkFunc) Log(entry *Entry) {
+	f(entry)
+}
+
+// jsonSink writes the entries as JSON lines
+type jsonSink struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewJSONSink returns a Sink, which writes the entries as JSON lines to out.
+// The keys "time", "level" and "msg" are the ones of slog's JSONHandler:
+//
+//	{"time":"2022-01-02T15:04:05.123Z","level":"INFO","msg":"GET /users/1","method":"GET",...}
+func NewJSONSink(out io.Writer) Sink {
+	return &jsonSink{out: out}
+}
+
+// Log writes the entry
+func (s *jsonSink) Log(entry *Entry) {
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+
+	buf.B = append(buf.B, `{"time":`...)
+	buf.B = appendQuote(buf.B, entry.Time.Format(time.RFC3339Nano))
+	buf.B = appendString(buf.B, "level", string(entry.Level))
+	buf.B = appendString(buf.B, "msg", entry.Method+" "+entry.Path)
+	buf.B = appendString(buf.B, "method", entry.Method)
+	buf.B = appendString(buf.B, "path", entry.Path)
+	buf.B = appendString(buf.B, "route", entry.Route)
+	if entry.RouteName != "" {
+		buf.B = appendString(buf.B, "route_name", entry.RouteName)
+	}
+	buf.B = append(buf.B, `,"status":`...)
+	buf.B = strconv.AppendInt(buf.B, int64(entry.Status), 10)
+	buf.B = append(buf.B, `,"latency_ms":`...)
+	buf.B = strconv.AppendFloat(buf.B, float64(entry.Latency)/float64(time.Millisecond), 'f', -1, 64)
+	buf.B = append(buf.B, `,"bytes_in":`...)
+	buf.B = strconv.AppendInt(buf.B, int64(entry.BytesIn), 10)
+	buf.B = append(buf.B, `,"bytes_out":`...)
+	buf.B = strconv.AppendInt(buf.B, int64(entry.BytesOut), 10)
+	buf.B = appendString(buf.B, "ip", entry.IP)
+	if entry.UserAgent != "" {
+		buf.B = appendString(buf.B, "user_agent", entry.UserAgent)
+	}
+	if entry.RequestID != "" {
+		buf.B = appendString(buf.B, "request_id", entry.RequestID)
+	}
+	if entry.Error != nil {
+		buf.B = appendString(buf.B, "error", entry.Error.Error())
+	}
+	for _, field := range entry.Fields {
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			value = appendQuote(nil, err.Error())
+		}
+		buf.B = append(buf.B, ',')
+		buf.B = appendQuote(buf.B, field.Key)
+		buf.B = append(buf.B, ':')
+		buf.B = append(buf.B, value...)
+	}
+	buf.B = append(buf.B, "}\n"...)
+
+	s.mu.Lock()
+	_, _ = s.out.Write(buf.B)
+	s.mu.Unlock()
+}
+
+// appendString appends the key and the string value of a field
+func appendString(dst []byte, key, value string) []byte {
+	dst = append(dst, ',', '"')
+	dst = append(dst, key...)
+	dst = append(dst, '"', ':')
+	return appendQuote(dst, value)
+}
+
+// appendQuote appends the value as JSON string
+func appendQuote(dst []byte, value string) []byte {
+	const hex = "0123456789abcdef"
+	dst = append(dst, '"')
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c < 0x20:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return append(dst, '"')
+}
+
+// AsyncSink passes the entries to a Sink in a background goroutine, so that
+// slow outputs don't block the requests
+type AsyncSink struct {
+	dropped uint64 // Accessed atomically, first for the 64-bit alignment
+	sink    Sink
+	entries chan *Entry
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewAsyncSink returns an AsyncSink, which buffers up to size entries for
+// sink. Entries are dropped while the buffer is full, see Dropped. A size of
+// zero or less buffers 1024 entries.
+func NewAsyncSink(sink Sink, size int) *AsyncSink {
+	if size <= 0 {
+		size = 1024
+	}
+	s := &AsyncSink{
+		sink:    sink,
+		entries: make(chan *Entry, size),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// run passes the entries to the sink until Close is called
+func (s *AsyncSink) run() {
+	for entry := range s.entries {
+		s.sink.Log(entry)
+	}
+	close(s.done)
+}
+
+// Log buffers the entry, it's dropped if the buffer is full
+func (s *AsyncSink) Log(entry *Entry) {
+	select {
+	case s.entries <- entry:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// Dropped returns the number of entries dropped while the buffer was full
+func (s *AsyncSink) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close flushes the buffered entries to the sink, e.g. on shutdown. Entries
+// must not be logged after Close.
+func (s *AsyncSink) Close() error {
+	s.once.Do(func() {
+		close(s.entries)
+	})
+	<-s.done
+	return nil
+}
@@ -48,9 +48,19 @@ func New(config Config) fiber.Handler {
 		username := creds[:index]
 		password := creds[index+1:]
 
-		if cfg.Authorizer(username, password) {
+		var principal interface{}
+		if cfg.Validator != nil {
+			if principal, err = cfg.Validator(c, username, password); err != nil {
+				return err
+			}
+		} else if cfg.Authorizer(username, password) {
+			principal = username
+		}
+
+		if principal != nil {
 			c.Locals(cfg.ContextUsername, username)
 			c.Locals(cfg.ContextPassword, password)
+			c.Locals(cfg.ContextPrincipal, principal)
 			return c.Next()
 		}
 
@@ -93,6 +93,47 @@ func Test_Middleware_BasicAuth(t *testing.T) {
 	}
 }
 
+// go test -run Test_BasicAuth_Validator
+func Test_BasicAuth_Validator(t *testing.T) {
+	t.Parallel()
+	type user struct{ ID int }
+
+	app := fiber.New()
+	app.Use(New(Config{
+		Validator: func(c *fiber.Ctx, username, password string) (interface{}, error) {
+			switch {
+			case username == "db":
+				return nil, fiber.NewError(fiber.StatusServiceUnavailable, "db is down")
+			case username == "john" && password == "doe":
+				return &user{ID: 1}, nil
+			}
+			return nil, nil
+		},
+	}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(fmt.Sprintf("%d %s", c.Locals("principal").(*user).ID, c.Locals("username")))
+	})
+
+	for _, tc := range []struct {
+		creds  string
+		status int
+		body   string
+	}{
+		{"john:doe", fiber.StatusOK, "1 john"},
+		{"john:wrong", fiber.StatusUnauthorized, "Unauthorized"},
+		{"db:secret", fiber.StatusServiceUnavailable, "db is down"},
+	} {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Basic "+b64.StdEncoding.EncodeToString([]byte(tc.creds)))
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.status, resp.StatusCode)
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.body, string(body))
+	}
+}
+
 // go test -v -run=^$ -bench=Benchmark_Middleware_BasicAuth -benchmem -count=4
 func Benchmark_Middleware_BasicAuth(b *testing.B) {
 	app := fiber.New()
@@ -7,6 +7,9 @@ import (
 	"github.com/gofiber/fiber/v2/utils"
 )
 
+// MessageUnauthorized is the key of the response message in fiber.Config.Messages
+const MessageUnauthorized = "basicauth.unauthorized"
+
 // Config defines the config for middleware.
 type Config struct {
 	// Next defines a function to skip this middleware when returned true.
@@ -35,8 +38,17 @@ type Config struct {
 	// Optional. Default: nil.
 	Authorizer func(string, string) bool
 
+	// Validator defines a function to check the credentials with the
+	// request, e.g. against a database, which returns the authenticated
+	// principal. A nil principal rejects the credentials, an error is passed
+	// to the error handler of the app. It takes precedence over Authorizer.
+	//
+	// Optional. Default: nil.
+	Validator func(c *fiber.Ctx, username, password string) (interface{}, error)
+
 	// Unauthorized defines the response body for unauthorized responses.
-	// By default it will return with a 401 Unauthorized and the correct WWW-Auth header
+	// By default it will return with a 401 Unauthorized and the correct WWW-Auth header,
+	// the message can be overridden by MessageUnauthorized in fiber.Config.Messages
 	//
 	// Optional. Default: nil
 	Unauthorized fiber.Handler
@@ -50,17 +62,25 @@ type Config struct {
 	//
 	// Optional. Default: "password"
 	ContextPassword string
+
+	// ContextPrincipal is the key to store the principal returned by
+	// Validator in Locals, it's the username without Validator
+	//
+	// Optional. Default: "principal"
+	ContextPrincipal string
 }
 
 // ConfigDefault is the default config
 var ConfigDefault = Config{
-	Next:            nil,
-	Users:           map[string]string{},
-	Realm:           "Restricted",
-	Authorizer:      nil,
-	Unauthorized:    nil,
-	ContextUsername: "username",
-	ContextPassword: "password",
+	Next:             nil,
+	Users:            map[string]string{},
+	Realm:            "Restricted",
+	Authorizer:       nil,
+	Validator:        nil,
+	Unauthorized:     nil,
+	ContextUsername:  "username",
+	ContextPassword:  "password",
+	ContextPrincipal: "principal",
 }
 
 // Helper function to set default values
@@ -92,7 +112,8 @@ func configDefault(config ...Config) Config {
 	if cfg.Unauthorized == nil {
 		cfg.Unauthorized = func(c *fiber.Ctx) error {
 			c.Set(fiber.HeaderWWWAuthenticate, "basic realm="+cfg.Realm)
-			return c.SendStatus(fiber.StatusUnauthorized)
+			return c.Status(fiber.StatusUnauthorized).SendString(
+				c.Message(MessageUnauthorized, utils.StatusMessage(fiber.StatusUnauthorized)))
 		}
 	}
 	if cfg.ContextUsername == "" {
@@ -101,5 +122,8 @@ func configDefault(config ...Config) Config {
 	if cfg.ContextPassword == "" {
 		cfg.ContextPassword = ConfigDefault.ContextPassword
 	}
+	if cfg.ContextPrincipal == "" {
+		cfg.ContextPrincipal = ConfigDefault.ContextPrincipal
+	}
 	return cfg
 }
@@ -0,0 +1,174 @@
+package batch
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// Request is a sub-request of a batch
+type Request struct {
+	// Method of the sub-request, GET if empty
+	Method string `json:"method"`
+	// Path including the query string, e.g. "/users?page=2"
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is sent as is if it's a JSON string, other JSON values are sent as
+	// application/json
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// Response is the response to a sub-request of a batch
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is embedded as is for JSON responses, other bodies are embedded as
+	// JSON string
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// New creates a new batch endpoint handler. It accepts a JSON array of
+// sub-requests, dispatches them through the router of the app without network
+// hops and responds with a JSON array of their responses in the same order.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		var reqs []Request
+		if err := json.Unmarshal(c.Body(), &reqs); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "batch: the body must be a JSON array of requests")
+		}
+		if len(reqs) > cfg.MaxRequests {
+			return fiber.NewError(fiber.StatusBadRequest, "batch: too many requests")
+		}
+
+		// Collect the inherited headers beforehand, the batch request
+		// mustn't be accessed by concurrent sub-requests
+		d := dispatcher{
+			handler:    c.App().Server().Handler,
+			remoteAddr: c.Context().RemoteAddr(),
+			host:       utils.CopyString(c.Hostname()),
+			path:       utils.CopyString(c.Path()),
+			headers:    make(map[string]string, len(cfg.InheritHeaders)),
+		}
+		for _, key := range cfg.InheritHeaders {
+			if val := c.Get(key); val != "" {
+				d.headers[key] = utils.CopyString(val)
+			}
+		}
+
+		resps := make([]Response, len(reqs))
+		if cfg.Parallel {
+			var wg sync.WaitGroup
+			for i := range reqs {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					resps[i] = d.dispatch(reqs[i])
+				}(i)
+			}
+			wg.Wait()
+		} else {
+			for i := range reqs {
+				resps[i] = d.dispatch(reqs[i])
+			}
+		}
+
+		return c.JSON(resps)
+	}
+}
+
+// dispatcher dispatches the sub-requests of a batch request
+type dispatcher struct {
+	handler    fasthttp.RequestHandler
+	remoteAddr net.Addr
+	host       string
+	// path of the batch endpoint
+	path    string
+	headers map[string]string
+}
+
+// dispatch runs the sub-request through the router
+func (d *dispatcher) dispatch(r Request) Response {
+	if r.Method == "" {
+		r.Method = fiber.MethodGet
+	}
+	if r.Path == "" || r.Path[0] != '/' {
+		return errorResponse(fiber.StatusBadRequest, "batch: the path must start with a slash")
+	}
+	if path := strings.SplitN(r.Path, "?", 2)[0]; strings.EqualFold(strings.TrimRight(path, "/"), strings.TrimRight(d.path, "/")) {
+		return errorResponse(fiber.StatusBadRequest, "batch: nested batch requests are not allowed")
+	}
+
+	var req fasthttp.Request
+	req.Header.SetMethod(strings.ToUpper(r.Method))
+	req.SetRequestURI(r.Path)
+	req.Header.SetHost(d.host)
+	for key, val := range d.headers {
+		req.Header.Set(key, val)
+	}
+	if len(r.Body) > 0 {
+		var s string
+		if err := json.Unmarshal(r.Body, &s); err == nil {
+			req.SetBodyString(s)
+		} else {
+			req.Header.SetContentType(fiber.MIMEApplicationJSON)
+			req.SetBody(r.Body)
+		}
+	}
+	for key, val := range r.Headers {
+		req.Header.Set(key, val)
+	}
+
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Init(&req, d.remoteAddr, nil)
+	d.handler(fctx)
+	// Run the after-response phase of the app once the response is copied
+	defer fctx.ResetUserValues()
+
+	resp := Response{
+		Status:  fctx.Response.StatusCode(),
+		Headers: make(map[string]string),
+	}
+	fctx.Response.Header.VisitAll(func(key, val []byte) {
+		k := string(key)
+		if k == fiber.HeaderContentLength {
+			return
+		}
+		if v, ok := resp.Headers[k]; ok {
+			resp.Headers[k] = v + ", " + string(val)
+		} else {
+			resp.Headers[k] = string(val)
+		}
+	})
+
+	body := fctx.Response.Body()
+	if len(body) == 0 {
+		return resp
+	}
+	ctype := string(fctx.Response.Header.ContentType())
+	if strings.HasPrefix(utils.ParseVendorSpecificContentType(ctype), fiber.MIMEApplicationJSON) && json.Valid(body) {
+		resp.Body = append(json.RawMessage(nil), body...)
+	} else {
+		resp.Body, _ = json.Marshal(string(body))
+	}
+	return resp
+}
+
+// errorResponse returns the response to an invalid sub-request
+func errorResponse(status int, msg string) Response {
+	body, _ := json.Marshal(msg)
+	return Response{Status: status, Body: body}
+}
@@ -0,0 +1,105 @@
+package batch
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+func testApp(config ...Config) *fiber.App {
+	app := fiber.New()
+	app.Post("/batch", New(config...))
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		c.Set("X-User", c.Params("id"))
+		return c.JSON(fiber.Map{"id": c.Params("id"), "auth": c.Get(fiber.HeaderAuthorization)})
+	})
+	app.Post("/echo", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, c.Get(fiber.HeaderContentType))
+		return c.Send(c.Body())
+	})
+	app.Get("/text", func(c *fiber.Ctx) error {
+		return c.SendString("hello " + c.Query("name"))
+	})
+	return app
+}
+
+func doBatch(t *testing.T, app *fiber.App, body string) (int, []Response) {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodPost, "/batch", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer token")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	if resp.StatusCode != fiber.StatusOK {
+		return resp.StatusCode, nil
+	}
+	raw, err := io.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	var resps []Response
+	utils.AssertEqual(t, nil, json.Unmarshal(raw, &resps))
+	return resp.StatusCode, resps
+}
+
+// go test -run Test_Batch
+func Test_Batch(t *testing.T) {
+	t.Parallel()
+	for _, parallel := range []bool{false, true} {
+		app := testApp(Config{Parallel: parallel})
+		status, resps := doBatch(t, app, `[
+			{"path": "/users/1"},
+			{"method": "post", "path": "/echo", "body": {"name": "john"}},
+			{"method": "POST", "path": "/echo", "headers": {"Content-Type": "text/plain"}, "body": "raw text"},
+			{"path": "/text?name=doe"},
+			{"path": "/missing"},
+			{"path": "/batch"},
+			{"path": "relative"}
+		]`)
+		utils.AssertEqual(t, fiber.StatusOK, status)
+		utils.AssertEqual(t, 7, len(resps))
+
+		// Inherited headers and JSON bodies
+		utils.AssertEqual(t, fiber.StatusOK, resps[0].Status)
+		utils.AssertEqual(t, "1", resps[0].Headers["X-User"])
+		utils.AssertEqual(t, fiber.MIMEApplicationJSON, resps[0].Headers[fiber.HeaderContentType])
+		utils.AssertEqual(t, `{"auth":"Bearer token","id":"1"}`, string(resps[0].Body))
+
+		utils.AssertEqual(t, `{"name":"john"}`, string(resps[1].Body))
+		utils.AssertEqual(t, `"raw text"`, string(resps[2].Body))
+		utils.AssertEqual(t, `"hello doe"`, string(resps[3].Body))
+
+		// Failing sub-requests don't fail the batch
+		utils.AssertEqual(t, fiber.StatusNotFound, resps[4].Status)
+		utils.AssertEqual(t, fiber.StatusBadRequest, resps[5].Status)
+		utils.AssertEqual(t, fiber.StatusBadRequest, resps[6].Status)
+	}
+}
+
+// go test -run Test_Batch_Invalid
+func Test_Batch_Invalid(t *testing.T) {
+	t.Parallel()
+	app := testApp(Config{MaxRequests: 1})
+
+	status, _ := doBatch(t, app, `{"path": "/users/1"}`)
+	utils.AssertEqual(t, fiber.StatusBadRequest, status)
+
+	status, _ = doBatch(t, app, `[{"path": "/users/1"}, {"path": "/users/2"}]`)
+	utils.AssertEqual(t, fiber.StatusBadRequest, status)
+}
+
+// go test -run Test_Batch_Next
+func Test_Batch_Next(t *testing.T) {
+	t.Parallel()
+	app := testApp(Config{
+		Next: func(_ *fiber.Ctx) bool {
+			return true
+		},
+	})
+
+	status, _ := doBatch(t, app, `[]`)
+	utils.AssertEqual(t, fiber.StatusNotFound, status)
+}
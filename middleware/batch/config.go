@@ -0,0 +1,62 @@
+package batch
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// MaxRequests is the maximum number of sub-requests in a batch.
+	//
+	// Optional. Default: 20
+	MaxRequests int
+
+	// Parallel dispatches the sub-requests concurrently instead of one after another.
+	//
+	// Optional. Default: false
+	Parallel bool
+
+	// InheritHeaders are the headers of the batch request, which are copied to
+	// the sub-requests unless they set them themselves.
+	//
+	// Optional. Default: Authorization, Cookie, Accept-Language and User-Agent
+	InheritHeaders []string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:        nil,
+	MaxRequests: 20,
+	Parallel:    false,
+	InheritHeaders: []string{
+		fiber.HeaderAuthorization,
+		fiber.HeaderCookie,
+		fiber.HeaderAcceptLanguage,
+		fiber.HeaderUserAgent,
+	},
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.MaxRequests <= 0 {
+		cfg.MaxRequests = ConfigDefault.MaxRequests
+	}
+	if cfg.InheritHeaders == nil {
+		cfg.InheritHeaders = ConfigDefault.InheritHeaders
+	}
+	return cfg
+}
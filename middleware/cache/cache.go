@@ -4,6 +4,7 @@ package cache
 
 import (
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -17,14 +18,20 @@ import (
 // time it should not be too short to avoid overwhelming of the system
 const timestampUpdatePeriod = 300 * time.Millisecond
 
+// revalidateKey is the locals key marking the requests, which refresh an
+// entry in the background for StaleWhileRevalidate
+const revalidateKey = "cache_revalidate"
+
 // cache status
 // unreachable: when cache is bypass, or invalid
 // hit: cache is served
 // miss: do not have cache record
+// stale: expired cache is served, since the handler failed or the entry is revalidated
 const (
 	cacheUnreachable = "unreachable"
 	cacheHit         = "hit"
 	cacheMiss        = "miss"
+	cacheStale       = "stale"
 )
 
 var ignoreHeaders = map[string]interface{}{
@@ -40,6 +47,21 @@ var ignoreHeaders = map[string]interface{}{
 	"Content-Encoding":    nil, // already stored explicitely by the cache manager
 }
 
+// setResponse sets the response from the cache entry
+func setResponse(c *fiber.Ctx, e *item) {
+	c.Response().SetBodyRaw(e.body)
+	c.Response().SetStatusCode(e.status)
+	c.Response().Header.SetContentTypeBytes(e.ctype)
+	if len(e.cencoding) > 0 {
+		c.Response().Header.SetBytesV(fiber.HeaderContentEncoding, e.cencoding)
+	}
+	if e.headers != nil {
+		for k, v := range e.headers {
+			c.Response().Header.SetBytesV(k, v)
+		}
+	}
+}
+
 // New creates a new middleware handler
 func New(config ...Config) fiber.Handler {
 	// Set default config
@@ -65,12 +87,30 @@ func New(config ...Config) fiber.Handler {
 	var storedBytes uint = 0
 
 	// Update timestamp in the configured interval
-	go func() {
-		for {
-			atomic.StoreUint64(&timestamp, uint64(time.Now().Unix()))
-			time.Sleep(timestampUpdatePeriod)
+	now := cfg.now
+	if now == nil {
+		go func() {
+			for {
+				atomic.StoreUint64(&timestamp, uint64(time.Now().Unix()))
+				time.Sleep(timestampUpdatePeriod)
+			}
+		}()
+		now = func() uint64 {
+			return atomic.LoadUint64(&timestamp)
 		}
-	}()
+	}
+
+	// Seconds which expired entries are kept for StaleIfError and StaleWhileRevalidate
+	staleIfError := uint64(cfg.StaleIfError.Seconds())
+	staleWhileRevalidate := uint64(cfg.StaleWhileRevalidate.Seconds())
+	keep := staleIfError
+	if staleWhileRevalidate > keep {
+		keep = staleWhileRevalidate
+	}
+	// Channels of the requests which are executing the handler for a key
+	inflight := make(map[string]chan struct{})
+	// Keys which are refreshed in the background for StaleWhileRevalidate
+	revalidating := make(map[string]bool)
 
 	// Delete key from both manager and storage
 	deleteKey := func(dkey string) {
@@ -81,8 +121,18 @@ func New(config ...Config) fiber.Handler {
 		}
 	}
 
-	// Return new handler
-	return func(c *fiber.Ctx) error {
+	// Index the cached keys for the invalidation ( see invalidate.go )
+	index := newKeyIndex(mux, func(dkey string, heapidx int) {
+		deleteKey(dkey)
+		if cfg.MaxBytes > 0 {
+			_, size := heap.remove(heapidx)
+			storedBytes -= size
+		}
+	})
+
+	// The handler refreshes the entries for StaleWhileRevalidate with itself
+	var handler fiber.Handler
+	handler = func(c *fiber.Ctx) error {
 		// Only cache selected methods
 		var isExists bool
 		for _, method := range cfg.Methods {
@@ -96,63 +146,141 @@ func New(config ...Config) fiber.Handler {
 			return c.Next()
 		}
 
+		// Respect the Cache-Control directives of the request
+		refresh := c.Locals(revalidateKey) != nil
+		if cfg.HonorCacheControl {
+			if d := parseDirectives(c.Get(fiber.HeaderCacheControl)); d.noStore {
+				c.Set(cfg.CacheHeader, cacheUnreachable)
+				return c.Next()
+			} else if d.noCache || d.maxAge == 0 {
+				refresh = true
+			}
+		}
+
 		// Get key from request
 		// TODO(allocation optimization): try to minimize the allocation from 2 to 1
-		key := cfg.KeyGenerator(c) + "_" + c.Method()
+		baseKey := cfg.KeyGenerator(c)
+		key := baseKey
+		for _, header := range cfg.VaryHeaders {
+			key += "|" + c.Get(header)
+		}
+		key += "_" + c.Method()
+
+		var (
+			e      *item
+			ts     uint64
+			stale  *item
+			waited bool
+		)
+		for {
+			// Get entry from pool
+			e = manager.get(key)
 
-		// Get entry from pool
-		e := manager.get(key)
+			// Lock entry
+			mux.Lock()
 
-		// Lock entry
-		mux.Lock()
+			// Get timestamp
+			ts = now()
+
+			// Check if entry is expired
+			if e.exp != 0 && ts >= e.exp+keep {
+				deleteKey(key)
+				delete(index.entries, key)
+				if cfg.MaxBytes > 0 {
+					_, size := heap.remove(e.heapidx)
+					storedBytes -= size
+				}
+			} else if e.exp != 0 && ts < e.exp && !refresh {
+				// Separate body value to avoid msgp serialization
+				// We can store raw bytes with Storage 👍
+				if cfg.Storage != nil {
+					e.body = manager.getRaw(key + "_body")
+				}
+				// Set response headers from cache
+				setResponse(c, e)
+				// Set Cache-Control header if enabled
+				if cfg.CacheControl {
+					maxAge := strconv.FormatUint(e.exp-ts, 10)
+					c.Set(fiber.HeaderCacheControl, "public, max-age="+maxAge)
+				}
 
-		// Get timestamp
-		ts := atomic.LoadUint64(&timestamp)
+				c.Set(cfg.CacheHeader, cacheHit)
 
-		// Check if entry is expired
-		if e.exp != 0 && ts >= e.exp {
-			deleteKey(key)
-			if cfg.MaxBytes > 0 {
-				_, size := heap.remove(e.heapidx)
-				storedBytes -= size
-			}
-		} else if e.exp != 0 {
-			// Separate body value to avoid msgp serialization
-			// We can store raw bytes with Storage 👍
-			if cfg.Storage != nil {
-				e.body = manager.getRaw(key + "_body")
-			}
-			// Set response headers from cache
-			c.Response().SetBodyRaw(e.body)
-			c.Response().SetStatusCode(e.status)
-			c.Response().Header.SetContentTypeBytes(e.ctype)
-			if len(e.cencoding) > 0 {
-				c.Response().Header.SetBytesV(fiber.HeaderContentEncoding, e.cencoding)
-			}
-			if e.headers != nil {
-				for k, v := range e.headers {
-					c.Response().Header.SetBytesV(k, v)
+				mux.Unlock()
+
+				// Return response
+				return nil
+			} else if e.exp != 0 {
+				if cfg.Storage != nil {
+					e.body = manager.getRaw(key + "_body")
+				}
+				// Serve the expired entry and refresh it in the background
+				if !refresh && ts < e.exp+staleWhileRevalidate {
+					setResponse(c, e)
+					c.Set(cfg.CacheHeader, cacheStale)
+					cfg.Metrics.addStaleServe()
+					if !revalidating[key] {
+						revalidating[key] = true
+						revalidate(c, handler, func() {
+							mux.Lock()
+							delete(revalidating, key)
+							mux.Unlock()
+						})
+					}
+					mux.Unlock()
+					return nil
+				}
+				// Keep the entry in case the handler fails
+				if ts < e.exp+staleIfError {
+					stale = e
 				}
 			}
-			// Set Cache-Control header if enabled
-			if cfg.CacheControl {
-				maxAge := strconv.FormatUint(e.exp-ts, 10)
-				c.Set(fiber.HeaderCacheControl, "public, max-age="+maxAge)
+
+			// Let concurrent requests for the same key wait for the first one
+			if cfg.StampedeProtection {
+				if done, ok := inflight[key]; ok && !waited {
+					mux.Unlock()
+					cfg.Metrics.addLockWait()
+					timer := time.NewTimer(cfg.LockTimeout)
+					select {
+					case <-done:
+					case <-timer.C:
+						cfg.Metrics.addLockTimeout()
+					}
+					timer.Stop()
+					// Look up the entry again, the handler is executed if it's still missing
+					waited = true
+					continue
+				} else if !ok {
+					done = make(chan struct{})
+					inflight[key] = done
+					defer func() {
+						mux.Lock()
+						delete(inflight, key)
+						mux.Unlock()
+						close(done)
+					}()
+				}
 			}
+			break
+		}
 
-			c.Set(cfg.CacheHeader, cacheHit)
+		// make sure we're not blocking concurrent requests - do unlock
+		mux.Unlock()
 
+		// Continue stack, serve the expired entry if it fails
+		err := c.Next()
+		if stale != nil && (err != nil || c.Response().StatusCode() >= fiber.StatusInternalServerError) {
+			mux.Lock()
+			setResponse(c, stale)
 			mux.Unlock()
-
-			// Return response
+			c.Set(cfg.CacheHeader, cacheStale)
+			cfg.Metrics.addStaleServe()
 			return nil
 		}
 
-		// make sure we're not blocking concurrent requests - do unlock
-		mux.Unlock()
-
-		// Continue stack, return err to Fiber if exist
-		if err := c.Next(); err != nil {
+		// Return err to Fiber if exist
+		if err != nil {
 			return err
 		}
 
@@ -168,16 +296,37 @@ func New(config ...Config) fiber.Handler {
 
 		// Don't try to cache if body won't fit into cache
 		bodySize := uint(len(c.Response().Body()))
-		if cfg.MaxBytes > 0 && bodySize > cfg.MaxBytes {
+		if (cfg.MaxBytes > 0 && bodySize > cfg.MaxBytes) || (cfg.MaxBodySize > 0 && bodySize > cfg.MaxBodySize) {
 			c.Set(cfg.CacheHeader, cacheUnreachable)
 			return nil
 		}
 
+		// Respect the Cache-Control directives of the response
+		var d directives
+		if cfg.HonorCacheControl {
+			if d = parseDirectives(c.GetRespHeader(fiber.HeaderCacheControl)); d.noStore || d.noCache || d.private {
+				c.Set(cfg.CacheHeader, cacheUnreachable)
+				return nil
+			}
+		}
+
+		// Remove the entry which is replaced, i.e. the expired entry or
+		// the entry of a concurrent request
+		if cfg.MaxBytes > 0 {
+			if cur := manager.get(key); cur.exp != 0 {
+				_, size := heap.remove(cur.heapidx)
+				storedBytes -= size
+			} else {
+				manager.release(cur)
+			}
+		}
+
 		// Remove oldest to make room for new
 		if cfg.MaxBytes > 0 {
 			for storedBytes+bodySize > cfg.MaxBytes {
 				key, size := heap.removeFirst()
 				deleteKey(key)
+				delete(index.entries, key)
 				storedBytes -= size
 			}
 		}
@@ -208,15 +357,23 @@ func New(config ...Config) fiber.Handler {
 		// Calculate expiration by response header or other setting
 		if cfg.ExpirationGenerator != nil {
 			expiration = cfg.ExpirationGenerator(c, &cfg)
+		} else if cfg.HonorCacheControl && d.maxAge > 0 {
+			expiration = time.Duration(d.maxAge) * time.Second
 		}
 		e.exp = ts + uint64(expiration.Seconds())
+		// Keep the entry in the storage while it may be served stale
+		expiration += time.Duration(keep) * time.Second
 
 		// Store entry in heap
 		if cfg.MaxBytes > 0 {
-			e.heapidx = heap.put(key, e.exp, bodySize)
+			e.heapidx = heap.put(key, e.exp+keep, bodySize)
 			storedBytes += bodySize
 		}
 
+		// Index the key with the tags declared by the handler
+		tags, _ := c.Locals(tagsKey).([]string)
+		index.add(key, indexEntry{key: baseKey, tags: tags, exp: e.exp + keep, heapidx: e.heapidx}, ts)
+
 		// For external Storage we store raw body separated
 		if cfg.Storage != nil {
 			manager.setRaw(key+"_body", e.body, expiration)
@@ -234,4 +391,64 @@ func New(config ...Config) fiber.Handler {
 		// Finish response
 		return nil
 	}
+
+	// Return new handler
+	return handler
+}
+
+// revalidate executes the handler of the middleware for a fork of the request
+// in the background, so that the refreshed response is cached. Only the
+// handlers after the middleware are executed again, e.g. a limiter before it
+// doesn't count the revalidation. done is called when it has finished.
+func revalidate(c *fiber.Ctx, handler fiber.Handler, done func()) {
+	// The request is forked, since the context is released after the response
+	app, fork := c.App(), c.Fork()
+	fork.Locals(revalidateKey, true)
+
+	go func() {
+		defer done()
+		defer app.ReleaseCtx(fork)
+		_ = handler(fork)
+	}()
+}
+
+// directives are the Cache-Control directives the middleware respects
+type directives struct {
+	noStore bool
+	noCache bool
+	private bool
+	// s-maxage or max-age in seconds, -1 if missing
+	maxAge int
+}
+
+// parseDirectives parses the Cache-Control header
+func parseDirectives(header string) directives {
+	d := directives{maxAge: -1}
+	sMaxAge := -1
+	for _, part := range strings.Split(header, ",") {
+		name, value := utils.Trim(part, ' '), ""
+		if i := strings.IndexByte(name, '='); i >= 0 {
+			name, value = utils.Trim(name[:i], ' '), strings.Trim(utils.Trim(name[i+1:], ' '), `"`)
+		}
+		switch utils.ToLower(name) {
+		case "no-store":
+			d.noStore = true
+		case "no-cache":
+			d.noCache = true
+		case "private":
+			d.private = true
+		case "max-age":
+			if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+				d.maxAge = n
+			}
+		case "s-maxage":
+			if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+				sMaxAge = n
+			}
+		}
+	}
+	if sMaxAge >= 0 {
+		d.maxAge = sMaxAge
+	}
+	return d
 }
@@ -11,11 +11,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/internal/storage/memory"
+	"github.com/gofiber/fiber/v2/storage/memory"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/valyala/fasthttp"
 )
@@ -343,6 +345,34 @@ func Test_CustomKey(t *testing.T) {
 	utils.AssertEqual(t, true, called)
 }
 
+// go test -run Test_Cache_QueryKey
+func Test_Cache_QueryKey(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{QueryKey: true}))
+
+	count := 0
+	app.Get("/search", func(c *fiber.Ctx) error {
+		count++
+		return c.SendString(strconv.Itoa(count))
+	})
+
+	test := func(target string) string {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		utils.AssertEqual(t, nil, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		return string(body)
+	}
+
+	utils.AssertEqual(t, "1", test("/search?q=go&page=2"))
+	utils.AssertEqual(t, "1", test("/search?page=2&q=go&utm_source=mail"))
+	utils.AssertEqual(t, "1", test("/search?page=2&q=%67o&&"))
+	utils.AssertEqual(t, "2", test("/search?q=go&page=3"))
+	utils.AssertEqual(t, "3", test("/search"))
+}
+
 func Test_CustomExpiration(t *testing.T) {
 	t.Parallel()
 
@@ -624,6 +654,344 @@ func Test_Cache_MaxBytesSizes(t *testing.T) {
 	}
 }
 
+// go test -run Test_Cache_Invalidate
+func Test_Cache_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{MaxBytes: 100}))
+
+	app.Get("/invalidate/*", func(c *fiber.Ctx) error {
+		return c.SendString("1")
+	})
+
+	for _, path := range []string{"/invalidate/users/1", "/invalidate/users/2", "/invalidate/posts/1"} {
+		rsp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, cacheMiss, rsp.Header.Get("X-Cache"))
+	}
+
+	n, err := Invalidate("/invalidate/users/*")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, 2, n)
+
+	cases := [][]string{
+		{"/invalidate/users/1", cacheMiss},
+		{"/invalidate/users/2", cacheMiss},
+		{"/invalidate/posts/1", cacheHit},
+	}
+	for idx, tcase := range cases {
+		rsp, err := app.Test(httptest.NewRequest("GET", tcase[0], nil))
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tcase[1], rsp.Header.Get("X-Cache"), fmt.Sprintf("Case %v", idx))
+	}
+
+	_, err = Invalidate("/invalidate/[")
+	utils.AssertEqual(t, true, err != nil)
+}
+
+// go test -run Test_Cache_InvalidateTags
+func Test_Cache_InvalidateTags(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New())
+
+	app.Get("/tags/:id", func(c *fiber.Ctx) error {
+		Tag(c, "test-users", "test-user:"+c.Params("id"))
+		return c.SendString(c.Params("id"))
+	})
+	app.Put("/tags/:id", func(c *fiber.Ctx) error {
+		return c.SendString(strconv.Itoa(InvalidateTags("test-user:" + c.Params("id"))))
+	})
+
+	for _, path := range []string{"/tags/1", "/tags/2", "/tags/1"} {
+		_, err := app.Test(httptest.NewRequest("GET", path, nil))
+		utils.AssertEqual(t, nil, err)
+	}
+
+	rsp, err := app.Test(httptest.NewRequest("PUT", "/tags/1", nil))
+	utils.AssertEqual(t, nil, err)
+	body, err := ioutil.ReadAll(rsp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "1", string(body))
+
+	rsp, err = app.Test(httptest.NewRequest("GET", "/tags/1", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, cacheMiss, rsp.Header.Get("X-Cache"))
+	rsp, err = app.Test(httptest.NewRequest("GET", "/tags/2", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, cacheHit, rsp.Header.Get("X-Cache"))
+
+	utils.AssertEqual(t, 2, InvalidateTags("test-users"))
+}
+
+// go test -run Test_Cache_StampedeProtection -race
+func Test_Cache_StampedeProtection(t *testing.T) {
+	t.Parallel()
+
+	metrics := &Metrics{}
+	app := fiber.New()
+	app.Use(New(Config{
+		StampedeProtection: true,
+		Metrics:            metrics,
+	}))
+
+	var calls int32
+	app.Get("/stampede", func(c *fiber.Ctx) error {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		return c.SendString("1")
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rsp, err := app.Test(httptest.NewRequest("GET", "/stampede", nil))
+			utils.AssertEqual(t, nil, err)
+			body, err := ioutil.ReadAll(rsp.Body)
+			utils.AssertEqual(t, nil, err)
+			utils.AssertEqual(t, "1", string(body))
+		}()
+	}
+	wg.Wait()
+
+	utils.AssertEqual(t, int32(1), atomic.LoadInt32(&calls))
+	utils.AssertEqual(t, uint64(9), metrics.LockWaits())
+	utils.AssertEqual(t, uint64(0), metrics.LockTimeouts())
+}
+
+// go test -run Test_Cache_StaleIfError
+func Test_Cache_StaleIfError(t *testing.T) {
+	t.Parallel()
+
+	metrics := &Metrics{}
+	app := fiber.New()
+	app.Use(New(Config{
+		Expiration:   1 * time.Second,
+		StaleIfError: 10 * time.Second,
+		Metrics:      metrics,
+	}))
+
+	var fail int32
+	app.Get("/", func(c *fiber.Ctx) error {
+		if atomic.LoadInt32(&fail) == 1 {
+			return fiber.ErrServiceUnavailable
+		}
+		return c.SendString("fresh")
+	})
+
+	rsp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, cacheMiss, rsp.Header.Get("X-Cache"))
+
+	atomic.StoreInt32(&fail, 1)
+	time.Sleep(1500 * time.Millisecond)
+
+	rsp, err = app.Test(httptest.NewRequest("GET", "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, rsp.StatusCode)
+	utils.AssertEqual(t, cacheStale, rsp.Header.Get("X-Cache"))
+	body, err := ioutil.ReadAll(rsp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "fresh", string(body))
+	utils.AssertEqual(t, uint64(1), metrics.StaleServes())
+
+	// A successful response replaces the expired entry
+	atomic.StoreInt32(&fail, 0)
+	rsp, err = app.Test(httptest.NewRequest("GET", "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, cacheMiss, rsp.Header.Get("X-Cache"))
+	rsp, err = app.Test(httptest.NewRequest("GET", "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, cacheHit, rsp.Header.Get("X-Cache"))
+}
+
+// testClock is the clock of the cache in the tests, see Config.now
+type testClock struct {
+	ts uint64
+}
+
+func newTestClock() *testClock {
+	return &testClock{ts: uint64(time.Now().Unix())}
+}
+
+func (c *testClock) now() uint64 {
+	return atomic.LoadUint64(&c.ts)
+}
+
+func (c *testClock) advance(d time.Duration) {
+	atomic.AddUint64(&c.ts, uint64(d.Seconds()))
+}
+
+// go test -run Test_Cache_StaleWhileRevalidate
+func Test_Cache_StaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	clock := newTestClock()
+	app := fiber.New()
+	// The middleware before the cache, e.g. a limiter, sees the requests only
+	var requests int32
+	app.Use(func(c *fiber.Ctx) error {
+		atomic.AddInt32(&requests, 1)
+		c.Locals("user", "john")
+		return c.Next()
+	})
+	app.Use(New(Config{
+		Expiration:           1 * time.Second,
+		StaleWhileRevalidate: 10 * time.Second,
+		now:                  clock.now,
+	}))
+
+	var version int32
+	app.Get("/", func(c *fiber.Ctx) error {
+		// The revalidation keeps the Locals of the request
+		if c.Locals("user") != "john" {
+			return fiber.ErrUnauthorized
+		}
+		return c.SendString(strconv.Itoa(int(atomic.AddInt32(&version, 1))))
+	})
+
+	var sent int32
+	request := func() (string, string) {
+		sent++
+		rsp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		utils.AssertEqual(t, nil, err)
+		body, err := ioutil.ReadAll(rsp.Body)
+		utils.AssertEqual(t, nil, err)
+		return rsp.Header.Get("X-Cache"), string(body)
+	}
+
+	status, body := request()
+	utils.AssertEqual(t, cacheMiss, status)
+	utils.AssertEqual(t, "1", body)
+
+	clock.advance(2 * time.Second)
+
+	// The expired entry is served, while it's refreshed in the background
+	status, body = request()
+	utils.AssertEqual(t, cacheStale, status)
+	utils.AssertEqual(t, "1", body)
+
+	// Wait for the refreshed response to be cached, the clock stands still
+	for i := 0; i < 500 && status != cacheHit; i++ {
+		time.Sleep(10 * time.Millisecond)
+		status, body = request()
+	}
+	utils.AssertEqual(t, cacheHit, status)
+	utils.AssertEqual(t, "2", body)
+	utils.AssertEqual(t, int32(2), atomic.LoadInt32(&version))
+	utils.AssertEqual(t, sent, atomic.LoadInt32(&requests))
+}
+
+// go test -run Test_Cache_HonorCacheControl
+func Test_Cache_HonorCacheControl(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{HonorCacheControl: true, now: newTestClock().now}))
+
+	var count int32
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(strconv.Itoa(int(atomic.AddInt32(&count, 1))))
+	})
+	app.Get("/private", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "private, max-age=60")
+		return c.SendString("private")
+	})
+
+	request := func(target, cacheControl string) (string, string) {
+		req := httptest.NewRequest("GET", target, nil)
+		if cacheControl != "" {
+			req.Header.Set(fiber.HeaderCacheControl, cacheControl)
+		}
+		rsp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err)
+		body, err := ioutil.ReadAll(rsp.Body)
+		utils.AssertEqual(t, nil, err)
+		return rsp.Header.Get("X-Cache"), string(body)
+	}
+
+	status, body := request("/", "no-store")
+	utils.AssertEqual(t, cacheUnreachable, status)
+	utils.AssertEqual(t, "1", body)
+
+	status, _ = request("/", "")
+	utils.AssertEqual(t, cacheMiss, status)
+	status, body = request("/", "")
+	utils.AssertEqual(t, cacheHit, status)
+	utils.AssertEqual(t, "2", body)
+
+	// no-cache and max-age=0 refresh the entry
+	status, body = request("/", "no-cache")
+	utils.AssertEqual(t, cacheMiss, status)
+	utils.AssertEqual(t, "3", body)
+	status, body = request("/", "max-age=0")
+	utils.AssertEqual(t, cacheMiss, status)
+	utils.AssertEqual(t, "4", body)
+	status, body = request("/", "")
+	utils.AssertEqual(t, cacheHit, status)
+	utils.AssertEqual(t, "4", body)
+
+	status, _ = request("/private", "")
+	utils.AssertEqual(t, cacheUnreachable, status)
+
+	d := parseDirectives(`public, S-MaxAge="30", max-age=10`)
+	utils.AssertEqual(t, directives{maxAge: 30}, d)
+	d = parseDirectives("no-store,no-cache , private, max-age=x")
+	utils.AssertEqual(t, directives{noStore: true, noCache: true, private: true, maxAge: -1}, d)
+}
+
+// go test -run Test_Cache_VaryHeaders
+func Test_Cache_VaryHeaders(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{
+		VaryHeaders: []string{fiber.HeaderAcceptLanguage},
+		MaxBodySize: 5,
+	}))
+
+	app.Get("/vary-headers", func(c *fiber.Ctx) error {
+		return c.SendString(c.Get(fiber.HeaderAcceptLanguage))
+	})
+	app.Get("/vary-headers/large", func(c *fiber.Ctx) error {
+		return c.SendString("too large")
+	})
+
+	request := func(target, lang string) (string, string) {
+		req := httptest.NewRequest("GET", target, nil)
+		req.Header.Set(fiber.HeaderAcceptLanguage, lang)
+		rsp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err)
+		body, err := ioutil.ReadAll(rsp.Body)
+		utils.AssertEqual(t, nil, err)
+		return rsp.Header.Get("X-Cache"), string(body)
+	}
+
+	status, _ := request("/vary-headers", "en")
+	utils.AssertEqual(t, cacheMiss, status)
+	status, _ = request("/vary-headers", "de")
+	utils.AssertEqual(t, cacheMiss, status)
+	status, body := request("/vary-headers", "de")
+	utils.AssertEqual(t, cacheHit, status)
+	utils.AssertEqual(t, "de", body)
+	status, body = request("/vary-headers", "en")
+	utils.AssertEqual(t, cacheHit, status)
+	utils.AssertEqual(t, "en", body)
+
+	// Delete removes all variants of the key
+	utils.AssertEqual(t, 2, Delete("/vary-headers"))
+	status, _ = request("/vary-headers", "en")
+	utils.AssertEqual(t, cacheMiss, status)
+
+	// Bodies larger than MaxBodySize aren't cached
+	status, _ = request("/vary-headers/large", "en")
+	utils.AssertEqual(t, cacheUnreachable, status)
+}
+
 // go test -v -run=^$ -bench=Benchmark_Cache -benchmem -count=4
 func Benchmark_Cache(b *testing.B) {
 	app := fiber.New()
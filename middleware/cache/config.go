@@ -39,6 +39,25 @@ type Config struct {
 	// }
 	KeyGenerator func(*fiber.Ctx) string
 
+	// QueryKey adds the canonical query string of the request to the default
+	// key, so requests with the params in another order share the cached
+	// response, see fiber.CanonicalQuery.
+	//
+	// Optional. Default: false
+	QueryKey bool
+
+	// IgnoreQueryParams are left out of the query string of the key, if
+	// QueryKey is enabled. A trailing "*" matches the params with the prefix.
+	//
+	// Optional. Default: fiber.TrackingParams
+	IgnoreQueryParams []string
+
+	// VaryHeaders are the request headers, whose values are added to the key,
+	// e.g. Accept-Language when the response depends on it.
+	//
+	// Optional. Default: nil
+	VaryHeaders []string
+
 	// allows you to generate custom Expiration Key By Key, default is Expiration (Optional)
 	//
 	// Default: nil
@@ -67,11 +86,62 @@ type Config struct {
 	// Default: 0
 	MaxBytes uint
 
+	// MaxBodySize is the max number of bytes of a response body to be cached,
+	// larger responses aren't cached. 0 means no limit
+	//
+	// Default: 0
+	MaxBodySize uint
+
+	// HonorCacheControl respects the Cache-Control directives. Requests with
+	// no-store skip the cache, requests with no-cache or max-age=0 aren't
+	// served from the cache but refresh it. Responses with no-store, no-cache
+	// or private aren't cached, the s-maxage or max-age of responses is their
+	// expiration, unless an ExpirationGenerator is set.
+	//
+	// Default: false
+	HonorCacheControl bool
+
 	// You can specify HTTP methods to cache.
 	// The middleware just caches the routes of its methods in this slice.
 	//
 	// Default: []string{fiber.MethodGet, fiber.MethodHead}
 	Methods []string
+
+	// StampedeProtection lets concurrent requests for a missing or expired entry
+	// wait for the first request to cache the response, instead of all of them
+	// executing the handler when a popular entry expires.
+	//
+	// Default: false
+	StampedeProtection bool
+
+	// LockTimeout is the maximum time a request waits for a concurrent request
+	// of the same key, before it executes the handler itself.
+	//
+	// Default: 5 * time.Second
+	LockTimeout time.Duration
+
+	// StaleIfError keeps expired responses for the given duration and serves them,
+	// if the handler returns an error or a 5xx status code. The cache header
+	// is "stale" then.
+	//
+	// Default: 0
+	StaleIfError time.Duration
+
+	// StaleWhileRevalidate keeps expired responses for the given duration and
+	// serves them with the "stale" cache header, while the response is
+	// refreshed in the background by the handlers after the middleware.
+	//
+	// Default: 0
+	StaleWhileRevalidate time.Duration
+
+	// Metrics counts the lock waits of the stampede protection and the stale serves.
+	//
+	// Default: nil
+	Metrics *Metrics
+
+	// now returns the current unix timestamp in seconds, the tests control
+	// the expiration with it
+	now func() uint64
 }
 
 // ConfigDefault is the default config
@@ -83,11 +153,21 @@ var ConfigDefault = Config{
 	KeyGenerator: func(c *fiber.Ctx) string {
 		return utils.CopyString(c.Path())
 	},
+	QueryKey:             false,
+	IgnoreQueryParams:    fiber.TrackingParams,
+	VaryHeaders:          nil,
 	ExpirationGenerator:  nil,
 	StoreResponseHeaders: false,
 	Storage:              nil,
 	MaxBytes:             0,
+	MaxBodySize:          0,
+	HonorCacheControl:    false,
 	Methods:              []string{fiber.MethodGet, fiber.MethodHead},
+	StampedeProtection:   false,
+	LockTimeout:          5 * time.Second,
+	StaleIfError:         0,
+	StaleWhileRevalidate: 0,
+	Metrics:              nil,
 }
 
 // Helper function to set default values
@@ -118,11 +198,26 @@ func configDefault(config ...Config) Config {
 	if cfg.CacheHeader == "" {
 		cfg.CacheHeader = ConfigDefault.CacheHeader
 	}
+	if cfg.IgnoreQueryParams == nil {
+		cfg.IgnoreQueryParams = ConfigDefault.IgnoreQueryParams
+	}
+	if cfg.KeyGenerator == nil && cfg.QueryKey {
+		ignore := cfg.IgnoreQueryParams
+		cfg.KeyGenerator = func(c *fiber.Ctx) string {
+			if query := c.CanonicalQuery(ignore...); query != "" {
+				return c.Path() + "?" + query
+			}
+			return utils.CopyString(c.Path())
+		}
+	}
 	if cfg.KeyGenerator == nil {
 		cfg.KeyGenerator = ConfigDefault.KeyGenerator
 	}
 	if len(cfg.Methods) == 0 {
 		cfg.Methods = ConfigDefault.Methods
 	}
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = ConfigDefault.LockTimeout
+	}
 	return cfg
 }
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"path"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// tagsKey is the locals key of the cache tags declared by Tag
+const tagsKey = "cache_tags"
+
+// minPruneSize is the number of indexed keys from which expired keys are pruned
+const minPruneSize = 1024
+
+// indexes holds the key indexes of all cache middleware instances
+var (
+	indexes   []*keyIndex
+	indexesMu sync.Mutex
+)
+
+// indexEntry describes a cached response
+type indexEntry struct {
+	key     string
+	tags    []string
+	exp     uint64
+	heapidx int
+}
+
+// keyIndex tracks the cached keys of a middleware instance, since the
+// storage can't be searched. It's guarded by the mutex of the instance.
+type keyIndex struct {
+	mux     *sync.RWMutex
+	entries map[string]indexEntry
+	// remove deletes the entry from the storage and the heap
+	remove  func(storageKey string, heapidx int)
+	pruneAt int
+}
+
+// newKeyIndex creates and registers the key index of a middleware instance
+func newKeyIndex(mux *sync.RWMutex, remove func(storageKey string, heapidx int)) *keyIndex {
+	idx := &keyIndex{
+		mux:     mux,
+		entries: make(map[string]indexEntry),
+		remove:  remove,
+		pruneAt: minPruneSize,
+	}
+	indexesMu.Lock()
+	indexes = append(indexes, idx)
+	indexesMu.Unlock()
+	return idx
+}
+
+// add indexes the entry, expired entries are pruned as the index grows
+func (idx *keyIndex) add(storageKey string, entry indexEntry, ts uint64) {
+	idx.entries[storageKey] = entry
+	if len(idx.entries) < idx.pruneAt {
+		return
+	}
+	for k, e := range idx.entries {
+		if ts >= e.exp {
+			delete(idx.entries, k)
+		}
+	}
+	if idx.pruneAt = 2 * len(idx.entries); idx.pruneAt < minPruneSize {
+		idx.pruneAt = minPruneSize
+	}
+}
+
+// invalidate removes all entries which match and returns their number
+func (idx *keyIndex) invalidate(match func(e indexEntry) bool) int {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	n := 0
+	for k, e := range idx.entries {
+		if match(e) {
+			idx.remove(k, e.heapidx)
+			delete(idx.entries, k)
+			n++
+		}
+	}
+	return n
+}
+
+// invalidateAll invalidates the matching entries of all middleware instances
+func invalidateAll(match func(e indexEntry) bool) int {
+	indexesMu.Lock()
+	defer indexesMu.Unlock()
+	n := 0
+	for _, idx := range indexes {
+		n += idx.invalidate(match)
+	}
+	return n
+}
+
+// Tag declares cache tags for the response of the current request,
+// so that it can be purged with InvalidateTags later on:
+//
+//	app.Get("/users/:id", func(c *fiber.Ctx) error {
+//		cache.Tag(c, "users", "user:"+c.Params("id"))
+//		return c.JSON(user)
+//	})
+func Tag(c *fiber.Ctx, tags ...string) {
+	existing, _ := c.Locals(tagsKey).([]string)
+	for _, tag := range tags {
+		// The tags may be unsafe strings from the request
+		existing = append(existing, utils.CopyString(tag))
+	}
+	c.Locals(tagsKey, existing)
+}
+
+// Invalidate deletes the cached responses, whose key matches the pattern,
+// from all cache middleware instances of the process and returns their number.
+// The pattern syntax is the one of path.Match, e.g. "/users/*" matches the
+// default keys of the paths below /users. It's meant for write endpoints
+// purging related cached GET responses:
+//
+//	app.Put("/users/:id", func(c *fiber.Ctx) error {
+//		// update user
+//		_, err := cache.Invalidate("/users/" + c.Params("id"))
+//		return err
+//	})
+//
+// Only the responses cached by this process are known, entries in a shared
+// Storage which have been cached by other processes aren't deleted.
+func Invalidate(pattern string) (int, error) {
+	// Report malformed patterns, path.Match only does on a mismatch
+	if _, err := path.Match(pattern, ""); err != nil {
+		return 0, err
+	}
+	return invalidateAll(func(e indexEntry) bool {
+		matched, _ := path.Match(pattern, e.key)
+		return matched
+	}), nil
+}
+
+// Delete deletes the cached responses of the key, i.e. of all methods and
+// VaryHeaders, from all cache middleware instances of the process and returns
+// their number. Unlike Invalidate, the key isn't a pattern:
+//
+//	cache.Delete("/users/" + c.Params("id"))
+func Delete(key string) int {
+	return invalidateAll(func(e indexEntry) bool {
+		return e.key == key
+	})
+}
+
+// InvalidateTags deletes the cached responses, which have been tagged with
+// any of the tags by Tag, from all cache middleware instances of the process
+// and returns their number.
+func InvalidateTags(tags ...string) int {
+	return invalidateAll(func(e indexEntry) bool {
+		for _, tag := range e.tags {
+			for _, t := range tags {
+				if tag == t {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
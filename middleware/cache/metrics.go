@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"sync/atomic"
+)
+
+// Metrics counts the events of the stampede protection and the stale-if-error
+// serving. The same instance can be passed to several middleware instances.
+type Metrics struct {
+	lockWaits    uint64
+	lockTimeouts uint64
+	staleServes  uint64
+}
+
+// LockWaits returns the number of requests which waited for a concurrent request
+func (m *Metrics) LockWaits() uint64 {
+	return atomic.LoadUint64(&m.lockWaits)
+}
+
+// LockTimeouts returns the number of waiting requests which exceeded the LockTimeout
+func (m *Metrics) LockTimeouts() uint64 {
+	return atomic.LoadUint64(&m.lockTimeouts)
+}
+
+// StaleServes returns the number of expired responses served instead of an error
+func (m *Metrics) StaleServes() uint64 {
+	return atomic.LoadUint64(&m.staleServes)
+}
+
+func (m *Metrics) addLockWait() {
+	if m != nil {
+		atomic.AddUint64(&m.lockWaits, 1)
+	}
+}
+
+func (m *Metrics) addLockTimeout() {
+	if m != nil {
+		atomic.AddUint64(&m.lockTimeouts, 1)
+	}
+}
+
+func (m *Metrics) addStaleServe() {
+	if m != nil {
+		atomic.AddUint64(&m.staleServes, 1)
+	}
+}
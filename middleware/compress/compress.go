@@ -1,8 +1,11 @@
 package compress
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
-	"github.com/valyala/fasthttp"
+	"github.com/gofiber/fiber/v2/internal/bytebufferpool"
+	"github.com/gofiber/fiber/v2/utils"
 )
 
 // New creates a new middleware handler
@@ -10,39 +13,34 @@ func New(config ...Config) fiber.Handler {
 	// Set default config
 	cfg := configDefault(config...)
 
-	// Setup request handlers
-	var (
-		fctx       = func(c *fasthttp.RequestCtx) {}
-		compressor fasthttp.RequestHandler
-	)
-
-	// Setup compression algorithm
-	switch cfg.Level {
-	case LevelDefault:
-		// LevelDefault
-		compressor = fasthttp.CompressHandlerBrotliLevel(fctx,
-			fasthttp.CompressBrotliDefaultCompression,
-			fasthttp.CompressDefaultCompression,
-		)
-	case LevelBestSpeed:
-		// LevelBestSpeed
-		compressor = fasthttp.CompressHandlerBrotliLevel(fctx,
-			fasthttp.CompressBrotliBestSpeed,
-			fasthttp.CompressBestSpeed,
-		)
-	case LevelBestCompression:
-		// LevelBestCompression
-		compressor = fasthttp.CompressHandlerBrotliLevel(fctx,
-			fasthttp.CompressBrotliBestCompression,
-			fasthttp.CompressBestCompression,
-		)
-	default:
-		// LevelDisabled
+	if cfg.Level == LevelDisabled {
 		return func(c *fiber.Ctx) error {
 			return c.Next()
 		}
 	}
 
+	// Setup the encoders in the order of preference
+	var (
+		encoders     = make(map[string]*encoder, len(cfg.Encodings))
+		offers       []string
+		streamOffers []string
+		contentTypes = make([]string, len(cfg.ContentTypes))
+	)
+	for _, name := range cfg.Encodings {
+		enc := newEncoder(name, cfg.Level)
+		if enc == nil {
+			panic("compress: unsupported encoding " + name)
+		}
+		encoders[enc.name] = enc
+		offers = append(offers, enc.name)
+		if enc.stream != nil {
+			streamOffers = append(streamOffers, enc.name)
+		}
+	}
+	for i, contentType := range cfg.ContentTypes {
+		contentTypes[i] = strings.ToLower(contentType)
+	}
+
 	// Return new handler
 	return func(c *fiber.Ctx) error {
 		// Don't execute middleware if Next returns true
@@ -55,10 +53,71 @@ func New(config ...Config) fiber.Handler {
 			return err
 		}
 
+		resp := c.Response()
+		if !compressible(c, contentTypes) {
+			return nil
+		}
+		stream := resp.IsBodyStream()
+		if !stream && len(resp.Body()) < cfg.MinLength {
+			return nil
+		}
+
+		// The response depends on the Accept-Encoding header, even if it isn't compressed
+		c.Vary(fiber.HeaderAcceptEncoding)
+
+		available := offers
+		if stream {
+			available = streamOffers
+		}
+		name := utils.AcceptedEncoding(c.Get(fiber.HeaderAcceptEncoding), available...)
+		if name == "" {
+			return nil
+		}
+		enc := encoders[name]
+
 		// Compress response
-		compressor(c.Context())
+		if stream {
+			enc.compressStream(c)
+		} else {
+			buf := bytebufferpool.Get()
+			buf.B = enc.compress(buf.B, resp.Body())
+			resp.SetBody(buf.B)
+			bytebufferpool.Put(buf)
+			resp.Header.SetContentEncoding(enc.name)
+		}
+
+		// The compressed body isn't byte-for-byte identical anymore, see RFC 7232, 2.1
+		if etag := c.GetRespHeader(fiber.HeaderETag); strings.HasPrefix(etag, "\"") {
+			c.Set(fiber.HeaderETag, "W/"+etag)
+		}
 
 		// Return from handler
 		return nil
 	}
 }
+
+// compressible reports if the response may be compressed
+func compressible(c *fiber.Ctx, contentTypes []string) bool {
+	resp := c.Response()
+
+	// The body is compressed already, e.g. a precompressed file
+	if len(resp.Header.Peek(fiber.HeaderContentEncoding)) > 0 {
+		return false
+	}
+	switch status := resp.StatusCode(); {
+	case status < fiber.StatusOK, status == fiber.StatusNoContent,
+		status == fiber.StatusPartialContent, status == fiber.StatusNotModified:
+		return false
+	}
+	if strings.Contains(c.GetRespHeader(fiber.HeaderCacheControl), "no-transform") {
+		return false
+	}
+
+	mediaType := strings.ToLower(utils.Trim(strings.SplitN(string(resp.Header.ContentType()), ";", 2)[0], ' '))
+	for _, contentType := range contentTypes {
+		if matchContentType(contentType, mediaType) {
+			return true
+		}
+	}
+	return false
+}
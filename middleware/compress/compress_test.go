@@ -1,6 +1,8 @@
 package compress
 
 import (
+	"bytes"
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/utils"
+	"github.com/klauspost/compress/zstd"
 )
 
 var filedata []byte
@@ -180,3 +183,106 @@ func Test_Compress_Next(t *testing.T) {
 	utils.AssertEqual(t, nil, err)
 	utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
 }
+
+// go test -run Test_Compress_Zstd
+func Test_Compress_Zstd(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(New())
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.Send(filedata)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0.8, zstd")
+
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, 200, resp.StatusCode, "Status code")
+	utils.AssertEqual(t, "zstd", resp.Header.Get(fiber.HeaderContentEncoding))
+	utils.AssertEqual(t, fiber.HeaderAcceptEncoding, resp.Header.Get(fiber.HeaderVary))
+
+	// Validate that the body can be decompressed
+	dec, err := zstd.NewReader(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	defer dec.Close()
+	body, err := ioutil.ReadAll(dec)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, filedata, body)
+}
+
+// go test -run Test_Compress_Filters
+func Test_Compress_Filters(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(New(Config{MinLength: 1000}))
+
+	app.Get("/image", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "image/png")
+		return c.Send(filedata)
+	})
+	app.Get("/small", func(c *fiber.Ctx) error {
+		return c.Send(filedata[:999])
+	})
+	app.Get("/encoded", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentEncoding, "br")
+		return c.Send(filedata)
+	})
+	app.Get("/no-transform", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "no-transform")
+		return c.Send(filedata)
+	})
+	app.Get("/problem", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "application/problem+json; charset=utf-8")
+		c.Set(fiber.HeaderETag, `"abc"`)
+		return c.Send(filedata)
+	})
+
+	for path, encoding := range map[string]string{
+		"/image":        "",
+		"/small":        "",
+		"/encoded":      "br",
+		"/no-transform": "",
+		"/problem":      "gzip",
+	} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, encoding, resp.Header.Get(fiber.HeaderContentEncoding), path)
+	}
+
+	// The ETag of the uncompressed body becomes weak
+	req := httptest.NewRequest("GET", "/problem", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, `W/"abc"`, resp.Header.Get(fiber.HeaderETag))
+}
+
+// go test -run Test_Compress_Stream
+func Test_Compress_Stream(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(New())
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStream(bytes.NewReader(filedata), len(filedata))
+	})
+
+	// zstd doesn't support streams
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "zstd, gzip;q=0.5")
+
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, "gzip", resp.Header.Get(fiber.HeaderContentEncoding))
+
+	gz, err := gzip.NewReader(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	body, err := ioutil.ReadAll(gz)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, filedata, body)
+}
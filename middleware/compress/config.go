@@ -19,6 +19,25 @@ type Config struct {
 	// LevelBestSpeed:        1
 	// LevelBestCompression:  2
 	Level Level
+
+	// Encodings are the supported encodings, the earlier ones are preferred
+	// if the client accepts several of them with the same quality.
+	//
+	// Optional. Default: []string{"br", "zstd", "gzip", "deflate"}
+	Encodings []string
+
+	// MinLength is the minimum length of a body to compress, since the
+	// compressed body of a small one may even be larger. Streamed bodies
+	// are always compressed.
+	//
+	// Optional. Default: 200
+	MinLength int
+
+	// ContentTypes are the media types of the compressed responses, a "*"
+	// matches any characters, e.g. "text/*" or "application/*+json".
+	//
+	// Optional. Default: text, JSON, JavaScript, XML, WebAssembly, SVG, icons and fonts
+	ContentTypes []string
 }
 
 // Level is numeric representation of compression level
@@ -32,10 +51,35 @@ const (
 	LevelBestCompression Level = 2
 )
 
+// Supported encodings
+const (
+	EncodingBrotli  = "br"
+	EncodingZstd    = "zstd"
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+)
+
 // ConfigDefault is the default config
 var ConfigDefault = Config{
-	Next:  nil,
-	Level: LevelDefault,
+	Next:      nil,
+	Level:     LevelDefault,
+	Encodings: []string{EncodingBrotli, EncodingZstd, EncodingGzip, EncodingDeflate},
+	MinLength: 200,
+	ContentTypes: []string{
+		"text/*",
+		"application/json",
+		"application/*+json",
+		"application/javascript",
+		"application/x-javascript",
+		"application/xml",
+		"application/*+xml",
+		"application/wasm",
+		"image/svg+xml",
+		"image/x-icon",
+		"image/vnd.microsoft.icon",
+		"font/ttf",
+		"font/otf",
+	},
 }
 
 // Helper function to set default values
@@ -52,5 +96,14 @@ func configDefault(config ...Config) Config {
 	if cfg.Level < LevelDisabled || cfg.Level > LevelBestCompression {
 		cfg.Level = ConfigDefault.Level
 	}
+	if len(cfg.Encodings) == 0 {
+		cfg.Encodings = ConfigDefault.Encodings
+	}
+	if cfg.MinLength <= 0 {
+		cfg.MinLength = ConfigDefault.MinLength
+	}
+	if len(cfg.ContentTypes) == 0 {
+		cfg.ContentTypes = ConfigDefault.ContentTypes
+	}
 	return cfg
 }
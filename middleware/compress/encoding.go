@@ -0,0 +1,94 @@
+package compress
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/valyala/fasthttp"
+)
+
+// encoder compresses the bodies of an encoding
+type encoder struct {
+	name string
+	// compress appends the compressed src to dst
+	compress func(dst, src []byte) []byte
+	// stream compresses the body stream of the response, nil if the encoding
+	// doesn't support streams
+	stream fasthttp.RequestHandler
+}
+
+// newEncoder returns the encoder of the encoding, or nil if it isn't supported
+func newEncoder(name string, level Level) *encoder {
+	brotliLevel, otherLevel, zstdLevel := fasthttp.CompressBrotliDefaultCompression, fasthttp.CompressDefaultCompression, zstd.SpeedDefault
+	switch level {
+	case LevelBestSpeed:
+		brotliLevel, otherLevel, zstdLevel = fasthttp.CompressBrotliBestSpeed, fasthttp.CompressBestSpeed, zstd.SpeedFastest
+	case LevelBestCompression:
+		brotliLevel, otherLevel, zstdLevel = fasthttp.CompressBrotliBestCompression, fasthttp.CompressBestCompression, zstd.SpeedBestCompression
+	}
+	// The compressor of fasthttp chooses the encoding of streams by the Accept-Encoding header, see compressStream
+	stream := fasthttp.CompressHandlerBrotliLevel(func(*fasthttp.RequestCtx) {}, brotliLevel, otherLevel)
+
+	switch strings.ToLower(name) {
+	case EncodingBrotli:
+		return &encoder{
+			name: EncodingBrotli,
+			compress: func(dst, src []byte) []byte {
+				return fasthttp.AppendBrotliBytesLevel(dst, src, brotliLevel)
+			},
+			stream: stream,
+		}
+	case EncodingGzip:
+		return &encoder{
+			name: EncodingGzip,
+			compress: func(dst, src []byte) []byte {
+				return fasthttp.AppendGzipBytesLevel(dst, src, otherLevel)
+			},
+			stream: stream,
+		}
+	case EncodingDeflate:
+		return &encoder{
+			name: EncodingDeflate,
+			compress: func(dst, src []byte) []byte {
+				return fasthttp.AppendDeflateBytesLevel(dst, src, otherLevel)
+			},
+			stream: stream,
+		}
+	case EncodingZstd:
+		// EncodeAll can be called concurrently, the error only occurs for invalid options
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstdLevel))
+		if err != nil {
+			panic(err)
+		}
+		return &encoder{
+			name: EncodingZstd,
+			compress: func(dst, src []byte) []byte {
+				return enc.EncodeAll(src, dst)
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// compressStream compresses the body stream of the response
+func (e *encoder) compressStream(c *fiber.Ctx) {
+	header := &c.Request().Header
+	accept := append([]byte(nil), header.Peek(fiber.HeaderAcceptEncoding)...)
+	header.Set(fiber.HeaderAcceptEncoding, e.name)
+	e.stream(c.Context())
+	header.SetBytesV(fiber.HeaderAcceptEncoding, accept)
+}
+
+// matchContentType reports if the media type matches the pattern, which may
+// contain a "*"
+func matchContentType(pattern, mediaType string) bool {
+	star := strings.IndexByte(pattern, '*')
+	if star == -1 {
+		return pattern == mediaType
+	}
+	prefix, suffix := pattern[:star], pattern[star+1:]
+	return len(mediaType) >= len(prefix)+len(suffix) &&
+		strings.HasPrefix(mediaType, prefix) && strings.HasSuffix(mediaType, suffix)
+}
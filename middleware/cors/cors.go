@@ -2,8 +2,10 @@ package cors
 
 import (
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -16,10 +18,17 @@ type Config struct {
 	Next func(c *fiber.Ctx) bool
 
 	// AllowOrigin defines a list of origins that may access the resource.
+	// Subdomains are matched with a wildcard, e.g. "https://*.example.com".
 	//
-	// Optional. Default value "*"
+	// Optional. Default value "*", or "" if AllowOriginsFunc is set
 	AllowOrigins string
 
+	// AllowOriginsFunc allows the origins, which aren't in AllowOrigins, if
+	// it returns true, e.g. to look them up in a database.
+	//
+	// Optional. Default: nil
+	AllowOriginsFunc func(origin string) bool
+
 	// AllowMethods defines a list methods allowed when accessing the resource.
 	// This is used in response to a preflight request.
 	//
@@ -40,6 +49,13 @@ type Config struct {
 	// Optional. Default value false.
 	AllowCredentials bool
 
+	// AllowPrivateNetwork allows requests of public websites to the private
+	// network, e.g. to a device in the local network, by responding to the
+	// Access-Control-Request-Private-Network header of preflight requests.
+	//
+	// Optional. Default value false.
+	AllowPrivateNetwork bool
+
 	// ExposeHeaders defines a whitelist headers that clients are allowed to
 	// access.
 	//
@@ -47,7 +63,8 @@ type Config struct {
 	ExposeHeaders string
 
 	// MaxAge indicates how long (in seconds) the results of a preflight request
-	// can be cached.
+	// can be cached. A negative value disables the caching, browsers cache
+	// them for a few seconds otherwise.
 	//
 	// Optional. Default value 0.
 	MaxAge int
@@ -71,7 +88,16 @@ var ConfigDefault = Config{
 	MaxAge:           0,
 }
 
-// New creates a new middleware handler
+// New creates a new middleware handler. It can be used globally and per
+// group, the CORS middleware of the group overrides the config for its
+// routes:
+//
+//	app.Use(cors.New())
+//	api := app.Group("/api", cors.New(cors.Config{AllowOrigins: "https://example.com"}))
+//
+// Preflight requests are answered by the middleware of the most specific
+// group, without running the handlers in between. Groups with params in
+// their prefix can't override the config of preflight requests.
 func New(config ...Config) fiber.Handler {
 	// Set default config
 	cfg := ConfigDefault
@@ -84,13 +110,16 @@ func New(config ...Config) fiber.Handler {
 		if cfg.AllowMethods == "" {
 			cfg.AllowMethods = ConfigDefault.AllowMethods
 		}
-		if cfg.AllowOrigins == "" {
+		if cfg.AllowOrigins == "" && cfg.AllowOriginsFunc == nil {
 			cfg.AllowOrigins = ConfigDefault.AllowOrigins
 		}
 	}
 
 	// Convert string to slice
-	allowOrigins := strings.Split(strings.ReplaceAll(cfg.AllowOrigins, " ", ""), ",")
+	var allowOrigins []string
+	if cfg.AllowOrigins != "" {
+		allowOrigins = strings.Split(strings.ReplaceAll(cfg.AllowOrigins, " ", ""), ",")
+	}
 
 	// Strip white spaces
 	allowMethods := strings.ReplaceAll(cfg.AllowMethods, " ", "")
@@ -99,9 +128,17 @@ func New(config ...Config) fiber.Handler {
 
 	// Convert int to string
 	maxAge := strconv.Itoa(cfg.MaxAge)
+	if cfg.MaxAge < 0 {
+		maxAge = "0"
+	}
+
+	var (
+		once      sync.Once
+		overrides []override
+	)
 
 	// Return new handler
-	return func(c *fiber.Ctx) error {
+	handler := func(c *fiber.Ctx) error {
 		// Don't execute middleware if Next returns true
 		if cfg.Next != nil && cfg.Next(c) {
 			return c.Next()
@@ -126,22 +163,30 @@ func New(config ...Config) fiber.Handler {
 				break
 			}
 		}
+		if allowOrigin == "" && origin != "" && cfg.AllowOriginsFunc != nil && cfg.AllowOriginsFunc(origin) {
+			allowOrigin = origin
+		}
 
-		// Simple request
+		// Simple request, the headers of an outer CORS middleware are replaced
 		if c.Method() != http.MethodOptions {
 			c.Vary(fiber.HeaderOrigin)
 			c.Set(fiber.HeaderAccessControlAllowOrigin, allowOrigin)
+			setOrDel(c, fiber.HeaderAccessControlAllowCredentials, cfg.AllowCredentials, "true")
+			setOrDel(c, fiber.HeaderAccessControlExposeHeaders, exposeHeaders != "", exposeHeaders)
+			return c.Next()
+		}
 
-			if cfg.AllowCredentials {
-				c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
-			}
-			if exposeHeaders != "" {
-				c.Set(fiber.HeaderAccessControlExposeHeaders, exposeHeaders)
+		// Preflight request, which the CORS middleware of a group may answer
+		if c.Locals(delegatedKey) == nil {
+			once.Do(func() {
+				overrides = findOverrides(c.App(), c.Route().Path)
+			})
+			if handler := matchOverride(c, overrides); handler != nil {
+				c.Locals(delegatedKey, true)
+				return handler(c)
 			}
-			return c.Next()
 		}
 
-		// Preflight request
 		c.Vary(fiber.HeaderOrigin)
 		c.Vary(fiber.HeaderAccessControlRequestMethod)
 		c.Vary(fiber.HeaderAccessControlRequestHeaders)
@@ -163,12 +208,30 @@ func New(config ...Config) fiber.Handler {
 			}
 		}
 
+		// Set Allow-Private-Network if requested and allowed
+		if cfg.AllowPrivateNetwork && c.Get(fiber.HeaderAccessControlRequestPrivateNetwork) == "true" {
+			c.Set(fiber.HeaderAccessControlAllowPrivateNetwork, "true")
+		}
+
 		// Set MaxAge is set
-		if cfg.MaxAge > 0 {
+		if cfg.MaxAge != 0 {
 			c.Set(fiber.HeaderAccessControlMaxAge, maxAge)
 		}
 
 		// Send 204 No Content
 		return c.SendStatus(fiber.StatusNoContent)
 	}
+	handlerPCOnce.Do(func() {
+		handlerPC = reflect.ValueOf(handler).Pointer()
+	})
+	return handler
+}
+
+// setOrDel sets the header if ok, or deletes the header of an outer middleware
+func setOrDel(c *fiber.Ctx, key string, ok bool, value string) {
+	if ok {
+		c.Set(key, value)
+	} else {
+		c.Response().Header.Del(key)
+	}
 }
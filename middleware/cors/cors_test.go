@@ -235,3 +235,119 @@ func Test_CORS_Next(t *testing.T) {
 	utils.AssertEqual(t, nil, err)
 	utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
 }
+
+// go test -run Test_CORS_AllowOriginsFunc
+func Test_CORS_AllowOriginsFunc(t *testing.T) {
+	app := fiber.New()
+	app.Use(New(Config{
+		AllowOrigins: "https://example.com",
+		AllowOriginsFunc: func(origin string) bool {
+			return origin == "https://partner.com"
+		},
+	}))
+	h := app.Handler()
+
+	for origin, expected := range map[string]string{
+		"https://example.com": "https://example.com",
+		"https://partner.com": "https://partner.com",
+		"https://evil.com":    "",
+	} {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fiber.MethodGet)
+		ctx.Request.Header.Set(fiber.HeaderOrigin, origin)
+		h(ctx)
+		utils.AssertEqual(t, expected, string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin)))
+	}
+
+	// AllowOrigins isn't "*" by default with AllowOriginsFunc
+	app = fiber.New()
+	app.Use(New(Config{
+		AllowOriginsFunc: func(origin string) bool {
+			return false
+		},
+	}))
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodGet)
+	ctx.Request.Header.Set(fiber.HeaderOrigin, "https://example.com")
+	app.Handler()(ctx)
+	utils.AssertEqual(t, "", string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin)))
+}
+
+// go test -run Test_CORS_Preflight
+func Test_CORS_Preflight(t *testing.T) {
+	app := fiber.New()
+	app.Use(New(Config{
+		AllowPrivateNetwork: true,
+		MaxAge:              -1,
+	}))
+	h := app.Handler()
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodOptions)
+	ctx.Request.Header.Set(fiber.HeaderOrigin, "https://example.com")
+	ctx.Request.Header.Set(fiber.HeaderAccessControlRequestPrivateNetwork, "true")
+	h(ctx)
+	utils.AssertEqual(t, fiber.StatusNoContent, ctx.Response.StatusCode())
+	utils.AssertEqual(t, "true", string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowPrivateNetwork)))
+	utils.AssertEqual(t, "0", string(ctx.Response.Header.Peek(fiber.HeaderAccessControlMaxAge)))
+
+	ctx = &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodOptions)
+	h(ctx)
+	utils.AssertEqual(t, "", string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowPrivateNetwork)))
+}
+
+// go test -run Test_CORS_Group_Override
+func Test_CORS_Group_Override(t *testing.T) {
+	app := fiber.New()
+	app.Use(New(Config{
+		AllowCredentials: true,
+		ExposeHeaders:    "X-Total",
+	}))
+	// Preflight requests don't reach other middlewares
+	app.Use(func(c *fiber.Ctx) error {
+		if c.Method() == fiber.MethodOptions {
+			return fiber.ErrUnauthorized
+		}
+		return c.Next()
+	})
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	api := app.Group("/api", New(Config{
+		AllowOrigins: "https://example.com",
+		AllowMethods: "GET",
+	}))
+	api.Get("/users", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	h := app.Handler()
+
+	for _, tc := range []struct {
+		method      string
+		path        string
+		allowOrigin string
+		credentials string
+		methods     string
+	}{
+		{fiber.MethodGet, "/", "https://other.com", "true", ""},
+		{fiber.MethodGet, "/api/users", "", "", ""},
+		{fiber.MethodOptions, "/", "https://other.com", "true", "GET,POST,HEAD,PUT,DELETE,PATCH"},
+		{fiber.MethodOptions, "/API/users", "", "", "GET"},
+		{fiber.MethodOptions, "/apis", "https://other.com", "true", "GET,POST,HEAD,PUT,DELETE,PATCH"},
+	} {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(tc.method)
+		ctx.Request.SetRequestURI(tc.path)
+		ctx.Request.Header.Set(fiber.HeaderOrigin, "https://other.com")
+		h(ctx)
+		if tc.method == fiber.MethodOptions {
+			utils.AssertEqual(t, fiber.StatusNoContent, ctx.Response.StatusCode(), tc.path)
+		} else {
+			utils.AssertEqual(t, fiber.StatusOK, ctx.Response.StatusCode(), tc.path)
+		}
+		utils.AssertEqual(t, tc.allowOrigin, string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin)), tc.path)
+		utils.AssertEqual(t, tc.credentials, string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowCredentials)), tc.path)
+		utils.AssertEqual(t, tc.methods, string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowMethods)), tc.path)
+	}
+}
@@ -1,6 +1,14 @@
 package cors
 
-import "strings"
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
 
 func matchScheme(domain, pattern string) bool {
 	didx := strings.Index(domain, ":")
@@ -50,3 +58,64 @@ func matchSubdomain(domain, pattern string) bool {
 	}
 	return false
 }
+
+// delegatedKey marks preflight requests passed to the CORS middleware of a group
+const delegatedKey = "__cors_delegated__"
+
+// handlerPC is the code pointer of the handlers returned by New, which is the
+// same for all of them
+var (
+	handlerPC     uintptr
+	handlerPCOnce sync.Once
+)
+
+// override is the CORS middleware of a group
+type override struct {
+	prefix  string
+	handler fiber.Handler
+}
+
+// findOverrides returns the CORS middlewares of the groups with static
+// prefixes, which are more specific than the prefix, the longest first
+func findOverrides(app *fiber.App, prefix string) []override {
+	var overrides []override
+	for _, routes := range app.Stack() {
+		for _, route := range routes {
+			if route.Method != fiber.MethodOptions || len(route.Path) <= len(prefix) ||
+				strings.ContainsAny(route.Path, ":*+") {
+				continue
+			}
+			for _, handler := range route.Handlers {
+				if reflect.ValueOf(handler).Pointer() == handlerPC {
+					overrides = append(overrides, override{prefix: route.Path, handler: handler})
+					break
+				}
+			}
+		}
+	}
+	sort.SliceStable(overrides, func(i, j int) bool {
+		return len(overrides[i].prefix) > len(overrides[j].prefix)
+	})
+	return overrides
+}
+
+// matchOverride returns the handler of the most specific group of the path
+func matchOverride(c *fiber.Ctx, overrides []override) fiber.Handler {
+	if len(overrides) == 0 {
+		return nil
+	}
+	path := c.Path()
+	if !c.App().Config().CaseSensitive {
+		path = utils.ToLower(path)
+	}
+	for _, o := range overrides {
+		prefix := o.prefix
+		if !c.App().Config().CaseSensitive {
+			prefix = utils.ToLower(prefix)
+		}
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return o.handler
+		}
+	}
+	return nil
+}
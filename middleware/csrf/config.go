@@ -51,7 +51,8 @@ type Config struct {
 	// Optional. Default value false.
 	CookieHTTPOnly bool
 
-	// Value of SameSite cookie.
+	// Value of SameSite cookie. With "None" the cookie is always secure,
+	// since browsers reject it otherwise.
 	// Optional. Default value "Lax".
 	CookieSameSite string
 
@@ -69,10 +70,17 @@ type Config struct {
 	// Optional. Default: memory.New()
 	Storage fiber.Storage
 
-	// Context key to store generated CSRF token into context.
-	// If left empty, token will not be stored in context.
+	// DoubleSubmit checks that the token of the request equals the token of
+	// the cookie instead of looking it up in Storage, so no server-side state
+	// is needed. KeyLookup mustn't be the CSRF cookie then.
 	//
-	// Optional. Default: ""
+	// Optional. Default: false
+	DoubleSubmit bool
+
+	// Context key to store generated CSRF token into context, e.g. for the
+	// hidden form field of templates.
+	//
+	// Optional. Default: "csrf"
 	ContextKey string
 
 	// KeyGenerator creates a new CSRF token
@@ -104,12 +112,16 @@ type Config struct {
 
 const HeaderName = "X-Csrf-Token"
 
+// MessageForbidden is the key of the error message in fiber.Config.Messages
+const MessageForbidden = "csrf.forbidden"
+
 // ConfigDefault is the default config
 var ConfigDefault = Config{
 	KeyLookup:      "header:" + HeaderName,
 	CookieName:     "csrf_",
 	CookieSameSite: "Lax",
 	Expiration:     1 * time.Hour,
+	ContextKey:     "csrf",
 	KeyGenerator:   utils.UUID,
 	ErrorHandler:   defaultErrorHandler,
 	Extractor:      CsrfFromHeader(HeaderName),
@@ -117,6 +129,9 @@ var ConfigDefault = Config{
 
 // default ErrorHandler that process return error from fiber.Handler
 var defaultErrorHandler = func(c *fiber.Ctx, err error) error {
+	if msg := c.Message(MessageForbidden); msg != "" {
+		return fiber.NewError(fiber.StatusForbidden, msg)
+	}
 	return fiber.ErrForbidden
 }
 
@@ -168,6 +183,12 @@ func configDefault(config ...Config) Config {
 	if cfg.CookieSameSite == "" {
 		cfg.CookieSameSite = ConfigDefault.CookieSameSite
 	}
+	if utils.EqualFold(cfg.CookieSameSite, fiber.CookieSameSiteNoneMode) {
+		cfg.CookieSecure = true
+	}
+	if cfg.ContextKey == "" {
+		cfg.ContextKey = ConfigDefault.ContextKey
+	}
 	if cfg.KeyGenerator == nil {
 		cfg.KeyGenerator = ConfigDefault.KeyGenerator
 	}
@@ -181,6 +202,9 @@ func configDefault(config ...Config) Config {
 	if len(selectors) != 2 {
 		panic("[CSRF] KeyLookup must in the form of <source>:<key>")
 	}
+	if cfg.DoubleSubmit && cfg.Extractor == nil && selectors[0] == "cookie" && selectors[1] == cfg.CookieName {
+		panic("[CSRF] KeyLookup mustn't be the CSRF cookie with DoubleSubmit")
+	}
 
 	if cfg.Extractor == nil {
 		// By default we extract from a header
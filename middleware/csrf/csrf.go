@@ -1,6 +1,7 @@
 package csrf
 
 import (
+	"crypto/subtle"
 	"errors"
 	"time"
 
@@ -16,8 +17,12 @@ func New(config ...Config) fiber.Handler {
 	// Set default config
 	cfg := configDefault(config...)
 
-	// Create manager to simplify storage operations ( see manager.go )
-	manager := newManager(cfg.Storage)
+	// Create manager to simplify storage operations ( see manager.go ), the
+	// tokens of double submit cookies aren't stored
+	var manager *manager
+	if !cfg.DoubleSubmit {
+		manager = newManager(cfg.Storage)
+	}
 
 	dummyValue := []byte{'+'}
 
@@ -30,13 +35,13 @@ func New(config ...Config) fiber.Handler {
 
 		var token string
 
-		// Action depends on the HTTP method
-		switch c.Method() {
-		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+		// Action depends on the request class, which is derived from the HTTP method
+		// unless the route declares it
+		if c.RequestClass().IsSafe() {
 			// Declare empty token and try to get existing CSRF from cookie
 			token = c.Cookies(cfg.CookieName)
-		default:
-			// Assume that anything not defined as 'safe' by RFC7231 needs protection
+		} else {
+			// Assume that anything not 'safe' needs protection
 
 			// Extract token from client request i.e. header, query, param, form or cookie
 			token, err = cfg.Extractor(c)
@@ -44,8 +49,15 @@ func New(config ...Config) fiber.Handler {
 				return cfg.ErrorHandler(c, err)
 			}
 
-			// if token does not exist in Storage
-			if manager.getRaw(token) == nil {
+			// if token does not match the cookie or does not exist in Storage
+			var valid bool
+			if cfg.DoubleSubmit {
+				cookie := c.Cookies(cfg.CookieName)
+				valid = token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(cookie)) == 1
+			} else {
+				valid = manager.getRaw(token) != nil
+			}
+			if !valid {
 				// Expire cookie
 				c.Cookie(&fiber.Cookie{
 					Name:        cfg.CookieName,
@@ -68,7 +80,9 @@ func New(config ...Config) fiber.Handler {
 		}
 
 		// Add/update token to Storage
-		manager.setRaw(token, dummyValue, cfg.Expiration)
+		if manager != nil {
+			manager.setRaw(token, dummyValue, cfg.Expiration)
+		}
 
 		// Create cookie to pass token to client
 		cookie := &fiber.Cookie{
@@ -89,10 +103,8 @@ func New(config ...Config) fiber.Handler {
 		// a new header value is generated
 		c.Vary(fiber.HeaderCookie)
 
-		// Store token in context if set
-		if cfg.ContextKey != "" {
-			c.Locals(cfg.ContextKey, token)
-		}
+		// Store token in context
+		c.Locals(cfg.ContextKey, token)
 
 		// Continue stack
 		return c.Next()
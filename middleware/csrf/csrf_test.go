@@ -341,6 +341,27 @@ func Test_CSRF_ErrorHandler_EmptyToken(t *testing.T) {
 	utils.AssertEqual(t, "empty CSRF token", string(ctx.Response.Body()))
 }
 
+func Test_CSRF_Messages(t *testing.T) {
+	app := fiber.New(fiber.Config{
+		Messages: fiber.Messages{
+			"*": {MessageForbidden: "Ungültiges Formular"},
+		},
+	})
+
+	app.Use(New())
+
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod("POST")
+	h(ctx)
+	utils.AssertEqual(t, fiber.StatusForbidden, ctx.Response.StatusCode())
+	utils.AssertEqual(t, "Ungültiges Formular", string(ctx.Response.Body()))
+}
+
 // TODO: use this test case and make the unsafe header value bug from https://github.com/gofiber/fiber/issues/2045 reproducible and permanently fixed/tested by this testcase
 //func Test_CSRF_UnsafeHeaderValue(t *testing.T) {
 //	app := fiber.New()
@@ -448,3 +469,100 @@ func Benchmark_Middleware_CSRF_GenerateToken(b *testing.B) {
 
 	utils.AssertEqual(b, fiber.StatusTeapot, fctx.Response.Header.StatusCode())
 }
+
+// go test -run Test_CSRF_RequestClass
+func Test_CSRF_RequestClass(t *testing.T) {
+	app := fiber.New()
+	app.Use(New())
+	app.Post("/search", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Class(fiber.ClassSafe)
+	app.Get("/logout", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Class(fiber.ClassMutating)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/search", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/logout", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+// go test -run Test_CSRF_DoubleSubmit
+func Test_CSRF_DoubleSubmit(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(New(Config{DoubleSubmit: true}))
+
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("csrf").(string))
+	})
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+
+	// Generate CSRF token
+	ctx.Request.Header.SetMethod(fiber.MethodGet)
+	h(ctx)
+	token := string(ctx.Response.Header.Peek(fiber.HeaderSetCookie))
+	token = strings.Split(strings.Split(token, ";")[0], "=")[1]
+
+	// Token without the cookie
+	ctx.Request.Reset()
+	ctx.Response.Reset()
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	ctx.Request.Header.Set(HeaderName, token)
+	h(ctx)
+	utils.AssertEqual(t, 403, ctx.Response.StatusCode())
+
+	// Token of another cookie
+	ctx.Request.Reset()
+	ctx.Response.Reset()
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	ctx.Request.Header.Set(HeaderName, token)
+	ctx.Request.Header.SetCookie(ConfigDefault.CookieName, "johndoe")
+	h(ctx)
+	utils.AssertEqual(t, 403, ctx.Response.StatusCode())
+
+	// Cookie without token
+	ctx.Request.Reset()
+	ctx.Response.Reset()
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	ctx.Request.Header.SetCookie(ConfigDefault.CookieName, token)
+	h(ctx)
+	utils.AssertEqual(t, 403, ctx.Response.StatusCode())
+
+	// Token of the cookie, the token is exposed in the locals
+	ctx.Request.Reset()
+	ctx.Response.Reset()
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	ctx.Request.Header.Set(HeaderName, token)
+	ctx.Request.Header.SetCookie(ConfigDefault.CookieName, token)
+	h(ctx)
+	utils.AssertEqual(t, 200, ctx.Response.StatusCode())
+	utils.AssertEqual(t, token, string(ctx.Response.Body()))
+
+	// The CSRF cookie can't be the token
+	defer func() {
+		utils.AssertEqual(t, "[CSRF] KeyLookup mustn't be the CSRF cookie with DoubleSubmit", recover())
+	}()
+	New(Config{DoubleSubmit: true, KeyLookup: "cookie:csrf_"})
+}
+
+// go test -run Test_CSRF_SameSiteNone
+func Test_CSRF_SameSiteNone(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(New(Config{CookieSameSite: "None"}))
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodGet)
+	h(ctx)
+	cookie := string(ctx.Response.Header.Peek(fiber.HeaderSetCookie))
+	utils.AssertEqual(t, true, strings.Contains(cookie, "; secure; SameSite=None"), cookie)
+}
@@ -0,0 +1,58 @@
+package decompress
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Supported encodings
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+	EncodingBrotli  = "br"
+	EncodingZstd    = "zstd"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// MaxSize is the maximum size of a decompressed body in bytes, larger
+	// bodies are rejected with 413 Request Entity Too Large. It guards
+	// against small bodies, which expand to gigabytes ("zip bombs").
+	//
+	// Optional. Default: the BodyLimit of the app
+	MaxSize int
+
+	// Encodings are the accepted content encodings, bodies of other
+	// encodings are rejected with 415 Unsupported Media Type.
+	//
+	// Optional. Default: []string{"gzip", "deflate", "br", "zstd"}
+	Encodings []string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:      nil,
+	MaxSize:   0,
+	Encodings: []string{EncodingGzip, EncodingDeflate, EncodingBrotli, EncodingZstd},
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if len(cfg.Encodings) == 0 {
+		cfg.Encodings = ConfigDefault.Encodings
+	}
+	return cfg
+}
@@ -0,0 +1,128 @@
+package decompress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/internal/bytebufferpool"
+	"github.com/klauspost/compress/flate"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	accepted := make(map[string]bool, len(cfg.Encodings))
+	for _, encoding := range cfg.Encodings {
+		accepted[strings.ToLower(encoding)] = true
+	}
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		header := c.Get(fiber.HeaderContentEncoding)
+		if header == "" {
+			return c.Next()
+		}
+
+		// The encodings are listed in the order they were applied
+		var encodings []string
+		for _, encoding := range strings.Split(header, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if encoding == "" || encoding == "identity" {
+				continue
+			}
+			if !accepted[encoding] {
+				c.Set(fiber.HeaderAcceptEncoding, strings.Join(cfg.Encodings, ", "))
+				return fiber.ErrUnsupportedMediaType
+			}
+			encodings = append(encodings, encoding)
+		}
+
+		maxSize := cfg.MaxSize
+		if maxSize <= 0 {
+			maxSize = c.App().Config().BodyLimit
+		}
+
+		body := c.Request().Body()
+		buf := bytebufferpool.Get()
+		defer bytebufferpool.Put(buf)
+		for i := len(encodings) - 1; i >= 0; i-- {
+			buf.Reset()
+			if err := decode(buf, encodings[i], body, maxSize); err != nil {
+				return err
+			}
+			body = buf.B
+			// Decode the next encoding from a copy, since buf is reused
+			if i > 0 {
+				body = append([]byte(nil), body...)
+			}
+		}
+
+		// The handlers see an uncompressed request, e.g. for BodyParser and FormValue
+		c.Request().SetBody(body)
+		c.Request().Header.Del(fiber.HeaderContentEncoding)
+		c.Request().Header.SetContentLength(len(body))
+
+		return c.Next()
+	}
+}
+
+// decode writes the decoded body to buf, it fails if the decoded body exceeds maxSize
+func decode(buf *bytebufferpool.ByteBuffer, encoding string, body []byte, maxSize int) error {
+	var (
+		r   io.Reader
+		err error
+	)
+	switch encoding {
+	case EncodingGzip:
+		var gr *gzip.Reader
+		if gr, err = gzip.NewReader(bytes.NewReader(body)); err == nil {
+			defer gr.Close()
+			r = gr
+		}
+	case EncodingDeflate:
+		// Deflate is the zlib format, but some clients send raw deflate data
+		var zr io.ReadCloser
+		if zr, err = zlib.NewReader(bytes.NewReader(body)); err == nil {
+			defer zr.Close()
+			r = zr
+		} else {
+			fr := flate.NewReader(bytes.NewReader(body))
+			defer fr.Close()
+			r, err = fr, nil
+		}
+	case EncodingBrotli:
+		r = brotli.NewReader(bytes.NewReader(body))
+	case EncodingZstd:
+		var zr *zstd.Decoder
+		if zr, err = zstd.NewReader(bytes.NewReader(body), zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(uint64(maxSize)+1)); err == nil {
+			defer zr.Close()
+			r = zr
+		}
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "decompress: invalid "+encoding+" body")
+	}
+
+	// Read one more byte than allowed to detect larger bodies
+	n, err := buf.ReadFrom(io.LimitReader(r, int64(maxSize)+1))
+	if n > int64(maxSize) {
+		return fiber.ErrRequestEntityTooLarge
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "decompress: invalid "+encoding+" body")
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package decompress
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/klauspost/compress/zstd"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Decompress
+func Test_Decompress(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New())
+	app.Post("/", func(c *fiber.Ctx) error {
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return err
+		}
+		return c.SendString(body.Name + " " + c.Get(fiber.HeaderContentEncoding))
+	})
+	app.Post("/form", func(c *fiber.Ctx) error {
+		return c.SendString(c.FormValue("name"))
+	})
+
+	payload := []byte(`{"name":"john"}`)
+	enc, err := zstd.NewWriter(nil)
+	utils.AssertEqual(t, nil, err)
+	for encoding, body := range map[string][]byte{
+		"gzip":       fasthttp.AppendGzipBytes(nil, payload),
+		"deflate":    fasthttp.AppendDeflateBytes(nil, payload),
+		"br":         fasthttp.AppendBrotliBytes(nil, payload),
+		"zstd":       enc.EncodeAll(payload, nil),
+		"gzip, br":   fasthttp.AppendBrotliBytes(nil, fasthttp.AppendGzipBytes(nil, payload)),
+		"identity":   payload,
+		"GZIP":       fasthttp.AppendGzipBytes(nil, payload),
+		"":           payload,
+		"  deflate ": fasthttp.AppendDeflateBytes(nil, payload),
+	} {
+		req := httptest.NewRequest(fiber.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderContentEncoding, encoding)
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode, encoding)
+		respBody, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, "john ", string(respBody), encoding)
+	}
+
+	// Forms are parsed from the decompressed body
+	req := httptest.NewRequest(fiber.MethodPost, "/form", bytes.NewReader(fasthttp.AppendGzipBytes(nil, []byte("name=jane"))))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+	req.Header.Set(fiber.HeaderContentEncoding, "gzip")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	respBody, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "jane", string(respBody))
+}
+
+// go test -run Test_Decompress_Errors
+func Test_Decompress_Errors(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		MaxSize:   1024,
+		Encodings: []string{EncodingGzip},
+	}))
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	test := func(encoding string, body []byte) int {
+		req := httptest.NewRequest(fiber.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set(fiber.HeaderContentEncoding, encoding)
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		return resp.StatusCode
+	}
+
+	utils.AssertEqual(t, fiber.StatusOK, test("gzip", fasthttp.AppendGzipBytes(nil, bytes.Repeat([]byte("a"), 1024))))
+	// A small body, which expands beyond MaxSize
+	bomb := fasthttp.AppendGzipBytes(nil, bytes.Repeat([]byte("a"), 1025))
+	utils.AssertEqual(t, true, len(bomb) < 100)
+	utils.AssertEqual(t, fiber.StatusRequestEntityTooLarge, test("gzip", bomb))
+	utils.AssertEqual(t, fiber.StatusBadRequest, test("gzip", []byte("not gzip")))
+
+	req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("data"))
+	req.Header.Set(fiber.HeaderContentEncoding, "br")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+	utils.AssertEqual(t, "gzip", resp.Header.Get(fiber.HeaderAcceptEncoding))
+}
+
+// go test -run Test_Decompress_Next
+func Test_Decompress_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Next: func(_ *fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
+}
@@ -1,7 +1,10 @@
 package etag
 
 import (
+	"hash/crc32"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/internal/xxhash"
 )
 
 // Config defines the config for middleware.
@@ -20,12 +23,40 @@ type Config struct {
 	//
 	// Optional. Default: nil
 	Next func(c *fiber.Ctx) bool
+
+	// Hash returns the checksum of the response body, the ETag is the
+	// length of the body and its checksum. XXHash is faster for large
+	// bodies, but changes the ETags of CRC32.
+	//
+	// Optional. Default: CRC32
+	Hash func(body []byte) uint64
+
+	// MaxBodySize skips the ETag of response bodies above the size in
+	// bytes, e.g. to avoid hashing large downloads. Streamed bodies, e.g.
+	// sent by SendStream, are skipped anyway, since hashing would buffer them.
+	//
+	// Optional. Default: 0, i.e. no limit
+	MaxBodySize int
 }
 
 // ConfigDefault is the default config
 var ConfigDefault = Config{
-	Weak: false,
-	Next: nil,
+	Weak:        false,
+	Next:        nil,
+	Hash:        CRC32,
+	MaxBodySize: 0,
+}
+
+var crc32q = crc32.MakeTable(0xD5828281)
+
+// CRC32 returns the CRC-32 checksum of the body with the Koopman polynomial
+func CRC32(body []byte) uint64 {
+	return uint64(crc32.Checksum(body, crc32q))
+}
+
+// XXHash returns the xxHash64 checksum of the body
+func XXHash(body []byte) uint64 {
+	return xxhash.Sum64(body)
 }
 
 // Helper function to set default values
@@ -39,6 +70,8 @@ func configDefault(config ...Config) Config {
 	cfg := config[0]
 
 	// Set default values
-
+	if cfg.Hash == nil {
+		cfg.Hash = ConfigDefault.Hash
+	}
 	return cfg
 }
@@ -2,7 +2,6 @@ package etag
 
 import (
 	"bytes"
-	"hash/crc32"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/internal/bytebufferpool"
@@ -18,8 +17,6 @@ func New(config ...Config) fiber.Handler {
 	// Set default config
 	cfg := configDefault(config...)
 
-	crc32q := crc32.MakeTable(0xD5828281)
-
 	// Return new handler
 	return func(c *fiber.Ctx) (err error) {
 		// Don't execute middleware if Next returns true
@@ -36,9 +33,13 @@ func New(config ...Config) fiber.Handler {
 		if c.Response().StatusCode() != fiber.StatusOK {
 			return
 		}
+		// Don't buffer streamed bodies
+		if c.Response().IsBodyStream() {
+			return
+		}
 		body := c.Response().Body()
-		// Skips ETag if no response body is present
-		if len(body) == 0 {
+		// Skips ETag if no response body is present or it's too large
+		if len(body) == 0 || (cfg.MaxBodySize > 0 && len(body) > cfg.MaxBodySize) {
 			return
 		}
 		// Skip ETag if header is already present
@@ -56,9 +57,9 @@ func New(config ...Config) fiber.Handler {
 		}
 
 		_ = bb.WriteByte('"')
-		bb.B = appendUint(bb.Bytes(), uint32(len(body)))
+		bb.B = appendUint(bb.Bytes(), uint64(len(body)))
 		_ = bb.WriteByte('-')
-		bb.B = appendUint(bb.Bytes(), crc32.Checksum(body, crc32q))
+		bb.B = appendUint(bb.Bytes(), cfg.Hash(body))
 		_ = bb.WriteByte('"')
 
 		etag := bb.Bytes()
@@ -95,11 +96,11 @@ func New(config ...Config) fiber.Handler {
 }
 
 // appendUint appends n to dst and returns the extended dst.
-func appendUint(dst []byte, n uint32) []byte {
+func appendUint(dst []byte, n uint64) []byte {
 	var b [20]byte
 	buf := b[:]
 	i := len(buf)
-	var q uint32
+	var q uint64
 	for n >= 10 {
 		i--
 		q = n / 10
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
@@ -245,6 +246,50 @@ func Test_ETag_CustomEtagPut(t *testing.T) {
 	utils.AssertEqual(t, fiber.StatusPreconditionFailed, resp.StatusCode)
 }
 
+// go test -run Test_ETag_Hash
+func Test_ETag_Hash(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Hash: XXHash}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	etag := resp.Header.Get(fiber.HeaderETag)
+	utils.AssertEqual(t, `"13-`+strconv.FormatUint(XXHash([]byte("Hello, World!")), 10)+`"`, etag)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusNotModified, resp.StatusCode)
+}
+
+// go test -run Test_ETag_Skip_Large_And_Streamed
+func Test_ETag_Skip_Large_And_Streamed(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{MaxBodySize: 10}))
+	app.Get("/small", func(c *fiber.Ctx) error {
+		return c.SendString("small")
+	})
+	app.Get("/large", func(c *fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+	app.Get("/stream", func(c *fiber.Ctx) error {
+		return c.SendStream(bytes.NewReader([]byte("tiny")), 4)
+	})
+
+	for path, expected := range map[string]bool{"/small": true, "/large": false, "/stream": false} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+		utils.AssertEqual(t, expected, resp.Header.Get(fiber.HeaderETag) != "", path)
+	}
+}
+
 // go test -v -run=^$ -bench=Benchmark_Etag -benchmem -count=4
 func Benchmark_Etag(b *testing.B) {
 	app := fiber.New()
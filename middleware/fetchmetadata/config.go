@@ -0,0 +1,100 @@
+package fetchmetadata
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// MessageForbidden is the key of the error message in fiber.Config.Messages
+const MessageForbidden = "fetchmetadata.forbidden"
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// AllowedSites is a list of Sec-Fetch-Site values which are always
+	// accepted. Possible values: "same-origin", "same-site", "none" and
+	// "cross-site".
+	//
+	// Optional. Default: []string{"same-origin", "same-site", "none"}
+	AllowedSites []string
+
+	// By default cross-site top-level navigations using a safe HTTP method
+	// (GET, HEAD) are accepted, so that links from other sites keep working.
+	// Set BlockNavigation to true to reject them as well.
+	//
+	// Optional. Default: false
+	BlockNavigation bool
+
+	// TrustedOrigins is a list of origins (scheme://host[:port]) which are
+	// accepted even if the request is cross-site, e.g. "https://app.example.com".
+	// The origin of the request host is always trusted.
+	//
+	// Optional. Default: []string{}
+	TrustedOrigins []string
+
+	// RequireOrigin rejects unsafe requests of clients which send neither
+	// Sec-Fetch-Site nor Origin headers. By default such requests are
+	// accepted, since they are not issued by a modern browser.
+	//
+	// Optional. Default: false
+	RequireOrigin bool
+
+	// ErrorHandler is executed when a request is rejected.
+	//
+	// Optional. Default: returns fiber.ErrForbidden, or a 403 error with
+	// the MessageForbidden of fiber.Config.Messages
+	ErrorHandler fiber.ErrorHandler
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:           nil,
+	AllowedSites:   []string{SiteSameOrigin, SiteSameSite, SiteNone},
+	TrustedOrigins: []string{},
+	ErrorHandler:   defaultErrorHandler,
+}
+
+// default ErrorHandler that process return error from fiber.Handler
+var defaultErrorHandler = func(c *fiber.Ctx, err error) error {
+	if msg := c.Message(MessageForbidden); msg != "" {
+		return fiber.NewError(fiber.StatusForbidden, msg)
+	}
+	return fiber.ErrForbidden
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if len(cfg.AllowedSites) == 0 {
+		cfg.AllowedSites = ConfigDefault.AllowedSites
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = ConfigDefault.ErrorHandler
+	}
+	// Normalize copies of the slices, which may be shared by the caller
+	sites := make([]string, len(cfg.AllowedSites))
+	for i := range cfg.AllowedSites {
+		sites[i] = utils.ToLower(cfg.AllowedSites[i])
+	}
+	cfg.AllowedSites = sites
+	origins := make([]string, len(cfg.TrustedOrigins))
+	for i := range cfg.TrustedOrigins {
+		origins[i] = strings.TrimRight(utils.ToLower(cfg.TrustedOrigins[i]), "/")
+	}
+	cfg.TrustedOrigins = origins
+	return cfg
+}
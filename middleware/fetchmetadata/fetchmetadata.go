@@ -0,0 +1,138 @@
+package fetchmetadata
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Values of the Sec-Fetch-Site request header
+// https://www.w3.org/TR/fetch-metadata/#sec-fetch-site-header
+const (
+	SiteSameOrigin = "same-origin"
+	SiteSameSite   = "same-site"
+	SiteNone       = "none"
+	SiteCrossSite  = "cross-site"
+)
+
+var (
+	errCrossSiteRequest = errors.New("fetchmetadata: cross-site request rejected")
+	errUntrustedOrigin  = errors.New("fetchmetadata: untrusted origin")
+	errMissingOrigin    = errors.New("fetchmetadata: missing origin")
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	allowedSites := make(map[string]struct{}, len(cfg.AllowedSites))
+	for _, site := range cfg.AllowedSites {
+		allowedSites[site] = struct{}{}
+	}
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		site := utils.ToLower(c.Get(fiber.HeaderSecFetchSite))
+
+		// Browsers which don't support fetch metadata, fall back to the Origin header
+		if site == "" {
+			if err := checkOrigin(c, cfg); err != nil {
+				return cfg.ErrorHandler(c, err)
+			}
+			return c.Next()
+		}
+
+		// Requests from allowed sites, e.g. same-origin or user initiated
+		if _, ok := allowedSites[site]; ok {
+			return c.Next()
+		}
+
+		// Allow simple top-level navigations from other sites
+		if !cfg.BlockNavigation && isNavigation(c) {
+			return c.Next()
+		}
+
+		// Last resort, the origin is trusted explicitly
+		if origin := c.Get(fiber.HeaderOrigin); origin != "" && isTrustedOrigin(c, cfg, origin) {
+			return c.Next()
+		}
+
+		return cfg.ErrorHandler(c, errCrossSiteRequest)
+	}
+}
+
+// SameOrigin reports whether the request has been issued by a page of the
+// same origin according to the Sec-Fetch-Site header. It can be used as the
+// Next function of the csrf middleware to exempt same-origin requests from
+// the token check:
+//
+//	app.Use(csrf.New(csrf.Config{
+//		Next: fetchmetadata.SameOrigin,
+//	}))
+func SameOrigin(c *fiber.Ctx) bool {
+	return utils.ToLower(c.Get(fiber.HeaderSecFetchSite)) == SiteSameOrigin
+}
+
+// checkOrigin validates the Origin header for unsafe requests
+func checkOrigin(c *fiber.Ctx, cfg Config) error {
+	if isSafeMethod(c.Method()) {
+		return nil
+	}
+	origin := c.Get(fiber.HeaderOrigin)
+	if origin == "" || origin == "null" {
+		if cfg.RequireOrigin {
+			return errMissingOrigin
+		}
+		return nil
+	}
+	if !isTrustedOrigin(c, cfg, origin) {
+		return errUntrustedOrigin
+	}
+	return nil
+}
+
+// isTrustedOrigin checks the origin against the request host and the trusted origins
+func isTrustedOrigin(c *fiber.Ctx, cfg Config, origin string) bool {
+	origin = strings.TrimRight(utils.ToLower(origin), "/")
+	if origin == utils.ToLower(c.Protocol()+"://"+c.Hostname()) {
+		return true
+	}
+	for _, trusted := range cfg.TrustedOrigins {
+		if origin == trusted {
+			return true
+		}
+	}
+	return false
+}
+
+// isNavigation reports whether the request is a top-level navigation with a safe method
+func isNavigation(c *fiber.Ctx) bool {
+	if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+		return false
+	}
+	if utils.ToLower(c.Get(fiber.HeaderSecFetchMode)) != "navigate" {
+		return false
+	}
+	// Plugins must not be loaded cross-site
+	switch utils.ToLower(c.Get(fiber.HeaderSecFetchDest)) {
+	case "object", "embed":
+		return false
+	}
+	return true
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+		return true
+	}
+	return false
+}
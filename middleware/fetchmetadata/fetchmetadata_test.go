@@ -0,0 +1,153 @@
+package fetchmetadata
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+func newTestApp(config ...Config) *fiber.App {
+	app := fiber.New()
+	app.Use(New(config...))
+	app.All("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+// go test -run Test_FetchMetadata
+func Test_FetchMetadata(t *testing.T) {
+	t.Parallel()
+	app := newTestApp()
+
+	tests := []struct {
+		method  string
+		site    string
+		mode    string
+		dest    string
+		origin  string
+		expCode int
+	}{
+		{method: fiber.MethodPost, site: SiteSameOrigin, expCode: fiber.StatusOK},
+		{method: fiber.MethodPost, site: SiteSameSite, expCode: fiber.StatusOK},
+		{method: fiber.MethodPost, site: SiteNone, expCode: fiber.StatusOK},
+		{method: fiber.MethodPost, site: SiteCrossSite, expCode: fiber.StatusForbidden},
+		{method: fiber.MethodGet, site: SiteCrossSite, mode: "navigate", dest: "document", expCode: fiber.StatusOK},
+		{method: fiber.MethodGet, site: SiteCrossSite, mode: "navigate", dest: "embed", expCode: fiber.StatusForbidden},
+		{method: fiber.MethodGet, site: SiteCrossSite, mode: "no-cors", dest: "image", expCode: fiber.StatusForbidden},
+		{method: fiber.MethodPost, site: SiteCrossSite, mode: "navigate", dest: "document", expCode: fiber.StatusForbidden},
+		// Fallback to the Origin header
+		{method: fiber.MethodPost, expCode: fiber.StatusOK},
+		{method: fiber.MethodPost, origin: "http://example.com", expCode: fiber.StatusOK},
+		{method: fiber.MethodPost, origin: "http://evil.com", expCode: fiber.StatusForbidden},
+		{method: fiber.MethodGet, origin: "http://evil.com", expCode: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "http://example.com/", nil)
+		if tt.site != "" {
+			req.Header.Set(fiber.HeaderSecFetchSite, tt.site)
+		}
+		if tt.mode != "" {
+			req.Header.Set(fiber.HeaderSecFetchMode, tt.mode)
+		}
+		if tt.dest != "" {
+			req.Header.Set(fiber.HeaderSecFetchDest, tt.dest)
+		}
+		if tt.origin != "" {
+			req.Header.Set(fiber.HeaderOrigin, tt.origin)
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tt.expCode, resp.StatusCode, tt.method+" "+tt.site+" "+tt.mode+" "+tt.origin)
+	}
+}
+
+// go test -run Test_FetchMetadata_TrustedOrigins
+func Test_FetchMetadata_TrustedOrigins(t *testing.T) {
+	t.Parallel()
+	origins := []string{"https://App.example.org/"}
+	app := newTestApp(Config{
+		TrustedOrigins: origins,
+		RequireOrigin:  true,
+	})
+	// The origins of the caller aren't modified
+	utils.AssertEqual(t, "https://App.example.org/", origins[0])
+
+	req := httptest.NewRequest(fiber.MethodPost, "http://example.com/", nil)
+	req.Header.Set(fiber.HeaderSecFetchSite, SiteCrossSite)
+	req.Header.Set(fiber.HeaderOrigin, "https://app.example.org")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest(fiber.MethodPost, "http://example.com/", nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://app.example.org")
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+	// RequireOrigin rejects requests without any browser headers
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodPost, "http://example.com/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+// go test -run Test_FetchMetadata_BlockNavigation
+func Test_FetchMetadata_BlockNavigation(t *testing.T) {
+	t.Parallel()
+	app := newTestApp(Config{
+		BlockNavigation: true,
+		AllowedSites:    []string{SiteSameOrigin},
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderSecFetchSite, SiteCrossSite)
+	req.Header.Set(fiber.HeaderSecFetchMode, "navigate")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusForbidden, resp.StatusCode)
+
+	req = httptest.NewRequest(fiber.MethodPost, "/", nil)
+	req.Header.Set(fiber.HeaderSecFetchSite, SiteSameSite)
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+// go test -run Test_FetchMetadata_SameOrigin
+func Test_FetchMetadata_SameOrigin(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		if SameOrigin(c) {
+			return c.SendString("same-origin")
+		}
+		return c.SendString("other")
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderSecFetchSite, "Same-Origin")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, int64(len("same-origin")), resp.ContentLength)
+}
+
+// go test -run Test_FetchMetadata_Next
+func Test_FetchMetadata_Next(t *testing.T) {
+	t.Parallel()
+	app := newTestApp(Config{
+		Next: func(_ *fiber.Ctx) bool {
+			return true
+		},
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/", nil)
+	req.Header.Set(fiber.HeaderSecFetchSite, SiteCrossSite)
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+}
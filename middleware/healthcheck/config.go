@@ -0,0 +1,73 @@
+package healthcheck
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// LivenessProbe reports whether the app is alive, i.e. it doesn't need
+	// to be restarted.
+	//
+	// Optional. Default: func(c *fiber.Ctx) bool { return true }
+	LivenessProbe func(c *fiber.Ctx) bool
+
+	// LivenessEndpoint is the path of the liveness probe.
+	//
+	// Optional. Default: "/livez"
+	LivenessEndpoint string
+
+	// ReadinessProbe reports whether the app is ready to serve traffic, e.g.
+	// whether its database is reachable. The readiness probe always fails
+	// while the app is draining, see fiber.App.Draining.
+	//
+	// Optional. Default: func(c *fiber.Ctx) bool { return true }
+	ReadinessProbe func(c *fiber.Ctx) bool
+
+	// ReadinessEndpoint is the path of the readiness probe.
+	//
+	// Optional. Default: "/readyz"
+	ReadinessEndpoint string
+}
+
+func defaultProbe(*fiber.Ctx) bool { return true }
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:              nil,
+	LivenessProbe:     defaultProbe,
+	LivenessEndpoint:  "/livez",
+	ReadinessProbe:    defaultProbe,
+	ReadinessEndpoint: "/readyz",
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.LivenessProbe == nil {
+		cfg.LivenessProbe = ConfigDefault.LivenessProbe
+	}
+	if cfg.LivenessEndpoint == "" {
+		cfg.LivenessEndpoint = ConfigDefault.LivenessEndpoint
+	}
+	if cfg.ReadinessProbe == nil {
+		cfg.ReadinessProbe = ConfigDefault.ReadinessProbe
+	}
+	if cfg.ReadinessEndpoint == "" {
+		cfg.ReadinessEndpoint = ConfigDefault.ReadinessEndpoint
+	}
+	return cfg
+}
@@ -0,0 +1,42 @@
+package healthcheck
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// New creates a new middleware handler, which answers the liveness and
+// readiness probes of load balancers and orchestrators with 200 OK or
+// 503 Service Unavailable.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		// Only GET and HEAD requests are probes
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+
+		var healthy bool
+		switch c.Path() {
+		case cfg.LivenessEndpoint:
+			healthy = cfg.LivenessProbe(c)
+		case cfg.ReadinessEndpoint:
+			// Fail while draining, so that load balancers stop sending traffic
+			healthy = !c.App().Draining() && cfg.ReadinessProbe(c)
+		default:
+			return c.Next()
+		}
+
+		if healthy {
+			return c.SendStatus(fiber.StatusOK)
+		}
+		return c.SendStatus(fiber.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,85 @@
+package healthcheck
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+func probe(t *testing.T, app *fiber.App, method, path string) int {
+	resp, err := app.Test(httptest.NewRequest(method, path, nil))
+	utils.AssertEqual(t, nil, err)
+	return resp.StatusCode
+}
+
+// go test -run Test_HealthCheck_Default
+func Test_HealthCheck_Default(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	utils.AssertEqual(t, fiber.StatusOK, probe(t, app, fiber.MethodGet, "/livez"))
+	utils.AssertEqual(t, fiber.StatusOK, probe(t, app, fiber.MethodHead, "/readyz"))
+	utils.AssertEqual(t, fiber.StatusOK, probe(t, app, fiber.MethodGet, "/"))
+	utils.AssertEqual(t, fiber.StatusNotFound, probe(t, app, fiber.MethodPost, "/readyz"))
+	utils.AssertEqual(t, fiber.StatusNotFound, probe(t, app, fiber.MethodGet, "/healthz"))
+}
+
+// go test -run Test_HealthCheck_Probes
+func Test_HealthCheck_Probes(t *testing.T) {
+	t.Parallel()
+	ready := false
+	app := fiber.New()
+	app.Use(New(Config{
+		LivenessEndpoint: "/live",
+		ReadinessProbe: func(c *fiber.Ctx) bool {
+			return ready
+		},
+	}))
+
+	utils.AssertEqual(t, fiber.StatusOK, probe(t, app, fiber.MethodGet, "/live"))
+	utils.AssertEqual(t, fiber.StatusServiceUnavailable, probe(t, app, fiber.MethodGet, "/readyz"))
+	ready = true
+	utils.AssertEqual(t, fiber.StatusOK, probe(t, app, fiber.MethodGet, "/readyz"))
+}
+
+// go test -run Test_HealthCheck_Draining
+func Test_HealthCheck_Draining(t *testing.T) {
+	t.Parallel()
+	app := fiber.New(fiber.Config{
+		ShutdownDrainPeriod:   200 * time.Millisecond,
+		DrainCloseConnections: true,
+	})
+	app.Use(New())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- app.Shutdown()
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// Requests are still served during the drain period, but readiness fails
+	utils.AssertEqual(t, true, app.Draining())
+	utils.AssertEqual(t, fiber.StatusServiceUnavailable, probe(t, app, fiber.MethodGet, "/readyz"))
+	utils.AssertEqual(t, fiber.StatusOK, probe(t, app, fiber.MethodGet, "/livez"))
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, true, resp.Close)
+
+	select {
+	case err := <-done:
+		t.Fatalf("shutdown returned before the drain period: %v", err)
+	default:
+	}
+	utils.AssertEqual(t, nil, <-done)
+}
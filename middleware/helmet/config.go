@@ -0,0 +1,165 @@
+package helmet
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// MetaKey is the route metadata key of per-route overrides, see App.Meta:
+//
+//	app.Get("/embed", handler)
+//	app.Meta(helmet.MetaKey, helmet.Config{
+//		XFrameOptions: helmet.Omit,
+//	})
+const MetaKey = "helmet"
+
+// Omit omits a header, which is set by default, e.g. in the config of a route
+const Omit = "-"
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// ContentSecurityPolicy sets the Content-Security-Policy header
+	//
+	// Optional. Default: ""
+	ContentSecurityPolicy string
+
+	// ContentSecurityPolicyReportOnly sets the
+	// Content-Security-Policy-Report-Only header, which reports violations
+	// of the policy without enforcing it, e.g. to try out a stricter policy
+	//
+	// Optional. Default: ""
+	ContentSecurityPolicyReportOnly string
+
+	// HSTSMaxAge sets the max-age of the Strict-Transport-Security header in
+	// seconds, which is only sent over HTTPS. A negative value omits the
+	// header, e.g. in the config of a route.
+	//
+	// Optional. Default: 0, i.e. no Strict-Transport-Security header
+	HSTSMaxAge int
+
+	// HSTSExcludeSubdomains omits includeSubDomains of the
+	// Strict-Transport-Security header
+	//
+	// Optional. Default: false
+	HSTSExcludeSubdomains bool
+
+	// HSTSPreload adds preload to the Strict-Transport-Security header
+	//
+	// Optional. Default: false
+	HSTSPreload bool
+
+	// XFrameOptions sets the X-Frame-Options header
+	//
+	// Optional. Default: "SAMEORIGIN"
+	XFrameOptions string
+
+	// ContentTypeNosniff sets the X-Content-Type-Options header
+	//
+	// Optional. Default: "nosniff"
+	ContentTypeNosniff string
+
+	// ReferrerPolicy sets the Referrer-Policy header
+	//
+	// Optional. Default: "no-referrer"
+	ReferrerPolicy string
+
+	// CrossOriginOpenerPolicy sets the Cross-Origin-Opener-Policy header
+	//
+	// Optional. Default: "same-origin"
+	CrossOriginOpenerPolicy string
+
+	// CrossOriginEmbedderPolicy sets the Cross-Origin-Embedder-Policy header
+	//
+	// Optional. Default: "require-corp"
+	CrossOriginEmbedderPolicy string
+
+	// CrossOriginResourcePolicy sets the Cross-Origin-Resource-Policy header
+	//
+	// Optional. Default: "same-origin"
+	CrossOriginResourcePolicy string
+
+	// PermissionsPolicy sets the Permissions-Policy header,
+	// e.g. "geolocation=(), camera=()"
+	//
+	// Optional. Default: ""
+	PermissionsPolicy string
+
+	// OriginAgentCluster sets the Origin-Agent-Cluster header
+	//
+	// Optional. Default: "?1"
+	OriginAgentCluster string
+
+	// XSSProtection sets the X-XSS-Protection header, "0" disables the
+	// auditor of legacy browsers, which caused vulnerabilities itself
+	//
+	// Optional. Default: "0"
+	XSSProtection string
+
+	// XDNSPrefetchControl sets the X-DNS-Prefetch-Control header
+	//
+	// Optional. Default: "off"
+	XDNSPrefetchControl string
+
+	// XDownloadOptions sets the X-Download-Options header
+	//
+	// Optional. Default: "noopen"
+	XDownloadOptions string
+
+	// XPermittedCrossDomainPolicies sets the
+	// X-Permitted-Cross-Domain-Policies header
+	//
+	// Optional. Default: "none"
+	XPermittedCrossDomainPolicies string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:                          nil,
+	XFrameOptions:                 "SAMEORIGIN",
+	ContentTypeNosniff:            "nosniff",
+	ReferrerPolicy:                "no-referrer",
+	CrossOriginOpenerPolicy:       "same-origin",
+	CrossOriginEmbedderPolicy:     "require-corp",
+	CrossOriginResourcePolicy:     "same-origin",
+	OriginAgentCluster:            "?1",
+	XSSProtection:                 "0",
+	XDNSPrefetchControl:           "off",
+	XDownloadOptions:              "noopen",
+	XPermittedCrossDomainPolicies: "none",
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	setDefault(&cfg.XFrameOptions, ConfigDefault.XFrameOptions)
+	setDefault(&cfg.ContentTypeNosniff, ConfigDefault.ContentTypeNosniff)
+	setDefault(&cfg.ReferrerPolicy, ConfigDefault.ReferrerPolicy)
+	setDefault(&cfg.CrossOriginOpenerPolicy, ConfigDefault.CrossOriginOpenerPolicy)
+	setDefault(&cfg.CrossOriginEmbedderPolicy, ConfigDefault.CrossOriginEmbedderPolicy)
+	setDefault(&cfg.CrossOriginResourcePolicy, ConfigDefault.CrossOriginResourcePolicy)
+	setDefault(&cfg.OriginAgentCluster, ConfigDefault.OriginAgentCluster)
+	setDefault(&cfg.XSSProtection, ConfigDefault.XSSProtection)
+	setDefault(&cfg.XDNSPrefetchControl, ConfigDefault.XDNSPrefetchControl)
+	setDefault(&cfg.XDownloadOptions, ConfigDefault.XDownloadOptions)
+	setDefault(&cfg.XPermittedCrossDomainPolicies, ConfigDefault.XPermittedCrossDomainPolicies)
+	return cfg
+}
+
+// setDefault sets the value of an empty field
+func setDefault(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
@@ -0,0 +1,109 @@
+package helmet
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// header is a security header with its value
+type header struct {
+	key, value string
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// The headers of the routes without overrides
+	headers, hsts := cfg.headers()
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		headers, hsts := headers, hsts
+		if override, ok := c.RouteMeta(MetaKey).(Config); ok {
+			headers, hsts = cfg.merge(override).headers()
+		}
+
+		for _, h := range headers {
+			c.Set(h.key, h.value)
+		}
+		if hsts != "" && c.Protocol() == "https" {
+			c.Set(fiber.HeaderStrictTransportSecurity, hsts)
+		}
+		return c.Next()
+	}
+}
+
+// headers returns the headers of the config, except the omitted and empty
+// ones, and the value of the Strict-Transport-Security header
+func (cfg Config) headers() (headers []header, hsts string) {
+	for _, h := range []header{
+		{fiber.HeaderContentSecurityPolicy, cfg.ContentSecurityPolicy},
+		{fiber.HeaderContentSecurityPolicyReportOnly, cfg.ContentSecurityPolicyReportOnly},
+		{fiber.HeaderXFrameOptions, cfg.XFrameOptions},
+		{fiber.HeaderXContentTypeOptions, cfg.ContentTypeNosniff},
+		{fiber.HeaderReferrerPolicy, cfg.ReferrerPolicy},
+		{fiber.HeaderCrossOriginOpenerPolicy, cfg.CrossOriginOpenerPolicy},
+		{fiber.HeaderCrossOriginEmbedderPolicy, cfg.CrossOriginEmbedderPolicy},
+		{fiber.HeaderCrossOriginResourcePolicy, cfg.CrossOriginResourcePolicy},
+		{fiber.HeaderPermissionsPolicy, cfg.PermissionsPolicy},
+		{fiber.HeaderOriginAgentCluster, cfg.OriginAgentCluster},
+		{fiber.HeaderXXSSProtection, cfg.XSSProtection},
+		{fiber.HeaderXDNSPrefetchControl, cfg.XDNSPrefetchControl},
+		{fiber.HeaderXDownloadOptions, cfg.XDownloadOptions},
+		{fiber.HeaderXPermittedCrossDomainPolicies, cfg.XPermittedCrossDomainPolicies},
+	} {
+		if h.value != "" && h.value != Omit {
+			headers = append(headers, h)
+		}
+	}
+
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(cfg.HSTSMaxAge)
+		if !cfg.HSTSExcludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+	return headers, hsts
+}
+
+// merge returns the config with the non-empty fields of the override
+func (cfg Config) merge(override Config) Config {
+	merged := cfg
+	for _, f := range []struct{ field, value *string }{
+		{&merged.ContentSecurityPolicy, &override.ContentSecurityPolicy},
+		{&merged.ContentSecurityPolicyReportOnly, &override.ContentSecurityPolicyReportOnly},
+		{&merged.XFrameOptions, &override.XFrameOptions},
+		{&merged.ContentTypeNosniff, &override.ContentTypeNosniff},
+		{&merged.ReferrerPolicy, &override.ReferrerPolicy},
+		{&merged.CrossOriginOpenerPolicy, &override.CrossOriginOpenerPolicy},
+		{&merged.CrossOriginEmbedderPolicy, &override.CrossOriginEmbedderPolicy},
+		{&merged.CrossOriginResourcePolicy, &override.CrossOriginResourcePolicy},
+		{&merged.PermissionsPolicy, &override.PermissionsPolicy},
+		{&merged.OriginAgentCluster, &override.OriginAgentCluster},
+		{&merged.XSSProtection, &override.XSSProtection},
+		{&merged.XDNSPrefetchControl, &override.XDNSPrefetchControl},
+		{&merged.XDownloadOptions, &override.XDownloadOptions},
+		{&merged.XPermittedCrossDomainPolicies, &override.XPermittedCrossDomainPolicies},
+	} {
+		if *f.value != "" {
+			*f.field = *f.value
+		}
+	}
+	if override.HSTSMaxAge != 0 {
+		merged.HSTSMaxAge = override.HSTSMaxAge
+		merged.HSTSExcludeSubdomains = override.HSTSExcludeSubdomains
+		merged.HSTSPreload = override.HSTSPreload
+	}
+	return merged
+}
@@ -0,0 +1,141 @@
+package helmet
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Helmet_Next
+func Test_Helmet_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Next: func(_ *fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "", resp.Header.Get(fiber.HeaderXFrameOptions))
+}
+
+// go test -run Test_Helmet_Default
+func Test_Helmet_Default(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	for key, value := range map[string]string{
+		fiber.HeaderXFrameOptions:                 "SAMEORIGIN",
+		fiber.HeaderXContentTypeOptions:           "nosniff",
+		fiber.HeaderReferrerPolicy:                "no-referrer",
+		fiber.HeaderCrossOriginOpenerPolicy:       "same-origin",
+		fiber.HeaderCrossOriginEmbedderPolicy:     "require-corp",
+		fiber.HeaderCrossOriginResourcePolicy:     "same-origin",
+		fiber.HeaderOriginAgentCluster:            "?1",
+		fiber.HeaderXXSSProtection:                "0",
+		fiber.HeaderXDNSPrefetchControl:           "off",
+		fiber.HeaderXDownloadOptions:              "noopen",
+		fiber.HeaderXPermittedCrossDomainPolicies: "none",
+		fiber.HeaderContentSecurityPolicy:         "",
+		fiber.HeaderStrictTransportSecurity:       "",
+		fiber.HeaderPermissionsPolicy:             "",
+	} {
+		utils.AssertEqual(t, value, resp.Header.Get(key), key)
+	}
+}
+
+// go test -run Test_Helmet_Custom
+func Test_Helmet_Custom(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		ContentSecurityPolicy:           "default-src 'self'",
+		ContentSecurityPolicyReportOnly: "default-src 'self'; script-src 'none'; report-uri /csp",
+		PermissionsPolicy:               "geolocation=(), camera=()",
+		XDNSPrefetchControl:             Omit,
+		HSTSMaxAge:                      31536000,
+		HSTSPreload:                     true,
+	}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "default-src 'self'", resp.Header.Get(fiber.HeaderContentSecurityPolicy))
+	utils.AssertEqual(t, "default-src 'self'; script-src 'none'; report-uri /csp", resp.Header.Get(fiber.HeaderContentSecurityPolicyReportOnly))
+	utils.AssertEqual(t, "geolocation=(), camera=()", resp.Header.Get(fiber.HeaderPermissionsPolicy))
+	utils.AssertEqual(t, "", resp.Header.Get(fiber.HeaderXDNSPrefetchControl))
+	utils.AssertEqual(t, "SAMEORIGIN", resp.Header.Get(fiber.HeaderXFrameOptions))
+	// HSTS is only sent over HTTPS
+	utils.AssertEqual(t, "", resp.Header.Get(fiber.HeaderStrictTransportSecurity))
+
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.SetMethod(fiber.MethodGet)
+	fctx.Request.SetRequestURI("/")
+	fctx.Request.Header.Set(fiber.HeaderXForwardedProto, "https")
+	app.Handler()(fctx)
+	utils.AssertEqual(t, "max-age=31536000; includeSubDomains; preload", string(fctx.Response.Header.Peek(fiber.HeaderStrictTransportSecurity)))
+}
+
+// go test -run Test_Helmet_Route_Override
+func Test_Helmet_Route_Override(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{ContentSecurityPolicy: "default-src 'self'"}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+	app.Get("/embed", func(c *fiber.Ctx) error {
+		return c.SendString("embeddable")
+	})
+	app.Meta(MetaKey, Config{
+		XFrameOptions:             Omit,
+		ContentSecurityPolicy:     "frame-ancestors https://partner.example.com",
+		CrossOriginResourcePolicy: "cross-origin",
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/embed", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "", resp.Header.Get(fiber.HeaderXFrameOptions))
+	utils.AssertEqual(t, "frame-ancestors https://partner.example.com", resp.Header.Get(fiber.HeaderContentSecurityPolicy))
+	utils.AssertEqual(t, "cross-origin", resp.Header.Get(fiber.HeaderCrossOriginResourcePolicy))
+	// The other headers are kept
+	utils.AssertEqual(t, "nosniff", resp.Header.Get(fiber.HeaderXContentTypeOptions))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "SAMEORIGIN", resp.Header.Get(fiber.HeaderXFrameOptions))
+	utils.AssertEqual(t, "default-src 'self'", resp.Header.Get(fiber.HeaderContentSecurityPolicy))
+}
+
+// go test -v -run=^$ -bench=Benchmark_Helmet -benchmem -count=4
+func Benchmark_Helmet(b *testing.B) {
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return nil
+	})
+	h := app.Handler()
+
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.SetMethod(fiber.MethodGet)
+	fctx.Request.SetRequestURI("/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(fctx)
+	}
+}
@@ -0,0 +1,126 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: a function skipping the safe methods GET, HEAD,
+	// OPTIONS and TRACE
+	Next func(c *fiber.Ctx) bool
+
+	// Lifetime is the time the response of a key is replayed for.
+	//
+	// Optional. Default: 30 * time.Minute
+	Lifetime time.Duration
+
+	// LockTimeout is the time a key stays locked by a request in progress,
+	// e.g. if the instance handling it crashed. Duplicates sent meanwhile
+	// are rejected with 409 Conflict.
+	//
+	// Optional. Default: 1 * time.Minute
+	LockTimeout time.Duration
+
+	// KeyHeader is the name of the request header carrying the key.
+	//
+	// Optional. Default: "Idempotency-Key"
+	KeyHeader string
+
+	// KeyHeaderValidate validates the key, an error is answered with
+	// 400 Bad Request.
+	//
+	// Optional. Default: a function accepting keys of 1 to 255 characters
+	KeyHeaderValidate func(key string) error
+
+	// Required rejects requests without a key with 400 Bad Request, by
+	// default they are passed on without idempotency.
+	//
+	// Optional. Default: false
+	Required bool
+
+	// KeepResponseHeaders are the response headers which are replayed,
+	// nil replays all headers.
+	//
+	// Optional. Default: nil
+	KeepResponseHeaders []string
+
+	// Storage holds the locks and responses, with a shared storage the
+	// keys are idempotent across instances. Storages implementing
+	// fiber.CASStorage lock the keys atomically, other storages are only
+	// locked within this process.
+	//
+	// Optional. Default: an in memory storage for this process only
+	Storage fiber.Storage
+
+	// KeyPrefix is the prefix of the keys in Storage.
+	//
+	// Optional. Default: "idempotency_"
+	KeyPrefix string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:              skipSafeMethods,
+	Lifetime:          30 * time.Minute,
+	LockTimeout:       1 * time.Minute,
+	KeyHeader:         "Idempotency-Key",
+	KeyHeaderValidate: validateKey,
+	KeyPrefix:         "idempotency_",
+}
+
+// skipSafeMethods skips the methods without side effects
+func skipSafeMethods(c *fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+		return true
+	}
+	return false
+}
+
+var errInvalidKeyLength = errors.New("idempotency: key must have 1 to 255 characters")
+
+// validateKey is the default KeyHeaderValidate
+func validateKey(key string) error {
+	if len(key) == 0 || len(key) > 255 {
+		return errInvalidKeyLength
+	}
+	return nil
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Next == nil {
+		cfg.Next = ConfigDefault.Next
+	}
+	if cfg.Lifetime <= 0 {
+		cfg.Lifetime = ConfigDefault.Lifetime
+	}
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = ConfigDefault.LockTimeout
+	}
+	if cfg.KeyHeader == "" {
+		cfg.KeyHeader = ConfigDefault.KeyHeader
+	}
+	if cfg.KeyHeaderValidate == nil {
+		cfg.KeyHeaderValidate = ConfigDefault.KeyHeaderValidate
+	}
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = ConfigDefault.KeyPrefix
+	}
+	return cfg
+}
@@ -0,0 +1,230 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/storage/memory"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// HeaderReplayed is set on the replayed responses
+const HeaderReplayed = "Idempotent-Replayed"
+
+// Errors of the middleware, see
+// https://datatracker.ietf.org/doc/draft-ietf-httpapi-idempotency-key-header/
+var (
+	// ErrMissingKey is returned if Required is set and the request has no key
+	ErrMissingKey = fiber.NewError(fiber.StatusBadRequest, "Idempotency-Key is missing")
+	// ErrKeyInUse is returned if a request with the key is still in progress
+	ErrKeyInUse = fiber.NewError(fiber.StatusConflict, "A request with the Idempotency-Key is in progress")
+	// ErrKeyReused is returned if the key has been used for another request
+	ErrKeyReused = fiber.NewError(fiber.StatusUnprocessableEntity, "Idempotency-Key has been used for another request")
+)
+
+// skipHeaders are set by the server for every response
+var skipHeaders = map[string]struct{}{
+	"content-length": {},
+	"connection":     {},
+	"date":           {},
+	"server":         {},
+}
+
+var errContention = errors.New("idempotency: too much contention on the key")
+
+// record is the lock of a request in progress or its response
+type record struct {
+	Fingerprint string      `json:"f"`
+	Done        bool        `json:"d,omitempty"`
+	Status      int         `json:"s,omitempty"`
+	Headers     [][2]string `json:"h,omitempty"`
+	Body        []byte      `json:"b,omitempty"`
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	storage := casStorage(cfg.Storage)
+
+	var keepHeaders map[string]struct{}
+	if cfg.KeepResponseHeaders != nil {
+		keepHeaders = make(map[string]struct{}, len(cfg.KeepResponseHeaders))
+		for _, h := range cfg.KeepResponseHeaders {
+			keepHeaders[utils.ToLower(h)] = struct{}{}
+		}
+	}
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		key := c.Get(cfg.KeyHeader)
+		if key == "" {
+			if cfg.Required {
+				return ErrMissingKey
+			}
+			return c.Next()
+		}
+		// The draft defines the key as a structured header string
+		if len(key) > 1 && key[0] == '"' && key[len(key)-1] == '"' {
+			key = key[1 : len(key)-1]
+		}
+		if err := cfg.KeyHeaderValidate(key); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		key = cfg.KeyPrefix + key
+
+		fingerprint := fingerprint(c)
+		locked, err := lock(storage, key, fingerprint, cfg.LockTimeout)
+		if err != nil {
+			return err
+		}
+		if locked.Fingerprint != fingerprint {
+			return ErrKeyReused
+		}
+		if locked.Done {
+			return replay(c, locked)
+		}
+
+		// Render errors, so that their responses are replayed as well
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = storage.Delete(key)
+				return err
+			}
+		}
+
+		// Server errors and streams are not replayed, the request may be retried
+		resp := c.Response()
+		if resp.StatusCode() >= fiber.StatusInternalServerError || resp.IsBodyStream() {
+			_ = storage.Delete(key)
+			return nil
+		}
+
+		done := record{
+			Fingerprint: fingerprint,
+			Done:        true,
+			Status:      resp.StatusCode(),
+			Body:        resp.Body(),
+		}
+		resp.Header.VisitAll(func(k, v []byte) {
+			name := utils.ToLower(string(k))
+			if _, ok := skipHeaders[name]; ok {
+				return
+			}
+			if keepHeaders != nil {
+				if _, ok := keepHeaders[name]; !ok {
+					return
+				}
+			}
+			done.Headers = append(done.Headers, [2]string{string(k), string(v)})
+		})
+		raw, err := json.Marshal(done)
+		if err != nil {
+			_ = storage.Delete(key)
+			return err
+		}
+		return storage.Set(key, raw, cfg.Lifetime)
+	}
+}
+
+// lock locks the key for the request, or returns the record of the request
+// which locked it before
+func lock(storage fiber.CASStorage, key, fingerprint string, timeout time.Duration) (record, error) {
+	lock, err := json.Marshal(record{Fingerprint: fingerprint})
+	if err != nil {
+		return record{}, err
+	}
+	// The record may expire between the attempts
+	for i := 0; i < 3; i++ {
+		ok, err := storage.CompareAndSwap(key, nil, lock, timeout)
+		if err != nil {
+			return record{}, err
+		}
+		if ok {
+			return record{Fingerprint: fingerprint}, nil
+		}
+		raw, err := storage.Get(key)
+		if err != nil {
+			return record{}, err
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		var locked record
+		if err := json.Unmarshal(raw, &locked); err != nil {
+			return record{}, err
+		}
+		if !locked.Done && locked.Fingerprint == fingerprint {
+			return record{}, ErrKeyInUse
+		}
+		return locked, nil
+	}
+	return record{}, errContention
+}
+
+// replay sends the response of the record
+func replay(c *fiber.Ctx, r record) error {
+	c.Response().Reset()
+	for _, h := range r.Headers {
+		c.Response().Header.Add(h[0], h[1])
+	}
+	c.Set(HeaderReplayed, "true")
+	c.Status(r.Status)
+	return c.Send(r.Body)
+}
+
+// fingerprint identifies the request, a key must not be reused for another one
+func fingerprint(c *fiber.Ctx) string {
+	h := sha256.New()
+	_, _ = h.Write(c.Request().Header.Method())
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(c.Request().RequestURI())
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// casStorage returns the storage as CASStorage, storages without the
+// operation are guarded by a local lock
+func casStorage(storage fiber.Storage) fiber.CASStorage {
+	if storage == nil {
+		return memory.New()
+	}
+	if cas, ok := storage.(fiber.CASStorage); ok {
+		return cas
+	}
+	return &localCASStorage{Storage: storage}
+}
+
+// localCASStorage implements the compare-and-swap operation for a single instance
+type localCASStorage struct {
+	fiber.Storage
+	mux sync.Mutex
+}
+
+func (s *localCASStorage) CompareAndSwap(key string, old, val []byte, exp time.Duration) (bool, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	cur, err := s.Storage.Get(key)
+	if err != nil {
+		return false, err
+	}
+	if len(cur) == 0 {
+		cur = nil
+	}
+	if (cur == nil) != (old == nil) || string(cur) != string(old) {
+		return false, nil
+	}
+	return true, s.Storage.Set(key, val, exp)
+}
@@ -0,0 +1,136 @@
+package idempotency
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+func send(t *testing.T, app *fiber.App, method, key, body string) *http.Response {
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	resp, err := app.Test(req, -1)
+	utils.AssertEqual(t, nil, err)
+	return resp
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	b, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	return string(b)
+}
+
+// go test -run Test_Idempotency_Replay
+func Test_Idempotency_Replay(t *testing.T) {
+	t.Parallel()
+	var count int32
+	app := fiber.New()
+	app.Use(New())
+	app.All("/", func(c *fiber.Ctx) error {
+		n := atomic.AddInt32(&count, 1)
+		c.Set("X-Count", strconv.Itoa(int(n)))
+		return c.Status(fiber.StatusCreated).SendString("payment " + strconv.Itoa(int(n)))
+	})
+
+	resp := send(t, app, fiber.MethodPost, "abc", "amount=10")
+	utils.AssertEqual(t, fiber.StatusCreated, resp.StatusCode)
+	utils.AssertEqual(t, "", resp.Header.Get(HeaderReplayed))
+	utils.AssertEqual(t, "payment 1", readBody(t, resp))
+
+	// Retries are replayed
+	resp = send(t, app, fiber.MethodPost, `"abc"`, "amount=10")
+	utils.AssertEqual(t, fiber.StatusCreated, resp.StatusCode)
+	utils.AssertEqual(t, "true", resp.Header.Get(HeaderReplayed))
+	utils.AssertEqual(t, "1", resp.Header.Get("X-Count"))
+	utils.AssertEqual(t, "payment 1", readBody(t, resp))
+
+	// The key must not be reused for another request
+	resp = send(t, app, fiber.MethodPost, "abc", "amount=20")
+	utils.AssertEqual(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	// Requests without a key and safe methods are not idempotent
+	utils.AssertEqual(t, "payment 2", readBody(t, send(t, app, fiber.MethodPost, "", "amount=10")))
+	utils.AssertEqual(t, "payment 3", readBody(t, send(t, app, fiber.MethodGet, "abc", "")))
+	utils.AssertEqual(t, int32(3), atomic.LoadInt32(&count))
+}
+
+// go test -run Test_Idempotency_Errors
+func Test_Idempotency_Errors(t *testing.T) {
+	t.Parallel()
+	var count int32
+	app := fiber.New()
+	app.Use(New(Config{
+		Required:            true,
+		KeepResponseHeaders: []string{fiber.HeaderContentType},
+	}))
+	app.Post("/", func(c *fiber.Ctx) error {
+		switch atomic.AddInt32(&count, 1) {
+		case 1:
+			return fiber.ErrServiceUnavailable
+		case 2:
+			c.Set("X-Secret", "1")
+			return fiber.ErrBadRequest
+		}
+		return c.SendString("ok")
+	})
+
+	utils.AssertEqual(t, fiber.StatusBadRequest, send(t, app, fiber.MethodPost, "", "").StatusCode)
+	utils.AssertEqual(t, fiber.StatusBadRequest, send(t, app, fiber.MethodPost, strings.Repeat("k", 256), "").StatusCode)
+
+	// Server errors may be retried
+	utils.AssertEqual(t, fiber.StatusServiceUnavailable, send(t, app, fiber.MethodPost, "key", "").StatusCode)
+
+	// Client errors are replayed
+	resp := send(t, app, fiber.MethodPost, "key", "")
+	utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+	utils.AssertEqual(t, "1", resp.Header.Get("X-Secret"))
+	resp = send(t, app, fiber.MethodPost, "key", "")
+	utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+	utils.AssertEqual(t, "Bad Request", readBody(t, resp))
+	utils.AssertEqual(t, "", resp.Header.Get("X-Secret"))
+	utils.AssertEqual(t, fiber.MIMETextPlainCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+	utils.AssertEqual(t, int32(2), atomic.LoadInt32(&count))
+}
+
+// go test -run Test_Idempotency_Concurrent
+func Test_Idempotency_Concurrent(t *testing.T) {
+	t.Parallel()
+	var count int32
+	entered, release := make(chan struct{}), make(chan struct{})
+	app := fiber.New()
+	app.Use(New())
+	app.Post("/", func(c *fiber.Ctx) error {
+		atomic.AddInt32(&count, 1)
+		close(entered)
+		<-release
+		return c.SendString("done")
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		utils.AssertEqual(t, "done", readBody(t, send(t, app, fiber.MethodPost, "key", "")))
+	}()
+	<-entered
+
+	// Duplicates are rejected while the first request is in progress
+	utils.AssertEqual(t, fiber.StatusConflict, send(t, app, fiber.MethodPost, "key", "").StatusCode)
+	close(release)
+	wg.Wait()
+
+	resp := send(t, app, fiber.MethodPost, "key", "")
+	utils.AssertEqual(t, "true", resp.Header.Get(HeaderReplayed))
+	utils.AssertEqual(t, "done", readBody(t, resp))
+	utils.AssertEqual(t, int32(1), atomic.LoadInt32(&count))
+}
@@ -0,0 +1,171 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MessageUnauthorized is the key of the response message in fiber.Config.Messages
+const MessageUnauthorized = "jwt.unauthorized"
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// KeyLookup is a string in the form of "<source>:<name>" that is used
+	// to extract the token from the request.
+	// Possible values:
+	// - "header:<name>"
+	// - "query:<name>"
+	// - "cookie:<name>"
+	//
+	// Optional. Default: "header:Authorization"
+	KeyLookup string
+
+	// AuthScheme is the scheme preceding the token in the Authorization
+	// header. It's ignored for other lookups.
+	//
+	// Optional. Default: "Bearer"
+	AuthScheme string
+
+	// SigningKey verifies tokens without a key id (kid) or with an unknown
+	// key id if there's no JWKS. It's a []byte for HS algorithms, an
+	// *rsa.PublicKey for RS and PS, an *ecdsa.PublicKey for ES and an
+	// ed25519.PublicKey for EdDSA.
+	//
+	// Required if SigningKeys and JWKSURL are empty. Default: nil
+	SigningKey interface{}
+
+	// SigningKeys are the keys by their key id (kid), see SigningKey.
+	//
+	// Optional. Default: nil
+	SigningKeys map[string]interface{}
+
+	// JWKSURL is the URL of a JSON Web Key Set, e.g. of an OpenID provider.
+	// The keys are cached and refreshed after JWKSRefresh, or earlier for
+	// tokens with an unknown key id, at most once per JWKSMinRefresh. The
+	// cached keys are kept if a refresh fails.
+	//
+	// Optional. Default: ""
+	JWKSURL string
+
+	// JWKSRefresh is the interval in which the keys of JWKSURL are refreshed
+	//
+	// Optional. Default: 1 * time.Hour
+	JWKSRefresh time.Duration
+
+	// JWKSMinRefresh is the minimal interval between refreshes of the keys
+	// of JWKSURL, which limits the refreshes caused by unknown key ids
+	//
+	// Optional. Default: 1 * time.Minute
+	JWKSMinRefresh time.Duration
+
+	// JWKSTimeout is the timeout of a request to JWKSURL
+	//
+	// Optional. Default: 5 * time.Second
+	JWKSTimeout time.Duration
+
+	// Algorithms restricts the accepted signature algorithms, e.g. "RS256".
+	// The algorithm of a token must match the type of its key in any case.
+	//
+	// Optional. Default: nil, i.e. all supported algorithms
+	Algorithms []string
+
+	// Issuer is the expected "iss" claim
+	//
+	// Optional. Default: "", i.e. no check
+	Issuer string
+
+	// Audience are the accepted audiences, the "aud" claim must contain one
+	//
+	// Optional. Default: nil, i.e. no check
+	Audience []string
+
+	// ClockSkew is the tolerance of the "exp", "nbf" and "iat" claims
+	//
+	// Optional. Default: 0
+	ClockSkew time.Duration
+
+	// ContextKey is the key to store the *Token in Locals
+	//
+	// Optional. Default: "user"
+	ContextKey string
+
+	// ErrorHandler is called for missing and invalid tokens.
+	// By default it will return with a 401 Unauthorized and the
+	// WWW-Authenticate header, the message can be overridden by
+	// MessageUnauthorized in fiber.Config.Messages
+	//
+	// Optional. Default: nil
+	ErrorHandler fiber.ErrorHandler
+}
+
+// Errors of missing and invalid tokens, which are passed to the ErrorHandler
+var (
+	ErrMissingToken     = errors.New("jwt: missing token")
+	ErrMalformedToken   = errors.New("jwt: malformed token")
+	ErrUnknownKey       = errors.New("jwt: unknown signing key")
+	ErrInvalidAlgorithm = errors.New("jwt: invalid signing algorithm")
+	ErrInvalidSignature = errors.New("jwt: invalid signature")
+	ErrExpired          = errors.New("jwt: token is expired")
+	ErrNotValidYet      = errors.New("jwt: token is not valid yet")
+	ErrInvalidIssuer    = errors.New("jwt: invalid issuer")
+	ErrInvalidAudience  = errors.New("jwt: invalid audience")
+)
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:           nil,
+	KeyLookup:      "header:" + fiber.HeaderAuthorization,
+	AuthScheme:     "Bearer",
+	JWKSRefresh:    1 * time.Hour,
+	JWKSMinRefresh: 1 * time.Minute,
+	JWKSTimeout:    5 * time.Second,
+	ContextKey:     "user",
+	ErrorHandler:   nil,
+}
+
+// Helper function to set default values
+func configDefault(config Config) Config {
+	cfg := config
+
+	// Set default values
+	if cfg.KeyLookup == "" {
+		cfg.KeyLookup = ConfigDefault.KeyLookup
+	}
+	if cfg.AuthScheme == "" {
+		cfg.AuthScheme = ConfigDefault.AuthScheme
+	}
+	if cfg.JWKSRefresh <= 0 {
+		cfg.JWKSRefresh = ConfigDefault.JWKSRefresh
+	}
+	if cfg.JWKSMinRefresh <= 0 {
+		cfg.JWKSMinRefresh = ConfigDefault.JWKSMinRefresh
+	}
+	if cfg.JWKSTimeout <= 0 {
+		cfg.JWKSTimeout = ConfigDefault.JWKSTimeout
+	}
+	if cfg.ContextKey == "" {
+		cfg.ContextKey = ConfigDefault.ContextKey
+	}
+	if cfg.SigningKey == nil && len(cfg.SigningKeys) == 0 && cfg.JWKSURL == "" {
+		panic("[JWT] SigningKey, SigningKeys or JWKSURL is required")
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = func(c *fiber.Ctx, err error) error {
+			challenge := `Bearer realm="Restricted"`
+			if err != ErrMissingToken {
+				challenge += `, error="invalid_token"`
+			}
+			c.Set(fiber.HeaderWWWAuthenticate, challenge)
+			return c.Status(fiber.StatusUnauthorized).SendString(
+				c.Message(MessageUnauthorized, "Invalid or expired JWT"))
+		}
+	}
+	return cfg
+}
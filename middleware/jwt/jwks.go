@@ -0,0 +1,163 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// jwk is a JSON Web Key
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC and OKP
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	// oct
+	K string `json:"k"`
+}
+
+// signingKey is a key of a JWKS with its declared algorithm
+type signingKey struct {
+	alg string
+	key interface{}
+}
+
+// jwks caches the keys of a JSON Web Key Set
+type jwks struct {
+	url        string
+	refresh    time.Duration
+	minRefresh time.Duration
+	timeout    time.Duration
+
+	mux       sync.RWMutex
+	keys      map[string]signingKey
+	fetched   time.Time // last successful fetch
+	attempted time.Time // last fetch attempt
+	fetching  sync.Mutex
+}
+
+// get returns the key of the key id, the keys are refreshed if they're
+// outdated or don't contain the key id
+func (s *jwks) get(kid string) (signingKey, bool) {
+	s.mux.RLock()
+	key, ok := s.keys[kid]
+	outdated := time.Since(s.fetched) >= s.refresh
+	s.mux.RUnlock()
+	if ok && !outdated {
+		return key, true
+	}
+
+	s.fetching.Lock()
+	s.mux.RLock()
+	// Another request may have refreshed the keys in the meantime
+	key, ok = s.keys[kid]
+	refresh := time.Since(s.attempted) >= s.minRefresh && (!ok || time.Since(s.fetched) >= s.refresh)
+	s.mux.RUnlock()
+	if refresh {
+		keys, err := s.fetch()
+		s.mux.Lock()
+		s.attempted = time.Now()
+		if err == nil {
+			s.keys, s.fetched = keys, s.attempted
+		}
+		key, ok = s.keys[kid]
+		s.mux.Unlock()
+	}
+	s.fetching.Unlock()
+	return key, ok
+}
+
+// fetch requests the keys
+func (s *jwks) fetch() (map[string]signingKey, error) {
+	code, body, errs := fiber.Get(s.url).Timeout(s.timeout).Bytes()
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	if code != fiber.StatusOK {
+		return nil, fmt.Errorf("jwt: jwks returned status %d", code)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]signingKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+		// Skip unsupported keys, so that new key types don't break the set
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = signingKey{alg: k.Alg, key: key}
+		}
+	}
+	return keys, nil
+}
+
+// publicKey decodes the key
+func (k jwk) publicKey() (interface{}, error) {
+	decode := base64.RawURLEncoding.DecodeString
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil || len(e) > 4 {
+			return nil, fmt.Errorf("jwt: invalid rsa exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("jwt: unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, fmt.Errorf("jwt: invalid ec key")
+		}
+		return pub, nil
+	case "OKP":
+		x, err := decode(k.X)
+		if err != nil || k.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("jwt: unsupported okp key")
+		}
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		return decode(k.K)
+	}
+	return nil, fmt.Errorf("jwt: unsupported key type %q", k.Kty)
+}
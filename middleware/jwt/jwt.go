@@ -0,0 +1,126 @@
+package jwt
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// tokenKey is the key of the *Token in Locals for TokenFromCtx, independent
+// of Config.ContextKey
+const tokenKey = "__jwt_token__"
+
+// New creates a new middleware handler
+func New(config Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config)
+
+	// Create the extractor of the token and the key set
+	extract := extractor(cfg)
+	var set *jwks
+	if cfg.JWKSURL != "" {
+		set = &jwks{
+			url:        cfg.JWKSURL,
+			refresh:    cfg.JWKSRefresh,
+			minRefresh: cfg.JWKSMinRefresh,
+			timeout:    cfg.JWKSTimeout,
+		}
+	}
+	algorithms := make(map[string]bool, len(cfg.Algorithms))
+	for _, alg := range cfg.Algorithms {
+		algorithms[alg] = true
+	}
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		raw := extract(c)
+		if raw == "" {
+			return cfg.ErrorHandler(c, ErrMissingToken)
+		}
+		token, alg, kid, err := parse(raw)
+		if err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+		if alg == "" || alg == "none" || (len(algorithms) > 0 && !algorithms[alg]) {
+			return cfg.ErrorHandler(c, ErrInvalidAlgorithm)
+		}
+
+		// Find the key of the token
+		key, ok := cfg.SigningKeys[kid]
+		if !ok && set != nil {
+			var sk signingKey
+			if sk, ok = set.get(kid); ok {
+				if sk.alg != "" && sk.alg != alg {
+					return cfg.ErrorHandler(c, ErrInvalidAlgorithm)
+				}
+				key = sk.key
+			}
+		}
+		if !ok && cfg.SigningKey != nil && (kid == "" || set == nil) {
+			key, ok = cfg.SigningKey, true
+		}
+		if !ok {
+			return cfg.ErrorHandler(c, ErrUnknownKey)
+		}
+
+		if err = verify(raw, alg, key); err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+		if err = cfg.validate(token, time.Now()); err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+
+		c.Locals(cfg.ContextKey, token)
+		c.Locals(tokenKey, token)
+		return c.Next()
+	}
+}
+
+// TokenFromCtx returns the verified token of the request, nil if the
+// middleware didn't verify one
+func TokenFromCtx(c *fiber.Ctx) *Token {
+	token, _ := c.Locals(tokenKey).(*Token)
+	return token
+}
+
+// extractor returns a function that extracts the token from the request
+func extractor(cfg Config) func(c *fiber.Ctx) string {
+	selectors := strings.SplitN(cfg.KeyLookup, ":", 2)
+	if len(selectors) != 2 || selectors[1] == "" {
+		panic("[JWT] KeyLookup must in the form of <source>:<name>")
+	}
+	name := selectors[1]
+
+	switch selectors[0] {
+	case "header":
+		if !strings.EqualFold(name, fiber.HeaderAuthorization) {
+			return func(c *fiber.Ctx) string {
+				return c.Get(name)
+			}
+		}
+		// Strip the scheme from the Authorization header
+		prefix := cfg.AuthScheme + " "
+		return func(c *fiber.Ctx) string {
+			auth := c.Get(name)
+			if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+				return ""
+			}
+			return strings.TrimSpace(auth[len(prefix):])
+		}
+	case "query":
+		return func(c *fiber.Ctx) string {
+			return c.Query(name)
+		}
+	case "cookie":
+		return func(c *fiber.Ctx) string {
+			return c.Cookies(name)
+		}
+	}
+	panic("[JWT] KeyLookup source must be header, query or cookie")
+}
@@ -0,0 +1,28 @@
+//go:build go1.18
+// +build go1.18
+
+package jwt
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClaimsFromCtx decodes the claims of the verified token of the request into
+// a T, e.g. a struct with the claims as fields:
+//
+//	type Claims struct {
+//		Subject string   `json:"sub"`
+//		Roles   []string `json:"roles"`
+//	}
+//
+//	claims, err := jwt.ClaimsFromCtx[Claims](c)
+//
+// It returns ErrMissingToken if the middleware didn't verify a token.
+func ClaimsFromCtx[T any](c *fiber.Ctx) (T, error) {
+	var claims T
+	token := TokenFromCtx(c)
+	if token == nil {
+		return claims, ErrMissingToken
+	}
+	return claims, token.Decode(&claims)
+}
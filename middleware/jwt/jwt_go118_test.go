@@ -0,0 +1,41 @@
+//go:build go1.18
+// +build go1.18
+
+package jwt
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_JWT_ClaimsFromCtx
+func Test_JWT_ClaimsFromCtx(t *testing.T) {
+	t.Parallel()
+	type claims struct {
+		Subject string   `json:"sub"`
+		Roles   []string `json:"roles"`
+	}
+	secret := []byte("secret")
+
+	app := fiber.New()
+	app.Use(New(Config{SigningKey: secret, ContextKey: "token"}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		claims, err := ClaimsFromCtx[claims](c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(claims.Subject + " " + claims.Roles[0])
+	})
+
+	status, body := request(t, app, sign(t, "HS256", "", secret, map[string]interface{}{"sub": "john", "roles": []string{"admin"}}))
+	utils.AssertEqual(t, fiber.StatusOK, status)
+	utils.AssertEqual(t, "john admin", body)
+
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	_, err := ClaimsFromCtx[claims](c)
+	utils.AssertEqual(t, ErrMissingToken, err)
+}
@@ -0,0 +1,290 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// sign creates a token of the claims signed by the private key
+func sign(t *testing.T, alg, kid string, key interface{}, claims map[string]interface{}) string {
+	t.Helper()
+	header := map[string]interface{}{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	h, err := json.Marshal(header)
+	utils.AssertEqual(t, nil, err)
+	p, err := json.Marshal(claims)
+	utils.AssertEqual(t, nil, err)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(p)
+
+	hash := hashes[alg[len(alg)-3:]]
+	digest := func() []byte {
+		d := hash.New()
+		d.Write([]byte(signed))
+		return d.Sum(nil)
+	}
+	var sig []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(hash.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, hash, digest())
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, digest())
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig = make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, []byte(signed))
+	}
+	utils.AssertEqual(t, nil, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// request sends a request with the token and returns the status and body
+func request(t *testing.T, app *fiber.App, token string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	return resp.StatusCode, string(body)
+}
+
+func newApp(config Config) *fiber.App {
+	app := fiber.New()
+	app.Use(New(config))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("user").(*Token).Subject())
+	})
+	return app
+}
+
+// go test -run Test_JWT_Next
+func Test_JWT_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		SigningKey: []byte("secret"),
+		Next: func(_ *fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// go test -run Test_JWT_HMAC
+func Test_JWT_HMAC(t *testing.T) {
+	t.Parallel()
+	secret := []byte("secret")
+	var failure error
+	app := newApp(Config{
+		SigningKey: secret,
+		Issuer:     "https://issuer.example.com",
+		Audience:   []string{"api", "admin"},
+		ClockSkew:  time.Minute,
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			failure = err
+			return c.SendStatus(fiber.StatusUnauthorized)
+		},
+	})
+	now := time.Now().Unix()
+	claims := func(extra map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"sub": "john", "iss": "https://issuer.example.com", "aud": "api", "exp": now + 60}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+
+	status, body := request(t, app, sign(t, "HS256", "", secret, claims(nil)))
+	utils.AssertEqual(t, fiber.StatusOK, status)
+	utils.AssertEqual(t, "john", body)
+
+	status, _ = request(t, app, sign(t, "HS512", "", secret, claims(map[string]interface{}{"aud": []string{"web", "admin"}})))
+	utils.AssertEqual(t, fiber.StatusOK, status)
+	// Within the clock skew
+	status, _ = request(t, app, sign(t, "HS256", "", secret, claims(map[string]interface{}{"exp": now - 30, "nbf": now + 30})))
+	utils.AssertEqual(t, fiber.StatusOK, status)
+
+	for _, tc := range []struct {
+		token string
+		err   error
+	}{
+		{"", ErrMissingToken},
+		{"a.b", ErrMalformedToken},
+		{sign(t, "HS256", "", []byte("other"), claims(nil)), ErrInvalidSignature},
+		{sign(t, "HS256", "", secret, claims(map[string]interface{}{"exp": now - 120})), ErrExpired},
+		{sign(t, "HS256", "", secret, claims(map[string]interface{}{"nbf": now + 120})), ErrNotValidYet},
+		{sign(t, "HS256", "", secret, claims(map[string]interface{}{"iss": "https://evil.example.com"})), ErrInvalidIssuer},
+		{sign(t, "HS256", "", secret, claims(map[string]interface{}{"aud": []string{"web"}})), ErrInvalidAudience},
+		{sign(t, "HS256", "", secret, claims(map[string]interface{}{"aud": nil})), ErrInvalidAudience},
+	} {
+		failure = nil
+		status, _ = request(t, app, tc.token)
+		utils.AssertEqual(t, fiber.StatusUnauthorized, status)
+		utils.AssertEqual(t, tc.err, failure)
+	}
+}
+
+// go test -run Test_JWT_Algorithms
+func Test_JWT_Algorithms(t *testing.T) {
+	t.Parallel()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	utils.AssertEqual(t, nil, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	utils.AssertEqual(t, nil, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	utils.AssertEqual(t, nil, err)
+
+	app := newApp(Config{
+		SigningKeys: map[string]interface{}{
+			"rsa": &rsaKey.PublicKey,
+			"ec":  &ecKey.PublicKey,
+			"ed":  edPub,
+		},
+	})
+	claims := map[string]interface{}{"sub": "john"}
+
+	for _, tc := range []struct {
+		alg, kid string
+		key      interface{}
+		status   int
+	}{
+		{"RS256", "rsa", rsaKey, fiber.StatusOK},
+		{"RS512", "rsa", rsaKey, fiber.StatusOK},
+		{"ES256", "ec", ecKey, fiber.StatusOK},
+		{"EdDSA", "ed", edKey, fiber.StatusOK},
+		{"RS256", "ec", rsaKey, fiber.StatusUnauthorized},
+		{"ES256", "unknown", ecKey, fiber.StatusUnauthorized},
+		// The curve of the key must match the algorithm
+		{"ES384", "ec", ecKey, fiber.StatusUnauthorized},
+	} {
+		status, _ := request(t, app, sign(t, tc.alg, tc.kid, tc.key, claims))
+		utils.AssertEqual(t, tc.status, status, tc.alg+" "+tc.kid)
+	}
+
+	// The public key mustn't be usable as HMAC secret
+	pub, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	utils.AssertEqual(t, nil, err)
+	status, _ := request(t, app, sign(t, "HS256", "rsa", pub, claims))
+	utils.AssertEqual(t, fiber.StatusUnauthorized, status)
+
+	// Unsigned tokens are rejected
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"john"}`)) + "."
+	status, _ = request(t, app, unsigned)
+	utils.AssertEqual(t, fiber.StatusUnauthorized, status)
+
+	// Restricted algorithms
+	app = newApp(Config{SigningKey: &rsaKey.PublicKey, Algorithms: []string{"PS256"}})
+	status, _ = request(t, app, sign(t, "RS256", "", rsaKey, claims))
+	utils.AssertEqual(t, fiber.StatusUnauthorized, status)
+}
+
+// go test -run Test_JWT_JWKS
+func Test_JWT_JWKS(t *testing.T) {
+	t.Parallel()
+	newKey := func() *rsa.PrivateKey {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		utils.AssertEqual(t, nil, err)
+		return key
+	}
+	jwkOf := func(kid string, key *rsa.PrivateKey) map[string]interface{} {
+		return map[string]interface{}{
+			"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+	}
+
+	var (
+		keys    atomic.Value
+		fetches int32
+	)
+	first, second := newKey(), newKey()
+	keys.Store([]interface{}{jwkOf("1", first)})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys.Load()})
+	}))
+	defer server.Close()
+
+	app := newApp(Config{JWKSURL: server.URL, JWKSMinRefresh: 50 * time.Millisecond})
+	claims := map[string]interface{}{"sub": "john"}
+
+	status, body := request(t, app, sign(t, "RS256", "1", first, claims))
+	utils.AssertEqual(t, fiber.StatusOK, status)
+	utils.AssertEqual(t, "john", body)
+	status, _ = request(t, app, sign(t, "RS256", "1", first, claims))
+	utils.AssertEqual(t, fiber.StatusOK, status)
+	utils.AssertEqual(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// The key is rotated, the unknown key id refreshes the keys
+	keys.Store([]interface{}{jwkOf("1", first), jwkOf("2", second)})
+	time.Sleep(60 * time.Millisecond)
+	status, _ = request(t, app, sign(t, "RS256", "2", second, claims))
+	utils.AssertEqual(t, fiber.StatusOK, status)
+	utils.AssertEqual(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// Unknown key ids don't refresh the keys more than once per JWKSMinRefresh
+	for i := 0; i < 3; i++ {
+		status, _ = request(t, app, sign(t, "RS256", "3", second, claims))
+		utils.AssertEqual(t, fiber.StatusUnauthorized, status)
+	}
+	utils.AssertEqual(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// The declared algorithm of the key is enforced
+	status, _ = request(t, app, sign(t, "RS512", "2", second, claims))
+	utils.AssertEqual(t, fiber.StatusUnauthorized, status)
+}
+
+// go test -run Test_JWT_ErrorHandler
+func Test_JWT_ErrorHandler(t *testing.T) {
+	t.Parallel()
+	app := newApp(Config{SigningKey: []byte("secret")})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusUnauthorized, resp.StatusCode)
+	utils.AssertEqual(t, `Bearer realm="Restricted"`, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer invalid")
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `Bearer realm="Restricted", error="invalid_token"`, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "Invalid or expired JWT", string(body))
+
+}
@@ -0,0 +1,195 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // register SHA-256 for crypto.Hash
+	_ "crypto/sha512" // register SHA-384 and SHA-512 for crypto.Hash
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Token is a verified JSON Web Token
+type Token struct {
+	// Raw is the encoded token
+	Raw    string
+	Header map[string]interface{}
+	// Claims are the decoded claims, numbers are float64
+	Claims map[string]interface{}
+
+	payload []byte
+}
+
+// Decode decodes the claims into v, e.g. a struct with the claims as fields
+func (t *Token) Decode(v interface{}) error {
+	return json.Unmarshal(t.payload, v)
+}
+
+// Subject returns the "sub" claim
+func (t *Token) Subject() string {
+	sub, _ := t.Claims["sub"].(string)
+	return sub
+}
+
+// hashes are the hash functions of the algorithms by their suffix
+var hashes = map[string]crypto.Hash{
+	"256": crypto.SHA256,
+	"384": crypto.SHA384,
+	"512": crypto.SHA512,
+}
+
+// curves are the curves of the ES algorithms
+var curves = map[string]elliptic.Curve{
+	"ES256": elliptic.P256(),
+	"ES384": elliptic.P384(),
+	"ES512": elliptic.P521(),
+}
+
+// parse decodes the token without verifying it
+func parse(raw string) (token *Token, alg, kid string, err error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, "", "", ErrMalformedToken
+	}
+	token = &Token{Raw: raw}
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(header, &token.Header) != nil {
+		return nil, "", "", ErrMalformedToken
+	}
+	if token.payload, err = base64.RawURLEncoding.DecodeString(parts[1]); err != nil {
+		return nil, "", "", ErrMalformedToken
+	}
+	if json.Unmarshal(token.payload, &token.Claims) != nil {
+		return nil, "", "", ErrMalformedToken
+	}
+	alg, _ = token.Header["alg"].(string)
+	kid, _ = token.Header["kid"].(string)
+	return token, alg, kid, nil
+}
+
+// verify verifies the signature of the token by the key. The algorithm must
+// match the type of the key, so that e.g. a public RSA key can't be used as
+// HMAC secret.
+func verify(raw, alg string, key interface{}) error {
+	i := strings.LastIndexByte(raw, '.')
+	signed, encoded := raw[:i], raw[i+1:]
+	sig, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrMalformedToken
+	}
+	if alg == "EdDSA" {
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return ErrInvalidAlgorithm
+		}
+		if !ed25519.Verify(pub, []byte(signed), sig) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+
+	if len(alg) != 5 {
+		return ErrInvalidAlgorithm
+	}
+	hash, ok := hashes[alg[2:]]
+	if !ok {
+		return ErrInvalidAlgorithm
+	}
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	valid := false
+	switch alg[:2] {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok {
+			return ErrInvalidAlgorithm
+		}
+		mac := hmac.New(hash.New, secret)
+		mac.Write([]byte(signed))
+		valid = hmac.Equal(mac.Sum(nil), sig)
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrInvalidAlgorithm
+		}
+		valid = rsa.VerifyPKCS1v15(pub, hash, digest, sig) == nil
+	case "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrInvalidAlgorithm
+		}
+		valid = rsa.VerifyPSS(pub, hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto}) == nil
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || pub.Curve != curves[alg] {
+			return ErrInvalidAlgorithm
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return ErrInvalidSignature
+		}
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		valid = ecdsa.Verify(pub, digest, r, s)
+	default:
+		return ErrInvalidAlgorithm
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// validate checks the registered claims of the token
+func (cfg *Config) validate(token *Token, now time.Time) error {
+	if exp, ok := token.Claims["exp"].(float64); ok && !now.Before(unix(exp).Add(cfg.ClockSkew)) {
+		return ErrExpired
+	}
+	if nbf, ok := token.Claims["nbf"].(float64); ok && now.Add(cfg.ClockSkew).Before(unix(nbf)) {
+		return ErrNotValidYet
+	}
+	if iat, ok := token.Claims["iat"].(float64); ok && now.Add(cfg.ClockSkew).Before(unix(iat)) {
+		return ErrNotValidYet
+	}
+	if cfg.Issuer != "" && token.Claims["iss"] != cfg.Issuer {
+		return ErrInvalidIssuer
+	}
+	if len(cfg.Audience) > 0 && !hasAudience(token.Claims["aud"], cfg.Audience) {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+// unix converts a NumericDate to a time
+func unix(date float64) time.Time {
+	sec := int64(date)
+	return time.Unix(sec, int64((date-float64(sec))*1e9))
+}
+
+// hasAudience reports if the "aud" claim, a string or a list of strings,
+// contains one of the audiences
+func hasAudience(aud interface{}, audiences []string) bool {
+	var values []interface{}
+	switch v := aud.(type) {
+	case string:
+		values = []interface{}{v}
+	case []interface{}:
+		values = v
+	}
+	for _, value := range values {
+		for _, audience := range audiences {
+			if value == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package keyauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// MessageUnauthorized is the key of the response message in fiber.Config.Messages
+const MessageUnauthorized = "keyauth.unauthorized"
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// KeyLookup is a string in the form of "<source>:<name>" that is used
+	// to extract the key from the request.
+	// Possible values:
+	// - "header:<name>"
+	// - "query:<name>"
+	// - "cookie:<name>"
+	// - "form:<name>"
+	// - "param:<name>"
+	//
+	// Optional. Default: "header:Authorization"
+	KeyLookup string
+
+	// AuthScheme is the scheme preceding the key in the Authorization
+	// header, e.g. "Bearer <key>". It's ignored for other lookups.
+	//
+	// Optional. Default: "Bearer"
+	AuthScheme string
+
+	// Realm is the realm attribute of the WWW-Authenticate header of
+	// unauthorized responses.
+	//
+	// Optional. Default: "Restricted"
+	Realm string
+
+	// Keys defines the allowed keys, which are compared in constant time.
+	// The principal of a key is the key itself.
+	//
+	// Required if Validator is nil. Default: nil
+	Keys []string
+
+	// Validator defines a function to check the key with the request, e.g.
+	// against a database, which returns the authenticated principal. A nil
+	// principal rejects the key, an error is passed to the error handler of
+	// the app. It takes precedence over Keys.
+	//
+	// Required if Keys is empty. Default: nil
+	Validator func(c *fiber.Ctx, key string) (interface{}, error)
+
+	// Unauthorized defines the response for missing or rejected keys.
+	// By default it will return with a 401 Unauthorized and the
+	// WWW-Authenticate header for the Authorization header lookup,
+	// the message can be overridden by MessageUnauthorized in fiber.Config.Messages
+	//
+	// Optional. Default: nil
+	Unauthorized fiber.Handler
+
+	// ContextKey is the key to store the key in Locals
+	//
+	// Optional. Default: "token"
+	ContextKey string
+
+	// ContextPrincipal is the key to store the principal returned by
+	// Validator in Locals
+	//
+	// Optional. Default: "principal"
+	ContextPrincipal string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:             nil,
+	KeyLookup:        "header:" + fiber.HeaderAuthorization,
+	AuthScheme:       "Bearer",
+	Realm:            "Restricted",
+	Unauthorized:     nil,
+	ContextKey:       "token",
+	ContextPrincipal: "principal",
+}
+
+// Helper function to set default values
+func configDefault(config Config) Config {
+	cfg := config
+
+	// Set default values
+	if cfg.KeyLookup == "" {
+		cfg.KeyLookup = ConfigDefault.KeyLookup
+	}
+	if cfg.AuthScheme == "" {
+		cfg.AuthScheme = ConfigDefault.AuthScheme
+	}
+	if cfg.Realm == "" {
+		cfg.Realm = ConfigDefault.Realm
+	}
+	if cfg.ContextKey == "" {
+		cfg.ContextKey = ConfigDefault.ContextKey
+	}
+	if cfg.ContextPrincipal == "" {
+		cfg.ContextPrincipal = ConfigDefault.ContextPrincipal
+	}
+	if cfg.Validator == nil {
+		if len(cfg.Keys) == 0 {
+			panic("[KEYAUTH] Keys or Validator is required")
+		}
+		cfg.Validator = validateKeys(cfg.Keys)
+	}
+	if cfg.Unauthorized == nil {
+		challenge := ""
+		if strings.EqualFold(cfg.KeyLookup, ConfigDefault.KeyLookup) {
+			challenge = cfg.AuthScheme + ` realm="` + cfg.Realm + `"`
+		}
+		cfg.Unauthorized = func(c *fiber.Ctx) error {
+			if challenge != "" {
+				c.Set(fiber.HeaderWWWAuthenticate, challenge)
+			}
+			return c.Status(fiber.StatusUnauthorized).SendString(
+				c.Message(MessageUnauthorized, utils.StatusMessage(fiber.StatusUnauthorized)))
+		}
+	}
+	return cfg
+}
+
+// validateKeys returns a validator of the keys. The hashes of the keys are
+// compared, so that the comparison takes the same time for all keys and
+// doesn't leak their lengths.
+func validateKeys(keys []string) func(c *fiber.Ctx, key string) (interface{}, error) {
+	hashes := make([][sha256.Size]byte, len(keys))
+	for i, key := range keys {
+		hashes[i] = sha256.Sum256([]byte(key))
+	}
+	return func(_ *fiber.Ctx, key string) (interface{}, error) {
+		hash := sha256.Sum256([]byte(key))
+		match := 0
+		for i := range hashes {
+			match |= subtle.ConstantTimeCompare(hashes[i][:], hash[:])
+		}
+		if match == 1 {
+			return key, nil
+		}
+		return nil, nil
+	}
+}
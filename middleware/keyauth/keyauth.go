@@ -0,0 +1,87 @@
+package keyauth
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// New creates a new middleware handler
+func New(config Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config)
+
+	// Create the extractor of the key
+	extract := extractor(cfg)
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		// Get the key
+		key := extract(c)
+		if key == "" {
+			return cfg.Unauthorized(c)
+		}
+
+		// Validate the key
+		principal, err := cfg.Validator(c, key)
+		if err != nil {
+			return err
+		}
+		if principal == nil {
+			return cfg.Unauthorized(c)
+		}
+
+		c.Locals(cfg.ContextKey, key)
+		c.Locals(cfg.ContextPrincipal, principal)
+		return c.Next()
+	}
+}
+
+// extractor returns a function that extracts the key from the request
+func extractor(cfg Config) func(c *fiber.Ctx) string {
+	selectors := strings.SplitN(cfg.KeyLookup, ":", 2)
+	if len(selectors) != 2 || selectors[1] == "" {
+		panic("[KEYAUTH] KeyLookup must in the form of <source>:<name>")
+	}
+	name := selectors[1]
+
+	switch selectors[0] {
+	case "header":
+		if !strings.EqualFold(name, fiber.HeaderAuthorization) {
+			return func(c *fiber.Ctx) string {
+				return c.Get(name)
+			}
+		}
+		// Strip the scheme from the Authorization header
+		prefix := cfg.AuthScheme + " "
+		return func(c *fiber.Ctx) string {
+			auth := c.Get(name)
+			if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+				return ""
+			}
+			return strings.TrimSpace(auth[len(prefix):])
+		}
+	case "query":
+		return func(c *fiber.Ctx) string {
+			return c.Query(name)
+		}
+	case "cookie":
+		return func(c *fiber.Ctx) string {
+			return c.Cookies(name)
+		}
+	case "form":
+		return func(c *fiber.Ctx) string {
+			return c.FormValue(name)
+		}
+	case "param":
+		return func(c *fiber.Ctx) string {
+			return c.Params(name)
+		}
+	}
+	panic("[KEYAUTH] KeyLookup source must be header, query, cookie, form or param")
+}
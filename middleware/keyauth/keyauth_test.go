@@ -0,0 +1,138 @@
+package keyauth
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_KeyAuth_Next
+func Test_KeyAuth_Next(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{
+		Keys: []string{"secret"},
+		Next: func(_ *fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// go test -run Test_KeyAuth_Keys
+func Test_KeyAuth_Keys(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{Keys: []string{"secret", "another-secret"}}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("principal").(string))
+	})
+
+	for _, tc := range []struct {
+		auth   string
+		status int
+		body   string
+	}{
+		{"Bearer secret", fiber.StatusOK, "secret"},
+		{"bearer another-secret", fiber.StatusOK, "another-secret"},
+		{"Bearer secre", fiber.StatusUnauthorized, "Unauthorized"},
+		{"Basic secret", fiber.StatusUnauthorized, "Unauthorized"},
+		{"", fiber.StatusUnauthorized, "Unauthorized"},
+	} {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		if tc.auth != "" {
+			req.Header.Set(fiber.HeaderAuthorization, tc.auth)
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.status, resp.StatusCode, tc.auth)
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.body, string(body))
+		if tc.status == fiber.StatusUnauthorized {
+			utils.AssertEqual(t, `Bearer realm="Restricted"`, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+		}
+	}
+}
+
+// go test -run Test_KeyAuth_Validator
+func Test_KeyAuth_Validator(t *testing.T) {
+	t.Parallel()
+	type client struct{ Name string }
+
+	app := fiber.New()
+	app.Use(New(Config{
+		KeyLookup: "query:api_key",
+		Validator: func(c *fiber.Ctx, key string) (interface{}, error) {
+			switch key {
+			case "down":
+				return nil, fiber.NewError(fiber.StatusServiceUnavailable, "db is down")
+			case "valid":
+				return &client{Name: "john"}, nil
+			}
+			return nil, nil
+		},
+	}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("principal").(*client).Name + ":" + c.Locals("token").(string))
+	})
+
+	for _, tc := range []struct {
+		url    string
+		status int
+		body   string
+	}{
+		{"/?api_key=valid", fiber.StatusOK, "john:valid"},
+		{"/?api_key=invalid", fiber.StatusUnauthorized, "Unauthorized"},
+		{"/?api_key=down", fiber.StatusServiceUnavailable, "db is down"},
+		{"/", fiber.StatusUnauthorized, "Unauthorized"},
+	} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tc.url, nil))
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.status, resp.StatusCode, tc.url)
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.body, string(body))
+		// the challenge is only sent for the Authorization header
+		utils.AssertEqual(t, "", resp.Header.Get(fiber.HeaderWWWAuthenticate))
+	}
+}
+
+// go test -run Test_KeyAuth_Lookup
+func Test_KeyAuth_Lookup(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		lookup string
+		header string
+		value  string
+	}{
+		{"header:X-API-Key", "X-API-Key", "secret"},
+		{"cookie:api_key", fiber.HeaderCookie, "api_key=secret"},
+	} {
+		app := fiber.New()
+		app.Use(New(Config{KeyLookup: tc.lookup, Keys: []string{"secret"}}))
+		app.Get("/", func(c *fiber.Ctx) error {
+			return c.SendString(c.Locals("token").(string))
+		})
+
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(tc.header, tc.value)
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode, tc.lookup)
+	}
+
+	defer func() {
+		utils.AssertEqual(t, "[KEYAUTH] Keys or Validator is required", recover())
+	}()
+	New(Config{})
+}
@@ -5,8 +5,12 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 )
 
+// MessageLimitReached is the key of the response message in fiber.Config.Messages
+const MessageLimitReached = "limiter.limit_reached"
+
 // Config defines the config for middleware.
 type Config struct {
 	// Next defines a function to skip this middleware when returned true.
@@ -19,6 +23,13 @@ type Config struct {
 	// Default: 5
 	Max int
 
+	// MaxFunc returns the max number of connections of the request, e.g. by
+	// the plan of the API key, instead of Max. Values <= 0 fall back to Max.
+	// Keys must always get the same max, since they share their hits.
+	//
+	// Optional. Default: nil
+	MaxFunc func(c *fiber.Ctx) int
+
 	// KeyGenerator allows you to generate custom keys, by default c.IP() is used
 	//
 	// Default: func(c *fiber.Ctx) string {
@@ -34,7 +45,8 @@ type Config struct {
 	// LimitReached is called when a request hits the limit
 	//
 	// Default: func(c *fiber.Ctx) error {
-	//   return c.SendStatus(fiber.StatusTooManyRequests)
+	//   return c.Status(fiber.StatusTooManyRequests).SendString(
+	//     c.Message(MessageLimitReached, utils.StatusMessage(fiber.StatusTooManyRequests)))
 	// }
 	LimitReached fiber.Handler
 
@@ -53,7 +65,8 @@ type Config struct {
 	// Default: an in memory store for this process only
 	Storage fiber.Storage
 
-	// LimiterMiddleware is the struct that implements a limiter middleware.
+	// LimiterMiddleware is the struct that implements a limiter middleware,
+	// FixedWindow{}, SlidingWindow{} or TokenBucket{}.
 	//
 	// Default: a new Fixed Window Rate Limiter
 	LimiterMiddleware LimiterHandler
@@ -76,7 +89,8 @@ var ConfigDefault = Config{
 		return c.IP()
 	},
 	LimitReached: func(c *fiber.Ctx) error {
-		return c.SendStatus(fiber.StatusTooManyRequests)
+		return c.Status(fiber.StatusTooManyRequests).SendString(
+			c.Message(MessageLimitReached, utils.StatusMessage(fiber.StatusTooManyRequests)))
 	},
 	SkipFailedRequests:     false,
 	SkipSuccessfulRequests: false,
@@ -126,3 +140,25 @@ func configDefault(config ...Config) Config {
 	}
 	return cfg
 }
+
+// KeyByHeader returns a KeyGenerator, which counts the requests by the value
+// of the header, e.g. an API key. Requests without the header are counted by
+// their IP.
+func KeyByHeader(header string) func(*fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		if key := c.Get(header); key != "" {
+			return header + ":" + key
+		}
+		return c.IP()
+	}
+}
+
+// maxOf returns the max number of connections of the request
+func (cfg *Config) maxOf(c *fiber.Ctx) int {
+	if cfg.MaxFunc != nil {
+		if max := cfg.MaxFunc(c); max > 0 {
+			return max
+		}
+	}
+	return cfg.Max
+}
@@ -1,6 +1,9 @@
 package limiter
 
 import (
+	"strconv"
+	"sync"
+
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -11,6 +14,16 @@ const (
 	xRateLimitReset     = "X-RateLimit-Reset"
 )
 
+// setHeaders sets the X-RateLimit-* headers of the response
+func setHeaders(c *fiber.Ctx, max, remaining int, resetInSec uint64) {
+	if remaining < 0 {
+		remaining = 0
+	}
+	c.Set(xRateLimitLimit, strconv.Itoa(max))
+	c.Set(xRateLimitRemaining, strconv.Itoa(remaining))
+	c.Set(xRateLimitReset, strconv.FormatUint(resetInSec, 10))
+}
+
 type LimiterHandler interface {
 	New(config Config) fiber.Handler
 }
@@ -20,6 +33,57 @@ func New(config ...Config) fiber.Handler {
 	// Set default config
 	cfg := configDefault(config...)
 
-	// Return the specified middleware handler.
-	return cfg.LimiterMiddleware.New(cfg)
+	// Create the specified middleware handler.
+	handler := cfg.LimiterMiddleware.New(cfg)
+
+	// Handlers of the routes with a rate limit declared by Route.RateLimit
+	var (
+		mux           sync.Mutex
+		routeHandlers = make(map[*fiber.RateLimit]fiber.Handler)
+	)
+
+	return func(c *fiber.Ctx) error {
+		route := c.MatchedRoute()
+		if route == nil || route.RateLimit == nil {
+			return handler(c)
+		}
+
+		mux.Lock()
+		routeHandler, ok := routeHandlers[route.RateLimit]
+		if !ok {
+			routeHandler = cfg.LimiterMiddleware.New(routeConfig(cfg, route))
+			routeHandlers[route.RateLimit] = routeHandler
+		}
+		mux.Unlock()
+
+		return routeHandler(c)
+	}
+}
+
+// routeConfig overrides the config with the rate limit declared by the route
+func routeConfig(cfg Config, route *fiber.Route) Config {
+	limit := route.RateLimit
+	if limit.Max > 0 {
+		cfg.Max = limit.Max
+		cfg.MaxFunc = nil
+	}
+	if int(limit.Expiration.Seconds()) > 0 {
+		cfg.Expiration = limit.Expiration
+	}
+	keyGenerator := cfg.KeyGenerator
+	if limit.KeyGenerator != nil {
+		keyGenerator = limit.KeyGenerator
+	}
+
+	// The routes share the storage, prefix the keys to count them separately.
+	// HEAD requests are counted together with GET requests.
+	method := route.Method
+	if method == fiber.MethodHead {
+		method = fiber.MethodGet
+	}
+	prefix := method + " " + route.Path + "|"
+	cfg.KeyGenerator = func(c *fiber.Ctx) string {
+		return prefix + keyGenerator(c)
+	}
+	return cfg
 }
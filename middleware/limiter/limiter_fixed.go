@@ -16,7 +16,6 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 	var (
 		// Limiter variables
 		mux        = &sync.RWMutex{}
-		max        = strconv.Itoa(cfg.Max)
 		timestamp  = uint64(time.Now().Unix())
 		expiration = uint64(cfg.Expiration.Seconds())
 	)
@@ -39,8 +38,9 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 			return c.Next()
 		}
 
-		// Get key from request
+		// Get key and max from request
 		key := cfg.KeyGenerator(c)
+		max := cfg.maxOf(c)
 
 		// Lock entry
 		mux.Lock()
@@ -67,7 +67,7 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 		resetInSec := e.exp - ts
 
 		// Set how many hits we have left
-		remaining := cfg.Max - e.currHits
+		remaining := max - e.currHits
 
 		// Update storage
 		manager.set(key, e, cfg.Expiration)
@@ -80,6 +80,7 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 			// Return response with Retry-After header
 			// https://tools.ietf.org/html/rfc6584
 			c.Set(fiber.HeaderRetryAfter, strconv.FormatUint(resetInSec, 10))
+			setHeaders(c, max, 0, resetInSec)
 
 			// Call LimitReached handler
 			return cfg.LimitReached(c)
@@ -103,9 +104,7 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 		}
 
 		// We can continue, update RateLimit headers
-		c.Set(xRateLimitLimit, max)
-		c.Set(xRateLimitRemaining, strconv.Itoa(remaining))
-		c.Set(xRateLimitReset, strconv.FormatUint(resetInSec, 10))
+		setHeaders(c, max, remaining, resetInSec)
 
 		return err
 	}
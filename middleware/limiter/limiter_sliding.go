@@ -16,7 +16,6 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 	var (
 		// Limiter variables
 		mux        = &sync.RWMutex{}
-		max        = strconv.Itoa(cfg.Max)
 		timestamp  = uint64(time.Now().Unix())
 		expiration = uint64(cfg.Expiration.Seconds())
 	)
@@ -39,8 +38,9 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 			return c.Next()
 		}
 
-		// Get key from request
+		// Get key and max from request
 		key := cfg.KeyGenerator(c)
+		max := cfg.maxOf(c)
 
 		// Lock entry
 		mux.Lock()
@@ -86,7 +86,7 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 		rate := int(float64(e.prevHits)*weight) + e.currHits
 
 		// Calculate how many hits can be made based on the current rate
-		remaining := cfg.Max - rate
+		remaining := max - rate
 
 		// Update storage. Garbage collect when the next window ends.
 		// |--------------------------|--------------------------|
@@ -110,6 +110,7 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 			// Return response with Retry-After header
 			// https://tools.ietf.org/html/rfc6584
 			c.Set(fiber.HeaderRetryAfter, strconv.FormatUint(resetInSec, 10))
+			setHeaders(c, max, 0, resetInSec)
 
 			// Call LimitReached handler
 			return cfg.LimitReached(c)
@@ -122,16 +123,18 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 		// Check for SkipFailedRequests and SkipSuccessfulRequests
 		if (cfg.SkipSuccessfulRequests && c.Response().StatusCode() < fiber.StatusBadRequest) ||
 			(cfg.SkipFailedRequests && c.Response().StatusCode() >= fiber.StatusBadRequest) {
+			// Lock entry
 			mux.Lock()
+			e = manager.get(key)
 			e.currHits--
 			remaining++
+			manager.set(key, e, time.Duration(resetInSec+expiration)*time.Second)
+			// Unlock entry
 			mux.Unlock()
 		}
 
 		// We can continue, update RateLimit headers
-		c.Set(xRateLimitLimit, max)
-		c.Set(xRateLimitRemaining, strconv.Itoa(remaining))
-		c.Set(xRateLimitReset, strconv.FormatUint(resetInSec, 10))
+		setHeaders(c, max, remaining, resetInSec)
 
 		return err
 	}
@@ -4,12 +4,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/internal/storage/memory"
+	"github.com/gofiber/fiber/v2/storage/memory"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/valyala/fasthttp"
 )
@@ -278,6 +279,266 @@ func Test_Limiter_Headers(t *testing.T) {
 	}
 }
 
+// go test -run Test_Limiter_Messages
+func Test_Limiter_Messages(t *testing.T) {
+	app := fiber.New(fiber.Config{
+		Messages: fiber.Messages{
+			"de": {MessageLimitReached: "Zu viele Anfragen"},
+		},
+	})
+
+	app.Use(New(Config{
+		Max:        1,
+		Expiration: 2 * time.Second,
+	}))
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("Hello tester!")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderAcceptLanguage, "de-DE,de;q=0.9")
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "Zu viele Anfragen", string(body))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	body, err = ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "Too Many Requests", string(body))
+}
+
+// go test -run Test_Limiter_TokenBucket -race -v
+func Test_Limiter_TokenBucket(t *testing.T) {
+	// Two instances sharing a storage enforce the limit together
+	storage := memory.New()
+	newApp := func() *fiber.App {
+		app := fiber.New()
+		app.Use(New(Config{
+			Max:               10,
+			Expiration:        2 * time.Second,
+			Storage:           storage,
+			LimiterMiddleware: TokenBucket{},
+		}))
+		app.Get("/", func(c *fiber.Ctx) error {
+			return c.SendString("Hello tester!")
+		})
+		return app
+	}
+	apps := []*fiber.App{newApp(), newApp()}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(app *fiber.App) {
+			defer wg.Done()
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			utils.AssertEqual(t, nil, err)
+			if resp.StatusCode == fiber.StatusOK {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			} else {
+				utils.AssertEqual(t, fiber.StatusTooManyRequests, resp.StatusCode)
+				utils.AssertEqual(t, "1", resp.Header.Get(fiber.HeaderRetryAfter))
+			}
+		}(apps[i%2])
+	}
+	wg.Wait()
+	utils.AssertEqual(t, 10, allowed)
+
+	// The bucket is refilled continuously, a token per 200ms
+	time.Sleep(250 * time.Millisecond)
+	resp, err := apps[0].Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, "10", resp.Header.Get(xRateLimitLimit))
+	utils.AssertEqual(t, "0", resp.Header.Get(xRateLimitRemaining))
+	utils.AssertEqual(t, "2", resp.Header.Get(xRateLimitReset))
+}
+
+// go test -run Test_Limiter_TokenBucket_LocalStorage -v
+func Test_Limiter_TokenBucket_LocalStorage(t *testing.T) {
+	app := fiber.New()
+	app.Use(New(Config{
+		Max:                    2,
+		Expiration:             time.Minute,
+		Storage:                &storageWithoutCAS{memory.New()},
+		LimiterMiddleware:      TokenBucket{},
+		SkipSuccessfulRequests: true,
+	}))
+	app.Get("/:status", func(c *fiber.Ctx) error {
+		status, _ := c.ParamsInt("status")
+		return c.SendStatus(status)
+	})
+
+	for _, tc := range []struct {
+		path string
+		code int
+	}{
+		// Successful requests return their token
+		{"/200", fiber.StatusOK},
+		{"/200", fiber.StatusOK},
+		{"/200", fiber.StatusOK},
+		{"/400", fiber.StatusBadRequest},
+		{"/400", fiber.StatusBadRequest},
+		{"/400", fiber.StatusTooManyRequests},
+		{"/200", fiber.StatusTooManyRequests},
+	} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tc.path, nil))
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.code, resp.StatusCode, tc.path)
+	}
+}
+
+// storageWithoutCAS hides the CompareAndSwap method of the storage
+type storageWithoutCAS struct {
+	fiber.Storage
+}
+
+// go test -run Test_Limiter_RouteRateLimit -v
+func Test_Limiter_RouteRateLimit(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(New(Config{
+		Max:        3,
+		Expiration: 2 * time.Second,
+	}))
+
+	handler := func(c *fiber.Ctx) error {
+		return c.SendString("Hello tester!")
+	}
+	app.Get("/", handler)
+	app.Get("/login", handler)
+	app.RateLimit(1, time.Minute)
+	app.Group("/api").Post("/users", handler)
+	app.RateLimit(2, time.Minute, func(c *fiber.Ctx) string {
+		return c.Query("token")
+	})
+
+	request := func(method, target string) *http.Response {
+		resp, err := app.Test(httptest.NewRequest(method, target, nil))
+		utils.AssertEqual(t, nil, err)
+		return resp
+	}
+
+	resp := request(fiber.MethodGet, "/login")
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, "1", resp.Header.Get("X-RateLimit-Limit"))
+	// HEAD requests share the limit with GET requests
+	utils.AssertEqual(t, fiber.StatusTooManyRequests, request(fiber.MethodHead, "/login").StatusCode)
+
+	// The global limit is counted separately
+	resp = request(fiber.MethodGet, "/")
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, "3", resp.Header.Get("X-RateLimit-Limit"))
+
+	// Custom key generator of the route
+	utils.AssertEqual(t, fiber.StatusOK, request(fiber.MethodPost, "/api/users?token=1").StatusCode)
+	utils.AssertEqual(t, fiber.StatusOK, request(fiber.MethodPost, "/api/users?token=1").StatusCode)
+	utils.AssertEqual(t, fiber.StatusTooManyRequests, request(fiber.MethodPost, "/api/users?token=1").StatusCode)
+	utils.AssertEqual(t, fiber.StatusOK, request(fiber.MethodPost, "/api/users?token=2").StatusCode)
+}
+
+// go test -run Test_Limiter_MaxFunc
+func Test_Limiter_MaxFunc(t *testing.T) {
+	t.Parallel()
+	for _, middleware := range []LimiterHandler{FixedWindow{}, SlidingWindow{}, TokenBucket{}} {
+		app := fiber.New()
+		app.Use(New(Config{
+			Max:          1,
+			Expiration:   time.Minute,
+			KeyGenerator: KeyByHeader("X-API-Key"),
+			MaxFunc: func(c *fiber.Ctx) int {
+				if c.Get("X-API-Key") == "premium" {
+					return 3
+				}
+				return 0
+			},
+			LimiterMiddleware: middleware,
+		}))
+		app.Get("/", func(c *fiber.Ctx) error {
+			return c.SendString("Hello tester!")
+		})
+
+		request := func(key string) *http.Response {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if key != "" {
+				req.Header.Set("X-API-Key", key)
+			}
+			resp, err := app.Test(req)
+			utils.AssertEqual(t, nil, err)
+			return resp
+		}
+
+		for i := 2; i >= 0; i-- {
+			resp := request("premium")
+			utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+			utils.AssertEqual(t, "3", resp.Header.Get("X-RateLimit-Limit"))
+			utils.AssertEqual(t, strconv.Itoa(i), resp.Header.Get("X-RateLimit-Remaining"))
+		}
+
+		// The headers are set on rejected requests too
+		resp := request("premium")
+		utils.AssertEqual(t, fiber.StatusTooManyRequests, resp.StatusCode)
+		utils.AssertEqual(t, "3", resp.Header.Get("X-RateLimit-Limit"))
+		utils.AssertEqual(t, "0", resp.Header.Get("X-RateLimit-Remaining"))
+		utils.AssertEqual(t, true, resp.Header.Get("X-RateLimit-Reset") != "")
+
+		// Other keys and requests without a key fall back to Max
+		utils.AssertEqual(t, fiber.StatusOK, request("basic").StatusCode)
+		utils.AssertEqual(t, fiber.StatusTooManyRequests, request("basic").StatusCode)
+		utils.AssertEqual(t, fiber.StatusOK, request("").StatusCode)
+		utils.AssertEqual(t, fiber.StatusTooManyRequests, request("").StatusCode)
+	}
+}
+
+// go test -run Test_Sliding_Window_Skip_Successful_Requests
+func Test_Sliding_Window_Skip_Successful_Requests(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Max:                    1,
+		Expiration:             time.Minute,
+		SkipSuccessfulRequests: true,
+		Storage:                memory.New(),
+		LimiterMiddleware:      SlidingWindow{},
+	}))
+	app.Get("/:status", func(c *fiber.Ctx) error {
+		if c.Params("status") == "fail" {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for i := 0; i < 3; i++ {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ok", nil))
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/ok", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusTooManyRequests, resp.StatusCode)
+}
+
 // go test -v -run=^$ -bench=Benchmark_Limiter -benchmem -count=4
 func Benchmark_Limiter(b *testing.B) {
 	app := fiber.New()
@@ -0,0 +1,194 @@
+package limiter
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/storage/memory"
+)
+
+// CASStorage is a Storage with an atomic compare-and-swap operation, e.g.
+// implemented by a Lua script in Redis or a transaction in etcd. The TokenBucket
+// limiter coordinates through it, so that the limits are enforced cluster-wide
+// instead of per instance.
+type CASStorage = fiber.CASStorage
+
+// maxCASRetries is the number of attempts to update a bucket under contention
+const maxCASRetries = 16
+
+var errContention = errors.New("limiter: too many concurrent updates of the bucket")
+
+// TokenBucket is a token bucket limiter, which keeps its state in the Storage
+// and updates it with compare-and-swap operations. If the Storage implements
+// CASStorage, all instances sharing it enforce the limit together. Other
+// storages are updated under a lock of the instance, which isn't safe for
+// sharing them between instances.
+//
+// Each bucket holds up to Max tokens and is refilled with Max tokens per Expiration.
+// The clocks of the instances should be synchronized.
+type TokenBucket struct{}
+
+// New creates a new token bucket middleware handler
+func (TokenBucket) New(cfg Config) fiber.Handler {
+	storage := casStorage(cfg.Storage)
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		// Get key and max from request
+		key := cfg.KeyGenerator(c)
+		max := cfg.maxOf(c)
+		// Tokens per nanosecond
+		rate := float64(max) / float64(cfg.Expiration)
+
+		// Take a token
+		var tokens float64
+		allowed, err := updateBucket(storage, key, max, cfg.Expiration, rate, func(b *bucket) bool {
+			tokens = b.tokens
+			if b.tokens < 1 {
+				return false
+			}
+			b.tokens--
+			tokens = b.tokens
+			return true
+		})
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			// Return response with Retry-After header
+			// https://tools.ietf.org/html/rfc6584
+			c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(secondsUntil(1-tokens, rate), 10))
+			setHeaders(c, max, 0, uint64(secondsUntil(float64(max)-tokens, rate)))
+
+			// Call LimitReached handler
+			return cfg.LimitReached(c)
+		}
+
+		// Continue stack for reaching c.Response().StatusCode()
+		// Store err for returning
+		err = c.Next()
+
+		// Check for SkipFailedRequests and SkipSuccessfulRequests
+		if (cfg.SkipSuccessfulRequests && c.Response().StatusCode() < fiber.StatusBadRequest) ||
+			(cfg.SkipFailedRequests && c.Response().StatusCode() >= fiber.StatusBadRequest) {
+			// Return the token
+			if _, uerr := updateBucket(storage, key, max, cfg.Expiration, rate, func(b *bucket) bool {
+				b.tokens = math.Min(b.tokens+1, float64(max))
+				tokens = b.tokens
+				return true
+			}); uerr != nil && err == nil {
+				err = uerr
+			}
+		}
+
+		// We can continue, update RateLimit headers
+		setHeaders(c, max, int(tokens), uint64(secondsUntil(float64(max)-tokens, rate)))
+
+		return err
+	}
+}
+
+// bucket is the state of a token bucket
+type bucket struct {
+	tokens float64
+	// last refill in unix nanoseconds
+	last int64
+}
+
+// updateBucket refills the bucket and applies fn to it. The bucket is stored
+// if fn returns true, the update is retried if the bucket has been changed
+// concurrently.
+func updateBucket(storage CASStorage, key string, max int, exp time.Duration, rate float64, fn func(b *bucket) bool) (bool, error) {
+	for i := 0; i < maxCASRetries; i++ {
+		old, err := storage.Get(key)
+		if err != nil {
+			return false, err
+		}
+
+		if len(old) == 0 {
+			old = nil
+		}
+
+		// Missing and malformed buckets are full
+		now := time.Now().UnixNano()
+		b := bucket{tokens: float64(max), last: now}
+		if len(old) == 16 {
+			b.tokens = math.Float64frombits(binary.BigEndian.Uint64(old))
+			b.last = int64(binary.BigEndian.Uint64(old[8:]))
+		}
+		if elapsed := now - b.last; elapsed > 0 {
+			b.tokens = math.Min(b.tokens+float64(elapsed)*rate, float64(max))
+			b.last = now
+		}
+
+		if !fn(&b) {
+			return false, nil
+		}
+
+		val := make([]byte, 16)
+		binary.BigEndian.PutUint64(val, math.Float64bits(b.tokens))
+		binary.BigEndian.PutUint64(val[8:], uint64(b.last))
+		// The bucket is full again after the expiration
+		ok, err := storage.CompareAndSwap(key, old, val, exp)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, errContention
+}
+
+// secondsUntil returns the seconds until the bucket has been refilled with the tokens
+func secondsUntil(tokens, rate float64) int64 {
+	if tokens <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(tokens / rate / float64(time.Second)))
+}
+
+// casStorage returns the storage as CASStorage, storages without the
+// operation are guarded by a local lock
+func casStorage(storage fiber.Storage) CASStorage {
+	if storage == nil {
+		return memory.New()
+	}
+	if cas, ok := storage.(CASStorage); ok {
+		return cas
+	}
+	return &localCASStorage{Storage: storage}
+}
+
+// localCASStorage implements the compare-and-swap operation for a single instance
+type localCASStorage struct {
+	fiber.Storage
+	mux sync.Mutex
+}
+
+func (s *localCASStorage) CompareAndSwap(key string, old, val []byte, exp time.Duration) (bool, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	cur, err := s.Storage.Get(key)
+	if err != nil {
+		return false, err
+	}
+	if len(cur) == 0 {
+		cur = nil
+	}
+	if (cur == nil) != (old == nil) || string(cur) != string(old) {
+		return false, nil
+	}
+	return true, s.Storage.Set(key, val, exp)
+}
@@ -41,6 +41,7 @@ const (
 	TagBytesSent         = "bytesSent"
 	TagBytesReceived     = "bytesReceived"
 	TagRoute             = "route"
+	TagRequestID         = "requestid"
 	TagError             = "error"
 	// DEPRECATED: Use TagReqHeader instead
 	TagHeader     = "header:"
@@ -228,6 +229,8 @@ func New(config ...Config) fiber.Handler {
 				return appendInt(buf, len(c.Response().Body()))
 			case TagRoute:
 				return buf.WriteString(c.Route().Path)
+			case TagRequestID:
+				return buf.WriteString(c.RequestID())
 			case TagStatus:
 				if cfg.enableColors {
 					return buf.WriteString(fmt.Sprintf("%s %3d %s", statusColor(c.Response().StatusCode(), colors), c.Response().StatusCode(), colors.Reset))
@@ -317,6 +317,26 @@ func Test_Response_Header(t *testing.T) {
 	utils.AssertEqual(t, "Hello fiber!", buf.String())
 }
 
+// go test -run Test_Logger_RequestID
+func Test_Logger_RequestID(t *testing.T) {
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+
+	app := fiber.New()
+	app.Use(requestid.New())
+	app.Use(New(Config{
+		Format: "${requestid}",
+		Output: buf,
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "abc")
+	_, err := app.Test(req)
+
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "abc", buf.String())
+}
+
 // go test -run Test_Req_Header
 func Test_Req_Header(t *testing.T) {
 	buf := bytebufferpool.Get()
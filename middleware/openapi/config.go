@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// Title of the API
+	//
+	// Optional. Default: "Fiber API"
+	Title string
+
+	// Version of the API
+	//
+	// Optional. Default: "1.0.0"
+	Version string
+
+	// Description of the API
+	//
+	// Optional. Default: ""
+	Description string
+
+	// Servers are the base URLs of the API, e.g. "https://api.example.com"
+	//
+	// Optional. Default: nil
+	Servers []string
+
+	// Filter defines a function to leave out routes of the document when
+	// returned false, e.g. internal routes.
+	//
+	// Optional. Default: nil
+	Filter func(route fiber.Route) bool
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:    nil,
+	Title:   "Fiber API",
+	Version: "1.0.0",
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Title == "" {
+		cfg.Title = ConfigDefault.Title
+	}
+	if cfg.Version == "" {
+		cfg.Version = ConfigDefault.Version
+	}
+	return cfg
+}
@@ -0,0 +1,278 @@
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Version is the OpenAPI version of the generated documents
+const Version = "3.1.0"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components *Components         `json:"components,omitempty"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is a base URL of the API
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem holds the operations of a path by their lower case method
+type PathItem map[string]*Operation
+
+// Operation describes a route
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []*Parameter        `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a path, query or header parameter of an operation
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody is the request body of an operation
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema and examples of a request or response body
+type MediaType struct {
+	Schema   *Schema             `json:"schema,omitempty"`
+	Examples map[string]*Example `json:"examples,omitempty"`
+}
+
+// Example is an example value of a body
+type Example struct {
+	Summary string      `json:"summary,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
+}
+
+// Components holds the schemas of the named types
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Schema is a JSON schema of a type
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// paramPattern matches the params of a route path, e.g. ":id<int>?" or "*"
+var paramPattern = regexp.MustCompile(`:[^/\-.?<]+(<[^>]*>)?\??|[*+]`)
+
+// Generate generates the OpenAPI document of the routes of the app. The
+// request and response types of the routes are declared by App.Input and
+// App.Output, the metadata "summary", "description" and "tags" of the routes
+// is used for the operations, see App.Meta.
+func Generate(app *fiber.App, config ...Config) *Document {
+	cfg := configDefault(config...)
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       cfg.Title,
+			Version:     cfg.Version,
+			Description: cfg.Description,
+		},
+		Paths: make(map[string]PathItem),
+	}
+	for _, url := range cfg.Servers {
+		doc.Servers = append(doc.Servers, Server{URL: url})
+	}
+
+	gen := &generator{schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == "USE" || (cfg.Filter != nil && !cfg.Filter(route)) {
+			continue
+		}
+		path, method := openAPIPath(route), utils.ToLower(route.Method)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		if _, ok := item[method]; !ok {
+			item[method] = gen.operation(route)
+		}
+	}
+	// The HEAD routes of GET routes aren't documented separately
+	for _, item := range doc.Paths {
+		if _, ok := item["get"]; ok {
+			delete(item, "head")
+		}
+	}
+
+	if len(gen.schemas) > 0 {
+		doc.Components = &Components{Schemas: gen.schemas}
+	}
+	return doc
+}
+
+// openAPIPath converts the path of the route to the template of OpenAPI,
+// e.g. "/users/:id<int>" to "/users/{id}"
+func openAPIPath(route fiber.Route) string {
+	i := 0
+	return paramPattern.ReplaceAllStringFunc(route.Path, func(param string) string {
+		if i >= len(route.Params) {
+			return param
+		}
+		i++
+		return "{" + route.Params[i-1] + "}"
+	})
+}
+
+// operation describes the route
+func (gen *generator) operation(route fiber.Route) *Operation {
+	op := &Operation{
+		OperationID: route.Name,
+		Responses:   make(map[string]Response),
+	}
+	if summary, ok := route.Metadata["summary"].(string); ok {
+		op.Summary = summary
+	}
+	if description, ok := route.Metadata["description"].(string); ok {
+		op.Description = description
+	}
+	switch tags := route.Metadata["tags"].(type) {
+	case string:
+		op.Tags = []string{tags}
+	case []string:
+		op.Tags = tags
+	}
+
+	// Path params are always documented, the input declares their types
+	params := make(map[string]*Parameter)
+	for _, name := range route.Params {
+		param := &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		params["path "+name] = param
+		op.Parameters = append(op.Parameters, param)
+	}
+
+	var body *Schema
+	if route.Input != nil {
+		var inputParams []*Parameter
+		inputParams, body = gen.input(route.Input)
+		for _, param := range inputParams {
+			if existing, ok := params[param.In+" "+param.Name]; ok {
+				existing.Schema = param.Schema
+				continue
+			}
+			if param.In == "path" {
+				// The route doesn't have the param
+				continue
+			}
+			op.Parameters = append(op.Parameters, param)
+		}
+	}
+	if body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: body}},
+		}
+	}
+
+	if route.Output != nil {
+		op.Responses["200"] = Response{
+			Description: utils.StatusMessage(fiber.StatusOK),
+			Content:     map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: gen.schema(route.Output)}},
+		}
+	}
+	for _, example := range route.Examples {
+		gen.example(op, example)
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = Response{Description: utils.StatusMessage(fiber.StatusOK)}
+	}
+	return op
+}
+
+// example adds the example to the request body and the response of its status
+func (gen *generator) example(op *Operation, example fiber.RouteExample) {
+	name := example.Name
+	if name == "" {
+		name = "default"
+	}
+	if example.Request != nil && op.RequestBody != nil {
+		media := op.RequestBody.Content[fiber.MIMEApplicationJSON]
+		media.Examples = addExample(media.Examples, name, example.Summary, example.Request)
+		op.RequestBody.Content[fiber.MIMEApplicationJSON] = media
+	}
+
+	status := example.Status
+	if status == 0 {
+		status = fiber.StatusOK
+	}
+	code := strconv.Itoa(status)
+	resp, ok := op.Responses[code]
+	if !ok {
+		resp = Response{Description: utils.StatusMessage(status)}
+	}
+	if example.Response != nil {
+		contentType := example.ContentType
+		if contentType == "" {
+			contentType = fiber.MIMEApplicationJSON
+			if _, ok := example.Response.(string); ok {
+				contentType = fiber.MIMETextPlain
+			}
+		}
+		contentType = strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+		if resp.Content == nil {
+			resp.Content = make(map[string]MediaType)
+		}
+		media := resp.Content[contentType]
+		media.Examples = addExample(media.Examples, name, example.Summary, example.Response)
+		resp.Content[contentType] = media
+	}
+	op.Responses[code] = resp
+}
+
+func addExample(examples map[string]*Example, name, summary string, value interface{}) map[string]*Example {
+	if examples == nil {
+		examples = make(map[string]*Example)
+	}
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	examples[name] = &Example{Summary: summary, Value: value}
+	return examples
+}
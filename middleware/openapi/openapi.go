@@ -0,0 +1,155 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// MIMEApplicationYAML is the media type of the YAML document
+const MIMEApplicationYAML = "application/yaml"
+
+// New creates a new middleware handler, which serves the OpenAPI document of
+// the app, e.g. on /docs:
+//
+//	app.Get("/docs", openapi.New(openapi.Config{Title: "Users API"}))
+//
+// The document is served as JSON, or as YAML if the client accepts
+// application/yaml. It's generated on the first request, when all routes have
+// been registered, and leaves out the route of the handler itself.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	var (
+		once      sync.Once
+		jsonDoc   []byte
+		yamlDoc   []byte
+		encodeErr error
+	)
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		once.Do(func() {
+			docsPath := c.Route().Path
+			filter := cfg.Filter
+			docsCfg := cfg
+			docsCfg.Filter = func(route fiber.Route) bool {
+				return route.Path != docsPath && (filter == nil || filter(route))
+			}
+			jsonDoc, encodeErr = json.Marshal(Generate(c.App(), docsCfg))
+			if encodeErr == nil {
+				yamlDoc, encodeErr = jsonToYAML(jsonDoc)
+			}
+		})
+		if encodeErr != nil {
+			return encodeErr
+		}
+
+		if c.Accepts(fiber.MIMEApplicationJSON, MIMEApplicationYAML) == MIMEApplicationYAML {
+			c.Set(fiber.HeaderContentType, MIMEApplicationYAML)
+			return c.Send(yamlDoc)
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Send(jsonDoc)
+	}
+}
+
+// YAML encodes the document as YAML
+func (doc *Document) YAML() ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(data)
+}
+
+// jsonToYAML converts the JSON document to YAML, the keys of the objects are
+// sorted and strings are quoted like in JSON
+func jsonToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writeYAML(&buf, value, 0)
+	return buf.Bytes(), nil
+}
+
+// writeYAML writes the value in block style, which starts on a new line for
+// non-empty objects and lists
+func writeYAML(buf *bytes.Buffer, value interface{}, indent int) {
+	prefix := strings.Repeat("  ", indent)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		if indent > 0 {
+			buf.WriteByte('\n')
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			buf.WriteString(prefix)
+			buf.WriteString(yamlKey(k))
+			buf.WriteByte(':')
+			writeYAML(buf, v[k], indent+1)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteByte('\n')
+		for _, item := range v {
+			buf.WriteString(prefix)
+			buf.WriteByte('-')
+			writeYAML(buf, item, indent+1)
+		}
+	case string:
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.Quote(v))
+		buf.WriteByte('\n')
+	case nil:
+		buf.WriteString(" null\n")
+	default:
+		// Numbers and booleans
+		fmt.Fprintf(buf, " %v\n", v)
+	}
+}
+
+// yamlKey quotes the key unless it's a plain word, keys like "200" or "no"
+// would be decoded as numbers and booleans otherwise
+func yamlKey(key string) string {
+	switch utils.ToLower(key) {
+	case "", "true", "false", "null", "yes", "no", "on", "off", "y", "n", "~":
+		return strconv.Quote(key)
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		isLetter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+		if !isLetter && (i == 0 || !(c >= '0' && c <= '9' || c == '-' || c == '.')) {
+			return strconv.Quote(key)
+		}
+	}
+	return key
+}
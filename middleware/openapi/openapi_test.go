@@ -0,0 +1,177 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type address struct {
+	City string `json:"city"`
+}
+
+type user struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name" validate:"required"`
+	Email     *string   `json:"email,omitempty"`
+	Tags      []string  `json:"tags"`
+	Address   address   `json:"address"`
+	Friends   []*user   `json:"friends"`
+	CreatedAt time.Time `json:"created_at"`
+	password  string
+}
+
+type createUser struct {
+	OrgID   uint   `params:"org"`
+	Notify  bool   `query:"notify,required"`
+	TraceID string `reqHeader:"X-Trace-Id"`
+	Name    string `json:"name" validate:"required"`
+	Admin   bool   `json:"-"`
+}
+
+func newApp() *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		return c.Next()
+	})
+	app.Get("/users/:id<int>", func(c *fiber.Ctx) error {
+		return c.JSON(user{})
+	}).Name("getUser")
+	app.Output(user{}).Meta("summary", "Get a user").Meta("tags", "users").
+		Example(fiber.RouteExample{Name: "not-found", Status: fiber.StatusNotFound, Response: "Not Found"})
+	api := app.Group("/orgs/:org")
+	api.Post("/users", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+	app.Input(createUser{}).Output(user{})
+	app.Get("/files/*", func(c *fiber.Ctx) error {
+		return nil
+	})
+	return app
+}
+
+// go test -run Test_Generate
+func Test_Generate(t *testing.T) {
+	t.Parallel()
+	app := newApp()
+
+	doc := Generate(app, Config{Title: "Users API", Servers: []string{"https://api.example.com"}})
+	utils.AssertEqual(t, Version, doc.OpenAPI)
+	utils.AssertEqual(t, Info{Title: "Users API", Version: "1.0.0"}, doc.Info)
+	utils.AssertEqual(t, []Server{{URL: "https://api.example.com"}}, doc.Servers)
+	utils.AssertEqual(t, 3, len(doc.Paths))
+
+	// The HEAD route of the GET route is left out
+	getUser := doc.Paths["/users/{id}"]
+	utils.AssertEqual(t, 1, len(getUser))
+	op := getUser["get"]
+	utils.AssertEqual(t, "getUser", op.OperationID)
+	utils.AssertEqual(t, "Get a user", op.Summary)
+	utils.AssertEqual(t, []string{"users"}, op.Tags)
+	utils.AssertEqual(t, []*Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}}, op.Parameters)
+	utils.AssertEqual(t, "#/components/schemas/user", op.Responses["200"].Content[fiber.MIMEApplicationJSON].Schema.Ref)
+	utils.AssertEqual(t, "Not Found", op.Responses["404"].Content[fiber.MIMETextPlain].Examples["not-found"].Value)
+
+	op = doc.Paths["/orgs/{org}/users"]["post"]
+	utils.AssertEqual(t, 3, len(op.Parameters))
+	min := 0.0
+	utils.AssertEqual(t, &Parameter{Name: "org", In: "path", Required: true, Schema: &Schema{Type: "integer", Minimum: &min}}, op.Parameters[0])
+	utils.AssertEqual(t, &Parameter{Name: "notify", In: "query", Required: true, Schema: &Schema{Type: "boolean"}}, op.Parameters[1])
+	utils.AssertEqual(t, &Parameter{Name: "X-Trace-Id", In: "header", Schema: &Schema{Type: "string"}}, op.Parameters[2])
+	body := op.RequestBody.Content[fiber.MIMEApplicationJSON].Schema
+	utils.AssertEqual(t, &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"name": {Type: "string"}},
+		Required:   []string{"name"},
+	}, body)
+
+	_, ok := doc.Paths["/files/{*1}"]
+	utils.AssertEqual(t, true, ok)
+
+	utils.AssertEqual(t, 2, len(doc.Components.Schemas))
+	schema := doc.Components.Schemas["user"]
+	utils.AssertEqual(t, []string{"name"}, schema.Required)
+	utils.AssertEqual(t, 7, len(schema.Properties))
+	utils.AssertEqual(t, &Schema{Type: "integer", Format: "int64"}, schema.Properties["id"])
+	utils.AssertEqual(t, &Schema{Type: "string"}, schema.Properties["email"])
+	utils.AssertEqual(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, schema.Properties["tags"])
+	utils.AssertEqual(t, &Schema{Ref: "#/components/schemas/address"}, schema.Properties["address"])
+	utils.AssertEqual(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/user"}}, schema.Properties["friends"])
+	utils.AssertEqual(t, &Schema{Type: "string", Format: "date-time"}, schema.Properties["created_at"])
+
+	// Routes can be left out
+	doc = Generate(app, Config{Filter: func(route fiber.Route) bool {
+		return !strings.HasPrefix(route.Path, "/files")
+	}})
+	utils.AssertEqual(t, 2, len(doc.Paths))
+}
+
+// go test -run Test_OpenAPI_Handler
+func Test_OpenAPI_Handler(t *testing.T) {
+	t.Parallel()
+	app := newApp()
+	app.Get("/docs", New())
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/docs", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+	var doc Document
+	utils.AssertEqual(t, nil, json.NewDecoder(resp.Body).Decode(&doc))
+	utils.AssertEqual(t, "Fiber API", doc.Info.Title)
+	// The docs route itself is left out
+	utils.AssertEqual(t, 3, len(doc.Paths))
+
+	req := httptest.NewRequest(fiber.MethodGet, "/docs", nil)
+	req.Header.Set(fiber.HeaderAccept, MIMEApplicationYAML)
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, MIMEApplicationYAML, resp.Header.Get(fiber.HeaderContentType))
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, strings.Contains(string(body), "openapi: \"3.1.0\"\n"))
+	utils.AssertEqual(t, true, strings.Contains(string(body), "\n  \"/users/{id}\":\n    get:\n"))
+	utils.AssertEqual(t, true, strings.Contains(string(body), "\n        \"200\":\n"))
+}
+
+// go test -run Test_Document_YAML
+func Test_Document_YAML(t *testing.T) {
+	t.Parallel()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: "API: \"v1\"", Version: "1"},
+		Paths: map[string]PathItem{"/": {"get": &Operation{
+			Tags:       []string{"a", "b"},
+			Parameters: []*Parameter{{Name: "no", In: "query", Required: true, Schema: &Schema{}}},
+			Responses:  map[string]Response{"200": {Description: "OK"}},
+		}}},
+	}
+	data, err := doc.YAML()
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `info:
+  title: "API: \"v1\""
+  version: "1"
+openapi: "3.1.0"
+paths:
+  "/":
+    get:
+      parameters:
+        -
+          in: "query"
+          name: "no"
+          required: true
+          schema: {}
+      responses:
+        "200":
+          description: "OK"
+      tags:
+        - "a"
+        - "b"
+`, string(data))
+}
@@ -0,0 +1,224 @@
+package openapi
+
+import (
+	"encoding"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// invalidNameChars matches the characters which aren't allowed in the names of components
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// paramSources are the tags of the parsers by the location of their parameters
+var paramSources = []struct{ tag, in string }{
+	{"params", "path"},
+	{"query", "query"},
+	{"reqHeader", "header"},
+}
+
+// generator generates the schemas of the types, named struct types are
+// added to the components
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// input returns the parameters and the body schema of a request type, fields
+// with a params, query or reqHeader tag are parameters and the other fields
+// belong to the body. The body is nil if there are no body fields.
+func (gen *generator) input(t reflect.Type) (params []*Parameter, body *Schema) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, gen.schema(t)
+	}
+
+	body = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	gen.inputFields(t, &params, body)
+	if len(body.Properties) == 0 {
+		body = nil
+	}
+	return params, body
+}
+
+func (gen *generator) inputFields(t reflect.Type, params *[]*Parameter, body *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		isParam := false
+		for _, source := range paramSources {
+			tag, ok := field.Tag.Lookup(source.tag)
+			if !ok || tag == "-" {
+				continue
+			}
+			name, opts := splitTag(tag)
+			if name == "" {
+				name = field.Name
+			}
+			isParam = true
+			*params = append(*params, &Parameter{
+				Name:     name,
+				In:       source.in,
+				Required: source.in == "path" || hasOption(opts, "required") || isRequired(field),
+				Schema:   gen.schema(field.Type),
+			})
+		}
+		if isParam {
+			continue
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		// Fields of embedded structs are promoted
+		if field.Anonymous && ft.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			gen.inputFields(ft, params, body)
+			continue
+		}
+		gen.property(body, field)
+	}
+}
+
+// schema returns the schema of the type, named struct types are referenced
+func (gen *generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case reflect.PtrTo(t).Implements(textMarshalerType):
+		// Types like net.IP or fiber.ByteSize are encoded as strings
+		return &Schema{Type: "string"}
+	case t.Kind() == reflect.Struct && t.Name() == "":
+		return gen.object(t)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		min := 0.0
+		return &Schema{Type: "integer", Minimum: &min}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: gen.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: gen.schema(t.Elem())}
+	case reflect.Struct:
+		return &Schema{Ref: "#/components/schemas/" + gen.component(t)}
+	}
+	// Interfaces may have any value
+	return &Schema{}
+}
+
+// component adds the schema of the named struct type to the components and
+// returns its name
+func (gen *generator) component(t reflect.Type) string {
+	if name, ok := gen.names[t]; ok {
+		return name
+	}
+	name := invalidNameChars.ReplaceAllString(t.Name(), "_")
+	// Types of different packages may have the same name
+	if _, taken := gen.schemas[name]; taken {
+		name = invalidNameChars.ReplaceAllString(t.String(), "_")
+	}
+	for i := 2; ; i++ {
+		if _, taken := gen.schemas[name]; !taken {
+			break
+		}
+		name = invalidNameChars.ReplaceAllString(t.String(), "_") + "_" + strconv.Itoa(i)
+	}
+
+	// The name is reserved before the fields are added for recursive types
+	schema := &Schema{}
+	gen.names[t] = name
+	gen.schemas[name] = schema
+	*schema = *gen.object(t)
+	return name
+}
+
+// object returns the schema of the fields of the struct type
+func (gen *generator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	gen.properties(schema, t)
+	return schema
+}
+
+func (gen *generator) properties(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		// Fields of embedded structs are promoted
+		if field.Anonymous && ft.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			gen.properties(schema, ft)
+			continue
+		}
+		gen.property(schema, field)
+	}
+}
+
+// property adds the field to the properties of the object schema by its JSON name
+func (gen *generator) property(schema *Schema, field reflect.StructField) {
+	tag := field.Tag.Get("json")
+	if field.PkgPath != "" || tag == "-" {
+		return
+	}
+	name, _ := splitTag(tag)
+	if name == "" {
+		name = field.Name
+	}
+	schema.Properties[name] = gen.schema(field.Type)
+	if isRequired(field) {
+		schema.Required = append(schema.Required, name)
+	}
+}
+
+// splitTag splits the tag into the name and the comma separated options
+func splitTag(tag string) (name, opts string) {
+	if i := strings.IndexByte(tag, ','); i != -1 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
+
+// hasOption reports if the comma separated options contain the option
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isRequired reports if the field is required by a validate tag like
+// `validate:"required,min=1"`
+func isRequired(field reflect.StructField) bool {
+	return hasOption(field.Tag.Get("validate"), "required")
+}
@@ -20,9 +20,37 @@ type Config struct {
 	// which are used in a round-robin manner.
 	// i.e.: "https://foobar.com, http://www.foobar.com"
 	//
-	// Required
+	// Servers, which fail 3 requests in a row, are ejected for 10 seconds.
+	//
+	// Required, unless Discovery or Upstreams is set
 	Servers []string
 
+	// Upstreams is the pool of the upstream servers instead of Servers, e.g.
+	// to configure the weights of the servers or to read their statistics.
+	//
+	// Optional. Default: nil
+	Upstreams *fiber.UpstreamPool
+
+	// Discovery resolves the upstream servers, e.g. by DNS, instead of
+	// Servers. They are resolved again when their TTL expires, the previous
+	// servers are kept if resolving fails.
+	//
+	// Optional. Default: nil
+	Discovery Resolver
+
+	// HealthCheck reports if an upstream server is healthy. It's called for
+	// each server every HealthCheckInterval, unhealthy servers are ejected
+	// until a following check succeeds. All servers are used if all of them
+	// are ejected.
+	//
+	// Optional. Default: nil
+	HealthCheck func(addr string) bool
+
+	// HealthCheckInterval is the interval of the health checks
+	//
+	// Optional. Default: 10 seconds
+	HealthCheckInterval time.Duration
+
 	// ModifyRequest allows you to alter the request
 	//
 	// Optional. Default: nil
@@ -57,6 +85,8 @@ var ConfigDefault = Config{
 	ModifyRequest:  nil,
 	ModifyResponse: nil,
 	Timeout:        fasthttp.DefaultLBClientTimeout,
+
+	HealthCheckInterval: 10 * time.Second,
 }
 
 // configDefault function to set default values
@@ -74,8 +104,12 @@ func configDefault(config ...Config) Config {
 		cfg.Timeout = ConfigDefault.Timeout
 	}
 
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = ConfigDefault.HealthCheckInterval
+	}
+
 	// Set default values
-	if len(cfg.Servers) == 0 {
+	if len(cfg.Servers) == 0 && cfg.Discovery == nil && cfg.Upstreams == nil {
 		panic("Servers cannot be empty")
 	}
 	return cfg
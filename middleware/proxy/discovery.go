@@ -0,0 +1,147 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// resolveTimeout is the timeout of resolving the upstream servers
+	resolveTimeout = 5 * time.Second
+	// minResolveInterval limits the rate of resolving the servers, e.g. for DNS records with a TTL of 0
+	minResolveInterval = time.Second
+	// retryResolveInterval is the interval of resolving the servers after an error
+	retryResolveInterval = 5 * time.Second
+)
+
+// Resolver resolves the addresses of the upstream servers, e.g. by DNS
+type Resolver interface {
+	// Resolve returns the host:port addresses of the upstream servers and how
+	// long they may be used until they are resolved again, e.g. the TTL of
+	// the DNS records
+	Resolve(ctx context.Context) (addrs []string, ttl time.Duration, err error)
+}
+
+// DNS returns a Resolver of the A and AAAA records of host, which are used
+// with the port in a round-robin manner. Since the resolver of the standard
+// library doesn't expose the TTL of the records, they are resolved every ttl.
+func DNS(host, port string, ttl time.Duration) Resolver {
+	return &dnsResolver{
+		host:   host,
+		port:   port,
+		ttl:    ttl,
+		lookup: net.DefaultResolver.LookupHost,
+	}
+}
+
+type dnsResolver struct {
+	host, port string
+	ttl        time.Duration
+	lookup     func(ctx context.Context, host string) ([]string, error)
+}
+
+func (r *dnsResolver) Resolve(ctx context.Context) ([]string, time.Duration, error) {
+	hosts, err := r.lookup(ctx, r.host)
+	if err != nil {
+		return nil, 0, err
+	}
+	addrs := make([]string, len(hosts))
+	for i, host := range hosts {
+		addrs[i] = net.JoinHostPort(host, r.port)
+	}
+	// Sort the addresses, so that the servers don't change if only the order of the records does
+	sort.Strings(addrs)
+	return addrs, r.ttl, nil
+}
+
+// SRV returns a Resolver of the SRV records of the service, e.g.
+// SRV("http", "tcp", "api.internal", 30*time.Second) looks up
+// _http._tcp.api.internal. The targets with the lowest priority are used.
+// Since the resolver of the standard library doesn't expose the TTL of the
+// records, they are resolved every ttl.
+func SRV(service, proto, name string, ttl time.Duration) Resolver {
+	return &srvResolver{
+		service: service,
+		proto:   proto,
+		name:    name,
+		ttl:     ttl,
+		lookup:  net.DefaultResolver.LookupSRV,
+	}
+}
+
+type srvResolver struct {
+	service, proto, name string
+	ttl                  time.Duration
+	lookup               func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+func (r *srvResolver) Resolve(ctx context.Context) ([]string, time.Duration, error) {
+	_, records, err := r.lookup(ctx, r.service, r.proto, r.name)
+	if err != nil {
+		return nil, 0, err
+	}
+	var addrs []string
+	for _, record := range records {
+		// The records are sorted by priority
+		if record.Priority != records[0].Priority {
+			break
+		}
+		addrs = append(addrs, net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port))))
+	}
+	sort.Strings(addrs)
+	return addrs, r.ttl, nil
+}
+
+// HTTPHealthCheck returns a HealthCheck, which reports a server as healthy
+// if a GET request of path responds with a 2xx status within timeout.
+func HTTPHealthCheck(path string, timeout time.Duration) func(addr string) bool {
+	return func(addr string) bool {
+		req := fasthttp.AcquireRequest()
+		resp := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseRequest(req)
+		defer fasthttp.ReleaseResponse(resp)
+
+		req.SetRequestURI("http://" + addr + path)
+		if err := client.DoTimeout(req, resp, timeout); err != nil {
+			return false
+		}
+		status := resp.StatusCode()
+		return status >= 200 && status < 300
+	}
+}
+
+// resolve sets the resolved servers of the pool, it returns when to resolve them again
+func resolve(pool *fiber.UpstreamPool, resolver Resolver) time.Duration {
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+
+	addrs, ttl, err := resolver.Resolve(ctx)
+	if err != nil {
+		// Keep the previous servers
+		return retryResolveInterval
+	}
+	servers := make([]fiber.UpstreamServer, len(addrs))
+	for i, addr := range addrs {
+		servers[i] = fiber.UpstreamServer{Addr: addr}
+	}
+	pool.Set(servers...)
+	if ttl < minResolveInterval {
+		ttl = minResolveInterval
+	}
+	return ttl
+}
+
+// watchDiscovery resolves the servers of the pool whenever their TTL expires
+func watchDiscovery(pool *fiber.UpstreamPool, resolver Resolver, interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		interval = resolve(pool, resolver)
+	}
+}
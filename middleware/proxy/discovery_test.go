@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type staticResolver struct {
+	mutex sync.Mutex
+	addrs []string
+	err   error
+}
+
+func (r *staticResolver) Resolve(context.Context) ([]string, time.Duration, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.addrs, time.Second, r.err
+}
+
+// go test -run Test_Proxy_Discovery
+func Test_Proxy_Discovery(t *testing.T) {
+	t.Parallel()
+
+	_, addr := createProxyTestServer(func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusTeapot)
+	}, t)
+
+	resolver := &staticResolver{}
+	app := fiber.New()
+	app.Use(Balancer(Config{Discovery: resolver}))
+
+	// No servers yet
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	resolver.mutex.Lock()
+	resolver.addrs = []string{addr}
+	resolver.mutex.Unlock()
+	time.Sleep(1500 * time.Millisecond)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), 2000)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusTeapot, resp.StatusCode)
+	// Only 2xx responses are healthy
+	utils.AssertEqual(t, false, HTTPHealthCheck("/", time.Second)(addr))
+
+	// Failures keep the servers
+	resolver.mutex.Lock()
+	resolver.addrs, resolver.err = nil, errors.New("no such host")
+	resolver.mutex.Unlock()
+	time.Sleep(1500 * time.Millisecond)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), 2000)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusTeapot, resp.StatusCode)
+}
+
+// go test -run Test_Proxy_Resolvers
+func Test_Proxy_Resolvers(t *testing.T) {
+	t.Parallel()
+
+	dns := &dnsResolver{
+		host: "api.internal",
+		port: "8080",
+		ttl:  time.Minute,
+		lookup: func(_ context.Context, host string) ([]string, error) {
+			utils.AssertEqual(t, "api.internal", host)
+			return []string{"10.0.0.2", "::1", "10.0.0.1"}, nil
+		},
+	}
+	addrs, ttl, err := dns.Resolve(context.Background())
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, []string{"10.0.0.1:8080", "10.0.0.2:8080", "[::1]:8080"}, addrs)
+	utils.AssertEqual(t, time.Minute, ttl)
+
+	srv := &srvResolver{
+		service: "http",
+		proto:   "tcp",
+		name:    "api.internal",
+		ttl:     time.Minute,
+		lookup: func(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+			return "_http._tcp.api.internal.", []*net.SRV{
+				{Target: "b.api.internal.", Port: 8080, Priority: 1},
+				{Target: "a.api.internal.", Port: 8081, Priority: 1},
+				{Target: "backup.api.internal.", Port: 8080, Priority: 2},
+			}, nil
+		},
+	}
+	addrs, _, err = srv.Resolve(context.Background())
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, []string{"a.api.internal:8081", "b.api.internal:8080"}, addrs)
+}
+
+// go test -run Test_Proxy_Upstreams
+func Test_Proxy_Upstreams(t *testing.T) {
+	t.Parallel()
+
+	_, addr := createProxyTestServer(func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusTeapot)
+	}, t)
+
+	pool := fiber.NewUpstreamPool(fiber.UpstreamPoolConfig{
+		Servers: []fiber.UpstreamServer{{Addr: addr}},
+	})
+	app := fiber.New()
+	app.Use(Balancer(Config{Upstreams: pool}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), 2000)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusTeapot, resp.StatusCode)
+	utils.AssertEqual(t, uint64(1), pool.Stats()[0].Requests)
+}
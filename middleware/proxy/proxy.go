@@ -24,34 +24,46 @@ func Balancer(config Config) fiber.Handler {
 	cfg := configDefault(config)
 
 	// Load balanced client
-	var lbc fasthttp.LBClient
-	// Set timeout
-	lbc.Timeout = cfg.Timeout
-
-	// Scheme must be provided, falls back to http
-	// TODO add https support
-	for _, server := range cfg.Servers {
-		if !strings.HasPrefix(server, "http") {
-			server = "http://" + server
-		}
-
-		u, err := url.Parse(server)
-		if err != nil {
-			panic(err)
-		}
-
-		client := &fasthttp.HostClient{
-			NoDefaultUserAgentHeader: true,
-			DisablePathNormalizing:   true,
-			Addr:                     u.Host,
+	lbc := cfg.Upstreams
+	if lbc == nil {
+		// Scheme must be provided, falls back to http
+		// TODO add https support
+		servers := make([]fiber.UpstreamServer, 0, len(cfg.Servers))
+		for _, server := range cfg.Servers {
+			if !strings.HasPrefix(server, "http") {
+				server = "http://" + server
+			}
 
-			ReadBufferSize:  config.ReadBufferSize,
-			WriteBufferSize: config.WriteBufferSize,
+			u, err := url.Parse(server)
+			if err != nil {
+				panic(err)
+			}
 
-			TLSConfig: config.TlsConfig,
+			servers = append(servers, fiber.UpstreamServer{Addr: u.Host})
 		}
 
-		lbc.Clients = append(lbc.Clients, client)
+		lbc = fiber.NewUpstreamPool(fiber.UpstreamPoolConfig{
+			Servers:             servers,
+			HealthCheck:         cfg.HealthCheck,
+			HealthCheckInterval: cfg.HealthCheckInterval,
+			NewClient: func(addr string) *fasthttp.HostClient {
+				return &fasthttp.HostClient{
+					NoDefaultUserAgentHeader: true,
+					DisablePathNormalizing:   true,
+					Addr:                     addr,
+
+					ReadBufferSize:  cfg.ReadBufferSize,
+					WriteBufferSize: cfg.WriteBufferSize,
+
+					TLSConfig: cfg.TlsConfig,
+				}
+			},
+		})
+	}
+
+	if cfg.Discovery != nil {
+		// The servers are resolved before the first request
+		go watchDiscovery(lbc, cfg.Discovery, resolve(lbc, cfg.Discovery))
 	}
 
 	// Return new handler
@@ -78,7 +90,7 @@ func Balancer(config Config) fiber.Handler {
 		req.SetRequestURI(utils.UnsafeString(req.RequestURI()))
 
 		// Forward request
-		if err = lbc.Do(req, res); err != nil {
+		if err = lbc.Do(req, res, cfg.Timeout); err != nil {
 			return err
 		}
 
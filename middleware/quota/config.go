@@ -0,0 +1,137 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Period is the duration after which the quota is renewed
+type Period int
+
+const (
+	// Daily quotas are renewed at midnight
+	Daily Period = iota
+	// Monthly quotas are renewed on the first day of the month
+	Monthly
+)
+
+// MessageLimitReached is the key of the response message in fiber.Config.Messages
+const MessageLimitReached = "quota.limit_reached"
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// Limit is the number of requests a key may issue per period.
+	//
+	// Optional. Default: 1000
+	Limit int64
+
+	// Limits returns the quota of the given key, e.g. from the plan of a
+	// tenant. A negative value means unlimited, zero falls back to Limit.
+	//
+	// Optional. Default: nil
+	Limits func(c *fiber.Ctx, key string) int64
+
+	// Period after which the quota is renewed.
+	//
+	// Optional. Default: Daily
+	Period Period
+
+	// Location is the time zone the periods are calculated in.
+	//
+	// Optional. Default: time.UTC
+	Location *time.Location
+
+	// IdentityKey is the key of the authenticated identity in Locals, e.g. the
+	// key set by a custom auth middleware. The IdentityKeys of the basicauth,
+	// keyauth and jwt middleware are looked up after it, see Identity.
+	//
+	// Optional. Default: "username"
+	IdentityKey string
+
+	// KeyGenerator allows you to generate custom keys.
+	//
+	// Optional. Default: the Identity of the request with IdentityKey,
+	// or c.IP() for anonymous requests
+	KeyGenerator func(*fiber.Ctx) string
+
+	// LimitReached is called when a request exceeds the quota.
+	//
+	// Optional. Default: func(c *fiber.Ctx) error {
+	//   return c.Status(fiber.StatusTooManyRequests).SendString(
+	//     c.Message(MessageLimitReached, utils.StatusMessage(fiber.StatusTooManyRequests)))
+	// }
+	LimitReached fiber.Handler
+
+	// OnExhausted is called once per period, when the quota of a key has been used up,
+	// e.g. to notify the customer.
+	//
+	// Optional. Default: nil
+	OnExhausted func(usage Usage)
+
+	// When set to true, requests with StatusCode >= 400 won't be counted.
+	//
+	// Optional. Default: false
+	SkipFailedRequests bool
+
+	// ContextKey is the key to store the Usage of the request in Locals.
+	//
+	// Optional. Default: "quota"
+	ContextKey string
+
+	// Storage is used to store the counters. With a shared storage, the
+	// quota is enforced across processes, but concurrent requests of the same
+	// key may be counted once, since the storage has no atomic increment.
+	//
+	// Optional. Default: an in memory storage for this process only
+	Storage fiber.Storage
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:        nil,
+	Limit:       1000,
+	Period:      Daily,
+	Location:    time.UTC,
+	IdentityKey: "username",
+	LimitReached: func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusTooManyRequests).SendString(
+			c.Message(MessageLimitReached, utils.StatusMessage(fiber.StatusTooManyRequests)))
+	},
+	ContextKey: "quota",
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Limit <= 0 {
+		cfg.Limit = ConfigDefault.Limit
+	}
+	if cfg.Location == nil {
+		cfg.Location = ConfigDefault.Location
+	}
+	if cfg.IdentityKey == "" {
+		cfg.IdentityKey = ConfigDefault.IdentityKey
+	}
+	if cfg.LimitReached == nil {
+		cfg.LimitReached = ConfigDefault.LimitReached
+	}
+	if cfg.ContextKey == "" {
+		cfg.ContextKey = ConfigDefault.ContextKey
+	}
+	return cfg
+}
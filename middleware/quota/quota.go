@@ -0,0 +1,197 @@
+package quota
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/storage/memory"
+)
+
+const (
+	// X-Quota-* headers
+	xQuotaLimit     = "X-Quota-Limit"
+	xQuotaRemaining = "X-Quota-Remaining"
+	xQuotaReset     = "X-Quota-Reset"
+)
+
+// Usage is the quota usage of a key in the current period
+type Usage struct {
+	Key   string    `json:"key"`
+	Limit int64     `json:"limit"` // Negative if unlimited
+	Used  int64     `json:"used"`
+	Reset time.Time `json:"reset"` // Start of the next period
+}
+
+// Remaining returns the number of requests left in the current period
+func (u Usage) Remaining() int64 {
+	if u.Limit < 0 {
+		return -1
+	}
+	if u.Used >= u.Limit {
+		return 0
+	}
+	return u.Limit - u.Used
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	storage := cfg.Storage
+	if storage == nil {
+		storage = memory.New()
+	}
+
+	var mux sync.Mutex
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		key := identity(c, cfg)
+		usage := Usage{
+			Key:   key,
+			Limit: cfg.Limit,
+		}
+		if cfg.Limits != nil {
+			if limit := cfg.Limits(c, key); limit != 0 {
+				usage.Limit = limit
+			}
+		}
+
+		// Unlimited keys are not counted
+		if usage.Limit < 0 {
+			c.Locals(cfg.ContextKey, usage)
+			return c.Next()
+		}
+
+		now := time.Now().In(cfg.Location)
+		start, reset := periodBounds(cfg.Period, now)
+		usage.Reset = reset
+		storageKey := "quota:" + key + ":" + start.Format("2006-01-02")
+
+		mux.Lock()
+		used, err := load(storage, storageKey)
+		if err != nil {
+			mux.Unlock()
+			return err
+		}
+		exceeded := used >= usage.Limit
+		if !exceeded {
+			used++
+			if err = store(storage, storageKey, used, reset.Sub(now)); err != nil {
+				mux.Unlock()
+				return err
+			}
+		}
+		mux.Unlock()
+
+		usage.Used = used
+		setHeaders(c, usage, now)
+		c.Locals(cfg.ContextKey, usage)
+
+		if exceeded {
+			return cfg.LimitReached(c)
+		}
+		if used == usage.Limit && cfg.OnExhausted != nil {
+			cfg.OnExhausted(usage)
+		}
+
+		err = c.Next()
+
+		if cfg.SkipFailedRequests && c.Response().StatusCode() >= fiber.StatusBadRequest {
+			mux.Lock()
+			if used, lerr := load(storage, storageKey); lerr == nil && used > 0 {
+				_ = store(storage, storageKey, used-1, reset.Sub(time.Now().In(cfg.Location)))
+				usage.Used = used - 1
+			}
+			mux.Unlock()
+			setHeaders(c, usage, now)
+		}
+
+		return err
+	}
+}
+
+// identity returns the key of the request
+func identity(c *fiber.Ctx, cfg Config) string {
+	if cfg.KeyGenerator != nil {
+		return cfg.KeyGenerator(c)
+	}
+	if id := Identity(c, cfg.IdentityKey); id != "" {
+		return id
+	}
+	return c.IP()
+}
+
+// IdentityKeys are the keys of Locals, which Identity looks up after the given
+// ones: the ContextUsername of the basicauth middleware, the ContextPrincipal
+// of the keyauth middleware and the ContextKey of the jwt middleware.
+var IdentityKeys = []string{"username", "principal", "user"}
+
+// Identity returns the authenticated identity of the request, which is stored
+// in Locals under the first of the keys or IdentityKeys with a value. Strings,
+// values with a Subject() string method like the *jwt.Token and fmt.Stringers
+// are supported. It returns "" for anonymous requests.
+func Identity(c *fiber.Ctx, keys ...string) string {
+	for _, key := range keys {
+		if id := identityOf(c.Locals(key)); id != "" {
+			return id
+		}
+	}
+	for _, key := range IdentityKeys {
+		if id := identityOf(c.Locals(key)); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// identityOf returns the identity of a value in Locals
+func identityOf(v interface{}) string {
+	switch id := v.(type) {
+	case string:
+		return id
+	case interface{ Subject() string }:
+		return id.Subject()
+	case fmt.Stringer:
+		return id.String()
+	}
+	return ""
+}
+
+// periodBounds returns the start of the current and the next period
+func periodBounds(period Period, now time.Time) (start, next time.Time) {
+	year, month, day := now.Date()
+	if period == Monthly {
+		start = time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+	start = time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(0, 0, 1)
+}
+
+func setHeaders(c *fiber.Ctx, usage Usage, now time.Time) {
+	c.Set(xQuotaLimit, strconv.FormatInt(usage.Limit, 10))
+	c.Set(xQuotaRemaining, strconv.FormatInt(usage.Remaining(), 10))
+	c.Set(xQuotaReset, strconv.FormatInt(int64(usage.Reset.Sub(now).Seconds()), 10))
+}
+
+func load(storage fiber.Storage, key string) (int64, error) {
+	raw, err := storage.Get(key)
+	if err != nil || len(raw) == 0 {
+		return 0, err
+	}
+	return strconv.ParseInt(string(raw), 10, 64)
+}
+
+func store(storage fiber.Storage, key string, used int64, exp time.Duration) error {
+	return storage.Set(key, []byte(strconv.FormatInt(used, 10)), exp)
+}
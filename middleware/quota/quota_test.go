@@ -0,0 +1,218 @@
+package quota
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/keyauth"
+	"github.com/gofiber/fiber/v2/storage/memory"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Quota
+func Test_Quota(t *testing.T) {
+	t.Parallel()
+	var exhausted []Usage
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("username", c.Get("X-User"))
+		return c.Next()
+	})
+	app.Use(New(Config{
+		Limit: 2,
+		Limits: func(c *fiber.Ctx, key string) int64 {
+			switch key {
+			case "enterprise":
+				return -1
+			case "pro":
+				return 3
+			}
+			return 0
+		},
+		OnExhausted: func(usage Usage) {
+			exhausted = append(exhausted, usage)
+		},
+	}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		usage := c.Locals("quota").(Usage)
+		return c.SendString(usage.Key)
+	})
+
+	request := func(user string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set("X-User", user)
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err)
+		return resp.StatusCode, resp.Header.Get(xQuotaRemaining)
+	}
+
+	code, remaining := request("john")
+	utils.AssertEqual(t, fiber.StatusOK, code)
+	utils.AssertEqual(t, "1", remaining)
+	code, remaining = request("john")
+	utils.AssertEqual(t, fiber.StatusOK, code)
+	utils.AssertEqual(t, "0", remaining)
+	code, _ = request("john")
+	utils.AssertEqual(t, fiber.StatusTooManyRequests, code)
+
+	// OnExhausted is called once
+	utils.AssertEqual(t, 1, len(exhausted))
+	utils.AssertEqual(t, "john", exhausted[0].Key)
+	utils.AssertEqual(t, int64(2), exhausted[0].Used)
+
+	// Quota of the plan
+	for i := 0; i < 3; i++ {
+		code, _ = request("pro")
+		utils.AssertEqual(t, fiber.StatusOK, code)
+	}
+	code, _ = request("pro")
+	utils.AssertEqual(t, fiber.StatusTooManyRequests, code)
+
+	// Unlimited
+	for i := 0; i < 5; i++ {
+		code, remaining = request("enterprise")
+		utils.AssertEqual(t, fiber.StatusOK, code)
+		utils.AssertEqual(t, "", remaining)
+	}
+}
+
+// go test -run Test_Quota_SkipFailedRequests
+func Test_Quota_SkipFailedRequests(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Limit:              1,
+		Period:             Monthly,
+		SkipFailedRequests: true,
+		Storage:            memory.New(),
+	}))
+	app.Get("/:status", func(c *fiber.Ctx) error {
+		status, err := c.ParamsInt("status")
+		if err != nil {
+			return err
+		}
+		return c.SendStatus(status)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/500", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+	utils.AssertEqual(t, "1", resp.Header.Get(xQuotaRemaining))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/200", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/200", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusTooManyRequests, resp.StatusCode)
+}
+
+type testTenant struct {
+	name string
+}
+
+func (t testTenant) String() string { return t.name }
+
+// go test -run Test_Quota_KeyAuth
+func Test_Quota_KeyAuth(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(keyauth.New(keyauth.Config{
+		Validator: func(_ *fiber.Ctx, key string) (interface{}, error) {
+			switch key {
+			case "key-a", "key-b":
+				return testTenant{name: "acme"}, nil
+			}
+			return nil, nil
+		},
+	}))
+	app.Use(New(Config{Limit: 2}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("quota").(Usage).Key)
+	})
+
+	request := func(key string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+key)
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		return resp.StatusCode, string(body)
+	}
+
+	// The keys of a tenant share its quota
+	code, body := request("key-a")
+	utils.AssertEqual(t, fiber.StatusOK, code)
+	utils.AssertEqual(t, "acme", body)
+	code, body = request("key-b")
+	utils.AssertEqual(t, fiber.StatusOK, code)
+	utils.AssertEqual(t, "acme", body)
+	code, _ = request("key-a")
+	utils.AssertEqual(t, fiber.StatusTooManyRequests, code)
+}
+
+type testToken struct {
+	sub string
+}
+
+func (t *testToken) Subject() string { return t.sub }
+
+// go test -run Test_Identity
+func Test_Identity(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	utils.AssertEqual(t, "", Identity(c))
+
+	// The subject of a token, like the *jwt.Token
+	c.Locals("user", &testToken{sub: "john"})
+	utils.AssertEqual(t, "john", Identity(c))
+
+	// The principal of keyauth comes first
+	c.Locals("principal", testTenant{name: "acme"})
+	utils.AssertEqual(t, "acme", Identity(c))
+
+	// The given keys come first, values of other types are skipped
+	c.Locals("tenant", 42)
+	utils.AssertEqual(t, "acme", Identity(c, "tenant"))
+	c.Locals("tenant", "initech")
+	utils.AssertEqual(t, "initech", Identity(c, "tenant"))
+}
+
+// go test -run Test_PeriodBounds
+func Test_PeriodBounds(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2021, time.December, 31, 15, 4, 5, 0, time.UTC)
+
+	start, next := periodBounds(Daily, now)
+	utils.AssertEqual(t, time.Date(2021, time.December, 31, 0, 0, 0, 0, time.UTC), start)
+	utils.AssertEqual(t, time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC), next)
+
+	start, next = periodBounds(Monthly, now)
+	utils.AssertEqual(t, time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC), start)
+	utils.AssertEqual(t, time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC), next)
+}
+
+// go test -run Test_Quota_Next
+func Test_Quota_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Next: func(_ *fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
+	utils.AssertEqual(t, "", resp.Header.Get(xQuotaLimit))
+}
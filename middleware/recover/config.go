@@ -20,6 +20,19 @@ type Config struct {
 	//
 	// Optional. Default: defaultStackTraceHandler
 	StackTraceHandler func(c *fiber.Ctx, e interface{})
+
+	// OnPanic is called with the recovered value and the stack trace of the
+	// panic, e.g. to report it to Sentry or Rollbar.
+	//
+	// Optional. Default: nil
+	OnPanic func(c *fiber.Ctx, e interface{}, stack []byte)
+
+	// ExposeStackTrace adds the stack trace to the message of the error for
+	// the error handler, which the default one sends to the client. Only
+	// enable it in development.
+	//
+	// Optional. Default: false
+	ExposeStackTrace bool
 }
 
 var defaultStackTraceBufLen = 1024
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"runtime/debug"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -33,11 +34,27 @@ func New(config ...Config) fiber.Handler {
 					cfg.StackTraceHandler(c, r)
 				}
 
+				var stack []byte
+				if cfg.OnPanic != nil || cfg.ExposeStackTrace {
+					stack = debug.Stack()
+				}
+				if cfg.OnPanic != nil {
+					cfg.OnPanic(c, r, stack)
+				}
+
 				var ok bool
 				if err, ok = r.(error); !ok {
 					// Set error that will call the global error handler
 					err = fmt.Errorf("%v", r)
 				}
+
+				if cfg.ExposeStackTrace {
+					code := fiber.StatusInternalServerError
+					if e, ok := err.(*fiber.Error); ok {
+						code = e.Code
+					}
+					err = fiber.NewError(code, fmt.Sprintf("panic: %v\n\n%s", r, stack))
+				}
 			}
 		}()
 
@@ -1,7 +1,9 @@
 package recover
 
 import (
+	"io/ioutil"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
@@ -56,3 +58,51 @@ func Test_Recover_EnableStackTrace(t *testing.T) {
 	utils.AssertEqual(t, nil, err)
 	utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
 }
+
+// go test -run Test_Recover_OnPanic
+func Test_Recover_OnPanic(t *testing.T) {
+	var (
+		value interface{}
+		stack []byte
+	)
+	app := fiber.New()
+	app.Use(New(Config{
+		OnPanic: func(c *fiber.Ctx, e interface{}, s []byte) {
+			value, stack = e, s
+		},
+	}))
+
+	app.Get("/panic", func(c *fiber.Ctx) error {
+		panic("Hi, I'm an error!")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/panic", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+	utils.AssertEqual(t, "Hi, I'm an error!", value)
+	utils.AssertEqual(t, true, strings.Contains(string(stack), "recover.Test_Recover_OnPanic"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "Hi, I'm an error!", string(body))
+}
+
+// go test -run Test_Recover_ExposeStackTrace
+func Test_Recover_ExposeStackTrace(t *testing.T) {
+	app := fiber.New()
+	app.Use(New(Config{
+		ExposeStackTrace: true,
+	}))
+
+	app.Get("/panic", func(c *fiber.Ctx) error {
+		panic(fiber.ErrTeapot)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/panic", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusTeapot, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, strings.HasPrefix(string(body), "panic: I'm a teapot\n\ngoroutine "))
+	utils.AssertEqual(t, true, strings.Contains(string(body), "recover.Test_Recover_ExposeStackTrace"))
+}
@@ -2,7 +2,6 @@ package requestid
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/utils"
 )
 
 // Config defines the config for middleware.
@@ -18,8 +17,10 @@ type Config struct {
 	Header string
 
 	// Generator defines a function to generate the unique identifier.
+	// By default the IDs are generated by c.NewID in the format of
+	// fiber.Config.IDFormat, so that all IDs of the app are consistent.
 	//
-	// Optional. Default: utils.UUID
+	// Optional. Default: nil
 	Generator func() string
 
 	// ContextKey defines the key used when storing the request ID in
@@ -33,7 +34,7 @@ type Config struct {
 var ConfigDefault = Config{
 	Next:       nil,
 	Header:     fiber.HeaderXRequestID,
-	Generator:  utils.UUID,
+	Generator:  nil,
 	ContextKey: "requestid",
 }
 
@@ -51,9 +52,6 @@ func configDefault(config ...Config) Config {
 	if cfg.Header == "" {
 		cfg.Header = ConfigDefault.Header
 	}
-	if cfg.Generator == nil {
-		cfg.Generator = ConfigDefault.Generator
-	}
 	if cfg.ContextKey == "" {
 		cfg.ContextKey = ConfigDefault.ContextKey
 	}
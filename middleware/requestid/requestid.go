@@ -16,13 +16,21 @@ func New(config ...Config) fiber.Handler {
 			return c.Next()
 		}
 		// Get id from request, else we generate one
-		rid := c.Get(cfg.Header, cfg.Generator())
+		rid := c.Get(cfg.Header)
+		if rid == "" {
+			if cfg.Generator != nil {
+				rid = cfg.Generator()
+			} else {
+				rid = c.NewID()
+			}
+		}
 
 		// Set new id to response header
 		c.Set(cfg.Header, rid)
 
-		// Add the request ID to locals
+		// Add the request ID to locals and the ctx, see Ctx.RequestID
 		c.Locals(cfg.ContextKey, rid)
+		c.SetRequestID(rid)
 
 		// Continue stack
 		return c.Next()
@@ -73,3 +73,43 @@ func Test_RequestID_Locals(t *testing.T) {
 	utils.AssertEqual(t, nil, err)
 	utils.AssertEqual(t, reqId, ctxVal)
 }
+
+// go test -run Test_RequestID_Ctx
+func Test_RequestID_Ctx(t *testing.T) {
+	generated := 0
+	app := fiber.New()
+	app.Use(New(Config{
+		Generator: func() string {
+			generated++
+			return "generated-id"
+		},
+	}))
+
+	var rid string
+	app.Get("/", func(c *fiber.Ctx) error {
+		rid = c.RequestID()
+		return nil
+	})
+
+	_, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "generated-id", rid)
+
+	// The generator isn't called for requests with an ID
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "upstream-id")
+	_, err = app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "upstream-id", rid)
+	utils.AssertEqual(t, 1, generated)
+}
+
+// go test -run Test_RequestID_IDFormat
+func Test_RequestID_IDFormat(t *testing.T) {
+	app := fiber.New(fiber.Config{IDFormat: fiber.IDFormatXID})
+	app.Use(New())
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, 20, len(resp.Header.Get(fiber.HeaderXRequestID)))
+}
@@ -11,10 +11,16 @@ import (
 
 // Config defines the config for middleware.
 type Config struct {
-	// Allowed session duration
+	// Allowed session duration, i.e. the idle timeout, since the expiration
+	// is renewed when the session is saved
 	// Optional. Default value 24 * time.Hour
 	Expiration time.Duration
 
+	// AbsoluteExpiration is the max lifetime of a session since its creation,
+	// regardless of its activity. Regenerate keeps the creation time.
+	// Optional. Default value 0, i.e. no max lifetime
+	AbsoluteExpiration time.Duration
+
 	// Storage interface to store the session data
 	// Optional. Default value memory.New()
 	Storage fiber.Storage
@@ -11,6 +11,10 @@ import (
 	"github.com/valyala/fasthttp"
 )
 
+// createdKey is the key of the creation time of the session in its data, see
+// Config.AbsoluteExpiration
+const createdKey = "__session_created"
+
 type Session struct {
 	id         string        // session id
 	fresh      bool          // if new session
@@ -144,6 +148,22 @@ func (s *Session) Save() error {
 		s.exp = s.config.Expiration
 	}
 
+	// The session mustn't outlive its absolute expiration
+	if s.config.AbsoluteExpiration > 0 {
+		created := s.CreatedAt()
+		if created.IsZero() {
+			created = time.Now()
+			s.Set(createdKey, created.Unix())
+		}
+		remaining := time.Until(created.Add(s.config.AbsoluteExpiration))
+		if remaining <= 0 {
+			return s.Destroy()
+		}
+		if remaining < s.exp {
+			s.exp = remaining
+		}
+	}
+
 	// Update client cookie
 	s.setSession()
 
@@ -177,7 +197,22 @@ func (s *Session) Keys() []string {
 	if s.data == nil {
 		return []string{}
 	}
-	return s.data.Keys()
+	keys := s.data.Keys()
+	for i, key := range keys {
+		if key == createdKey {
+			return append(keys[:i], keys[i+1:]...)
+		}
+	}
+	return keys
+}
+
+// CreatedAt returns the creation time of the session, it's the zero time
+// for sessions which haven't been saved yet
+func (s *Session) CreatedAt() time.Time {
+	if created, ok := s.Get(createdKey).(int64); ok {
+		return time.Unix(created, 0)
+	}
+	return time.Time{}
 }
 
 // SetExpiry sets a specific expiration for this session
@@ -0,0 +1,13 @@
+//go:build go1.18
+// +build go1.18
+
+package session
+
+// GetAs returns the value of the key as T. It reports false if the session
+// has no value of the key or it isn't a T:
+//
+//	userID, ok := session.GetAs[int](sess, "user_id")
+func GetAs[T any](s *Session, key string) (T, bool) {
+	value, ok := s.Get(key).(T)
+	return value, ok
+}
@@ -0,0 +1,34 @@
+//go:build go1.18
+// +build go1.18
+
+package session
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Session_GetAs
+func Test_Session_GetAs(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	store := New()
+	ctx := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(ctx)
+
+	sess, err := store.Get(ctx)
+	utils.AssertEqual(t, nil, err)
+	sess.Set("user_id", 42)
+
+	id, ok := GetAs[int](sess, "user_id")
+	utils.AssertEqual(t, true, ok)
+	utils.AssertEqual(t, 42, id)
+
+	_, ok = GetAs[string](sess, "user_id")
+	utils.AssertEqual(t, false, ok)
+	_, ok = GetAs[int](sess, "missing")
+	utils.AssertEqual(t, false, ok)
+}
@@ -5,7 +5,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/internal/storage/memory"
+	"github.com/gofiber/fiber/v2/storage/memory"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/valyala/fasthttp"
 )
@@ -481,6 +481,56 @@ func Test_Session_Regenerate(t *testing.T) {
 	})
 }
 
+// go test -run Test_Session_AbsoluteExpiration
+func Test_Session_AbsoluteExpiration(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	store := New(Config{AbsoluteExpiration: time.Hour})
+	ctx := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(ctx)
+
+	sess, err := store.Get(ctx)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, sess.CreatedAt().IsZero())
+	sess.Set("name", "john")
+	// the session was created 59 minutes ago
+	sess.Set(createdKey, time.Now().Add(-59*time.Minute).Unix())
+	id := sess.ID()
+	utils.AssertEqual(t, nil, sess.Save())
+
+	// the idle expiration is capped by the absolute expiration
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+	cookie.SetKey(store.sessionName)
+	utils.AssertEqual(t, true, ctx.Response().Header.Cookie(cookie))
+	utils.AssertEqual(t, true, cookie.MaxAge() > 0 && cookie.MaxAge() <= 60)
+
+	ctx.Request().Header.SetCookie(store.sessionName, id)
+	sess, err = store.Get(ctx)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, false, sess.Fresh())
+	utils.AssertEqual(t, "john", sess.Get("name"))
+	utils.AssertEqual(t, []string{"name"}, sess.Keys())
+
+	// Regenerate keeps the creation time
+	created := sess.CreatedAt()
+	utils.AssertEqual(t, nil, sess.Regenerate())
+	utils.AssertEqual(t, created, sess.CreatedAt())
+	id = sess.ID()
+	utils.AssertEqual(t, nil, sess.Save())
+
+	// the session has reached the absolute expiration
+	store.AbsoluteExpiration = 30 * time.Minute
+	ctx.Request().Header.SetCookie(store.sessionName, id)
+	sess, err = store.Get(ctx)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, sess.Fresh())
+	utils.AssertEqual(t, true, sess.ID() != id)
+	utils.AssertEqual(t, nil, sess.Get("name"))
+	raw, _ := store.Storage.Get(id)
+	utils.AssertEqual(t, true, raw == nil)
+}
+
 // go test -v -run=^$ -bench=Benchmark_Session -benchmem -count=4
 func Benchmark_Session(b *testing.B) {
 	app, store := fiber.New(), New()
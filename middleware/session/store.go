@@ -3,9 +3,10 @@ package session
 import (
 	"encoding/gob"
 	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/internal/storage/memory"
+	"github.com/gofiber/fiber/v2/storage/memory"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/valyala/fasthttp"
 )
@@ -84,6 +85,17 @@ func (s *Store) Get(c *fiber.Ctx) (*Session, error) {
 		}
 	}
 
+	// Replace the session if it has reached its absolute expiration
+	if s.AbsoluteExpiration > 0 && !sess.fresh {
+		if created := sess.CreatedAt(); !created.IsZero() && time.Since(created) >= s.AbsoluteExpiration {
+			if err := s.Storage.Delete(id); err != nil {
+				return nil, err
+			}
+			sess.data.Reset()
+			sess.refresh()
+		}
+	}
+
 	return sess, nil
 }
 
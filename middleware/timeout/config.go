@@ -0,0 +1,51 @@
+package timeout
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Timeout is the deadline of the handler, the user context of the
+	// request is cancelled once it's reached.
+	//
+	// Required.
+	Timeout time.Duration
+
+	// Status is the status code of the timeout error, e.g.
+	// fiber.StatusServiceUnavailable behind a gateway.
+	//
+	// Optional. Default: fiber.StatusRequestTimeout
+	Status int
+
+	// Errors are treated as timeouts when returned by the handler, e.g. the
+	// timeout errors of a database driver. context.DeadlineExceeded is
+	// always treated as timeout.
+	//
+	// Optional. Default: nil
+	Errors []error
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Status: fiber.StatusRequestTimeout,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Status == 0 {
+		cfg.Status = ConfigDefault.Status
+	}
+	return cfg
+}
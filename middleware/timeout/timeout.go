@@ -1,43 +1,75 @@
 package timeout
 
 import (
-	"fmt"
-	"sync"
+	"context"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 )
 
-var once sync.Once
+// MessageRequestTimeout is the key of the error message in fiber.Config.Messages
+const MessageRequestTimeout = "timeout.request_timeout"
 
 // New wraps a handler and aborts the process of the handler if the timeout is reached
 func New(handler fiber.Handler, timeout time.Duration) fiber.Handler {
-	once.Do(func() {
-		fmt.Println("[Warning] timeout contains data race issues, not ready for production!")
-	})
+	return NewWithConfig(handler, Config{Timeout: timeout})
+}
+
+// NewWithConfig wraps a handler with a deadline. The handler runs with a user
+// context, see fiber.Ctx.UserContext, which is cancelled once the deadline
+// is reached, so that it's able to abort its work, e.g. database queries:
+//
+//	app.Get("/report", timeout.New(func(c *fiber.Ctx) error {
+//		rows, err := db.QueryContext(c.UserContext(), query)
+//		...
+//	}, 5*time.Second))
+//
+// If the handler returns after the deadline, its response, including the status
+// and headers, is discarded and the timeout error is forwarded to the error
+// handler instead, so that the client never receives a partially written
+// response.
+func NewWithConfig(handler fiber.Handler, config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
 
-	if timeout <= 0 {
+	if cfg.Timeout <= 0 {
 		return handler
 	}
 
-	// logic is from fasthttp.TimeoutWithCodeHandler https://github.com/valyala/fasthttp/blob/master/server.go#L418
-	return func(ctx *fiber.Ctx) error {
-		ch := make(chan struct{}, 1)
-
-		go func() {
-			defer func() {
-				_ = recover()
-			}()
-			_ = handler(ctx)
-			ch <- struct{}{}
-		}()
-
-		select {
-		case <-ch:
-		case <-time.After(timeout):
-			return fiber.ErrRequestTimeout
+	return func(c *fiber.Ctx) error {
+		parent := c.UserContext()
+		ctx, cancel := context.WithTimeout(parent, cfg.Timeout)
+		defer cancel()
+
+		// Restore the context even if the handler panics
+		c.SetUserContext(ctx)
+		defer c.SetUserContext(parent)
+
+		err := handler(c)
+		if !timedOut(ctx, err, cfg.Errors) {
+			return err
 		}
 
-		return nil
+		// Discard whatever the handler wrote before or after the deadline
+		c.Response().Reset()
+		return fiber.NewError(cfg.Status, c.Message(MessageRequestTimeout, utils.StatusMessage(cfg.Status)))
+	}
+}
+
+// timedOut reports whether the handler exceeded its deadline
+func timedOut(ctx context.Context, err error, timeoutErrors []error) bool {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if err == nil {
+		return false
+	}
+	for _, timeoutErr := range timeoutErrors {
+		if errors.Is(err, timeoutErr) {
+			return true
+		}
 	}
+	return false
 }
@@ -1,55 +1,127 @@
 package timeout
 
-// // go test -run Test_Middleware_Timeout
-// func Test_Middleware_Timeout(t *testing.T) {
-// 	app := fiber.New(fiber.Config{DisableStartupMessage: true})
-
-// 	h := New(func(c *fiber.Ctx) error {
-// 		sleepTime, _ := time.ParseDuration(c.Params("sleepTime") + "ms")
-// 		time.Sleep(sleepTime)
-// 		return c.SendString("After " + c.Params("sleepTime") + "ms sleeping")
-// 	}, 5*time.Millisecond)
-// 	app.Get("/test/:sleepTime", h)
-
-// 	testTimeout := func(timeoutStr string) {
-// 		resp, err := app.Test(httptest.NewRequest("GET", "/test/"+timeoutStr, nil))
-// 		utils.AssertEqual(t, nil, err, "app.Test(req)")
-// 		utils.AssertEqual(t, fiber.StatusRequestTimeout, resp.StatusCode, "Status code")
-
-// 		body, err := ioutil.ReadAll(resp.Body)
-// 		utils.AssertEqual(t, nil, err)
-// 		utils.AssertEqual(t, "Request Timeout", string(body))
-// 	}
-// 	testSucces := func(timeoutStr string) {
-// 		resp, err := app.Test(httptest.NewRequest("GET", "/test/"+timeoutStr, nil))
-// 		utils.AssertEqual(t, nil, err, "app.Test(req)")
-// 		utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode, "Status code")
-
-// 		body, err := ioutil.ReadAll(resp.Body)
-// 		utils.AssertEqual(t, nil, err)
-// 		utils.AssertEqual(t, "After "+timeoutStr+"ms sleeping", string(body))
-// 	}
-
-// 	testTimeout("15")
-// 	testSucces("2")
-// 	testTimeout("30")
-// 	testSucces("3")
-// }
-
-// // go test -run -v Test_Timeout_Panic
-// func Test_Timeout_Panic(t *testing.T) {
-// 	app := fiber.New(fiber.Config{DisableStartupMessage: true})
-
-// 	app.Get("/panic", recover.New(), New(func(c *fiber.Ctx) error {
-// 		c.Set("dummy", "this should not be here")
-// 		panic("panic in timeout handler")
-// 	}, 5*time.Millisecond))
-
-// 	resp, err := app.Test(httptest.NewRequest("GET", "/panic", nil))
-// 	utils.AssertEqual(t, nil, err, "app.Test(req)")
-// 	utils.AssertEqual(t, fiber.StatusRequestTimeout, resp.StatusCode, "Status code")
-
-// 	body, err := ioutil.ReadAll(resp.Body)
-// 	utils.AssertEqual(t, nil, err)
-// 	utils.AssertEqual(t, "Request Timeout", string(body))
-// }
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// sleep waits for the duration or the cancellation of the context
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// go test -run Test_Timeout
+func Test_Timeout(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/:sleep", New(func(c *fiber.Ctx) error {
+		d, _ := time.ParseDuration(c.Params("sleep") + "ms")
+		if err := sleep(c.UserContext(), d); err != nil {
+			return err
+		}
+		return c.SendString("slept " + c.Params("sleep") + "ms")
+	}, 50*time.Millisecond))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/1", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "slept 1ms", string(body))
+
+	start := time.Now()
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/1000", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusRequestTimeout, resp.StatusCode)
+	utils.AssertEqual(t, true, time.Since(start) < 500*time.Millisecond)
+	body, err = ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "Request Timeout", string(body))
+}
+
+// go test -run Test_Timeout_Write_After_Deadline
+func Test_Timeout_Write_After_Deadline(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/", NewWithConfig(func(c *fiber.Ctx) error {
+		c.Set("X-Partial", "1")
+		c.Status(fiber.StatusAccepted).Type("json")
+		_ = c.SendString("partial")
+		time.Sleep(30 * time.Millisecond)
+		return c.SendString("too late")
+	}, Config{Timeout: 10 * time.Millisecond, Status: fiber.StatusServiceUnavailable}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	utils.AssertEqual(t, "", resp.Header.Get("X-Partial"))
+	utils.AssertEqual(t, fiber.MIMETextPlainCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "Service Unavailable", string(body))
+}
+
+// go test -run Test_Timeout_Panic
+func Test_Timeout_Panic(t *testing.T) {
+	t.Parallel()
+	var parent, after context.Context
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) (err error) {
+		parent = c.UserContext()
+		defer func() {
+			after = c.UserContext()
+			if r := recover(); r != nil {
+				err = fiber.ErrInternalServerError
+			}
+		}()
+		return c.Next()
+	}, New(func(c *fiber.Ctx) error {
+		panic("handler")
+	}, time.Second))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+	// The context of the panicking handler isn't leaked
+	utils.AssertEqual(t, parent, after)
+}
+
+// go test -run Test_Timeout_Errors
+func Test_Timeout_Errors(t *testing.T) {
+	t.Parallel()
+	errQueryTimeout := errors.New("query timeout")
+	var parent context.Context
+	app := fiber.New(fiber.Config{
+		Messages: fiber.Messages{"*": {MessageRequestTimeout: "Too slow"}},
+	})
+	app.Get("/", func(c *fiber.Ctx) error {
+		parent = c.UserContext()
+		err := c.Next()
+		// The user context is restored
+		utils.AssertEqual(t, parent, c.UserContext())
+		return err
+	}, NewWithConfig(func(c *fiber.Ctx) error {
+		_, ok := c.UserContext().Deadline()
+		utils.AssertEqual(t, true, ok)
+		return errQueryTimeout
+	}, Config{Timeout: time.Second, Errors: []error{errQueryTimeout}}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusRequestTimeout, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "Too slow", string(body))
+}
@@ -0,0 +1,89 @@
+package transaction
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// Begin starts the transaction of the request, e.g.
+	//
+	//	func(c *fiber.Ctx) (interface{}, error) {
+	//		return db.BeginTx(c.UserContext(), nil)
+	//	}
+	//
+	// Required.
+	Begin func(c *fiber.Ctx) (interface{}, error)
+
+	// Commit commits the transaction.
+	//
+	// Optional. Default: calls the Commit() error method of the transaction, e.g. of *sql.Tx
+	Commit func(tx interface{}) error
+
+	// Rollback rolls the transaction back.
+	//
+	// Optional. Default: calls the Rollback() error method of the transaction, e.g. of *sql.Tx
+	Rollback func(tx interface{}) error
+
+	// CommitOn decides if the transaction is committed after the handlers
+	// returned err, it's rolled back otherwise.
+	//
+	// Optional. Default: err is nil and the status code is below 400
+	CommitOn func(c *fiber.Ctx, err error) bool
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:     nil,
+	Commit:   commit,
+	Rollback: rollback,
+	CommitOn: commitOn,
+}
+
+func commit(tx interface{}) error {
+	if committer, ok := tx.(interface{ Commit() error }); ok {
+		return committer.Commit()
+	}
+	return errors.New("transaction: the transaction has no Commit() error method, set Config.Commit")
+}
+
+func rollback(tx interface{}) error {
+	if rollbacker, ok := tx.(interface{ Rollback() error }); ok {
+		return rollbacker.Rollback()
+	}
+	return errors.New("transaction: the transaction has no Rollback() error method, set Config.Rollback")
+}
+
+func commitOn(c *fiber.Ctx, err error) bool {
+	return err == nil && c.Response().StatusCode() < fiber.StatusBadRequest
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// The transaction can't be started without Begin
+	if len(config) < 1 || config[0].Begin == nil {
+		panic("transaction: Config.Begin is required")
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Commit == nil {
+		cfg.Commit = ConfigDefault.Commit
+	}
+	if cfg.Rollback == nil {
+		cfg.Rollback = ConfigDefault.Rollback
+	}
+	if cfg.CommitOn == nil {
+		cfg.CommitOn = ConfigDefault.CommitOn
+	}
+	return cfg
+}
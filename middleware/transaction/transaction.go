@@ -0,0 +1,46 @@
+package transaction
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// New creates a new middleware handler, which runs the following handlers in
+// a transaction. The transaction is committed if they succeed and rolled back
+// if they return an error, respond with an error status or panic.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c *fiber.Ctx) (err error) {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		tx, err := cfg.Begin(c)
+		if err != nil {
+			return err
+		}
+		c.SetTransaction(tx)
+
+		// Roll back if a handler panics, the panic continues afterwards
+		completed := false
+		defer func() {
+			if !completed {
+				_ = cfg.Rollback(tx)
+			}
+		}()
+
+		err = c.Next()
+		completed = true
+
+		if cfg.CommitOn(c, err) {
+			return cfg.Commit(tx)
+		}
+		if rbErr := cfg.Rollback(tx); rbErr != nil && err == nil {
+			return rbErr
+		}
+		return err
+	}
+}
@@ -0,0 +1,135 @@
+package transaction
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type fakeTx struct {
+	committed, rolledBack bool
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.rolledBack = true
+	return nil
+}
+
+// go test -run Test_Transaction
+func Test_Transaction(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	var tx *fakeTx
+	app.Use(func(c *fiber.Ctx) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fiber.ErrInternalServerError
+			}
+		}()
+		return c.Next()
+	})
+	app.Use(New(Config{
+		Begin: func(c *fiber.Ctx) (interface{}, error) {
+			if c.Query("fail") != "" {
+				return nil, errors.New("database unavailable")
+			}
+			tx = &fakeTx{}
+			return tx, nil
+		},
+	}))
+	app.Get("/:action", func(c *fiber.Ctx) error {
+		utils.AssertEqual(t, tx, c.Transaction())
+		switch c.Params("action") {
+		case "error":
+			return fiber.ErrConflict
+		case "status":
+			return c.SendStatus(fiber.StatusUnprocessableEntity)
+		case "panic":
+			panic("boom")
+		}
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	for _, tc := range []struct {
+		action    string
+		status    int
+		committed bool
+	}{
+		{"ok", fiber.StatusCreated, true},
+		{"error", fiber.StatusConflict, false},
+		{"status", fiber.StatusUnprocessableEntity, false},
+		{"panic", fiber.StatusInternalServerError, false},
+	} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/"+tc.action, nil))
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, tc.status, resp.StatusCode, tc.action)
+		utils.AssertEqual(t, tc.committed, tx.committed, tc.action)
+		utils.AssertEqual(t, !tc.committed, tx.rolledBack, tc.action)
+	}
+
+	// Begin fails
+	tx = nil
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ok?fail=1", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+	utils.AssertEqual(t, (*fakeTx)(nil), tx)
+}
+
+// go test -run Test_Transaction_Hooks
+func Test_Transaction_Hooks(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	var calls []string
+	app.Use(New(Config{
+		Begin: func(c *fiber.Ctx) (interface{}, error) {
+			return "custom", nil
+		},
+		Commit: func(tx interface{}) error {
+			calls = append(calls, "commit "+tx.(string))
+			return errors.New("serialization failure")
+		},
+		Rollback: func(tx interface{}) error {
+			calls = append(calls, "rollback "+tx.(string))
+			return nil
+		},
+		CommitOn: func(c *fiber.Ctx, err error) bool {
+			return err == nil && c.Method() != fiber.MethodDelete
+		},
+	}))
+	app.All("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// A failed commit is an error
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodDelete, "/", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, fiber.StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, []string{"commit custom", "rollback custom"}, calls)
+
+	// Default hooks require the methods
+	utils.AssertEqual(t, true, commit("tx") != nil)
+	utils.AssertEqual(t, true, rollback("tx") != nil)
+}
+
+// go test -run Test_Transaction_Begin_Required
+func Test_Transaction_Begin_Required(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		utils.AssertEqual(t, "transaction: Config.Begin is required", recover())
+	}()
+	New()
+}
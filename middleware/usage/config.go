@@ -0,0 +1,106 @@
+package usage
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// Sink receives the batches of usage events.
+	//
+	// Required. Default: nil
+	Sink Sink
+
+	// IdentityKey is the key of the authenticated identity in Locals, e.g. the
+	// key set by a custom auth middleware. The identities of the basicauth,
+	// keyauth and jwt middleware are looked up after it, see quota.Identity.
+	//
+	// Optional. Default: "username"
+	IdentityKey string
+
+	// Identity returns the identity the request is accounted to.
+	//
+	// Optional. Default: quota.Identity with IdentityKey
+	Identity func(c *fiber.Ctx) string
+
+	// BatchSize is the maximum number of events sent at once.
+	//
+	// Optional. Default: 100
+	BatchSize int
+
+	// FlushInterval is the maximum time an event is buffered before it's sent.
+	//
+	// Optional. Default: 5 * time.Second
+	FlushInterval time.Duration
+
+	// BufferSize is the number of events which are buffered while the sink is busy.
+	// Further events are dropped and passed to OnError.
+	//
+	// Optional. Default: 10000
+	BufferSize int
+
+	// MaxRetries is the number of retries if the sink fails to send a batch.
+	//
+	// Optional. Default: 3
+	MaxRetries int
+
+	// RetryInterval is the delay before the first retry, it's doubled for every further retry.
+	//
+	// Optional. Default: 1 * time.Second
+	RetryInterval time.Duration
+
+	// OnError is called with the events which couldn't be sent.
+	//
+	// Optional. Default: nil
+	OnError func(err error, events []Event)
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:          nil,
+	IdentityKey:   "username",
+	BatchSize:     100,
+	FlushInterval: 5 * time.Second,
+	BufferSize:    10000,
+	MaxRetries:    3,
+	RetryInterval: 1 * time.Second,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.IdentityKey == "" {
+		cfg.IdentityKey = ConfigDefault.IdentityKey
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = ConfigDefault.BatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = ConfigDefault.FlushInterval
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = ConfigDefault.BufferSize
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = ConfigDefault.MaxRetries
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = ConfigDefault.RetryInterval
+	}
+	return cfg
+}
@@ -0,0 +1,207 @@
+package usage
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/quota"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Event is a normalized usage event of a request
+type Event struct {
+	Time     time.Time     `json:"time"`
+	Identity string        `json:"identity"`
+	Method   string        `json:"method"`
+	Route    string        `json:"route"` // Registered path of the route, e.g. "/users/:id"
+	Path     string        `json:"path"`
+	Status   int           `json:"status"`
+	BytesIn  int           `json:"bytes_in"`
+	BytesOut int           `json:"bytes_out"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Sink sends batches of usage events, e.g. to a billing service or a queue
+type Sink interface {
+	Send(events []Event) error
+}
+
+// SinkFunc is an adapter to allow the use of ordinary functions as Sink
+type SinkFunc func(events []Event) error
+
+// Send calls f(events)
+func (f SinkFunc) Send(events []Event) error {
+	return f(events)
+}
+
+// ErrBufferFull is passed to OnError for the events dropped because the buffer is full
+var ErrBufferFull = errors.New("usage: event buffer is full")
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	if cfg.Sink == nil {
+		panic("usage: Sink is required")
+	}
+
+	e := newEmitter(cfg)
+	var once sync.Once
+
+	// Return new handler
+	return func(c *fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		// Flush the pending events when the app shuts down
+		once.Do(func() {
+			c.App().Hooks().OnShutdown(func() error {
+				e.close()
+				return nil
+			})
+		})
+
+		start := time.Now()
+		err := c.Next()
+
+		event := Event{
+			Time:     start,
+			Identity: identity(c, cfg),
+			Method:   utils.CopyString(c.Method()),
+			Route:    c.Route().Path,
+			Path:     utils.CopyString(c.Path()),
+			Status:   c.Response().StatusCode(),
+			BytesIn:  c.Request().Header.ContentLength(),
+			BytesOut: len(c.Response().Body()),
+			Duration: time.Since(start),
+		}
+		// The error handler sets the status code after the middleware returned
+		if err != nil {
+			event.Status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				event.Status = fe.Code
+			}
+		}
+		if event.BytesIn < 0 {
+			event.BytesIn = 0
+		}
+		if c.Response().IsBodyStream() {
+			event.BytesOut = c.Response().Header.ContentLength()
+		}
+		e.emit(event)
+
+		return err
+	}
+}
+
+// identity returns the identity of the request
+func identity(c *fiber.Ctx, cfg Config) string {
+	if cfg.Identity != nil {
+		return cfg.Identity(c)
+	}
+	return quota.Identity(c, cfg.IdentityKey)
+}
+
+// emitter batches the events and sends them to the sink
+type emitter struct {
+	cfg    Config
+	events chan Event
+	done   chan struct{}
+	mux    sync.RWMutex
+	closed bool
+}
+
+func newEmitter(cfg Config) *emitter {
+	e := &emitter{
+		cfg:    cfg,
+		events: make(chan Event, cfg.BufferSize),
+		done:   make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// emit buffers the event without blocking the request
+func (e *emitter) emit(event Event) {
+	e.mux.RLock()
+	defer e.mux.RUnlock()
+	// Events of requests finishing after the shutdown are sent directly
+	if e.closed {
+		e.send([]Event{event})
+		return
+	}
+	select {
+	case e.events <- event:
+	default:
+		if e.cfg.OnError != nil {
+			e.cfg.OnError(ErrBufferFull, []Event{event})
+		}
+	}
+}
+
+// close sends the pending events and stops the emitter
+func (e *emitter) close() {
+	e.mux.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.events)
+	}
+	e.mux.Unlock()
+	<-e.done
+}
+
+func (e *emitter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, e.cfg.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		e.send(batch)
+		batch = make([]Event, 0, e.cfg.BatchSize)
+	}
+
+	for {
+		select {
+		case event, ok := <-e.events:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= e.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// send sends the batch to the sink, retrying with an exponential backoff
+func (e *emitter) send(batch []Event) {
+	interval := e.cfg.RetryInterval
+	var err error
+	for attempt := 0; attempt <= e.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(interval)
+			interval *= 2
+		}
+		if err = e.cfg.Sink.Send(batch); err == nil {
+			return
+		}
+	}
+	if e.cfg.OnError != nil {
+		e.cfg.OnError(err, batch)
+	}
+}
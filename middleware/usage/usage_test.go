@@ -0,0 +1,172 @@
+package usage
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/keyauth"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type testSink struct {
+	mux     sync.Mutex
+	batches [][]Event
+	fails   int
+}
+
+func (s *testSink) Send(events []Event) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.fails > 0 {
+		s.fails--
+		return errors.New("sink unavailable")
+	}
+	s.batches = append(s.batches, events)
+	return nil
+}
+
+func (s *testSink) get() [][]Event {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.batches
+}
+
+// go test -run Test_Usage
+func Test_Usage(t *testing.T) {
+	t.Parallel()
+	sink := &testSink{}
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("username", "john")
+		return c.Next()
+	})
+	app.Use(New(Config{
+		Sink:          sink,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	}))
+	app.Post("/users/:id", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).SendString("created")
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return fiber.ErrTeapot
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/users/1", strings.NewReader("name=john")))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusCreated, resp.StatusCode)
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusTeapot, resp.StatusCode)
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+	utils.AssertEqual(t, nil, err)
+
+	// The full batch is sent right away
+	for i := 0; i < 100 && len(sink.get()) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	batches := sink.get()
+	utils.AssertEqual(t, 1, len(batches))
+	utils.AssertEqual(t, 2, len(batches[0]))
+
+	event := batches[0][0]
+	utils.AssertEqual(t, "john", event.Identity)
+	utils.AssertEqual(t, fiber.MethodPost, event.Method)
+	utils.AssertEqual(t, "/users/:id", event.Route)
+	utils.AssertEqual(t, "/users/1", event.Path)
+	utils.AssertEqual(t, fiber.StatusCreated, event.Status)
+	utils.AssertEqual(t, 9, event.BytesIn)
+	utils.AssertEqual(t, 7, event.BytesOut)
+	utils.AssertEqual(t, fiber.StatusTeapot, batches[0][1].Status)
+
+	// The pending event is sent on shutdown
+	_ = app.Shutdown()
+	batches = sink.get()
+	utils.AssertEqual(t, 2, len(batches))
+	utils.AssertEqual(t, 1, len(batches[1]))
+}
+
+// go test -run Test_Usage_KeyAuth
+func Test_Usage_KeyAuth(t *testing.T) {
+	t.Parallel()
+	sink := &testSink{}
+	app := fiber.New()
+	app.Use(keyauth.New(keyauth.Config{
+		Validator: func(_ *fiber.Ctx, key string) (interface{}, error) {
+			return "tenant-" + key, nil
+		},
+	}))
+	app.Use(New(Config{Sink: sink}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer acme")
+	_, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+
+	// The principal of keyauth is the identity
+	_ = app.Shutdown()
+	batches := sink.get()
+	utils.AssertEqual(t, 1, len(batches))
+	utils.AssertEqual(t, "tenant-acme", batches[0][0].Identity)
+}
+
+// go test -run Test_Usage_Retry
+func Test_Usage_Retry(t *testing.T) {
+	t.Parallel()
+	var failed []Event
+	sink := &testSink{fails: 2}
+	e := newEmitter(configDefault(Config{
+		Sink:          sink,
+		MaxRetries:    2,
+		RetryInterval: time.Millisecond,
+		OnError: func(err error, events []Event) {
+			failed = append(failed, events...)
+		},
+	}))
+	e.emit(Event{Path: "/1"})
+	e.close()
+	utils.AssertEqual(t, 1, len(sink.get()))
+	utils.AssertEqual(t, 0, len(failed))
+
+	sink = &testSink{fails: 2}
+	e = newEmitter(configDefault(Config{
+		Sink:          sink,
+		MaxRetries:    1,
+		RetryInterval: time.Millisecond,
+		OnError: func(err error, events []Event) {
+			failed = append(failed, events...)
+		},
+	}))
+	e.emit(Event{Path: "/2"})
+	e.close()
+	utils.AssertEqual(t, 0, len(sink.get()))
+	utils.AssertEqual(t, 1, len(failed))
+	utils.AssertEqual(t, "/2", failed[0].Path)
+}
+
+// go test -run Test_Usage_Next
+func Test_Usage_Next(t *testing.T) {
+	t.Parallel()
+	sink := &testSink{}
+	app := fiber.New()
+	app.Use(New(Config{
+		Sink: sink,
+		Next: func(_ *fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
+	_ = app.Shutdown()
+	utils.AssertEqual(t, 0, len(sink.get()))
+}
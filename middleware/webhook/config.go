@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// Config defines the config for the sender.
+type Config struct {
+	// Endpoints receive the events they are subscribed to, further endpoints
+	// can be added with Sender.AddEndpoint.
+	//
+	// Optional. Default: nil
+	Endpoints []Endpoint
+
+	// Storage persists the outbox, so that pending deliveries survive a restart.
+	//
+	// Optional. Default: an in-memory storage
+	Storage fiber.Storage
+
+	// KeyPrefix is the prefix of the keys in Storage.
+	//
+	// Optional. Default: "webhook_"
+	KeyPrefix string
+
+	// MaxAttempts is the number of attempts to deliver an event, after which
+	// the delivery fails. Failed deliveries are kept until they are retried
+	// or deleted.
+	//
+	// Optional. Default: 10
+	MaxAttempts int
+
+	// Backoff is the delay before the second attempt, it's doubled for every
+	// further attempt.
+	//
+	// Optional. Default: 1 * time.Second
+	Backoff time.Duration
+
+	// MaxBackoff is the maximum delay between two attempts.
+	//
+	// Optional. Default: 1 * time.Hour
+	MaxBackoff time.Duration
+
+	// Timeout of a delivery request.
+	//
+	// Optional. Default: 10 * time.Second
+	Timeout time.Duration
+
+	// Concurrency is the maximum number of deliveries sent at once.
+	//
+	// Optional. Default: 10
+	Concurrency int
+
+	// PollInterval is the interval in which the outbox is checked for due deliveries.
+	//
+	// Optional. Default: 1 * time.Second
+	PollInterval time.Duration
+
+	// Client sends the delivery requests.
+	//
+	// Optional. Default: &fasthttp.Client{}
+	Client *fasthttp.Client
+
+	// OnFailure is called when a delivery failed its last attempt, e.g. to
+	// notify the owner of the endpoint.
+	//
+	// Optional. Default: nil
+	OnFailure func(d Delivery)
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	KeyPrefix:    "webhook_",
+	MaxAttempts:  10,
+	Backoff:      1 * time.Second,
+	MaxBackoff:   1 * time.Hour,
+	Timeout:      10 * time.Second,
+	Concurrency:  10,
+	PollInterval: 1 * time.Second,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = ConfigDefault.KeyPrefix
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = ConfigDefault.MaxAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = ConfigDefault.Backoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = ConfigDefault.MaxBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = ConfigDefault.Timeout
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = ConfigDefault.Concurrency
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = ConfigDefault.PollInterval
+	}
+	return cfg
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Headers of a delivery request
+const (
+	// HeaderID is the ID of the event, which is the same for all attempts,
+	// so receivers can ignore duplicates
+	HeaderID = "X-Webhook-Id"
+	// HeaderEvent is the name of the event
+	HeaderEvent = "X-Webhook-Event"
+	// HeaderTimestamp is the time of the attempt in Unix seconds
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature is the signature of the request, see Sign
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign returns the signature of a delivery request, which is the hex encoded
+// HMAC-SHA256 of "<id>.<timestamp>.<body>" with the prefix "v1=". Signing the
+// ID and the timestamp prevents replaying the body with another ID or later.
+func Sign(secret, id string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(id))
+	_, _ = mac.Write([]byte{'.'})
+	_, _ = mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	_, _ = mac.Write([]byte{'.'})
+	_, _ = mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify creates a middleware, which verifies the signature of the requests
+// sent by a Sender. It responds with 401 Unauthorized if the signature doesn't
+// match any of the secrets, or the timestamp differs by more than tolerance
+// from the current time. Multiple secrets allow rotating them. A tolerance
+// <= 0 disables the timestamp check.
+func Verify(tolerance time.Duration, secrets ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		timestamp, err := strconv.ParseInt(c.Get(HeaderTimestamp), 10, 64)
+		if err != nil {
+			return fiber.ErrUnauthorized
+		}
+		if tolerance > 0 {
+			if diff := time.Since(time.Unix(timestamp, 0)); diff > tolerance || diff < -tolerance {
+				return fiber.ErrUnauthorized
+			}
+		}
+
+		id := c.Get(HeaderID)
+		// The header may list several signatures separated by commas
+		signatures := strings.Split(c.Get(HeaderSignature), ",")
+		for _, secret := range secrets {
+			expected := []byte(Sign(secret, id, timestamp, c.Body()))
+			for _, signature := range signatures {
+				if hmac.Equal(expected, []byte(strings.TrimSpace(signature))) {
+					return c.Next()
+				}
+			}
+		}
+		return fiber.ErrUnauthorized
+	}
+}
@@ -0,0 +1,591 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/storage/memory"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// Endpoint is a receiver of webhooks
+type Endpoint struct {
+	// ID identifies the endpoint in the deliveries and statistics
+	ID string `json:"id"`
+	// URL the events are posted to
+	URL string `json:"url"`
+	// Secret the requests are signed with, see Sign
+	Secret string `json:"-"`
+	// Events the endpoint is subscribed to, all events if empty
+	Events []string `json:"events,omitempty"`
+}
+
+// subscribed reports if the endpoint receives the event
+func (e Endpoint) subscribed(event string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, name := range e.Events {
+		if name == event || name == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus is the status of a Delivery
+type DeliveryStatus string
+
+const (
+	// StatusPending deliveries are attempted until they succeed or fail
+	StatusPending DeliveryStatus = "pending"
+	// StatusFailed deliveries failed their last attempt or the endpoint
+	// responded with 410 Gone
+	StatusFailed DeliveryStatus = "failed"
+)
+
+// Delivery is an event, which is delivered to an endpoint. It's removed from
+// the outbox once the endpoint responded with a 2xx status.
+type Delivery struct {
+	ID             string          `json:"id"`
+	EventID        string          `json:"event_id"`
+	Event          string          `json:"event"`
+	Endpoint       string          `json:"endpoint"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	CreatedAt      time.Time       `json:"created_at"`
+	NextAttempt    time.Time       `json:"next_attempt"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+}
+
+// EndpointStatus is the status of an endpoint
+type EndpointStatus struct {
+	Endpoint
+	Pending             int       `json:"pending"`
+	Failed              int       `json:"failed"`
+	Delivered           uint64    `json:"delivered"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastSuccess         time.Time `json:"last_success"`
+	LastFailure         time.Time `json:"last_failure"`
+	LastError           string    `json:"last_error,omitempty"`
+}
+
+// ErrNotFound is returned for unknown deliveries
+var ErrNotFound = errors.New("webhook: delivery not found")
+
+// errEndpointRemoved fails the deliveries of removed endpoints
+var errEndpointRemoved = errors.New("webhook: endpoint removed")
+
+// Sender delivers events to the subscribed endpoints. The events are written
+// to an outbox in Storage first and delivered in the background, failed
+// attempts are retried with an exponential backoff.
+type Sender struct {
+	cfg Config
+
+	mutex      sync.Mutex
+	endpoints  []*EndpointStatus
+	deliveries map[string]*Delivery
+	inFlight   map[string]bool
+
+	sem       chan struct{}
+	wake      chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// New creates a new Sender, which loads the pending deliveries from Storage
+// and delivers them until it's closed.
+func New(config ...Config) *Sender {
+	// Set default config
+	cfg := configDefault(config...)
+
+	if cfg.Storage == nil {
+		cfg.Storage = memory.New()
+	}
+	if cfg.Client == nil {
+		cfg.Client = &fasthttp.Client{}
+	}
+
+	s := &Sender{
+		cfg:        cfg,
+		deliveries: make(map[string]*Delivery),
+		inFlight:   make(map[string]bool),
+		sem:        make(chan struct{}, cfg.Concurrency),
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	for _, e := range cfg.Endpoints {
+		s.AddEndpoint(e)
+	}
+	if err := s.load(); err != nil {
+		panic(fmt.Sprintf("webhook: failed to load the outbox: %v", err))
+	}
+
+	go s.run()
+	return s
+}
+
+// AddEndpoint adds the endpoint, it replaces an endpoint with the same ID.
+func (s *Sender) AddEndpoint(e Endpoint) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, status := range s.endpoints {
+		if status.ID == e.ID {
+			status.Endpoint = e
+			return
+		}
+	}
+	s.endpoints = append(s.endpoints, &EndpointStatus{Endpoint: e})
+}
+
+// RemoveEndpoint removes the endpoint, its pending deliveries fail.
+func (s *Sender) RemoveEndpoint(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, status := range s.endpoints {
+		if status.ID == id {
+			s.endpoints = append(s.endpoints[:i], s.endpoints[i+1:]...)
+			return
+		}
+	}
+}
+
+// Send enqueues the event for all endpoints subscribed to it and returns the
+// ID of the event. The payload is encoded as JSON, unless it's a []byte of
+// JSON already. Send returns once the deliveries are written to Storage.
+func (s *Sender) Send(event string, payload interface{}) (string, error) {
+	body, ok := payload.([]byte)
+	if !ok {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return "", err
+		}
+	}
+
+	eventID := utils.UUIDv4()
+	now := time.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var added []string
+	for _, e := range s.endpoints {
+		if !e.subscribed(event) {
+			continue
+		}
+		d := &Delivery{
+			ID:          utils.UUIDv4(),
+			EventID:     eventID,
+			Event:       event,
+			Endpoint:    e.ID,
+			Payload:     body,
+			Status:      StatusPending,
+			CreatedAt:   now,
+			NextAttempt: now,
+		}
+		if err := s.store(d); err != nil {
+			// Don't deliver the event partially
+			for _, id := range added {
+				delete(s.deliveries, id)
+				_ = s.cfg.Storage.Delete(s.deliveryKey(id))
+			}
+			return "", err
+		}
+		s.deliveries[d.ID] = d
+		added = append(added, d.ID)
+	}
+	if len(added) == 0 {
+		return eventID, nil
+	}
+	if err := s.storeOutbox(); err != nil {
+		return "", err
+	}
+
+	s.notify()
+	return eventID, nil
+}
+
+// Endpoints returns the endpoints and their statistics.
+func (s *Sender) Endpoints() []EndpointStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	endpoints := make([]EndpointStatus, len(s.endpoints))
+	index := make(map[string]int, len(s.endpoints))
+	for i, e := range s.endpoints {
+		endpoints[i] = *e
+		index[e.ID] = i
+	}
+	for _, d := range s.deliveries {
+		i, ok := index[d.Endpoint]
+		if !ok {
+			continue
+		}
+		if d.Status == StatusPending {
+			endpoints[i].Pending++
+		} else {
+			endpoints[i].Failed++
+		}
+	}
+	return endpoints
+}
+
+// Deliveries returns the deliveries in the outbox with the status, or all of
+// them if status is empty, ordered by their creation.
+func (s *Sender) Deliveries(status DeliveryStatus) []Delivery {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deliveries := make([]Delivery, 0, len(s.deliveries))
+	for _, d := range s.deliveries {
+		if status == "" || d.Status == status {
+			deliveries = append(deliveries, *d)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].ID < deliveries[j].ID
+		}
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+	return deliveries
+}
+
+// Retry attempts the delivery again immediately, failed deliveries get
+// MaxAttempts further attempts.
+func (s *Sender) Retry(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	d, ok := s.deliveries[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if s.inFlight[id] {
+		return nil
+	}
+	if d.Status == StatusFailed {
+		d.Attempts = 0
+	}
+	d.Status = StatusPending
+	d.NextAttempt = time.Now()
+	if err := s.store(d); err != nil {
+		return err
+	}
+
+	s.notify()
+	return nil
+}
+
+// Delete removes the delivery from the outbox.
+func (s *Sender) Delete(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.deliveries[id]; !ok {
+		return ErrNotFound
+	}
+	return s.remove(id)
+}
+
+// Routes registers the management endpoints on the router, which should be
+// protected, e.g. by the basicauth middleware:
+//
+//	GET    /endpoints               the endpoints and their statistics
+//	GET    /deliveries?status=      the deliveries in the outbox
+//	POST   /deliveries/:id/retry    attempts the delivery again
+//	DELETE /deliveries/:id          removes the delivery from the outbox
+func (s *Sender) Routes(router fiber.Router) {
+	router.Get("/endpoints", func(c *fiber.Ctx) error {
+		return c.JSON(s.Endpoints())
+	})
+	router.Get("/deliveries", func(c *fiber.Ctx) error {
+		return c.JSON(s.Deliveries(DeliveryStatus(c.Query("status"))))
+	})
+	router.Post("/deliveries/:id/retry", func(c *fiber.Ctx) error {
+		return managementResult(c, s.Retry(c.Params("id")))
+	})
+	router.Delete("/deliveries/:id", func(c *fiber.Ctx) error {
+		return managementResult(c, s.Delete(c.Params("id")))
+	})
+}
+
+// managementResult responds to a management request
+func managementResult(c *fiber.Ctx, err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return fiber.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Close stops the delivery and waits for the running attempts, the pending
+// deliveries remain in Storage.
+func (s *Sender) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	<-s.stopped
+	s.wg.Wait()
+}
+
+// notify wakes up the delivery loop
+func (s *Sender) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers the due deliveries until the sender is closed
+func (s *Sender) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		s.dispatch()
+		select {
+		case <-ticker.C:
+		case <-s.wake:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// dispatch starts the attempts of the due deliveries
+func (s *Sender) dispatch() {
+	now := time.Now()
+
+	s.mutex.Lock()
+	var due []*Delivery
+	for _, d := range s.deliveries {
+		if d.Status == StatusPending && !s.inFlight[d.ID] && !d.NextAttempt.After(now) {
+			s.inFlight[d.ID] = true
+			due = append(due, d)
+		}
+	}
+	s.mutex.Unlock()
+
+	// Attempt the oldest deliveries first
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+	for i, d := range due {
+		select {
+		case s.sem <- struct{}{}:
+		case <-s.done:
+			s.mutex.Lock()
+			for _, d := range due[i:] {
+				delete(s.inFlight, d.ID)
+			}
+			s.mutex.Unlock()
+			return
+		}
+		s.wg.Add(1)
+		go func(d *Delivery) {
+			defer func() {
+				<-s.sem
+				s.wg.Done()
+			}()
+			s.attempt(d)
+		}(d)
+	}
+}
+
+// attempt sends the delivery to its endpoint and records the result
+func (s *Sender) attempt(d *Delivery) {
+	s.mutex.Lock()
+	var endpoint *EndpointStatus
+	for _, e := range s.endpoints {
+		if e.ID == d.Endpoint {
+			endpoint = e
+		}
+	}
+	var url, secret string
+	if endpoint != nil {
+		url, secret = endpoint.URL, endpoint.Secret
+	}
+	eventID, event, payload := d.EventID, d.Event, d.Payload
+	s.mutex.Unlock()
+
+	code, err := 0, errEndpointRemoved
+	if endpoint != nil {
+		code, err = s.post(url, secret, eventID, event, payload)
+	}
+
+	s.mutex.Lock()
+	delete(s.inFlight, d.ID)
+	// The delivery was deleted during the attempt
+	if s.deliveries[d.ID] != d {
+		s.mutex.Unlock()
+		return
+	}
+
+	now := time.Now()
+	d.Attempts++
+	d.LastStatusCode = code
+	d.LastError = ""
+	if err != nil {
+		d.LastError = err.Error()
+	}
+
+	if endpoint != nil {
+		if err == nil {
+			endpoint.Delivered++
+			endpoint.ConsecutiveFailures = 0
+			endpoint.LastSuccess = now
+		} else {
+			endpoint.ConsecutiveFailures++
+			endpoint.LastFailure = now
+			endpoint.LastError = d.LastError
+		}
+	}
+
+	if err == nil {
+		_ = s.remove(d.ID)
+		s.mutex.Unlock()
+		return
+	}
+
+	failed := endpoint == nil || code == fiber.StatusGone || d.Attempts >= s.cfg.MaxAttempts
+	if failed {
+		d.Status = StatusFailed
+	} else {
+		d.NextAttempt = now.Add(s.backoff(d.Attempts))
+	}
+	_ = s.store(d)
+	failure := *d
+	s.mutex.Unlock()
+
+	if failed && s.cfg.OnFailure != nil {
+		s.cfg.OnFailure(failure)
+	}
+}
+
+// post sends the signed payload to the url, it returns the status of the
+// response and an error unless the status is 2xx
+func (s *Sender) post(url, secret, eventID, event string, payload []byte) (int, error) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	timestamp := time.Now().Unix()
+	req.Header.SetMethod(fiber.MethodPost)
+	req.SetRequestURI(url)
+	req.Header.SetContentType(fiber.MIMEApplicationJSON)
+	req.Header.Set(HeaderID, eventID)
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, eventID, timestamp, payload))
+	req.SetBody(payload)
+
+	if err := s.cfg.Client.DoTimeout(req, resp, s.cfg.Timeout); err != nil {
+		return 0, err
+	}
+	code := resp.StatusCode()
+	if code < 200 || code >= 300 {
+		return code, fmt.Errorf("webhook: endpoint responded with status %d", code)
+	}
+	return code, nil
+}
+
+// backoff returns the delay after the attempt
+func (s *Sender) backoff(attempts int) time.Duration {
+	delay := s.cfg.Backoff
+	for i := 1; i < attempts && delay < s.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.cfg.MaxBackoff {
+		delay = s.cfg.MaxBackoff
+	}
+	return delay
+}
+
+// outboxKey is the key of the IDs of the deliveries in the outbox
+func (s *Sender) outboxKey() string {
+	return s.cfg.KeyPrefix + "outbox"
+}
+
+// deliveryKey is the key of a delivery
+func (s *Sender) deliveryKey(id string) string {
+	return s.cfg.KeyPrefix + "delivery_" + id
+}
+
+// load reads the outbox from Storage
+func (s *Sender) load() error {
+	raw, err := s.cfg.Storage.Get(s.outboxKey())
+	if err != nil || raw == nil {
+		return err
+	}
+	var ids []string
+	if err = json.Unmarshal(raw, &ids); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		raw, err = s.cfg.Storage.Get(s.deliveryKey(id))
+		if err != nil {
+			return err
+		}
+		// The delivery was removed, but the outbox not updated
+		if raw == nil {
+			continue
+		}
+		d := new(Delivery)
+		if err = json.Unmarshal(raw, d); err != nil {
+			return err
+		}
+		s.deliveries[d.ID] = d
+	}
+	return nil
+}
+
+// store writes the delivery to Storage, the mutex must be held
+func (s *Sender) store(d *Delivery) error {
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return s.cfg.Storage.Set(s.deliveryKey(d.ID), raw, 0)
+}
+
+// storeOutbox writes the IDs of the deliveries to Storage, the mutex must be held
+func (s *Sender) storeOutbox() error {
+	ids := make([]string, 0, len(s.deliveries))
+	for id := range s.deliveries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	raw, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	return s.cfg.Storage.Set(s.outboxKey(), raw, 0)
+}
+
+// remove deletes the delivery from the outbox, the mutex must be held
+func (s *Sender) remove(id string) error {
+	delete(s.deliveries, id)
+	if err := s.storeOutbox(); err != nil {
+		return err
+	}
+	return s.cfg.Storage.Delete(s.deliveryKey(id))
+}
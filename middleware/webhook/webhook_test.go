@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/storage/memory"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// receiver starts an app receiving webhooks, it returns a client connected to it
+func receiver(t *testing.T, app *fiber.App) *fasthttp.Client {
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		utils.AssertEqual(t, nil, app.Listener(ln))
+	}()
+	t.Cleanup(func() {
+		utils.AssertEqual(t, nil, app.Shutdown())
+	})
+	return &fasthttp.Client{
+		Dial: func(string) (net.Conn, error) {
+			return ln.Dial()
+		},
+	}
+}
+
+// waitFor waits until the condition is true
+func waitFor(t *testing.T, condition func() bool) {
+	for deadline := time.Now().Add(2 * time.Second); !condition() && time.Now().Before(deadline); {
+		time.Sleep(5 * time.Millisecond)
+	}
+	utils.AssertEqual(t, true, condition())
+}
+
+// go test -run Test_Webhook_Deliver
+func Test_Webhook_Deliver(t *testing.T) {
+	t.Parallel()
+	var mutex sync.Mutex
+	var received []string
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post("/hooks", Verify(time.Minute, "secret"), func(c *fiber.Ctx) error {
+		mutex.Lock()
+		received = append(received, c.Get(HeaderEvent)+" "+c.Get(HeaderID)+" "+string(c.Body()))
+		mutex.Unlock()
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	s := New(Config{
+		Endpoints: []Endpoint{
+			{ID: "orders", URL: "http://receiver/hooks", Secret: "secret", Events: []string{"order.created"}},
+			{ID: "users", URL: "http://receiver/hooks", Secret: "secret", Events: []string{"user.created"}},
+		},
+		Client: receiver(t, app),
+	})
+	defer s.Close()
+
+	id, err := s.Send("order.created", fiber.Map{"id": 1})
+	utils.AssertEqual(t, nil, err)
+	waitFor(t, func() bool {
+		return len(s.Deliveries("")) == 0
+	})
+
+	mutex.Lock()
+	utils.AssertEqual(t, []string{"order.created " + id + ` {"id":1}`}, received)
+	mutex.Unlock()
+
+	endpoints := s.Endpoints()
+	utils.AssertEqual(t, 2, len(endpoints))
+	utils.AssertEqual(t, uint64(1), endpoints[0].Delivered)
+	utils.AssertEqual(t, false, endpoints[0].LastSuccess.IsZero())
+	utils.AssertEqual(t, uint64(0), endpoints[1].Delivered)
+}
+
+// go test -run Test_Webhook_Retry
+func Test_Webhook_Retry(t *testing.T) {
+	t.Parallel()
+	var attempts, healthy int32
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post("/hooks", func(c *fiber.Ctx) error {
+		atomic.AddInt32(&attempts, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			return c.SendStatus(fiber.StatusServiceUnavailable)
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	var failures int32
+	s := New(Config{
+		Endpoints:    []Endpoint{{ID: "a", URL: "http://receiver/hooks", Secret: "secret"}},
+		MaxAttempts:  3,
+		Backoff:      5 * time.Millisecond,
+		PollInterval: 5 * time.Millisecond,
+		Client:       receiver(t, app),
+		OnFailure: func(d Delivery) {
+			atomic.AddInt32(&failures, 1)
+		},
+	})
+	defer s.Close()
+
+	_, err := s.Send("ping", []byte(`{}`))
+	utils.AssertEqual(t, nil, err)
+	waitFor(t, func() bool {
+		return atomic.LoadInt32(&failures) == 1
+	})
+
+	failed := s.Deliveries(StatusFailed)
+	utils.AssertEqual(t, 1, len(failed))
+	utils.AssertEqual(t, 3, failed[0].Attempts)
+	utils.AssertEqual(t, fiber.StatusServiceUnavailable, failed[0].LastStatusCode)
+	utils.AssertEqual(t, int32(3), atomic.LoadInt32(&attempts))
+	utils.AssertEqual(t, 3, s.Endpoints()[0].ConsecutiveFailures)
+	utils.AssertEqual(t, 1, s.Endpoints()[0].Failed)
+
+	// Retry the failed delivery by the management endpoint
+	admin := fiber.New()
+	s.Routes(admin)
+	atomic.StoreInt32(&healthy, 1)
+	resp, err := admin.Test(httptest.NewRequest(fiber.MethodPost, "/deliveries/"+failed[0].ID+"/retry", nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusNoContent, resp.StatusCode)
+	waitFor(t, func() bool {
+		return len(s.Deliveries("")) == 0
+	})
+	utils.AssertEqual(t, 0, s.Endpoints()[0].ConsecutiveFailures)
+
+	resp, err = admin.Test(httptest.NewRequest(fiber.MethodDelete, "/deliveries/"+failed[0].ID, nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// go test -run Test_Webhook_Storage
+func Test_Webhook_Storage(t *testing.T) {
+	t.Parallel()
+	var attempts int32
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post("/hooks", func(c *fiber.Ctx) error {
+		atomic.AddInt32(&attempts, 1)
+		return c.SendStatus(fiber.StatusInternalServerError)
+	})
+	client := receiver(t, app)
+	storage := memory.New()
+	config := Config{
+		Endpoints:    []Endpoint{{ID: "a", URL: "http://receiver/hooks"}},
+		Storage:      storage,
+		Backoff:      time.Hour,
+		PollInterval: 5 * time.Millisecond,
+		Client:       client,
+	}
+
+	s := New(config)
+	_, err := s.Send("ping", nil)
+	utils.AssertEqual(t, nil, err)
+	waitFor(t, func() bool {
+		return atomic.LoadInt32(&attempts) == 1 && s.Deliveries("")[0].Attempts == 1
+	})
+	s.Close()
+
+	// The pending delivery is loaded by a new sender
+	s = New(config)
+	defer s.Close()
+	deliveries := s.Deliveries(StatusPending)
+	utils.AssertEqual(t, 1, len(deliveries))
+	utils.AssertEqual(t, 1, deliveries[0].Attempts)
+	utils.AssertEqual(t, "null", string(deliveries[0].Payload))
+	utils.AssertEqual(t, 1, s.Endpoints()[0].Pending)
+
+	admin := fiber.New()
+	s.Routes(admin)
+	resp, err := admin.Test(httptest.NewRequest(fiber.MethodDelete, "/deliveries/"+deliveries[0].ID, nil))
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, fiber.StatusNoContent, resp.StatusCode)
+	utils.AssertEqual(t, 0, len(s.Deliveries("")))
+
+	raw, err := storage.Get("webhook_outbox")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "[]", string(raw))
+}
+
+// go test -run Test_Webhook_Verify
+func Test_Webhook_Verify(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Post("/", Verify(time.Minute, "old", "new"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	test := func(secret string, timestamp time.Time) int {
+		ts := timestamp.Unix()
+		req := httptest.NewRequest(fiber.MethodPost, "/", nil)
+		req.Header.Set(HeaderID, "1")
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+		req.Header.Set(HeaderSignature, "v1=invalid, "+Sign(secret, "1", ts, nil))
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err)
+		return resp.StatusCode
+	}
+
+	utils.AssertEqual(t, fiber.StatusNoContent, test("new", time.Now()))
+	utils.AssertEqual(t, fiber.StatusNoContent, test("old", time.Now()))
+	utils.AssertEqual(t, fiber.StatusUnauthorized, test("other", time.Now()))
+	utils.AssertEqual(t, fiber.StatusUnauthorized, test("new", time.Now().Add(-time.Hour)))
+}
@@ -0,0 +1,72 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2/internal/msgp"
+)
+
+// mimeApplicationXMsgPack is the unregistered MessagePack content type,
+// which is still common
+const mimeApplicationXMsgPack = "application/x-msgpack"
+
+// MsgPack encodes the data as MessagePack with the MsgPackEncoder of the
+// config and sets the content header to application/msgpack.
+// BodyParser decodes MessagePack request bodies accordingly.
+func (c *Ctx) MsgPack(data interface{}) error {
+	raw, err := c.app.config.MsgPackEncoder(data)
+	if err != nil {
+		return err
+	}
+	c.fasthttp.Response.SetBodyRaw(raw)
+	c.fasthttp.Response.Header.SetContentType(MIMEApplicationMsgPack)
+	return nil
+}
+
+// msgpackMarshal is the default MsgPackEncoder. It encodes the types generated
+// by github.com/tinylib/msgp, maps with string keys, slices and basic types.
+func msgpackMarshal(v interface{}) ([]byte, error) {
+	if m, ok := v.(msgp.Marshaler); ok {
+		return m.MarshalMsg(nil)
+	}
+	// The writer supports named map and slice types like Map by reflection
+	var buf bytes.Buffer
+	w := msgp.NewWriter(&buf)
+	if err := w.WriteIntf(v); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// msgpackUnmarshal is the default MsgPackDecoder. It decodes into the types
+// generated by github.com/tinylib/msgp, *interface{} and *map[string]interface{}.
+func msgpackUnmarshal(data []byte, v interface{}) error {
+	switch out := v.(type) {
+	case msgp.Unmarshaler:
+		_, err := out.UnmarshalMsg(data)
+		return err
+	case *interface{}:
+		val, _, err := msgp.ReadIntfBytes(data)
+		if err != nil {
+			return err
+		}
+		*out = val
+		return nil
+	case *map[string]interface{}:
+		val, _, err := msgp.ReadMapStrIntfBytes(data, *out)
+		if err != nil {
+			return err
+		}
+		*out = val
+		return nil
+	}
+	return fmt.Errorf("failed to decode msgpack into %T: generate its codec with github.com/tinylib/msgp or set Config.MsgPackDecoder", v)
+}
@@ -0,0 +1,104 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/internal/msgp"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// msgpackUser implements the codec generated by github.com/tinylib/msgp
+type msgpackUser struct {
+	Name string
+}
+
+func (u *msgpackUser) MarshalMsg(b []byte) ([]byte, error) {
+	b = msgp.AppendMapHeader(b, 1)
+	b = msgp.AppendString(b, "name")
+	return msgp.AppendString(b, u.Name), nil
+}
+
+func (u *msgpackUser) UnmarshalMsg(b []byte) ([]byte, error) {
+	m, o, err := msgp.ReadMapStrIntfBytes(b, nil)
+	if err != nil {
+		return o, err
+	}
+	u.Name, _ = m["name"].(string)
+	return o, nil
+}
+
+// go test -run Test_Ctx_MsgPack
+func Test_Ctx_MsgPack(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	utils.AssertEqual(t, nil, c.MsgPack(&msgpackUser{Name: "john"}))
+	utils.AssertEqual(t, MIMEApplicationMsgPack, string(c.Response().Header.ContentType()))
+	utils.AssertEqual(t, "\x81\xa4name\xa4john", string(c.Response().Body()))
+
+	utils.AssertEqual(t, nil, c.MsgPack(Map{"name": "john"}))
+	utils.AssertEqual(t, "\x81\xa4name\xa4john", string(c.Response().Body()))
+
+	utils.AssertEqual(t, nil, c.MsgPack([]int{1, 2}))
+	utils.AssertEqual(t, "\x92\x01\x02", string(c.Response().Body()))
+
+	// Structs need a generated codec or a custom encoder
+	utils.AssertEqual(t, true, c.MsgPack(struct{ Name string }{"john"}) != nil)
+
+	// Custom encoder
+	app = New(Config{
+		MsgPackEncoder: func(v interface{}) ([]byte, error) {
+			return nil, errors.New("custom")
+		},
+	})
+	c2 := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c2)
+	utils.AssertEqual(t, "custom", c2.MsgPack(Map{}).Error())
+}
+
+// go test -run Test_Ctx_BodyParser_MsgPack
+func Test_Ctx_BodyParser_MsgPack(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().Header.SetContentType(MIMEApplicationMsgPack)
+	c.Request().SetBody([]byte("\x81\xa4name\xa4john"))
+
+	user := new(msgpackUser)
+	utils.AssertEqual(t, nil, c.BodyParser(user))
+	utils.AssertEqual(t, "john", user.Name)
+
+	var m map[string]interface{}
+	utils.AssertEqual(t, nil, c.BodyParser(&m))
+	utils.AssertEqual(t, "john", m["name"])
+
+	c.Request().Header.SetContentType("application/x-msgpack")
+	var v interface{}
+	utils.AssertEqual(t, nil, c.BodyParser(&v))
+	utils.AssertEqual(t, map[string]interface{}{"name": "john"}, v)
+
+	utils.AssertEqual(t, true, c.BodyParser(&struct{ Name string }{}) != nil)
+}
+
+// go test -run Test_Ctx_Format_MsgPack
+func Test_Ctx_Format_MsgPack(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().Header.Set(HeaderAccept, MIMEApplicationMsgPack)
+	utils.AssertEqual(t, nil, c.Format([]string{"john"}))
+	utils.AssertEqual(t, MIMEApplicationMsgPack, string(c.Response().Header.ContentType()))
+	utils.AssertEqual(t, "\x91\xa4john", string(c.Response().Body()))
+}
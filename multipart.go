@@ -0,0 +1,401 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/internal/bytebufferpool"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// MultipartConfig defines the limits which are enforced while a multipart
+// form is parsed by c.MultipartForm, c.FormFile and c.BodyParser.
+// The global BodyLimit is too blunt for forms mixing small fields and big files.
+//
+// Enabling any of the limits disables the pre-parsing of multipart forms
+// by the server, see Config.DisablePreParseMultipartForm.
+type MultipartConfig struct {
+	// MaxFieldSize is the maximum size in bytes of a single form value.
+	// Zero means unlimited.
+	//
+	// Default: 0
+	MaxFieldSize int64 `json:"max_field_size"`
+
+	// MaxFileSize is the maximum size in bytes of a single uploaded file.
+	// Zero means unlimited.
+	//
+	// Default: 0
+	MaxFileSize int64 `json:"max_file_size"`
+
+	// FieldLimits overrides MaxFieldSize and MaxFileSize for the given form fields.
+	//
+	// Default: nil
+	FieldLimits map[string]int64 `json:"field_limits"`
+
+	// MaxTotalSize is the maximum size in bytes of all form values and
+	// files together. Zero means unlimited.
+	//
+	// Default: 0
+	MaxTotalSize int64 `json:"max_total_size"`
+
+	// AllowedTypes restricts the Content-Type of uploaded files per form field.
+	// The key "*" applies to all fields without an own entry.
+	// Types may contain wildcards, e.g. "image/*".
+	//
+	// Default: nil
+	AllowedTypes map[string][]string `json:"allowed_types"`
+
+	// SniffContent verifies the content of uploaded files by their magic bytes,
+	// so that e.g. image-only endpoints stop accepting renamed executables.
+	// The type is detected by http.DetectContentType from the first 512 bytes.
+	// If AllowedTypes has an entry for the field, the detected type must be allowed.
+	// Otherwise it must belong to the same top-level type as the declared
+	// Content-Type, plain text is accepted for all but image, audio, video and font files.
+	//
+	// Default: false
+	SniffContent bool `json:"sniff_content"`
+
+	// FileWriter enables the streaming mode. The content of each uploaded
+	// file is streamed into the returned writer instead of being buffered
+	// in memory, the writer is closed afterwards if it implements io.Closer.
+	// If the form fails, a *MultipartStreamError reports the written files.
+	// The parsed form only contains the file names, headers and sizes,
+	// so fh.Open() can't be used to read the content.
+	//
+	// Combined with StreamRequestBody, this allows uploads much larger
+	// than the available memory:
+	//
+	//	FileWriter: func(c *fiber.Ctx, fh *multipart.FileHeader) (io.Writer, error) {
+	//		return os.Create(filepath.Join("./uploads", filepath.Base(fh.Filename)))
+	//	}
+	//
+	// Default: nil
+	FileWriter func(c *Ctx, fh *multipart.FileHeader) (io.Writer, error) `json:"-"`
+}
+
+// sniffLen is the amount of bytes considered by http.DetectContentType
+const sniffLen = 512
+
+// MultipartError is returned when a part of a multipart form violates the
+// MultipartConfig. It names the offending form field and unwraps to an *Error
+// carrying the status code, so the ErrorHandler responds with either
+// 413 Request Entity Too Large or 415 Unsupported Media Type.
+type MultipartError struct {
+	Code    int    `json:"code"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	// DetectedType is the sniffed content type, if the file content
+	// didn't match the declared or allowed content types
+	DetectedType string `json:"detected_type,omitempty"`
+}
+
+// Error makes it compatible with the `error` interface.
+func (e *MultipartError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the *Error with the status code of the multipart error.
+func (e *MultipartError) Unwrap() error {
+	return NewError(e.Code, e.Message)
+}
+
+// MultipartStreamError is returned in the streaming mode, if the multipart
+// form fails after FileWriter returned writers. The writers have been closed,
+// the files can be removed with the reported file headers. It unwraps to the
+// cause, e.g. a *MultipartError.
+type MultipartStreamError struct {
+	// Files are the files, which have been written completely
+	Files []*multipart.FileHeader `json:"-"`
+	// Failed is the file, whose writer failed or was incomplete, if any
+	Failed *multipart.FileHeader `json:"-"`
+	Err    error                 `json:"-"`
+}
+
+// Error makes it compatible with the `error` interface.
+func (e *MultipartStreamError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *MultipartStreamError) Unwrap() error {
+	return e.Err
+}
+
+// enabled reports whether any limit is configured
+func (cfg *MultipartConfig) enabled() bool {
+	return cfg.MaxFieldSize > 0 || cfg.MaxFileSize > 0 || cfg.MaxTotalSize > 0 ||
+		len(cfg.FieldLimits) > 0 || len(cfg.AllowedTypes) > 0 || cfg.SniffContent || cfg.FileWriter != nil
+}
+
+// limit returns the size limit for the given field
+func (cfg *MultipartConfig) limit(field string, isFile bool) int64 {
+	if limit, ok := cfg.FieldLimits[field]; ok {
+		return limit
+	}
+	if isFile {
+		return cfg.MaxFileSize
+	}
+	return cfg.MaxFieldSize
+}
+
+// allowedTypes returns the allowed content types for the given file field
+func (cfg *MultipartConfig) allowedTypes(field string) []string {
+	if types, ok := cfg.AllowedTypes[field]; ok {
+		return types
+	}
+	return cfg.AllowedTypes["*"]
+}
+
+// matchMIME checks if the content type matches any of the allowed types
+func matchMIME(ctype string, allowed []string) bool {
+	ctype = mediaType(ctype)
+	for _, typ := range allowed {
+		typ = utils.ToLower(typ)
+		if typ == "*/*" || typ == ctype {
+			return true
+		}
+		if strings.HasSuffix(typ, "/*") && strings.HasPrefix(ctype, typ[:len(typ)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaType returns the lowercase media type without parameters
+func mediaType(ctype string) string {
+	if i := strings.IndexByte(ctype, ';'); i != -1 {
+		ctype = ctype[:i]
+	}
+	return utils.ToLower(utils.Trim(ctype, ' '))
+}
+
+// sniffMatches checks whether the detected content type of a file is
+// compatible with the declared content type or the allowed types
+func sniffMatches(declared, detected string, allowed []string) bool {
+	if len(allowed) > 0 {
+		return matchMIME(detected, allowed)
+	}
+	declared, detected = mediaType(declared), mediaType(detected)
+	if declared == "" || declared == detected {
+		return true
+	}
+	declaredTop, detectedTop := declared, detected
+	if i := strings.IndexByte(declared, '/'); i != -1 {
+		declaredTop = declared[:i]
+	}
+	if i := strings.IndexByte(detected, '/'); i != -1 {
+		detectedTop = detected[:i]
+	}
+	if declaredTop == detectedTop {
+		return true
+	}
+	// The sniffer can't distinguish between textual formats like csv, json or yaml
+	if detected == MIMETextPlain {
+		switch declaredTop {
+		case "image", "audio", "video", "font":
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// multipartLimitReader fails as soon as the field or total limit is exceeded
+type multipartLimitReader struct {
+	r          io.Reader
+	field      string
+	limit      int64
+	read       int64
+	total      *int64
+	totalLimit int64
+}
+
+func (lr *multipartLimitReader) Read(p []byte) (int, error) {
+	n, err := lr.r.Read(p)
+	lr.read += int64(n)
+	*lr.total += int64(n)
+	if lr.limit > 0 && lr.read > lr.limit {
+		return n, &MultipartError{
+			Code:    StatusRequestEntityTooLarge,
+			Field:   lr.field,
+			Message: fmt.Sprintf("multipart: field %q exceeds the limit of %d bytes", lr.field, lr.limit),
+		}
+	}
+	if lr.totalLimit > 0 && *lr.total > lr.totalLimit {
+		return n, &MultipartError{
+			Code:    StatusRequestEntityTooLarge,
+			Field:   lr.field,
+			Message: fmt.Sprintf("multipart: form exceeds the limit of %d bytes at field %q", lr.totalLimit, lr.field),
+		}
+	}
+	return n, err
+}
+
+// readMultipart walks all parts of the multipart body and enforces the MultipartConfig.
+// fn is called for every part with a reader, which fails as soon as a limit is exceeded.
+func (c *Ctx) readMultipart(body io.Reader, fn func(part *multipart.Part, r io.Reader) error) error {
+	boundary := c.fasthttp.Request.Header.MultipartFormBoundary()
+	if len(boundary) == 0 {
+		return fasthttp.ErrNoMultipartForm
+	}
+	cfg := &c.app.config.Multipart
+	mr := multipart.NewReader(body, c.app.getString(boundary))
+	var total int64
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := part.FormName()
+		isFile := part.FileName() != ""
+		if isFile {
+			if allowed := cfg.allowedTypes(name); len(allowed) > 0 && !matchMIME(part.Header.Get(HeaderContentType), allowed) {
+				return &MultipartError{
+					Code:    StatusUnsupportedMediaType,
+					Field:   name,
+					Message: fmt.Sprintf("multipart: unsupported content type %q for field %q", part.Header.Get(HeaderContentType), name),
+				}
+			}
+		}
+		var pr io.Reader = part
+		if isFile && cfg.SniffContent {
+			br := bufio.NewReaderSize(part, sniffLen)
+			// Peek returns the available bytes for files smaller than sniffLen
+			head, err := br.Peek(sniffLen)
+			if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+				return err
+			}
+			declared := part.Header.Get(HeaderContentType)
+			if detected := http.DetectContentType(head); !sniffMatches(declared, detected, cfg.allowedTypes(name)) {
+				return &MultipartError{
+					Code:         StatusUnsupportedMediaType,
+					Field:        name,
+					Message:      fmt.Sprintf("multipart: content of field %q doesn't match the content type %q", name, declared),
+					DetectedType: detected,
+				}
+			}
+			pr = br
+		}
+		r := &multipartLimitReader{
+			r:          pr,
+			field:      name,
+			limit:      cfg.limit(name, isFile),
+			total:      &total,
+			totalLimit: cfg.MaxTotalSize,
+		}
+		if err = fn(part, r); err != nil {
+			return err
+		}
+	}
+}
+
+// checkMultipartForm validates every part of the multipart form against the MultipartConfig
+func (c *Ctx) checkMultipartForm() error {
+	if c.multipartChecked {
+		return nil
+	}
+	// A streamed body is validated while it's buffered, so that it's rejected
+	// as soon as a limit is exceeded instead of after reading it completely
+	var body io.Reader
+	var buf *bytebufferpool.ByteBuffer
+	if stream := c.fasthttp.RequestBodyStream(); stream != nil {
+		buf = bytebufferpool.Get()
+		defer bytebufferpool.Put(buf)
+		body = io.TeeReader(stream, buf)
+	} else {
+		body = bytes.NewReader(c.fasthttp.Request.Body())
+	}
+	err := c.readMultipart(body, func(_ *multipart.Part, r io.Reader) error {
+		_, err := io.Copy(ioutil.Discard, r)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if buf != nil {
+		// Buffer the epilogue and parse the form from the buffered body
+		if _, err = io.Copy(ioutil.Discard, body); err != nil {
+			return err
+		}
+		c.fasthttp.Request.SetBody(buf.B)
+	}
+	c.multipartChecked = true
+	return nil
+}
+
+// streamMultipartForm parses the multipart form in a single pass and streams
+// all files into the writers returned by MultipartConfig.FileWriter
+func (c *Ctx) streamMultipartForm() (*multipart.Form, error) {
+	if c.multipartForm != nil {
+		return c.multipartForm, nil
+	}
+	var body io.Reader
+	if stream := c.fasthttp.RequestBodyStream(); stream != nil {
+		body = stream
+	} else {
+		body = bytes.NewReader(c.fasthttp.Request.Body())
+	}
+	form := &multipart.Form{
+		Value: make(map[string][]string),
+		File:  make(map[string][]*multipart.FileHeader),
+	}
+	// The written files are reported on errors, so that they can be removed
+	var written []*multipart.FileHeader
+	var failed *multipart.FileHeader
+	err := c.readMultipart(body, func(part *multipart.Part, r io.Reader) error {
+		name := part.FormName()
+		if part.FileName() == "" {
+			value, err := ioutil.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			form.Value[name] = append(form.Value[name], string(value))
+			return nil
+		}
+		fh := &multipart.FileHeader{
+			Filename: part.FileName(),
+			Header:   part.Header,
+		}
+		w, err := c.app.config.Multipart.FileWriter(c, fh)
+		if err == nil {
+			fh.Size, err = io.Copy(w, r)
+		}
+		// The writer is closed, even if the FileWriter returned it with an error
+		if closer, ok := w.(io.Closer); ok {
+			if cerr := closer.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			if w != nil {
+				failed = fh
+			}
+			return err
+		}
+		written = append(written, fh)
+		form.File[name] = append(form.File[name], fh)
+		return nil
+	})
+	if err != nil {
+		if len(written) > 0 || failed != nil {
+			err = &MultipartStreamError{Files: written, Failed: failed, Err: err}
+		}
+		return nil, err
+	}
+	c.multipartForm = form
+	return form, nil
+}
@@ -0,0 +1,358 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+type testMultipartFile struct {
+	field, filename, ctype, content string
+}
+
+func newTestMultipartBody(t *testing.T, values map[string]string, files ...testMultipartFile) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for k, v := range values {
+		utils.AssertEqual(t, nil, writer.WriteField(k, v))
+	}
+	for _, f := range files {
+		h := make(textproto.MIMEHeader)
+		h.Set(HeaderContentDisposition, `form-data; name="`+f.field+`"; filename="`+f.filename+`"`)
+		h.Set(HeaderContentType, f.ctype)
+		w, err := writer.CreatePart(h)
+		utils.AssertEqual(t, nil, err)
+		_, err = w.Write([]byte(f.content))
+		utils.AssertEqual(t, nil, err)
+	}
+	utils.AssertEqual(t, nil, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func testMultipart(t *testing.T, app *App, values map[string]string, files ...testMultipartFile) (int, string) {
+	body, ctype := newTestMultipartBody(t, values, files...)
+	req := httptest.NewRequest(MethodPost, "/", body)
+	req.Header.Set(HeaderContentType, ctype)
+	req.Header.Set(HeaderContentLength, strconv.Itoa(body.Len()))
+
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err)
+	b, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	return resp.StatusCode, string(b)
+}
+
+// go test -run Test_Ctx_MultipartForm_Limits
+func Test_Ctx_MultipartForm_Limits(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		Multipart: MultipartConfig{
+			MaxFieldSize: 5,
+			MaxFileSize:  10,
+			FieldLimits:  map[string]int64{"bio": 20},
+		},
+	})
+	app.Post("/", func(c *Ctx) error {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return err
+		}
+		return c.SendString(strconv.Itoa(len(form.Value)) + "-" + strconv.Itoa(len(form.File)))
+	})
+
+	code, body := testMultipart(t, app, map[string]string{"name": "john", "bio": "a longer biography"},
+		testMultipartFile{"avatar", "a.png", "image/png", "0123456789"})
+	utils.AssertEqual(t, StatusOK, code)
+	utils.AssertEqual(t, "2-1", body)
+
+	code, body = testMultipart(t, app, map[string]string{"name": "johnny"})
+	utils.AssertEqual(t, StatusRequestEntityTooLarge, code)
+	utils.AssertEqual(t, `multipart: field "name" exceeds the limit of 5 bytes`, body)
+
+	code, body = testMultipart(t, app, nil, testMultipartFile{"avatar", "a.png", "image/png", "01234567890"})
+	utils.AssertEqual(t, StatusRequestEntityTooLarge, code)
+	utils.AssertEqual(t, `multipart: field "avatar" exceeds the limit of 10 bytes`, body)
+}
+
+// go test -run Test_Ctx_MultipartForm_AllowedTypes
+func Test_Ctx_MultipartForm_AllowedTypes(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		Multipart: MultipartConfig{
+			AllowedTypes: map[string][]string{
+				"avatar": {"image/*"},
+				"*":      {MIMETextPlain},
+			},
+		},
+	})
+	app.Post("/", func(c *Ctx) error {
+		_, err := c.FormFile("avatar")
+		var merr *MultipartError
+		if errors.As(err, &merr) {
+			return c.Status(merr.Code).SendString(merr.Field)
+		}
+		return err
+	})
+
+	code, _ := testMultipart(t, app, nil,
+		testMultipartFile{"avatar", "a.png", "image/png", "png"},
+		testMultipartFile{"notes", "n.txt", "text/plain; charset=utf-8", "txt"})
+	utils.AssertEqual(t, StatusOK, code)
+
+	code, body := testMultipart(t, app, nil, testMultipartFile{"avatar", "a.exe", "application/octet-stream", "MZ"})
+	utils.AssertEqual(t, StatusUnsupportedMediaType, code)
+	utils.AssertEqual(t, "avatar", body)
+
+	code, body = testMultipart(t, app, nil, testMultipartFile{"notes", "n.html", MIMETextHTML, "<p>"})
+	utils.AssertEqual(t, StatusUnsupportedMediaType, code)
+	utils.AssertEqual(t, "notes", body)
+}
+
+type testCountingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *testCountingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	return n, err
+}
+
+// go test -run Test_Ctx_MultipartForm_StreamLimits
+func Test_Ctx_MultipartForm_StreamLimits(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		StreamRequestBody: true,
+		Multipart:         MultipartConfig{MaxFileSize: 1024},
+	})
+	test := func(content string) (*testCountingReader, *multipart.Form, error) {
+		body, ctype := newTestMultipartBody(t, map[string]string{"name": "john"},
+			testMultipartFile{"doc", "a.txt", MIMETextPlain, content})
+		stream := &testCountingReader{r: body}
+		fctx := &fasthttp.RequestCtx{}
+		fctx.Request.Header.SetContentType(ctype)
+		fctx.Request.SetBodyStream(stream, body.Len())
+		c := app.AcquireCtx(fctx)
+		defer app.ReleaseCtx(c)
+		form, err := c.MultipartForm()
+		return stream, form, err
+	}
+
+	// The body is rejected before it's read completely
+	stream, _, err := test(strings.Repeat("a", 1<<20))
+	var merr *MultipartError
+	utils.AssertEqual(t, true, errors.As(err, &merr))
+	utils.AssertEqual(t, StatusRequestEntityTooLarge, merr.Code)
+	utils.AssertEqual(t, true, stream.n < 64<<10)
+
+	// The valid form is parsed from the buffered body
+	_, form, err := test("hello world")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, []string{"john"}, form.Value["name"])
+	utils.AssertEqual(t, "a.txt", form.File["doc"][0].Filename)
+	utils.AssertEqual(t, int64(11), form.File["doc"][0].Size)
+}
+
+// go test -run Test_Ctx_BodyParser_MultipartLimits
+func Test_Ctx_BodyParser_MultipartLimits(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		Multipart: MultipartConfig{MaxFieldSize: 4},
+	})
+	type Demo struct {
+		Name string `form:"name"`
+	}
+	app.Post("/", func(c *Ctx) error {
+		d := new(Demo)
+		if err := c.BodyParser(d); err != nil {
+			return err
+		}
+		return c.SendString(d.Name)
+	})
+
+	code, body := testMultipart(t, app, map[string]string{"name": "john"})
+	utils.AssertEqual(t, StatusOK, code)
+	utils.AssertEqual(t, "john", body)
+
+	code, _ = testMultipart(t, app, map[string]string{"name": "johnny"})
+	utils.AssertEqual(t, StatusRequestEntityTooLarge, code)
+}
+
+// go test -run Test_MatchMIME
+func Test_MatchMIME(t *testing.T) {
+	t.Parallel()
+	utils.AssertEqual(t, true, matchMIME("image/png", []string{"image/*"}))
+	utils.AssertEqual(t, true, matchMIME("Text/Plain; charset=utf-8", []string{"text/plain"}))
+	utils.AssertEqual(t, true, matchMIME("application/pdf", []string{"*/*"}))
+	utils.AssertEqual(t, false, matchMIME("imagepng", []string{"image/*"}))
+	utils.AssertEqual(t, false, matchMIME("", []string{"image/png"}))
+}
+
+// go test -run Test_Ctx_MultipartForm_FileWriter
+func Test_Ctx_MultipartForm_FileWriter(t *testing.T) {
+	t.Parallel()
+	for _, stream := range []bool{false, true} {
+		var files bytes.Buffer
+		app := New(Config{
+			StreamRequestBody: stream,
+			Multipart: MultipartConfig{
+				MaxTotalSize: 30,
+				FileWriter: func(c *Ctx, fh *multipart.FileHeader) (io.Writer, error) {
+					files.WriteString(fh.Filename + ":")
+					return &files, nil
+				},
+			},
+		})
+		type Demo struct {
+			Name string `form:"name"`
+		}
+		app.Post("/", func(c *Ctx) error {
+			d := new(Demo)
+			if err := c.BodyParser(d); err != nil {
+				return err
+			}
+			fh, err := c.FormFile("doc")
+			if err != nil {
+				return err
+			}
+			return c.SendString(d.Name + "-" + fh.Filename + "-" + strconv.FormatInt(fh.Size, 10))
+		})
+
+		code, body := testMultipart(t, app, map[string]string{"name": "john"},
+			testMultipartFile{"doc", "a.txt", MIMETextPlain, "hello world"})
+		utils.AssertEqual(t, StatusOK, code)
+		utils.AssertEqual(t, "john-a.txt-11", body)
+		utils.AssertEqual(t, "a.txt:hello world", files.String())
+
+		code, body = testMultipart(t, app, map[string]string{"name": "john"},
+			testMultipartFile{"doc", "b.txt", MIMETextPlain, "this file is too large for the form"})
+		utils.AssertEqual(t, StatusRequestEntityTooLarge, code)
+		utils.AssertEqual(t, `multipart: form exceeds the limit of 30 bytes at field "doc"`, body)
+	}
+}
+
+type testClosingWriter struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *testClosingWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+// go test -run Test_Ctx_MultipartForm_FileWriter_Error
+func Test_Ctx_MultipartForm_FileWriter_Error(t *testing.T) {
+	t.Parallel()
+	var writers []*testClosingWriter
+	app := New(Config{
+		Multipart: MultipartConfig{
+			MaxFileSize:  10,
+			AllowedTypes: map[string][]string{"*": {MIMETextPlain}},
+			FileWriter: func(c *Ctx, fh *multipart.FileHeader) (io.Writer, error) {
+				w := &testClosingWriter{}
+				writers = append(writers, w)
+				return w, nil
+			},
+		},
+	})
+	var streamErr *MultipartStreamError
+	app.Post("/", func(c *Ctx) error {
+		streamErr = nil
+		_, err := c.MultipartForm()
+		if errors.As(err, &streamErr) {
+			var merr *MultipartError
+			utils.AssertEqual(t, true, errors.As(err, &merr))
+			return c.Status(merr.Code).SendString(merr.Field)
+		}
+		return err
+	})
+
+	// The writer of the failed file is closed and reported with the written files
+	code, body := testMultipart(t, app, nil,
+		testMultipartFile{"a", "a.txt", MIMETextPlain, "hello"},
+		testMultipartFile{"b", "b.txt", MIMETextPlain, "this file is too large"})
+	utils.AssertEqual(t, StatusRequestEntityTooLarge, code)
+	utils.AssertEqual(t, "b", body)
+	utils.AssertEqual(t, 2, len(writers))
+	utils.AssertEqual(t, true, writers[0].closed)
+	utils.AssertEqual(t, true, writers[1].closed)
+	utils.AssertEqual(t, 1, len(streamErr.Files))
+	utils.AssertEqual(t, "a.txt", streamErr.Files[0].Filename)
+	utils.AssertEqual(t, "b.txt", streamErr.Failed.Filename)
+
+	// Parts rejected before their writer is returned aren't reported as failed
+	writers = nil
+	code, body = testMultipart(t, app, nil,
+		testMultipartFile{"a", "a.txt", MIMETextPlain, "hello"},
+		testMultipartFile{"b", "b.pdf", "application/pdf", "%PDF"})
+	utils.AssertEqual(t, StatusUnsupportedMediaType, code)
+	utils.AssertEqual(t, "b", body)
+	utils.AssertEqual(t, 1, len(writers))
+	utils.AssertEqual(t, true, writers[0].closed)
+	utils.AssertEqual(t, 1, len(streamErr.Files))
+	utils.AssertEqual(t, true, streamErr.Failed == nil)
+}
+
+// go test -run Test_Ctx_MultipartForm_SniffContent
+func Test_Ctx_MultipartForm_SniffContent(t *testing.T) {
+	t.Parallel()
+	png := "\x89PNG\x0D\x0A\x1A\x0A" + "image data"
+	app := New(Config{
+		Multipart: MultipartConfig{
+			SniffContent: true,
+			AllowedTypes: map[string][]string{"avatar": {"image/png", "image/jpeg"}},
+		},
+	})
+	app.Post("/", func(c *Ctx) error {
+		_, err := c.MultipartForm()
+		var merr *MultipartError
+		if errors.As(err, &merr) {
+			return c.Status(merr.Code).SendString(merr.Field + ":" + merr.DetectedType)
+		}
+		return err
+	})
+
+	code, _ := testMultipart(t, app, nil,
+		testMultipartFile{"avatar", "a.png", "image/png", png},
+		testMultipartFile{"doc", "a.csv", "text/csv", "a,b\n1,2"})
+	utils.AssertEqual(t, StatusOK, code)
+
+	// Renamed executable with an allowed content type
+	code, body := testMultipart(t, app, nil, testMultipartFile{"avatar", "a.png", "image/png", "MZ\x90\x00\x03\x00"})
+	utils.AssertEqual(t, StatusUnsupportedMediaType, code)
+	utils.AssertEqual(t, "avatar:"+MIMEOctetStream, body)
+
+	// Without an allowlist the content must match the declared type
+	code, body = testMultipart(t, app, nil, testMultipartFile{"doc", "a.pdf", "application/pdf", "<html><script>"})
+	utils.AssertEqual(t, StatusUnsupportedMediaType, code)
+	utils.AssertEqual(t, "doc:"+MIMETextHTMLCharsetUTF8, body)
+}
+
+// go test -run Test_SniffMatches
+func Test_SniffMatches(t *testing.T) {
+	t.Parallel()
+	utils.AssertEqual(t, true, sniffMatches("image/png", "image/png", nil))
+	utils.AssertEqual(t, true, sniffMatches("", MIMEOctetStream, nil))
+	utils.AssertEqual(t, true, sniffMatches("application/vnd.openxmlformats-officedocument.wordprocessingml.document", "application/zip", nil))
+	utils.AssertEqual(t, true, sniffMatches(MIMEApplicationJSON, MIMETextPlainCharsetUTF8, nil))
+	utils.AssertEqual(t, false, sniffMatches("image/png", MIMETextPlainCharsetUTF8, nil))
+	utils.AssertEqual(t, false, sniffMatches("image/gif", MIMEOctetStream, nil))
+	utils.AssertEqual(t, true, sniffMatches("image/png", "image/gif", []string{"image/*"}))
+	utils.AssertEqual(t, false, sniffMatches("image/png", MIMETextPlainCharsetUTF8, []string{"image/*"}))
+}
@@ -0,0 +1,179 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Offer is a format, which Ctx.Negotiate can respond with
+type Offer struct {
+	// Type is the MIME type of the format, e.g. "application/json"
+	Type string
+	// Render writes the value in the format including the content type
+	Render func(c *Ctx, v interface{}) error
+}
+
+// Offers of the built-in formats
+var (
+	OfferJSON = Offer{MIMEApplicationJSON, func(c *Ctx, v interface{}) error {
+		return c.JSON(v)
+	}}
+	OfferXML = Offer{MIMEApplicationXML, func(c *Ctx, v interface{}) error {
+		return c.XML(v)
+	}}
+	OfferMsgPack = Offer{MIMEApplicationMsgPack, func(c *Ctx, v interface{}) error {
+		return c.MsgPack(v)
+	}}
+	OfferCBOR = Offer{MIMEApplicationCBOR, func(c *Ctx, v interface{}) error {
+		return c.CBOR(v)
+	}}
+	// OfferText formats the value with fmt.Sprint
+	OfferText = Offer{MIMETextPlain, func(c *Ctx, v interface{}) error {
+		c.fasthttp.Response.Header.SetContentType(MIMETextPlainCharsetUTF8)
+		switch val := v.(type) {
+		case []byte:
+			return c.Send(val)
+		case string:
+			return c.SendString(val)
+		}
+		return c.SendString(fmt.Sprint(v))
+	}}
+)
+
+// defaultOffers are negotiated if no offers are passed to Negotiate
+var defaultOffers = []Offer{OfferJSON, OfferXML, OfferMsgPack, OfferCBOR, OfferText}
+
+// OfferHTML renders the value with the html/template snippet, see SendHTMLSafe.
+func OfferHTML(tmpl string) Offer {
+	return Offer{MIMETextHTML, func(c *Ctx, v interface{}) error {
+		return c.SendHTMLSafe(tmpl, v)
+	}}
+}
+
+// OfferView renders the value with the template of the Views engine, see Render.
+func OfferView(name string, layouts ...string) Offer {
+	return Offer{MIMETextHTML, func(c *Ctx, v interface{}) error {
+		return c.Render(name, v, layouts...)
+	}}
+}
+
+// Negotiate responds with the value in the offered format, which the client
+// prefers according to the q-values of the Accept header. Ties are resolved by
+// the more specific media range and then by the order of the offers, so the
+// first offer is used for "*/*" and a missing Accept header:
+//
+//	app.Get("/users/:id", func(c *fiber.Ctx) error {
+//		return c.Negotiate(user, fiber.OfferJSON, fiber.OfferXML, fiber.OfferHTML(`<h1>{{.Name}}</h1>`))
+//	})
+//
+// Without offers JSON, XML, MsgPack, CBOR and plain text are offered. If none of
+// the offers is acceptable, the offer of Config.NegotiationFallback is used or
+// ErrNotAcceptable is returned.
+func (c *Ctx) Negotiate(v interface{}, offers ...Offer) error {
+	if len(offers) == 0 {
+		offers = defaultOffers
+	}
+	c.Vary(HeaderAccept)
+
+	if offer, ok := negotiate(c.Get(HeaderAccept), offers); ok {
+		return offer.Render(c, v)
+	}
+	if fallback := c.app.config.NegotiationFallback; fallback != "" {
+		for _, offer := range offers {
+			if utils.EqualFold(offer.Type, fallback) {
+				return offer.Render(c, v)
+			}
+		}
+	}
+	return ErrNotAcceptable
+}
+
+// acceptSpec is a media range of the Accept header
+type acceptSpec struct {
+	typ, subtype string
+	quality      float64
+}
+
+// parseAccept returns the media ranges of the Accept header
+func parseAccept(header string) []acceptSpec {
+	specs := make([]acceptSpec, 0, strings.Count(header, ",")+1)
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		mediaRange := utils.ToLower(utils.Trim(params[0], ' '))
+		if mediaRange == "" {
+			continue
+		}
+		spec := acceptSpec{quality: 1}
+		if slash := strings.IndexByte(mediaRange, '/'); slash >= 0 {
+			spec.typ, spec.subtype = mediaRange[:slash], mediaRange[slash+1:]
+		} else {
+			// Some clients send "*" for "*/*"
+			spec.typ, spec.subtype = mediaRange, "*"
+		}
+		for _, param := range params[1:] {
+			param = utils.Trim(param, ' ')
+			if len(param) > 2 && (param[0] == 'q' || param[0] == 'Q') && param[1] == '=' {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil && q >= 0 && q <= 1 {
+					spec.quality = q
+				}
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// negotiate returns the acceptable offer with the highest quality
+func negotiate(header string, offers []Offer) (Offer, bool) {
+	if header == "" {
+		return offers[0], true
+	}
+	specs := parseAccept(header)
+
+	best, bestQuality, bestSpecificity := -1, 0.0, -1
+	for i, offer := range offers {
+		typ, subtype := utils.ToLower(offer.Type), ""
+		if slash := strings.IndexByte(typ, '/'); slash >= 0 {
+			typ, subtype = typ[:slash], typ[slash+1:]
+		}
+		if semi := strings.IndexByte(subtype, ';'); semi >= 0 {
+			subtype = utils.Trim(subtype[:semi], ' ')
+		}
+
+		// The most specific media range determines the quality of the offer
+		quality, specificity := 0.0, -1
+		for _, spec := range specs {
+			var s int
+			switch {
+			case spec.typ == typ && spec.subtype == subtype:
+				s = 2
+			case spec.typ == typ && spec.subtype == "*":
+				s = 1
+			case spec.typ == "*":
+				s = 0
+			default:
+				continue
+			}
+			if s > specificity {
+				quality, specificity = spec.quality, s
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		if quality > bestQuality || (quality == bestQuality && specificity > bestSpecificity) {
+			best, bestQuality, bestSpecificity = i, quality, specificity
+		}
+	}
+	if best < 0 {
+		return Offer{}, false
+	}
+	return offers[best], true
+}
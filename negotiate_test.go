@@ -0,0 +1,95 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Ctx_Negotiate
+func Test_Ctx_Negotiate(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	type user struct {
+		Name string `json:"name" xml:"name"`
+	}
+	offers := []Offer{OfferJSON, OfferXML, OfferHTML(`<h1>{{.Name}}</h1>`), OfferText}
+
+	for _, tc := range []struct {
+		accept string
+		ctype  string
+		body   string
+	}{
+		{"", MIMEApplicationJSON, `{"name":"john"}`},
+		{"*/*", MIMEApplicationJSON, `{"name":"john"}`},
+		{"application/xml", MIMEApplicationXML, `<user><name>john</name></user>`},
+		{"text/html, application/json;q=0.9", MIMETextHTMLCharsetUTF8, `<h1>john</h1>`},
+		{"text/html;q=0.5, application/xml;q=0.8, */*;q=0.1", MIMEApplicationXML, `<user><name>john</name></user>`},
+		// The more specific media range wins
+		{"text/*;q=0.3, text/plain;q=0.7, */*;q=0.2", MIMETextPlainCharsetUTF8, `{john}`},
+		{"text/*", MIMETextHTMLCharsetUTF8, `<h1>john</h1>`},
+		// Excluded formats
+		{"application/json;q=0, */*", MIMEApplicationXML, `<user><name>john</name></user>`},
+		{"APPLICATION/XML; Q=1", MIMEApplicationXML, `<user><name>john</name></user>`},
+	} {
+		c := app.AcquireCtx(&fasthttp.RequestCtx{})
+		c.Request().Header.Set(HeaderAccept, tc.accept)
+		utils.AssertEqual(t, nil, c.Negotiate(user{"john"}, offers...), tc.accept)
+		utils.AssertEqual(t, tc.ctype, string(c.Response().Header.ContentType()), tc.accept)
+		utils.AssertEqual(t, tc.body, string(c.Response().Body()), tc.accept)
+		utils.AssertEqual(t, HeaderAccept, string(c.Response().Header.Peek(HeaderVary)))
+		app.ReleaseCtx(c)
+	}
+
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	// Not acceptable
+	c.Request().Header.Set(HeaderAccept, "image/png")
+	utils.AssertEqual(t, ErrNotAcceptable, c.Negotiate(user{"john"}, offers...))
+
+	// Default offers
+	c.Request().Header.Set(HeaderAccept, MIMEApplicationMsgPack)
+	utils.AssertEqual(t, nil, c.Negotiate(Map{"name": "john"}))
+	utils.AssertEqual(t, MIMEApplicationMsgPack, string(c.Response().Header.ContentType()))
+	c.Request().Header.Set(HeaderAccept, MIMETextPlain)
+	utils.AssertEqual(t, nil, c.Negotiate("john"))
+	utils.AssertEqual(t, "john", string(c.Response().Body()))
+}
+
+// go test -run Test_Ctx_Negotiate_Fallback
+func Test_Ctx_Negotiate_Fallback(t *testing.T) {
+	t.Parallel()
+	app := New(Config{NegotiationFallback: MIMEApplicationJSON})
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().Header.Set(HeaderAccept, "image/png")
+	utils.AssertEqual(t, nil, c.Negotiate(Map{"name": "john"}, OfferXML, OfferJSON))
+	utils.AssertEqual(t, MIMEApplicationJSON, string(c.Response().Header.ContentType()))
+
+	// The fallback has to be offered
+	utils.AssertEqual(t, ErrNotAcceptable, c.Negotiate("john", OfferText))
+}
+
+// go test -v -run=^$ -bench=Benchmark_Ctx_Negotiate -benchmem -count=4
+func Benchmark_Ctx_Negotiate(b *testing.B) {
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	c.Request().Header.Set(HeaderAccept, "text/html;q=0.9, application/xml;q=0.8, */*;q=0.1")
+	var err error
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		err = c.Negotiate("john", OfferJSON, OfferXML, OfferText)
+	}
+	utils.AssertEqual(b, nil, err)
+	utils.AssertEqual(b, MIMEApplicationXML, string(c.Response().Header.ContentType()))
+}
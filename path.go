@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -67,6 +68,42 @@ type Constraint struct {
 	ID            TypeConstraint
 	RegexCompiler *regexp.Regexp
 	Data          []string
+
+	custom ConstraintFunc // check of a custom constraint, see RegisterConstraint
+}
+
+// ConstraintFunc reports if the param satisfies a custom route constraint,
+// data holds the arguments of the constraint, e.g. ["3"] for `<slug(3)>`.
+type ConstraintFunc func(param string, data []string) bool
+
+// customConstraints holds the constraints registered by RegisterConstraint
+var (
+	customConstraints   = make(map[string]ConstraintFunc)
+	customConstraintsMu sync.RWMutex
+)
+
+// RegisterConstraint registers a custom route constraint, which can be used
+// like the built-in ones:
+//
+//	fiber.RegisterConstraint("slug", func(param string, _ []string) bool {
+//		return slugPattern.MatchString(param)
+//	})
+//	app.Get("/posts/:slug<slug>", handler)
+//
+// Requests with a param, which doesn't satisfy the constraint, fall through to
+// the next route. RegisterConstraint is meant to be called during initialization,
+// before the routes are registered. Built-in constraints can't be overridden.
+func RegisterConstraint(name string, check ConstraintFunc) {
+	customConstraintsMu.Lock()
+	customConstraints[name] = check
+	customConstraintsMu.Unlock()
+}
+
+// customConstraint returns the custom constraint registered by the name
+func customConstraint(name string) ConstraintFunc {
+	customConstraintsMu.RLock()
+	defer customConstraintsMu.RUnlock()
+	return customConstraints[name]
 }
 
 const (
@@ -85,6 +122,7 @@ const (
 	maxConstraint
 	rangeConstraint
 	regexConstraint
+	customConstraintType
 )
 
 // list of possible parameter and segment delimiter
@@ -285,17 +323,24 @@ func (routeParser *routeParser) analyseParameterPart(pattern string) (string, *r
 					}
 				}
 
-				// Precompile regex if has regex constraint
+				// Precompile regex if has regex constraint, it has to match the whole param
 				if constraint.ID == regexConstraint {
-					constraint.RegexCompiler = regexp.MustCompile(constraint.Data[0])
+					constraint.RegexCompiler = regexp.MustCompile("^(?:" + constraint.Data[0] + ")$")
+				}
+				if constraint.ID == noConstraint {
+					constraint.setCustom(c[:start])
 				}
 
 				constraints = append(constraints, constraint)
 			} else {
-				constraints = append(constraints, &Constraint{
+				constraint := &Constraint{
 					ID:   getParamConstraintType(c),
 					Data: []string{},
-				})
+				}
+				if constraint.ID == noConstraint {
+					constraint.setCustom(c)
+				}
+				constraints = append(constraints, constraint)
 			}
 		}
 
@@ -437,10 +482,12 @@ func (routeParser *routeParser) getMatch(detectionPath, path string, params *[ma
 			// take over the params positions
 			params[paramsIterator] = path[:i]
 
-			// check constraint
-			for _, c := range segment.Constraints {
-				if matched := c.CheckConstraint(params[paramsIterator]); !matched {
-					return false
+			// check constraint, a missing optional param has nothing to check
+			if i > 0 || !segment.IsOptional {
+				for _, c := range segment.Constraints {
+					if matched := c.CheckConstraint(params[paramsIterator]); !matched {
+						return false
+					}
 				}
 			}
 
@@ -579,6 +626,13 @@ func getParamConstraintType(constraintPart string) TypeConstraint {
 
 }
 
+// setCustom turns the constraint into the custom one registered by the name, if any
+func (c *Constraint) setCustom(name string) {
+	if check := customConstraint(name); check != nil {
+		c.ID, c.custom = customConstraintType, check
+	}
+}
+
 func (c *Constraint) CheckConstraint(param string) bool {
 	var err error
 	var num int
@@ -668,6 +722,8 @@ func (c *Constraint) CheckConstraint(param string) bool {
 		if match := c.RegexCompiler.MatchString(param); !match {
 			return false
 		}
+	case customConstraintType:
+		return c.custom(param, c.Data)
 	}
 
 	return err == nil
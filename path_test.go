@@ -6,6 +6,7 @@ package fiber
 
 import (
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/gofiber/fiber/v2/utils"
@@ -525,6 +526,13 @@ func Test_Path_matchParams(t *testing.T) {
 		{url: "/api/v1/15", params: nil, match: false},
 		{url: "/api/v1/peach", params: []string{"peach"}, match: true},
 		{url: "/api/v1/p34ch", params: nil, match: false},
+		{url: "/api/v1/peachy", params: nil, match: false},
+		{url: "/api/v1/apeach", params: nil, match: false},
+	})
+	testCase("/api/v1/:param<regex(\\w+\\.png)>", []testparams{
+		{url: "/api/v1/a.png", params: []string{"a.png"}, match: true},
+		{url: "/api/v1/a.pngx", params: nil, match: false},
+		{url: "/api/v1/x-a.png", params: nil, match: false},
 	})
 	testCase("/api/v1/:param<regex(\\d{4}-\\d{2}-\\d{2})}>", []testparams{
 		{url: "/api/v1/ent", params: nil, match: false},
@@ -532,6 +540,12 @@ func Test_Path_matchParams(t *testing.T) {
 		{url: "/api/v1/2022-08-27", params: []string{"2022-08-27"}, match: true},
 		{url: "/api/v1/2022/08-27", params: nil, match: false},
 	})
+	testCase("/api/v1/:param<int>?", []testparams{
+		{url: "/api/v1", params: []string{""}, match: true},
+		{url: "/api/v1/", params: []string{""}, match: true},
+		{url: "/api/v1/25", params: []string{"25"}, match: true},
+		{url: "/api/v1/true", params: nil, match: false},
+	})
 	testCase("/api/v1/:param<int;bool((>", []testparams{
 		{url: "/api/v1/entity", params: nil, match: false},
 		{url: "/api/v1/8728382", params: []string{"8728382"}, match: true},
@@ -599,6 +613,32 @@ func Test_Path_matchParams(t *testing.T) {
 	})
 }
 
+// go test -race -run Test_Path_matchParams_CustomConstraint
+func Test_Path_matchParams_CustomConstraint(t *testing.T) {
+	t.Parallel()
+	RegisterConstraint("test_hex", func(param string, data []string) bool {
+		if len(data) == 1 && strconv.Itoa(len(param)) != data[0] {
+			return false
+		}
+		_, err := strconv.ParseUint(param, 16, 64)
+		return err == nil
+	})
+
+	var ctxParams [maxParams]string
+	parser := parseRoute("/api/v1/:id<test_hex>")
+	utils.AssertEqual(t, true, parser.getMatch("/api/v1/ff", "/api/v1/ff", &ctxParams, false))
+	utils.AssertEqual(t, "ff", ctxParams[0])
+	utils.AssertEqual(t, false, parser.getMatch("/api/v1/fg", "/api/v1/fg", &ctxParams, false))
+
+	parser = parseRoute("/api/v1/:id<test_hex(4)>")
+	utils.AssertEqual(t, true, parser.getMatch("/api/v1/beef", "/api/v1/beef", &ctxParams, false))
+	utils.AssertEqual(t, false, parser.getMatch("/api/v1/ff", "/api/v1/ff", &ctxParams, false))
+
+	// Unknown constraints are ignored
+	parser = parseRoute("/api/v1/:id<test_unknown>")
+	utils.AssertEqual(t, true, parser.getMatch("/api/v1/fg", "/api/v1/fg", &ctxParams, false))
+}
+
 func Test_Utils_GetTrimmedParam(t *testing.T) {
 	t.Parallel()
 	res := GetTrimmedParam("")
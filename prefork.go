@@ -54,7 +54,7 @@ func (app *App) prefork(network, addr string, tlsConfig *tls.Config) (err error)
 		app.startupProcess()
 
 		// listen for incoming connections
-		return app.server.Serve(ln)
+		return app.serve(ln)
 	}
 
 	// 👮 master process 👮
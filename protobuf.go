@@ -0,0 +1,52 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+)
+
+// mimeApplicationProtobuf is the registered protobuf content type, which is
+// accepted besides the more common application/x-protobuf
+const mimeApplicationProtobuf = "application/protobuf"
+
+// protoMarshaler is implemented by messages generated by gogo/protobuf and similar
+type protoMarshaler interface {
+	Marshal() ([]byte, error)
+}
+
+// protoUnmarshaler is implemented by messages generated by gogo/protobuf and similar
+type protoUnmarshaler interface {
+	Unmarshal(data []byte) error
+}
+
+// Protobuf encodes the message with the ProtobufEncoder of the config and
+// sets the content header to application/x-protobuf.
+// BodyParser decodes protobuf request bodies accordingly.
+func (c *Ctx) Protobuf(msg interface{}) error {
+	raw, err := c.app.config.ProtobufEncoder(msg)
+	if err != nil {
+		return err
+	}
+	c.fasthttp.Response.SetBodyRaw(raw)
+	c.fasthttp.Response.Header.SetContentType(MIMEApplicationProtobuf)
+	return nil
+}
+
+// protobufMarshal is the default ProtobufEncoder
+func protobufMarshal(v interface{}) ([]byte, error) {
+	if m, ok := v.(protoMarshaler); ok {
+		return m.Marshal()
+	}
+	return nil, fmt.Errorf("failed to encode %T as protobuf: set Config.ProtobufEncoder", v)
+}
+
+// protobufUnmarshal is the default ProtobufDecoder
+func protobufUnmarshal(data []byte, v interface{}) error {
+	if m, ok := v.(protoUnmarshaler); ok {
+		return m.Unmarshal(data)
+	}
+	return fmt.Errorf("failed to decode protobuf into %T: set Config.ProtobufDecoder", v)
+}
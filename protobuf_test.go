@@ -0,0 +1,73 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// protoUser mimics a generated message with the string field 1
+type protoUser struct {
+	Name string
+}
+
+func (u *protoUser) Marshal() ([]byte, error) {
+	return append([]byte{0x0a, byte(len(u.Name))}, u.Name...), nil
+}
+
+func (u *protoUser) Unmarshal(data []byte) error {
+	if len(data) < 2 || data[0] != 0x0a || int(data[1]) != len(data)-2 {
+		return errors.New("invalid message")
+	}
+	u.Name = string(data[2:])
+	return nil
+}
+
+// go test -run Test_Ctx_Protobuf
+func Test_Ctx_Protobuf(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	utils.AssertEqual(t, nil, c.Protobuf(&protoUser{Name: "john"}))
+	utils.AssertEqual(t, MIMEApplicationProtobuf, string(c.Response().Header.ContentType()))
+	utils.AssertEqual(t, "\x0a\x04john", string(c.Response().Body()))
+
+	// Messages without Marshal method need a custom encoder
+	utils.AssertEqual(t, true, c.Protobuf(struct{}{}) != nil)
+
+	app = New(Config{
+		ProtobufEncoder: func(v interface{}) ([]byte, error) {
+			return []byte("custom"), nil
+		},
+	})
+	c2 := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c2)
+	utils.AssertEqual(t, nil, c2.Protobuf(struct{}{}))
+	utils.AssertEqual(t, "custom", string(c2.Response().Body()))
+}
+
+// go test -run Test_Ctx_BodyParser_Protobuf
+func Test_Ctx_BodyParser_Protobuf(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().SetBody([]byte("\x0a\x04john"))
+	for _, ctype := range []string{MIMEApplicationProtobuf, "application/protobuf"} {
+		c.Request().Header.SetContentType(ctype)
+		user := new(protoUser)
+		utils.AssertEqual(t, nil, c.BodyParser(user))
+		utils.AssertEqual(t, "john", user.Name)
+	}
+
+	utils.AssertEqual(t, true, c.BodyParser(&struct{ Name string }{}) != nil)
+}
@@ -0,0 +1,93 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// TrackingParams are the query params of analytics and ad campaigns, which
+// don't change the response, e.g. to leave them out of cache keys with
+// CanonicalQuery. A trailing "*" matches the params with the prefix.
+var TrackingParams = []string{"utm_*", "gclid", "dclid", "fbclid", "msclkid", "mc_cid", "mc_eid", "_ga", "_gl"}
+
+// CanonicalQuery returns the canonical form of the query string, which is
+// the same for equivalent queries, e.g. for cache keys of the cache middleware
+// or a CDN:
+//
+//	fiber.CanonicalQuery("b=2&a=%7e1&utm_source=mail&a=0", fiber.TrackingParams...) // "a=~1&a=0&b=2"
+//
+// The params are sorted by their keys, the values of a key keep their order.
+// Keys and values are decoded and encoded again like by url.QueryEscape,
+// empty pairs are dropped and so are the ignored params. A trailing "*" of an
+// ignored param matches the params with the prefix.
+func CanonicalQuery(query string, ignore ...string) string {
+	type param struct{ key, value string }
+	params := make([]param, 0, strings.Count(query, "&")+1)
+	for query != "" {
+		pair := query
+		if i := strings.IndexByte(query, '&'); i != -1 {
+			pair, query = query[:i], query[i+1:]
+		} else {
+			query = ""
+		}
+		if pair == "" {
+			continue
+		}
+		key, value := pair, ""
+		if i := strings.IndexByte(pair, '='); i != -1 {
+			key, value = pair[:i], pair[i+1:]
+		}
+		key, value = unescapeQuery(key), unescapeQuery(value)
+		if key == "" || isIgnoredParam(key, ignore) {
+			continue
+		}
+		params = append(params, param{key, value})
+	}
+	sort.SliceStable(params, func(i, j int) bool {
+		return params[i].key < params[j].key
+	})
+
+	var b strings.Builder
+	for i, p := range params {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(p.key))
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(p.value))
+	}
+	return b.String()
+}
+
+// CanonicalQuery returns the canonical form of the query string of the
+// request, see CanonicalQuery.
+func (c *Ctx) CanonicalQuery(ignore ...string) string {
+	return CanonicalQuery(c.app.getString(c.fasthttp.URI().QueryString()), ignore...)
+}
+
+// unescapeQuery decodes a key or value of a query, invalid escapes are kept as is
+func unescapeQuery(s string) string {
+	if unescaped, err := url.QueryUnescape(s); err == nil {
+		return unescaped
+	}
+	return s
+}
+
+// isIgnoredParam reports if the key matches one of the ignored params
+func isIgnoredParam(key string, ignore []string) bool {
+	for _, param := range ignore {
+		if strings.HasSuffix(param, "*") {
+			if strings.HasPrefix(key, param[:len(param)-1]) {
+				return true
+			}
+		} else if key == param {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_CanonicalQuery
+func Test_CanonicalQuery(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		query    string
+		ignore   []string
+		expected string
+	}{
+		{query: "", expected: ""},
+		{query: "b=2&a=1", expected: "a=1&b=2"},
+		{query: "b=2&a=%7e1&utm_source=mail&a=0", ignore: TrackingParams, expected: "a=~1&a=0&b=2"},
+		{query: "q=hello+world&q2=hello%20world", expected: "q=hello+world&q2=hello+world"},
+		{query: "&&flag&x=%zz&%61=%2F", expected: "a=%2F&flag=&x=%25zz"},
+		{query: "=1&utm_campaign=x&gclid=y&session=z", ignore: []string{"utm_*", "gclid"}, expected: "session=z"},
+	}
+	for _, tc := range testCases {
+		utils.AssertEqual(t, tc.expected, CanonicalQuery(tc.query, tc.ignore...), tc.query)
+	}
+
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	c.Request().URI().SetQueryString("page=2&q=go&fbclid=abc")
+	utils.AssertEqual(t, "page=2&q=go", c.CanonicalQuery(TrackingParams...))
+}
@@ -0,0 +1,220 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// maxRanges is the maximum number of ranges served as multipart/byteranges,
+// the full content is served for requests with more ranges
+const maxRanges = 16
+
+// byteRange is a satisfiable range of a Range header, end is inclusive
+type byteRange struct {
+	start, end int64
+}
+
+// serveFileRanges runs the file handler of fasthttp, which serves single byte
+// ranges, and adds support for If-Range and multiple ranges, see RFC 7233.
+func serveFileRanges(fctx *fasthttp.RequestCtx, fileHandler fasthttp.RequestHandler) {
+	rangeHeader := utils.UnsafeString(fctx.Request.Header.Peek(HeaderRange))
+	ifRange := utils.UnsafeString(fctx.Request.Header.Peek(HeaderIfRange))
+	multiple := strings.IndexByte(rangeHeader, ',') >= 0
+	if rangeHeader == "" || (ifRange == "" && !multiple) || !(fctx.IsGet() || fctx.IsHead()) {
+		fileHandler(fctx)
+		return
+	}
+
+	// Look up the size and the validator of the file without serving it
+	probe := &fasthttp.RequestCtx{}
+	probe.Init(&fctx.Request, fctx.RemoteAddr(), nil)
+	probe.Request.Header.SetMethod(MethodHead)
+	probe.Request.Header.Del(HeaderRange)
+	probe.Request.Header.Del(HeaderAcceptEncoding)
+	fileHandler(probe)
+	if probe.Response.StatusCode() != StatusOK {
+		// E.g. not found or not modified
+		fileHandler(fctx)
+		return
+	}
+
+	// The range is ignored if the file changed, a date is compared exactly
+	// because it's a weak validator otherwise
+	if ifRange != "" && ifRange != utils.UnsafeString(probe.Response.Header.Peek(HeaderLastModified)) {
+		fctx.Request.Header.Del(HeaderRange)
+		fileHandler(fctx)
+		return
+	}
+	if !multiple {
+		fileHandler(fctx)
+		return
+	}
+
+	size := int64(probe.Response.Header.ContentLength())
+	ranges, err := parseRanges(rangeHeader, size)
+	if err == errRangeNotSatisfiable {
+		fctx.Error(utils.StatusMessage(StatusRequestedRangeNotSatisfiable), StatusRequestedRangeNotSatisfiable)
+		fctx.Response.Header.Set(HeaderContentRange, "bytes */"+strconv.FormatInt(size, 10))
+		return
+	}
+	if err != nil || len(ranges) > maxRanges || overlapping(ranges) {
+		// Invalid or too expensive ranges are ignored
+		fctx.Request.Header.Del(HeaderRange)
+		fileHandler(fctx)
+		return
+	}
+	if len(ranges) == 1 {
+		fctx.Request.Header.Set(HeaderRange, "bytes="+strconv.FormatInt(ranges[0].start, 10)+"-"+strconv.FormatInt(ranges[0].end, 10))
+		fileHandler(fctx)
+		return
+	}
+
+	contentType := string(probe.Response.Header.ContentType())
+	boundary := strings.Replace(utils.UUID(), "-", "", -1)
+	heads := make([]string, len(ranges))
+	length := int64(len("\r\n--" + boundary + "--\r\n"))
+	for i, r := range ranges {
+		heads[i] = "\r\n--" + boundary + "\r\n" +
+			HeaderContentType + ": " + contentType + "\r\n" +
+			HeaderContentRange + ": bytes " + strconv.FormatInt(r.start, 10) + "-" + strconv.FormatInt(r.end, 10) + "/" + strconv.FormatInt(size, 10) + "\r\n\r\n"
+		length += int64(len(heads[i])) + r.end - r.start + 1
+	}
+	// The first boundary isn't preceded by a line break
+	heads[0] = heads[0][2:]
+	length -= 2
+
+	resp := &fctx.Response
+	resp.Header.Set(HeaderAcceptRanges, "bytes")
+	resp.Header.Set(HeaderLastModified, string(probe.Response.Header.Peek(HeaderLastModified)))
+	resp.Header.SetContentType("multipart/byteranges; boundary=" + boundary)
+	resp.SetStatusCode(StatusPartialContent)
+	if fctx.IsHead() {
+		resp.ResetBody()
+		resp.SkipBody = true
+		resp.Header.SetContentLength(int(length))
+		return
+	}
+
+	// The parts are served lazily, so no file is opened if the body is not sent
+	req := &fasthttp.Request{}
+	fctx.Request.CopyTo(req)
+	req.Header.Del(HeaderIfRange)
+	pr, pw := io.Pipe()
+	resp.SetBodyStream(&streamWriterReader{
+		pr: pr,
+		run: func() {
+			_ = pw.CloseWithError(writeRanges(pw, req, fileHandler, ranges, heads, boundary))
+		},
+	}, int(length))
+}
+
+// writeRanges writes the parts of the multipart/byteranges body
+func writeRanges(w io.Writer, req *fasthttp.Request, fileHandler fasthttp.RequestHandler, ranges []byteRange, heads []string, boundary string) error {
+	for i, r := range ranges {
+		if _, err := io.WriteString(w, heads[i]); err != nil {
+			return err
+		}
+		part := &fasthttp.RequestCtx{}
+		part.Init(req, nil, nil)
+		part.Request.Header.Set(HeaderRange, "bytes="+strconv.FormatInt(r.start, 10)+"-"+strconv.FormatInt(r.end, 10))
+		fileHandler(part)
+		if part.Response.StatusCode() != StatusPartialContent {
+			part.Response.ResetBody()
+			return errors.New("ranges: file changed while serving")
+		}
+		if err := part.Response.BodyWriteTo(w); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "\r\n--"+boundary+"--\r\n")
+	return err
+}
+
+var errRangeNotSatisfiable = errors.New("ranges: not satisfiable")
+
+// parseRanges returns the satisfiable ranges of the Range header. It returns
+// errRangeNotSatisfiable if none of the ranges is satisfiable.
+func parseRanges(header string, size int64) ([]byteRange, error) {
+	const prefix = "bytes="
+	if len(header) < len(prefix) || !utils.EqualFold(header[:len(prefix)], prefix) {
+		return nil, errors.New("ranges: unsupported unit")
+	}
+
+	var ranges []byteRange
+	specs := 0
+	for _, spec := range strings.Split(header[len(prefix):], ",") {
+		spec = utils.Trim(spec, ' ')
+		if spec == "" {
+			continue
+		}
+		specs++
+		dash := strings.IndexByte(spec, '-')
+		if dash < 0 {
+			return nil, errors.New("ranges: invalid range")
+		}
+		first, last := spec[:dash], spec[dash+1:]
+
+		var r byteRange
+		if first == "" {
+			// Suffix range of the last bytes
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return nil, errors.New("ranges: invalid range")
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r = byteRange{size - n, size - 1}
+		} else {
+			start, err := strconv.ParseInt(first, 10, 64)
+			if err != nil || start < 0 {
+				return nil, errors.New("ranges: invalid range")
+			}
+			end := size - 1
+			if last != "" {
+				if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+					return nil, errors.New("ranges: invalid range")
+				}
+				if end >= size {
+					end = size - 1
+				}
+			}
+			if start >= size {
+				continue
+			}
+			r = byteRange{start, end}
+		}
+		ranges = append(ranges, r)
+	}
+	if specs == 0 {
+		return nil, errors.New("ranges: missing range")
+	}
+	if len(ranges) == 0 {
+		return nil, errRangeNotSatisfiable
+	}
+	return ranges, nil
+}
+
+// overlapping reports if any of the ranges overlap
+func overlapping(ranges []byteRange) bool {
+	for i := range ranges {
+		for j := i + 1; j < len(ranges); j++ {
+			if ranges[i].start <= ranges[j].end && ranges[j].start <= ranges[i].end {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_Ctx_SendFile_Ranges
+func Test_Ctx_SendFile_Ranges(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "ranges.txt")
+	utils.AssertEqual(t, nil, ioutil.WriteFile(file, []byte("0123456789abcdef"), 0o600))
+
+	app := New()
+	app.Get("/", func(c *Ctx) error {
+		return c.SendFile(file)
+	})
+	request := func(method, byteRange, ifRange string) (int, map[string]string, string) {
+		req := httptest.NewRequest(method, "/", nil)
+		req.Header.Set(HeaderRange, byteRange)
+		if ifRange != "" {
+			req.Header.Set(HeaderIfRange, ifRange)
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		headers := map[string]string{}
+		for key := range resp.Header {
+			headers[key] = resp.Header.Get(key)
+		}
+		return resp.StatusCode, headers, string(body)
+	}
+
+	// Single range
+	status, headers, body := request(MethodGet, "bytes=2-5", "")
+	utils.AssertEqual(t, StatusPartialContent, status)
+	utils.AssertEqual(t, "bytes 2-5/16", headers[HeaderContentRange])
+	utils.AssertEqual(t, "2345", body)
+	lastModified := headers[HeaderLastModified]
+
+	// Multiple ranges
+	status, headers, body = request(MethodGet, "bytes=0-1, 10-11, -2", "")
+	utils.AssertEqual(t, StatusPartialContent, status)
+	mediaType, params, err := mime.ParseMediaType(headers[HeaderContentType])
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "multipart/byteranges", mediaType)
+	utils.AssertEqual(t, utils.ToString(len(body)), headers[HeaderContentLength])
+
+	reader := multipart.NewReader(strings.NewReader(body), params["boundary"])
+	for _, expected := range []struct{ contentRange, data string }{
+		{"bytes 0-1/16", "01"},
+		{"bytes 10-11/16", "ab"},
+		{"bytes 14-15/16", "ef"},
+	} {
+		part, err := reader.NextPart()
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, expected.contentRange, part.Header.Get(HeaderContentRange))
+		utils.AssertEqual(t, MIMETextPlainCharsetUTF8, part.Header.Get(HeaderContentType))
+		data, err := ioutil.ReadAll(part)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, expected.data, string(data))
+	}
+	_, err = reader.NextPart()
+	utils.AssertEqual(t, "EOF", err.Error())
+
+	status, headers, _ = request(MethodHead, "bytes=0-1,4-5", "")
+	utils.AssertEqual(t, StatusPartialContent, status)
+	utils.AssertEqual(t, true, headers[HeaderContentLength] != "0")
+
+	// If-Range
+	status, _, body = request(MethodGet, "bytes=2-5", lastModified)
+	utils.AssertEqual(t, StatusPartialContent, status)
+	utils.AssertEqual(t, "2345", body)
+	status, _, body = request(MethodGet, "bytes=2-5,8-9", "Mon, 02 Jan 2006 15:04:05 GMT")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, "0123456789abcdef", body)
+
+	// Overlapping ranges are ignored
+	status, _, body = request(MethodGet, "bytes=0-5,4-8", "")
+	utils.AssertEqual(t, StatusOK, status)
+	utils.AssertEqual(t, "0123456789abcdef", body)
+
+	// Unsatisfiable
+	status, headers, _ = request(MethodGet, "bytes=20-30,40-", "")
+	utils.AssertEqual(t, StatusRequestedRangeNotSatisfiable, status)
+	utils.AssertEqual(t, "bytes */16", headers[HeaderContentRange])
+}
+
+// go test -run Test_Static_Ranges
+func Test_Static_Ranges(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	utils.AssertEqual(t, nil, ioutil.WriteFile(filepath.Join(dir, "video.txt"), []byte("0123456789"), 0o600))
+
+	app := New()
+	app.Static("/", dir, Static{ByteRange: true})
+
+	req := httptest.NewRequest(MethodGet, "/video.txt", nil)
+	req.Header.Set(HeaderRange, "bytes=0-0,9-9")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusPartialContent, resp.StatusCode)
+	_, params, err := mime.ParseMediaType(resp.Header.Get(HeaderContentType))
+	utils.AssertEqual(t, nil, err)
+
+	reader := multipart.NewReader(resp.Body, params["boundary"])
+	for _, expected := range []string{"0", "9"} {
+		part, err := reader.NextPart()
+		utils.AssertEqual(t, nil, err)
+		data, err := ioutil.ReadAll(part)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, expected, string(data))
+	}
+}
+
+// go test -run Test_parseRanges
+func Test_parseRanges(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		header string
+		ranges []byteRange
+		err    bool
+	}{
+		{"bytes=0-4", []byteRange{{0, 4}}, false},
+		{"bytes=5-", []byteRange{{5, 9}}, false},
+		{"bytes=-3", []byteRange{{7, 9}}, false},
+		{"bytes=-20", []byteRange{{0, 9}}, false},
+		{"bytes=0-100", []byteRange{{0, 9}}, false},
+		{"BYTES=1-2, 4-5", []byteRange{{1, 2}, {4, 5}}, false},
+		{"bytes=1-2, 20-", []byteRange{{1, 2}}, false},
+		{"bytes=20-", nil, true},
+		{"bytes=", nil, true},
+		{"bytes=5-1", nil, true},
+		{"bytes=a-b", nil, true},
+		{"lines=1-2", nil, true},
+	} {
+		ranges, err := parseRanges(tc.header, 10)
+		utils.AssertEqual(t, tc.ranges, ranges, tc.header)
+		utils.AssertEqual(t, tc.err, err != nil, tc.header)
+	}
+}
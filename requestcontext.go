@@ -0,0 +1,170 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRequestFinished is the error of the request context, see Ctx.UserContext,
+// once the handlers of the request returned.
+var ErrRequestFinished = errors.New("fiber: request finished")
+
+// requestContext is the default user context of a request. It's cancelled
+// once the client disconnected, the request finished or the server shuts
+// down, and its values are the locals of the request.
+type requestContext struct {
+	c        *Ctx
+	shutdown <-chan struct{}
+
+	mutex    sync.Mutex
+	done     chan struct{}
+	err      error
+	finished bool
+
+	// Disconnect detection, while the handlers run, see Ctx.Done
+	watch         *disconnectWatch
+	handled       bool
+	onDisconnects []func()
+}
+
+func newRequestContext(c *Ctx) *requestContext {
+	return &requestContext{c: c, shutdown: c.app.done}
+}
+
+// Deadline implements context.Context, the request has no deadline
+func (r *requestContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+// Done implements context.Context
+func (r *requestContext) Done() <-chan struct{} {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.done == nil {
+		r.done = make(chan struct{})
+		if r.err != nil {
+			close(r.done)
+		} else {
+			// The server and the connection are only watched once the channel has been asked for
+			if r.shutdown != nil {
+				go r.watchShutdown(r.done)
+			}
+			r.watchDisconnect()
+		}
+	}
+	return r.done
+}
+
+// watchShutdown cancels the context on shutdown
+func (r *requestContext) watchShutdown(done chan struct{}) {
+	select {
+	case <-r.shutdown:
+		r.cancel(context.Canceled)
+	case <-done:
+	}
+}
+
+// Err implements context.Context
+func (r *requestContext) Err() error {
+	select {
+	case <-r.shutdown:
+		r.cancel(context.Canceled)
+	default:
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.err
+}
+
+// Value implements context.Context, the values are the locals of the request,
+// which are only available until the request finished. The locals are set
+// under the mutex, see Ctx.setLocal, since the context is passed to other
+// goroutines.
+func (r *requestContext) Value(key interface{}) interface{} {
+	k, ok := key.(string)
+	if !ok {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.finished {
+		return nil
+	}
+	return r.c.fasthttp.UserValue(k)
+}
+
+// onDisconnect registers a function called on disconnect
+func (r *requestContext) onDisconnect(fn func()) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.onDisconnects = append(r.onDisconnects, fn)
+	r.watchDisconnect()
+}
+
+// watchDisconnect starts watching the connection, the mutex must be held
+func (r *requestContext) watchDisconnect() {
+	if r.watch != nil || r.handled || r.err != nil {
+		return
+	}
+	r.watch = watchDisconnect(r.c.fasthttp.Conn(), r.disconnected)
+}
+
+// disconnected cancels the context and calls the OnDisconnect functions
+func (r *requestContext) disconnected() {
+	if !r.cancel(ErrClientDisconnected) {
+		return
+	}
+	r.mutex.Lock()
+	fns := r.onDisconnects
+	r.mutex.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// handlersReturned stops watching the connection, before the server uses it again
+func (r *requestContext) handlersReturned() {
+	r.mutex.Lock()
+	r.handled = true
+	watch := r.watch
+	r.watch = nil
+	r.mutex.Unlock()
+	if watch != nil {
+		watch.stop()
+	}
+}
+
+// finish cancels the context and detaches it from the Ctx, which is released
+func (r *requestContext) finish() {
+	r.handlersReturned()
+	r.mutex.Lock()
+	r.finished = true
+	r.mutex.Unlock()
+	r.cancel(ErrRequestFinished)
+}
+
+// cancel cancels the context with the error, once. It reports whether the
+// context has been cancelled by the call.
+func (r *requestContext) cancel(err error) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.err != nil {
+		return false
+	}
+	r.err = err
+	if r.done != nil {
+		close(r.done)
+	}
+	return true
+}
+
+// String implements fmt.Stringer like the contexts of the standard library
+func (r *requestContext) String() string {
+	return "fiber.Ctx.UserContext"
+}
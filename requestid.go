@@ -0,0 +1,25 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+// requestIDKey define the key name for storing the ID of the request
+const requestIDKey = "__local_request_id__"
+
+// RequestID returns the ID of the request, which was set by the requestid
+// middleware or SetRequestID, or "", e.g. to correlate logs:
+//
+//	log.Printf("[%s] user %d created", c.RequestID(), user.ID)
+//
+// Forward it to other services with the X-Request-ID header to correlate
+// their logs too.
+func (c *Ctx) RequestID() string {
+	id, _ := c.fasthttp.UserValue(requestIDKey).(string)
+	return id
+}
+
+// SetRequestID sets the ID of the request, see RequestID.
+func (c *Ctx) SetRequestID(id string) {
+	c.setLocal(requestIDKey, id)
+}
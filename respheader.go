@@ -0,0 +1,234 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	cookieType        = reflect.TypeOf(Cookie{})
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	respFieldsCache   sync.Map // reflect.Type => []respField
+)
+
+// respField describes a struct field written by SetFromStruct
+type respField struct {
+	index     []int
+	name      string
+	cookie    bool
+	omitempty bool
+}
+
+// SetFromStruct writes the fields of the struct, which are tagged with
+// `respHeader` or `cookie`, into the response headers and cookies. It's the
+// inverse of the parsers, e.g. for pagination metadata declared as a type:
+//
+//	type Page struct {
+//		Total  int      `respHeader:"X-Total-Count"`
+//		Links  []string `respHeader:"Link,omitempty"`
+//		Cursor string   `cookie:"cursor"`
+//	}
+//
+// Slices are written as repeated headers, zero values are skipped with the
+// omitempty option. The values are formatted by encoding.TextMarshaler,
+// fmt.Stringer or strconv, time.Time as HTTP date. Fields of the type Cookie
+// set the whole cookie, the name defaults to the tag.
+func (c *Ctx) SetFromStruct(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("fiber: SetFromStruct expects a struct, got %s", rv.Type())
+	}
+	for _, f := range respFieldsOf(rv.Type()) {
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok || (f.omitempty && fv.IsZero()) {
+			continue
+		}
+		if f.cookie {
+			if err := c.setCookieField(f.name, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+			c.fasthttp.Response.Header.Del(f.name)
+			for i := 0; i < fv.Len(); i++ {
+				s, err := formatRespValue(fv.Index(i))
+				if err != nil {
+					return fmt.Errorf("fiber: field %s: %w", f.name, err)
+				}
+				c.fasthttp.Response.Header.Add(f.name, s)
+			}
+			continue
+		}
+		s, err := formatRespValue(fv)
+		if err != nil {
+			return fmt.Errorf("fiber: field %s: %w", f.name, err)
+		}
+		c.Set(f.name, s)
+	}
+	return nil
+}
+
+// setCookieField sets a cookie from a string-like field or a Cookie field
+func (c *Ctx) setCookieField(name string, fv reflect.Value) error {
+	for fv.Kind() == reflect.Ptr {
+		fv = fv.Elem()
+	}
+	if fv.Type() == cookieType {
+		cookie := fv.Interface().(Cookie)
+		if cookie.Name == "" {
+			cookie.Name = name
+		}
+		c.Cookie(&cookie)
+		return nil
+	}
+	s, err := formatRespValue(fv)
+	if err != nil {
+		return fmt.Errorf("fiber: cookie %s: %w", name, err)
+	}
+	c.Cookie(&Cookie{Name: name, Value: s})
+	return nil
+}
+
+// respFieldsOf returns the cached fields of the struct type written by SetFromStruct
+func respFieldsOf(t reflect.Type) []respField {
+	if fields, ok := respFieldsCache.Load(t); ok {
+		return fields.([]respField)
+	}
+	fields := collectRespFields(t, nil, nil)
+	respFieldsCache.Store(t, fields)
+	return fields
+}
+
+func collectRespFields(t reflect.Type, index []int, fields []respField) []respField {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+		tag, cookie := field.Tag.Get(respHeaderTag), false
+		if tag == "" {
+			tag, cookie = field.Tag.Get(cookieTag), true
+		}
+		if tag == "" || tag == "-" {
+			// Fields of embedded structs are promoted
+			if ft := indirectStructType(field.Type); field.Anonymous && ft != nil {
+				fields = collectRespFields(ft, fieldIndex, fields)
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+		name, options := tag, ""
+		if i := strings.IndexByte(tag, ','); i != -1 {
+			name, options = tag[:i], tag[i+1:]
+		}
+		fields = append(fields, respField{
+			index:     fieldIndex,
+			name:      name,
+			cookie:    cookie,
+			omitempty: options == "omitempty",
+		})
+	}
+	return fields
+}
+
+// indirectStructType returns the struct type of t or *t, otherwise nil
+func indirectStructType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but reports nil pointers
+// instead of panicking
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// formatRespValue formats a single value for a header or cookie
+func formatRespValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		// Pointer receivers are checked below
+		if !v.Type().Implements(textMarshalerType) && !v.Type().Implements(stringerType) {
+			v = v.Elem()
+		}
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).UTC().Format(http.TimeFormat), nil
+	}
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case encoding.TextMarshaler:
+			b, err := x.MarshalText()
+			return string(b), err
+		case fmt.Stringer:
+			return x.String(), nil
+		}
+	}
+	if v.CanAddr() {
+		switch x := v.Addr().Interface().(type) {
+		case encoding.TextMarshaler:
+			b, err := x.MarshalText()
+			return string(b), err
+		case fmt.Stringer:
+			return x.String(), nil
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
@@ -0,0 +1,78 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Ctx_SetFromStruct
+func Test_Ctx_SetFromStruct(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Meta struct {
+		RequestID string `respHeader:"X-Request-Id"`
+	}
+	type Page struct {
+		Meta
+		Total    int       `respHeader:"X-Total-Count"`
+		Links    []string  `respHeader:"Link"`
+		Next     *int      `respHeader:"X-Next-Page"`
+		Modified time.Time `respHeader:"Last-Modified,omitempty"`
+		Server   net.IP    `respHeader:"X-Server"`
+		Cursor   string    `cookie:"cursor"`
+		Session  Cookie    `cookie:"session"`
+		Ignored  string
+	}
+	page := &Page{
+		Meta:     Meta{RequestID: "abc"},
+		Total:    42,
+		Links:    []string{`</items?page=2>; rel="next"`, `</items?page=5>; rel="last"`},
+		Modified: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Server:   net.IPv4(10, 0, 0, 1),
+		Cursor:   "c1",
+		Session:  Cookie{Value: "s1", HTTPOnly: true},
+		Ignored:  "ignored",
+	}
+	utils.AssertEqual(t, nil, c.SetFromStruct(page))
+
+	h := &c.Response().Header
+	utils.AssertEqual(t, "abc", string(h.Peek("X-Request-Id")))
+	utils.AssertEqual(t, "42", string(h.Peek("X-Total-Count")))
+	var links []string
+	h.VisitAll(func(key, value []byte) {
+		if string(key) == HeaderLink {
+			links = append(links, string(value))
+		}
+	})
+	utils.AssertEqual(t, page.Links, links)
+	utils.AssertEqual(t, "", string(h.Peek("X-Next-Page")))
+	utils.AssertEqual(t, "Sun, 02 Jan 2022 03:04:05 GMT", string(h.Peek(HeaderLastModified)))
+	utils.AssertEqual(t, "10.0.0.1", string(h.Peek("X-Server")))
+	utils.AssertEqual(t, "cursor=c1; path=/; SameSite=Lax", string(h.PeekCookie("cursor")))
+	utils.AssertEqual(t, "session=s1; path=/; HttpOnly; SameSite=Lax", string(h.PeekCookie("session")))
+
+	// omitempty skips zero values
+	c.Response().Reset()
+	utils.AssertEqual(t, nil, c.SetFromStruct(Page{}))
+	utils.AssertEqual(t, "0", string(h.Peek("X-Total-Count")))
+	utils.AssertEqual(t, "", string(h.Peek(HeaderLastModified)))
+
+	// Unsupported types
+	type Invalid struct {
+		Data map[string]string `respHeader:"X-Data"`
+	}
+	utils.AssertEqual(t, "fiber: field X-Data: unsupported type map[string]string",
+		c.SetFromStruct(Invalid{Data: map[string]string{}}).Error())
+	utils.AssertEqual(t, "fiber: SetFromStruct expects a struct, got string", c.SetFromStruct("").Error())
+}
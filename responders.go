@@ -0,0 +1,74 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Created responds with 201 Created and the location of the new resource. The
+// body is encoded as JSON, a nil body leaves the response empty.
+//
+//	return c.Created("/users/"+id, user)
+func (c *Ctx) Created(location string, body interface{}) error {
+	c.Status(StatusCreated)
+	if location != "" {
+		c.Location(location)
+	}
+	if body == nil {
+		c.fasthttp.Response.ResetBody()
+		return nil
+	}
+	return c.JSON(body)
+}
+
+// CreatedRoute responds with 201 Created like Created, the location is the URL
+// of the named route with the params, see RouteURL.
+//
+//	return c.CreatedRoute("user.show", fiber.Map{"id": user.ID}, user)
+func (c *Ctx) CreatedRoute(routeName string, params Map, body interface{}) error {
+	location, err := c.RouteURL(routeName, params)
+	if err != nil {
+		return err
+	}
+	return c.Created(location, body)
+}
+
+// Accepted responds with 202 Accepted for requests, which are processed
+// asynchronously. The location, e.g. of a status monitor, is optional.
+func (c *Ctx) Accepted(location string) error {
+	c.Status(StatusAccepted)
+	if location != "" {
+		c.Location(location)
+	}
+	c.fasthttp.Response.ResetBody()
+	return nil
+}
+
+// NoContent responds with 204 No Content and an empty body, e.g. after a
+// DELETE or PUT request.
+func (c *Ctx) NoContent() error {
+	c.fasthttp.Response.ResetBody()
+	c.Status(StatusNoContent)
+	return nil
+}
+
+// NotModified responds with 304 Not Modified and an empty body, keeping the
+// headers of the response, e.g. ETag and Cache-Control.
+func (c *Ctx) NotModified() error {
+	notModified(c)
+	return nil
+}
+
+// Gone responds with 410 Gone for resources, which have been deleted, e.g.
+// soft-deleted records, instead of 404 Not Found for unknown ones. The body is
+// the status message unless a message is given.
+func (c *Ctx) Gone(message ...string) error {
+	c.Status(StatusGone)
+	if len(message) > 0 {
+		return c.SendString(message[0])
+	}
+	return c.SendString(utils.StatusMessage(StatusGone))
+}
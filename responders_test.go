@@ -0,0 +1,74 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_Ctx_Responders
+func Test_Ctx_Responders(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/users/:id", func(c *Ctx) error {
+		if c.Params("id") == "2" {
+			return c.Gone()
+		}
+		c.Set(HeaderETag, `"v1"`)
+		if c.Get(HeaderIfNoneMatch) == `"v1"` {
+			return c.NotModified()
+		}
+		return c.JSON(Map{"id": 1})
+	}).Name("user.show")
+	app.Post("/users", func(c *Ctx) error {
+		return c.CreatedRoute("user.show", Map{"id": 1}, Map{"id": 1})
+	})
+	app.Post("/users/none", func(c *Ctx) error {
+		return c.Created("", nil)
+	})
+	app.Post("/users/missing", func(c *Ctx) error {
+		return c.CreatedRoute("user.missing", nil, nil)
+	})
+	app.Post("/imports", func(c *Ctx) error {
+		return c.Accepted("/imports/7")
+	})
+	app.Delete("/users/:id", func(c *Ctx) error {
+		_ = c.SendString("deleted")
+		return c.NoContent()
+	})
+
+	for _, tc := range []struct {
+		method, target, noneMatch string
+		status                    int
+		location, body            string
+	}{
+		{MethodPost, "/users", "", StatusCreated, "/users/1", `{"id":1}`},
+		{MethodPost, "/users/none", "", StatusCreated, "", ""},
+		{MethodPost, "/users/missing", "", StatusInternalServerError, "", ""},
+		{MethodPost, "/imports", "", StatusAccepted, "/imports/7", ""},
+		{MethodDelete, "/users/1", "", StatusNoContent, "", ""},
+		{MethodGet, "/users/1", `"v1"`, StatusNotModified, "", ""},
+		{MethodGet, "/users/2", "", StatusGone, "", "Gone"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.target, nil)
+		if tc.noneMatch != "" {
+			req.Header.Set(HeaderIfNoneMatch, tc.noneMatch)
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, tc.status, resp.StatusCode, tc.target)
+		utils.AssertEqual(t, tc.location, resp.Header.Get(HeaderLocation), tc.target)
+		if tc.status == StatusInternalServerError {
+			continue
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, tc.body, string(body), tc.target)
+	}
+}
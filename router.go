@@ -6,6 +6,10 @@ package fiber
 
 import (
 	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -30,8 +34,9 @@ type Router interface {
 	Trace(path string, handlers ...Handler) Router
 	Patch(path string, handlers ...Handler) Router
 
-	Add(method, path string, handlers ...Handler) Router
+	Add(methods []string, path string, handlers ...Handler) Router
 	Static(prefix, root string, config ...Static) Router
+	StaticFS(prefix string, fsys fs.FS, config ...Static) Router
 	All(path string, handlers ...Handler) Router
 
 	Group(prefix string, handlers ...Handler) Router
@@ -52,6 +57,8 @@ type Route struct {
 	root        bool        // Path equals '/'
 	path        string      // Prettified path
 	routeParser routeParser // Parameter parser
+	group       *Group      // Group the route was registered by, nil for the app
+	mount       *App        // Mounted app the route was registered by, nil for the app
 
 	// Public fields
 	Method   string    `json:"method"` // HTTP method
@@ -59,6 +66,36 @@ type Route struct {
 	Path     string    `json:"path"`   // Original registered route path
 	Params   []string  `json:"params"` // Case sensitive param keys
 	Handlers []Handler `json:"-"`      // Ctx handlers
+
+	RateLimit *RateLimit     `json:"rate_limit,omitempty"` // Declared rate limit, see App.RateLimit
+	Class     RequestClass   `json:"class,omitempty"`      // Declared request class, see App.Class
+	ETag      ETagMode       `json:"etag,omitempty"`       // Declared ETag generation, see App.ETag
+	Examples  []RouteExample `json:"examples,omitempty"`   // Example requests and responses, see App.Example
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"` // Arbitrary metadata, see App.Meta
+	Input    reflect.Type           `json:"-"`                  // Declared request type, see App.Input
+	Output   reflect.Type           `json:"-"`                  // Declared response type, see App.Output
+}
+
+// HandlerNames returns the function names of the route's handlers, e.g.
+// "github.com/gofiber/fiber/v2.emptyHandler".
+func (r *Route) HandlerNames() []string {
+	names := make([]string, len(r.Handlers))
+	for i, handler := range r.Handlers {
+		names[i] = runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	}
+	return names
+}
+
+// RateLimit is a rate limit declared alongside the route registration,
+// it's enforced by the limiter middleware.
+type RateLimit struct {
+	// Max number of requests during Expiration
+	Max int `json:"max"`
+	// Expiration is the duration of the rate limit window
+	Expiration time.Duration `json:"expiration"`
+	// KeyGenerator generates the key of the client, the limiter's one is used if nil
+	KeyGenerator func(*Ctx) string `json:"-"`
 }
 
 func (r *Route) match(detectionPath, path string, params *[maxParams]string) (match bool) {
@@ -147,28 +184,49 @@ func (app *App) next(c *Ctx) (match bool, err error) {
 func (app *App) handler(rctx *fasthttp.RequestCtx) {
 	// Acquire Ctx with fasthttp request from pool
 	c := app.AcquireCtx(rctx)
+	// The after-response phase releases the Ctx, see UseAfter
+	deferred := app.deferRelease(c)
 
 	// handle invalid http method directly
 	if c.methodINT == -1 {
 		_ = c.Status(StatusBadRequest).SendString("Invalid http method")
-		app.ReleaseCtx(c)
+		if !deferred {
+			app.ReleaseCtx(c)
+		}
 		return
 	}
 
 	// Find match in stack
 	match, err := app.next(c)
-	if err != nil {
+	c.settleRoute()
+	if c.requestContext != nil {
+		c.requestContext.handlersReturned()
+	}
+	if err != nil && !app.mockExample(c, err) {
 		if catch := c.app.ErrorHandler(c, err); catch != nil {
 			_ = c.SendStatus(StatusInternalServerError)
 		}
 	}
-	// Generate ETag if enabled
-	if match && app.config.ETag {
-		setETag(c, false)
+	if match {
+		// Generate ETag if enabled
+		// The ETags of JSON responses have been set by Ctx.JSON with ETagJSON
+		if mode := app.etagMode(c); mode != ETagDisabled {
+			if mode != ETagJSON || len(c.fasthttp.Response.Header.Peek(HeaderETag)) == 0 {
+				setETag(c, mode == ETagWeak)
+			}
+			// Evaluate the conditions of the request, e.g. If-None-Match
+			setNotModified(c)
+		}
+	}
+	// Move keep-alive clients to other instances while draining
+	if app.config.DrainCloseConnections && app.Draining() {
+		rctx.SetConnectionClose()
 	}
 
 	// Release Ctx
-	app.ReleaseCtx(c)
+	if !deferred {
+		app.ReleaseCtx(c)
+	}
 }
 
 func (app *App) addPrefixToRoute(prefix string, route *Route) *Route {
@@ -203,19 +261,27 @@ func (app *App) copyRoute(route *Route) *Route {
 		path:        route.path,
 		routeParser: route.routeParser,
 		Params:      route.Params,
+		mount:       route.mount,
 
 		// Public data
-		Path:     route.Path,
-		Method:   route.Method,
-		Handlers: route.Handlers,
+		Path:      route.Path,
+		Method:    route.Method,
+		Handlers:  route.Handlers,
+		RateLimit: route.RateLimit,
+		Class:     route.Class,
+		ETag:      route.ETag,
+		Examples:  route.Examples,
+		Metadata:  route.Metadata,
+		Input:     route.Input,
+		Output:    route.Output,
 	}
 }
 
-func (app *App) register(method, pathRaw string, handlers ...Handler) Router {
+func (app *App) register(method, pathRaw string, group *Group, handlers ...Handler) Router {
 	// Uppercase HTTP methods
 	method = utils.ToUpper(method)
 	// Check if the HTTP method is valid unless it's USE
-	if method != methodUse && methodInt(method) == -1 {
+	if method != methodUse && app.methodInt(method) == -1 {
 		panic(fmt.Sprintf("add: invalid http method %s\n", method))
 	}
 	// A route requires atleast one ctx handler
@@ -261,6 +327,7 @@ func (app *App) register(method, pathRaw string, handlers ...Handler) Router {
 		path:        RemoveEscapeChar(pathPretty),
 		routeParser: parsedPretty,
 		Params:      parsedRaw.params,
+		group:       group,
 
 		// Public data
 		Path:     pathRaw,
@@ -273,7 +340,7 @@ func (app *App) register(method, pathRaw string, handlers ...Handler) Router {
 	// Middleware route matches all HTTP methods
 	if isUse {
 		// Add route to all HTTP methods stack
-		for _, m := range intMethod {
+		for _, m := range app.config.RequestMethods {
 			// Create a route copy to avoid duplicates during compression
 			r := route
 			app.addRoute(m, &r)
@@ -290,6 +357,46 @@ func (app *App) registerStatic(prefix, root string, config ...Static) Router {
 	if root == "" {
 		root = "."
 	}
+	// Strip trailing slashes from the root path
+	if len(root) > 0 && root[len(root)-1] == '/' {
+		root = root[:len(root)-1]
+	}
+	// Precompressed files are looked up by the handler of StaticFS
+	if len(config) > 0 && config[0].Precompressed {
+		return app.StaticFS(prefix, os.DirFS(root), config...)
+	}
+	prefix, isStar, isRoot := app.staticPrefix(prefix)
+	// Fileserver settings
+	fs := &fasthttp.FS{
+		Root:                 root,
+		AllowEmptyRoot:       true,
+		GenerateIndexPages:   false,
+		AcceptByteRange:      false,
+		Compress:             false,
+		CompressedFileSuffix: app.config.CompressedFileSuffix,
+		CacheDuration:        10 * time.Second,
+		IndexNames:           []string{"index.html"},
+		PathRewrite:          app.staticPathRewrite(prefix, isStar),
+		PathNotFound: func(fctx *fasthttp.RequestCtx) {
+			fctx.Response.SetStatusCode(StatusNotFound)
+		},
+	}
+
+	// Set config if provided
+	if len(config) > 0 {
+		fs.CacheDuration = config[0].CacheDuration
+		fs.Compress = config[0].Compress
+		fs.AcceptByteRange = config[0].ByteRange
+		fs.GenerateIndexPages = config[0].Browse
+		if config[0].Index != "" {
+			fs.IndexNames = []string{config[0].Index}
+		}
+	}
+	return app.addStaticRoute(prefix, isRoot, fs.NewRequestHandler(), config...)
+}
+
+// staticPrefix normalizes the prefix of a static route
+func (app *App) staticPrefix(prefix string) (_ string, isStar, isRoot bool) {
 	// Cannot have an empty prefix
 	if prefix == "" {
 		prefix = "/"
@@ -302,14 +409,10 @@ func (app *App) registerStatic(prefix, root string, config ...Static) Router {
 	if !app.config.CaseSensitive {
 		prefix = utils.ToLower(prefix)
 	}
-	// Strip trailing slashes from the root path
-	if len(root) > 0 && root[len(root)-1] == '/' {
-		root = root[:len(root)-1]
-	}
 	// Is prefix a direct wildcard?
-	isStar := prefix == "/*"
+	isStar = prefix == "/*"
 	// Is prefix a root slash?
-	isRoot := prefix == "/"
+	isRoot = prefix == "/"
 	// Is prefix a partial wildcard?
 	if strings.Contains(prefix, "*") {
 		// /john* -> /john
@@ -323,61 +426,48 @@ func (app *App) registerStatic(prefix, root string, config ...Static) Router {
 		prefixLen--
 		prefix = prefix[:prefixLen]
 	}
-	// Fileserver settings
-	fs := &fasthttp.FS{
-		Root:                 root,
-		AllowEmptyRoot:       true,
-		GenerateIndexPages:   false,
-		AcceptByteRange:      false,
-		Compress:             false,
-		CompressedFileSuffix: app.config.CompressedFileSuffix,
-		CacheDuration:        10 * time.Second,
-		IndexNames:           []string{"index.html"},
-		PathRewrite: func(fctx *fasthttp.RequestCtx) []byte {
-			path := fctx.Path()
-			if len(path) >= prefixLen {
-				if isStar && app.getString(path[0:prefixLen]) == prefix {
-					path = append(path[0:0], '/')
-				} else {
-					path = path[prefixLen:]
-					if len(path) == 0 || path[len(path)-1] != '/' {
-						path = append(path, '/')
-					}
+	return prefix, isStar, isRoot
+}
+
+// staticPathRewrite returns the file path of a request to a static route
+func (app *App) staticPathRewrite(prefix string, isStar bool) fasthttp.PathRewriteFunc {
+	prefixLen := len(prefix)
+	return func(fctx *fasthttp.RequestCtx) []byte {
+		path := fctx.Path()
+		if len(path) >= prefixLen {
+			if isStar && app.getString(path[0:prefixLen]) == prefix {
+				path = append(path[0:0], '/')
+			} else {
+				path = path[prefixLen:]
+				if len(path) == 0 || path[len(path)-1] != '/' {
+					path = append(path, '/')
 				}
 			}
-			if len(path) > 0 && path[0] != '/' {
-				path = append([]byte("/"), path...)
-			}
-			return path
-		},
-		PathNotFound: func(fctx *fasthttp.RequestCtx) {
-			fctx.Response.SetStatusCode(StatusNotFound)
-		},
+		}
+		if len(path) > 0 && path[0] != '/' {
+			path = append([]byte("/"), path...)
+		}
+		return path
 	}
+}
 
-	// Set config if provided
+// addStaticRoute registers the file handler of a static route for GET and HEAD requests
+func (app *App) addStaticRoute(prefix string, isRoot bool, fileHandler fasthttp.RequestHandler, config ...Static) Router {
 	var cacheControlValue string
-	if len(config) > 0 {
-		maxAge := config[0].MaxAge
-		if maxAge > 0 {
-			cacheControlValue = "public, max-age=" + strconv.Itoa(maxAge)
-		}
-		fs.CacheDuration = config[0].CacheDuration
-		fs.Compress = config[0].Compress
-		fs.AcceptByteRange = config[0].ByteRange
-		fs.GenerateIndexPages = config[0].Browse
-		if config[0].Index != "" {
-			fs.IndexNames = []string{config[0].Index}
-		}
+	if len(config) > 0 && config[0].MaxAge > 0 {
+		cacheControlValue = "public, max-age=" + strconv.Itoa(config[0].MaxAge)
 	}
-	fileHandler := fs.NewRequestHandler()
 	handler := func(c *Ctx) error {
 		// Don't execute middleware if Next returns true
 		if len(config) != 0 && config[0].Next != nil && config[0].Next(c) {
 			return c.Next()
 		}
 		// Serve file
-		fileHandler(c.fasthttp)
+		if len(config) != 0 && config[0].ByteRange {
+			serveFileRanges(c.fasthttp, fileHandler)
+		} else {
+			fileHandler(c.fasthttp)
+		}
 		// Sets the response Content-Disposition header to attachment if the Download option is true
 		if len(config) > 0 && config[0].Download {
 			c.Attachment()
@@ -420,13 +510,19 @@ func (app *App) registerStatic(prefix, root string, config ...Static) Router {
 
 func (app *App) addRoute(method string, route *Route) {
 	// Get unique HTTP method identifier
-	m := methodInt(method)
+	m := app.methodInt(method)
+	if m == -1 {
+		// Routes of mounted apps with methods the app doesn't route
+		panic(fmt.Sprintf("add: invalid http method %s\n", method))
+	}
 
 	// prevent identically route registration
 	l := len(app.stack[m])
+	latest := route
 	if l > 0 && app.stack[m][l-1].Path == route.Path && route.use == app.stack[m][l-1].use {
 		preRoute := app.stack[m][l-1]
 		preRoute.Handlers = append(preRoute.Handlers, route.Handlers...)
+		latest = preRoute
 	} else {
 		// Increment global route position
 		route.pos = atomic.AddUint32(&app.routesCount, 1)
@@ -436,11 +532,18 @@ func (app *App) addRoute(method string, route *Route) {
 		app.routesRefreshed = true
 	}
 
+	app.markTreeStale()
+
 	app.mutex.Lock()
-	app.latestRoute = route
+	app.latestRoute = latest
 	if err := app.hooks.executeOnRouteHooks(*route); err != nil {
 		panic(err)
 	}
+	for _, grp := range route.group.lineage() {
+		if err := grp.hooks.executeOnRouteHooks(*route); err != nil {
+			panic(err)
+		}
+	}
 	app.mutex.Unlock()
 }
 
@@ -450,7 +553,7 @@ func (app *App) buildTree() *App {
 		return app
 	}
 	// loop all the methods and stacks and create the prefix tree
-	for m := range intMethod {
+	for m := range app.config.RequestMethods {
 		tsMap := make(map[string][]*Route)
 		for _, route := range app.stack[m] {
 			treePath := ""
@@ -463,7 +566,7 @@ func (app *App) buildTree() *App {
 		app.treeStack[m] = tsMap
 	}
 	// loop the methods and tree stacks and add global stack and sort everything
-	for m := range intMethod {
+	for m := range app.config.RequestMethods {
 		tsMap := app.treeStack[m]
 		for treePart := range tsMap {
 			if treePart != "" {
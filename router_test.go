@@ -145,6 +145,43 @@ func Test_Route_Match_Parser(t *testing.T) {
 	utils.AssertEqual(t, "test", app.getString(body))
 }
 
+func Test_Route_Match_Constraints(t *testing.T) {
+	app := New()
+
+	app.Get("/users/:id<int>", func(c *Ctx) error {
+		return c.SendString("id " + c.Params("id"))
+	})
+	app.Get("/users/:name", func(c *Ctx) error {
+		return c.SendString("name " + c.Params("name"))
+	})
+	app.Get("/files/:name<regex(\\w+\\.png)>", func(c *Ctx) error {
+		return c.SendString(c.Params("name"))
+	})
+	app.Get("/date/:d<datetime(2006\\-01\\-02)>", func(c *Ctx) error {
+		return c.SendString(c.Params("d"))
+	})
+
+	test := func(url string, status int, expected string) {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, url, nil))
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		utils.AssertEqual(t, status, resp.StatusCode, url)
+		if status == StatusOK {
+			body, err := ioutil.ReadAll(resp.Body)
+			utils.AssertEqual(t, nil, err)
+			utils.AssertEqual(t, expected, app.getString(body), url)
+		}
+	}
+
+	// Requests not satisfying the constraints fall through to the next route
+	test("/users/42", StatusOK, "id 42")
+	test("/users/john", StatusOK, "name john")
+	test("/files/logo.png", StatusOK, "logo.png")
+	test("/files/logo.pngx", StatusNotFound, "")
+	test("/files/x-logo.png", StatusNotFound, "")
+	test("/date/2022-08-27", StatusOK, "2022-08-27")
+	test("/date/2022-13-27", StatusNotFound, "")
+}
+
 func Test_Route_Match_Middleware(t *testing.T) {
 	app := New()
 
@@ -279,7 +316,7 @@ func Test_Router_Register_Missing_Handler(t *testing.T) {
 			utils.AssertEqual(t, "missing handler in route: /doe\n", fmt.Sprintf("%v", err))
 		}
 	}()
-	app.register("USE", "/doe")
+	app.register("USE", "/doe", nil)
 }
 
 func Test_Ensure_Router_Interface_Implementation(t *testing.T) {
@@ -451,7 +488,7 @@ func registerDummyRoutes(app *App) {
 		return nil
 	}
 	for _, r := range routesFixture.GithubAPI {
-		app.Add(r.Method, r.Path, h)
+		app.Add([]string{r.Method}, r.Path, h)
 	}
 }
 
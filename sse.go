@@ -0,0 +1,402 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bufio"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ErrSSEClosed is returned by SSEWriter if the client disconnected or the server shuts down
+var ErrSSEClosed = errors.New("sse: stream closed")
+
+// SSEConfig configures the event stream of Ctx.SSE
+type SSEConfig struct {
+	// Heartbeat is the interval of comments sent to keep idle connections
+	// alive and to detect disconnected clients. A negative value disables it.
+	//
+	// Optional. Default: 15 * time.Second
+	Heartbeat time.Duration
+
+	// Retry is the reconnection time sent to the client, the browser default
+	// is used if it is zero.
+	//
+	// Optional. Default: 0
+	Retry time.Duration
+
+	// Replay sends the events a reconnecting client missed, i.e. the events
+	// after the one of its Last-Event-ID header, before the handler is
+	// called. The handler may send the replayed events again, e.g. if it
+	// subscribed to them before, they are skipped by their ID.
+	//
+	// Optional. Default: nil
+	Replay SSEReplayer
+}
+
+// SSEEvent is a server-sent event
+type SSEEvent struct {
+	ID    string `json:"id"`
+	Event string `json:"event,omitempty"`
+	Data  string `json:"data"`
+}
+
+// SSEReplayer returns the events a reconnecting client missed, see SSEConfig.Replay
+// and SSEBuffer. Implementations may use a database or a message log, e.g. to
+// replay the events sent by other instances of the application.
+type SSEReplayer interface {
+	// Replay returns the events after the event with lastEventID in the
+	// order they were sent.
+	Replay(lastEventID string) ([]SSEEvent, error)
+}
+
+// sseHeartbeat is the default heartbeat interval
+const sseHeartbeat = 15 * time.Second
+
+// SSEWriter writes server-sent events to the client, see Ctx.SSE.
+// It is safe for concurrent use.
+type SSEWriter struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	err    error
+	closed chan struct{}
+
+	lastEventID string
+	// IDs of the replayed events, which aren't sent again
+	replayed map[string]struct{}
+}
+
+func newSSEWriter(w *bufio.Writer) *SSEWriter {
+	return &SSEWriter{
+		w:      w,
+		closed: make(chan struct{}),
+	}
+}
+
+// SSE streams server-sent events. It sets the text/event-stream headers and
+// calls handler with the writer once the headers are sent:
+//
+//	app.Get("/events", func(c *fiber.Ctx) error {
+//		return c.SSE(func(w *fiber.SSEWriter) {
+//			for {
+//				select {
+//				case msg := <-messages:
+//					if err := w.Send("message", msg, ""); err != nil {
+//						return
+//					}
+//				case <-w.Done():
+//					return
+//				}
+//			}
+//		})
+//	})
+//
+// The handler runs after the handler of the route returned, so it must not
+// use the Ctx, copy the values it needs beforehand. The stream ends when the
+// handler returns. Every event is flushed to the client and a failed write,
+// i.e. a disconnected client, or shutting down the server closes Done.
+func (c *Ctx) SSE(handler func(w *SSEWriter), config ...SSEConfig) error {
+	cfg := SSEConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Heartbeat == 0 {
+		cfg.Heartbeat = sseHeartbeat
+	}
+
+	c.fasthttp.Response.Header.SetContentType(MIMETextEventStream)
+	c.fasthttp.Response.Header.Set(HeaderCacheControl, "no-cache")
+	c.fasthttp.Response.Header.Set(HeaderConnection, "keep-alive")
+	// Disable response buffering of nginx
+	c.fasthttp.Response.Header.Set("X-Accel-Buffering", "no")
+
+	// Closed on shutdown, it is safe to use after the Ctx was released
+	shutdown := serverDone(c.fasthttp)
+	lastEventID := string(c.fasthttp.Request.Header.Peek(HeaderLastEventID))
+
+	c.fasthttp.SetBodyStreamWriter(func(bw *bufio.Writer) {
+		w := newSSEWriter(bw)
+		w.lastEventID = lastEventID
+		defer w.close(nil)
+
+		if cfg.Retry > 0 {
+			_ = w.write("retry: " + strconv.FormatInt(cfg.Retry.Milliseconds(), 10) + "\n\n")
+		} else {
+			// Send the headers right away
+			_ = w.write("")
+		}
+
+		stop := make(chan struct{})
+		defer close(stop)
+		go w.watch(cfg.Heartbeat, shutdown, stop)
+
+		if cfg.Replay != nil && lastEventID != "" {
+			if err := w.replay(cfg.Replay); err != nil {
+				return
+			}
+		}
+
+		handler(w)
+	})
+	return nil
+}
+
+// serverDone returns the channel, which is closed on shutdown. It is nil for
+// request contexts, which are not served by a server, e.g. in tests.
+func serverDone(fctx *fasthttp.RequestCtx) (done <-chan struct{}) {
+	defer func() {
+		_ = recover()
+	}()
+	return fctx.Done()
+}
+
+// Send writes an event, event and id are omitted if empty. Data spanning
+// multiple lines is sent as multiple data fields.
+func (w *SSEWriter) Send(event, data, id string) error {
+	if strings.ContainsAny(event, "\r\n") || strings.ContainsAny(id, "\r\n") {
+		return errors.New("sse: event and id must not contain line breaks")
+	}
+	if id != "" && w.skipReplayed(id) {
+		return nil
+	}
+
+	var b strings.Builder
+	if event != "" {
+		b.WriteString("event: ")
+		b.WriteString(event)
+		b.WriteByte('\n')
+	}
+	if id != "" {
+		b.WriteString("id: ")
+		b.WriteString(id)
+		b.WriteByte('\n')
+	}
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: ")
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	return w.write(b.String())
+}
+
+// Comment writes a comment, which is ignored by clients
+func (w *SSEWriter) Comment(text string) error {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		b.WriteString(": ")
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	return w.write(b.String())
+}
+
+// LastEventID returns the Last-Event-ID header of a reconnecting client, i.e.
+// the ID of the last event it received.
+func (w *SSEWriter) LastEventID() string {
+	return w.lastEventID
+}
+
+// replay sends the events the client missed
+func (w *SSEWriter) replay(replayer SSEReplayer) error {
+	events, err := replayer.Replay(w.lastEventID)
+	if err != nil {
+		// The client gets the new events at least
+		return nil
+	}
+	for _, event := range events {
+		if err = w.Send(event.Event, event.Data, event.ID); err != nil {
+			return err
+		}
+	}
+
+	w.mu.Lock()
+	w.replayed = make(map[string]struct{}, len(events))
+	for _, event := range events {
+		if event.ID != "" {
+			w.replayed[event.ID] = struct{}{}
+		}
+	}
+	w.mu.Unlock()
+	return nil
+}
+
+// skipReplayed reports if the event with the id was replayed already
+func (w *SSEWriter) skipReplayed(id string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.replayed[id]; ok {
+		delete(w.replayed, id)
+		return true
+	}
+	return false
+}
+
+// Done is closed if the client disconnected or the server shuts down
+func (w *SSEWriter) Done() <-chan struct{} {
+	return w.closed
+}
+
+// Err returns the reason the stream was closed
+func (w *SSEWriter) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// write writes and flushes s, the stream is closed on failure
+func (w *SSEWriter) write(s string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return ErrSSEClosed
+	}
+	if _, err := w.w.WriteString(s); err != nil {
+		w.closeLocked(err)
+		return ErrSSEClosed
+	}
+	if err := w.w.Flush(); err != nil {
+		w.closeLocked(err)
+		return ErrSSEClosed
+	}
+	return nil
+}
+
+// watch closes the stream on shutdown and writes heartbeats if interval is
+// positive until stop is closed or a write fails
+func (w *SSEWriter) watch(interval time.Duration, shutdown <-chan struct{}, stop <-chan struct{}) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-tick:
+			if w.write(":\n\n") != nil {
+				return
+			}
+		case <-shutdown:
+			w.close(ErrSSEClosed)
+			return
+		case <-w.closed:
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (w *SSEWriter) close(err error) {
+	w.mu.Lock()
+	w.closeLocked(err)
+	w.mu.Unlock()
+}
+
+func (w *SSEWriter) closeLocked(err error) {
+	if w.err != nil {
+		return
+	}
+	if err == nil {
+		err = ErrSSEClosed
+	}
+	w.err = err
+	close(w.closed)
+}
+
+// SSEBuffer is an in-memory SSEReplayer, which keeps the latest events:
+//
+//	buffer := fiber.NewSSEBuffer(1000)
+//
+//	// Publish an event to the buffer and the connected clients
+//	event := buffer.Add(fiber.SSEEvent{Event: "message", Data: msg})
+//	broadcast(event)
+//
+//	app.Get("/events", func(c *fiber.Ctx) error {
+//		// Subscribe before the replay, so no event gets lost in between
+//		events := subscribe()
+//		return c.SSE(func(w *fiber.SSEWriter) {
+//			defer unsubscribe(events)
+//			for event := range events {
+//				if err := w.Send(event.Event, event.Data, event.ID); err != nil {
+//					return
+//				}
+//			}
+//		}, fiber.SSEConfig{Replay: buffer})
+//	})
+//
+// Clients with an unknown Last-Event-ID, e.g. of an event evicted from the
+// buffer or sent before a restart, get all buffered events.
+type SSEBuffer struct {
+	mu     sync.RWMutex
+	events []SSEEvent
+	// start is the index of the oldest event in the ring
+	start int
+	size  int
+
+	// epoch distinguishes the IDs of the buffer from the ones of previous processes
+	epoch string
+	seq   uint64
+}
+
+// NewSSEBuffer creates an SSEBuffer keeping the latest size events.
+func NewSSEBuffer(size int) *SSEBuffer {
+	if size <= 0 {
+		size = 1
+	}
+	return &SSEBuffer{
+		events: make([]SSEEvent, size),
+		epoch:  strconv.FormatInt(time.Now().UnixNano(), 36),
+	}
+}
+
+// Add stores the event and returns it. Events without an ID get a unique
+// one, events with an ID must be added in the order of their IDs.
+func (b *SSEBuffer) Add(event SSEEvent) SSEEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if event.ID == "" {
+		b.seq++
+		event.ID = b.epoch + "-" + strconv.FormatUint(b.seq, 10)
+	}
+	if b.size < len(b.events) {
+		b.events[(b.start+b.size)%len(b.events)] = event
+		b.size++
+	} else {
+		// Evict the oldest event
+		b.events[b.start] = event
+		b.start = (b.start + 1) % len(b.events)
+	}
+	return event
+}
+
+// Replay returns the buffered events after the event with lastEventID, or
+// all of them if the event isn't buffered.
+func (b *SSEBuffer) Replay(lastEventID string) ([]SSEEvent, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	from := 0
+	for i := b.size - 1; i >= 0; i-- {
+		if b.events[(b.start+i)%len(b.events)].ID == lastEventID {
+			from = i + 1
+			break
+		}
+	}
+	events := make([]SSEEvent, 0, b.size-from)
+	for i := from; i < b.size; i++ {
+		events = append(events, b.events[(b.start+i)%len(b.events)])
+	}
+	return events, nil
+}
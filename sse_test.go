@@ -0,0 +1,153 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_Ctx_SSE
+func Test_Ctx_SSE(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/events", func(c *Ctx) error {
+		name := c.Query("name")
+		return c.SSE(func(w *SSEWriter) {
+			utils.AssertEqual(t, nil, w.Send("greeting", "hello "+name, "1"))
+			utils.AssertEqual(t, nil, w.Send("", "line1\nline2", ""))
+			utils.AssertEqual(t, nil, w.Comment("note"))
+			utils.AssertEqual(t, true, w.Send("a\nb", "data", "") != nil)
+		}, SSEConfig{Retry: 3 * time.Second})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/events?name=john", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, MIMETextEventStream, resp.Header.Get(HeaderContentType))
+	utils.AssertEqual(t, "no-cache", resp.Header.Get(HeaderCacheControl))
+	utils.AssertEqual(t, "no", resp.Header.Get("X-Accel-Buffering"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "retry: 3000\n\n"+
+		"event: greeting\nid: 1\ndata: hello john\n\n"+
+		"data: line1\ndata: line2\n\n"+
+		": note\n\n", string(body))
+}
+
+// go test -run Test_Ctx_SSE_Heartbeat
+func Test_Ctx_SSE_Heartbeat(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c *Ctx) error {
+		return c.SSE(func(w *SSEWriter) {
+			time.Sleep(100 * time.Millisecond)
+		}, SSEConfig{Heartbeat: 10 * time.Millisecond})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, strings.HasPrefix(string(body), ":\n\n"), string(body))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+// go test -run Test_SSEWriter_Disconnect
+func Test_SSEWriter_Disconnect(t *testing.T) {
+	t.Parallel()
+	w := newSSEWriter(bufio.NewWriter(failingWriter{}))
+
+	select {
+	case <-w.Done():
+		t.Fatal("stream closed before writing")
+	default:
+	}
+
+	utils.AssertEqual(t, ErrSSEClosed, w.Send("", "data", ""))
+	<-w.Done()
+	utils.AssertEqual(t, "broken pipe", w.Err().Error())
+	utils.AssertEqual(t, ErrSSEClosed, w.Comment("ping"))
+}
+
+// go test -run Test_SSEBuffer
+func Test_SSEBuffer(t *testing.T) {
+	t.Parallel()
+	buffer := NewSSEBuffer(3)
+	first := buffer.Add(SSEEvent{Data: "1"})
+	second := buffer.Add(SSEEvent{Data: "2"})
+	utils.AssertEqual(t, true, first.ID != "" && first.ID != second.ID)
+
+	events, err := buffer.Replay(first.ID)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, []SSEEvent{second}, events)
+
+	events, err = buffer.Replay(second.ID)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, 0, len(events))
+
+	// The first event is evicted
+	third := buffer.Add(SSEEvent{Event: "custom", Data: "3", ID: "c"})
+	fourth := buffer.Add(SSEEvent{Data: "4"})
+	events, err = buffer.Replay(third.ID)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, []SSEEvent{fourth}, events)
+
+	// Unknown IDs get all buffered events
+	events, err = buffer.Replay(first.ID)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, []SSEEvent{second, third, fourth}, events)
+}
+
+// go test -run Test_Ctx_SSE_Replay
+func Test_Ctx_SSE_Replay(t *testing.T) {
+	t.Parallel()
+	buffer := NewSSEBuffer(10)
+	for _, id := range []string{"1", "2", "3"} {
+		buffer.Add(SSEEvent{Event: "message", Data: "event " + id, ID: id})
+	}
+
+	app := New()
+	app.Get("/events", func(c *Ctx) error {
+		lastEventID := utils.CopyString(c.Get(HeaderLastEventID))
+		return c.SSE(func(w *SSEWriter) {
+			utils.AssertEqual(t, lastEventID, w.LastEventID())
+			// The replayed event isn't sent again
+			utils.AssertEqual(t, nil, w.Send("message", "event 3", "3"))
+			utils.AssertEqual(t, nil, w.Send("message", "event 4", "4"))
+		}, SSEConfig{Replay: buffer})
+	})
+
+	req := httptest.NewRequest(MethodGet, "/events", nil)
+	req.Header.Set(HeaderLastEventID, "1")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "event: message\nid: 2\ndata: event 2\n\n"+
+		"event: message\nid: 3\ndata: event 3\n\n"+
+		"event: message\nid: 4\ndata: event 4\n\n", string(body))
+
+	// New clients don't get a replay
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/events", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	body, err = ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "event: message\nid: 3\ndata: event 3\n\n"+
+		"event: message\nid: 4\ndata: event 4\n\n", string(body))
+}
@@ -0,0 +1,286 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// StaticFS will create a file server serving the files of fsys, e.g. an embed.FS:
+//
+//	//go:embed public
+//	var public embed.FS
+//
+//	assets, _ := fs.Sub(public, "public")
+//	app.StaticFS("/assets", assets, fiber.Static{MaxAge: 3600})
+//
+// The Compress and CacheDuration options are not supported, use Precompressed
+// to serve compressed files.
+func (app *App) StaticFS(prefix string, fsys fs.FS, config ...Static) Router {
+	prefix, isStar, isRoot := app.staticPrefix(prefix)
+	handler := &fsHandler{
+		fsys:      fsys,
+		name:      app.staticPathRewrite(prefix, isStar),
+		index:     "index.html",
+		byteRange: len(config) > 0 && config[0].ByteRange,
+		browse:    len(config) > 0 && config[0].Browse,
+
+		precompressed: len(config) > 0 && config[0].Precompressed,
+	}
+	if len(config) > 0 && config[0].Index != "" {
+		handler.index = config[0].Index
+	}
+	return app.addStaticRoute(prefix, isRoot, handler.handle, config...)
+}
+
+// StaticFS will create a file server serving the files of fsys, see App.StaticFS.
+func (grp *Group) StaticFS(prefix string, fsys fs.FS, config ...Static) Router {
+	return grp.app.StaticFS(getGroupPath(grp.Prefix, prefix), fsys, config...)
+}
+
+// SendFileFS transfers the file with the given name of fsys, e.g. an embed.FS.
+// Sets the Content-Type response HTTP header field based on the filenames extension.
+// Range requests are served including If-Range and multiple ranges, which are sent as multipart/byteranges.
+func (c *Ctx) SendFileFS(fsys fs.FS, name string) error {
+	handler := &fsHandler{
+		fsys: fsys,
+		name: func(*fasthttp.RequestCtx) []byte {
+			return []byte(name)
+		},
+		index:     "index.html",
+		byteRange: true,
+	}
+	// Save status code
+	status := c.fasthttp.Response.StatusCode()
+	// Serve file
+	serveFileRanges(c.fasthttp, handler.handle)
+	// Get the status code which is set by the handler
+	fsStatus := c.fasthttp.Response.StatusCode()
+	// Set the status code set by the user if it is different from the handler status code and 200
+	if status != fsStatus && status != StatusOK {
+		c.Status(status)
+	}
+	// Check for error
+	if status != StatusNotFound && fsStatus == StatusNotFound {
+		return NewError(StatusNotFound, fmt.Sprintf("sendfile: file %s not found", name))
+	}
+	return nil
+}
+
+// fsHandler serves the files of a fs.FS like fasthttp.FS serves the files of a
+// directory, including single byte ranges
+type fsHandler struct {
+	fsys      fs.FS
+	name      func(fctx *fasthttp.RequestCtx) []byte
+	index     string
+	byteRange bool
+	browse    bool
+
+	precompressed bool
+}
+
+// precompressedEncodings are the encodings of precompressed files in the order of preference
+var precompressedEncodings = []string{"br", "zstd", "gzip"}
+
+// precompressedSuffixes are the suffixes of precompressed files by their encoding
+var precompressedSuffixes = map[string]string{
+	"br":   ".br",
+	"zstd": ".zst",
+	"gzip": ".gz",
+}
+
+// fsFile is the body stream of a file, which is closed by fasthttp
+type fsFile struct {
+	io.Reader
+	io.Closer
+}
+
+func (h *fsHandler) handle(fctx *fasthttp.RequestCtx) {
+	// Paths of fs.FS are unrooted and must not contain dot segments
+	name := strings.TrimPrefix(path.Clean("/"+string(h.name(fctx))), "/")
+	if name == "" {
+		name = "."
+	}
+
+	file, err := h.fsys.Open(name)
+	if err != nil {
+		h.error(fctx, err)
+		return
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		h.error(fctx, err)
+		return
+	}
+
+	// Serve index if path is directory
+	if stat.IsDir() {
+		if index, err := h.fsys.Open(path.Join(name, h.index)); err == nil {
+			if indexStat, err := index.Stat(); err == nil && !indexStat.IsDir() {
+				_ = file.Close()
+				file, stat = index, indexStat
+				name = path.Join(name, h.index)
+			} else {
+				_ = index.Close()
+			}
+		}
+	}
+	if stat.IsDir() {
+		defer file.Close()
+		if h.browse {
+			h.dirList(fctx, name)
+			return
+		}
+		fctx.Error(utils.StatusMessage(StatusForbidden), StatusForbidden)
+		return
+	}
+
+	resp := &fctx.Response
+	contentType := mime.TypeByExtension(path.Ext(stat.Name()))
+	if contentType == "" {
+		contentType = MIMEOctetStream
+	}
+	resp.Header.SetContentType(contentType)
+	if h.precompressed {
+		// The response depends on the Accept-Encoding header
+		resp.Header.Add(HeaderVary, HeaderAcceptEncoding)
+		if encoding, compressed, compressedStat := h.openPrecompressed(fctx, name); compressed != nil {
+			_ = file.Close()
+			file, stat = compressed, compressedStat
+			resp.Header.Set(HeaderContentEncoding, encoding)
+		}
+	}
+	if modTime := stat.ModTime(); !modTime.IsZero() {
+		if since, err := fasthttp.ParseHTTPDate(fctx.Request.Header.Peek(HeaderIfModifiedSince)); err == nil && !modTime.Truncate(time.Second).After(since) {
+			_ = file.Close()
+			fctx.NotModified()
+			return
+		}
+		resp.Header.Set(HeaderLastModified, modTime.UTC().Format(http.TimeFormat))
+	}
+
+	size := stat.Size()
+	start, end := int64(0), size-1
+	if h.byteRange {
+		resp.Header.Set(HeaderAcceptRanges, "bytes")
+		// Multiple ranges are served by serveFileRanges
+		if rangeHeader := fctx.Request.Header.Peek(HeaderRange); len(rangeHeader) > 0 {
+			ranges, err := parseRanges(utils.UnsafeString(rangeHeader), size)
+			if err == errRangeNotSatisfiable {
+				_ = file.Close()
+				fctx.Error(utils.StatusMessage(StatusRequestedRangeNotSatisfiable), StatusRequestedRangeNotSatisfiable)
+				resp.Header.Set(HeaderContentRange, "bytes */"+strconv.FormatInt(size, 10))
+				return
+			}
+			seeker, ok := file.(io.Seeker)
+			if err == nil && len(ranges) == 1 && ok {
+				if _, err = seeker.Seek(ranges[0].start, io.SeekStart); err != nil {
+					_ = file.Close()
+					h.error(fctx, err)
+					return
+				}
+				start, end = ranges[0].start, ranges[0].end
+				resp.Header.Set(HeaderContentRange, "bytes "+strconv.FormatInt(start, 10)+"-"+strconv.FormatInt(end, 10)+"/"+strconv.FormatInt(size, 10))
+				resp.SetStatusCode(StatusPartialContent)
+			}
+		}
+	}
+
+	length := int(end - start + 1)
+	if fctx.IsHead() {
+		_ = file.Close()
+		resp.ResetBody()
+		resp.SkipBody = true
+		resp.Header.SetContentLength(length)
+		return
+	}
+	resp.SetBodyStream(fsFile{io.LimitReader(file, int64(length)), file}, length)
+}
+
+// openPrecompressed opens the precompressed file of the preferred encoding,
+// which is accepted by the client
+func (h *fsHandler) openPrecompressed(fctx *fasthttp.RequestCtx, name string) (string, fs.File, fs.FileInfo) {
+	// Ranges would refer to the compressed file
+	if len(fctx.Request.Header.Peek(HeaderRange)) > 0 {
+		return "", nil, nil
+	}
+	accept := utils.UnsafeString(fctx.Request.Header.Peek(HeaderAcceptEncoding))
+	offers := precompressedEncodings
+	for {
+		encoding := utils.AcceptedEncoding(accept, offers...)
+		if encoding == "" {
+			return "", nil, nil
+		}
+		if file, err := h.fsys.Open(name + precompressedSuffixes[encoding]); err == nil {
+			if stat, err := file.Stat(); err == nil && !stat.IsDir() {
+				return encoding, file, stat
+			}
+			_ = file.Close()
+		}
+		// Try the next accepted encoding
+		remaining := make([]string, 0, len(offers)-1)
+		for _, offer := range offers {
+			if offer != encoding {
+				remaining = append(remaining, offer)
+			}
+		}
+		offers = remaining
+	}
+}
+
+// error sets the status of the error opening or reading a file
+func (h *fsHandler) error(fctx *fasthttp.RequestCtx, err error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrInvalid):
+		fctx.Response.SetStatusCode(StatusNotFound)
+	case errors.Is(err, fs.ErrPermission):
+		fctx.Error(utils.StatusMessage(StatusForbidden), StatusForbidden)
+	default:
+		fctx.Error(utils.StatusMessage(StatusInternalServerError), StatusInternalServerError)
+	}
+}
+
+// dirList renders the entries of the directory as html
+func (h *fsHandler) dirList(fctx *fasthttp.RequestCtx, name string) {
+	entries, err := fs.ReadDir(h.fsys, name)
+	if err != nil {
+		h.error(fctx, err)
+		return
+	}
+
+	base := utils.TrimRight(string(fctx.Path()), '/')
+	baseEscaped := html.EscapeString(base + "/")
+	w := fctx.Response.BodyWriter()
+	fmt.Fprintf(w, "<html><head><title>%s</title><style>.dir { font-weight: bold }</style></head><body>", baseEscaped)
+	fmt.Fprintf(w, "<h1>%s</h1><ul>", baseEscaped)
+	if base != "" {
+		fmt.Fprintf(w, `<li><a href="%s" class="dir">..</a></li>`, html.EscapeString(base+"/.."))
+	}
+	// fs.ReadDir returns the entries sorted by name
+	for _, entry := range entries {
+		className := "file"
+		if entry.IsDir() {
+			className = "dir"
+		}
+		fmt.Fprintf(w, `<li><a href="%s" class="%s">%s</a></li>`,
+			html.EscapeString(base+"/"+entry.Name()), className, html.EscapeString(entry.Name()))
+	}
+	fmt.Fprint(w, "</ul></body></html>")
+	fctx.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
+}
@@ -0,0 +1,189 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+var testFS = fstest.MapFS{
+	"index.html":       {Data: []byte("<h1>Hello</h1>"), ModTime: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)},
+	"css/style.css":    {Data: []byte("body{}")},
+	"video/clip.bin":   {Data: []byte("0123456789")},
+	"docs/readme.txt":  {Data: []byte("read me")},
+	"docs/guide/a.txt": {Data: []byte("a")},
+}
+
+// go test -run Test_App_StaticFS
+func Test_App_StaticFS(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.StaticFS("/", testFS, Static{MaxAge: 60, ByteRange: true})
+	app.Group("/v1").StaticFS("/docs", testFS, Static{Browse: true})
+	app.Get("*", func(c *Ctx) error {
+		return c.SendString("fallback")
+	})
+
+	request := func(method, target string, header ...string) (*http.Response, string) {
+		req := httptest.NewRequest(method, target, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		return resp, string(body)
+	}
+
+	// Index
+	resp, body := request(MethodGet, "/")
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, "<h1>Hello</h1>", body)
+	utils.AssertEqual(t, MIMETextHTMLCharsetUTF8, resp.Header.Get(HeaderContentType))
+	utils.AssertEqual(t, "public, max-age=60", resp.Header.Get(HeaderCacheControl))
+	utils.AssertEqual(t, "Sun, 02 Jan 2022 03:04:05 GMT", resp.Header.Get(HeaderLastModified))
+
+	resp, body = request(MethodGet, "/", HeaderIfModifiedSince, "Sun, 02 Jan 2022 03:04:05 GMT")
+	utils.AssertEqual(t, StatusNotModified, resp.StatusCode)
+	utils.AssertEqual(t, "", body)
+
+	resp, body = request(MethodGet, "/css/style.css")
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, "body{}", body)
+	utils.AssertEqual(t, "text/css; charset=utf-8", resp.Header.Get(HeaderContentType))
+
+	resp, body = request(MethodHead, "/css/style.css")
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, "", body)
+	utils.AssertEqual(t, "6", resp.Header.Get(HeaderContentLength))
+
+	// Files outside of the FS don't exist
+	_, body = request(MethodGet, "/../static_fs.go")
+	utils.AssertEqual(t, "fallback", body)
+	_, body = request(MethodGet, "/missing.txt")
+	utils.AssertEqual(t, "fallback", body)
+	// Directories without an index are forbidden
+	_, body = request(MethodGet, "/css")
+	utils.AssertEqual(t, "fallback", body)
+
+	// Ranges
+	resp, body = request(MethodGet, "/video/clip.bin", HeaderRange, "bytes=2-4")
+	utils.AssertEqual(t, StatusPartialContent, resp.StatusCode)
+	utils.AssertEqual(t, "bytes 2-4/10", resp.Header.Get(HeaderContentRange))
+	utils.AssertEqual(t, "234", body)
+
+	resp, body = request(MethodGet, "/video/clip.bin", HeaderRange, "bytes=0-0,-1")
+	utils.AssertEqual(t, StatusPartialContent, resp.StatusCode)
+	_, params, err := mime.ParseMediaType(resp.Header.Get(HeaderContentType))
+	utils.AssertEqual(t, nil, err)
+	reader := multipart.NewReader(strings.NewReader(body), params["boundary"])
+	for _, expected := range []string{"0", "9"} {
+		part, err := reader.NextPart()
+		utils.AssertEqual(t, nil, err)
+		data, err := ioutil.ReadAll(part)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, expected, string(data))
+	}
+
+	resp, _ = request(MethodGet, "/video/clip.bin", HeaderRange, "bytes=20-")
+	utils.AssertEqual(t, StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+	utils.AssertEqual(t, "bytes */10", resp.Header.Get(HeaderContentRange))
+
+	// Without a modification time the If-Range can't match
+	resp, body = request(MethodGet, "/video/clip.bin", HeaderRange, "bytes=2-4", HeaderIfRange, "Sun, 02 Jan 2022 03:04:05 GMT")
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, "0123456789", body)
+
+	// Browse
+	resp, body = request(MethodGet, "/v1/docs/docs")
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, true, strings.Contains(body, `<a href="/v1/docs/docs/guide" class="dir">guide</a>`))
+	utils.AssertEqual(t, true, strings.Contains(body, `<a href="/v1/docs/docs/readme.txt" class="file">readme.txt</a>`))
+	_, body = request(MethodGet, "/v1/docs/docs/readme.txt")
+	utils.AssertEqual(t, "read me", body)
+}
+
+// go test -run Test_App_Static_Precompressed
+func Test_App_Static_Precompressed(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := map[string]string{"app.js": "plain", "app.js.br": "brotli", "app.js.gz": "gzip"}
+	for name, content := range files {
+		utils.AssertEqual(t, nil, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	app := New()
+	app.Static("/", dir, Static{Precompressed: true, ByteRange: true})
+
+	request := func(acceptEncoding string, header ...string) (*http.Response, string) {
+		req := httptest.NewRequest(MethodGet, "/app.js", nil)
+		req.Header.Set(HeaderAcceptEncoding, acceptEncoding)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := app.Test(req)
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		return resp, string(body)
+	}
+
+	resp, body := request("gzip, deflate, br")
+	utils.AssertEqual(t, "brotli", body)
+	utils.AssertEqual(t, "br", resp.Header.Get(HeaderContentEncoding))
+	utils.AssertEqual(t, true, strings.Contains(resp.Header.Get(HeaderContentType), "javascript"))
+	utils.AssertEqual(t, HeaderAcceptEncoding, resp.Header.Get(HeaderVary))
+
+	// There is no zstd file
+	resp, body = request("zstd, gzip;q=0.5")
+	utils.AssertEqual(t, "gzip", body)
+	utils.AssertEqual(t, "gzip", resp.Header.Get(HeaderContentEncoding))
+
+	resp, body = request("deflate")
+	utils.AssertEqual(t, "plain", body)
+	utils.AssertEqual(t, "", resp.Header.Get(HeaderContentEncoding))
+	utils.AssertEqual(t, HeaderAcceptEncoding, resp.Header.Get(HeaderVary))
+
+	// Ranges refer to the original file
+	resp, body = request("br", HeaderRange, "bytes=0-1")
+	utils.AssertEqual(t, StatusPartialContent, resp.StatusCode)
+	utils.AssertEqual(t, "pl", body)
+	utils.AssertEqual(t, "", resp.Header.Get(HeaderContentEncoding))
+}
+
+// go test -run Test_Ctx_SendFileFS
+func Test_Ctx_SendFileFS(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/:name", func(c *Ctx) error {
+		return c.SendFileFS(testFS, "docs/"+c.Params("name"))
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/readme.txt", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusOK, resp.StatusCode)
+	utils.AssertEqual(t, "bytes", resp.Header.Get(HeaderAcceptRanges))
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "read me", string(body))
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/missing.txt", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusNotFound, resp.StatusCode)
+	body, err = ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "sendfile: file docs/missing.txt not found", string(body))
+}
@@ -0,0 +1,269 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+// Package memory is a sharded in-memory storage, which implements
+// fiber.Storage, fiber.StorageWithContext and fiber.CASStorage. It's the
+// default storage of the stateful middleware, e.g. session, cache, limiter
+// and csrf, and keeps their state for this process only:
+//
+//	store := memory.New(memory.Config{Shards: 64})
+//	app.Use(limiter.New(limiter.Config{Storage: store}))
+//	app.Use(cache.New(cache.Config{Storage: store}))
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2/internal/xxhash"
+)
+
+// Config defines the config for the storage.
+type Config struct {
+	// Shards is the number of independently locked maps the keys are
+	// distributed to, more shards reduce the lock contention.
+	//
+	// Default: 32
+	Shards int
+
+	// GCInterval is the interval in which expired keys are deleted in the
+	// background. Expired keys are never returned, even before.
+	//
+	// Default: 10 * time.Second
+	GCInterval time.Duration
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Shards:     32,
+	GCInterval: 10 * time.Second,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Shards <= 0 {
+		cfg.Shards = ConfigDefault.Shards
+	}
+	if cfg.GCInterval <= 0 {
+		cfg.GCInterval = ConfigDefault.GCInterval
+	}
+	return cfg
+}
+
+// Storage is a sharded in-memory storage. The values aren't copied, so they
+// mustn't be modified after Set or when returned by Get.
+type Storage struct {
+	shards    []*shard
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type shard struct {
+	mux sync.RWMutex
+	db  map[string]entry
+}
+
+type entry struct {
+	// unix nanoseconds, 0 means no expiration
+	expiry int64
+	data   []byte
+}
+
+// expired reports if the entry has expired at now
+func (e entry) expired(now int64) bool {
+	return e.expiry != 0 && e.expiry <= now
+}
+
+// New creates a new memory storage
+func New(config ...Config) *Storage {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Create storage
+	s := &Storage{
+		shards: make([]*shard, cfg.Shards),
+		done:   make(chan struct{}),
+	}
+	for i := range s.shards {
+		s.shards[i] = &shard{db: make(map[string]entry)}
+	}
+
+	// Start garbage collector
+	go s.gc(cfg.GCInterval)
+
+	return s
+}
+
+// shard returns the shard of the key
+func (s *Storage) shard(key string) *shard {
+	return s.shards[xxhash.Sum64String(key)%uint64(len(s.shards))]
+}
+
+// expiry returns the expiry of an entry stored now for the duration
+func expiry(exp time.Duration) int64 {
+	if exp <= 0 {
+		return 0
+	}
+	return time.Now().Add(exp).UnixNano()
+}
+
+// Get value by key, nil if the key doesn't exist or has expired
+func (s *Storage) Get(key string) ([]byte, error) {
+	if len(key) <= 0 {
+		return nil, nil
+	}
+	sh := s.shard(key)
+	sh.mux.RLock()
+	e, ok := sh.db[key]
+	sh.mux.RUnlock()
+	if !ok || e.expired(time.Now().UnixNano()) {
+		return nil, nil
+	}
+	return e.data, nil
+}
+
+// Set key with value, 0 means no expiration
+func (s *Storage) Set(key string, val []byte, exp time.Duration) error {
+	// Ain't Nobody Got Time For That
+	if len(key) <= 0 || len(val) <= 0 {
+		return nil
+	}
+	sh := s.shard(key)
+	sh.mux.Lock()
+	sh.db[key] = entry{expiry(exp), val}
+	sh.mux.Unlock()
+	return nil
+}
+
+// CompareAndSwap sets the value of the key to val, if its current value equals old.
+// A nil old value means that the key must not exist.
+func (s *Storage) CompareAndSwap(key string, old, val []byte, exp time.Duration) (bool, error) {
+	// Ain't Nobody Got Time For That
+	if len(key) <= 0 || len(val) <= 0 {
+		return false, nil
+	}
+	sh := s.shard(key)
+	sh.mux.Lock()
+	defer sh.mux.Unlock()
+	e, ok := sh.db[key]
+	if ok && e.expired(time.Now().UnixNano()) {
+		ok = false
+	}
+	if ok != (old != nil) || (ok && !bytes.Equal(e.data, old)) {
+		return false, nil
+	}
+	sh.db[key] = entry{expiry(exp), val}
+	return true, nil
+}
+
+// Delete key by key
+func (s *Storage) Delete(key string) error {
+	// Ain't Nobody Got Time For That
+	if len(key) <= 0 {
+		return nil
+	}
+	sh := s.shard(key)
+	sh.mux.Lock()
+	delete(sh.db, key)
+	sh.mux.Unlock()
+	return nil
+}
+
+// Reset all keys
+func (s *Storage) Reset() error {
+	for _, sh := range s.shards {
+		sh.mux.Lock()
+		sh.db = make(map[string]entry)
+		sh.mux.Unlock()
+	}
+	return nil
+}
+
+// Len returns the number of stored keys, including the expired keys which
+// haven't been deleted by the garbage collector yet
+func (s *Storage) Len() int {
+	n := 0
+	for _, sh := range s.shards {
+		sh.mux.RLock()
+		n += len(sh.db)
+		sh.mux.RUnlock()
+	}
+	return n
+}
+
+// GetWithContext gets the value like Get, unless the context is done
+func (s *Storage) GetWithContext(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.Get(key)
+}
+
+// SetWithContext sets the value like Set, unless the context is done
+func (s *Storage) SetWithContext(ctx context.Context, key string, val []byte, exp time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.Set(key, val, exp)
+}
+
+// DeleteWithContext deletes the key like Delete, unless the context is done
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.Delete(key)
+}
+
+// ResetWithContext resets the storage like Reset, unless the context is done
+func (s *Storage) ResetWithContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.Reset()
+}
+
+// Close stops the garbage collector of the storage
+func (s *Storage) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+// gc deletes the expired keys periodically, one shard at a time, so that
+// the other shards stay available
+func (s *Storage) gc(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case t := <-ticker.C:
+			now := t.UnixNano()
+			for _, sh := range s.shards {
+				sh.mux.Lock()
+				for key, e := range sh.db {
+					if e.expired(now) {
+						delete(sh.db, key)
+					}
+				}
+				sh.mux.Unlock()
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package memory_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/storage/memory"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+var (
+	_ fiber.StorageWithContext = (*memory.Storage)(nil)
+	_ fiber.CASStorage         = (*memory.Storage)(nil)
+)
+
+// go test -run Test_Storage
+func Test_Storage(t *testing.T) {
+	t.Parallel()
+	s := memory.New(memory.Config{Shards: 4})
+	defer s.Close()
+
+	val, err := s.Get("john")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, val == nil)
+
+	utils.AssertEqual(t, nil, s.Set("john", []byte("doe"), 0))
+	utils.AssertEqual(t, nil, s.Set("", []byte("ignored"), 0))
+	utils.AssertEqual(t, nil, s.Set("empty", nil, 0))
+	val, err = s.Get("john")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "doe", string(val))
+	utils.AssertEqual(t, 1, s.Len())
+
+	utils.AssertEqual(t, nil, s.Delete("john"))
+	val, _ = s.Get("john")
+	utils.AssertEqual(t, true, val == nil)
+
+	for i := 0; i < 100; i++ {
+		utils.AssertEqual(t, nil, s.Set(strconv.Itoa(i), []byte("v"), 0))
+	}
+	utils.AssertEqual(t, 100, s.Len())
+	utils.AssertEqual(t, nil, s.Reset())
+	utils.AssertEqual(t, 0, s.Len())
+
+	// Close can be called several times
+	utils.AssertEqual(t, nil, s.Close())
+}
+
+// go test -run Test_Storage_Expiration
+func Test_Storage_Expiration(t *testing.T) {
+	t.Parallel()
+	s := memory.New(memory.Config{GCInterval: 50 * time.Millisecond})
+	defer s.Close()
+
+	utils.AssertEqual(t, nil, s.Set("john", []byte("doe"), 100*time.Millisecond))
+	val, _ := s.Get("john")
+	utils.AssertEqual(t, "doe", string(val))
+
+	time.Sleep(120 * time.Millisecond)
+	val, _ = s.Get("john")
+	utils.AssertEqual(t, true, val == nil)
+
+	// The garbage collector deletes the key in the background
+	time.Sleep(100 * time.Millisecond)
+	utils.AssertEqual(t, 0, s.Len())
+}
+
+// go test -run Test_Storage_CompareAndSwap
+func Test_Storage_CompareAndSwap(t *testing.T) {
+	t.Parallel()
+	s := memory.New()
+	defer s.Close()
+
+	ok, err := s.CompareAndSwap("counter", nil, []byte("1"), 0)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, ok)
+	ok, _ = s.CompareAndSwap("counter", nil, []byte("1"), 0)
+	utils.AssertEqual(t, false, ok)
+	ok, _ = s.CompareAndSwap("counter", []byte("2"), []byte("3"), 0)
+	utils.AssertEqual(t, false, ok)
+	ok, _ = s.CompareAndSwap("counter", []byte("1"), []byte("2"), 0)
+	utils.AssertEqual(t, true, ok)
+
+	// Concurrent increments aren't lost
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				old, _ := s.Get("counter")
+				n, _ := strconv.Atoi(string(old))
+				if ok, _ := s.CompareAndSwap("counter", old, []byte(strconv.Itoa(n+1)), 0); ok {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	val, _ := s.Get("counter")
+	utils.AssertEqual(t, "12", string(val))
+}
+
+// go test -run Test_Storage_WithContext
+func Test_Storage_WithContext(t *testing.T) {
+	t.Parallel()
+	s := memory.New()
+	defer s.Close()
+
+	ctx := context.Background()
+	utils.AssertEqual(t, nil, s.SetWithContext(ctx, "john", []byte("doe"), 0))
+	val, err := s.GetWithContext(ctx, "john")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "doe", string(val))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = s.GetWithContext(canceled, "john")
+	utils.AssertEqual(t, context.Canceled, err)
+	utils.AssertEqual(t, context.Canceled, s.SetWithContext(canceled, "john", []byte("x"), 0))
+	utils.AssertEqual(t, context.Canceled, s.DeleteWithContext(canceled, "john"))
+	utils.AssertEqual(t, context.Canceled, s.ResetWithContext(canceled))
+
+	utils.AssertEqual(t, nil, s.DeleteWithContext(ctx, "john"))
+	utils.AssertEqual(t, nil, s.ResetWithContext(ctx))
+	utils.AssertEqual(t, 0, s.Len())
+}
+
+// go test -v -run=^$ -bench=Benchmark_Storage -benchmem -count=4
+func Benchmark_Storage(b *testing.B) {
+	s := memory.New()
+	defer s.Close()
+	val := []byte("doe")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := strconv.Itoa(i % 1024)
+			_ = s.Set(key, val, time.Minute)
+			_, _ = s.Get(key)
+			i++
+		}
+	})
+}
@@ -0,0 +1,349 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+// Package testsupport provides helpers to test Fiber apps, e.g. contract tests
+// which replay recorded interactions or Pact files against an app.
+package testsupport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Interaction is a recorded request and the expected response, it has the
+// format of the interactions of Pact files.
+type Interaction struct {
+	Description string `json:"description"`
+	// ProviderState is the state the app has to be in, Pact v2
+	ProviderState string `json:"providerState,omitempty"`
+	// ProviderStates are the states the app has to be in, Pact v3
+	ProviderStates []ProviderState `json:"providerStates,omitempty"`
+	Request        Request         `json:"request"`
+	Response       Response        `json:"response"`
+}
+
+// ProviderState is a named state of the app, e.g. "user 1 exists"
+type ProviderState struct {
+	Name   string                 `json:"name"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// Request is a recorded request
+type Request struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   Query             `json:"query,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is sent as is if it's a JSON string, other values are sent as JSON
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// Response is the expected response of a request, only the given headers and
+// body fields are compared
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// Query is the query of a request, which is a string in Pact v2 files and
+// an object in Pact v3 files
+type Query url.Values
+
+// UnmarshalJSON decodes a query string, or an object of strings or lists of strings
+func (q *Query) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		values, err := url.ParseQuery(raw)
+		*q = Query(values)
+		return err
+	}
+	var list map[string][]string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*q = list
+		return nil
+	}
+	var single map[string]string
+	if err := json.Unmarshal(data, &single); err != nil {
+		return fmt.Errorf("testsupport: invalid query %s", data)
+	}
+	*q = make(Query, len(single))
+	for k, v := range single {
+		(*q)[k] = []string{v}
+	}
+	return nil
+}
+
+// Config defines the config for replaying interactions.
+type Config struct {
+	// States set up the provider states of the interactions by name, e.g. by
+	// seeding a database. Interactions with an unknown state fail.
+	//
+	// Optional. Default: nil
+	States map[string]func(params map[string]interface{}) error
+
+	// Validate checks the request and response of each interaction, e.g. the
+	// conformance with an OpenAPI document.
+	//
+	// Optional. Default: nil
+	Validate func(req *http.Request, resp *http.Response, body []byte) error
+
+	// Timeout of each request in milliseconds, -1 disables it. See App.Test.
+	//
+	// Optional. Default: -1
+	Timeout int
+}
+
+// Replay replays the interactions of the JSON files in dir against the app as
+// sub-tests, see Load:
+//
+//	func Test_Contract(t *testing.T) {
+//	    testsupport.Replay(t, newApp(), "testdata/contracts")
+//	}
+func Replay(t *testing.T, app *fiber.App, dir string, config ...Config) {
+	t.Helper()
+
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && strings.EqualFold(filepath.Ext(path), ".json") {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("testsupport: %v", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		interactions, err := Load(file)
+		if err != nil {
+			t.Fatalf("testsupport: %v", err)
+		}
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			Verify(t, app, interactions, config...)
+		})
+	}
+}
+
+// Load reads the interactions of a file, which is a Pact file, a list of
+// interactions or a single interaction.
+func Load(file string) ([]Interaction, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	var interactions []Interaction
+	if len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &interactions)
+	} else {
+		var pact struct {
+			Interactions []Interaction `json:"interactions"`
+			Interaction
+		}
+		if err = json.Unmarshal(data, &pact); err == nil {
+			interactions = pact.Interactions
+			if interactions == nil {
+				interactions = []Interaction{pact.Interaction}
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return interactions, nil
+}
+
+// Verify replays the interactions against the app as sub-tests, which fail
+// if the response doesn't match the expected one.
+func Verify(t *testing.T, app *fiber.App, interactions []Interaction, config ...Config) {
+	t.Helper()
+	cfg := Config{Timeout: -1}
+	if len(config) > 0 {
+		cfg = config[0]
+		if cfg.Timeout == 0 {
+			cfg.Timeout = -1
+		}
+	}
+
+	for i, interaction := range interactions {
+		interaction := interaction
+		name := interaction.Description
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		t.Run(name, func(t *testing.T) {
+			if err := verify(app, interaction, cfg); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// verify replays the interaction and compares the response
+func verify(app *fiber.App, interaction Interaction, cfg Config) error {
+	states := interaction.ProviderStates
+	if interaction.ProviderState != "" {
+		states = append([]ProviderState{{Name: interaction.ProviderState}}, states...)
+	}
+	for _, state := range states {
+		setup, ok := cfg.States[state.Name]
+		if !ok {
+			return fmt.Errorf("no setup for provider state %q", state.Name)
+		}
+		if err := setup(state.Params); err != nil {
+			return fmt.Errorf("provider state %q: %v", state.Name, err)
+		}
+	}
+
+	req := newRequest(interaction.Request)
+	resp, err := app.Test(req, cfg.Timeout)
+	if err != nil {
+		return err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err = compare(interaction.Response, resp, body); err != nil {
+		return fmt.Errorf("%s %s: %v", req.Method, req.URL.RequestURI(), err)
+	}
+	if cfg.Validate != nil {
+		if err = cfg.Validate(req, resp, body); err != nil {
+			return fmt.Errorf("%s %s: %v", req.Method, req.URL.RequestURI(), err)
+		}
+	}
+	return nil
+}
+
+// newRequest creates the recorded request
+func newRequest(r Request) *http.Request {
+	method := r.Method
+	if method == "" {
+		method = fiber.MethodGet
+	}
+	target := r.Path
+	if len(r.Query) > 0 {
+		target += "?" + url.Values(r.Query).Encode()
+	}
+
+	var body []byte
+	var text string
+	isText := json.Unmarshal(r.Body, &text) == nil
+	if isText {
+		body = []byte(text)
+	} else if len(r.Body) > 0 && string(r.Body) != "null" {
+		body = r.Body
+	}
+
+	req := httptest.NewRequest(utils.ToUpper(method), target, bytes.NewReader(body))
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+	if body != nil && !isText && req.Header.Get(fiber.HeaderContentType) == "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	return req
+}
+
+// compare reports the first difference between the expected and actual response
+func compare(expected Response, resp *http.Response, body []byte) error {
+	if expected.Status != 0 && expected.Status != resp.StatusCode {
+		return fmt.Errorf("expected status %d, got %d", expected.Status, resp.StatusCode)
+	}
+
+	for k, v := range expected.Headers {
+		actual := resp.Header.Get(k)
+		// Parameters like the charset are only compared if they are expected
+		if strings.EqualFold(k, fiber.HeaderContentType) && !strings.Contains(v, ";") {
+			actual = strings.TrimSpace(strings.SplitN(actual, ";", 2)[0])
+		}
+		if !strings.EqualFold(strings.ReplaceAll(v, " ", ""), strings.ReplaceAll(actual, " ", "")) {
+			return fmt.Errorf("expected header %s %q, got %q", k, v, actual)
+		}
+	}
+
+	if len(expected.Body) == 0 || string(expected.Body) == "null" {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(expected.Body, &text); err == nil && !isJSON(resp.Header.Get(fiber.HeaderContentType)) {
+		if text != string(body) {
+			return fmt.Errorf("expected body %q, got %q", text, body)
+		}
+		return nil
+	}
+
+	var want, got interface{}
+	if err := json.Unmarshal(expected.Body, &want); err != nil {
+		return fmt.Errorf("invalid expected body: %v", err)
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		return fmt.Errorf("expected a JSON body, got %q", body)
+	}
+	return match("$", want, got)
+}
+
+// match compares JSON values, objects may have more fields than expected
+func match(path string, want, got interface{}) error {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		obj, ok := got.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object, got %v", path, got)
+		}
+		keys := make([]string, 0, len(want))
+		for k := range want {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v, ok := obj[k]
+			if !ok {
+				return fmt.Errorf("%s.%s: missing", path, k)
+			}
+			if err := match(path+"."+k, want[k], v); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		list, ok := got.([]interface{})
+		if !ok || len(list) != len(want) {
+			return fmt.Errorf("%s: expected %d items, got %v", path, len(want), got)
+		}
+		for i := range want {
+			if err := match(path+"["+strconv.Itoa(i)+"]", want[i], list[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		if want != got {
+			return fmt.Errorf("%s: expected %v, got %v", path, want, got)
+		}
+	}
+	return nil
+}
+
+// isJSON reports if the content type is a JSON media type
+func isJSON(contentType string) bool {
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	return mediaType == fiber.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")
+}
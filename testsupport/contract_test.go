@@ -0,0 +1,128 @@
+package testsupport
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func newApp(users map[int]user, nextID *int) *fiber.App {
+	app := fiber.New()
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+	app.Get("/users", func(c *fiber.Ctx) error {
+		var found []user
+		for _, u := range users {
+			if u.Name == c.Query("name") {
+				found = append(found, u)
+			}
+		}
+		return c.JSON(found)
+	})
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		id, _ := c.ParamsInt("id")
+		u, ok := users[id]
+		if !ok {
+			return fiber.ErrNotFound
+		}
+		return c.JSON(u)
+	})
+	app.Post("/users", func(c *fiber.Ctx) error {
+		var u user
+		if err := c.BodyParser(&u); err != nil {
+			return err
+		}
+		u.ID = *nextID
+		users[u.ID] = u
+		c.Location("/users/" + strconv.Itoa(u.ID))
+		return c.Status(fiber.StatusCreated).JSON(u)
+	})
+	return app
+}
+
+// go test -run Test_Replay
+func Test_Replay(t *testing.T) {
+	users := map[int]user{}
+	nextID := 1
+	app := newApp(users, &nextID)
+
+	var validated int
+	Replay(t, app, "testdata", Config{
+		States: map[string]func(map[string]interface{}) error{
+			"user 1 exists": func(map[string]interface{}) error {
+				users[1] = user{ID: 1, Name: "john"}
+				return nil
+			},
+			"no users": func(params map[string]interface{}) error {
+				for id := range users {
+					delete(users, id)
+				}
+				nextID = int(params["next_id"].(float64))
+				return nil
+			},
+		},
+		Validate: func(req *http.Request, resp *http.Response, body []byte) error {
+			validated++
+			return nil
+		},
+	})
+	utils.AssertEqual(t, 4, validated)
+}
+
+// go test -run Test_Load
+func Test_Load(t *testing.T) {
+	t.Parallel()
+	interactions, err := Load("testdata/users.pact.json")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, 2, len(interactions))
+	utils.AssertEqual(t, "user 1 exists", interactions[0].ProviderState)
+	utils.AssertEqual(t, []string{"john"}, interactions[1].Request.Query["name"])
+
+	interactions, err = Load("testdata/create_user.json")
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, 1, len(interactions))
+	utils.AssertEqual(t, "no users", interactions[0].ProviderStates[0].Name)
+	utils.AssertEqual(t, []string{"false"}, interactions[0].Request.Query["notify"])
+
+	_, err = Load("testdata/missing.json")
+	utils.AssertEqual(t, true, err != nil)
+}
+
+// go test -run Test_Verify_Mismatch
+func Test_Verify_Mismatch(t *testing.T) {
+	t.Parallel()
+	app := newApp(map[int]user{1: {ID: 1, Name: "john"}}, new(int))
+
+	test := func(response Response, state ...string) string {
+		interaction := Interaction{
+			Request:  Request{Method: fiber.MethodGet, Path: "/users/1"},
+			Response: response,
+		}
+		if len(state) > 0 {
+			interaction.ProviderState = state[0]
+		}
+		err := verify(app, interaction, Config{Timeout: -1})
+		if err == nil {
+			return ""
+		}
+		return err.Error()
+	}
+
+	utils.AssertEqual(t, "", test(Response{Status: 200, Body: []byte(`{"name":"john"}`)}))
+	utils.AssertEqual(t, "GET /users/1: expected status 404, got 200", test(Response{Status: 404}))
+	utils.AssertEqual(t, "GET /users/1: $.name: expected jane, got john", test(Response{Body: []byte(`{"name":"jane"}`)}))
+	utils.AssertEqual(t, "GET /users/1: $.email: missing", test(Response{Body: []byte(`{"email":"john@example.com"}`)}))
+	utils.AssertEqual(t, "GET /users/1: $: expected 1 items, got map[id:1 name:john]", test(Response{Body: []byte(`[{"id":1}]`)}))
+	utils.AssertEqual(t, `GET /users/1: expected header Content-Type "text/plain", got "application/json"`,
+		test(Response{Headers: map[string]string{"Content-Type": "text/plain"}}))
+	utils.AssertEqual(t, `no setup for provider state "unknown"`, test(Response{}, "unknown"))
+}
@@ -0,0 +1,236 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package testsupport
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LoadRequest is a request sent by LoadTest
+type LoadRequest struct {
+	Method string
+	// Path may contain a query string
+	Path    string
+	Headers map[string]string
+	Body    []byte
+}
+
+// LoadProfile describes the synthetic load of LoadTest
+type LoadProfile struct {
+	// Requests are sent round-robin
+	//
+	// Required.
+	Requests []LoadRequest
+
+	// Total is the number of measured requests
+	//
+	// Optional. Default: 1000
+	Total int
+
+	// Duration stops the run early once it has elapsed
+	//
+	// Optional. Default: 0, i.e. until Total requests have been sent
+	Duration time.Duration
+
+	// Concurrency is the number of concurrent clients
+	//
+	// Optional. Default: runtime.GOMAXPROCS(0)
+	Concurrency int
+
+	// Warmup is the number of requests sent before the measurement, e.g. to
+	// fill caches and pools
+	//
+	// Optional. Default: 0
+	Warmup int
+}
+
+// LatencyBucket is a bucket of the latency histogram of a LoadReport
+type LatencyBucket struct {
+	// Le is the inclusive upper bound of the bucket
+	Le    time.Duration
+	Count int
+}
+
+// LoadReport is the outcome of LoadTest
+type LoadReport struct {
+	Requests int
+	// Errors is the number of responses with a status >= 500
+	Errors   int
+	Statuses map[int]int
+	Duration time.Duration
+	// Throughput in requests per second
+	Throughput float64
+
+	Min, Mean, Max time.Duration
+	P50, P90, P99  time.Duration
+	// Histogram has exponential buckets, from 1µs doubling up to the max latency
+	Histogram []LatencyBucket
+
+	// AllocsPerRequest and BytesPerRequest are the heap allocations of the
+	// process per request, including the request generation
+	AllocsPerRequest float64
+	BytesPerRequest  float64
+
+	latencies []time.Duration
+}
+
+// LoadTest drives synthetic load through the app in-process, see
+// fiber.App.Dispatch, so that the performance characteristics of routes can be
+// asserted by tests without external tools:
+//
+//	report, err := testsupport.LoadTest(app, testsupport.LoadProfile{
+//		Requests: []testsupport.LoadRequest{{Method: fiber.MethodGet, Path: "/users/1"}},
+//		Total:    10000,
+//	})
+//	if err != nil || report.Errors > 0 || report.P99 > 5*time.Millisecond {
+//		t.Fatalf("unexpected load test result: %v\n%s", err, report)
+//	}
+//
+// The latencies exclude the network, they're meant to compare routes and
+// detect regressions rather than to predict the latencies of a deployment.
+func LoadTest(app *fiber.App, profile LoadProfile) (*LoadReport, error) {
+	if len(profile.Requests) == 0 {
+		return nil, fmt.Errorf("testsupport: load profile without requests")
+	}
+	if profile.Total <= 0 {
+		profile.Total = 1000
+	}
+	if profile.Concurrency <= 0 {
+		profile.Concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	// Fail fast on invalid requests and warm up
+	for i := 0; i < len(profile.Requests) || i < profile.Warmup; i++ {
+		r := profile.Requests[i%len(profile.Requests)]
+		if _, err := app.Dispatch(r.Method, r.Path, r.Headers, r.Body); err != nil {
+			return nil, fmt.Errorf("testsupport: %v", err)
+		}
+	}
+
+	var (
+		next     int64 = -1
+		mux      sync.Mutex
+		wg       sync.WaitGroup
+		before   runtime.MemStats
+		after    runtime.MemStats
+		report   = &LoadReport{Statuses: make(map[int]int)}
+		deadline time.Time
+	)
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	if profile.Duration > 0 {
+		deadline = start.Add(profile.Duration)
+	}
+
+	for w := 0; w < profile.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses := make(map[int]int)
+			latencies := make([]time.Duration, 0, profile.Total/profile.Concurrency+1)
+			for {
+				i := atomic.AddInt64(&next, 1)
+				if i >= int64(profile.Total) || (!deadline.IsZero() && time.Now().After(deadline)) {
+					break
+				}
+				r := profile.Requests[i%int64(len(profile.Requests))]
+				began := time.Now()
+				resp, _ := app.Dispatch(r.Method, r.Path, r.Headers, r.Body)
+				latencies = append(latencies, time.Since(began))
+				statuses[resp.StatusCode()]++
+			}
+			mux.Lock()
+			report.latencies = append(report.latencies, latencies...)
+			for status, n := range statuses {
+				report.Statuses[status] += n
+			}
+			mux.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	runtime.ReadMemStats(&after)
+	report.Requests = len(report.latencies)
+	for status, n := range report.Statuses {
+		if status >= fiber.StatusInternalServerError {
+			report.Errors += n
+		}
+	}
+	if report.Requests > 0 {
+		report.AllocsPerRequest = float64(after.Mallocs-before.Mallocs) / float64(report.Requests)
+		report.BytesPerRequest = float64(after.TotalAlloc-before.TotalAlloc) / float64(report.Requests)
+		report.Throughput = float64(report.Requests) / report.Duration.Seconds()
+	}
+	report.summarize()
+	return report, nil
+}
+
+// summarize computes the latency statistics
+func (r *LoadReport) summarize() {
+	if len(r.latencies) == 0 {
+		return
+	}
+	sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+
+	var sum time.Duration
+	for _, latency := range r.latencies {
+		sum += latency
+	}
+	r.Min, r.Max = r.latencies[0], r.latencies[len(r.latencies)-1]
+	r.Mean = sum / time.Duration(len(r.latencies))
+	r.P50, r.P90, r.P99 = r.Percentile(50), r.Percentile(90), r.Percentile(99)
+
+	i := 0
+	for le := time.Microsecond; ; le *= 2 {
+		bucket := LatencyBucket{Le: le}
+		for ; i < len(r.latencies) && r.latencies[i] <= le; i++ {
+			bucket.Count++
+		}
+		r.Histogram = append(r.Histogram, bucket)
+		if i == len(r.latencies) {
+			break
+		}
+	}
+}
+
+// Percentile returns the latency of the percentile p, e.g. 99.9
+func (r *LoadReport) Percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	i := int(p / 100 * float64(len(r.latencies)))
+	if i >= len(r.latencies) {
+		i = len(r.latencies) - 1
+	} else if i < 0 {
+		i = 0
+	}
+	return r.latencies[i]
+}
+
+// String summarizes the report, e.g. for test logs
+func (r *LoadReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "requests: %d in %v (%.0f req/s), errors: %d, statuses: %v\n",
+		r.Requests, r.Duration.Round(time.Millisecond), r.Throughput, r.Errors, r.Statuses)
+	fmt.Fprintf(&b, "latency: min %v, mean %v, p50 %v, p90 %v, p99 %v, max %v\n",
+		r.Min, r.Mean, r.P50, r.P90, r.P99, r.Max)
+	fmt.Fprintf(&b, "allocs: %.1f/req, %.0f B/req\n", r.AllocsPerRequest, r.BytesPerRequest)
+	for _, bucket := range r.Histogram {
+		if bucket.Count > 0 {
+			fmt.Fprintf(&b, "  <= %-8v %d\n", bucket.Le, bucket.Count)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package testsupport
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_LoadTest
+func Test_LoadTest(t *testing.T) {
+	t.Parallel()
+	app := newApp(map[int]user{1: {ID: 1, Name: "john"}}, new(int))
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return fiber.ErrServiceUnavailable
+	})
+
+	report, err := LoadTest(app, LoadProfile{
+		Requests: []LoadRequest{
+			{Method: fiber.MethodGet, Path: "/users/1"},
+			{Method: fiber.MethodGet, Path: "/users/2"},
+			{Method: fiber.MethodGet, Path: "/health"},
+			{Method: fiber.MethodGet, Path: "/fail"},
+		},
+		Total:       400,
+		Concurrency: 4,
+		Warmup:      10,
+	})
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, 400, report.Requests)
+	utils.AssertEqual(t, map[int]int{200: 200, 404: 100, 503: 100}, report.Statuses)
+	utils.AssertEqual(t, 100, report.Errors)
+	utils.AssertEqual(t, true, report.Min <= report.P50 && report.P50 <= report.P99 && report.P99 <= report.Max)
+	utils.AssertEqual(t, true, report.Mean > 0 && report.Throughput > 0 && report.AllocsPerRequest > 0)
+
+	counted := 0
+	for _, bucket := range report.Histogram {
+		counted += bucket.Count
+	}
+	utils.AssertEqual(t, 400, counted)
+	utils.AssertEqual(t, true, report.Histogram[len(report.Histogram)-1].Le >= report.Max)
+	utils.AssertEqual(t, true, strings.HasPrefix(report.String(), "requests: 400 in "))
+}
+
+// go test -run Test_LoadTest_Duration
+func Test_LoadTest_Duration(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+
+	report, err := LoadTest(app, LoadProfile{
+		Requests:    []LoadRequest{{Method: fiber.MethodGet, Path: "/"}},
+		Total:       1 << 20,
+		Duration:    50 * time.Millisecond,
+		Concurrency: 2,
+	})
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, true, report.Requests > 0 && report.Requests < 1<<20)
+	utils.AssertEqual(t, true, report.Min >= time.Millisecond)
+
+	_, err = LoadTest(app, LoadProfile{})
+	utils.AssertEqual(t, "testsupport: load profile without requests", err.Error())
+	_, err = LoadTest(app, LoadProfile{Requests: []LoadRequest{{Method: fiber.MethodGet, Path: "missing-slash"}}})
+	utils.AssertEqual(t, `testsupport: dispatch: path "missing-slash" must start with a slash`, err.Error())
+}
@@ -0,0 +1,94 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+// traceContextKey define the key name for storing the trace context of the request
+const traceContextKey = "__local_trace_context__"
+
+// TraceContext is the W3C Trace Context and Baggage of a request, see
+// https://www.w3.org/TR/trace-context/ and https://www.w3.org/TR/baggage/
+type TraceContext struct {
+	// TraceParent is the traceparent header, i.e.
+	// "<version>-<trace-id>-<parent-id>-<trace-flags>", where the parent id
+	// is the id of the current span
+	TraceParent string
+	TraceState  string
+	Baggage     string
+}
+
+// Valid reports if the traceparent has the W3C format with a non-zero
+// trace id and parent id
+func (tc TraceContext) Valid() bool {
+	p := tc.TraceParent
+	if len(p) < 55 || p[2] != '-' || p[35] != '-' || p[52] != '-' || (len(p) > 55 && p[55] != '-') {
+		return false
+	}
+	// Version ff is forbidden, version 00 has no other fields
+	if p[:2] == "ff" || (p[:2] == "00" && len(p) != 55) {
+		return false
+	}
+	return isLowerHex(p[:2]) && isNonZeroHex(p[3:35]) && isNonZeroHex(p[36:52]) && isLowerHex(p[53:55])
+}
+
+// isLowerHex reports if s consists of lowercase hex digits
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isNonZeroHex reports if s consists of lowercase hex digits, which aren't all zero
+func isNonZeroHex(s string) bool {
+	return isLowerHex(s) && s != "00000000000000000000000000000000"[:len(s)]
+}
+
+// TraceContext returns the trace context of the request, which was set by
+// a tracing middleware with SetTraceContext, or the zero TraceContext.
+func (c *Ctx) TraceContext() TraceContext {
+	tc, _ := c.fasthttp.UserValue(traceContextKey).(TraceContext)
+	return tc
+}
+
+// SetTraceContext sets the trace context of the request, e.g. by a tracing
+// middleware with the id of the span of the request as parent id, so that
+// outgoing requests of the client continue the trace, see Agent.WithCtx.
+func (c *Ctx) SetTraceContext(tc TraceContext) {
+	c.setLocal(traceContextKey, tc)
+}
+
+// WithCtx propagates the trace context of the originating Ctx, see
+// Ctx.TraceContext, with the traceparent, tracestate and baggage headers,
+// so that distributed traces don't break at outgoing requests:
+//
+//	app.Get("/orders/:id", func(c *fiber.Ctx) error {
+//		code, body, errs := fiber.Get("http://inventory/items").WithCtx(c).Bytes()
+//		// ...
+//	})
+//
+// Headers, which have been set explicitly, are kept. It's a no-op if the
+// Ctx has no valid trace context or NoTracePropagation is set, which must
+// be set before.
+func (a *Agent) WithCtx(c *Ctx) *Agent {
+	if a.NoTracePropagation {
+		return a
+	}
+	tc := c.TraceContext()
+	if !tc.Valid() {
+		return a
+	}
+	for _, h := range [...]struct{ key, value string }{
+		{HeaderTraceParent, tc.TraceParent},
+		{HeaderTraceState, tc.TraceState},
+		{HeaderBaggage, tc.Baggage},
+	} {
+		if h.value != "" && len(a.req.Header.Peek(h.key)) == 0 {
+			a.req.Header.Set(h.key, h.value)
+		}
+	}
+	return a
+}
@@ -0,0 +1,81 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// go test -run Test_TraceContext_Valid
+func Test_TraceContext_Valid(t *testing.T) {
+	t.Parallel()
+	for parent, valid := range map[string]bool{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01":      true,
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-more": true,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-more": false,
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01":      false,
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01":      false,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01":      false,
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01":      false,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7":         false,
+		"": false,
+	} {
+		utils.AssertEqual(t, valid, TraceContext{TraceParent: parent}.Valid(), parent)
+	}
+}
+
+// go test -run Test_Client_Agent_WithCtx
+func Test_Client_Agent_WithCtx(t *testing.T) {
+	t.Parallel()
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	ln := fasthttputil.NewInmemoryListener()
+	downstream := New(Config{DisableStartupMessage: true})
+	downstream.Get("/", func(c *Ctx) error {
+		return c.SendString(c.Get(HeaderTraceParent) + "|" + c.Get(HeaderTraceState) + "|" + c.Get(HeaderBaggage))
+	})
+	go func() { utils.AssertEqual(t, nil, downstream.Listener(ln)) }()
+
+	app := New()
+	// A tracing middleware sets the trace context of the request
+	app.Use(func(c *Ctx) error {
+		if c.Query("traced") != "" {
+			c.SetTraceContext(TraceContext{TraceParent: parent, TraceState: "vendor=value", Baggage: "user=john"})
+		}
+		return c.Next()
+	})
+	app.Get("/", func(c *Ctx) error {
+		client := &Client{NoTracePropagation: c.Query("disabled") != ""}
+		a := client.Get("http://example.com").WithCtx(c)
+		if c.Query("explicit") != "" {
+			a = client.Get("http://example.com").Set(HeaderBaggage, "explicit=1").WithCtx(c)
+		}
+		a.HostClient.Dial = func(addr string) (net.Conn, error) { return ln.Dial() }
+		_, body, errs := a.String()
+		if len(errs) > 0 {
+			return errs[0]
+		}
+		return c.SendString(body)
+	})
+
+	for target, expected := range map[string]string{
+		"/?traced=1":            parent + "|vendor=value|user=john",
+		"/?traced=1&explicit=1": parent + "|vendor=value|explicit=1",
+		"/?traced=1&disabled=1": "||",
+		"/":                     "||",
+	} {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, target, nil))
+		utils.AssertEqual(t, nil, err, "app.Test(req)")
+		body, err := ioutil.ReadAll(resp.Body)
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, expected, string(body), target)
+	}
+}
@@ -0,0 +1,19 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+// transactionKey define the key name for storing the transaction of the request
+const transactionKey = "__local_transaction__"
+
+// Transaction returns the transaction of the request, which was set by the
+// transaction middleware or SetTransaction, or nil.
+func (c *Ctx) Transaction() interface{} {
+	return c.fasthttp.UserValue(transactionKey)
+}
+
+// SetTransaction sets the transaction of the request, e.g. a *sql.Tx
+func (c *Ctx) SetTransaction(tx interface{}) {
+	c.setLocal(transactionKey, tx)
+}
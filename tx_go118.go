@@ -0,0 +1,30 @@
+//go:build go1.18
+// +build go1.18
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import "fmt"
+
+// Tx returns the transaction of the request as T, which was begun by the
+// transaction middleware:
+//
+//	app.Post("/orders", func(c *fiber.Ctx) error {
+//		tx := fiber.Tx[*sql.Tx](c)
+//		_, err := tx.Exec("INSERT INTO orders ...")
+//		return err
+//	})
+//
+// It panics if the request has no transaction of type T, because the
+// middleware is missing or configured for another driver.
+func Tx[T any](c *Ctx) T {
+	tx, ok := c.Transaction().(T)
+	if !ok {
+		var zero T
+		panic(fmt.Sprintf("fiber: the request has no transaction of type %T but %T", zero, c.Transaction()))
+	}
+	return tx
+}
@@ -0,0 +1,36 @@
+//go:build go1.18
+// +build go1.18
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+type testTx struct {
+	name string
+}
+
+// go test -run Test_Tx
+func Test_Tx(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	utils.AssertEqual(t, nil, c.Transaction())
+	c.SetTransaction(&testTx{name: "orders"})
+	utils.AssertEqual(t, "orders", Tx[*testTx](c).name)
+
+	defer func() {
+		utils.AssertEqual(t, "fiber: the request has no transaction of type string but *fiber.testTx", recover())
+	}()
+	_ = Tx[string](c)
+}
@@ -0,0 +1,46 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"reflect"
+)
+
+// Input declares the request type of the latest registered route, which is
+// bound by BindTo, e.g. for API docs like the ones of the openapi middleware:
+//
+//	type CreateUser struct {
+//	    OrgID int    `params:"org"`
+//	    Name  string `json:"name"`
+//	}
+//
+//	app.Post("/orgs/:org/users", handler)
+//	app.Input(CreateUser{}).Output(User{})
+//
+// The type is available by Route.Input, the value itself is ignored.
+func (app *App) Input(input interface{}) *App {
+	typ := reflect.TypeOf(input)
+	app.mutex.Lock()
+	for _, route := range app.latestRoutes() {
+		route.Input = typ
+	}
+	app.mutex.Unlock()
+
+	return app
+}
+
+// Output declares the response type of the latest registered route, see
+// App.Input. The type is available by Route.Output, the value itself is
+// ignored.
+func (app *App) Output(output interface{}) *App {
+	typ := reflect.TypeOf(output)
+	app.mutex.Lock()
+	for _, route := range app.latestRoutes() {
+		route.Output = typ
+	}
+	app.mutex.Unlock()
+
+	return app
+}
@@ -0,0 +1,372 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// UpstreamState is the state of a server of an UpstreamPool
+type UpstreamState int32
+
+const (
+	// UpstreamHealthy servers get requests
+	UpstreamHealthy UpstreamState = iota
+	// UpstreamUnhealthy servers failed the last active health check
+	UpstreamUnhealthy
+	// UpstreamEjected servers failed MaxFails requests in a row, they get
+	// requests again once FailTimeout expired
+	UpstreamEjected
+)
+
+// String returns "healthy", "unhealthy" or "ejected".
+func (s UpstreamState) String() string {
+	switch s {
+	case UpstreamHealthy:
+		return "healthy"
+	case UpstreamUnhealthy:
+		return "unhealthy"
+	case UpstreamEjected:
+		return "ejected"
+	default:
+		return ""
+	}
+}
+
+// UpstreamServer is a server of an UpstreamPool
+type UpstreamServer struct {
+	// Addr of the server in the form of host:port
+	Addr string `json:"addr"`
+	// Weight of the server, a server with weight 2 gets twice as many
+	// requests as one with weight 1.
+	//
+	// Optional. Default: 1
+	Weight int `json:"weight"`
+}
+
+// UpstreamStats are the statistics of a server of an UpstreamPool, e.g. for metrics
+type UpstreamStats struct {
+	Addr     string        `json:"addr"`
+	Weight   int           `json:"weight"`
+	State    UpstreamState `json:"state"`
+	Requests uint64        `json:"requests"`
+	Failures uint64        `json:"failures"`
+}
+
+// UpstreamPoolConfig is a struct holding the configuration of an UpstreamPool
+type UpstreamPoolConfig struct {
+	// Servers of the pool, they can be replaced with UpstreamPool.Set
+	//
+	// Optional. Default: nil
+	Servers []UpstreamServer
+
+	// HealthCheck reports if a server is healthy. It's called for each
+	// server every HealthCheckInterval, unhealthy servers get no requests
+	// until a following check succeeds.
+	//
+	// Optional. Default: nil
+	HealthCheck func(addr string) bool
+
+	// HealthCheckInterval is the interval of the health checks
+	//
+	// Optional. Default: 10 * time.Second
+	HealthCheckInterval time.Duration
+
+	// MaxFails is the number of failed requests in a row, after which a
+	// server is ejected for FailTimeout. Requests fail if the server can't be
+	// reached or responds with 502, 503 or 504. Use a negative value to
+	// disable the ejection.
+	//
+	// Optional. Default: 3
+	MaxFails int
+
+	// FailTimeout is the duration of the ejection of a server
+	//
+	// Optional. Default: 10 * time.Second
+	FailTimeout time.Duration
+
+	// OnStateChange is called when the state of a server changes, it must
+	// not block.
+	//
+	// Optional. Default: nil
+	OnStateChange func(addr string, from, to UpstreamState)
+
+	// NewClient creates the client of a server, e.g. to configure TLS
+	//
+	// Optional. Default: a fasthttp.HostClient of the address
+	NewClient func(addr string) *fasthttp.HostClient
+}
+
+// UpstreamPool balances requests among upstream servers by their weight. It
+// skips servers, which fail the active health check or too many requests, all
+// servers are used if all of them are skipped. The proxy middleware and the
+// client use it:
+//
+//	pool := fiber.NewUpstreamPool(fiber.UpstreamPoolConfig{
+//		Servers: []fiber.UpstreamServer{
+//			{Addr: "10.0.0.1:8080", Weight: 2},
+//			{Addr: "10.0.0.2:8080"},
+//		},
+//	})
+//	defer pool.Close()
+//
+//	code, body, errs := fiber.Get("http://api/users").Upstreams(pool).Bytes()
+type UpstreamPool struct {
+	config UpstreamPoolConfig
+
+	mutex     sync.Mutex
+	upstreams []*Upstream
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Upstream is a server of an UpstreamPool
+type Upstream struct {
+	// The counters are accessed atomically and come first for the alignment
+	requests uint64
+	failures uint64
+
+	addr   string
+	client *fasthttp.HostClient
+
+	state        int32
+	fails        int32
+	ejectedUntil int64
+
+	// Guarded by the mutex of the pool
+	weight        int
+	currentWeight int
+}
+
+// Addr returns the address of the server.
+func (u *Upstream) Addr() string {
+	return u.addr
+}
+
+// Client returns the client of the server.
+func (u *Upstream) Client() *fasthttp.HostClient {
+	return u.client
+}
+
+// State returns the state of the server.
+func (u *Upstream) State() UpstreamState {
+	return UpstreamState(atomic.LoadInt32(&u.state))
+}
+
+// NewUpstreamPool creates a new UpstreamPool, which runs the health checks
+// until it's closed.
+func NewUpstreamPool(config ...UpstreamPoolConfig) *UpstreamPool {
+	p := &UpstreamPool{done: make(chan struct{})}
+	if len(config) > 0 {
+		p.config = config[0]
+	}
+	if p.config.HealthCheckInterval <= 0 {
+		p.config.HealthCheckInterval = 10 * time.Second
+	}
+	if p.config.MaxFails == 0 {
+		p.config.MaxFails = 3
+	}
+	if p.config.FailTimeout <= 0 {
+		p.config.FailTimeout = 10 * time.Second
+	}
+	if p.config.NewClient == nil {
+		p.config.NewClient = func(addr string) *fasthttp.HostClient {
+			return &fasthttp.HostClient{Addr: addr}
+		}
+	}
+	p.Set(p.config.Servers...)
+
+	if p.config.HealthCheck != nil {
+		go p.watchHealth()
+	}
+	return p
+}
+
+// Set replaces the servers of the pool. Servers, which are kept, keep their
+// connections, state and statistics.
+func (p *UpstreamPool) Set(servers ...UpstreamServer) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	existing := make(map[string]*Upstream, len(p.upstreams))
+	for _, u := range p.upstreams {
+		existing[u.addr] = u
+	}
+	upstreams := make([]*Upstream, 0, len(servers))
+	for _, server := range servers {
+		u, ok := existing[server.Addr]
+		if !ok {
+			u = &Upstream{
+				addr:   server.Addr,
+				client: p.config.NewClient(server.Addr),
+			}
+		}
+		u.weight = server.Weight
+		if u.weight <= 0 {
+			u.weight = 1
+		}
+		upstreams = append(upstreams, u)
+	}
+	p.upstreams = upstreams
+}
+
+// Next returns the next server by the smooth weighted round-robin algorithm,
+// it returns nil if the pool has no servers.
+func (p *UpstreamPool) Next() *Upstream {
+	now := time.Now().UnixNano()
+
+	p.mutex.Lock()
+	var next *Upstream
+	// All servers are used if none of them is available
+	for _, all := range [2]bool{false, true} {
+		total := 0
+		for _, u := range p.upstreams {
+			if !all && !u.available(now) {
+				continue
+			}
+			u.currentWeight += u.weight
+			total += u.weight
+			if next == nil || u.currentWeight > next.currentWeight {
+				next = u
+			}
+		}
+		if next != nil {
+			next.currentWeight -= total
+			break
+		}
+	}
+	p.mutex.Unlock()
+
+	// The ejection expired, the server is tried again
+	if next != nil && next.State() == UpstreamEjected && now >= atomic.LoadInt64(&next.ejectedUntil) {
+		atomic.StoreInt32(&next.fails, 0)
+		p.transition(next, UpstreamEjected, UpstreamHealthy)
+	}
+	return next
+}
+
+// available reports if the server gets requests
+func (u *Upstream) available(now int64) bool {
+	switch u.State() {
+	case UpstreamHealthy:
+		return true
+	case UpstreamEjected:
+		return now >= atomic.LoadInt64(&u.ejectedUntil)
+	default:
+		return false
+	}
+}
+
+// Report records the result of a request to the server, the server is ejected
+// after MaxFails failed requests in a row.
+func (p *UpstreamPool) Report(u *Upstream, failed bool) {
+	atomic.AddUint64(&u.requests, 1)
+	if !failed {
+		atomic.StoreInt32(&u.fails, 0)
+		return
+	}
+	atomic.AddUint64(&u.failures, 1)
+	if p.config.MaxFails > 0 && atomic.AddInt32(&u.fails, 1) >= int32(p.config.MaxFails) {
+		atomic.StoreInt64(&u.ejectedUntil, time.Now().Add(p.config.FailTimeout).UnixNano())
+		p.transition(u, UpstreamHealthy, UpstreamEjected)
+	}
+}
+
+// Do forwards the request to the next server and reports the result, a
+// timeout <= 0 means no timeout. It returns ErrServiceUnavailable if the pool
+// has no servers.
+func (p *UpstreamPool) Do(req *fasthttp.Request, resp *fasthttp.Response, timeout time.Duration) (err error) {
+	u := p.Next()
+	if u == nil {
+		return ErrServiceUnavailable
+	}
+	if timeout > 0 {
+		err = u.client.DoTimeout(req, resp, timeout)
+	} else {
+		err = u.client.Do(req, resp)
+	}
+	p.Report(u, err != nil || isUpstreamFailure(resp.StatusCode()))
+	return err
+}
+
+// isUpstreamFailure reports if the status means the server failed the request
+func isUpstreamFailure(status int) bool {
+	return status == StatusBadGateway || status == StatusServiceUnavailable || status == StatusGatewayTimeout
+}
+
+// Stats returns the statistics of the servers.
+func (p *UpstreamPool) Stats() []UpstreamStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	stats := make([]UpstreamStats, len(p.upstreams))
+	for i, u := range p.upstreams {
+		stats[i] = UpstreamStats{
+			Addr:     u.addr,
+			Weight:   u.weight,
+			State:    u.State(),
+			Requests: atomic.LoadUint64(&u.requests),
+			Failures: atomic.LoadUint64(&u.failures),
+		}
+	}
+	return stats
+}
+
+// Close stops the health checks.
+func (p *UpstreamPool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+}
+
+// transition changes the state of the server if it's in the from state
+func (p *UpstreamPool) transition(u *Upstream, from, to UpstreamState) {
+	if atomic.CompareAndSwapInt32(&u.state, int32(from), int32(to)) && p.config.OnStateChange != nil {
+		p.config.OnStateChange(u.addr, from, to)
+	}
+}
+
+// checkHealth checks the health of all servers
+func (p *UpstreamPool) checkHealth() {
+	p.mutex.Lock()
+	upstreams := p.upstreams
+	p.mutex.Unlock()
+
+	var wg sync.WaitGroup
+	for _, u := range upstreams {
+		wg.Add(1)
+		go func(u *Upstream) {
+			defer wg.Done()
+			to := UpstreamUnhealthy
+			if p.config.HealthCheck(u.addr) {
+				to = UpstreamHealthy
+				atomic.StoreInt32(&u.fails, 0)
+			}
+			if from := u.State(); from != to {
+				p.transition(u, from, to)
+			}
+		}(u)
+	}
+	wg.Wait()
+}
+
+// watchHealth checks the health of the servers every HealthCheckInterval
+func (p *UpstreamPool) watchHealth() {
+	ticker := time.NewTicker(p.config.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		p.checkHealth()
+		select {
+		case <-ticker.C:
+		case <-p.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// go test -run Test_UpstreamPool_Weights
+func Test_UpstreamPool_Weights(t *testing.T) {
+	t.Parallel()
+	pool := NewUpstreamPool(UpstreamPoolConfig{
+		Servers: []UpstreamServer{{Addr: "a:80", Weight: 3}, {Addr: "b:80"}},
+	})
+	defer pool.Close()
+
+	var order string
+	for i := 0; i < 8; i++ {
+		order += pool.Next().Addr()[:1]
+	}
+	// Smooth weighted round-robin
+	utils.AssertEqual(t, "aabaaaba", order)
+
+	// Kept servers keep their statistics
+	pool.Report(pool.Next(), false)
+	pool.Set(UpstreamServer{Addr: "c:80"}, UpstreamServer{Addr: "a:80"})
+	stats := pool.Stats()
+	utils.AssertEqual(t, []UpstreamStats{
+		{Addr: "c:80", Weight: 1, State: UpstreamHealthy},
+		{Addr: "a:80", Weight: 1, State: UpstreamHealthy, Requests: 1},
+	}, stats)
+
+	pool.Set()
+	utils.AssertEqual(t, (*Upstream)(nil), pool.Next())
+	utils.AssertEqual(t, ErrServiceUnavailable, pool.Do(nil, nil, 0))
+}
+
+// go test -run Test_UpstreamPool_Ejection
+func Test_UpstreamPool_Ejection(t *testing.T) {
+	t.Parallel()
+	var mutex sync.Mutex
+	var changes []string
+	pool := NewUpstreamPool(UpstreamPoolConfig{
+		Servers:     []UpstreamServer{{Addr: "a:80"}, {Addr: "b:80"}},
+		MaxFails:    2,
+		FailTimeout: 50 * time.Millisecond,
+		OnStateChange: func(addr string, from, to UpstreamState) {
+			mutex.Lock()
+			changes = append(changes, addr+" "+from.String()+" -> "+to.String())
+			mutex.Unlock()
+		},
+	})
+	defer pool.Close()
+
+	a := pool.Next()
+	utils.AssertEqual(t, "a:80", a.Addr())
+	pool.Report(a, true)
+	utils.AssertEqual(t, UpstreamHealthy, a.State())
+	pool.Report(a, true)
+	utils.AssertEqual(t, UpstreamEjected, a.State())
+
+	for i := 0; i < 3; i++ {
+		utils.AssertEqual(t, "b:80", pool.Next().Addr())
+	}
+
+	// The server is tried again once the ejection expired
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 2 && pool.Next() != a; i++ {
+	}
+	utils.AssertEqual(t, UpstreamHealthy, a.State())
+	utils.AssertEqual(t, []string{"a:80 healthy -> ejected", "a:80 ejected -> healthy"}, changes)
+	utils.AssertEqual(t, uint64(2), pool.Stats()[0].Failures)
+}
+
+// go test -run Test_UpstreamPool_HealthCheck
+func Test_UpstreamPool_HealthCheck(t *testing.T) {
+	t.Parallel()
+	var mutex sync.Mutex
+	healthy := map[string]bool{"a:80": true, "b:80": false}
+	pool := NewUpstreamPool(UpstreamPoolConfig{
+		Servers: []UpstreamServer{{Addr: "a:80"}, {Addr: "b:80"}},
+		HealthCheck: func(addr string) bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return healthy[addr]
+		},
+		HealthCheckInterval: time.Hour,
+	})
+	defer pool.Close()
+
+	pool.checkHealth()
+	utils.AssertEqual(t, UpstreamUnhealthy, pool.Stats()[1].State)
+	for i := 0; i < 3; i++ {
+		utils.AssertEqual(t, "a:80", pool.Next().Addr())
+	}
+
+	// All servers are used if none of them is healthy
+	mutex.Lock()
+	healthy["a:80"] = false
+	mutex.Unlock()
+	pool.checkHealth()
+	utils.AssertEqual(t, true, pool.Next() != nil)
+}
+
+// go test -run Test_Client_Agent_Upstreams
+func Test_Client_Agent_Upstreams(t *testing.T) {
+	t.Parallel()
+	ln := fasthttputil.NewInmemoryListener()
+	app := New(Config{DisableStartupMessage: true})
+	app.Get("/", func(c *Ctx) error {
+		return c.SendString(c.Hostname())
+	})
+	go func() {
+		utils.AssertEqual(t, nil, app.Listener(ln))
+	}()
+	defer func() {
+		utils.AssertEqual(t, nil, app.Shutdown())
+	}()
+
+	pool := NewUpstreamPool(UpstreamPoolConfig{
+		Servers: []UpstreamServer{{Addr: "upstream:80"}},
+		NewClient: func(addr string) *fasthttp.HostClient {
+			return &fasthttp.HostClient{
+				Addr: addr,
+				Dial: func(string) (net.Conn, error) {
+					return ln.Dial()
+				},
+			}
+		},
+	})
+	defer pool.Close()
+
+	code, body, errs := Get("http://api.internal/").Upstreams(pool).String()
+	utils.AssertEqual(t, 0, len(errs))
+	utils.AssertEqual(t, StatusOK, code)
+	utils.AssertEqual(t, "api.internal", body)
+	utils.AssertEqual(t, uint64(1), pool.Stats()[0].Requests)
+}
@@ -0,0 +1,8 @@
+package utils
+
+// CBORMarshal returns the CBOR encoding of v.
+type CBORMarshal func(v interface{}) ([]byte, error)
+
+// CBORUnmarshal parses the CBOR-encoded data and stores the result
+// in the value pointed to by v.
+type CBORUnmarshal func(data []byte, v interface{}) error
@@ -4,7 +4,10 @@
 
 package utils
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+)
 
 const MIMEOctetStream = "application/octet-stream"
 
@@ -52,6 +55,45 @@ func ParseVendorSpecificContentType(cType string) string {
 	return cType[0:slashIndex+1] + parsableType
 }
 
+// AcceptedEncoding returns the offer with the highest quality in the
+// Accept-Encoding header, preferring the earlier offers on equal quality.
+// Encodings with q=0 are refused, "*" matches the offers, which are not
+// listed. It returns "" if no offer is acceptable or the header is empty.
+func AcceptedEncoding(header string, offers ...string) string {
+	if header == "" {
+		return ""
+	}
+	best, bestQuality := "", 0.0
+	for _, offer := range offers {
+		quality, wildcard := -1.0, -1.0
+		for _, spec := range strings.Split(header, ",") {
+			name, q := spec, 1.0
+			if i := strings.IndexByte(spec, ';'); i != -1 {
+				name = spec[:i]
+				param := strings.TrimSpace(spec[i+1:])
+				if len(param) > 2 && (param[0] == 'q' || param[0] == 'Q') && param[1] == '=' {
+					if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+						q = v
+					}
+				}
+			}
+			name = strings.TrimSpace(name)
+			if strings.EqualFold(name, offer) {
+				quality = q
+			} else if name == "*" {
+				wildcard = q
+			}
+		}
+		if quality < 0 {
+			quality = wildcard
+		}
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best
+}
+
 // limits for HTTP statuscodes
 const (
 	statusMessageMin = 100
@@ -166,6 +208,8 @@ var mimeExtensions = map[string]string{
 	"war":     "application/java-archive",
 	"ear":     "application/java-archive",
 	"json":    "application/json",
+	"msgpack": "application/msgpack",
+	"cbor":    "application/cbor",
 	"hqx":     "application/mac-binhex40",
 	"doc":     "application/msword",
 	"pdf":     "application/pdf",
@@ -95,6 +95,19 @@ func Benchmark_ParseVendorSpecificContentType(b *testing.B) {
 	})
 }
 
+func Test_AcceptedEncoding(t *testing.T) {
+	t.Parallel()
+	offers := []string{"br", "zstd", "gzip"}
+
+	AssertEqual(t, "", AcceptedEncoding("", offers...))
+	AssertEqual(t, "", AcceptedEncoding("identity", offers...))
+	AssertEqual(t, "gzip", AcceptedEncoding("gzip, deflate", offers...))
+	AssertEqual(t, "br", AcceptedEncoding("gzip, deflate, br", offers...))
+	AssertEqual(t, "gzip", AcceptedEncoding("br;q=0.5, GZIP", offers...))
+	AssertEqual(t, "zstd", AcceptedEncoding("br;q=0, *;q=0.8", offers...))
+	AssertEqual(t, "", AcceptedEncoding("*;q=0", offers...))
+}
+
 func Test_StatusMessage(t *testing.T) {
 	t.Parallel()
 	res := StatusMessage(204)
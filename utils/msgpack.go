@@ -0,0 +1,8 @@
+package utils
+
+// MsgPackMarshal returns the MessagePack encoding of v.
+type MsgPackMarshal func(v interface{}) ([]byte, error)
+
+// MsgPackUnmarshal parses the MessagePack-encoded data and stores the result
+// in the value pointed to by v.
+type MsgPackUnmarshal func(data []byte, v interface{}) error
@@ -0,0 +1,8 @@
+package utils
+
+// ProtobufMarshal returns the protobuf encoding of the message v.
+type ProtobufMarshal func(v interface{}) ([]byte, error)
+
+// ProtobufUnmarshal parses the protobuf-encoded data and stores the result
+// in the message v.
+type ProtobufUnmarshal func(data []byte, v interface{}) error
@@ -0,0 +1,182 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2/internal/bytebufferpool"
+)
+
+// HTMLViews is a Views engine of html/template, see NewHTMLViews
+type HTMLViews struct {
+	fsys      fs.FS
+	extension string
+	reload    bool
+	funcs     template.FuncMap
+
+	mutex     sync.RWMutex
+	templates *template.Template
+	// signature of the parsed files, see Reload
+	signature string
+	// layoutMutex guards the embed function of the layouts
+	layoutMutex sync.Mutex
+}
+
+// NewHTMLViews returns a Views engine, which parses the templates with the
+// extension in fsys, e.g. os.DirFS("./views") or an embed.FS. Templates are
+// named by their path without the extension and can include each other:
+//
+//	{{template "partials/footer" .}}
+//
+// A layout includes the rendered template with {{embed}}, layouts can be nested:
+//
+//	app := fiber.New(fiber.Config{
+//		Views:       fiber.NewHTMLViews(os.DirFS("./views"), ".html"),
+//		ViewsLayout: "layouts/main",
+//	})
+//
+//	app.Get("/", func(c *fiber.Ctx) error {
+//		return c.Render("index", fiber.Map{"Title": "Home"})
+//	})
+func NewHTMLViews(fsys fs.FS, extension string) *HTMLViews {
+	v := &HTMLViews{
+		fsys:      fsys,
+		extension: extension,
+		funcs:     template.FuncMap{},
+	}
+	v.funcs["embed"] = func() (template.HTML, error) {
+		return "", fmt.Errorf("views: embed called outside of a layout")
+	}
+	return v
+}
+
+// AddFunc adds the function to the function map of the templates, it must be
+// called before the templates are loaded.
+func (v *HTMLViews) AddFunc(name string, fn interface{}) *HTMLViews {
+	v.funcs[name] = fn
+	return v
+}
+
+// Reload enables parsing the templates again when a file changed, use it in
+// development to edit the templates without restarting the application.
+func (v *HTMLViews) Reload(enabled bool) *HTMLViews {
+	v.reload = enabled
+	return v
+}
+
+// Load parses the templates, it's called by fiber.New.
+func (v *HTMLViews) Load() error {
+	signature, err := v.fileSignature()
+	if err != nil {
+		return err
+	}
+
+	templates := template.New("").Funcs(v.funcs)
+	err = fs.WalkDir(v.fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, v.extension) {
+			return err
+		}
+		content, err := fs.ReadFile(v.fsys, path)
+		if err != nil {
+			return err
+		}
+		// Create new template associated with the others, so they can include each other
+		_, err = templates.New(strings.TrimSuffix(path, v.extension)).Parse(string(content))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	v.mutex.Lock()
+	v.templates, v.signature = templates, signature
+	v.mutex.Unlock()
+	return nil
+}
+
+// fileSignature returns the names, sizes and modification times of the template files
+func (v *HTMLViews) fileSignature() (string, error) {
+	var b strings.Builder
+	err := fs.WalkDir(v.fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, v.extension) {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(&b, "%s:%d:%d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	return b.String(), err
+}
+
+// Render executes the template with the data, the layouts are applied from
+// the first to the last one.
+func (v *HTMLViews) Render(w io.Writer, name string, data interface{}, layouts ...string) error {
+	v.mutex.RLock()
+	templates, signature := v.templates, v.signature
+	v.mutex.RUnlock()
+
+	if templates == nil || v.reload {
+		current, err := v.fileSignature()
+		if err != nil {
+			return err
+		}
+		if templates == nil || current != signature {
+			if err = v.Load(); err != nil {
+				return err
+			}
+			v.mutex.RLock()
+			templates = v.templates
+			v.mutex.RUnlock()
+		}
+	}
+
+	tmpl := templates.Lookup(name)
+	if tmpl == nil {
+		return fmt.Errorf("views: template %s does not exist", name)
+	}
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+	if err := tmpl.Execute(buf, data); err != nil {
+		return err
+	}
+
+	for _, layout := range layouts {
+		if layout == "" {
+			continue
+		}
+		lay := templates.Lookup(layout)
+		if lay == nil {
+			return fmt.Errorf("views: layout %s does not exist", layout)
+		}
+		// The content is rendered by html/template already
+		content := template.HTML(buf.String()) // #nosec G203
+		buf.Reset()
+
+		v.layoutMutex.Lock()
+		lay.Funcs(template.FuncMap{
+			"embed": func() template.HTML {
+				return content
+			},
+		})
+		err := lay.Execute(buf, data)
+		lay.Funcs(template.FuncMap{"embed": v.funcs["embed"]})
+		v.layoutMutex.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,81 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// go test -run Test_HTMLViews
+func Test_HTMLViews(t *testing.T) {
+	t.Parallel()
+	views := NewHTMLViews(fstest.MapFS{
+		"index.html":           {Data: []byte(`<h1>{{.Title}}</h1>{{template "partials/footer" .}}`)},
+		"partials/footer.html": {Data: []byte(`<footer>{{upper .User}}</footer>`)},
+		"layouts/main.html":    {Data: []byte(`<html>{{embed}}</html>`)},
+		"layouts/admin.html":   {Data: []byte(`<main class="admin">{{embed}}</main>`)},
+		"notes.txt":            {Data: []byte(`not a template`)},
+	}, ".html").AddFunc("upper", strings.ToUpper)
+
+	app := New(Config{
+		Views:             views,
+		ViewsLayout:       "layouts/main",
+		PassLocalsToViews: true,
+	})
+	app.Get("/", func(c *Ctx) error {
+		c.Locals("User", "john")
+		return c.Render("index", Map{"Title": "<Home>"})
+	})
+	app.Get("/admin", func(c *Ctx) error {
+		_ = c.Bind(Map{"Title": "Admin", "User": "jane"})
+		return c.Render("index", nil, "layouts/admin", "layouts/main")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	body, err := ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, "<html><h1>&lt;Home&gt;</h1><footer>JOHN</footer></html>", string(body))
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/admin", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	body, err = ioutil.ReadAll(resp.Body)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, `<html><main class="admin"><h1>Admin</h1><footer>JANE</footer></main></html>`, string(body))
+
+	var buf bytes.Buffer
+	utils.AssertEqual(t, "views: template notes does not exist", views.Render(&buf, "notes", nil).Error())
+	utils.AssertEqual(t, "views: layout missing does not exist", views.Render(&buf, "index", Map{"User": "john"}, "missing").Error())
+	utils.AssertEqual(t, true, views.Render(&buf, "layouts/main", nil) != nil)
+}
+
+// go test -run Test_HTMLViews_Reload
+func Test_HTMLViews_Reload(t *testing.T) {
+	t.Parallel()
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`v1`), ModTime: time.Unix(1, 0)},
+	}
+	views := NewHTMLViews(fsys, ".html")
+	render := func() string {
+		var buf bytes.Buffer
+		utils.AssertEqual(t, nil, views.Render(&buf, "index", nil))
+		return buf.String()
+	}
+
+	utils.AssertEqual(t, "v1", render())
+	fsys["index.html"] = &fstest.MapFile{Data: []byte(`v2`), ModTime: time.Unix(2, 0)}
+	utils.AssertEqual(t, "v1", render())
+
+	views.Reload(true)
+	utils.AssertEqual(t, "v2", render())
+}
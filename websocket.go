@@ -0,0 +1,592 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Message types of WebSocket, see RFC 6455, 11.8
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+)
+
+// Close codes of WebSocket, see RFC 6455, 11.7
+const (
+	CloseNormalClosure           = 1000
+	CloseGoingAway               = 1001
+	CloseProtocolError           = 1002
+	CloseUnsupportedData         = 1003
+	CloseNoStatusReceived        = 1005
+	CloseAbnormalClosure         = 1006
+	CloseInvalidFramePayloadData = 1007
+	ClosePolicyViolation         = 1008
+	CloseMessageTooBig           = 1009
+	CloseInternalServerErr       = 1011
+)
+
+// websocketGUID is appended to the key of the handshake, see RFC 6455, 1.3
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// ErrWebSocketClosed is returned when writing after the close frame was sent
+var ErrWebSocketClosed = errors.New("websocket: close sent")
+
+// errWebSocketShutdown rejects upgrades once the app is shutting down
+var errWebSocketShutdown = NewError(StatusServiceUnavailable, "websocket: server is shutting down")
+
+// WebSocketShutdownError is returned by App.Shutdown if WebSocket connections
+// were still open after Config.WebSocketShutdownTimeout. They have been closed
+// forcibly.
+type WebSocketShutdownError struct {
+	// Remote addresses of the lingering connections
+	Lingering []net.Addr
+}
+
+func (e *WebSocketShutdownError) Error() string {
+	addrs := make([]string, len(e.Lingering))
+	for i, addr := range e.Lingering {
+		addrs[i] = addr.String()
+	}
+	return "shutdown: " + strconv.Itoa(len(addrs)) + " websocket connection(s) closed forcibly: " + strings.Join(addrs, ", ")
+}
+
+// WebSocketConfig configures the WebSocket connections of Ctx.Upgrade
+type WebSocketConfig struct {
+	// Origins allowed to connect, "*" allows all origins. By default only
+	// handshakes of the same origin, whose Origin host equals the Host header,
+	// and the ones without an Origin header of non-browser clients are accepted,
+	// so that other sites can't hijack the connections of their visitors.
+	//
+	// Optional. Default: nil
+	Origins []string
+
+	// Subprotocols supported by the server in order of preference. The first
+	// one requested by the client is selected.
+	//
+	// Optional. Default: nil
+	Subprotocols []string
+
+	// ReadLimit is the maximum size of a message in bytes, 0 means no limit.
+	// Larger messages close the connection with CloseMessageTooBig.
+	//
+	// Optional. Default: 0
+	ReadLimit int64
+
+	// ReadBufferSize and WriteBufferSize are the sizes of the I/O buffers.
+	//
+	// Optional. Default: 4096
+	ReadBufferSize  int
+	WriteBufferSize int
+}
+
+// WebSocketCloseError is returned by ReadMessage if the peer closed the connection
+type WebSocketCloseError struct {
+	Code int
+	Text string
+}
+
+func (e *WebSocketCloseError) Error() string {
+	if e.Text == "" {
+		return "websocket: close " + strconv.Itoa(e.Code)
+	}
+	return "websocket: close " + strconv.Itoa(e.Code) + " " + e.Text
+}
+
+// WebSocket is an upgraded connection, see Ctx.Upgrade. Reads have to be done
+// by a single goroutine, writes are safe for concurrent use.
+type WebSocket struct {
+	c           *Ctx
+	conn        net.Conn
+	br          *bufio.Reader
+	subprotocol string
+	readLimit   int64
+	readErr     error
+
+	wmu       sync.Mutex
+	bw        *bufio.Writer
+	closeSent bool
+}
+
+// IsWebSocket returns true if the request asks for a WebSocket upgrade
+func (c *Ctx) IsWebSocket() bool {
+	return headerHasToken(c.Get(HeaderConnection), "upgrade") &&
+		headerHasToken(c.Get(HeaderUpgrade), "websocket")
+}
+
+// headerHasToken reports if the comma-separated header contains the token
+func headerHasToken(header, token string) bool {
+	for _, part := range strings.Split(header, ",") {
+		if utils.EqualFold(utils.Trim(part, ' '), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// Upgrade upgrades the request to a WebSocket connection, which is passed to
+// handler once the handshake response has been sent:
+//
+//	app.Get("/ws/:room", func(c *fiber.Ctx) error {
+//		return c.Upgrade(func(ws *fiber.WebSocket) {
+//			room := ws.Ctx().Params("room")
+//			for {
+//				mt, msg, err := ws.ReadMessage()
+//				if err != nil {
+//					return
+//				}
+//				_ = ws.WriteMessage(mt, msg)
+//			}
+//		})
+//	})
+//
+// The params and Locals of the route are available through Ctx, which is a
+// copy of the request, see Ctx.Fork, and stays valid until handler returns.
+// Locals implementing io.Closer aren't copied. The connection is closed when handler returns.
+// ErrUpgradeRequired is returned if the request is no WebSocket handshake.
+func (c *Ctx) Upgrade(handler func(ws *WebSocket), config ...WebSocketConfig) error {
+	cfg := WebSocketConfig{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.ReadBufferSize <= 0 {
+		cfg.ReadBufferSize = 4096
+	}
+	if cfg.WriteBufferSize <= 0 {
+		cfg.WriteBufferSize = 4096
+	}
+
+	if c.methodINT != c.app.methodInt(MethodGet) || !c.IsWebSocket() {
+		return ErrUpgradeRequired
+	}
+	if c.app.Draining() {
+		return errWebSocketShutdown
+	}
+	if c.Get(HeaderSecWebSocketVersion) != "13" {
+		c.Set(HeaderSecWebSocketVersion, "13")
+		return ErrUpgradeRequired
+	}
+	key := c.Get(HeaderSecWebSocketKey)
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return NewError(StatusBadRequest, "websocket: invalid Sec-WebSocket-Key")
+	}
+	if !allowedOrigin(cfg.Origins, c.Get(HeaderOrigin), c.app.getString(c.fasthttp.Request.Host())) {
+		return ErrForbidden
+	}
+
+	var subprotocol string
+	if requested := c.Get(HeaderSecWebSocketProtocol); requested != "" {
+	search:
+		for _, supported := range cfg.Subprotocols {
+			for _, protocol := range strings.Split(requested, ",") {
+				if utils.Trim(protocol, ' ') == supported {
+					subprotocol = supported
+					break search
+				}
+			}
+		}
+	}
+
+	c.Status(StatusSwitchingProtocols)
+	c.Set(HeaderUpgrade, "websocket")
+	c.Set(HeaderConnection, "Upgrade")
+	c.Set(HeaderSecWebSocketAccept, websocketAccept(key))
+	if subprotocol != "" {
+		c.Set(HeaderSecWebSocketProtocol, subprotocol)
+	}
+
+	// The Ctx is released once the handlers returned, like for other requests,
+	// the WebSocket uses a copy of the request instead
+	app, fork := c.app, c.Fork()
+	c.fasthttp.Hijack(func(conn net.Conn) {
+		ws := &WebSocket{
+			c:           fork,
+			conn:        conn,
+			br:          bufio.NewReaderSize(conn, cfg.ReadBufferSize),
+			bw:          bufio.NewWriterSize(conn, cfg.WriteBufferSize),
+			subprotocol: subprotocol,
+			readLimit:   cfg.ReadLimit,
+		}
+		tracked := app.websockets.add(ws)
+		defer func() {
+			_ = ws.Close()
+			if tracked {
+				app.websockets.remove(ws)
+			}
+			app.ReleaseCtx(fork)
+		}()
+		// The app started shutting down after the handshake
+		if !tracked {
+			_ = ws.WriteClose(CloseGoingAway, "server shutting down")
+			return
+		}
+		handler(ws)
+	})
+	return nil
+}
+
+// allowedOrigin reports if the origin is allowed to connect, without origins
+// only the same origin as the host is allowed
+func allowedOrigin(origins []string, origin, host string) bool {
+	if len(origins) == 0 {
+		if origin == "" {
+			return true
+		}
+		u, err := url.Parse(origin)
+		return err == nil && utils.EqualFold(u.Host, host)
+	}
+	for _, allowed := range origins {
+		if allowed == "*" || utils.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// websocketAccept returns the Sec-WebSocket-Accept value of the key
+func websocketAccept(key string) string {
+	h := sha1.New()
+	_, _ = h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Ctx returns a copy of the context of the upgraded request, see Ctx.Upgrade
+func (ws *WebSocket) Ctx() *Ctx {
+	return ws.c
+}
+
+// Subprotocol returns the negotiated subprotocol
+func (ws *WebSocket) Subprotocol() string {
+	return ws.subprotocol
+}
+
+// RemoteAddr returns the remote network address
+func (ws *WebSocket) RemoteAddr() net.Addr {
+	return ws.conn.RemoteAddr()
+}
+
+// SetReadDeadline sets the deadline of reads, see net.Conn
+func (ws *WebSocket) SetReadDeadline(t time.Time) error {
+	return ws.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline of writes, see net.Conn
+func (ws *WebSocket) SetWriteDeadline(t time.Time) error {
+	return ws.conn.SetWriteDeadline(t)
+}
+
+// ReadMessage returns the next text or binary message. Pings are answered
+// and pongs are skipped. A *WebSocketCloseError is returned if the peer
+// closed the connection, the close is answered automatically.
+func (ws *WebSocket) ReadMessage() (messageType int, p []byte, err error) {
+	if ws.readErr != nil {
+		return 0, nil, ws.readErr
+	}
+	for {
+		fin, opcode, payload, err := ws.readFrame(int64(len(p)))
+		if err != nil {
+			return 0, nil, ws.fail(err)
+		}
+
+		switch opcode {
+		case PingMessage:
+			if err := ws.writeFrame(PongMessage, payload); err != nil && err != ErrWebSocketClosed {
+				return 0, nil, ws.fail(err)
+			}
+			continue
+		case PongMessage:
+			continue
+		case CloseMessage:
+			closeErr := &WebSocketCloseError{Code: CloseNoStatusReceived}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Text = string(payload[2:])
+			}
+			reply := closeErr.Code
+			if reply == CloseNoStatusReceived {
+				reply = CloseNormalClosure
+			}
+			_ = ws.WriteClose(reply, "")
+			ws.readErr = closeErr
+			return 0, nil, closeErr
+		case 0:
+			if messageType == 0 {
+				return 0, nil, ws.fail(&WebSocketCloseError{CloseProtocolError, "unexpected continuation frame"})
+			}
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, ws.fail(&WebSocketCloseError{CloseProtocolError, "expected continuation frame"})
+			}
+			messageType = opcode
+		default:
+			return 0, nil, ws.fail(&WebSocketCloseError{CloseProtocolError, "unknown opcode"})
+		}
+
+		p = append(p, payload...)
+		if fin {
+			if messageType == TextMessage && !utf8.Valid(p) {
+				return 0, nil, ws.fail(&WebSocketCloseError{CloseInvalidFramePayloadData, "invalid utf-8"})
+			}
+			return messageType, p, nil
+		}
+	}
+}
+
+// readFrame reads a frame, read is the size of the message read so far
+func (ws *WebSocket) readFrame(read int64) (fin bool, opcode int, payload []byte, err error) {
+	var head [14]byte
+	if _, err = io.ReadFull(ws.br, head[:2]); err != nil {
+		return
+	}
+	fin = head[0]&0x80 != 0
+	opcode = int(head[0] & 0x0f)
+	if head[0]&0x70 != 0 {
+		err = &WebSocketCloseError{CloseProtocolError, "reserved bits set"}
+		return
+	}
+	if head[1]&0x80 == 0 {
+		err = &WebSocketCloseError{CloseProtocolError, "unmasked client frame"}
+		return
+	}
+
+	size := uint64(head[1] & 0x7f)
+	switch size {
+	case 126:
+		if _, err = io.ReadFull(ws.br, head[2:4]); err != nil {
+			return
+		}
+		size = uint64(binary.BigEndian.Uint16(head[2:4]))
+	case 127:
+		if _, err = io.ReadFull(ws.br, head[2:10]); err != nil {
+			return
+		}
+		size = binary.BigEndian.Uint64(head[2:10])
+	}
+	if opcode >= CloseMessage && (!fin || size > 125) {
+		err = &WebSocketCloseError{CloseProtocolError, "invalid control frame"}
+		return
+	}
+	if size > 1<<62 || (ws.readLimit > 0 && opcode < CloseMessage && read+int64(size) > ws.readLimit) {
+		err = &WebSocketCloseError{CloseMessageTooBig, "message too big"}
+		return
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(ws.br, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(ws.br, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i&3]
+	}
+	return
+}
+
+// fail closes the connection because of a read error, which is returned by
+// all following reads
+func (ws *WebSocket) fail(err error) error {
+	var closeErr *WebSocketCloseError
+	if errors.As(err, &closeErr) {
+		_ = ws.WriteClose(closeErr.Code, closeErr.Text)
+	}
+	ws.readErr = err
+	return err
+}
+
+// ReadJSON reads the next message and decodes it with the JSON decoder of the app
+func (ws *WebSocket) ReadJSON(v interface{}) error {
+	_, p, err := ws.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return ws.c.app.config.JSONDecoder(p, v)
+}
+
+// WriteMessage writes a text, binary, ping or pong message
+func (ws *WebSocket) WriteMessage(messageType int, data []byte) error {
+	switch messageType {
+	case TextMessage, BinaryMessage:
+	case PingMessage, PongMessage:
+		if len(data) > 125 {
+			return errors.New("websocket: control frame too big")
+		}
+	default:
+		return errors.New("websocket: invalid message type")
+	}
+	return ws.writeFrame(messageType, data)
+}
+
+// WriteJSON writes v encoded with the JSON encoder of the app as text message
+func (ws *WebSocket) WriteJSON(v interface{}) error {
+	data, err := ws.c.app.config.JSONEncoder(v)
+	if err != nil {
+		return err
+	}
+	return ws.writeFrame(TextMessage, data)
+}
+
+// WriteClose sends a close frame with the code and reason, afterwards no
+// messages can be written
+func (ws *WebSocket) WriteClose(code int, text string) error {
+	if len(text) > 123 {
+		text = text[:123]
+	}
+	payload := make([]byte, 2, 2+len(text))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	return ws.writeFrame(CloseMessage, append(payload, text...))
+}
+
+// Close sends a normal close frame if none was sent and closes the connection
+func (ws *WebSocket) Close() error {
+	_ = ws.WriteClose(CloseNormalClosure, "")
+	return ws.conn.Close()
+}
+
+func (ws *WebSocket) writeFrame(opcode int, payload []byte) error {
+	ws.wmu.Lock()
+	defer ws.wmu.Unlock()
+	if ws.closeSent {
+		return ErrWebSocketClosed
+	}
+	if opcode == CloseMessage {
+		ws.closeSent = true
+	}
+
+	var head [10]byte
+	head[0] = 0x80 | byte(opcode)
+	n := 2
+	switch size := len(payload); {
+	case size <= 125:
+		head[1] = byte(size)
+	case size <= 0xffff:
+		head[1] = 126
+		binary.BigEndian.PutUint16(head[2:], uint16(size))
+		n = 4
+	default:
+		head[1] = 127
+		binary.BigEndian.PutUint64(head[2:], uint64(size))
+		n = 10
+	}
+	if _, err := ws.bw.Write(head[:n]); err != nil {
+		return err
+	}
+	if _, err := ws.bw.Write(payload); err != nil {
+		return err
+	}
+	return ws.bw.Flush()
+}
+
+// webSockets tracks the upgraded connections of an app, which are invisible
+// to the server once they're hijacked
+type webSockets struct {
+	mutex   sync.Mutex
+	conns   map[*WebSocket]struct{}
+	closing bool
+	// Closed once all connections returned after closing started
+	idle chan struct{}
+}
+
+// add tracks the connection, unless the app is shutting down
+func (s *webSockets) add(ws *WebSocket) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closing {
+		return false
+	}
+	if s.conns == nil {
+		s.conns = make(map[*WebSocket]struct{})
+	}
+	s.conns[ws] = struct{}{}
+	return true
+}
+
+// remove stops tracking the connection once its handler returned
+func (s *webSockets) remove(ws *WebSocket) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.conns, ws)
+	if s.idle != nil && len(s.conns) == 0 {
+		close(s.idle)
+		s.idle = nil
+	}
+}
+
+// shutdown sends a close frame to all connections and waits for their
+// handlers to return. Connections still open after the timeout are closed
+// forcibly and reported by a *WebSocketShutdownError.
+func (s *webSockets) shutdown(timeout time.Duration) error {
+	s.mutex.Lock()
+	s.closing = true
+	conns := make([]*WebSocket, 0, len(s.conns))
+	for ws := range s.conns {
+		conns = append(conns, ws)
+	}
+	if len(conns) > 0 && s.idle == nil {
+		s.idle = make(chan struct{})
+	}
+	idle := s.idle
+	s.mutex.Unlock()
+	if len(conns) == 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, ws := range conns {
+		// Writes of clients, which don't read, must not block the shutdown
+		go func(ws *WebSocket) {
+			_ = ws.conn.SetWriteDeadline(deadline)
+			_ = ws.WriteClose(CloseGoingAway, "server shutting down")
+		}(ws)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-idle:
+		return nil
+	case <-timer.C:
+	}
+
+	s.mutex.Lock()
+	lingering := make([]*WebSocket, 0, len(s.conns))
+	for ws := range s.conns {
+		lingering = append(lingering, ws)
+	}
+	s.mutex.Unlock()
+	if len(lingering) == 0 {
+		return nil
+	}
+	err := &WebSocketShutdownError{Lingering: make([]net.Addr, len(lingering))}
+	for i, ws := range lingering {
+		err.Lingering[i] = ws.conn.RemoteAddr()
+		// Closing a hijacked connection is deferred until the handler returned
+		conn := ws.conn
+		if hijacked, ok := conn.(interface{ UnsafeConn() net.Conn }); ok {
+			conn = hijacked.UnsafeConn()
+		}
+		_ = conn.Close()
+	}
+	return err
+}
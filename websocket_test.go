@@ -0,0 +1,419 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// writeClientFrame writes a masked frame
+func writeClientFrame(t *testing.T, w io.Writer, fin bool, opcode int, payload []byte) {
+	head := []byte{byte(opcode), 0x80 | byte(len(payload))}
+	if fin {
+		head[0] |= 0x80
+	}
+	mask := []byte{1, 2, 3, 4}
+	masked := make([]byte, len(payload))
+	for i := range payload {
+		masked[i] = payload[i] ^ mask[i&3]
+	}
+	_, err := w.Write(append(append(head, mask...), masked...))
+	utils.AssertEqual(t, nil, err)
+}
+
+// readServerFrame reads an unmasked frame with a payload shorter than 126 bytes
+func readServerFrame(t *testing.T, r io.Reader) (int, []byte) {
+	head := make([]byte, 2)
+	_, err := io.ReadFull(r, head)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, byte(0x80), head[0]&0x80, "fin")
+	payload := make([]byte, head[1])
+	_, err = io.ReadFull(r, payload)
+	utils.AssertEqual(t, nil, err)
+	return int(head[0] & 0x0f), payload
+}
+
+// go test -run Test_Ctx_Upgrade
+func Test_Ctx_Upgrade(t *testing.T) {
+	t.Parallel()
+	app := New(Config{DisableStartupMessage: true})
+
+	closed := make(chan error, 1)
+	app.Use(func(c *Ctx) error {
+		c.Locals("user", "john")
+		return c.Next()
+	})
+	app.Ws("/ws/:room", func(ws *WebSocket) {
+		greeting := ws.Ctx().Params("room") + " " + ws.Ctx().Locals("user").(string) + " " + ws.Subprotocol()
+		utils.AssertEqual(t, nil, ws.WriteMessage(TextMessage, []byte(greeting)))
+		for {
+			mt, msg, err := ws.ReadMessage()
+			if err != nil {
+				closed <- err
+				return
+			}
+			utils.AssertEqual(t, nil, ws.WriteMessage(mt, msg))
+		}
+	}, WebSocketConfig{Subprotocols: []string{"chat", "json"}})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		utils.AssertEqual(t, nil, app.Listener(ln))
+	}()
+	defer func() {
+		utils.AssertEqual(t, nil, app.Shutdown())
+	}()
+
+	conn, err := ln.Dial()
+	utils.AssertEqual(t, nil, err)
+	defer conn.Close()
+	utils.AssertEqual(t, nil, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	_, err = conn.Write([]byte("GET /ws/lobby HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"Connection: keep-alive, Upgrade\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+		"Sec-WebSocket-Protocol: json, chat\r\n\r\n"))
+	utils.AssertEqual(t, nil, err)
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusSwitchingProtocols, resp.StatusCode)
+	// Example of RFC 6455, 1.3
+	utils.AssertEqual(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get(HeaderSecWebSocketAccept))
+	utils.AssertEqual(t, "chat", resp.Header.Get(HeaderSecWebSocketProtocol))
+
+	opcode, payload := readServerFrame(t, br)
+	utils.AssertEqual(t, TextMessage, opcode)
+	utils.AssertEqual(t, "lobby john chat", string(payload))
+
+	// Fragmented message with an interleaved ping
+	writeClientFrame(t, conn, false, TextMessage, []byte("hel"))
+	writeClientFrame(t, conn, true, PingMessage, []byte("ping"))
+	writeClientFrame(t, conn, true, 0, []byte("lo"))
+	opcode, payload = readServerFrame(t, br)
+	utils.AssertEqual(t, PongMessage, opcode)
+	utils.AssertEqual(t, "ping", string(payload))
+	opcode, payload = readServerFrame(t, br)
+	utils.AssertEqual(t, TextMessage, opcode)
+	utils.AssertEqual(t, "hello", string(payload))
+
+	// Close handshake
+	closePayload := make([]byte, 2, 6)
+	binary.BigEndian.PutUint16(closePayload, CloseGoingAway)
+	writeClientFrame(t, conn, true, CloseMessage, append(closePayload, "bye"...))
+	opcode, payload = readServerFrame(t, br)
+	utils.AssertEqual(t, CloseMessage, opcode)
+	utils.AssertEqual(t, uint16(CloseGoingAway), binary.BigEndian.Uint16(payload))
+
+	select {
+	case err := <-closed:
+		utils.AssertEqual(t, &WebSocketCloseError{Code: CloseGoingAway, Text: "bye"}, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not receive the close")
+	}
+}
+
+// go test -run Test_Ctx_Upgrade_Release
+func Test_Ctx_Upgrade_Release(t *testing.T) {
+	t.Parallel()
+	app := New(Config{DisableStartupMessage: true})
+
+	var after int32
+	app.UseAfter(func(c *Ctx) {
+		atomic.AddInt32(&after, 1)
+	})
+	app.Use(func(c *Ctx) error {
+		c.Locals("user", "john")
+		return c.Next()
+	})
+	app.Get("/ping", func(c *Ctx) error {
+		return c.SendString("pong")
+	})
+	app.Ws("/ws/:room", func(ws *WebSocket) {
+		// The Ctx of the handshake may be reused by other requests meanwhile
+		resp, err := app.Test(httptest.NewRequest(MethodGet, "/ping", nil))
+		utils.AssertEqual(t, nil, err)
+		utils.AssertEqual(t, StatusOK, resp.StatusCode)
+		greeting := ws.Ctx().Params("room") + " " + ws.Ctx().Locals("user").(string)
+		utils.AssertEqual(t, nil, ws.WriteMessage(TextMessage, []byte(greeting)))
+	})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		utils.AssertEqual(t, nil, app.Listener(ln))
+	}()
+	defer func() {
+		utils.AssertEqual(t, nil, app.Shutdown())
+	}()
+
+	conn, err := ln.Dial()
+	utils.AssertEqual(t, nil, err)
+	defer conn.Close()
+	utils.AssertEqual(t, nil, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Write([]byte("GET /ws/lobby HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"))
+	utils.AssertEqual(t, nil, err)
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusSwitchingProtocols, resp.StatusCode)
+	opcode, payload := readServerFrame(t, br)
+	utils.AssertEqual(t, TextMessage, opcode)
+	utils.AssertEqual(t, "lobby john", string(payload))
+
+	// The after handlers run once for the handshake, once the connection closed
+	opcode, _ = readServerFrame(t, br)
+	utils.AssertEqual(t, CloseMessage, opcode)
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&after) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	utils.AssertEqual(t, int32(2), atomic.LoadInt32(&after))
+}
+
+// go test -run Test_Ctx_Upgrade_Invalid
+func Test_Ctx_Upgrade_Invalid(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Ws("/ws", func(ws *WebSocket) {
+		t.Fatal("handler must not be called")
+	}, WebSocketConfig{Origins: []string{"https://example.com"}})
+
+	// No handshake
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/ws", nil))
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusUpgradeRequired, resp.StatusCode)
+
+	newHandshake := func() *http.Request {
+		req := httptest.NewRequest(MethodGet, "/ws", nil)
+		req.Header.Set(HeaderConnection, "Upgrade")
+		req.Header.Set(HeaderUpgrade, "websocket")
+		req.Header.Set(HeaderSecWebSocketVersion, "13")
+		req.Header.Set(HeaderSecWebSocketKey, "dGhlIHNhbXBsZSBub25jZQ==")
+		req.Header.Set(HeaderOrigin, "https://example.com")
+		return req
+	}
+
+	req := newHandshake()
+	req.Header.Set(HeaderSecWebSocketVersion, "8")
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusUpgradeRequired, resp.StatusCode)
+	utils.AssertEqual(t, "13", resp.Header.Get(HeaderSecWebSocketVersion))
+
+	req = newHandshake()
+	req.Header.Set(HeaderSecWebSocketKey, "invalid")
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusBadRequest, resp.StatusCode)
+
+	req = newHandshake()
+	req.Header.Set(HeaderOrigin, "https://evil.com")
+	resp, err = app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusForbidden, resp.StatusCode)
+}
+
+// go test -run Test_Ctx_Upgrade_Origins
+func Test_Ctx_Upgrade_Origins(t *testing.T) {
+	t.Parallel()
+	app := New(Config{DisableStartupMessage: true})
+	handler := func(ws *WebSocket) {}
+	app.Ws("/same", handler)
+	app.Ws("/any", handler, WebSocketConfig{Origins: []string{"*"}})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		utils.AssertEqual(t, nil, app.Listener(ln))
+	}()
+	defer func() {
+		utils.AssertEqual(t, nil, app.Shutdown())
+	}()
+
+	handshake := func(path, origin string) int {
+		conn, err := ln.Dial()
+		utils.AssertEqual(t, nil, err)
+		defer conn.Close()
+		utils.AssertEqual(t, nil, conn.SetDeadline(time.Now().Add(5*time.Second)))
+		req := "GET " + path + " HTTP/1.1\r\n" +
+			"Host: example.com\r\n" +
+			"Connection: Upgrade\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Sec-WebSocket-Version: 13\r\n" +
+			"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"
+		if origin != "" {
+			req += "Origin: " + origin + "\r\n"
+		}
+		_, err = conn.Write([]byte(req + "\r\n"))
+		utils.AssertEqual(t, nil, err)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		utils.AssertEqual(t, nil, err)
+		return resp.StatusCode
+	}
+
+	// Only the same origin is accepted by default
+	utils.AssertEqual(t, StatusSwitchingProtocols, handshake("/same", "https://example.com"))
+	utils.AssertEqual(t, StatusSwitchingProtocols, handshake("/same", "https://EXAMPLE.com"))
+	utils.AssertEqual(t, StatusSwitchingProtocols, handshake("/same", ""))
+	utils.AssertEqual(t, StatusForbidden, handshake("/same", "https://evil.com"))
+	utils.AssertEqual(t, StatusForbidden, handshake("/same", "https://example.com.evil.com"))
+
+	// "*" accepts all origins
+	utils.AssertEqual(t, StatusSwitchingProtocols, handshake("/any", "https://evil.com"))
+}
+
+// go test -run Test_WebSocket_ReadLimit
+func Test_WebSocket_ReadLimit(t *testing.T) {
+	t.Parallel()
+	in, out := new(bytes.Buffer), new(bytes.Buffer)
+	writeClientFrame(t, in, true, BinaryMessage, []byte("too big"))
+	ws := &WebSocket{
+		br:        bufio.NewReader(in),
+		bw:        bufio.NewWriter(out),
+		readLimit: 4,
+	}
+
+	_, _, err := ws.ReadMessage()
+	utils.AssertEqual(t, &WebSocketCloseError{Code: CloseMessageTooBig, Text: "message too big"}, err)
+	opcode, payload := readServerFrame(t, out)
+	utils.AssertEqual(t, CloseMessage, opcode)
+	utils.AssertEqual(t, uint16(CloseMessageTooBig), binary.BigEndian.Uint16(payload))
+
+	// Following reads fail and writing after the close frame fails
+	_, _, err = ws.ReadMessage()
+	utils.AssertEqual(t, CloseMessageTooBig, err.(*WebSocketCloseError).Code)
+	utils.AssertEqual(t, ErrWebSocketClosed, ws.WriteMessage(TextMessage, []byte("late")))
+}
+
+// dialWebSocket upgrades a connection of the listener
+func dialWebSocket(t *testing.T, ln *fasthttputil.InmemoryListener) (net.Conn, *bufio.Reader) {
+	conn, err := ln.Dial()
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, nil, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Write([]byte("GET /ws HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"))
+	utils.AssertEqual(t, nil, err)
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	utils.AssertEqual(t, nil, err)
+	utils.AssertEqual(t, StatusSwitchingProtocols, resp.StatusCode)
+	return conn, br
+}
+
+// go test -run Test_App_Shutdown_WebSocket
+func Test_App_Shutdown_WebSocket(t *testing.T) {
+	t.Parallel()
+	app := New(Config{DisableStartupMessage: true})
+	upgraded := make(chan struct{}, 1)
+	closed := make(chan error, 1)
+	app.Ws("/ws", func(ws *WebSocket) {
+		upgraded <- struct{}{}
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				closed <- err
+				return
+			}
+		}
+	})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		utils.AssertEqual(t, nil, app.Listener(ln))
+	}()
+
+	conn, br := dialWebSocket(t, ln)
+	defer conn.Close()
+	<-upgraded
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- app.Shutdown()
+	}()
+
+	// The client answers the close frame of the server
+	opcode, payload := readServerFrame(t, br)
+	utils.AssertEqual(t, CloseMessage, opcode)
+	utils.AssertEqual(t, uint16(CloseGoingAway), binary.BigEndian.Uint16(payload))
+	utils.AssertEqual(t, "server shutting down", string(payload[2:]))
+	writeClientFrame(t, conn, true, CloseMessage, payload[:2])
+
+	utils.AssertEqual(t, &WebSocketCloseError{Code: CloseGoingAway}, <-closed)
+	utils.AssertEqual(t, nil, <-shutdown)
+}
+
+// go test -run Test_App_Shutdown_WebSocket_Lingering
+func Test_App_Shutdown_WebSocket_Lingering(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		DisableStartupMessage:    true,
+		WebSocketShutdownTimeout: 50 * time.Millisecond,
+	})
+	upgraded := make(chan struct{}, 1)
+	closed := make(chan error, 1)
+	app.Ws("/ws", func(ws *WebSocket) {
+		upgraded <- struct{}{}
+		_, _, err := ws.ReadMessage()
+		closed <- err
+	})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		utils.AssertEqual(t, nil, app.Listener(ln))
+	}()
+
+	conn, br := dialWebSocket(t, ln)
+	defer conn.Close()
+	<-upgraded
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- app.Shutdown()
+	}()
+
+	// The client ignores the close frame, it's closed forcibly
+	opcode, _ := readServerFrame(t, br)
+	utils.AssertEqual(t, CloseMessage, opcode)
+	err := <-shutdown
+	shutdownErr, ok := err.(*WebSocketShutdownError)
+	utils.AssertEqual(t, true, ok, "WebSocketShutdownError")
+	utils.AssertEqual(t, 1, len(shutdownErr.Lingering))
+	utils.AssertEqual(t, true, <-closed != nil)
+
+	// Upgrades are rejected once the app is shutting down
+	req := httptest.NewRequest(MethodGet, "/ws", nil)
+	req.Header.Set(HeaderConnection, "Upgrade")
+	req.Header.Set(HeaderUpgrade, "websocket")
+	req.Header.Set(HeaderSecWebSocketVersion, "13")
+	req.Header.Set(HeaderSecWebSocketKey, "dGhlIHNhbXBsZSBub25jZQ==")
+	resp, err := app.Test(req)
+	utils.AssertEqual(t, nil, err, "app.Test(req)")
+	utils.AssertEqual(t, StatusServiceUnavailable, resp.StatusCode)
+}